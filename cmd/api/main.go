@@ -10,11 +10,28 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/TakuyaAizawa/gox/internal/analytics"
 	"github.com/TakuyaAizawa/gox/internal/api/routes"
 	"github.com/TakuyaAizawa/gox/internal/config"
+	"github.com/TakuyaAizawa/gox/internal/email"
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/jobs"
+	"github.com/TakuyaAizawa/gox/internal/push"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
 	"github.com/TakuyaAizawa/gox/internal/repository/postgres"
+	redisrepo "github.com/TakuyaAizawa/gox/internal/repository/redis"
+	"github.com/TakuyaAizawa/gox/internal/search"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/storage"
+	"github.com/TakuyaAizawa/gox/internal/telemetry"
+	"github.com/TakuyaAizawa/gox/internal/util/oidc"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 // @title GoX API
@@ -30,9 +47,19 @@ import (
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host localhost:8080
-// @BasePath /api/v1
+// @BasePath /
 // @schemes http https
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description 「Bearer 」に続けてアクセストークンを指定する
+
+// @securityDefinitions.apikey SCIMToken
+// @in header
+// @name Authorization
+// @description 「Bearer 」に続けてSCIMのプロビジョニング用のトークンを指定する
+
 func main() {
 	// 設定のロード
 	cfg, err := config.Load()
@@ -47,6 +74,22 @@ func main() {
 	}
 	defer l.Sync()
 
+	// 分散トレーシング（無効の場合もトレースIDの伝播のみ行う）
+	shutdownTracing, err := telemetry.Setup(
+		context.Background(),
+		cfg.Tracing.Enabled,
+		cfg.Tracing.Endpoint,
+		cfg.Tracing.ServiceName,
+		cfg.App.Env,
+		cfg.Tracing.SampleRate,
+	)
+	if err != nil {
+		l.Fatal("トレースの送信先の設定に失敗しました", "error", err)
+	}
+	if cfg.Tracing.Enabled {
+		l.Info("分散トレーシングを有効化しました", "endpoint", cfg.Tracing.Endpoint, "sample_rate", cfg.Tracing.SampleRate)
+	}
+
 	// データベース接続文字列の構築
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.SSLMode)
@@ -67,6 +110,65 @@ func main() {
 	dbConfig.MaxConnLifetime = 5 * time.Minute
 	dbConfig.MaxConnIdleTime = 5 * time.Minute
 
+	// 接続プーラー（pgbouncerのトランザクションプーリング）との互換モード
+	poolerDetected, poolerReason, err := postgres.DetectTransactionPooler(ctx, dbConfig.ConnConfig)
+	if err != nil {
+		l.Warn("接続プーラーの検出に失敗しました", "error", err)
+	}
+	transactionPooling := false
+	switch cfg.DB.PoolMode {
+	case "transaction":
+		postgres.EnableTransactionPooling(dbConfig)
+		transactionPooling = true
+		l.Info("接続プーラーとの互換モードを有効化しました（プリペアドステートメントのキャッシュを使用しません）")
+		if err == nil && !poolerDetected {
+			l.Warn("接続プーラーが検出されませんでした。直接接続している場合はDB_POOL_MODE=sessionの方が高速です")
+		}
+	case "auto":
+		if poolerDetected {
+			postgres.EnableTransactionPooling(dbConfig)
+			transactionPooling = true
+			l.Warn("トランザクションプーリングの接続プーラーを検出したため、互換モードを有効化しました", "reason", poolerReason)
+		}
+	default:
+		if poolerDetected {
+			l.Warn("トランザクションプーリングの接続プーラーを検出しました。プリペアドステートメントのエラーが発生する場合はDB_POOL_MODE=transactionを設定してください", "reason", poolerReason)
+		}
+	}
+
+	// 文の実行時間の上限（サーバー側のstatement_timeoutと、クエリごとのコンテキストの期限）
+	// pgbouncerは既定で未知の接続時パラメータを拒否するため、互換モードではstatement_timeoutを設定しない
+	if cfg.DB.StatementTimeout > 0 && transactionPooling {
+		l.Warn("接続プーラーとの互換モードではstatement_timeoutを設定しません。pgbouncerやロール側で設定してください")
+	} else {
+		postgres.SetStatementTimeout(dbConfig, cfg.DB.StatementTimeout)
+	}
+	postgres.SetQueryTimeout(cfg.DB.QueryTimeout)
+
+	// クエリのトレース（リクエストのスパンの子としてクエリごとのスパンを記録する）
+	var queryTracers []pgx.QueryTracer
+	if cfg.Tracing.Enabled {
+		queryTracers = append(queryTracers, otelpgx.NewTracer(otelpgx.WithTrimSQLInSpanName()))
+	}
+
+	// クエリのサンプリングと実行計画のログ出力
+	var queryTracer *postgres.QueryTracer
+	if cfg.DB.QuerySampleRate > 0 {
+		analyze := cfg.DB.ExplainAnalyze && cfg.App.Env == "development"
+		queryTracer = postgres.NewQueryTracer(cfg.DB.QuerySampleRate, analyze, l)
+		queryTracers = append(queryTracers, queryTracer)
+		l.Info("クエリの実行計画サンプリングを有効化しました", "sample_rate", cfg.DB.QuerySampleRate, "analyze", analyze)
+	}
+
+	// 低速なクエリのログ出力
+	if cfg.DB.SlowQueryThreshold > 0 {
+		queryTracers = append(queryTracers, postgres.NewSlowQueryLogger(cfg.DB.SlowQueryThreshold, l))
+		l.Info("低速なクエリのログ出力を有効化しました", "threshold_ms", cfg.DB.SlowQueryThreshold.Milliseconds())
+	}
+	if len(queryTracers) > 0 {
+		dbConfig.ConnConfig.Tracer = multitracer.New(queryTracers...)
+	}
+
 	// データベース接続プールの作成
 	db, err := pgxpool.NewWithConfig(ctx, dbConfig)
 	if err != nil {
@@ -74,18 +176,562 @@ func main() {
 	}
 	defer db.Close()
 
+	if queryTracer != nil {
+		queryTracer.SetPool(db)
+	}
+
 	// 接続テスト
 	if err := db.Ping(ctx); err != nil {
 		l.Fatal("データベース接続テストに失敗しました", "error", err)
 	}
 	l.Info("データベースに正常に接続しました")
 
+	// 一時的なエラーで失敗したクエリの再試行
+	postgres.SetRetryPolicy(postgres.RetryPolicy{
+		MaxAttempts: cfg.DB.RetryMaxAttempts,
+		BaseDelay:   cfg.DB.RetryBaseDelay,
+		MaxDelay:    time.Second,
+	})
+
+	// 接続プールの死活確認（フェイルオーバー時にレディネスを落として接続を張り直す）
+	dbHealth := postgres.NewHealthMonitor(db, cfg.DB.HealthCheckInterval, cfg.DB.HealthFailureThreshold, l)
+	dbHealth.Start()
+
+	// 読み取り専用のレプリカ（タイムライン・プロフィール・検索の読み取りをレプリカで実行し、接続できない間はプライマリで実行する）
+	// レプリカの死活はレディネスに影響しない
+	var replicaHealth *postgres.HealthMonitor
+	if cfg.DB.ReplicaURL != "" {
+		replicaConfig, err := pgxpool.ParseConfig(cfg.DB.ReplicaURL)
+		if err != nil {
+			l.Fatal("読み取りレプリカの接続文字列の解析に失敗しました", "error", err)
+		}
+		replicaConfig.MaxConns = dbConfig.MaxConns
+		replicaConfig.MinConns = dbConfig.MinConns
+		replicaConfig.MaxConnLifetime = dbConfig.MaxConnLifetime
+		replicaConfig.MaxConnIdleTime = dbConfig.MaxConnIdleTime
+		// プライマリと同じ接続プーラーの互換モード・statement_timeout・トレースを使う
+		replicaConfig.ConnConfig.DefaultQueryExecMode = dbConfig.ConnConfig.DefaultQueryExecMode
+		replicaConfig.ConnConfig.StatementCacheCapacity = dbConfig.ConnConfig.StatementCacheCapacity
+		replicaConfig.ConnConfig.DescriptionCacheCapacity = dbConfig.ConnConfig.DescriptionCacheCapacity
+		if timeout, ok := dbConfig.ConnConfig.RuntimeParams["statement_timeout"]; ok {
+			replicaConfig.ConnConfig.RuntimeParams["statement_timeout"] = timeout
+		}
+		replicaConfig.ConnConfig.Tracer = dbConfig.ConnConfig.Tracer
+
+		replicaDB, err := pgxpool.NewWithConfig(ctx, replicaConfig)
+		if err != nil {
+			l.Fatal("読み取りレプリカの接続プールの作成に失敗しました", "error", err)
+		}
+		defer replicaDB.Close()
+
+		if err := replicaDB.Ping(ctx); err != nil {
+			l.Warn("読み取りレプリカに接続できません。接続できるまでプライマリで読み取ります", "error", err)
+		}
+		replicaHealth = postgres.NewHealthMonitor(replicaDB, cfg.DB.HealthCheckInterval, cfg.DB.HealthFailureThreshold, l)
+		replicaHealth.Start()
+		postgres.UseReadReplica(db, replicaDB, replicaHealth)
+		l.Info("読み取りレプリカを有効化しました")
+	}
+
 	// リポジトリの初期化
 	userRepo := postgres.NewUserRepository(db)
 	postRepo := postgres.NewPostRepository(db)
 	followRepo := postgres.NewFollowRepository(db)
 	likeRepo := postgres.NewLikeRepository(db)
+	reactionRepo := postgres.NewReactionRepository(db)
 	notificationRepo := postgres.NewNotificationRepository(db)
+	blockRepo := postgres.NewBlockRepository(db)
+	listRepo := postgres.NewListRepository(db)
+	conversationMuteRepo := postgres.NewConversationMuteRepository(db)
+	supporterRepo := postgres.NewSupporterRepository(db)
+	postViewRepo := postgres.NewPostViewRepository(db)
+	settingsRepo := postgres.NewSettingsRepository(db)
+	auditRepo := postgres.NewAuditLogRepository(db)
+	reportRepo := postgres.NewReportRepository(db)
+	contentFilterRepo := postgres.NewContentFilterRepository(db)
+	trendRepo := postgres.NewTrendRepository(db)
+	topicRepo := postgres.NewTopicRepository(db)
+
+	// 複数のリポジトリにまたがる処理のトランザクション
+	txManager := postgres.NewTxManager(db)
+
+	// バックグラウンドジョブのキュー（各機能が処理を登録し、サーバーの起動前にワーカーを起動する）
+	jobQueue := jobs.NewQueue(
+		postgres.NewJobRepository(db),
+		txManager,
+		cfg.Jobs.Workers,
+		cfg.Jobs.PollInterval,
+		cfg.Jobs.Timeout,
+		cfg.Jobs.MaxAttempts,
+		cfg.Jobs.RetryDelay,
+		cfg.Jobs.Retention,
+		l,
+	)
+
+	// いいね数・フォロワー数などのカウンター（保守タスクとして定期的に元のテーブルから再計算する）
+	counterRepo := postgres.NewCounterRepository(db)
+	counters := service.NewCounterService(counterRepo)
+
+	// フォローイベントの射影（記録だけされたイベントを一定間隔でfollowsに反映する）
+	followProjector := service.NewFollowProjectorService(followRepo, counterRepo, cfg.Follows.ProjectInterval, cfg.Follows.ProjectBatchSize, l)
+	followProjector.Start()
+
+	// 閲覧数の集計（一定間隔でまとめて書き込む）
+	viewCounter := service.NewViewCounterService(postViewRepo, cfg.Views.FlushInterval, cfg.Views.MaxPending, l)
+	viewCounter.Start()
+
+	// ユーザー統計（活動の記録と毎日のコホート統計の集計）
+	userStatsRepo := postgres.NewUserStatsRepository(db)
+	userStats := service.NewUserStatsService(userStatsRepo, cfg.Stats.RollupHour, l)
+	userStats.Start()
+
+	// インスタンスディレクトリ向けの公開統計（定期的に集計する。無効の場合は公開エンドポイントが404を返す）
+	var instanceStats *service.InstanceStatsService
+	if cfg.Stats.PublicEnabled {
+		instanceStats = service.NewInstanceStatsService(userStatsRepo, cfg.Stats.PublicRefreshInterval, l)
+		instanceStats.Start()
+	}
+
+	// API利用状況の集計（1時間単位で集計し、一定間隔でまとめて書き込む）
+	apiUsageRepo := postgres.NewAPIUsageRepository(db)
+	apiUsage := service.NewAPIUsageService(apiUsageRepo, cfg.Usage.FlushInterval, cfg.Usage.MaxPending, l)
+	apiUsage.Start()
+
+	// ストレージプロバイダーの作成
+	var storageProvider coreinterfaces.StorageProvider
+	if cfg.Storage.Provider == "local" {
+		storageProvider = storage.NewLocalStorage(cfg.Storage.BaseDir, cfg.Storage.BaseURL, l)
+	} else {
+		l.Warn("ストレージプロバイダー設定が無効です。ローカルストレージを使用します", "provider", cfg.Storage.Provider)
+		storageProvider = storage.NewLocalStorage(cfg.Storage.BaseDir, cfg.Storage.BaseURL, l)
+	}
+
+	// セカンダリへの非同期複製（プライマリの障害時は読み込みをセカンダリへ振り替える）
+	var replicatedStorage *storage.ReplicatedStorage
+	if cfg.Storage.ReplicaEnabled {
+		secondary := storage.NewLocalStorage(cfg.Storage.ReplicaBaseDir, cfg.Storage.ReplicaBaseURL, l)
+		replicatedStorage = storage.NewReplicatedStorage(
+			storageProvider,
+			secondary,
+			cfg.Storage.ReplicaWorkers,
+			cfg.Storage.ReplicaQueueSize,
+			cfg.Storage.ReplicaMaxAttempts,
+			cfg.Storage.ReplicaHealthInterval,
+			l,
+		)
+		replicatedStorage.Start()
+		storageProvider = replicatedStorage
+	}
+
+	// アップロードされたメディア（内容のハッシュが同じファイルは1つだけ保存して共有する。ユーザーごとの使用量に上限を設ける）
+	mediaRepo := postgres.NewMediaRepository(db)
+	media := service.NewMediaService(
+		mediaRepo,
+		postgres.NewStorageUsageRepository(db),
+		storageProvider,
+		cfg.Storage.FFmpegPath,
+		cfg.Storage.UserQuota,
+		l,
+	)
+
+	// 分割アップロード（パートを結合してからメディアとして保存する。期限切れのアップロードは定期的に削除する）
+	uploadSessionRepo := postgres.NewUploadSessionRepository(db)
+	uploadSessions := service.NewUploadSessionService(
+		uploadSessionRepo,
+		storage.NewLocalUploadPartStore(cfg.Storage.UploadPartsDir, l),
+		media,
+		cfg.Storage.UploadPartSize,
+		cfg.Storage.UploadSessionTTL,
+		l,
+	)
+	uploadSessions.Start()
+
+	// Redis（タイムラインのキャッシュ・レート制限・WebSocketの中継で使用。接続できない場合はそれぞれデータベースとプロセス内の処理に切り替える）
+	var redisClient *redis.Client
+	if cfg.Timeline.CacheEnabled || cfg.RateLimit.Backend == "redis" || cfg.WebSocket.Broker == "redis" {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		if err := redisClient.Ping(ctx).Err(); err != nil {
+			l.Warn("Redisに接続できないため、Redisを使用する機能を無効化します", "error", err)
+			redisClient.Close()
+			redisClient = nil
+		} else {
+			l.Info("Redisに正常に接続しました")
+		}
+	}
+
+	// WebSocketハブ（通知の配信と接続の管理。複数のAPIサーバーで動かす場合はRedisで通知を中継する）
+	hub := websocket.NewHub(l)
+	hub.SetMaxConnectionsPerUser(cfg.WebSocket.MaxConnectionsPerUser)
+	if cfg.WebSocket.Broker == "redis" {
+		if redisClient != nil {
+			hub.SetBroker(redisrepo.NewHubBroker(redisClient, cfg.WebSocket.Channel))
+		} else {
+			l.Warn("Redisに接続できないため、WebSocketの通知はこのサーバーのクライアントにのみ配信します")
+		}
+	}
+	// 再接続したクライアントへの通知の再送（中継する場合はどのサーバーに再接続しても再送できるようRedisに保存する）
+	if cfg.WebSocket.Broker == "redis" && redisClient != nil {
+		hub.SetEventBuffer(redisrepo.NewEventBuffer(redisClient, cfg.WebSocket.ReplayTTL, cfg.WebSocket.ReplayMaxEvents))
+	} else {
+		hub.SetEventBuffer(websocket.NewMemoryEventBuffer(cfg.WebSocket.ReplayTTL, cfg.WebSocket.ReplayMaxEvents))
+	}
+
+	// オンライン状態（WebSocketの通知をRedisで中継する場合は、オンライン状態もRedisで共有する）
+	var presenceStore interfaces.PresenceStore
+	if cfg.WebSocket.Broker == "redis" && redisClient != nil {
+		presenceStore = redisrepo.NewPresenceStore(redisClient)
+	}
+	presenceRepo := postgres.NewPresenceRepository(db)
+	presence := service.NewPresenceService(
+		hub,
+		presenceStore,
+		presenceRepo,
+		cfg.Presence.TTL,
+		cfg.Presence.RefreshInterval,
+		l,
+	)
+	presence.Start()
+	go hub.Run()
+
+	// アカウントの削除（関連データをバックグラウンドで順に削除する）
+	accountDeletionRepo := postgres.NewAccountDeletionRepository(db)
+	accountDeletion := service.NewAccountDeletionService(
+		accountDeletionRepo,
+		userRepo,
+		txManager,
+		storageProvider,
+		media,
+		hub,
+		cfg.Accounts.DeletionPollInterval,
+		cfg.Accounts.DeletionMaxAttempts,
+		l,
+	)
+	accountDeletion.Start()
+
+	// アカウントの統合（管理者が開始し、統合元のデータをバックグラウンドで統合先へ移す）
+	accountMergeRepo := postgres.NewAccountMergeRepository(db)
+	accountMerge := service.NewAccountMergeService(
+		accountMergeRepo,
+		userRepo,
+		txManager,
+		hub,
+		cfg.Accounts.MergePollInterval,
+		cfg.Accounts.MergeMaxAttempts,
+		l,
+	)
+	accountMerge.Start()
+
+	// 投稿・ユーザーの検索エンジン（設定された場合は作成・更新を索引へ非同期に反映し、検索エンジンで検索する）
+	var searchIndexer coreinterfaces.SearchIndexer
+	switch cfg.Search.Backend {
+	case "postgres", "":
+	case "elasticsearch":
+		searchIndexer = search.NewElasticsearchIndexer(
+			cfg.Search.ElasticsearchURL,
+			cfg.Search.ElasticsearchUsername,
+			cfg.Search.ElasticsearchPassword,
+			cfg.Search.IndexPrefix,
+		)
+		setupCtx, setupCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := searchIndexer.Setup(setupCtx); err != nil {
+			l.Warn("検索エンジンの索引を作成できませんでした", "error", err, "url", cfg.Search.ElasticsearchURL)
+		}
+		setupCancel()
+		l.Info("投稿・ユーザーの検索に検索エンジンを使用します", "backend", searchIndexer.Name())
+	default:
+		l.Warn("検索エンジンの設定が無効です。データベースで検索します", "backend", cfg.Search.Backend)
+	}
+	searchIndex := service.NewSearchIndexService(
+		searchIndexer,
+		postRepo,
+		userRepo,
+		cfg.Search.IndexWorkers,
+		cfg.Search.IndexQueueSize,
+		l,
+	)
+	searchIndex.Start()
+	reindexCtx, reindexCancel := context.WithCancel(context.Background())
+	if cfg.Search.ReindexOnStart && searchIndexer != nil {
+		go func() {
+			posts, users, err := searchIndex.Reindex(reindexCtx)
+			if err != nil {
+				l.Error("検索エンジンの索引の作り直しに失敗しました", "error", err, "posts", posts, "users", users)
+				return
+			}
+			l.Info("検索エンジンの索引を作り直しました", "posts", posts, "users", users)
+		}()
+	}
+
+	// ホームタイムラインのキャッシュ（Redisに接続できない場合はデータベースから取得する）
+	var timelineCache interfaces.TimelineCache
+	if cfg.Timeline.CacheEnabled && redisClient != nil {
+		timelineCache = redisrepo.NewTimelineCache(redisClient, cfg.Timeline.CacheMaxLength, cfg.Timeline.CacheTTL)
+	}
+	timelineFanout := service.NewTimelineFanoutService(
+		timelineCache,
+		followRepo,
+		postRepo,
+		cfg.Timeline.CacheMinFollowing,
+		cfg.Timeline.CacheMaxLength,
+		cfg.Timeline.FanoutWorkers,
+		cfg.Timeline.FanoutQueueSize,
+		l,
+	)
+	timelineFanout.Start()
+
+	// スレッドの展開（リーダーモード。スレッドの構造はタイムラインと同じRedisにキャッシュする）
+	var threadCache interfaces.ThreadCache
+	if cfg.Timeline.CacheEnabled && redisClient != nil {
+		threadCache = redisrepo.NewThreadCache(redisClient, cfg.Threads.UnrollCacheTTL)
+	}
+	threadUnroll := service.NewThreadUnrollService(postRepo, threadCache, cfg.Threads.UnrollMaxPosts, l)
+
+	// 期限切れの投稿の削除（期限を過ぎた投稿は削除されるまでの間も表示されない）
+	postExpiration := service.NewPostExpirationService(
+		postRepo,
+		media,
+		threadUnroll,
+		cfg.Posts.ExpirationPurgeInterval,
+		cfg.Posts.ExpirationPurgeBatchSize,
+		l,
+	)
+	postExpiration.Start()
+
+	// クライアントの差分同期（変更はリポジトリが記録し、保持期間を過ぎたものを定期的に削除する）
+	syncService := service.NewSyncService(
+		postgres.NewSyncEventRepository(db),
+		cfg.Sync.Retention,
+		cfg.Sync.SettleDelay,
+		l,
+	)
+	syncService.Start()
+
+	// システムアカウント（存在しない場合は作成し、お知らせは全ユーザーのタイムラインへ配信する）
+	systemAccounts := service.NewSystemAccountService(
+		userRepo,
+		postRepo,
+		timelineFanout,
+		cfg.System.Accounts,
+		cfg.System.AnnouncementsAccount,
+		cfg.System.ReservedUsernames,
+		l,
+	)
+	if err := systemAccounts.Bootstrap(ctx); err != nil {
+		l.Error("システムアカウントの作成に失敗しました", "error", err)
+	}
+
+	// シングルサインオン（有効な場合はパスワードでのログインと登録を無効化する）
+	var ssoProvider *oidc.Provider
+	if cfg.SSO.Enabled {
+		ssoProvider = oidc.NewProvider(
+			cfg.SSO.Issuer,
+			cfg.SSO.ClientID,
+			cfg.SSO.ClientSecret,
+			cfg.SSO.RedirectURL,
+			cfg.SSO.Scopes,
+		)
+		l.Info("シングルサインオンが有効です", "issuer", cfg.SSO.Issuer)
+	}
+	identityRepo := postgres.NewUserIdentityRepository(db)
+
+	// 分析イベント（インプレッション・クリック・登録を設定された送信先へまとめて送信する）
+	var analyticsSink coreinterfaces.AnalyticsSink
+	switch cfg.Analytics.Sink {
+	case "none", "":
+	case "postgres":
+		analyticsSink = analytics.NewPostgresSink(postgres.NewAnalyticsEventRepository(db))
+	case "log":
+		analyticsSink, err = analytics.NewLogFileSink(cfg.Analytics.LogPath)
+		if err != nil {
+			l.Fatal("分析イベントの出力ファイルを開けませんでした", "error", err, "path", cfg.Analytics.LogPath)
+		}
+	case "http":
+		analyticsSink = analytics.NewHTTPSink(cfg.Analytics.HTTPEndpoint, cfg.Analytics.HTTPToken)
+	default:
+		l.Warn("分析イベントの送信先の設定が無効です。分析イベントは送信しません", "sink", cfg.Analytics.Sink)
+	}
+	analyticsService := service.NewAnalyticsService(
+		analyticsSink,
+		cfg.Analytics.BufferSize,
+		cfg.Analytics.BatchSize,
+		cfg.Analytics.FlushInterval,
+		l,
+	)
+	analyticsService.Start()
+
+	// オンボーディング（おすすめのユーザーと登録時の自動フォロー）
+	onboarding := service.NewOnboardingService(
+		userRepo,
+		followRepo,
+		blockRepo,
+		cfg.Onboarding.SuggestedAccounts,
+		cfg.Onboarding.AutoFollowAccounts,
+		cfg.Onboarding.SuggestionLimit,
+		cfg.Onboarding.MinFollows,
+		l,
+	)
+
+	sso := service.NewSSOService(
+		ssoProvider,
+		userRepo,
+		identityRepo,
+		txManager,
+		systemAccounts,
+		onboarding,
+		analyticsService,
+		cfg.SSO.GroupsClaim,
+		cfg.SSO.AllowedGroups,
+		cfg.SSO.AdminGroups,
+		cfg.SSO.ModeratorGroups,
+		cfg.SSO.PostLoginRedirectURL,
+		l,
+	)
+
+	// プロフィール訪問者（両方が有効にしている場合のみ記録し、古い履歴を定期的に削除する）
+	profileVisitRepo := postgres.NewProfileVisitRepository(db)
+	profileVisitors := service.NewProfileVisitorService(
+		profileVisitRepo,
+		settingsRepo,
+		cfg.Visitors.SampleRate,
+		cfg.Visitors.Retention,
+		cfg.Visitors.MaxPerUser,
+		l,
+	)
+	profileVisitors.Start()
+
+	// 個人用Webhook（自分へのフォロー・メンションをユーザーが登録したURLへ送信する）
+	webhookRepo := postgres.NewWebhookRepository(db)
+	webhooks := service.NewWebhookService(
+		webhookRepo,
+		cfg.Webhooks.MaxPerUser,
+		cfg.Webhooks.Timeout,
+		cfg.Webhooks.MaxFailures,
+		cfg.Webhooks.Workers,
+		cfg.Webhooks.QueueSize,
+		cfg.Webhooks.AllowPrivateNetworks,
+		l,
+	)
+	webhooks.Start()
+
+	// プッシュ通知（設定された送信先の端末へ、通知をWebSocketと合わせて送信する）
+	var pushProviders []coreinterfaces.PushProvider
+	if cfg.Push.FCMCredentialsFile != "" {
+		fcm, err := push.NewFCMProvider(cfg.Push.FCMCredentialsFile, cfg.Push.Timeout)
+		if err != nil {
+			l.Fatal("FCMの設定を読み込めませんでした", "error", err)
+		}
+		pushProviders = append(pushProviders, fcm)
+	}
+	if cfg.Push.APNsKeyFile != "" {
+		apns, err := push.NewAPNsProvider(
+			cfg.Push.APNsKeyFile,
+			cfg.Push.APNsKeyID,
+			cfg.Push.APNsTeamID,
+			cfg.Push.APNsBundleID,
+			cfg.Push.APNsSandbox,
+			cfg.Push.Timeout,
+		)
+		if err != nil {
+			l.Fatal("APNsの設定を読み込めませんでした", "error", err)
+		}
+		pushProviders = append(pushProviders, apns)
+	}
+	var pushService *service.PushService
+	if len(pushProviders) > 0 {
+		pushService = service.NewPushService(
+			postgres.NewDeviceTokenRepository(db),
+			pushProviders,
+			cfg.Push.MaxAttempts,
+			cfg.Push.RetryDelay,
+			cfg.Push.Timeout,
+			cfg.Push.Workers,
+			cfg.Push.QueueSize,
+			l,
+		)
+		pushService.Start()
+	}
+
+	// メール通知（オフラインのユーザーへフォロー・メンションをメールで送信する）
+	notificationSettingsRepo := postgres.NewNotificationSettingsRepository(db)
+	var emailNotifications *service.EmailNotificationService
+	if cfg.Email.SMTPHost != "" {
+		sender, err := email.NewSMTPSender(
+			cfg.Email.SMTPHost,
+			cfg.Email.SMTPPort,
+			cfg.Email.SMTPUsername,
+			cfg.Email.SMTPPassword,
+			cfg.Email.FromAddress,
+			cfg.Email.FromName,
+			cfg.Email.Timeout,
+		)
+		if err != nil {
+			l.Fatal("メールの送信の設定が不正です", "error", err)
+		}
+		emailNotifications = service.NewEmailNotificationService(
+			notificationSettingsRepo,
+			userRepo,
+			hub,
+			sender,
+			cfg.App.Name,
+			cfg.App.URL,
+			cfg.Email.Timeout,
+			cfg.Email.Workers,
+			cfg.Email.QueueSize,
+			l,
+		)
+		emailNotifications.Start()
+	}
+
+	// 通知の配信（通知と同じトランザクションでアウトボックスに保存した配信イベントを、成功するまで再試行して配信する）
+	outbox := service.NewOutboxService(
+		postgres.NewOutboxRepository(db),
+		txManager,
+		hub,
+		webhooks,
+		pushService,
+		emailNotifications,
+		cfg.Outbox.PollInterval,
+		cfg.Outbox.BatchSize,
+		cfg.Outbox.MaxAttempts,
+		cfg.Outbox.RetryDelay,
+		l,
+	)
+	outbox.Start()
+
+	// 検索（検索履歴・保存した検索と新着投稿の定期確認。新着投稿の通知はアウトボックスを通じて配信する）
+	searchRepo := postgres.NewSearchRepository(db)
+	searchService := service.NewSearchService(
+		searchRepo,
+		postRepo,
+		service.NewNotificationService(
+			notificationRepo,
+			userRepo,
+			postRepo,
+			blockRepo,
+			notificationSettingsRepo,
+			txManager,
+			outbox,
+			l,
+		),
+		service.NewBlockService(blockRepo, l),
+		cfg.Search.SavedCheckInterval,
+		l,
+	)
+	searchService.Start()
+
+	// レート制限（複数のAPIサーバーで共有する場合はRedisに保存する。nilの場合はプロセス内で数える）
+	var rateLimiter interfaces.RateLimiter
+	if cfg.RateLimit.Backend == "redis" {
+		if redisClient != nil {
+			rateLimiter = redisrepo.NewRateLimiter(redisClient)
+		} else {
+			l.Warn("Redisに接続できないため、レート制限はプロセス内で数えます")
+		}
+	}
 
 	// ルーターのセットアップ
 	router := routes.SetupRouter(
@@ -95,9 +741,88 @@ func main() {
 		postRepo,
 		followRepo,
 		likeRepo,
+		reactionRepo,
 		notificationRepo,
+		blockRepo,
+		listRepo,
+		conversationMuteRepo,
+		supporterRepo,
+		postViewRepo,
+		auditRepo,
+		reportRepo,
+		contentFilterRepo,
+		webhookRepo,
+		uploadSessionRepo,
+		followProjector,
+		viewCounter,
+		userStats,
+		apiUsage,
+		settingsRepo,
+		txManager,
+		searchService,
+		timelineFanout,
+		profileVisitors,
+		systemAccounts,
+		dbHealth,
+		rateLimiter,
+		storageProvider,
+		media,
+		hub,
+		accountDeletion,
+		accountMerge,
+		sso,
+		onboarding,
+		threadUnroll,
+		analyticsService,
+		webhooks,
+		uploadSessions,
+		instanceStats,
+		syncService,
+		pushService,
+		emailNotifications,
+		notificationSettingsRepo,
+		outbox,
+		trendRepo,
+		presence,
+		topicRepo,
+		searchIndex,
 	)
 
+	// 保守タスクの定期実行（実行時刻ごとにジョブとして登録し、ジョブのワーカーが実行する）
+	maintenance := service.NewMaintenanceService(
+		counters,
+		notificationRepo,
+		trendRepo,
+		media,
+		cfg.Scheduler.NotificationRetention,
+		cfg.Scheduler.TrendRetention,
+		cfg.Scheduler.MediaGracePeriod,
+		l,
+	)
+	scheduler := jobs.NewScheduler(jobQueue, l)
+	scheduledTasks := []struct {
+		name    string
+		config  config.ScheduledTaskConfig
+		handler jobs.Handler
+	}{
+		{"counter_reconcile", cfg.Scheduler.CounterReconcile, maintenance.ReconcileCounters},
+		{"notification_cleanup", cfg.Scheduler.NotificationCleanup, maintenance.PurgeNotifications},
+		{"trend_aggregation", cfg.Scheduler.TrendAggregation, maintenance.AggregateTrends},
+		{"media_sweep", cfg.Scheduler.MediaSweep, maintenance.SweepOrphanedMedia},
+	}
+	for _, task := range scheduledTasks {
+		if !task.config.Enabled {
+			continue
+		}
+		if err := scheduler.Add(task.name, task.config.Schedule, task.handler); err != nil {
+			l.Fatal("保守タスクの登録に失敗しました", "task", task.name, "error", err)
+		}
+	}
+
+	// 処理の登録が済んだジョブのワーカーを起動する
+	jobQueue.Start()
+	scheduler.Start()
+
 	// HTTPサーバーの設定
 	server := &http.Server{
 		Addr:         ":" + cfg.App.Port,
@@ -128,5 +853,68 @@ func main() {
 		l.Fatal("サーバーの強制シャットダウンが発生しました", "error", err)
 	}
 
+	// WebSocketの接続はサーバーのシャットダウンの対象外のため、クライアントにクローズフレームを送って切断する
+	hubCtx, hubCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer hubCancel()
+	if err := hub.Shutdown(hubCtx); err != nil {
+		l.Warn("WebSocketクライアントの切断が完了しませんでした", "error", err)
+	}
+	// 切断したユーザーの最終ログイン日時を記録する
+	presence.Stop()
+
+	// 未書き込みの閲覧数を書き込む
+	viewCounter.Stop()
+	userStats.Stop()
+	apiUsage.Stop()
+	accountDeletion.Stop()
+	accountMerge.Stop()
+	searchService.Stop()
+	reindexCancel()
+	searchIndex.Stop()
+	if searchIndexer != nil {
+		searchIndexer.Close()
+	}
+	profileVisitors.Stop()
+	followProjector.Stop()
+	analyticsService.Stop()
+	// 実行中のジョブの終了を待つ
+	scheduler.Stop()
+	jobQueue.Stop()
+	// 配信先のキューを止める前に、アウトボックスからの配信を止める
+	outbox.Stop()
+	webhooks.Stop()
+	postExpiration.Stop()
+	uploadSessions.Stop()
+	syncService.Stop()
+	if pushService != nil {
+		pushService.Stop()
+	}
+	if emailNotifications != nil {
+		emailNotifications.Stop()
+	}
+	if instanceStats != nil {
+		instanceStats.Stop()
+	}
+	if replicatedStorage != nil {
+		replicatedStorage.Stop()
+	}
+
+	// 配信待ちの投稿をタイムラインのキャッシュへ配信する
+	timelineFanout.Stop()
+	if redisClient != nil {
+		redisClient.Close()
+	}
+	dbHealth.Stop()
+	if replicaHealth != nil {
+		replicaHealth.Stop()
+	}
+
+	// 未送信のスパンを送信する
+	tracingCtx, tracingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer tracingCancel()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		l.Warn("トレースの送信に失敗しました", "error", err)
+	}
+
 	l.Info("サーバーを終了します")
 }
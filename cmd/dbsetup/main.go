@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
@@ -15,11 +17,54 @@ func main() {
 	// コマンドライン引数の解析
 	var (
 		envFile        = flag.String("env", ".env", "環境変数ファイルのパス")
-		migrationsPath = flag.String("migrations", "migrations", "マイグレーションファイルのディレクトリパス")
+		migrationsPath = flag.String("migrations", "", "マイグレーションファイルのディレクトリパス（空の場合はバイナリに埋め込まれたマイグレーションを使用する）")
 		rollback       = flag.Bool("rollback", false, "最後のマイグレーションをロールバックする")
 		version        = flag.Bool("version", false, "現在のマイグレーションバージョンを表示する")
+		steps          = flag.Int("steps", 0, "マイグレーションをN件適用する（負の値の場合はN件ロールバックする）")
+		gotoVersion    = flag.Uint("goto", 0, "指定したバージョンまでマイグレーションを適用またはロールバックする")
+		forceVersion   = flag.Int("force", 0, "マイグレーションを実行せずに適用済みのバージョンを設定し、ダーティ状態を解除する（-1で未適用）")
 	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "使い方: %s [オプション] [lint|drift]\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "  lint   マイグレーションファイルを検証する（データベース接続不要）")
+		fmt.Fprintln(flag.CommandLine.Output(), "  drift  マイグレーションファイルを検証し、ライブスキーマとの差分を検出する")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
+	command := flag.Arg(0)
+
+	// マイグレーションの操作は1つだけ指定できる
+	operations := 0
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+		switch f.Name {
+		case "rollback", "version", "steps", "goto", "force":
+			operations++
+		}
+	})
+	if operations > 1 || (operations > 0 && command != "") {
+		fmt.Fprintln(flag.CommandLine.Output(), "-rollback, -version, -steps, -goto, -force はいずれか1つだけ指定できます")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch command {
+	case "", "lint", "drift":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// マイグレーションファイルの検証（CIで使用するためデータベース接続前に実行）
+	if command == "lint" || command == "drift" {
+		if !lintMigrations(*migrationsPath) {
+			os.Exit(1)
+		}
+		if command == "lint" {
+			return
+		}
+	}
 
 	// 環境変数ファイルの読み込み
 	loadEnvFile(*envFile)
@@ -63,7 +108,22 @@ func main() {
 	}
 
 	// マイグレーションの実行
-	if *rollback {
+	if command == "drift" {
+		// スキーマドリフトの検出
+		log.Println("ライブスキーマとマイグレーションの差分を確認しています...")
+		report, err := database.DetectSchemaDrift(context.Background(), db, migrationOptions)
+		if err != nil {
+			log.Fatalf("スキーマドリフトの検出に失敗しました: %v", err)
+		}
+		fmt.Print(database.FormatDriftReport(report))
+		if report.HasDrift() {
+			log.Println("スキーマドリフトが検出されました")
+			db.Close()
+			os.Exit(1)
+		}
+		log.Println("スキーマドリフトはありません")
+		return
+	} else if *rollback {
 		// ロールバック
 		log.Println("マイグレーションをロールバックしています...")
 		if err := database.RollbackMigration(db, migrationOptions); err != nil {
@@ -72,9 +132,22 @@ func main() {
 		log.Println("マイグレーションのロールバックが完了しました")
 	} else if *version {
 		// バージョン表示
-		log.Println("現在のマイグレーションバージョンを表示します")
-		// TODO: バージョン表示の実装
-		log.Println("注意: バージョン表示機能は未実装です")
+		printMigrationVersion(db, migrationOptions)
+	} else if setFlags["steps"] {
+		// 指定した件数の適用・ロールバック
+		if err := database.MigrateSteps(db, migrationOptions, *steps); err != nil {
+			log.Fatalf("マイグレーションの実行に失敗しました: %v", err)
+		}
+	} else if setFlags["goto"] {
+		// 指定したバージョンへの移動
+		if err := database.MigrateTo(db, migrationOptions, *gotoVersion); err != nil {
+			log.Fatalf("マイグレーションの実行に失敗しました: %v", err)
+		}
+	} else if setFlags["force"] {
+		// ダーティ状態からの復旧
+		if err := database.ForceMigrationVersion(db, migrationOptions, *forceVersion); err != nil {
+			log.Fatalf("マイグレーションバージョンの設定に失敗しました: %v", err)
+		}
 	} else {
 		// マイグレーション実行
 		log.Println("マイグレーションを実行しています...")
@@ -87,6 +160,64 @@ func main() {
 	log.Println("データベースセットアップが正常に完了しました")
 }
 
+// printMigrationVersion は適用済みのマイグレーションバージョンと未適用のマイグレーションの件数を出力します
+func printMigrationVersion(db *database.PostgresDB, options *database.MigrationOptions) {
+	current, dirty, applied, err := database.MigrationVersion(db, options)
+	if err != nil {
+		log.Fatalf("マイグレーションバージョンの取得に失敗しました: %v", err)
+	}
+
+	files, _, err := database.LoadMigrationFiles(options.MigrationsPath)
+	if err != nil {
+		log.Fatalf("マイグレーションファイルの読み込みに失敗しました: %v", err)
+	}
+	var latest uint
+	pending := 0
+	for _, file := range files {
+		if file.Direction != "up" {
+			continue
+		}
+		if file.Version > latest {
+			latest = file.Version
+		}
+		if !applied || file.Version > current {
+			pending++
+		}
+	}
+
+	if applied {
+		fmt.Printf("現在のバージョン: %d\n", current)
+	} else {
+		fmt.Println("現在のバージョン: なし（マイグレーションは実行されていません）")
+	}
+	fmt.Printf("最新のバージョン: %d\n", latest)
+	fmt.Printf("未適用のマイグレーション: %d 件\n", pending)
+	if dirty {
+		fmt.Printf("警告: バージョン %d のマイグレーションが途中で失敗した「ダーティ」状態です。修復後に -force VERSION で解除してください\n", current)
+	}
+}
+
+// lintMigrations はマイグレーションファイルを検証して結果を出力し、エラーがなければtrueを返します
+func lintMigrations(migrationsPath string) bool {
+	log.Println("マイグレーションファイルを検証しています...")
+	issues, err := database.LintMigrations(migrationsPath)
+	if err != nil {
+		log.Fatalf("マイグレーションファイルの検証に失敗しました: %v", err)
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue.String())
+	}
+
+	if database.HasLintErrors(issues) {
+		log.Printf("マイグレーションファイルにエラーがあります (%d 件の問題)", len(issues))
+		return false
+	}
+
+	log.Printf("マイグレーションファイルの検証に成功しました (%d 件の警告)", len(issues))
+	return true
+}
+
 // loadEnvFile は環境変数ファイルを読み込みます
 func loadEnvFile(envPath string) {
 	// 絶対パスに変換
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/postgres"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JWTの署名鍵のバイト数
+const jwtSecretBytes = 48
+
+// 削除の完了を待つ場合の進捗の確認間隔
+const purgeWaitInterval = 5 * time.Second
+
+// adminApp 管理コマンドが使用するリポジトリとサービス
+type adminApp struct {
+	userRepo     interfaces.UserRepository
+	auditRepo    interfaces.AuditLogRepository
+	deletionRepo interfaces.AccountDeletionRepository
+	txManager    interfaces.TxManager
+	counters     *service.CounterService
+}
+
+// newAdminApp APIサーバーと同じリポジトリを作成する
+func newAdminApp(db *pgxpool.Pool) *adminApp {
+	return &adminApp{
+		userRepo:     postgres.NewUserRepository(db),
+		auditRepo:    postgres.NewAuditLogRepository(db),
+		deletionRepo: postgres.NewAccountDeletionRepository(db),
+		txManager:    postgres.NewTxManager(db),
+		counters:     service.NewCounterService(postgres.NewCounterRepository(db)),
+	}
+}
+
+// findUser ユーザー名またはIDでユーザーを取得する（停止中・無効化されたアカウントも対象とする）
+func (a *adminApp) findUser(ctx context.Context, identifier string) (*models.User, error) {
+	if id, err := uuid.Parse(identifier); err == nil {
+		user, err := a.userRepo.GetByIDIncludingInactive(ctx, id)
+		if err != nil {
+			if err.Error() == "user not found" {
+				return nil, fmt.Errorf("ユーザーが見つかりません: %s", identifier)
+			}
+			return nil, err
+		}
+		return user, nil
+	}
+
+	username := strings.TrimPrefix(identifier, "@")
+	user, err := a.userRepo.GetByUsername(ctx, username)
+	if err == nil {
+		return user, nil
+	}
+	if err.Error() != "user not found" {
+		return nil, err
+	}
+
+	// 停止中のアカウントなど、通常の取得では隠されるユーザー
+	users, err := a.userRepo.ListProvisioned(ctx, username, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("ユーザーが見つかりません: %s", identifier)
+	}
+	return users[0], nil
+}
+
+// audited fnと監査ログの記録を同じトランザクションで実行する
+func (a *adminApp) audited(ctx context.Context, action models.AuditAction, userID uuid.UUID, details map[string]string, fn func(ctx context.Context) error) error {
+	return a.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return a.auditRepo.Create(ctx, models.NewOperatorAuditLog(action, models.AuditTargetUser, userID, details))
+	})
+}
+
+// runPromote ユーザーの権限を変更する
+func runPromote(ctx context.Context, app *adminApp, args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ContinueOnError)
+	role := fs.String("role", string(models.UserRoleAdmin), "設定する権限（admin・moderator・user）")
+	identifier, err := parseUserArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if !models.UserRole(*role).IsValid() {
+		return errors.New("権限はuser・moderator・adminのいずれかを指定してください")
+	}
+
+	user, err := app.findUser(ctx, identifier)
+	if err != nil {
+		return err
+	}
+	if user.IsSystem {
+		return errors.New("システムアカウントの権限は変更できません")
+	}
+
+	newRole := models.UserRole(*role)
+	details := map[string]string{"role": string(newRole)}
+	err = app.audited(ctx, models.AuditUserRole, user.ID, details, func(ctx context.Context) error {
+		return app.userRepo.UpdateRole(ctx, user.ID, newRole)
+	})
+	if err != nil {
+		return fmt.Errorf("権限の変更に失敗しました: %w", err)
+	}
+
+	fmt.Printf("@%s の権限を %s から %s に変更しました（次にトークンを発行した時点から反映されます）\n", user.Username, user.Role, newRole)
+	return nil
+}
+
+// runVerify 認証バッジを付与・解除する
+func runVerify(ctx context.Context, app *adminApp, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	revoke := fs.Bool("revoke", false, "認証バッジを解除する")
+	identifier, err := parseUserArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	user, err := app.findUser(ctx, identifier)
+	if err != nil {
+		return err
+	}
+
+	verified := !*revoke
+	details := map[string]string{"verified": fmt.Sprint(verified)}
+	err = app.audited(ctx, models.AuditUserVerify, user.ID, details, func(ctx context.Context) error {
+		return app.userRepo.SetVerified(ctx, user.ID, verified)
+	})
+	if err != nil {
+		return fmt.Errorf("認証バッジの更新に失敗しました: %w", err)
+	}
+
+	if verified {
+		fmt.Printf("@%s に認証バッジを付与しました\n", user.Username)
+	} else {
+		fmt.Printf("@%s の認証バッジを解除しました\n", user.Username)
+	}
+	return nil
+}
+
+// runVerifyAge 確認した生年月日と居住国を登録して年齢確認済みにする（-revokeで取り消す）
+func runVerifyAge(ctx context.Context, app *adminApp, args []string) error {
+	fs := flag.NewFlagSet("verify-age", flag.ContinueOnError)
+	birthDateValue := fs.String("birth-date", "", "確認した生年月日（YYYY-MM-DD）")
+	country := fs.String("country", "", "確認した居住国（ISO 3166-1 alpha-2）")
+	revoke := fs.Bool("revoke", false, "年齢確認を取り消す")
+	identifier, err := parseUserArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var birthDate *time.Time
+	countryCode := strings.ToUpper(*country)
+	if !*revoke {
+		if *birthDateValue == "" || len(countryCode) != 2 {
+			return errors.New("-birth-dateと-country（2文字の国コード）を指定してください")
+		}
+		date, err := time.Parse("2006-01-02", *birthDateValue)
+		if err != nil || date.After(time.Now()) {
+			return fmt.Errorf("無効な生年月日です: %s", *birthDateValue)
+		}
+		birthDate = &date
+	}
+
+	user, err := app.findUser(ctx, identifier)
+	if err != nil {
+		return err
+	}
+
+	// 監査ログには生年月日を記録しない
+	details := map[string]string{"verified": fmt.Sprint(!*revoke)}
+	if countryCode != "" {
+		details["country_code"] = countryCode
+	}
+	err = app.audited(ctx, models.AuditUserAgeVerify, user.ID, details, func(ctx context.Context) error {
+		return app.userRepo.SetAgeVerification(ctx, user.ID, birthDate, countryCode)
+	})
+	if err != nil {
+		return fmt.Errorf("年齢確認の更新に失敗しました: %w", err)
+	}
+
+	if *revoke {
+		fmt.Printf("@%s の年齢確認を取り消しました\n", user.Username)
+	} else {
+		fmt.Printf("@%s を年齢確認済みにしました（国: %s）\n", user.Username, countryCode)
+	}
+	return nil
+}
+
+// runSuspend アカウントを停止する（-undoで停止を解除する）
+func runSuspend(ctx context.Context, app *adminApp, args []string) error {
+	fs := flag.NewFlagSet("suspend", flag.ContinueOnError)
+	undo := fs.Bool("undo", false, "停止を解除する")
+	identifier, err := parseUserArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	user, err := app.findUser(ctx, identifier)
+	if err != nil {
+		return err
+	}
+	if user.IsSystem {
+		return errors.New("システムアカウントは停止できません")
+	}
+
+	if *undo {
+		err = app.audited(ctx, models.AuditUserUnsuspend, user.ID, nil, func(ctx context.Context) error {
+			return app.userRepo.Unban(ctx, user.ID)
+		})
+		if err != nil {
+			if err.Error() == "user not found" {
+				return fmt.Errorf("@%s は停止されていません（状態: %s）", user.Username, user.Status)
+			}
+			return fmt.Errorf("停止の解除に失敗しました: %w", err)
+		}
+		fmt.Printf("@%s の停止を解除しました\n", user.Username)
+		return nil
+	}
+
+	details := map[string]string{"dry_run": "false"}
+	err = app.audited(ctx, models.AuditUserSuspend, user.ID, details, func(ctx context.Context) error {
+		return app.userRepo.Ban(ctx, user.ID)
+	})
+	if err != nil {
+		if err.Error() == "user not found" {
+			return fmt.Errorf("@%s は停止できる状態ではありません（状態: %s）", user.Username, user.Status)
+		}
+		return fmt.Errorf("アカウントの停止に失敗しました: %w", err)
+	}
+	fmt.Printf("@%s を停止しました\n", user.Username)
+	return nil
+}
+
+// runPurge アカウントとそのデータの削除を登録する
+// アカウントはすぐにすべてのエンドポイントから隠され、関連データはAPIサーバーのアカウント削除ワーカーが順に削除する
+func runPurge(ctx context.Context, app *adminApp, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "確認なしで削除する（元に戻せません）")
+	wait := fs.Bool("wait", false, "削除が完了するまで待つ")
+	identifier, err := parseUserArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	user, err := app.findUser(ctx, identifier)
+	if err != nil {
+		return err
+	}
+	if user.IsSystem {
+		return errors.New("システムアカウントは削除できません")
+	}
+
+	if !*yes {
+		fmt.Printf("@%s（%s）の投稿・いいね・フォロー・通知・メディアとアカウントを削除します。元に戻せません\n", user.Username, user.ID)
+		return errors.New("削除するには -yes を指定してください")
+	}
+
+	if !user.IsDeleting() {
+		err = app.audited(ctx, models.AuditUserPurge, user.ID, nil, func(ctx context.Context) error {
+			if err := app.userRepo.MarkForDeletion(ctx, user.ID); err != nil {
+				return err
+			}
+			return app.deletionRepo.Create(ctx, models.NewAccountDeletion(user.ID))
+		})
+		if err != nil {
+			return fmt.Errorf("削除の登録に失敗しました: %w", err)
+		}
+		fmt.Printf("@%s の削除を登録しました\n", user.Username)
+	} else {
+		fmt.Printf("@%s は削除手続き中です\n", user.Username)
+	}
+
+	if !*wait {
+		return nil
+	}
+	return waitForDeletion(ctx, app, user)
+}
+
+// waitForDeletion 削除手続きが完了するか失敗するまで進捗を出力する
+func waitForDeletion(ctx context.Context, app *adminApp, user *models.User) error {
+	ticker := time.NewTicker(purgeWaitInterval)
+	defer ticker.Stop()
+
+	for {
+		deletion, err := app.deletionRepo.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("削除手続きの取得に失敗しました: %w", err)
+		}
+
+		switch deletion.Status {
+		case models.AccountDeletionCompleted:
+			fmt.Printf("@%s を削除しました\n", user.Username)
+			return nil
+		case models.AccountDeletionFailed:
+			message := ""
+			if deletion.LastError != nil {
+				message = *deletion.LastError
+			}
+			return fmt.Errorf("削除に失敗しました（手順: %s）: %s", deletion.Step, message)
+		}
+		fmt.Printf("削除中: %d/%d 手順完了（状態: %s）\n", deletion.StepsCompleted(), len(models.AccountDeletionSteps), deletion.Status)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runRecomputeCounters 投稿・ユーザーの集計値を元のテーブルから再計算する
+func runRecomputeCounters(ctx context.Context, app *adminApp, args []string) error {
+	fs := flag.NewFlagSet("recompute-counters", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	posts, users, err := app.counters.Reconcile(ctx)
+	if err != nil {
+		return fmt.Errorf("集計値の再計算に失敗しました: %w", err)
+	}
+
+	fmt.Printf("集計値を再計算しました（修正した投稿: %d 件、ユーザー: %d 件）\n", posts, users)
+	return nil
+}
+
+// runRotateJWTSecret 新しいJWTの署名鍵を生成する
+// 署名鍵を変更すると、APIサーバーの再起動後は発行済みのアクセストークン・リフレッシュトークンがすべて無効になる
+func runRotateJWTSecret(_ context.Context, _ *adminApp, args []string) error {
+	fs := flag.NewFlagSet("rotate-jwt-secret", flag.ContinueOnError)
+	write := fs.Bool("write", false, "環境変数ファイルのJWT_SECRETを書き換える")
+	envFile := fs.String("env", ".env", "書き換える環境変数ファイルのパス")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	secret, err := generateSecret(jwtSecretBytes)
+	if err != nil {
+		return fmt.Errorf("署名鍵の生成に失敗しました: %w", err)
+	}
+
+	if !*write {
+		fmt.Printf("JWT_SECRET=%s\n", secret)
+		fmt.Fprintln(os.Stderr, "APIサーバーの環境変数に設定して再起動してください。発行済みのトークンはすべて無効になり、利用者は再ログインが必要です")
+		return nil
+	}
+
+	if err := replaceEnvValue(*envFile, "JWT_SECRET", secret); err != nil {
+		return fmt.Errorf("環境変数ファイルの書き換えに失敗しました: %w", err)
+	}
+	fmt.Printf("%s のJWT_SECRETを書き換えました。APIサーバーを再起動すると発行済みのトークンはすべて無効になり、利用者は再ログインが必要です\n", *envFile)
+	return nil
+}
+
+// parseUserArgs オプションを解析し、対象のユーザー名またはIDを1つ返す
+func parseUserArgs(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() != 1 {
+		return "", errors.New("対象のユーザー名またはIDを1つ指定してください")
+	}
+	return fs.Arg(0), nil
+}
+
+// generateSecret 暗号論的に安全な乱数からURLセーフな文字列を生成する
+func generateSecret(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// replaceEnvValue 環境変数ファイルのkeyの値を書き換える（keyがない場合は末尾に追加する）
+func replaceEnvValue(path, key, value string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	replaced := false
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), key+"=") {
+			line = key + "=" + value
+			replaced = true
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if !replaced {
+		lines = append(lines, key+"="+value)
+	}
+
+	// 書き込みの途中で失敗しても元のファイルが壊れないよう、一時ファイルに書いてから置き換える
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
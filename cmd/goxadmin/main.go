@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/config"
+	"github.com/TakuyaAizawa/gox/internal/repository/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// command 管理コマンド1つ分の定義
+type command struct {
+	usage       string
+	description string
+	// データベースに接続せずに実行できるか
+	offline bool
+	run     func(ctx context.Context, app *adminApp, args []string) error
+}
+
+// コマンド名と定義の対応（usageの表示順）
+var commandNames = []string{"promote", "verify", "verify-age", "suspend", "purge", "recompute-counters", "rotate-jwt-secret"}
+
+var commands = map[string]command{
+	"promote": {
+		usage:       "promote [-role admin|moderator|user] <ユーザー名またはID>",
+		description: "ユーザーの権限を変更する（既定は管理者に昇格する）",
+		run:         runPromote,
+	},
+	"verify": {
+		usage:       "verify [-revoke] <ユーザー名またはID>",
+		description: "認証バッジを付与する（-revokeで解除する）",
+		run:         runVerify,
+	},
+	"verify-age": {
+		usage:       "verify-age [-revoke] [-birth-date YYYY-MM-DD -country JP] <ユーザー名またはID>",
+		description: "確認した生年月日と居住国を登録して年齢確認済みにする（-revokeで取り消す）",
+		run:         runVerifyAge,
+	},
+	"suspend": {
+		usage:       "suspend [-undo] <ユーザー名またはID>",
+		description: "アカウントを停止する（-undoで停止を解除する）",
+		run:         runSuspend,
+	},
+	"purge": {
+		usage:       "purge -yes [-wait] <ユーザー名またはID>",
+		description: "アカウントとそのデータの削除を登録する（削除はAPIサーバーのアカウント削除ワーカーが行う）",
+		run:         runPurge,
+	},
+	"recompute-counters": {
+		usage:       "recompute-counters",
+		description: "投稿・ユーザーの集計値（いいね数・フォロワー数など）を再計算する",
+		run:         runRecomputeCounters,
+	},
+	"rotate-jwt-secret": {
+		usage:       "rotate-jwt-secret [-write] [-env .env]",
+		description: "新しいJWTの署名鍵を生成する（-writeで環境変数ファイルに書き込む。発行済みのトークンはすべて無効になる）",
+		offline:     true,
+		run:         runRotateJWTSecret,
+	},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(flag.CommandLine.Output(), "不明なコマンドです: %s\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	app := &adminApp{}
+	if !cmd.offline {
+		// 設定のロード（APIサーバーと同じ環境変数と.envファイルを使用する）
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatalf("設定の読み込みに失敗しました: %v", err)
+		}
+
+		db, err := connect(ctx, cfg)
+		if err != nil {
+			log.Fatalf("データベース接続に失敗しました: %v", err)
+		}
+		defer db.Close()
+
+		app = newAdminApp(db)
+	}
+
+	if err := cmd.run(ctx, app, flag.Args()[1:]); err != nil {
+		log.Printf("%s: %v", name, err)
+		os.Exit(1)
+	}
+}
+
+// usage コマンドの一覧を出力する
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "使い方: %s <コマンド> [オプション] [引数]\n\nコマンド:\n", os.Args[0])
+	for _, name := range commandNames {
+		cmd := commands[name]
+		fmt.Fprintf(out, "  %s\n      %s\n", cmd.usage, cmd.description)
+	}
+}
+
+// connect APIサーバーと同じ設定でデータベースに接続する
+// 集計値の再計算などの長い処理を打ち切らないよう、statement_timeoutとクエリごとの期限は設定しない
+func connect(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.SSLMode)
+
+	dbConfig, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("データベース設定の解析に失敗しました: %w", err)
+	}
+	dbConfig.MaxConns = 2
+
+	// 接続プーラー（pgbouncerのトランザクションプーリング）との互換モード
+	switch cfg.DB.PoolMode {
+	case "transaction":
+		postgres.EnableTransactionPooling(dbConfig)
+	case "auto":
+		if detected, _, err := postgres.DetectTransactionPooler(ctx, dbConfig.ConnConfig); err == nil && detected {
+			postgres.EnableTransactionPooling(dbConfig)
+		}
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db, err := pgxpool.NewWithConfig(connectCtx, dbConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(connectCtx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/config"
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/importer"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Twitter/Xのアーカイブ（ZIP）のツイートを、投稿日時を保持したままユーザーの投稿として取り込む
+// 同じアーカイブを再度取り込んだ場合、取り込み済みのツイートは除く
+// 取り込んだ投稿はタイムラインへの配信・通知・トピックの分類を行わない（検索エンジンを使用している場合は索引を再構築する）
+func main() {
+	var (
+		archivePath    = flag.String("archive", "", "Twitter/Xのアーカイブ（ZIP）のパス")
+		userIdentifier = flag.String("user", "", "取り込み先のユーザー名またはID")
+		includeReplies = flag.Bool("include-replies", false, "他のユーザーへの返信も（返信ではない）投稿として取り込む")
+		batchSize      = flag.Int("batch-size", 1000, "1回のCOPYで作成する投稿の件数")
+		dryRun         = flag.Bool("dry-run", false, "取り込まずに件数のみ表示する")
+	)
+	flag.Parse()
+	if *archivePath == "" || *userIdentifier == "" || *batchSize <= 0 {
+		fmt.Fprintf(flag.CommandLine.Output(), "使い方: %s -archive <ZIPのパス> -user <ユーザー名またはID> [オプション]\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	// アーカイブの読み込み
+	file, err := os.Open(*archivePath)
+	if err != nil {
+		log.Fatalf("アーカイブを開けません: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		log.Fatalf("アーカイブを開けません: %v", err)
+	}
+
+	archive, err := importer.ReadTwitterArchive(file, info.Size())
+	if err != nil {
+		log.Fatalf("アーカイブの読み込みに失敗しました: %v", err)
+	}
+	log.Printf("アーカイブを読み込みました（@%s、ツイート: %d 件）", archive.Username, len(archive.Tweets))
+
+	// 設定のロード（APIサーバーと同じ環境変数と.envファイルを使用する）
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+
+	ctx := context.Background()
+	db, err := connect(ctx, cfg)
+	if err != nil {
+		log.Fatalf("データベース接続に失敗しました: %v", err)
+	}
+	defer db.Close()
+
+	userRepo := postgres.NewUserRepository(db)
+	importRepo := postgres.NewPostImportRepository(db)
+
+	user, err := findUser(ctx, userRepo, *userIdentifier)
+	if err != nil {
+		log.Fatalf("取り込み先のユーザーを取得できません: %v", err)
+	}
+
+	imported, err := importRepo.GetImportedPostIDs(ctx, user.ID, models.PostImportSourceTwitter)
+	if err != nil {
+		log.Fatalf("取り込み済みのツイートの取得に失敗しました: %v", err)
+	}
+
+	imports, summary := importer.BuildTwitterImports(archive, user.ID, imported, importer.TwitterImportOptions{
+		IncludeReplies: *includeReplies,
+	})
+	log.Printf("取り込むツイート: %d 件（取り込み済み: %d 件、除外したリツイート: %d 件、他のユーザーへの返信: %d 件、本文が長すぎるツイート: %d 件、本文のないツイート: %d 件）",
+		summary.Converted, summary.AlreadyImported, summary.Retweets, summary.Replies, summary.TooLong, summary.Empty)
+
+	if *dryRun {
+		log.Println("dry-runのため取り込みません")
+		return
+	}
+
+	// 返信先の投稿が先に作成されるよう、投稿日時の古い順にまとめて作成する
+	var total int64
+	for start := 0; start < len(imports); start += *batchSize {
+		end := min(start+*batchSize, len(imports))
+		count, err := importRepo.Import(ctx, user.ID, imports[start:end])
+		if err != nil {
+			log.Fatalf("投稿の取り込みに失敗しました（%d 件目以降は取り込まれていません）: %v", total+1, err)
+		}
+		total += count
+		log.Printf("投稿を取り込みました（%d/%d 件）", total, len(imports))
+	}
+
+	log.Printf("@%s に %d 件の投稿を取り込みました", user.Username, total)
+}
+
+// findUser ユーザー名またはIDでユーザーを取得する
+func findUser(ctx context.Context, userRepo interfaces.UserRepository, identifier string) (*models.User, error) {
+	if id, err := uuid.Parse(identifier); err == nil {
+		return userRepo.GetByID(ctx, id)
+	}
+	return userRepo.GetByUsername(ctx, strings.TrimPrefix(identifier, "@"))
+}
+
+// connect APIサーバーと同じ設定でデータベースに接続する
+// 大量の投稿を作成する処理を打ち切らないよう、statement_timeoutとクエリごとの期限は設定しない
+func connect(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.SSLMode)
+
+	dbConfig, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("データベース設定の解析に失敗しました: %w", err)
+	}
+	dbConfig.MaxConns = 2
+
+	// 接続プーラー（pgbouncerのトランザクションプーリング）との互換モード
+	switch cfg.DB.PoolMode {
+	case "transaction":
+		postgres.EnableTransactionPooling(dbConfig)
+	case "auto":
+		if detected, _, err := postgres.DetectTransactionPooler(ctx, dbConfig.ConnConfig); err == nil && detected {
+			postgres.EnableTransactionPooling(dbConfig)
+		}
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	db, err := pgxpool.NewWithConfig(connectCtx, dbConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(connectCtx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
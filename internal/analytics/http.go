@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+)
+
+// 収集サーバーへの1回の送信にかける最大時間
+const httpSinkTimeout = 10 * time.Second
+
+// HTTPSink はイベントをJSONでHTTPの収集サーバー（Plausible・Segmentのようなイベント収集API）へ送信する送信先です
+// リクエストの本文は {"events": [...]} の形式で、トークンが設定されている場合はBearerトークンとして送信します
+type HTTPSink struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// NewHTTPSink は新しいHTTPSinkインスタンスを作成します
+func NewHTTPSink(endpoint, token string) interfaces.AnalyticsSink {
+	return &HTTPSink{
+		endpoint: endpoint,
+		token:    token,
+		client:   &http.Client{Timeout: httpSinkTimeout},
+	}
+}
+
+// Name は送信先の名前を返します
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+// Write はイベントをまとめて収集サーバーへ送信します（2xx以外の応答はエラーとして再送されます）
+func (s *HTTPSink) Write(ctx context.Context, events []*models.AnalyticsEvent) error {
+	body, err := json.Marshal(map[string]any{"events": events})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("収集サーバーがステータス%dを返しました", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close はアイドル状態の接続を閉じます
+func (s *HTTPSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
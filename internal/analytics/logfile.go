@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+)
+
+// LogFileSink はイベントを1行1件のJSON（JSON Lines）としてファイルへ追記する送信先です
+// ログ収集エージェントなどでファイルを取り込む場合に使用します
+type LogFileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewLogFileSink は新しいLogFileSinkインスタンスを作成します（ファイルがない場合は作成します）
+func NewLogFileSink(path string) (interfaces.AnalyticsSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルを開けませんでした: %w", err)
+	}
+
+	return &LogFileSink{file: file}, nil
+}
+
+// Name は送信先の名前を返します
+func (s *LogFileSink) Name() string {
+	return "log"
+}
+
+// Write はイベントをファイルへ追記します
+// 途中で失敗したバッチは再送されるため、同じイベントが複数回書き込まれることがあります（IDで重複を除いてください）
+func (s *LogFileSink) Write(ctx context.Context, events []*models.AnalyticsEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := bufio.NewWriter(s.file)
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Close はファイルを閉じます
+func (s *LogFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,34 @@
+package analytics
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	repointerfaces "github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+)
+
+// PostgresSink はイベントをanalytics_eventsテーブルへ保存する送信先です
+type PostgresSink struct {
+	repo repointerfaces.AnalyticsEventRepository
+}
+
+// NewPostgresSink は新しいPostgresSinkインスタンスを作成します
+func NewPostgresSink(repo repointerfaces.AnalyticsEventRepository) interfaces.AnalyticsSink {
+	return &PostgresSink{repo: repo}
+}
+
+// Name は送信先の名前を返します
+func (s *PostgresSink) Name() string {
+	return "postgres"
+}
+
+// Write はイベントをまとめて保存します（再送されたイベントはIDで重複を除きます）
+func (s *PostgresSink) Write(ctx context.Context, events []*models.AnalyticsEvent) error {
+	return s.repo.InsertBatch(ctx, events)
+}
+
+// Close は何もしません（接続プールはアプリケーション全体で共有しています）
+func (s *PostgresSink) Close() error {
+	return nil
+}
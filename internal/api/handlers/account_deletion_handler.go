@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccountDeletionHandler アカウント削除のハンドラーを管理する構造体
+type AccountDeletionHandler struct {
+	accountDeletion *service.AccountDeletionService
+	log             logger.Logger
+}
+
+// NewAccountDeletionHandler 新しいアカウント削除ハンドラーを作成する
+func NewAccountDeletionHandler(accountDeletion *service.AccountDeletionService, log logger.Logger) *AccountDeletionHandler {
+	return &AccountDeletionHandler{
+		accountDeletion: accountDeletion,
+		log:             log,
+	}
+}
+
+// DeleteAccount アカウント削除ハンドラー
+// アカウントはすぐにすべてのエンドポイントから隠され、投稿・いいね・フォロー・通知・アップロードしたメディアはバックグラウンドで削除される
+// @Summary アカウント削除
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me [delete]
+func (h *AccountDeletionHandler) DeleteAccount(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	deletion, err := h.accountDeletion.Schedule(c, currentUserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "ユーザーが見つかりません")
+			return
+		}
+		if err.Error() == "account deletion already scheduled" {
+			response.Conflict(c, "このアカウントはすでに削除手続き中です", nil)
+			return
+		}
+		h.log.Error("アカウントの削除の受付中にエラーが発生しました", "error", err, "user_id", currentUserID)
+		response.InternalServerError(c, "アカウントの削除の受付中にエラーが発生しました")
+		return
+	}
+
+	response.JSON(c, http.StatusAccepted, response.NewSuccessResponse(accountDeletionResponse(deletion)))
+}
+
+// GetAccountDeletion アカウント削除の進捗取得ハンドラー
+// @Summary アカウント削除の進捗取得
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/deletion [get]
+func (h *AccountDeletionHandler) GetAccountDeletion(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	deletion, err := h.accountDeletion.GetStatus(c, currentUserID)
+	if err != nil {
+		if err.Error() == "account deletion not found" {
+			response.NotFound(c, "アカウントの削除手続きが見つかりません")
+			return
+		}
+		h.log.Error("アカウント削除の進捗の取得中にエラーが発生しました", "error", err, "user_id", currentUserID)
+		response.InternalServerError(c, "アカウント削除の進捗の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, accountDeletionResponse(deletion))
+}
+
+// currentUserID 認証ユーザーのIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *AccountDeletionHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
+
+// accountDeletionResponse 削除手続きの進捗をレスポンス用に変換する
+func accountDeletionResponse(deletion *models.AccountDeletion) gin.H {
+	resp := gin.H{
+		"status":          deletion.Status,
+		"step":            deletion.Step,
+		"steps_completed": deletion.StepsCompleted(),
+		"steps_total":     len(models.AccountDeletionSteps),
+		"media_total":     deletion.MediaTotal,
+		"media_deleted":   deletion.MediaDeleted,
+		"requested_at":    deletion.RequestedAt,
+		"updated_at":      deletion.UpdatedAt,
+	}
+	if deletion.StartedAt != nil {
+		resp["started_at"] = deletion.StartedAt
+	}
+	if deletion.CompletedAt != nil {
+		resp["completed_at"] = deletion.CompletedAt
+	}
+	return resp
+}
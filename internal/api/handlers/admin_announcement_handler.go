@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAnnouncementHandler 管理者向けのお知らせハンドラーを管理する構造体
+type AdminAnnouncementHandler struct {
+	systemAccounts *service.SystemAccountService
+	log            logger.Logger
+}
+
+// NewAdminAnnouncementHandler 新しい管理者向けお知らせハンドラーを作成する
+func NewAdminAnnouncementHandler(systemAccounts *service.SystemAccountService, log logger.Logger) *AdminAnnouncementHandler {
+	return &AdminAnnouncementHandler{
+		systemAccounts: systemAccounts,
+		log:            log,
+	}
+}
+
+// CreateAnnouncementRequest お知らせの投稿リクエストの構造体
+type CreateAnnouncementRequest struct {
+	Content string `json:"content" binding:"required,max=280"`
+}
+
+// CreateAnnouncement お知らせ用のシステムアカウントで投稿し、全ユーザーのホームタイムラインへ配信するハンドラー
+// @Summary お知らせ用のシステムアカウントで投稿し、全ユーザーのホームタイムラインへ配信する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAnnouncementRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/announcements [post]
+func (h *AdminAnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	var req CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	post, err := h.systemAccounts.PostAnnouncement(c.Request.Context(), req.Content)
+	if err != nil {
+		if errors.Is(err, service.ErrNoAnnouncementsAccount) {
+			response.Conflict(c, "お知らせ用のシステムアカウントがありません", nil)
+			return
+		}
+		h.log.Error("お知らせの投稿中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "お知らせの投稿中にエラーが発生しました")
+		return
+	}
+
+	response.Created(c, gin.H{
+		"id":         post.ID,
+		"user_id":    post.UserID,
+		"content":    post.Content,
+		"created_at": post.CreatedAt,
+	})
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminContentFilterHandler 投稿・プロフィールを審査する禁止語ルールを管理するハンドラーを管理する構造体
+// ルールの変更は監査ログに記録し、このサーバーの審査には即時反映する（他のサーバーには読み直しの間隔で反映される）
+type AdminContentFilterHandler struct {
+	filterRepo    interfaces.ContentFilterRepository
+	auditRepo     interfaces.AuditLogRepository
+	txManager     interfaces.TxManager
+	contentFilter *service.ContentFilterService
+	log           logger.Logger
+}
+
+// NewAdminContentFilterHandler 新しい禁止語ルールの管理ハンドラーを作成する
+func NewAdminContentFilterHandler(
+	filterRepo interfaces.ContentFilterRepository,
+	auditRepo interfaces.AuditLogRepository,
+	txManager interfaces.TxManager,
+	contentFilter *service.ContentFilterService,
+	log logger.Logger,
+) *AdminContentFilterHandler {
+	return &AdminContentFilterHandler{
+		filterRepo:    filterRepo,
+		auditRepo:     auditRepo,
+		txManager:     txManager,
+		contentFilter: contentFilter,
+		log:           log,
+	}
+}
+
+// ContentFilterRuleRequest 禁止語ルールの作成・更新リクエストの構造体
+type ContentFilterRuleRequest struct {
+	Pattern string `json:"pattern" binding:"required"`
+	Action  string `json:"action" binding:"required,oneof=reject flag label"`
+	// labelの場合に付けるレーティング
+	Rating string `json:"rating" binding:"omitempty,oneof=sensitive adult"`
+}
+
+// TestContentFilterRequest 禁止語ルールの確認リクエストの構造体
+type TestContentFilterRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// ListRules 禁止語ルールの一覧を取得するハンドラー
+// @Summary 禁止語ルールの一覧を取得する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/content-filter/rules [get]
+func (h *AdminContentFilterHandler) ListRules(c *gin.Context) {
+	rules, err := h.filterRepo.List(c)
+	if err != nil {
+		h.log.Error("禁止語ルールの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "禁止語ルールの取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{"rules": rules})
+}
+
+// CreateRule 禁止語ルールを追加するハンドラー
+// @Summary 禁止語ルールを追加する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ContentFilterRuleRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/content-filter/rules [post]
+func (h *AdminContentFilterHandler) CreateRule(c *gin.Context) {
+	actorID, ok := h.actorID(c)
+	if !ok {
+		return
+	}
+
+	var req ContentFilterRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	action, rating, ok := validateContentFilterRule(c, req)
+	if !ok {
+		return
+	}
+
+	rule := models.NewContentFilterRule(req.Pattern, action, rating, actorID)
+	err := h.audited(c, actorID, models.AuditContentFilterCreate, rule, func(ctx context.Context) error {
+		return h.filterRepo.Create(ctx, rule)
+	})
+	if err != nil {
+		if err.Error() == "content filter rule already exists" {
+			response.Conflict(c, "同じパターンのルールが既に登録されています", nil)
+			return
+		}
+		h.log.Error("禁止語ルールの追加中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "禁止語ルールの追加中にエラーが発生しました")
+		return
+	}
+
+	h.contentFilter.Invalidate()
+	response.Created(c, rule)
+}
+
+// UpdateRule 禁止語ルールのパターン・アクション・レーティングを変更するハンドラー
+// @Summary 禁止語ルールのパターン・アクション・レーティングを変更する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body ContentFilterRuleRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/content-filter/rules/{id} [put]
+func (h *AdminContentFilterHandler) UpdateRule(c *gin.Context) {
+	actorID, ok := h.actorID(c)
+	if !ok {
+		return
+	}
+
+	rule, ok := h.targetRule(c)
+	if !ok {
+		return
+	}
+
+	var req ContentFilterRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	action, rating, ok := validateContentFilterRule(c, req)
+	if !ok {
+		return
+	}
+
+	rule.Pattern = models.NormalizeContentFilterPattern(req.Pattern)
+	rule.Action = action
+	rule.Rating = rating
+	err := h.audited(c, actorID, models.AuditContentFilterUpdate, rule, func(ctx context.Context) error {
+		return h.filterRepo.Update(ctx, rule)
+	})
+	if err != nil {
+		switch err.Error() {
+		case "content filter rule already exists":
+			response.Conflict(c, "同じパターンのルールが既に登録されています", nil)
+		case "content filter rule not found":
+			response.NotFound(c, "禁止語ルールが見つかりません")
+		default:
+			h.log.Error("禁止語ルールの変更中にエラーが発生しました", "error", err, "rule_id", rule.ID)
+			response.InternalServerError(c, "禁止語ルールの変更中にエラーが発生しました")
+		}
+		return
+	}
+
+	h.contentFilter.Invalidate()
+	response.Success(c, rule)
+}
+
+// DeleteRule 禁止語ルールを削除するハンドラー
+// @Summary 禁止語ルールを削除する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/content-filter/rules/{id} [delete]
+func (h *AdminContentFilterHandler) DeleteRule(c *gin.Context) {
+	actorID, ok := h.actorID(c)
+	if !ok {
+		return
+	}
+
+	rule, ok := h.targetRule(c)
+	if !ok {
+		return
+	}
+
+	err := h.audited(c, actorID, models.AuditContentFilterDelete, rule, func(ctx context.Context) error {
+		return h.filterRepo.Delete(ctx, rule.ID)
+	})
+	if err != nil {
+		if err.Error() == "content filter rule not found" {
+			response.NotFound(c, "禁止語ルールが見つかりません")
+			return
+		}
+		h.log.Error("禁止語ルールの削除中にエラーが発生しました", "error", err, "rule_id", rule.ID)
+		response.InternalServerError(c, "禁止語ルールの削除中にエラーが発生しました")
+		return
+	}
+
+	h.contentFilter.Invalidate()
+	response.Success(c, gin.H{"id": rule.ID, "deleted": true})
+}
+
+// TestRules テキストを現在の禁止語ルールで審査した結果を返すハンドラー（投稿は作成しない）
+// @Summary テキストを現在の禁止語ルールで審査した結果を返す（投稿は作成しない）
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TestContentFilterRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/content-filter/test [post]
+func (h *AdminContentFilterHandler) TestRules(c *gin.Context) {
+	var req TestContentFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	result, err := h.contentFilter.Screen(c, req.Text)
+	if err != nil {
+		h.log.Error("禁止語ルールによる審査中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "禁止語ルールによる審査中にエラーが発生しました")
+		return
+	}
+
+	matches := result.Matches
+	if matches == nil {
+		matches = []*models.ContentFilterRule{}
+	}
+	response.Success(c, gin.H{
+		"rejected": result.Rejected,
+		"flagged":  result.Flagged,
+		"rating":   result.Rating,
+		"matches":  matches,
+	})
+}
+
+// actorID 操作する管理者のIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *AdminContentFilterHandler) actorID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	actorID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return actorID, true
+}
+
+// targetRule パスで指定された禁止語ルールを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *AdminContentFilterHandler) targetRule(c *gin.Context) (*models.ContentFilterRule, bool) {
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効なルールIDです", nil)
+		return nil, false
+	}
+
+	rule, err := h.filterRepo.GetByID(c, ruleID)
+	if err != nil {
+		if err.Error() == "content filter rule not found" {
+			response.NotFound(c, "禁止語ルールが見つかりません")
+			return nil, false
+		}
+		h.log.Error("禁止語ルールの取得中にエラーが発生しました", "error", err, "rule_id", ruleID)
+		response.InternalServerError(c, "禁止語ルールの取得中にエラーが発生しました")
+		return nil, false
+	}
+
+	return rule, true
+}
+
+// audited 操作を実行し、同じトランザクションで監査ログに記録する
+func (h *AdminContentFilterHandler) audited(
+	c *gin.Context,
+	actorID uuid.UUID,
+	action models.AuditAction,
+	rule *models.ContentFilterRule,
+	fn func(ctx context.Context) error,
+) error {
+	details := map[string]string{
+		"pattern": rule.Pattern,
+		"action":  string(rule.Action),
+		"rating":  string(rule.Rating),
+	}
+	return h.txManager.WithinTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return h.auditRepo.Create(ctx, models.NewAuditLog(actorID, action, models.AuditTargetContentFilterRule, rule.ID, details).WithClient(c.ClientIP(), c.Request.UserAgent()))
+	})
+}
+
+// validateContentFilterRule 禁止語ルールのリクエストを検証する
+// labelの場合はレーティングが必要で、それ以外の場合はレーティングを無視する
+func validateContentFilterRule(c *gin.Context, req ContentFilterRuleRequest) (models.ContentFilterAction, models.ContentRating, bool) {
+	pattern := models.NormalizeContentFilterPattern(req.Pattern)
+	if pattern == "" || utf8.RuneCountInString(pattern) > models.MaxContentFilterPatternLength {
+		response.BadRequest(c, "パターンは1文字以上100文字以内で指定してください", nil)
+		return "", "", false
+	}
+
+	action := models.ContentFilterAction(req.Action)
+	if action != models.ContentFilterActionLabel {
+		return action, models.ContentRatingGeneral, true
+	}
+	if req.Rating == "" {
+		response.BadRequest(c, "labelのルールにはレーティング（sensitiveまたはadult）を指定してください", nil)
+		return "", "", false
+	}
+	return action, models.ContentRating(req.Rating), true
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 1回のパージで削除するメディアの既定の件数と上限
+const (
+	defaultMediaPurgeLimit = 100
+	maxMediaPurgeLimit     = 1000
+)
+
+// AdminMediaHandler 管理者向けのメディア管理ハンドラーを管理する構造体
+// 削除の操作は監査ログに記録する（操作と記録は同じトランザクションで行う）
+type AdminMediaHandler struct {
+	media       *service.MediaService
+	auditRepo   interfaces.AuditLogRepository
+	txManager   interfaces.TxManager
+	gracePeriod time.Duration
+	log         logger.Logger
+}
+
+// NewAdminMediaHandler 新しい管理者向けメディア管理ハンドラーを作成する
+// gracePeriodはアップロード後に参照されるまで待つ期間で、定期的な削除と同じ値を使う
+func NewAdminMediaHandler(
+	media *service.MediaService,
+	auditRepo interfaces.AuditLogRepository,
+	txManager interfaces.TxManager,
+	gracePeriod time.Duration,
+	log logger.Logger,
+) *AdminMediaHandler {
+	if gracePeriod <= 0 {
+		gracePeriod = 24 * time.Hour
+	}
+
+	return &AdminMediaHandler{
+		media:       media,
+		auditRepo:   auditRepo,
+		txManager:   txManager,
+		gracePeriod: gracePeriod,
+		log:         log,
+	}
+}
+
+// PurgeMedia どこからも参照されていないメディアを削除するハンドラー（dry_run=trueの場合は削除せずに対象を返す）
+// 定期的な削除を待たずに実行するためのもので、猶予を過ぎたメディアを最大limit件削除する
+// @Summary 参照されていないメディアを削除する（dry_run=trueの場合は削除せずに対象を返す）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dry_run query boolean false "trueの場合は変更せずに対象を返す"
+// @Param limit query integer false "削除する最大件数" default(100)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/media/purge [post]
+func (h *AdminMediaHandler) PurgeMedia(c *gin.Context) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+	actorID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	limit := defaultMediaPurgeLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxMediaPurgeLimit {
+			response.BadRequest(c, "limitは1から1000の範囲で指定してください", nil)
+			return
+		}
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	before := time.Now().Add(-h.gracePeriod)
+
+	result, err := service.RunBulkAction(c.Request.Context(), h.txManager, dryRun, func(ctx context.Context, result *service.BulkActionResult) error {
+		if err := h.media.PurgeOrphans(ctx, h.txManager, before, limit, result); err != nil {
+			return err
+		}
+
+		details := map[string]string{
+			"dry_run": strconv.FormatBool(dryRun),
+			"rows":    strconv.FormatInt(result.Rows, 10),
+			"files":   strconv.Itoa(result.Files),
+		}
+		entry := models.NewAuditLog(actorID, models.AuditMediaPurge, "", uuid.Nil, details).WithClient(c.ClientIP(), c.Request.UserAgent())
+		entry.TargetID = nil
+		return h.auditRepo.Create(ctx, entry)
+	})
+	if err != nil {
+		h.log.Error("メディアの削除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "メディアの削除中にエラーが発生しました")
+		return
+	}
+
+	if !dryRun {
+		h.log.Info("管理者が参照されていないメディアを削除しました", "count", result.Rows, "actor_id", actorID)
+	}
+	response.Success(c, bulkActionResponse(result))
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOrphanMediaRepository 参照されていないメディアの一覧と削除だけを実装するMediaRepository
+type fakeOrphanMediaRepository struct {
+	interfaces.MediaRepository
+	objects []*models.MediaObject
+	deleted []uuid.UUID
+}
+
+func (r *fakeOrphanMediaRepository) ListOrphaned(ctx context.Context, before time.Time, limit int) ([]*models.MediaObject, error) {
+	return r.objects, nil
+}
+
+func (r *fakeOrphanMediaRepository) DeleteOrphan(ctx context.Context, id uuid.UUID, before time.Time, deleteObject func(ctx context.Context) error) ([]*models.MediaUpload, error) {
+	if err := deleteObject(ctx); err != nil {
+		return nil, err
+	}
+	r.deleted = append(r.deleted, id)
+	return nil, nil
+}
+
+// fakeStorage 削除したパスを記録するStorageProvider（/media/以下のURLをこのストレージのURLとする）
+type fakeStorage struct {
+	coreinterfaces.StorageProvider
+	deleted []string
+}
+
+func (s *fakeStorage) PathFromURL(fileURL string) (string, bool) {
+	return strings.CutPrefix(fileURL, "http://localhost:8080/media/")
+}
+
+func (s *fakeStorage) DeleteFile(ctx context.Context, path string) error {
+	s.deleted = append(s.deleted, path)
+	return nil
+}
+
+func TestPurgeMedia(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.NewLogger("error", "json")
+	require.NoError(t, err)
+
+	video := models.NewMediaObject("a", "http://localhost:8080/media/a.mp4", 4096)
+	video.ThumbnailURL = "http://localhost:8080/media/a_thumb.jpg"
+	image := models.NewMediaObject("b", "http://localhost:8080/media/b.png", 1024)
+	// このストレージのURLでないファイルは削除しない
+	external := models.NewMediaObject("c", "https://cdn.example.com/c.png", 512)
+
+	tests := []struct {
+		name         string
+		query        string
+		dryRuns      int
+		deletedFiles []string
+	}{
+		// ドライランでは対象を数えるだけでファイルを削除しない
+		{"DryRun", "?dry_run=true", 1, nil},
+		// 実行した場合はコミット後にファイルを削除する
+		{"Run", "", 0, []string{"a.mp4", "a_thumb.jpg", "b.png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mediaRepo := &fakeOrphanMediaRepository{objects: []*models.MediaObject{video, image, external}}
+			storage := &fakeStorage{}
+			auditRepo := &fakeAuditLogRepository{}
+			txManager := &fakeTxManager{}
+			media := service.NewMediaService(mediaRepo, nil, storage, "", 0, log)
+			handler := NewAdminMediaHandler(media, auditRepo, txManager, time.Hour, log)
+
+			router := gin.New()
+			router.POST("/admin/media/purge", func(c *gin.Context) {
+				c.Set("userID", uuid.New().String())
+			}, handler.PurgeMedia)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/media/purge"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.dryRuns, txManager.dryRuns)
+			assert.Equal(t, tt.deletedFiles, storage.deleted)
+
+			// ドライランと実行で同じ件数と対象を返す
+			var body struct {
+				Data gin.H `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, gin.H{
+				"dry_run": tt.dryRuns == 1,
+				"rows":    float64(3),
+				"files":   float64(3),
+				"sample":  []interface{}{video.URL, image.URL, external.URL},
+			}, body.Data)
+
+			require.Len(t, auditRepo.entries, 1)
+			assert.Equal(t, models.AuditMediaPurge, auditRepo.entries[0].Action)
+			assert.Nil(t, auditRepo.entries[0].TargetID)
+		})
+	}
+}
+
+func TestPurgeMediaInvalidLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.NewLogger("error", "json")
+	require.NoError(t, err)
+
+	handler := NewAdminMediaHandler(nil, nil, &fakeTxManager{}, time.Hour, log)
+	router := gin.New()
+	router.POST("/admin/media/purge", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+	}, handler.PurgeMedia)
+
+	for _, limit := range []string{"0", "1001", "abc"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/admin/media/purge?limit="+limit, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, limit)
+	}
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminPostHandler モデレーターによる投稿の対応（非表示・削除・注意書き・返信のロック）のハンドラーを管理する構造体
+// 状態を変更する操作はすべて監査ログに記録する（操作と記録は同じトランザクションで行う）
+type AdminPostHandler struct {
+	postRepo            interfaces.PostRepository
+	auditRepo           interfaces.AuditLogRepository
+	txManager           interfaces.TxManager
+	notificationService *service.NotificationService
+	systemAccounts      *service.SystemAccountService
+	log                 logger.Logger
+}
+
+// NewAdminPostHandler 新しい投稿のモデレーションハンドラーを作成する
+func NewAdminPostHandler(
+	postRepo interfaces.PostRepository,
+	auditRepo interfaces.AuditLogRepository,
+	txManager interfaces.TxManager,
+	notificationService *service.NotificationService,
+	systemAccounts *service.SystemAccountService,
+	log logger.Logger,
+) *AdminPostHandler {
+	return &AdminPostHandler{
+		postRepo:            postRepo,
+		auditRepo:           auditRepo,
+		txManager:           txManager,
+		notificationService: notificationService,
+		systemAccounts:      systemAccounts,
+		log:                 log,
+	}
+}
+
+// ModeratePostRequest 投稿の非表示・削除・復元のリクエストの構造体
+type ModeratePostRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// UpdateContentWarningRequest 投稿の注意書きの変更リクエストの構造体（空文字で解除）
+type UpdateContentWarningRequest struct {
+	ContentWarning string `json:"content_warning"`
+}
+
+// UpdateRepliesLockedRequest 投稿への返信のロックの変更リクエストの構造体
+type UpdateRepliesLockedRequest struct {
+	Locked *bool `json:"locked" binding:"required"`
+}
+
+// ListModeratedPosts 非表示・削除された投稿を対応した日時の新しい順に一覧するハンドラー
+// statusでhiddenまたはremovedに絞り込む
+// @Summary 非表示・削除された投稿を対応した日時の新しい順に一覧する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "状態で絞り込む"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/posts [get]
+func (h *AdminPostHandler) ListModeratedPosts(c *gin.Context) {
+	status := models.ModerationStatus(c.Query("status"))
+	switch status {
+	case "", models.ModerationStatusHidden, models.ModerationStatusRemoved:
+	default:
+		response.BadRequest(c, "状態はhiddenまたはremovedを指定してください", nil)
+		return
+	}
+
+	page, perPage, offset := listPagination(c)
+
+	posts, err := h.postRepo.ListModerated(c, status, offset, perPage)
+	if err != nil {
+		h.log.Error("非表示の投稿一覧の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "非表示の投稿一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	total, err := h.postRepo.CountModerated(c, status)
+	if err != nil {
+		h.log.Error("非表示の投稿数の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "非表示の投稿一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	postsResponse := make([]gin.H, 0, len(posts))
+	for _, post := range posts {
+		postsResponse = append(postsResponse, moderatedPostResponse(post))
+	}
+
+	response.Success(c, gin.H{
+		"posts":      postsResponse,
+		"pagination": paginationMeta(total, page, perPage),
+	})
+}
+
+// HidePost 投稿を一時的に非表示にするハンドラー（通報の確認中などに使用し、投稿者には通知しない）
+// @Summary 投稿を一時的に非表示にする（通報の確認中などに使用し、投稿者には通知しない）
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param dry_run query boolean false "trueの場合は変更せずに対象を返す"
+// @Param request body ModeratePostRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/posts/{id}/hide [post]
+func (h *AdminPostHandler) HidePost(c *gin.Context) {
+	h.moderate(c, models.ModerationStatusHidden, models.AuditPostHide)
+}
+
+// RemovePost 規約違反として投稿を削除するハンドラー（dry_run=trueの場合は削除せずに対象を返す）
+// 削除された投稿はすべての一覧・取得から除かれ、投稿者に通知される
+// @Summary 規約違反として投稿を削除する（dry_run=trueの場合は削除せずに対象を返す）
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param dry_run query boolean false "trueの場合は変更せずに対象を返す"
+// @Param request body ModeratePostRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/posts/{id}/remove [post]
+func (h *AdminPostHandler) RemovePost(c *gin.Context) {
+	h.moderate(c, models.ModerationStatusRemoved, models.AuditPostRemove)
+}
+
+// RestorePost 非表示・削除した投稿を再び表示するハンドラー
+// @Summary 非表示・削除した投稿を再び表示する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param dry_run query boolean false "trueの場合は変更せずに対象を返す"
+// @Param request body ModeratePostRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/posts/{id}/restore [post]
+func (h *AdminPostHandler) RestorePost(c *gin.Context) {
+	h.moderate(c, models.ModerationStatusVisible, models.AuditPostRestore)
+}
+
+// UpdateContentWarning 投稿の注意書き（コンテンツ警告）を設定・解除するハンドラー
+// 注意書きのある投稿は、閲覧者が選択するまで本文の代わりに注意書きが表示される
+// @Summary 投稿の注意書き（コンテンツ警告）を設定・解除する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateContentWarningRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/posts/{id}/content-warning [put]
+func (h *AdminPostHandler) UpdateContentWarning(c *gin.Context) {
+	actorID, post, ok := h.targetPost(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateContentWarningRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if utf8.RuneCountInString(req.ContentWarning) > models.MaxContentWarningLength {
+		response.BadRequest(c, "注意書きは100文字以内で指定してください", nil)
+		return
+	}
+
+	details := map[string]string{"content_warning": req.ContentWarning}
+	err := h.audited(c, actorID, models.AuditPostContentWarning, post.ID, details, func(ctx context.Context) error {
+		return h.postRepo.SetContentWarning(ctx, post.ID, req.ContentWarning)
+	})
+	if err != nil {
+		if err.Error() == "post not found" {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("注意書きの変更中にエラーが発生しました", "error", err, "post_id", post.ID)
+		response.InternalServerError(c, "注意書きの変更中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"id":              post.ID,
+		"content_warning": req.ContentWarning,
+	})
+}
+
+// UpdateRepliesLocked 投稿への返信をロック・解除するハンドラー
+// ロックされた投稿には投稿者も含めて新しく返信できない（既存の返信はそのまま表示される）
+// @Summary 投稿への返信をロック・解除する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateRepliesLockedRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/posts/{id}/replies-locked [put]
+func (h *AdminPostHandler) UpdateRepliesLocked(c *gin.Context) {
+	actorID, post, ok := h.targetPost(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateRepliesLockedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	details := map[string]string{"locked": strconv.FormatBool(*req.Locked)}
+	err := h.audited(c, actorID, models.AuditPostLockReplies, post.ID, details, func(ctx context.Context) error {
+		return h.postRepo.SetRepliesLocked(ctx, post.ID, *req.Locked)
+	})
+	if err != nil {
+		if err.Error() == "post not found" {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("返信のロックの変更中にエラーが発生しました", "error", err, "post_id", post.ID)
+		response.InternalServerError(c, "返信のロックの変更中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"id":             post.ID,
+		"replies_locked": *req.Locked,
+	})
+}
+
+// moderate 投稿の表示状態を変更して監査ログに記録する
+// 削除した場合はコミット後に投稿者へ通知する（ドライランと、既に削除されていた場合は通知しない）
+func (h *AdminPostHandler) moderate(c *gin.Context, status models.ModerationStatus, action models.AuditAction) {
+	actorID, post, ok := h.targetPost(c)
+	if !ok {
+		return
+	}
+
+	var req ModeratePostRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, err)
+			return
+		}
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	result, err := service.RunBulkAction(c.Request.Context(), h.txManager, dryRun, func(ctx context.Context, result *service.BulkActionResult) error {
+		if err := h.postRepo.SetModerationStatus(ctx, post.ID, actorID, status, req.Reason); err != nil {
+			return err
+		}
+		result.Rows = 1
+		result.AddSample(post.ID.String())
+
+		details := map[string]string{
+			"dry_run":  strconv.FormatBool(dryRun),
+			"reason":   req.Reason,
+			"previous": string(post.ModerationStatus),
+		}
+		return h.auditRepo.Create(ctx, models.NewAuditLog(actorID, action, models.AuditTargetPost, post.ID, details).WithClient(c.ClientIP(), c.Request.UserAgent()))
+	})
+	if err != nil {
+		if err.Error() == "post not found" {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("投稿の表示状態の変更中にエラーが発生しました", "error", err, "post_id", post.ID, "status", status)
+		response.InternalServerError(c, "投稿の表示状態の変更中にエラーが発生しました")
+		return
+	}
+
+	if !dryRun {
+		h.log.Info("モデレーターが投稿の表示状態を変更しました", "post_id", post.ID, "status", status, "actor_id", actorID)
+		if status == models.ModerationStatusRemoved && post.ModerationStatus != models.ModerationStatusRemoved {
+			h.notifyRemoved(c, actorID, post, req.Reason)
+		}
+	}
+	response.Success(c, bulkActionResponse(result))
+}
+
+// notifyRemoved 投稿が削除されたことを投稿者に通知する
+// 送信者はお知らせ用のシステムアカウントとし、設定されていない場合は対応したモデレーターとする
+func (h *AdminPostHandler) notifyRemoved(c *gin.Context, actorID uuid.UUID, post *models.Post, reason string) {
+	if h.notificationService == nil {
+		return
+	}
+
+	senderID := h.systemAccounts.AnnouncementsUserID()
+	if senderID == uuid.Nil {
+		senderID = actorID
+	}
+
+	if err := h.notificationService.CreatePostRemovedNotification(c.Request.Context(), senderID, post.UserID, post.ID, reason); err != nil {
+		// 通知のエラーは削除の結果には影響させない
+		h.log.Error("投稿削除の通知の作成中にエラーが発生しました", "error", err, "post_id", post.ID)
+	}
+}
+
+// targetPost 操作するモデレーターのIDとパスで指定された対象の投稿を取得する
+// 非表示・削除された投稿も対象とし、取得できない場合はエラーレスポンスを送信してfalseを返す
+func (h *AdminPostHandler) targetPost(c *gin.Context) (uuid.UUID, *models.Post, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, nil, false
+	}
+
+	actorID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, nil, false
+	}
+
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return uuid.Nil, nil, false
+	}
+
+	post, err := h.postRepo.GetByIDIncludingDeleted(c, postID)
+	if err != nil || post.IsDeleted() {
+		response.NotFound(c, "投稿が見つかりません")
+		return uuid.Nil, nil, false
+	}
+
+	return actorID, post, true
+}
+
+// audited 操作を実行し、同じトランザクションで監査ログに記録する
+func (h *AdminPostHandler) audited(
+	c *gin.Context,
+	actorID uuid.UUID,
+	action models.AuditAction,
+	postID uuid.UUID,
+	details map[string]string,
+	fn func(ctx context.Context) error,
+) error {
+	return h.txManager.WithinTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return h.auditRepo.Create(ctx, models.NewAuditLog(actorID, action, models.AuditTargetPost, postID, details).WithClient(c.ClientIP(), c.Request.UserAgent()))
+	})
+}
+
+// moderatedPostResponse モデレーター向けに対応の内容を含めて投稿をレスポンス用に変換する
+func moderatedPostResponse(post *models.Post) gin.H {
+	return gin.H{
+		"id":                post.ID,
+		"user_id":           post.UserID,
+		"content":           post.Content,
+		"media_urls":        post.MediaURLs,
+		"content_warning":   post.ContentWarning,
+		"expires_at":        post.ExpiresAt,
+		"expiring":          post.IsExpiring(),
+		"replies_locked":    post.RepliesLocked,
+		"moderation_status": post.ModerationStatus,
+		"moderation_reason": post.ModerationReason,
+		"moderated_by":      post.ModeratedBy,
+		"moderated_at":      post.ModeratedAt,
+		"created_at":        post.CreatedAt,
+	}
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// 一度に取得・集計できるコホートの最大日数
+const maxCohortRangeDays = 366
+
+// AdminStatsHandler 管理者向けの統計ハンドラーを管理する構造体
+type AdminStatsHandler struct {
+	postRepo        interfaces.PostRepository
+	userStats       *service.UserStatsService
+	followProjector *service.FollowProjectorService
+	log             logger.Logger
+}
+
+// NewAdminStatsHandler 新しい管理者向け統計ハンドラーを作成する
+func NewAdminStatsHandler(
+	postRepo interfaces.PostRepository,
+	userStats *service.UserStatsService,
+	followProjector *service.FollowProjectorService,
+	log logger.Logger,
+) *AdminStatsHandler {
+	return &AdminStatsHandler{
+		postRepo:        postRepo,
+		userStats:       userStats,
+		followProjector: followProjector,
+		log:             log,
+	}
+}
+
+// RollupCohortsRequest コホート統計の再集計リクエストの構造体
+type RollupCohortsRequest struct {
+	From string `json:"from" binding:"required,datetime=2006-01-02"`
+	To   string `json:"to" binding:"required,datetime=2006-01-02"`
+}
+
+// GetCohorts 登録日ごとのコホート統計とリテンション（D1/D7/D30）を取得するハンドラー
+// @Summary 登録日ごとのコホート統計とリテンション（D1/D7/D30）を取得する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param to query string false "期間の終了日（YYYY-MM-DD）"
+// @Param from query string false "期間の開始日（YYYY-MM-DD）"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/stats/cohorts [get]
+func (h *AdminStatsHandler) GetCohorts(c *gin.Context) {
+	// 期間の取得（デフォルトは昨日までの30日間）
+	from, to, ok := statsDateRange(c, time.Now().UTC().AddDate(0, 0, -1))
+	if !ok {
+		return
+	}
+
+	cohorts, err := h.userStats.GetCohorts(c, from, to)
+	if err != nil {
+		h.log.Error("コホート統計の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "コホート統計の取得中にエラーが発生しました")
+		return
+	}
+
+	cohortResponses := make([]gin.H, 0, len(cohorts))
+	var signups int
+	var d1, d7, d30 retentionTotal
+	for _, cohort := range cohorts {
+		signups += cohort.CohortSize
+		d1.add(cohort.CohortSize, cohort.D1Retained)
+		d7.add(cohort.CohortSize, cohort.D7Retained)
+		d30.add(cohort.CohortSize, cohort.D30Retained)
+
+		cohortResponses = append(cohortResponses, gin.H{
+			"cohort_date":  cohort.CohortDate.Format("2006-01-02"),
+			"cohort_size":  cohort.CohortSize,
+			"d1_retained":  cohort.D1Retained,
+			"d7_retained":  cohort.D7Retained,
+			"d30_retained": cohort.D30Retained,
+			"d1_rate":      retentionRate(cohort.CohortSize, cohort.D1Retained),
+			"d7_rate":      retentionRate(cohort.CohortSize, cohort.D7Retained),
+			"d30_rate":     retentionRate(cohort.CohortSize, cohort.D30Retained),
+			"computed_at":  cohort.ComputedAt,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"from":    from.Format("2006-01-02"),
+		"to":      to.Format("2006-01-02"),
+		"cohorts": cohortResponses,
+		// 期間全体のリテンション（確定しているコホートのみで計算）
+		"summary": gin.H{
+			"signups":  signups,
+			"d1_rate":  d1.rate(),
+			"d7_rate":  d7.rate(),
+			"d30_rate": d30.rate(),
+		},
+	})
+}
+
+// RollupCohorts 指定した期間のコホート統計を再集計するハンドラー
+// 通常は毎日の集計ジョブで更新されるため、過去の期間を集計し直す場合に使用する
+// @Summary 指定した期間のコホート統計を再集計する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RollupCohortsRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/stats/cohorts/rollup [post]
+func (h *AdminStatsHandler) RollupCohorts(c *gin.Context) {
+	var req RollupCohortsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	from, _ := time.Parse("2006-01-02", req.From)
+	to, _ := time.Parse("2006-01-02", req.To)
+	if !validCohortRange(c, from, to) {
+		return
+	}
+
+	count, err := h.userStats.Rollup(c, from, to)
+	if err != nil {
+		h.log.Error("コホート統計の集計中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "コホート統計の集計中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"from":    req.From,
+		"to":      req.To,
+		"cohorts": count,
+	})
+}
+
+// GetFollowChurn サービス全体の日ごとのフォロー・フォロー解除の数を取得するハンドラー
+// @Summary サービス全体の日ごとのフォロー・フォロー解除の数を取得する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param to query string false "期間の終了日（YYYY-MM-DD）"
+// @Param from query string false "期間の開始日（YYYY-MM-DD）"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/stats/follows [get]
+func (h *AdminStatsHandler) GetFollowChurn(c *gin.Context) {
+	// 期間の取得（デフォルトは今日までの30日間）
+	from, to, ok := statsDateRange(c, time.Now().UTC())
+	if !ok {
+		return
+	}
+
+	days, err := h.followProjector.GetChurn(c, nil, from, to)
+	if err != nil {
+		h.log.Error("フォロー数の推移の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロー数の推移の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, followChurnResponse(from, to, days))
+}
+
+// RebuildFollows フォロー関係をフォローイベントから作り直し、フォロワー数・フォロー数を再計算するハンドラー
+// 手動での修正や障害などでfollowsとイベントがずれた場合に使用する
+// @Summary フォロー関係をフォローイベントから作り直し、フォロワー数・フォロー数を再計算する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/follows/rebuild [post]
+func (h *AdminStatsHandler) RebuildFollows(c *gin.Context) {
+	follows, users, err := h.followProjector.Rebuild(c)
+	if err != nil {
+		h.log.Error("フォロー関係の再構築中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロー関係の再構築中にエラーが発生しました")
+		return
+	}
+
+	h.log.Info("フォロー関係をイベントから再構築しました", "follows", follows, "users", users)
+	response.Success(c, gin.H{
+		"follows":       follows,
+		"users_updated": users,
+	})
+}
+
+// GetAccessibilityReport 直近の投稿の添付メディアに代替テキストが設定されている割合と、言語が設定されている投稿の割合を取得するハンドラー
+// @Summary 直近の投稿の添付メディアに代替テキストが設定されている割合と、言語が設定されている投稿の割合を取得する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param days query integer false "集計する日数（1〜366日、デフォルト30日）" default(30)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/stats/accessibility [get]
+func (h *AdminStatsHandler) GetAccessibilityReport(c *gin.Context) {
+	// 集計する日数（1〜366日、デフォルト30日）
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > maxCohortRangeDays {
+		response.BadRequest(c, "日数は1〜366の範囲で指定してください", nil)
+		return
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	stats, err := h.postRepo.GetAccessibilityStats(c, since)
+	if err != nil {
+		h.log.Error("アクセシビリティの集計中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "アクセシビリティの集計中にエラーが発生しました")
+		return
+	}
+
+	// メディアの種類ごとの代替テキストの設定状況（件数の多い順）
+	var media, mediaWithAltText int64
+	typeResponses := make([]gin.H, 0)
+	byType := stats.MediaByType()
+	mediaTypes := make([]models.MediaType, 0, len(byType))
+	for mediaType, count := range byType {
+		media += count.Total
+		mediaWithAltText += count.WithAltText
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Slice(mediaTypes, func(i, j int) bool {
+		a, b := byType[mediaTypes[i]], byType[mediaTypes[j]]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return mediaTypes[i] < mediaTypes[j]
+	})
+	for _, mediaType := range mediaTypes {
+		count := byType[mediaType]
+		typeResponses = append(typeResponses, gin.H{
+			"type":          mediaType,
+			"total":         count.Total,
+			"with_alt_text": count.WithAltText,
+			"alt_text_rate": accessibilityRate(count.Total, count.WithAltText),
+		})
+	}
+
+	response.Success(c, gin.H{
+		"since": since,
+		"days":  days,
+		"posts": gin.H{
+			"total":         stats.Posts,
+			"with_media":    stats.PostsWithMedia,
+			"with_language": stats.PostsWithLanguage,
+			"language_rate": accessibilityRate(stats.Posts, stats.PostsWithLanguage),
+		},
+		"media": gin.H{
+			"total":         media,
+			"with_alt_text": mediaWithAltText,
+			"alt_text_rate": accessibilityRate(media, mediaWithAltText),
+			"by_type":       typeResponses,
+		},
+	})
+}
+
+// statsDateRange クエリパラメーターfrom・toから統計の期間を取得する
+// 指定がない場合はdefaultToまでの30日間とし、無効な場合はエラーレスポンスを送信してfalseを返す
+func statsDateRange(c *gin.Context, defaultTo time.Time) (from, to time.Time, ok bool) {
+	to = defaultTo
+	from = to.AddDate(0, 0, -29)
+	var err error
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			response.BadRequest(c, "終了日はYYYY-MM-DD形式で指定してください", nil)
+			return from, to, false
+		}
+		if c.Query("from") == "" {
+			from = to.AddDate(0, 0, -29)
+		}
+	}
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			response.BadRequest(c, "開始日はYYYY-MM-DD形式で指定してください", nil)
+			return from, to, false
+		}
+	}
+	return from, to, validCohortRange(c, from, to)
+}
+
+// followChurnResponse 日ごとのフォロー・フォロー解除の数と期間全体の合計のレスポンスを組み立てる
+func followChurnResponse(from, to time.Time, days []*models.FollowChurnDay) gin.H {
+	dayResponses := make([]gin.H, 0, len(days))
+	var gained, lost int64
+	for _, day := range days {
+		gained += day.Gained
+		lost += day.Lost
+		dayResponses = append(dayResponses, gin.H{
+			"date":   day.Date.Format("2006-01-02"),
+			"gained": day.Gained,
+			"lost":   day.Lost,
+			"net":    day.Gained - day.Lost,
+		})
+	}
+
+	return gin.H{
+		"from": from.Format("2006-01-02"),
+		"to":   to.Format("2006-01-02"),
+		// フォロー・フォロー解除があった日のみ含める
+		"days": dayResponses,
+		"summary": gin.H{
+			"gained": gained,
+			"lost":   lost,
+			"net":    gained - lost,
+		},
+	}
+}
+
+// validCohortRange コホートの期間が有効かを確認し、無効な場合はエラーレスポンスを送信してfalseを返す
+func validCohortRange(c *gin.Context, from, to time.Time) bool {
+	if from.After(to) {
+		response.BadRequest(c, "開始日は終了日以前の日付を指定してください", nil)
+		return false
+	}
+	if to.Sub(from) >= maxCohortRangeDays*24*time.Hour {
+		response.BadRequest(c, "期間は366日以内で指定してください", nil)
+		return false
+	}
+	return true
+}
+
+// retentionRate コホートのリテンション率を返す（確定していない場合はnil）
+func retentionRate(size int, retained *int) *float64 {
+	if retained == nil || size == 0 {
+		return nil
+	}
+	rate := float64(*retained) / float64(size)
+	return &rate
+}
+
+// accessibilityRate 代替テキストや言語が設定されている割合を返す（対象がない場合はnil）
+func accessibilityRate(total, count int64) *float64 {
+	if total == 0 {
+		return nil
+	}
+	rate := float64(count) / float64(total)
+	return &rate
+}
+
+// retentionTotal 複数のコホートのリテンションを集計する
+type retentionTotal struct {
+	size     int
+	retained int
+}
+
+func (t *retentionTotal) add(size int, retained *int) {
+	if retained == nil {
+		return
+	}
+	t.size += size
+	t.retained += *retained
+}
+
+func (t *retentionTotal) rate() *float64 {
+	retained := t.retained
+	return retentionRate(t.size, &retained)
+}
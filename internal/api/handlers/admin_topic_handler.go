@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminTopicHandler 探索タブのトピックを管理するハンドラーを管理する構造体
+// トピックの変更は監査ログに記録する（ハッシュタグの変更はそれ以降の投稿の分類にのみ反映される）
+type AdminTopicHandler struct {
+	topicRepo interfaces.TopicRepository
+	auditRepo interfaces.AuditLogRepository
+	txManager interfaces.TxManager
+	log       logger.Logger
+}
+
+// NewAdminTopicHandler 新しいトピックの管理ハンドラーを作成する
+func NewAdminTopicHandler(
+	topicRepo interfaces.TopicRepository,
+	auditRepo interfaces.AuditLogRepository,
+	txManager interfaces.TxManager,
+	log logger.Logger,
+) *AdminTopicHandler {
+	return &AdminTopicHandler{
+		topicRepo: topicRepo,
+		auditRepo: auditRepo,
+		txManager: txManager,
+		log:       log,
+	}
+}
+
+// TopicRequest トピックの作成・更新リクエストの構造体
+type TopicRequest struct {
+	// URLやクエリで使う識別子（英小文字・数字・ハイフン、50文字以内）
+	Slug        string `json:"slug" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:"max=500"`
+	// このトピックに分類する投稿のハッシュタグ（#は省略可、20件以内）
+	Hashtags []string `json:"hashtags"`
+}
+
+// CreateTopic トピックを追加するハンドラー
+// @Summary トピックを追加する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TopicRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/topics [post]
+func (h *AdminTopicHandler) CreateTopic(c *gin.Context) {
+	actorID, ok := h.actorID(c)
+	if !ok {
+		return
+	}
+
+	var req TopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	slug, hashtags, ok := validateTopic(c, req)
+	if !ok {
+		return
+	}
+
+	topic := models.NewTopic(slug, strings.TrimSpace(req.Name), strings.TrimSpace(req.Description), hashtags, actorID)
+	err := h.audited(c, actorID, models.AuditTopicCreate, topic, func(ctx context.Context) error {
+		return h.topicRepo.Create(ctx, topic)
+	})
+	if err != nil {
+		if err.Error() == "topic already exists" {
+			response.Conflict(c, "同じスラッグのトピックが既に登録されています", nil)
+			return
+		}
+		h.log.Error("トピックの追加中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "トピックの追加中にエラーが発生しました")
+		return
+	}
+
+	response.Created(c, topic)
+}
+
+// UpdateTopic トピックのスラッグ・名前・説明・ハッシュタグを変更するハンドラー
+// @Summary トピックのスラッグ・名前・説明・ハッシュタグを変更する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body TopicRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/topics/{id} [put]
+func (h *AdminTopicHandler) UpdateTopic(c *gin.Context) {
+	actorID, ok := h.actorID(c)
+	if !ok {
+		return
+	}
+
+	topic, ok := h.targetTopic(c)
+	if !ok {
+		return
+	}
+
+	var req TopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	slug, hashtags, ok := validateTopic(c, req)
+	if !ok {
+		return
+	}
+
+	topic.Slug = slug
+	topic.Name = strings.TrimSpace(req.Name)
+	topic.Description = strings.TrimSpace(req.Description)
+	topic.Hashtags = hashtags
+	err := h.audited(c, actorID, models.AuditTopicUpdate, topic, func(ctx context.Context) error {
+		return h.topicRepo.Update(ctx, topic)
+	})
+	if err != nil {
+		switch err.Error() {
+		case "topic already exists":
+			response.Conflict(c, "同じスラッグのトピックが既に登録されています", nil)
+		case "topic not found":
+			response.NotFound(c, "トピックが見つかりません")
+		default:
+			h.log.Error("トピックの変更中にエラーが発生しました", "error", err, "topic_id", topic.ID)
+			response.InternalServerError(c, "トピックの変更中にエラーが発生しました")
+		}
+		return
+	}
+
+	response.Success(c, topic)
+}
+
+// DeleteTopic トピックを削除するハンドラー（投稿のトピックへの分類も削除される）
+// @Summary トピックを削除する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/topics/{id} [delete]
+func (h *AdminTopicHandler) DeleteTopic(c *gin.Context) {
+	actorID, ok := h.actorID(c)
+	if !ok {
+		return
+	}
+
+	topic, ok := h.targetTopic(c)
+	if !ok {
+		return
+	}
+
+	err := h.audited(c, actorID, models.AuditTopicDelete, topic, func(ctx context.Context) error {
+		return h.topicRepo.Delete(ctx, topic.ID)
+	})
+	if err != nil {
+		if err.Error() == "topic not found" {
+			response.NotFound(c, "トピックが見つかりません")
+			return
+		}
+		h.log.Error("トピックの削除中にエラーが発生しました", "error", err, "topic_id", topic.ID)
+		response.InternalServerError(c, "トピックの削除中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{"id": topic.ID, "deleted": true})
+}
+
+// actorID 操作する管理者のIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *AdminTopicHandler) actorID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	actorID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return actorID, true
+}
+
+// targetTopic パスで指定されたトピックを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *AdminTopicHandler) targetTopic(c *gin.Context) (*models.Topic, bool) {
+	topicID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効なトピックIDです", nil)
+		return nil, false
+	}
+
+	topic, err := h.topicRepo.GetByID(c, topicID)
+	if err != nil {
+		if err.Error() == "topic not found" {
+			response.NotFound(c, "トピックが見つかりません")
+			return nil, false
+		}
+		h.log.Error("トピックの取得中にエラーが発生しました", "error", err, "topic_id", topicID)
+		response.InternalServerError(c, "トピックの取得中にエラーが発生しました")
+		return nil, false
+	}
+
+	return topic, true
+}
+
+// audited 操作を実行し、同じトランザクションで監査ログに記録する
+func (h *AdminTopicHandler) audited(
+	c *gin.Context,
+	actorID uuid.UUID,
+	action models.AuditAction,
+	topic *models.Topic,
+	fn func(ctx context.Context) error,
+) error {
+	details := map[string]string{
+		"slug":     topic.Slug,
+		"name":     topic.Name,
+		"hashtags": strings.Join(topic.Hashtags, ","),
+	}
+	return h.txManager.WithinTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return h.auditRepo.Create(ctx, models.NewAuditLog(actorID, action, models.AuditTargetTopic, topic.ID, details).WithClient(c.ClientIP(), c.Request.UserAgent()))
+	})
+}
+
+// validateTopic トピックのリクエストを検証し、正規化したスラッグとハッシュタグを返す
+func validateTopic(c *gin.Context, req TopicRequest) (string, []string, bool) {
+	slug, ok := models.NormalizeTopicSlug(req.Slug)
+	if !ok {
+		response.BadRequest(c, "スラッグは英小文字・数字・ハイフンの50文字以内で指定してください", nil)
+		return "", nil, false
+	}
+	if !models.IsValidTopicName(req.Name) {
+		response.BadRequest(c, "名前は1文字以上100文字以内で指定してください", nil)
+		return "", nil, false
+	}
+	hashtags, ok := models.NormalizeTopicHashtags(req.Hashtags)
+	if !ok {
+		response.BadRequest(c, "ハッシュタグは有効なものを20件以内で指定してください", nil)
+		return "", nil, false
+	}
+	return slug, hashtags, true
+}
@@ -0,0 +1,716 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminUserHandler 管理者向けのユーザー管理ハンドラーを管理する構造体
+// 状態を変更する操作はすべて監査ログに記録する（操作と記録は同じトランザクションで行う）
+type AdminUserHandler struct {
+	userRepo  interfaces.UserRepository
+	auditRepo interfaces.AuditLogRepository
+	txManager interfaces.TxManager
+	merges    *service.AccountMergeService
+	accounts  *service.AccountStatusService
+	hub       *websocket.Hub
+	log       logger.Logger
+}
+
+// NewAdminUserHandler 新しい管理者向けユーザー管理ハンドラーを作成する
+func NewAdminUserHandler(
+	userRepo interfaces.UserRepository,
+	auditRepo interfaces.AuditLogRepository,
+	txManager interfaces.TxManager,
+	merges *service.AccountMergeService,
+	accounts *service.AccountStatusService,
+	hub *websocket.Hub,
+	log logger.Logger,
+) *AdminUserHandler {
+	return &AdminUserHandler{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		txManager: txManager,
+		merges:    merges,
+		accounts:  accounts,
+		hub:       hub,
+		log:       log,
+	}
+}
+
+// UpdateUserRoleRequest ユーザーの権限の変更リクエストの構造体
+type UpdateUserRoleRequest struct {
+	Role models.UserRole `json:"role" binding:"required"`
+}
+
+// MergeUserRequest アカウントの統合リクエストの構造体
+type MergeUserRequest struct {
+	// 統合先のユーザーID（パスで指定したユーザーのデータをこのユーザーへ移す）
+	TargetUserID string `json:"target_user_id" binding:"required,uuid"`
+}
+
+// UpdateUserVerifiedRequest 認証バッジの変更リクエストの構造体
+type UpdateUserVerifiedRequest struct {
+	Verified *bool `json:"verified" binding:"required"`
+}
+
+// UpdateUserAgeVerificationRequest 年齢確認の登録・取り消しリクエストの構造体
+type UpdateUserAgeVerificationRequest struct {
+	Verified *bool `json:"verified" binding:"required"`
+	// 確認した生年月日（YYYY-MM-DD）と居住国（ISO 3166-1 alpha-2）。verifiedがtrueの場合は必須
+	BirthDate   string `json:"birth_date" binding:"omitempty,datetime=2006-01-02"`
+	CountryCode string `json:"country_code" binding:"omitempty,len=2,alpha"`
+}
+
+// ListUsers すべての状態のユーザーを一覧・検索するハンドラー
+// qでユーザー名・名前・メールアドレスの部分一致、statusで状態（active・deactivated・suspended・deleting・merging・banned）を絞り込む
+// @Summary すべての状態のユーザーを一覧・検索する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "検索キーワード"
+// @Param status query string false "状態で絞り込む"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users [get]
+func (h *AdminUserHandler) ListUsers(c *gin.Context) {
+	query := c.Query("q")
+	status := models.UserStatus(c.Query("status"))
+	switch status {
+	case "", models.UserStatusActive, models.UserStatusDeactivated, models.UserStatusSuspended, models.UserStatusDeleting, models.UserStatusMerging, models.UserStatusBanned:
+	default:
+		response.BadRequest(c, "無効な状態です", nil)
+		return
+	}
+
+	page, perPage, offset := listPagination(c)
+
+	users, err := h.userRepo.ListForAdmin(c, query, status, offset, perPage)
+	if err != nil {
+		h.log.Error("ユーザー一覧の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	total, err := h.userRepo.CountForAdmin(c, query, status)
+	if err != nil {
+		h.log.Error("ユーザー数の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	usersResponse := make([]gin.H, 0, len(users))
+	for _, user := range users {
+		usersResponse = append(usersResponse, adminUserResponse(user))
+	}
+
+	response.Success(c, gin.H{
+		"users":      usersResponse,
+		"pagination": paginationMeta(total, page, perPage),
+	})
+}
+
+// SuspendUser アカウントを停止するハンドラー（dry_run=trueの場合は停止せずに対象を返す）
+// 停止されたアカウントはログインできず、投稿やフォロー関係はすべてのエンドポイントから隠される
+// IdPからの停止（suspended）とは別の状態（banned）にするため、SCIMで有効にされても解除されない
+// 発行済みのトークンは拒否し、接続中のWebSocketは切断する
+// @Summary アカウントを停止する（dry_run=trueの場合は停止せずに対象を返す）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param dry_run query boolean false "trueの場合は変更せずに対象を返す"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users/{id}/suspend [post]
+func (h *AdminUserHandler) SuspendUser(c *gin.Context) {
+	actorID, userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+	if actorID == userID {
+		response.BadRequest(c, "自分自身のアカウントは停止できません", nil)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	result, err := service.RunBulkAction(c.Request.Context(), h.txManager, dryRun, func(ctx context.Context, result *service.BulkActionResult) error {
+		if err := h.userRepo.Ban(ctx, userID); err != nil {
+			return err
+		}
+		result.Rows = 1
+		result.AddSample(userID.String())
+
+		details := map[string]string{"dry_run": strconv.FormatBool(dryRun)}
+		return h.auditRepo.Create(ctx, models.NewAuditLog(actorID, models.AuditUserSuspend, models.AuditTargetUser, userID, details).WithClient(c.ClientIP(), c.Request.UserAgent()))
+	})
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "停止できるユーザーが見つかりません")
+			return
+		}
+		h.log.Error("アカウントの停止中にエラーが発生しました", "error", err, "user_id", userID)
+		response.InternalServerError(c, "アカウントの停止中にエラーが発生しました")
+		return
+	}
+
+	if !dryRun {
+		h.accounts.Forget(userID)
+		h.hub.DisconnectUser(userID)
+		h.log.Info("管理者がアカウントを停止しました", "user_id", userID, "actor_id", actorID)
+	}
+	response.Success(c, bulkActionResponse(result))
+}
+
+// UnsuspendUser 管理者が停止したアカウントを有効に戻すハンドラー
+// IdPから停止されたアカウントはIdPで有効にする必要がある
+// @Summary 管理者が停止したアカウントを有効に戻す
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users/{id}/suspend [delete]
+func (h *AdminUserHandler) UnsuspendUser(c *gin.Context) {
+	actorID, userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	err := h.audited(c, actorID, models.AuditUserUnsuspend, userID, nil, func(ctx context.Context) error {
+		return h.userRepo.Unban(ctx, userID)
+	})
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "停止されたユーザーが見つかりません")
+			return
+		}
+		h.log.Error("アカウントの停止の解除中にエラーが発生しました", "error", err, "user_id", userID)
+		response.InternalServerError(c, "アカウントの停止の解除中にエラーが発生しました")
+		return
+	}
+	h.accounts.Forget(userID)
+
+	response.Success(c, gin.H{
+		"id":     userID,
+		"status": models.UserStatusActive,
+	})
+}
+
+// UpdateUserVerified 認証バッジ（IsVerified）を付与・解除するハンドラー
+// @Summary 認証バッジ（IsVerified）を付与・解除する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateUserVerifiedRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users/{id}/verified [put]
+func (h *AdminUserHandler) UpdateUserVerified(c *gin.Context) {
+	actorID, userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateUserVerifiedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	details := map[string]string{"verified": strconv.FormatBool(*req.Verified)}
+	err := h.audited(c, actorID, models.AuditUserVerify, userID, details, func(ctx context.Context) error {
+		return h.userRepo.SetVerified(ctx, userID, *req.Verified)
+	})
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "ユーザーが見つかりません")
+			return
+		}
+		h.log.Error("認証バッジの更新中にエラーが発生しました", "error", err, "user_id", userID)
+		response.InternalServerError(c, "認証バッジの更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"id":          userID,
+		"is_verified": *req.Verified,
+	})
+}
+
+// UpdateUserAgeVerification 本人確認書類などで確認した生年月日と居住国を登録し、年齢確認済みにするハンドラー
+// verifiedがfalseの場合は年齢確認を取り消す（国コードは変更しない）
+// 監査ログには生年月日を記録しない
+// @Summary 年齢確認を登録・取り消す
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateUserAgeVerificationRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users/{id}/age-verification [put]
+func (h *AdminUserHandler) UpdateUserAgeVerification(c *gin.Context) {
+	actorID, userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateUserAgeVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	var birthDate *time.Time
+	countryCode := strings.ToUpper(req.CountryCode)
+	if *req.Verified {
+		if req.BirthDate == "" || countryCode == "" {
+			response.BadRequest(c, "年齢確認には生年月日と国コードが必要です", nil)
+			return
+		}
+		date, err := time.Parse("2006-01-02", req.BirthDate)
+		if err != nil || date.After(time.Now()) {
+			response.BadRequest(c, "無効な生年月日です", nil)
+			return
+		}
+		birthDate = &date
+	}
+
+	details := map[string]string{"verified": strconv.FormatBool(*req.Verified)}
+	if countryCode != "" {
+		details["country_code"] = countryCode
+	}
+	err := h.audited(c, actorID, models.AuditUserAgeVerify, userID, details, func(ctx context.Context) error {
+		return h.userRepo.SetAgeVerification(ctx, userID, birthDate, countryCode)
+	})
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "ユーザーが見つかりません")
+			return
+		}
+		h.log.Error("年齢確認の更新中にエラーが発生しました", "error", err, "user_id", userID)
+		response.InternalServerError(c, "年齢確認の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"id":              userID,
+		"is_age_verified": *req.Verified,
+	})
+}
+
+// ForcePasswordReset 次のログインでパスワードの再設定を求めるハンドラー
+// 対象のユーザーは新しいパスワードを設定するまでパスワードでログインできない（発行済みのトークンは有効期限まで使用できる）
+// @Summary 次のログインでパスワードの再設定を求める
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users/{id}/password-reset [post]
+func (h *AdminUserHandler) ForcePasswordReset(c *gin.Context) {
+	actorID, userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	err := h.audited(c, actorID, models.AuditUserPasswordReset, userID, nil, func(ctx context.Context) error {
+		return h.userRepo.SetPasswordResetRequired(ctx, userID, true)
+	})
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "ユーザーが見つかりません")
+			return
+		}
+		h.log.Error("パスワードの再設定の要求中にエラーが発生しました", "error", err, "user_id", userID)
+		response.InternalServerError(c, "パスワードの再設定の要求中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"id":                      userID,
+		"password_reset_required": true,
+	})
+}
+
+// UpdateUserRole ユーザーの権限を変更するハンドラー
+// 変更した権限は対象のユーザーが次にトークンを発行した時点から反映される
+// @Summary ユーザーの権限を変更する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateUserRoleRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users/{id}/role [put]
+func (h *AdminUserHandler) UpdateUserRole(c *gin.Context) {
+	actorID, userID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if !req.Role.IsValid() {
+		response.BadRequest(c, "権限はuser・moderator・adminのいずれかを指定してください", nil)
+		return
+	}
+
+	// 自分自身の権限を外して管理できなくなるのを防ぐ
+	if actorID == userID {
+		response.BadRequest(c, "自分自身の権限は変更できません", nil)
+		return
+	}
+
+	details := map[string]string{"role": string(req.Role)}
+	err := h.audited(c, actorID, models.AuditUserRole, userID, details, func(ctx context.Context) error {
+		return h.userRepo.UpdateRole(ctx, userID, req.Role)
+	})
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "ユーザーが見つかりません")
+			return
+		}
+		h.log.Error("ユーザーの権限の変更中にエラーが発生しました", "error", err, "user_id", userID)
+		response.InternalServerError(c, "ユーザーの権限の変更中にエラーが発生しました")
+		return
+	}
+
+	h.log.Info("ユーザーの権限を変更しました", "user_id", userID, "role", req.Role, "actor_id", actorID)
+	response.Success(c, gin.H{
+		"id":   userID,
+		"role": req.Role,
+	})
+}
+
+// ListAuditLogs 監査ログ（管理者の操作・ログインの試行・パスワードの変更・データのエクスポート）を新しい順に取得するハンドラー
+// 操作したユーザー・対象・操作の種類・IPアドレス（CIDR形式も可）・期間で絞り込む
+// @Summary 監査ログを新しい順に取得する（操作したユーザー・対象・操作の種類・IPアドレス・期間で絞り込む）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query string false "操作したユーザーのID"
+// @Param target_id query string false "対象のID"
+// @Param action query string false "操作の種類（例: auth.login_failed）"
+// @Param ip query string false "IPアドレス（CIDR形式で範囲も指定できる）"
+// @Param since query string false "この日時以降の記録のみ取得する（RFC3339形式）"
+// @Param until query string false "この日時より前の記録のみ取得する（RFC3339形式）"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/audit-logs [get]
+func (h *AdminUserHandler) ListAuditLogs(c *gin.Context) {
+	filter, ok := auditLogFilter(c)
+	if !ok {
+		return
+	}
+
+	page, perPage, offset := listPagination(c)
+
+	entries, err := h.auditRepo.List(c, filter, offset, perPage)
+	if err != nil {
+		h.log.Error("監査ログの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "監査ログの取得中にエラーが発生しました")
+		return
+	}
+
+	total, err := h.auditRepo.Count(c, filter)
+	if err != nil {
+		h.log.Error("監査ログの件数の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "監査ログの取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"audit_logs": entries,
+		"pagination": paginationMeta(total, page, perPage),
+	})
+}
+
+// MergeUser 重複して作成されたアカウントを別のアカウントに統合するハンドラー
+// dry_run=trueの場合は統合せずに、移す件数と重複のため削除する件数を返す
+// 統合元のアカウントはすぐにすべてのエンドポイントから隠され、投稿・フォロー・フォロワー・いいね・通知はバックグラウンドで統合先へ移される
+// 最後に統合元のアカウントを削除し、統合元のユーザー名を統合先へ転送する
+// @Summary 重複して作成されたアカウントを別のアカウントに統合する
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param dry_run query boolean false "trueの場合は変更せずに対象を返す"
+// @Param request body MergeUserRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Success 202 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/users/{id}/merge [post]
+func (h *AdminUserHandler) MergeUser(c *gin.Context) {
+	actorID, sourceID, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	var req MergeUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	targetID := uuid.MustParse(req.TargetUserID)
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	if dryRun {
+		report, err := h.merges.Preview(c.Request.Context(), sourceID, targetID)
+		if err != nil {
+			h.respondMergeError(c, err, sourceID)
+			return
+		}
+		response.Success(c, gin.H{
+			"dry_run": true,
+			"report":  report,
+		})
+		return
+	}
+
+	var merge *models.AccountMerge
+	details := map[string]string{"target_user_id": targetID.String()}
+	err := h.audited(c, actorID, models.AuditUserMerge, sourceID, details, func(ctx context.Context) error {
+		var err error
+		merge, err = h.merges.Schedule(ctx, sourceID, targetID, actorID)
+		return err
+	})
+	if err != nil {
+		h.respondMergeError(c, err, sourceID)
+		return
+	}
+
+	h.log.Info("管理者がアカウントの統合を開始しました", "source_user_id", sourceID, "target_user_id", targetID, "actor_id", actorID)
+	response.JSON(c, http.StatusAccepted, response.NewSuccessResponse(merge))
+}
+
+// GetAccountMerge アカウントの統合手続きの進捗を取得するハンドラー
+// @Summary アカウントの統合手続きの進捗を取得する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/merges/{id} [get]
+func (h *AdminUserHandler) GetAccountMerge(c *gin.Context) {
+	mergeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な統合IDです", nil)
+		return
+	}
+
+	merge, err := h.merges.GetStatus(c, mergeID)
+	if err != nil {
+		if err.Error() == "account merge not found" {
+			response.NotFound(c, "アカウントの統合手続きが見つかりません")
+			return
+		}
+		h.log.Error("アカウント統合の進捗の取得中にエラーが発生しました", "error", err, "merge_id", mergeID)
+		response.InternalServerError(c, "アカウント統合の進捗の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, merge)
+}
+
+// respondMergeError アカウントの統合を受け付けられなかった理由をエラーレスポンスとして送信する
+func (h *AdminUserHandler) respondMergeError(c *gin.Context, err error, sourceID uuid.UUID) {
+	switch {
+	case errors.Is(err, service.ErrAccountMergeSelf):
+		response.BadRequest(c, "同じアカウントには統合できません", nil)
+	case errors.Is(err, service.ErrAccountMergeSystem):
+		response.BadRequest(c, "システムアカウントは統合できません", nil)
+	case err.Error() == "user not found":
+		response.NotFound(c, "統合できるユーザーが見つかりません")
+	case err.Error() == "account merge already in progress":
+		response.Conflict(c, "このアカウントはすでに統合中です", nil)
+	default:
+		h.log.Error("アカウントの統合の受付中にエラーが発生しました", "error", err, "source_user_id", sourceID)
+		response.InternalServerError(c, "アカウントの統合の受付中にエラーが発生しました")
+	}
+}
+
+// targetUser 操作する管理者のIDとパスで指定された対象のユーザーIDを取得する
+// 取得できない場合はエラーレスポンスを送信してfalseを返す
+func (h *AdminUserHandler) targetUser(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	actorID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効なユーザーIDです", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return actorID, userID, true
+}
+
+// audited 操作を実行し、同じトランザクションで監査ログに記録する
+func (h *AdminUserHandler) audited(
+	c *gin.Context,
+	actorID uuid.UUID,
+	action models.AuditAction,
+	userID uuid.UUID,
+	details map[string]string,
+	fn func(ctx context.Context) error,
+) error {
+	return h.txManager.WithinTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return h.auditRepo.Create(ctx, models.NewAuditLog(actorID, action, models.AuditTargetUser, userID, details).WithClient(c.ClientIP(), c.Request.UserAgent()))
+	})
+}
+
+// auditLogFilter クエリパラメーターから監査ログの絞り込み条件を取得する
+// 無効な値がある場合はエラーレスポンスを送信してfalseを返す
+func auditLogFilter(c *gin.Context) (models.AuditLogFilter, bool) {
+	filter := models.AuditLogFilter{
+		Action: models.AuditAction(c.Query("action")),
+	}
+
+	if value := c.Query("actor_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			response.BadRequest(c, "無効なユーザーIDです", nil)
+			return filter, false
+		}
+		filter.ActorID = &id
+	}
+
+	if value := c.Query("target_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			response.BadRequest(c, "無効な対象IDです", nil)
+			return filter, false
+		}
+		filter.TargetID = &id
+	}
+
+	if value := c.Query("ip"); value != "" {
+		if _, err := netip.ParsePrefix(value); err != nil {
+			if _, err := netip.ParseAddr(value); err != nil {
+				response.BadRequest(c, "無効なIPアドレスです", nil)
+				return filter, false
+			}
+		}
+		filter.IPAddress = value
+	}
+
+	if value := c.Query("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.BadRequest(c, "開始日時はRFC3339形式で指定してください", nil)
+			return filter, false
+		}
+		filter.Since = &since
+	}
+
+	if value := c.Query("until"); value != "" {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.BadRequest(c, "終了日時はRFC3339形式で指定してください", nil)
+			return filter, false
+		}
+		filter.Until = &until
+	}
+
+	return filter, true
+}
+
+// adminUserResponse 管理者向けにユーザーの状態を含めてレスポンス用に変換する
+func adminUserResponse(user *models.User) gin.H {
+	return gin.H{
+		"id":                      user.ID,
+		"username":                user.Username,
+		"email":                   user.Email,
+		"display_name":            user.Name,
+		"is_verified":             user.IsVerified,
+		"is_system":               user.IsSystem,
+		"status":                  user.Status,
+		"role":                    user.Role,
+		"password_reset_required": user.PasswordResetRequired,
+		"deactivated_at":          user.DeactivatedAt,
+		"created_at":              user.CreatedAt,
+	}
+}
+
+// bulkActionResponse 管理者による一括操作の結果をレスポンス用に変換する
+func bulkActionResponse(result *service.BulkActionResult) gin.H {
+	return gin.H{
+		"dry_run": result.DryRun,
+		"rows":    result.Rows,
+		"files":   result.Files,
+		"sample":  result.Sample,
+	}
+}
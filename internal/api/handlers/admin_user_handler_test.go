@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTxManager 実行したトランザクションの種類を記録するTxManager
+// AfterCommitで登録された処理はコミットした場合のみ実行する
+type fakeTxManager struct {
+	dryRuns     int
+	commits     int
+	afterCommit []func()
+}
+
+func (m *fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.afterCommit = nil
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	m.commits++
+	for _, callback := range m.afterCommit {
+		callback()
+	}
+	return nil
+}
+
+func (m *fakeTxManager) AfterCommit(ctx context.Context, fn func()) {
+	m.afterCommit = append(m.afterCommit, fn)
+}
+
+func (m *fakeTxManager) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.afterCommit = nil
+	m.dryRuns++
+	return fn(ctx)
+}
+
+// fakeBanUserRepository Banだけを実装するUserRepository（他のメソッドは呼び出されない）
+type fakeBanUserRepository struct {
+	interfaces.UserRepository
+	banned map[uuid.UUID]bool
+}
+
+func (r *fakeBanUserRepository) Ban(ctx context.Context, userID uuid.UUID) error {
+	if _, ok := r.banned[userID]; !ok {
+		return errors.New("user not found")
+	}
+	r.banned[userID] = true
+	return nil
+}
+
+// fakeAuditLogRepository 記録した監査ログを保持するAuditLogRepository
+type fakeAuditLogRepository struct {
+	interfaces.AuditLogRepository
+	entries []*models.AuditLog
+}
+
+func (r *fakeAuditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestSuspendUserDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := logger.NewLogger("error", "json")
+	require.NoError(t, err)
+
+	actorID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		status   int
+		expected gin.H
+	}{
+		// 停止される対象と件数を返す
+		{"Preview", userID, http.StatusOK, gin.H{
+			"dry_run": true,
+			"rows":    float64(1),
+			"files":   float64(0),
+			"sample":  []interface{}{userID.String()},
+		}},
+		// 停止できないユーザーは実行した場合と同じエラーになる
+		{"NotFound", uuid.New(), http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &fakeBanUserRepository{banned: map[uuid.UUID]bool{userID: false}}
+			auditRepo := &fakeAuditLogRepository{}
+			txManager := &fakeTxManager{}
+			// ドライランではトークンの拒否もWebSocketの切断もしないため、状態サービスとハブは渡さない
+			handler := NewAdminUserHandler(userRepo, auditRepo, txManager, nil, nil, nil, log)
+
+			router := gin.New()
+			router.POST("/admin/users/:id/suspend", func(c *gin.Context) {
+				c.Set("userID", actorID.String())
+			}, handler.SuspendUser)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.userID.String()+"/suspend?dry_run=true", nil)
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			assert.Equal(t, 1, txManager.dryRuns)
+			assert.Equal(t, 0, txManager.commits)
+			if tt.expected == nil {
+				return
+			}
+
+			var body struct {
+				Data gin.H `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expected, body.Data)
+
+			// 監査ログには同じ処理でドライランとして記録される（実際にはロールバックされる）
+			require.Len(t, auditRepo.entries, 1)
+			assert.Equal(t, models.AuditUserSuspend, auditRepo.entries[0].Action)
+			assert.Equal(t, "true", auditRepo.entries[0].Details["dry_run"])
+		})
+	}
+}
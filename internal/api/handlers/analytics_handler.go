@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"unicode/utf8"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// 1つのイベントに付けられるプロパティの最大数
+	maxAnalyticsProperties = 10
+	// プロパティの名前と値の最大文字数
+	maxAnalyticsPropertyLength = 200
+)
+
+// AnalyticsEventRequest クライアントから送信される分析イベント
+type AnalyticsEventRequest struct {
+	Type       string            `json:"type" binding:"required,oneof=impression click"`
+	PostID     *string           `json:"post_id" binding:"omitempty,uuid"`
+	Properties map[string]string `json:"properties"`
+}
+
+// TrackEventsRequest 分析イベントの送信リクエスト
+type TrackEventsRequest struct {
+	Events []AnalyticsEventRequest `json:"events" binding:"required,min=1,max=100,dive"`
+}
+
+// AnalyticsHandler 分析イベント関連のハンドラーを管理する構造体
+type AnalyticsHandler struct {
+	analytics *service.AnalyticsService
+	log       logger.Logger
+}
+
+// NewAnalyticsHandler 新しい分析イベントハンドラーを作成する
+func NewAnalyticsHandler(analytics *service.AnalyticsService, log logger.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analytics: analytics,
+		log:       log,
+	}
+}
+
+// TrackEvents クライアントで発生したインプレッション・クリックを受け取るハンドラー
+// イベントはイベントバスに発行するのみで、送信先への送信は待たない（送信先が設定されていない場合は破棄する）
+// @Summary クライアントで発生したインプレッション・クリックを受け取る
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TrackEventsRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/analytics/events [post]
+func (h *AnalyticsHandler) TrackEvents(c *gin.Context) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	var req TrackEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	events := make([]*models.AnalyticsEvent, 0, len(req.Events))
+	for _, eventReq := range req.Events {
+		if !validAnalyticsProperties(eventReq.Properties) {
+			response.BadRequest(c, "プロパティは最大10件で、名前と値は200文字以内で指定してください", nil)
+			return
+		}
+
+		var postID *uuid.UUID
+		if eventReq.PostID != nil {
+			id := uuid.MustParse(*eventReq.PostID)
+			postID = &id
+		}
+		userID := currentUserID
+		events = append(events, models.NewAnalyticsEvent(models.AnalyticsEventType(eventReq.Type), &userID, postID, eventReq.Properties))
+	}
+
+	for _, event := range events {
+		h.analytics.Track(event)
+	}
+
+	response.Success(c, gin.H{
+		"accepted": len(events),
+		"enabled":  h.analytics.Enabled(),
+	})
+}
+
+// validAnalyticsProperties プロパティの件数と長さが上限以内かどうかを確認する
+func validAnalyticsProperties(properties map[string]string) bool {
+	if len(properties) > maxAnalyticsProperties {
+		return false
+	}
+	for key, value := range properties {
+		if key == "" || utf8.RuneCountInString(key) > maxAnalyticsPropertyLength || utf8.RuneCountInString(value) > maxAnalyticsPropertyLength {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 一度に取得できる利用状況の最大日数
+const maxUsageRangeDays = 31
+
+// APIUsageHandler API利用状況のハンドラーを管理する構造体
+type APIUsageHandler struct {
+	usage *service.APIUsageService
+	log   logger.Logger
+}
+
+// NewAPIUsageHandler 新しいAPI利用状況ハンドラーを作成する
+func NewAPIUsageHandler(usage *service.APIUsageService, log logger.Logger) *APIUsageHandler {
+	return &APIUsageHandler{
+		usage: usage,
+		log:   log,
+	}
+}
+
+// GetMyUsage 認証ユーザーの1時間ごとのAPI利用状況を取得するハンドラー
+// @Summary 認証ユーザーの1時間ごとのAPI利用状況を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param to query string false "期間の終了日（YYYY-MM-DD）"
+// @Param from query string false "期間の開始日（YYYY-MM-DD）"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/usage [get]
+func (h *APIUsageHandler) GetMyUsage(c *gin.Context) {
+	userIDValue, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+	userID, err := uuid.Parse(userIDValue.(string))
+	if err != nil {
+		response.BadRequest(c, "無効なユーザーIDです", nil)
+		return
+	}
+
+	from, to, ok := usageRange(c)
+	if !ok {
+		return
+	}
+
+	usages, err := h.usage.GetUserUsage(c, userID, from, to)
+	if err != nil {
+		h.log.Error("API利用状況の取得中にエラーが発生しました", "error", err, "user_id", userID)
+		response.InternalServerError(c, "API利用状況の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"from":           from.Format("2006-01-02"),
+		"to":             to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"current_key_id": c.GetString("tokenKey"),
+		"usage":          usageResponses(usages),
+		"total":          usageTotal(usages),
+	})
+}
+
+// GetUsageSummary トークンごとのAPI利用状況を集計して取得する管理者向けハンドラー（appで絞り込み可能）
+// @Summary トークンごとのAPI利用状況を集計して取得する（appで絞り込み可能）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query integer false "取得する件数"
+// @Param app query string false "アプリで絞り込む"
+// @Param to query string false "期間の終了日（YYYY-MM-DD）"
+// @Param from query string false "期間の開始日（YYYY-MM-DD）"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/usage [get]
+func (h *APIUsageHandler) GetUsageSummary(c *gin.Context) {
+	from, to, ok := usageRange(c)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 500 {
+			response.BadRequest(c, "limitは1から500の整数で指定してください", nil)
+			return
+		}
+		limit = parsed
+	}
+	app := c.Query("app")
+
+	summaries, err := h.usage.SummarizeByKey(c, app, from, to, limit)
+	if err != nil {
+		h.log.Error("API利用状況の集計中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "API利用状況の集計中にエラーが発生しました")
+		return
+	}
+	if summaries == nil {
+		summaries = []*models.APIUsageSummary{}
+	}
+
+	response.Success(c, gin.H{
+		"from": from.Format("2006-01-02"),
+		"to":   to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"app":  app,
+		"keys": summaries,
+	})
+}
+
+// GetKeyUsage 指定したキーの1時間ごとのAPI利用状況を取得する管理者向けハンドラー
+// @Summary 指定したキーの1時間ごとのAPI利用状況を取得する
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param key_id path string true "APIキーのID"
+// @Param to query string false "期間の終了日（YYYY-MM-DD）"
+// @Param from query string false "期間の開始日（YYYY-MM-DD）"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/usage/keys/{key_id} [get]
+func (h *APIUsageHandler) GetKeyUsage(c *gin.Context) {
+	keyID := c.Param("key_id")
+	from, to, ok := usageRange(c)
+	if !ok {
+		return
+	}
+
+	usages, err := h.usage.GetKeyUsage(c, keyID, from, to)
+	if err != nil {
+		h.log.Error("API利用状況の取得中にエラーが発生しました", "error", err, "key_id", keyID)
+		response.InternalServerError(c, "API利用状況の取得中にエラーが発生しました")
+		return
+	}
+
+	resp := gin.H{
+		"from":   from.Format("2006-01-02"),
+		"to":     to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"key_id": keyID,
+		"usage":  usageResponses(usages),
+		"total":  usageTotal(usages),
+	}
+	// キーは1人のユーザーのトークンに対応する
+	if len(usages) > 0 {
+		resp["user_id"] = usages[0].UserID
+	}
+
+	response.Success(c, resp)
+}
+
+// usageRange クエリパラメータから利用状況の期間を取得する（toは翌日の0時、デフォルトは今日までの7日間）
+// 無効な場合はエラーレスポンスを送信してfalseを返す
+func usageRange(c *gin.Context) (time.Time, time.Time, bool) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to := today
+	from := to.AddDate(0, 0, -6)
+	var err error
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			response.BadRequest(c, "終了日はYYYY-MM-DD形式で指定してください", nil)
+			return time.Time{}, time.Time{}, false
+		}
+		if c.Query("from") == "" {
+			from = to.AddDate(0, 0, -6)
+		}
+	}
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			response.BadRequest(c, "開始日はYYYY-MM-DD形式で指定してください", nil)
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	if from.After(to) {
+		response.BadRequest(c, "開始日は終了日以前の日付を指定してください", nil)
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Sub(from) >= maxUsageRangeDays*24*time.Hour {
+		response.BadRequest(c, "期間は31日以内で指定してください", nil)
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to.AddDate(0, 0, 1), true
+}
+
+// usageResponses 1時間ごとの利用状況をレスポンス用に変換する
+func usageResponses(usages []*models.APIUsage) []gin.H {
+	responses := make([]gin.H, 0, len(usages))
+	for _, usage := range usages {
+		responses = append(responses, gin.H{
+			"hour":           usage.Hour,
+			"app":            usage.App,
+			"key_id":         usage.KeyID,
+			"endpoint":       usage.Endpoint,
+			"request_count":  usage.RequestCount,
+			"request_bytes":  usage.RequestBytes,
+			"response_bytes": usage.ResponseBytes,
+		})
+	}
+	return responses
+}
+
+// usageTotal 期間全体の利用状況を集計する
+func usageTotal(usages []*models.APIUsage) gin.H {
+	var requests, requestBytes, responseBytes int64
+	for _, usage := range usages {
+		requests += usage.RequestCount
+		requestBytes += usage.RequestBytes
+		responseBytes += usage.ResponseBytes
+	}
+	return gin.H{
+		"request_count":  requests,
+		"request_bytes":  requestBytes,
+		"response_bytes": responseBytes,
+	}
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
 	"github.com/TakuyaAizawa/gox/internal/util/jwt"
+	"github.com/TakuyaAizawa/gox/internal/util/oidc"
 	"github.com/TakuyaAizawa/gox/internal/util/response"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
 	"github.com/gin-gonic/gin"
@@ -14,19 +20,52 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// シングルサインオンのstate・nonce・コード検証子を保持するクッキー
+const (
+	ssoCookieName   = "gox_sso"
+	ssoCookiePath   = "/api/v1/auth/sso"
+	ssoCookieMaxAge = 10 * 60
+)
+
 // AuthHandler 認証関連のハンドラーを管理する構造体
 type AuthHandler struct {
-	userRepo interfaces.UserRepository
-	log      logger.Logger
-	jwtUtil  *jwt.JWTUtil
+	userRepo       interfaces.UserRepository
+	auditRepo      interfaces.AuditLogRepository
+	systemAccounts *service.SystemAccountService
+	sso            *service.SSOService
+	onboarding     *service.OnboardingService
+	analytics      *service.AnalyticsService
+	searchIndex    *service.SearchIndexService
+	// 無効化したアカウントにログインして再開できる期間
+	reactivationGracePeriod time.Duration
+	log                     logger.Logger
+	jwtUtil                 *jwt.JWTUtil
 }
 
 // NewAuthHandler 新しい認証ハンドラーを作成する
-func NewAuthHandler(userRepo interfaces.UserRepository, log logger.Logger, jwtUtil *jwt.JWTUtil) *AuthHandler {
+func NewAuthHandler(
+	userRepo interfaces.UserRepository,
+	auditRepo interfaces.AuditLogRepository,
+	systemAccounts *service.SystemAccountService,
+	sso *service.SSOService,
+	onboarding *service.OnboardingService,
+	analytics *service.AnalyticsService,
+	searchIndex *service.SearchIndexService,
+	reactivationGracePeriod time.Duration,
+	log logger.Logger,
+	jwtUtil *jwt.JWTUtil,
+) *AuthHandler {
 	return &AuthHandler{
-		userRepo: userRepo,
-		log:      log,
-		jwtUtil:  jwtUtil,
+		userRepo:                userRepo,
+		auditRepo:               auditRepo,
+		systemAccounts:          systemAccounts,
+		sso:                     sso,
+		onboarding:              onboarding,
+		analytics:               analytics,
+		searchIndex:             searchIndex,
+		reactivationGracePeriod: reactivationGracePeriod,
+		log:                     log,
+		jwtUtil:                 jwtUtil,
 	}
 }
 
@@ -39,13 +78,34 @@ type RegisterRequest struct {
 }
 
 // Register ユーザー登録ハンドラー
+// @Summary ユーザー登録
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RegisterRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
+	if h.sso.Enabled() {
+		response.Forbidden(c, "このインスタンスではシングルサインオンでのみログインできます")
+		return
+	}
+
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ValidationError(c, err)
 		return
 	}
 
+	// システムアカウント用に予約されたユーザー名は登録できない
+	if h.systemAccounts.IsReserved(req.Username) {
+		response.BadRequest(c, "このユーザー名は使用できません", nil)
+		return
+	}
+
 	// ユーザー名とメールアドレスの使用可否をチェック
 	usernameAvailable, err := h.userRepo.IsUsernameAvailable(c, req.Username)
 	if err != nil {
@@ -95,8 +155,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// 登録直後のホームタイムラインが空にならないよう、設定されたアカウントを自動でフォローする
+	h.onboarding.Welcome(c, user.ID)
+	h.analytics.TrackSignup(user.ID, service.SignupMethodPassword)
+	h.searchIndex.IndexUser(user)
+
 	// JWTトークンを生成
-	token, err := h.jwtUtil.GenerateToken(user.ID.String())
+	token, err := h.jwtUtil.GenerateTokenWithRole(user.ID.String(), string(user.Role))
 	if err != nil {
 		h.log.Error("トークンの生成中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "トークンの生成中にエラーが発生しました")
@@ -121,7 +186,23 @@ type LoginRequest struct {
 }
 
 // Login ログインハンドラー
+// @Summary ログイン
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
+	if h.sso.Enabled() {
+		response.Forbidden(c, "このインスタンスではシングルサインオンでのみログインできます")
+		return
+	}
+
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ValidationError(c, err)
@@ -132,6 +213,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	user, err := h.userRepo.GetByEmail(c, req.Email)
 	if err != nil {
 		h.log.Error("ユーザーの取得中にエラーが発生しました", "error", err)
+		h.recordLoginFailure(c, nil, service.SignupMethodPassword, "unknown_email", req.Email)
 		response.Unauthorized(c, "メールアドレスまたはパスワードが正しくありません")
 		return
 	}
@@ -139,33 +221,361 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	// パスワードを検証
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		h.log.Info("パスワードの検証に失敗しました", "userID", user.ID)
+		h.recordLoginFailure(c, &user.ID, service.SignupMethodPassword, "invalid_password", req.Email)
+		response.Unauthorized(c, "メールアドレスまたはパスワードが正しくありません")
+		return
+	}
+
+	// 管理者からパスワードの再設定を求められている場合は、新しいパスワードを設定するまでログインさせない
+	if user.PasswordResetRequired {
+		h.recordLoginFailure(c, &user.ID, service.SignupMethodPassword, "password_reset_required", req.Email)
+		response.JSON(c, http.StatusForbidden, response.NewErrorResponse(
+			"PASSWORD_RESET_REQUIRED", "新しいパスワードを設定してください", nil,
+		))
+		return
+	}
+
+	token, reactivated, ok := h.startSession(c, user, service.SignupMethodPassword)
+	if !ok {
+		return
+	}
+
+	// レスポンスを返す
+	c.JSON(http.StatusOK, loginResponse(user, token, reactivated))
+}
+
+// ResetPasswordRequest パスワードの再設定リクエストの構造体
+type ResetPasswordRequest struct {
+	Email           string `json:"email" binding:"required,email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ResetPassword 現在のパスワードを確認して新しいパスワードを設定し、ログインするハンドラー
+// 管理者からパスワードの再設定を求められたユーザーは、このエンドポイントで新しいパスワードを設定するまでログインできない
+// @Summary 現在のパスワードを確認して新しいパスワードを設定し、ログインする
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	if h.sso.Enabled() {
+		response.Forbidden(c, "このインスタンスではシングルサインオンでのみログインできます")
+		return
+	}
+
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	user, err := h.userRepo.GetByEmail(c, req.Email)
+	if err != nil {
+		h.log.Error("ユーザーの取得中にエラーが発生しました", "error", err)
+		h.recordLoginFailure(c, nil, service.SignupMethodPassword, "unknown_email", req.Email)
+		response.Unauthorized(c, "メールアドレスまたはパスワードが正しくありません")
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		h.log.Info("パスワードの検証に失敗しました", "userID", user.ID)
+		h.recordLoginFailure(c, &user.ID, service.SignupMethodPassword, "invalid_password", req.Email)
 		response.Unauthorized(c, "メールアドレスまたはパスワードが正しくありません")
 		return
 	}
 
+	if req.NewPassword == req.CurrentPassword {
+		response.BadRequest(c, "現在のパスワードとは異なるパスワードを設定してください", nil)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		h.log.Error("パスワードのハッシュ化中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "パスワードの更新中にエラーが発生しました")
+		return
+	}
+
+	if err := h.userRepo.UpdatePassword(c, user.ID, string(hashedPassword)); err != nil {
+		h.log.Error("パスワードの更新中にエラーが発生しました", "error", err, "userID", user.ID)
+		response.InternalServerError(c, "パスワードの更新中にエラーが発生しました")
+		return
+	}
+	user.PasswordResetRequired = false
+	h.recordAuthEvent(c, models.NewAuthAuditLog(&user.ID, models.AuditPasswordChange, nil))
+
+	token, reactivated, ok := h.startSession(c, user, service.SignupMethodPassword)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse(user, token, reactivated))
+}
+
+// GetMethods 利用できるログイン方法を返すハンドラー
+// @Summary 利用できるログイン方法を返す
+// @Tags auth
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/methods [get]
+func (h *AuthHandler) GetMethods(c *gin.Context) {
+	methods := gin.H{
+		"password": !h.sso.Enabled(),
+		"sso":      h.sso.Enabled(),
+	}
+	if h.sso.Enabled() {
+		methods["sso_login_url"] = ssoCookiePath + "/login"
+	}
+	c.JSON(http.StatusOK, methods)
+}
+
+// SSOLogin シングルサインオンの開始ハンドラー（プロバイダーのログイン画面へリダイレクトする）
+// @Summary シングルサインオンの開始（プロバイダーのログイン画面へリダイレクトする）
+// @Tags auth
+// @Produce json
+// @Success 302 "Found"
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Router /api/v1/auth/sso/login [get]
+func (h *AuthHandler) SSOLogin(c *gin.Context) {
+	if !h.sso.Enabled() {
+		response.NotFound(c, "シングルサインオンは有効になっていません")
+		return
+	}
+
+	// CSRFとリプレイを防ぐstate・nonceと、PKCEのコード検証子
+	values := make([]string, 3)
+	for i := range values {
+		value, err := oidc.RandomString()
+		if err != nil {
+			h.log.Error("シングルサインオンの開始中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "シングルサインオンの開始中にエラーが発生しました")
+			return
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	authURL, err := h.sso.AuthCodeURL(c, state, nonce, codeVerifier)
+	if err != nil {
+		h.log.Error("シングルサインオンの開始中にエラーが発生しました", "error", err)
+		response.JSON(c, http.StatusBadGateway, response.NewErrorResponse("BAD_GATEWAY", "認証プロバイダーに接続できません", nil))
+		return
+	}
+
+	h.setSSOCookie(c, strings.Join(values, "."), ssoCookieMaxAge)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// SSOCallback シングルサインオンのコールバックハンドラー
+// 認可コードを検証してログインし、初回のログインではユーザーを作成する
+// @Summary シングルサインオンのコールバック
+// @Tags auth
+// @Produce json
+// @Param error query string false "プロバイダーが返したエラー"
+// @Param error_description query string false "プロバイダーが返したエラーの説明"
+// @Param state query string false "ログインを開始したときに発行したstate"
+// @Param code query string false "認可コード"
+// @Success 200 {object} response.Response
+// @Success 302 "Found"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/auth/sso/callback [get]
+func (h *AuthHandler) SSOCallback(c *gin.Context) {
+	if !h.sso.Enabled() {
+		response.NotFound(c, "シングルサインオンは有効になっていません")
+		return
+	}
+
+	// クッキーは一度だけ使用する
+	cookie, _ := c.Cookie(ssoCookieName)
+	h.setSSOCookie(c, "", -1)
+
+	if providerError := c.Query("error"); providerError != "" {
+		h.log.Info("認証プロバイダーでログインが完了しませんでした", "error", providerError, "description", c.Query("error_description"))
+		response.Unauthorized(c, "シングルサインオンでのログインが完了しませんでした")
+		return
+	}
+
+	parts := strings.Split(cookie, ".")
+	state := c.Query("state")
+	code := c.Query("code")
+	if len(parts) != 3 || state == "" || code == "" || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(state)) != 1 {
+		response.BadRequest(c, "シングルサインオンのリクエストが無効です。もう一度ログインしてください", nil)
+		return
+	}
+
+	user, err := h.sso.Authenticate(c, code, parts[2], parts[1])
+	if err != nil {
+		switch err.Error() {
+		case "sso group not allowed":
+			h.recordLoginFailure(c, nil, service.SignupMethodSSO, "group_not_allowed", "")
+			response.Forbidden(c, "このアカウントにはログインが許可されていません")
+		case "sso email missing":
+			h.recordLoginFailure(c, nil, service.SignupMethodSSO, "email_missing", "")
+			response.Forbidden(c, "認証プロバイダーからメールアドレスを取得できませんでした")
+		case "sso account conflict":
+			h.recordLoginFailure(c, nil, service.SignupMethodSSO, "account_conflict", "")
+			response.Conflict(c, "このメールアドレスは既に別のアカウントで使用されています", nil)
+		default:
+			h.log.Error("シングルサインオンでのログイン中にエラーが発生しました", "error", err)
+			h.recordLoginFailure(c, nil, service.SignupMethodSSO, "provider_error", "")
+			response.Unauthorized(c, "シングルサインオンでのログインに失敗しました")
+		}
+		return
+	}
+
+	token, reactivated, ok := h.startSession(c, user, service.SignupMethodSSO)
+	if !ok {
+		return
+	}
+
+	// フロントエンドへはURLのフラグメントでトークンを渡す（サーバーのログに残らないようにする）
+	if redirectURL := h.sso.PostLoginRedirectURL(); redirectURL != "" {
+		fragment := url.Values{
+			"token":       {token},
+			"reactivated": {map[bool]string{true: "true", false: "false"}[reactivated]},
+		}
+		c.Redirect(http.StatusFound, redirectURL+"#"+fragment.Encode())
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse(user, token, reactivated))
+}
+
+// startSession 認証済みのユーザーのアクセストークンを発行する
+// 削除手続き中・統合中・IdPから停止されたアカウントはログインさせず、無効化されたアカウントは猶予期間内であれば再開する
+// ログインできない場合はエラーレスポンスを送信してfalseを返す
+// ログインの成否は監査ログに記録する（methodはログイン方法）
+func (h *AuthHandler) startSession(c *gin.Context, user *models.User, method string) (string, bool, bool) {
+	// 削除手続き中のアカウントはログインさせない
+	if user.IsDeleting() {
+		h.recordLoginFailure(c, &user.ID, method, "deleting", "")
+		response.Forbidden(c, "このアカウントは削除手続き中です")
+		return "", false, false
+	}
+
+	// 他のアカウントに統合中のアカウントはログインさせない
+	if user.IsMerging() {
+		h.recordLoginFailure(c, &user.ID, method, "merging", "")
+		response.Forbidden(c, "このアカウントは他のアカウントに統合中です")
+		return "", false, false
+	}
+
+	// IdPから停止されたアカウントは、IdPで有効化されるまでログインさせない
+	if user.IsSuspended() {
+		h.recordLoginFailure(c, &user.ID, method, "suspended", "")
+		response.Forbidden(c, "このアカウントは管理者によって停止されています")
+		return "", false, false
+	}
+
+	// 管理者が停止したアカウントは、管理者が解除するまでログインさせない
+	if user.IsBanned() {
+		h.recordLoginFailure(c, &user.ID, method, "banned", "")
+		response.Forbidden(c, "このアカウントは管理者によって停止されています")
+		return "", false, false
+	}
+
+	// 無効化されたアカウントは猶予期間内であれば再開し、過ぎている場合はログインさせない
+	reactivated := false
+	if user.IsDeactivated() {
+		if !user.CanReactivateAt(time.Now().UTC(), h.reactivationGracePeriod) {
+			h.recordLoginFailure(c, &user.ID, method, "deactivated", "")
+			response.Forbidden(c, "このアカウントは無効化されています")
+			return "", false, false
+		}
+		if err := h.userRepo.Reactivate(c, user.ID); err != nil {
+			h.log.Error("アカウントの再開中にエラーが発生しました", "error", err, "userID", user.ID)
+			response.InternalServerError(c, "アカウントの再開中にエラーが発生しました")
+			return "", false, false
+		}
+		h.log.Info("無効化されたアカウントを再開しました", "userID", user.ID)
+		reactivated = true
+	}
+
 	// JWTトークンを生成
-	token, err := h.jwtUtil.GenerateToken(user.ID.String())
+	token, err := h.jwtUtil.GenerateTokenWithRole(user.ID.String(), string(user.Role))
 	if err != nil {
 		h.log.Error("トークンの生成中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "トークンの生成中にエラーが発生しました")
-		return
+		return "", false, false
 	}
 
-	// レスポンスを返す
-	c.JSON(http.StatusOK, gin.H{
+	h.recordAuthEvent(c, models.NewAuthAuditLog(&user.ID, models.AuditLoginSucceeded, map[string]string{
+		"method":      method,
+		"reactivated": strconv.FormatBool(reactivated),
+	}))
+
+	return token, reactivated, true
+}
+
+// recordLoginFailure 拒否したログインの試行を監査ログに記録する
+// userIDはアカウントを特定できなかった場合はnil、emailは入力されたメールアドレス（ない場合は空）
+func (h *AuthHandler) recordLoginFailure(c *gin.Context, userID *uuid.UUID, method, reason, email string) {
+	details := map[string]string{
+		"method": method,
+		"reason": reason,
+	}
+	if email != "" {
+		details["email"] = email
+	}
+	h.recordAuthEvent(c, models.NewAuthAuditLog(userID, models.AuditLoginFailed, details))
+}
+
+// recordAuthEvent 認証に関する操作をリクエストしたクライアントの情報とともに監査ログに記録する
+// 記録に失敗しても認証の処理は続ける
+func (h *AuthHandler) recordAuthEvent(c *gin.Context, entry *models.AuditLog) {
+	entry.WithClient(c.ClientIP(), c.Request.UserAgent())
+	if err := h.auditRepo.Create(c, entry); err != nil {
+		h.log.Error("監査ログの記録中にエラーが発生しました", "error", err, "action", entry.Action)
+	}
+}
+
+// setSSOCookie シングルサインオンのクッキーを設定する（maxAgeが負の場合は削除する）
+func (h *AuthHandler) setSSOCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	// プロバイダーからのリダイレクトでも送信されるようLaxにする
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoCookieName, value, maxAge, ssoCookiePath, "", secure, true)
+}
+
+// loginResponse ログインのレスポンスを作成する
+func loginResponse(user *models.User, token string, reactivated bool) gin.H {
+	return gin.H{
 		"user": gin.H{
 			"id":           user.ID,
 			"username":     user.Username,
 			"email":        user.Email,
 			"display_name": user.Name,
 			"avatar_url":   user.ProfileImage,
+			"is_supporter": user.IsSupporter(),
 			"bio":          user.Bio,
 		},
-		"token": token,
-	})
+		"token":       token,
+		"reactivated": reactivated,
+	}
 }
 
 // RefreshToken トークン更新ハンドラー
+// @Summary トークン更新
+// @Tags auth
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	// リフレッシュトークンはAuthミドルウェアで検証済み
 	// c.GetFromContextで現在のユーザーIDを取得
@@ -183,8 +593,8 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// ユーザーが存在するか確認
-	_, err = h.userRepo.GetByID(c, userID)
+	// ユーザーが存在するか確認（権限が変わっている場合に備えて最新の権限をトークンに含める）
+	user, err := h.userRepo.GetByID(c, userID)
 	if err != nil {
 		h.log.Error("ユーザーの確認中にエラーが発生しました", "error", err)
 		response.Unauthorized(c, "トークンが無効です")
@@ -192,7 +602,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	// 新しいJWTトークンを生成
-	token, err := h.jwtUtil.GenerateToken(userID.String())
+	token, err := h.jwtUtil.GenerateTokenWithRole(userID.String(), string(user.Role))
 	if err != nil {
 		h.log.Error("トークンの生成中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "トークンの生成中にエラーが発生しました")
@@ -205,6 +615,11 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 }
 
 // Logout ログアウトハンドラー
+// @Summary ログアウト
+// @Tags auth
+// @Produce json
+// @Success 204 "No Content"
+// @Router /api/v1/auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
 	// サーバー側でトークンを無効化する必要はありません
 	// クライアント側でトークンを削除すればOK
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeviceHandler プッシュ通知を受け取るモバイル端末の登録を管理するハンドラーを管理する構造体
+type DeviceHandler struct {
+	push *service.PushService
+	log  logger.Logger
+}
+
+// NewDeviceHandler 新しい端末ハンドラーを作成する
+// pushがnilの場合はプッシュ通知を無効とする
+func NewDeviceHandler(push *service.PushService, log logger.Logger) *DeviceHandler {
+	return &DeviceHandler{
+		push: push,
+		log:  log,
+	}
+}
+
+// RegisterDeviceRequest 端末の登録リクエストの構造体
+type RegisterDeviceRequest struct {
+	// プッシュサービス（fcm・apns）
+	Platform string `json:"platform" binding:"required,oneof=fcm apns"`
+	// プッシュサービスが発行した端末トークン
+	Token string `json:"token" binding:"required,max=4096"`
+}
+
+// ListDevices 自分が登録した端末の一覧を取得するハンドラー
+// @Summary 自分が登録した端末の一覧を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/devices [get]
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	devices, err := h.push.ListDevices(c, currentUserID)
+	if err != nil {
+		h.log.Error("端末の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "端末の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{"devices": devices})
+}
+
+// RegisterDevice プッシュ通知を受け取る端末を登録するハンドラー
+// アプリの起動時やトークンの更新時に呼び出す（登録済みのトークンは上書きする）
+// @Summary プッシュ通知を受け取る端末を登録する
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterDeviceRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/devices [post]
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	device, err := h.push.RegisterDevice(c, currentUserID, models.DevicePlatform(req.Platform), req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidDeviceToken):
+			response.BadRequest(c, "端末トークンが不正です", nil)
+		case errors.Is(err, service.ErrPushPlatformUnavailable):
+			response.BadRequest(c, "このプラットフォームへのプッシュ通知は利用できません", gin.H{"platform": req.Platform})
+		default:
+			h.log.Error("端末の登録中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "端末の登録中にエラーが発生しました")
+		}
+		return
+	}
+
+	response.Created(c, gin.H{"device": device})
+}
+
+// UnregisterDevice 端末の登録を解除するハンドラー（ログアウト時など）
+// @Summary 端末の登録を解除する（ログアウト時など）
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "端末のトークン"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/devices/{token} [delete]
+func (h *DeviceHandler) UnregisterDevice(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	token := c.Param("token")
+	if err := h.push.UnregisterDevice(c, currentUserID, token); err != nil {
+		if err.Error() == "device token not found" {
+			response.NotFound(c, "端末が見つかりません")
+			return
+		}
+		h.log.Error("端末の登録解除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "端末の登録解除中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{"token": token, "deleted": true})
+}
+
+// currentUserID プッシュ通知が有効であることを確認し、認証済みユーザーのIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *DeviceHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	if h.push == nil {
+		response.NotFound(c, "このインスタンスはプッシュ通知を提供していません")
+		return uuid.Nil, false
+	}
+
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
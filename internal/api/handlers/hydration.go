@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/google/uuid"
+)
+
+// postHydration 投稿一覧のレスポンス作成に必要な関連データをまとめて取得した結果
+// 投稿ごとにユーザーやいいね状態を取得するとクエリ数が投稿数に比例するため、一覧単位で取得する
+type postHydration struct {
+	// 投稿者と参照先の投稿者
+	users map[uuid.UUID]*models.User
+	// 閲覧者がいいね済みの投稿
+	liked map[uuid.UUID]bool
+	// 投稿ごとの絵文字別のリアクション数と、閲覧者がリアクションした絵文字
+	reactions map[uuid.UUID][]*models.ReactionCount
+	reacted   map[uuid.UUID][]string
+	// 返信先・リポスト元の投稿
+	related map[uuid.UUID]*models.Post
+	// 投稿ごとの添付メディア（プレースホルダーと画像の大きさを含む）
+	media map[uuid.UUID][]models.MediaAttachment
+}
+
+// hydratePosts 投稿一覧の投稿者・返信先とリポスト元の投稿・リアクション数・添付メディア・閲覧者のいいねとリアクションの状態を最大6クエリで取得する
+// viewerIDがuuid.Nilの場合はいいねとリアクションの状態を取得しない
+func hydratePosts(
+	ctx context.Context,
+	userRepo interfaces.UserRepository,
+	postRepo interfaces.PostRepository,
+	likeRepo interfaces.LikeRepository,
+	reactionRepo interfaces.ReactionRepository,
+	media *service.MediaService,
+	viewerID uuid.UUID,
+	posts []*models.Post,
+) (*postHydration, error) {
+	h := &postHydration{
+		users:     map[uuid.UUID]*models.User{},
+		liked:     map[uuid.UUID]bool{},
+		reactions: map[uuid.UUID][]*models.ReactionCount{},
+		reacted:   map[uuid.UUID][]string{},
+		related:   map[uuid.UUID]*models.Post{},
+		media:     map[uuid.UUID][]models.MediaAttachment{},
+	}
+	if len(posts) == 0 {
+		return h, nil
+	}
+
+	// 返信先・リポスト元の投稿
+	var relatedIDs []uuid.UUID
+	for _, post := range posts {
+		if post.ReplyToID != nil {
+			relatedIDs = append(relatedIDs, *post.ReplyToID)
+		}
+		if post.RepostID != nil {
+			relatedIDs = append(relatedIDs, *post.RepostID)
+		}
+	}
+	if len(relatedIDs) > 0 {
+		related, err := postRepo.GetByIDs(ctx, uniqueIDs(relatedIDs))
+		if err != nil {
+			return nil, err
+		}
+		h.related = related
+	}
+
+	// 投稿者と参照先の投稿者
+	userIDs := make([]uuid.UUID, 0, len(posts)+len(h.related))
+	for _, post := range posts {
+		userIDs = append(userIDs, post.UserID)
+	}
+	for _, post := range h.related {
+		userIDs = append(userIDs, post.UserID)
+	}
+	users, err := userRepo.GetByIDs(ctx, uniqueIDs(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	h.users = users
+
+	postIDs := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	// 絵文字ごとのリアクション数
+	reactions, err := reactionRepo.GetSummaries(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	h.reactions = reactions
+
+	// 添付メディアのプレースホルダー（取得できない場合はURLのみ）
+	h.media = media.Attachments(ctx, posts)
+
+	// 閲覧者のいいねとリアクションの状態
+	if viewerID != uuid.Nil {
+		liked, err := likeRepo.HasLikedBatch(ctx, viewerID, postIDs)
+		if err != nil {
+			return nil, err
+		}
+		h.liked = liked
+
+		reacted, err := reactionRepo.GetUserReactions(ctx, viewerID, postIDs)
+		if err != nil {
+			return nil, err
+		}
+		h.reacted = reacted
+	}
+
+	return h, nil
+}
+
+// reactionCounts 投稿の絵文字ごとのリアクション数を返す（リアクションがない場合は空のスライス）
+func (h *postHydration) reactionCounts(postID uuid.UUID) []*models.ReactionCount {
+	if counts, ok := h.reactions[postID]; ok {
+		return counts
+	}
+	return []*models.ReactionCount{}
+}
+
+// viewerReactions 閲覧者が投稿にリアクションした絵文字を返す（リアクションしていない場合は空のスライス）
+func (h *postHydration) viewerReactions(postID uuid.UUID) []string {
+	if emoji, ok := h.reacted[postID]; ok {
+		return emoji
+	}
+	return []string{}
+}
+
+// mediaAttachments 投稿の添付メディアを返す（添付がない場合は空のスライス）
+func (h *postHydration) mediaAttachments(postID uuid.UUID) []models.MediaAttachment {
+	if media, ok := h.media[postID]; ok {
+		return media
+	}
+	return []models.MediaAttachment{}
+}
+
+// uniqueIDs 重複を除いたIDの一覧を返す（順序は維持する）
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
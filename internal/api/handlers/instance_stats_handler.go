@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 集計前のリクエストに再試行を促すまでの秒数
+const instanceStatsRetryAfterSeconds = 60
+
+// InstanceStatsHandler インスタンスディレクトリ向けの公開統計のハンドラーを管理する構造体
+type InstanceStatsHandler struct {
+	instanceStats *service.InstanceStatsService
+	maxAge        time.Duration
+}
+
+// NewInstanceStatsHandler 新しい公開統計のハンドラーを作成する
+// instanceStatsがnilの場合は公開統計を無効とし、maxAgeはレスポンスをキャッシュしてよい期間
+func NewInstanceStatsHandler(instanceStats *service.InstanceStatsService, maxAge time.Duration) *InstanceStatsHandler {
+	return &InstanceStatsHandler{
+		instanceStats: instanceStats,
+		maxAge:        maxAge,
+	}
+}
+
+// GetInstanceStats ユーザー数・投稿数・直近30日間のアクティブユーザー数を取得するハンドラー
+// 定期的な集計の結果のみを返すため、リクエストが集中してもデータベースに負荷をかけない
+// @Summary ユーザー数・投稿数・直近30日間のアクティブユーザー数を取得する
+// @Tags instance
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/instance/stats [get]
+func (h *InstanceStatsHandler) GetInstanceStats(c *gin.Context) {
+	if h.instanceStats == nil {
+		response.NotFound(c, "このインスタンスは統計を公開していません")
+		return
+	}
+
+	stats := h.instanceStats.Current()
+	if stats == nil {
+		c.Header("Retry-After", strconv.Itoa(instanceStatsRetryAfterSeconds))
+		response.JSON(c, http.StatusServiceUnavailable, response.NewErrorResponse(
+			"STATS_NOT_READY", "統計を集計中です。しばらくしてから再度お試しください", nil,
+		))
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(h.maxAge/time.Second)))
+	response.Success(c, stats)
+}
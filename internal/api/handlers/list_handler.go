@@ -0,0 +1,690 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// リストに追加できるメンバーの上限
+const maxListMembers = 5000
+
+// ListHandler リスト関連のハンドラーを管理する構造体
+type ListHandler struct {
+	listRepo      interfaces.ListRepository
+	userRepo      interfaces.UserRepository
+	postRepo      interfaces.PostRepository
+	likeRepo      interfaces.LikeRepository
+	reactionRepo  interfaces.ReactionRepository
+	blockService  *service.BlockService
+	contentPolicy *service.ContentPolicyService
+	media         *service.MediaService
+	log           logger.Logger
+}
+
+// NewListHandler 新しいリストハンドラーを作成する
+func NewListHandler(
+	listRepo interfaces.ListRepository,
+	userRepo interfaces.UserRepository,
+	postRepo interfaces.PostRepository,
+	likeRepo interfaces.LikeRepository,
+	reactionRepo interfaces.ReactionRepository,
+	blockService *service.BlockService,
+	contentPolicy *service.ContentPolicyService,
+	media *service.MediaService,
+	log logger.Logger,
+) *ListHandler {
+	return &ListHandler{
+		listRepo:      listRepo,
+		userRepo:      userRepo,
+		postRepo:      postRepo,
+		likeRepo:      likeRepo,
+		reactionRepo:  reactionRepo,
+		blockService:  blockService,
+		contentPolicy: contentPolicy,
+		media:         media,
+		log:           log,
+	}
+}
+
+// CreateListRequest リスト作成リクエストの構造体
+type CreateListRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=25"`
+	Description string `json:"description" binding:"omitempty,max=100"`
+	IsPrivate   bool   `json:"is_private"`
+}
+
+// UpdateListRequest リスト更新リクエストの構造体
+type UpdateListRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=25"`
+	Description *string `json:"description" binding:"omitempty,max=100"`
+	IsPrivate   *bool   `json:"is_private"`
+}
+
+// AddListMemberRequest リストメンバー追加リクエストの構造体
+type AddListMemberRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// CreateList リスト作成ハンドラー
+// @Summary リスト作成
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateListRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists [post]
+func (h *ListHandler) CreateList(c *gin.Context) {
+	var req CreateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list := models.NewList(currentUserID, req.Name, req.Description, req.IsPrivate)
+	if err := h.listRepo.Create(c, list); err != nil {
+		h.log.Error("リスト作成中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストの作成中にエラーが発生しました")
+		return
+	}
+
+	response.Created(c, listResponse(list))
+}
+
+// GetMyLists 自分が作成したリスト一覧取得ハンドラー
+// @Summary 自分が作成したリスト一覧取得
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists [get]
+func (h *ListHandler) GetMyLists(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, perPage, offset := listPagination(c)
+
+	lists, err := h.listRepo.GetByOwnerID(c, currentUserID, offset, perPage)
+	if err != nil {
+		h.log.Error("リスト一覧の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リスト一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	totalLists, err := h.listRepo.CountByOwnerID(c, currentUserID)
+	if err != nil {
+		h.log.Error("リスト数の取得中にエラーが発生しました", "error", err)
+		totalLists = int64(len(lists))
+	}
+
+	listsResponse := make([]gin.H, 0, len(lists))
+	for _, list := range lists {
+		listsResponse = append(listsResponse, listResponse(list))
+	}
+
+	response.Success(c, gin.H{
+		"lists":      listsResponse,
+		"pagination": paginationMeta(totalLists, page, perPage),
+	})
+}
+
+// GetList リスト詳細取得ハンドラー
+// @Summary リスト詳細取得
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists/{id} [get]
+func (h *ListHandler) GetList(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, ok := h.loadVisibleList(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	response.Success(c, listResponse(list))
+}
+
+// UpdateList リスト更新ハンドラー
+// @Summary リスト更新
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateListRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists/{id} [put]
+func (h *ListHandler) UpdateList(c *gin.Context) {
+	var req UpdateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, ok := h.loadOwnedList(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	// 変更があるフィールドのみ更新
+	if req.Name != nil {
+		list.Name = *req.Name
+	}
+	if req.Description != nil {
+		list.Description = *req.Description
+	}
+	if req.IsPrivate != nil {
+		list.IsPrivate = *req.IsPrivate
+	}
+	list.UpdatedAt = time.Now().UTC()
+
+	if err := h.listRepo.Update(c, list); err != nil {
+		h.log.Error("リスト更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストの更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, listResponse(list))
+}
+
+// DeleteList リスト削除ハンドラー
+// @Summary リスト削除
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists/{id} [delete]
+func (h *ListHandler) DeleteList(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, ok := h.loadOwnedList(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	if err := h.listRepo.Delete(c, list.ID); err != nil {
+		h.log.Error("リスト削除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストの削除中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"message": "リストを削除しました",
+	})
+}
+
+// GetListMembers リストメンバー一覧取得ハンドラー
+// @Summary リストメンバー一覧取得
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists/{id}/members [get]
+func (h *ListHandler) GetListMembers(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, ok := h.loadVisibleList(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	page, perPage, offset := listPagination(c)
+
+	memberIDs, err := h.listRepo.GetMembers(c, list.ID, offset, perPage)
+	if err != nil {
+		h.log.Error("リストメンバーの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストメンバーの取得中にエラーが発生しました")
+		return
+	}
+
+	// ブロック関係にあるユーザーを除外
+	memberIDs, err = h.blockService.FilterUserIDs(c, currentUserID, memberIDs)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストメンバーの取得中にエラーが発生しました")
+		return
+	}
+
+	members, err := h.userRepo.GetByIDs(c, memberIDs)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストメンバーの取得中にエラーが発生しました")
+		return
+	}
+
+	membersResponse := make([]gin.H, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		member, ok := members[memberID]
+		if !ok {
+			continue
+		}
+
+		membersResponse = append(membersResponse, gin.H{
+			"id":           member.ID,
+			"username":     member.Username,
+			"display_name": member.Name,
+			"bio":          member.Bio,
+			"avatar_url":   member.ProfileImage,
+			"is_supporter": member.IsSupporter(),
+			"verified":     member.IsVerified,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"members":    membersResponse,
+		"pagination": paginationMeta(int64(list.MemberCount), page, perPage),
+	})
+}
+
+// AddListMember リストメンバー追加ハンドラー
+// @Summary リストメンバー追加
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body AddListMemberRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists/{id}/members [post]
+func (h *ListHandler) AddListMember(c *gin.Context) {
+	var req AddListMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, ok := h.loadOwnedList(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	if list.MemberCount >= maxListMembers {
+		response.BadRequest(c, "リストのメンバー数が上限に達しています", gin.H{
+			"max_members": maxListMembers,
+		})
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByUsername(c, req.Username)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	// ブロック関係にあるユーザーはリストに追加できない
+	if err := h.blockService.CheckInteraction(c, currentUserID, targetUser.ID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.Forbidden(c, "このユーザーをリストに追加することはできません")
+			return
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストメンバーの追加中にエラーが発生しました")
+		return
+	}
+
+	isMember, err := h.listRepo.IsMember(c, list.ID, targetUser.ID)
+	if err != nil {
+		h.log.Error("リストメンバーの確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストメンバーの追加中にエラーが発生しました")
+		return
+	}
+	if isMember {
+		response.BadRequest(c, "既にリストに追加されています", nil)
+		return
+	}
+
+	if err := h.listRepo.AddMember(c, list.ID, targetUser.ID); err != nil {
+		h.log.Error("リストメンバーの追加中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストメンバーの追加中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"message": "リストにユーザーを追加しました",
+		"list_id": list.ID,
+		"user_id": targetUser.ID,
+	})
+}
+
+// RemoveListMember リストメンバー削除ハンドラー
+// @Summary リストメンバー削除
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param username path string true "ユーザー名"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists/{id}/members/{username} [delete]
+func (h *ListHandler) RemoveListMember(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, ok := h.loadOwnedList(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	targetUser, err := h.userRepo.GetByUsername(c, username)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	if err := h.listRepo.RemoveMember(c, list.ID, targetUser.ID); err != nil {
+		h.log.Error("リストメンバーの削除中にエラーが発生しました", "error", err)
+		response.NotFound(c, "リストにこのユーザーは含まれていません")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"message": "リストからユーザーを削除しました",
+		"list_id": list.ID,
+		"user_id": targetUser.ID,
+	})
+}
+
+// GetListTimeline リストタイムライン取得ハンドラー
+// リストメンバーの投稿を時系列順で取得する
+// @Summary リストタイムライン取得
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/lists/{id}/timeline [get]
+func (h *ListHandler) GetListTimeline(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, ok := h.loadVisibleList(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	page, perPage, offset := listPagination(c)
+
+	memberIDs, err := h.listRepo.GetAllMemberIDs(c, list.ID)
+	if err != nil {
+		h.log.Error("リストメンバーの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストタイムラインの取得中にエラーが発生しました")
+		return
+	}
+
+	// ブロック関係にあるユーザーの投稿はクエリで除外される
+	posts, err := h.postRepo.GetByUserIDs(c, memberIDs, currentUserID, offset, perPage)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストタイムラインの取得中にエラーが発生しました")
+		return
+	}
+
+	totalPosts, err := h.postRepo.CountByUserIDs(c, memberIDs, currentUserID)
+	if err != nil {
+		h.log.Error("投稿数の取得中にエラーが発生しました", "error", err)
+		totalPosts = int64(len(posts))
+	}
+
+	// 年齢制限のある投稿を除外
+	viewer, err := h.contentPolicy.LoadViewer(c, currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストタイムラインの取得中にエラーが発生しました")
+		return
+	}
+	posts, hiddenCount := h.contentPolicy.FilterPosts(viewer, posts)
+
+	// 投稿者・いいね状態をまとめて取得
+	hydrated, err := hydratePosts(c, h.userRepo, h.postRepo, h.likeRepo, h.reactionRepo, h.media, currentUserID, posts)
+	if err != nil {
+		h.log.Error("投稿の関連データ取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストタイムラインの取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿のレスポンスを作成
+	postsResponse := make([]gin.H, 0, len(posts))
+	for _, post := range posts {
+		user, ok := hydrated.users[post.UserID]
+		if !ok {
+			continue
+		}
+
+		isLiked := hydrated.liked[post.ID]
+
+		postsResponse = append(postsResponse, gin.H{
+			"id":              post.ID,
+			"user_id":         post.UserID,
+			"content":         post.Content,
+			"media_urls":      post.MediaURLs,
+			"media":           hydrated.mediaAttachments(post.ID),
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"created_at":      post.CreatedAt,
+			"likes_count":     post.LikeCount,
+			"views_count":     post.ViewCount,
+			"shares_count":    post.ShareCount,
+			"sharing_enabled": post.SharingEnabled,
+			"replies_count":   post.ReplyCount,
+			"reposts_count":   post.RepostCount,
+			"is_liked":        isLiked,
+			"reactions":       hydrated.reactionCounts(post.ID),
+			"my_reactions":    hydrated.viewerReactions(post.ID),
+			"user": gin.H{
+				"id":           user.ID,
+				"username":     user.Username,
+				"display_name": user.Name,
+				"avatar_url":   user.ProfileImage,
+				"is_supporter": user.IsSupporter(),
+			},
+		})
+	}
+
+	response.Success(c, gin.H{
+		"list":           listResponse(list),
+		"posts":          postsResponse,
+		"content_filter": contentFilterMeta(hiddenCount),
+		"pagination":     paginationMeta(totalPosts, page, perPage),
+	})
+}
+
+// currentUserID 認証済みユーザーのIDを取得する（取得できない場合はエラーレスポンスを返す）
+func (h *ListHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
+
+// loadVisibleList パスパラメータのリストを取得する（非公開リストは所有者以外には存在しないものとして扱う）
+func (h *ListHandler) loadVisibleList(c *gin.Context, currentUserID uuid.UUID) (*models.List, bool) {
+	listID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効なリストIDです", nil)
+		return nil, false
+	}
+
+	list, err := h.listRepo.GetByID(c, listID)
+	if err != nil || !list.CanView(currentUserID) {
+		response.NotFound(c, "リストが見つかりません")
+		return nil, false
+	}
+
+	// ブロック関係にあるユーザーのリストは表示しない
+	if err := h.blockService.CheckInteraction(c, currentUserID, list.OwnerID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.NotFound(c, "リストが見つかりません")
+			return nil, false
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リストの取得中にエラーが発生しました")
+		return nil, false
+	}
+
+	return list, true
+}
+
+// loadOwnedList パスパラメータのリストを取得し、所有者であることを確認する
+func (h *ListHandler) loadOwnedList(c *gin.Context, currentUserID uuid.UUID) (*models.List, bool) {
+	list, ok := h.loadVisibleList(c, currentUserID)
+	if !ok {
+		return nil, false
+	}
+
+	if list.OwnerID != currentUserID {
+		response.Forbidden(c, "このリストを編集する権限がありません")
+		return nil, false
+	}
+
+	return list, true
+}
+
+// リストのレスポンスを作成する
+func listResponse(list *models.List) gin.H {
+	return gin.H{
+		"id":           list.ID,
+		"owner_id":     list.OwnerID,
+		"name":         list.Name,
+		"description":  list.Description,
+		"is_private":   list.IsPrivate,
+		"member_count": list.MemberCount,
+		"created_at":   list.CreatedAt,
+		"updated_at":   list.UpdatedAt,
+	}
+}
+
+// ページネーションパラメータを取得する
+func listPagination(c *gin.Context) (page, perPage, offset int) {
+	page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ = strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	return page, perPage, (page - 1) * perPage
+}
+
+// ページネーション情報を作成する
+func paginationMeta(total int64, page, perPage int) gin.H {
+	totalPages := int(total) / perPage
+	if int(total)%perPage > 0 {
+		totalPages++
+	}
+
+	return gin.H{
+		"total":       total,
+		"page":        page,
+		"per_page":    perPage,
+		"total_pages": totalPages,
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
 	"github.com/TakuyaAizawa/gox/internal/util/response"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
 	"github.com/gin-gonic/gin"
@@ -16,6 +17,8 @@ type NotificationHandler struct {
 	notificationRepo interfaces.NotificationRepository
 	userRepo         interfaces.UserRepository
 	postRepo         interfaces.PostRepository
+	settingsRepo     interfaces.SettingsRepository
+	contentPolicy    *service.ContentPolicyService
 	log              logger.Logger
 }
 
@@ -24,25 +27,54 @@ func NewNotificationHandler(
 	notificationRepo interfaces.NotificationRepository,
 	userRepo interfaces.UserRepository,
 	postRepo interfaces.PostRepository,
+	settingsRepo interfaces.SettingsRepository,
+	contentPolicy *service.ContentPolicyService,
 	log logger.Logger,
 ) *NotificationHandler {
 	return &NotificationHandler{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
 		postRepo:         postRepo,
+		settingsRepo:     settingsRepo,
+		contentPolicy:    contentPolicy,
 		log:              log,
 	}
 }
 
+// まとめた形式で、グループごとに返すアクション実行者の最大数（残りはactors_countで示す）
+const notificationActorPreview = 3
+
 // GetNotifications ユーザーの通知一覧を取得する
+// groupingクエリパラメータ（grouped・flat）、またはクライアントの種類ごとの設定に応じて、まとめた形式と1件ずつの形式のどちらかで返す
+// まとめた形式はデータベースでまとめるため、ページネーションはグループ単位となる（1件ずつの形式は通知単位）
+// @Summary ユーザーの通知一覧を取得する
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param page query integer false "ページ番号" default(1)
+// @Param limit query integer false "取得する件数" default(20)
+// @Param grouping query string false "通知の形式（grouped・flat）"
+// @Param client query string false "クライアントの種類"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/notifications [get]
 func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	// ユーザーIDを取得
-	currentUserID, exists := c.Get("userID")
+	currentUserIDStr, exists := c.Get("userID")
 	if !exists {
 		response.Unauthorized(c, "認証が必要です")
 		return
 	}
 
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
 	// クエリパラメータを取得
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "20")
@@ -63,8 +95,20 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	offset := (page - 1) * limit
 	perPage := limit
 
-	// 通知の取得
-	notifications, err := h.notificationRepo.GetByUserID(c.Request.Context(), currentUserID.(uuid.UUID), offset, perPage)
+	// 通知の表示形式
+	grouping, ok := h.notificationGrouping(c, currentUserID)
+	if !ok {
+		return
+	}
+
+	// 通知の取得（まとめた形式の場合はグループを取得）
+	var notifications []*models.Notification
+	var groups []*models.NotificationGroup
+	if grouping == models.NotificationGroupingGrouped {
+		groups, err = h.notificationRepo.GetGroupedByUserID(c.Request.Context(), currentUserID, offset, perPage, notificationActorPreview)
+	} else {
+		notifications, err = h.notificationRepo.GetByUserID(c.Request.Context(), currentUserID, offset, perPage)
+	}
 	if err != nil {
 		h.log.Error("通知取得中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "通知の取得中にエラーが発生しました")
@@ -72,59 +116,85 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	}
 
 	// 通知の総数を取得
-	totalNotifications, err := h.notificationRepo.CountUnreadByUserID(c.Request.Context(), currentUserID.(uuid.UUID))
+	totalNotifications, err := h.notificationRepo.CountUnreadByUserID(c.Request.Context(), currentUserID)
 	if err != nil {
 		h.log.Error("通知数の取得中にエラーが発生しました", "error", err)
-		totalNotifications = int64(len(notifications))
+		totalNotifications = int64(len(notifications) + len(groups))
+	}
+
+	// 年齢制限のある投稿のプレビューを伏せるため閲覧者情報を取得
+	viewer, err := h.contentPolicy.LoadViewer(c.Request.Context(), currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通知の取得中にエラーが発生しました")
+		return
 	}
 
 	// 未読の通知を既読にマーク
-	if len(notifications) > 0 {
-		err = h.notificationRepo.MarkAllAsRead(c.Request.Context(), currentUserID.(uuid.UUID))
+	if len(notifications) > 0 || len(groups) > 0 {
+		err = h.notificationRepo.MarkAllAsRead(c.Request.Context(), currentUserID)
 		if err != nil {
 			h.log.Error("通知の既読マーク中にエラーが発生しました", "error", err)
 		}
 	}
 
-	// 通知レスポンスの作成
-	notificationsResponse := make([]gin.H, 0, len(notifications))
+	// アクション実行者と対象の投稿をまとめて取得
+	actorIDs := make([]uuid.UUID, 0, len(notifications))
+	var postIDs []uuid.UUID
 	for _, notification := range notifications {
-		// アクション実行者の情報を取得
-		actor, err := h.userRepo.GetByID(c, notification.ActorID)
-		if err != nil {
-			h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
-			continue
+		actorIDs = append(actorIDs, notification.ActorID)
+		if notification.PostID != nil {
+			postIDs = append(postIDs, *notification.PostID)
 		}
-
-		notificationResponse := gin.H{
-			"id":         notification.ID,
-			"type":       notification.Type,
-			"created_at": notification.CreatedAt,
-			"read":       notification.IsRead,
-			"actor": gin.H{
-				"id":           actor.ID,
-				"username":     actor.Username,
-				"display_name": actor.Name,
-				"avatar_url":   actor.ProfileImage,
-			},
+	}
+	for _, group := range groups {
+		actorIDs = append(actorIDs, group.ActorIDs...)
+		if group.PostID != nil {
+			postIDs = append(postIDs, *group.PostID)
 		}
+	}
+	actors, err := h.userRepo.GetByIDs(c.Request.Context(), uniqueIDs(actorIDs))
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通知の取得中にエラーが発生しました")
+		return
+	}
+	posts, err := h.postRepo.GetByIDs(c.Request.Context(), uniqueIDs(postIDs))
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通知の取得中にエラーが発生しました")
+		return
+	}
 
-		// 通知タイプに応じて追加情報を取得
-		switch notification.Type {
-		case models.NotificationTypeLike, models.NotificationTypeReply, models.NotificationTypeRepost:
-			if notification.PostID != nil {
-				post, err := h.postRepo.GetByID(c, *notification.PostID)
-				if err == nil {
-					notificationResponse["post"] = gin.H{
-						"id":         post.ID,
-						"content":    post.Content,
-						"created_at": post.CreatedAt,
-					}
-				}
+	// 通知レスポンスの作成
+	notificationsResponse := make([]gin.H, 0, len(notifications)+len(groups))
+	if grouping == models.NotificationGroupingGrouped {
+		for _, group := range groups {
+			if groupResponse, ok := h.groupResponse(group, actors, posts, viewer); ok {
+				notificationsResponse = append(notificationsResponse, groupResponse)
 			}
 		}
+	} else {
+		for _, notification := range notifications {
+			// アクション実行者の情報を取得
+			actor, ok := actors[notification.ActorID]
+			if !ok {
+				continue
+			}
 
-		notificationsResponse = append(notificationsResponse, notificationResponse)
+			notificationResponse := gin.H{
+				"id":         notification.ID,
+				"type":       notification.Type,
+				"created_at": notification.CreatedAt,
+				"read":       notification.IsRead,
+				"actor":      notificationActor(actor),
+			}
+			if post, ok := h.notificationPost(notification.Type, notification.PostID, posts, viewer); ok {
+				notificationResponse["post"] = post
+			}
+
+			notificationsResponse = append(notificationsResponse, notificationResponse)
+		}
 	}
 
 	// ページネーション情報を含むレスポンスを返す
@@ -135,6 +205,7 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 
 	response.Success(c, gin.H{
 		"notifications": notificationsResponse,
+		"grouping":      grouping,
 		"pagination": gin.H{
 			"total":       totalNotifications,
 			"page":        page,
@@ -144,7 +215,127 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	})
 }
 
+// notificationGrouping リクエストに対する通知の表示形式を決める
+// groupingクエリパラメータを優先し、指定がない場合はクライアントの種類（clientクエリパラメータまたはX-Client-Typeヘッダー）ごとの設定を使用する
+// 不正な表示形式が指定された場合はエラーレスポンスを送信してfalseを返す
+func (h *NotificationHandler) notificationGrouping(c *gin.Context, userID uuid.UUID) (models.NotificationGrouping, bool) {
+	if value := c.Query("grouping"); value != "" {
+		grouping := models.NotificationGrouping(value)
+		if !grouping.IsValid() {
+			response.BadRequest(c, "groupingはgroupedまたはflatで指定してください", nil)
+			return "", false
+		}
+		return grouping, true
+	}
+
+	clientType := c.Query("client")
+	if clientType == "" {
+		clientType = c.GetHeader("X-Client-Type")
+	}
+	if !models.IsValidClientType(clientType) {
+		return models.NotificationGroupingFlat, true
+	}
+
+	settings, err := h.settingsRepo.Get(c.Request.Context(), userID)
+	if err != nil {
+		// 設定を取得できない場合も通知は1件ずつの形式で返す
+		h.log.Error("設定の取得中にエラーが発生しました", "error", err)
+		return models.NotificationGroupingFlat, true
+	}
+	return settings.NotificationGroupingFor(clientType), true
+}
+
+// groupResponse まとめた通知のレスポンスを作成する（アクション実行者を1人も取得できない場合はfalseを返す）
+// actorsは新しい順に最大notificationActorPreview人で、actors_countはグループ全体のアクション実行者の人数
+// （「Aliceさんと他5人がいいねしました」のように表示する）
+func (h *NotificationHandler) groupResponse(
+	group *models.NotificationGroup,
+	actors map[uuid.UUID]*models.User,
+	posts map[uuid.UUID]*models.Post,
+	viewer *models.User,
+) (gin.H, bool) {
+	actorsResponse := make([]gin.H, 0, len(group.ActorIDs))
+	for _, actorID := range group.ActorIDs {
+		if actor, ok := actors[actorID]; ok {
+			actorsResponse = append(actorsResponse, notificationActor(actor))
+		}
+	}
+	if len(actorsResponse) == 0 {
+		return nil, false
+	}
+
+	groupResponse := gin.H{
+		"id":                  group.ID,
+		"type":                group.Type,
+		"created_at":          group.LatestAt,
+		"read":                group.Read,
+		"actors":              actorsResponse,
+		"actors_count":        group.ActorCount,
+		"notifications_count": group.NotificationCount,
+	}
+	if post, ok := h.notificationPost(group.Type, group.PostID, posts, viewer); ok {
+		groupResponse["post"] = post
+	}
+
+	return groupResponse, true
+}
+
+// notificationPost 通知の対象の投稿のレスポンスを作成する（投稿のない通知や取得できない投稿の場合はfalseを返す）
+func (h *NotificationHandler) notificationPost(
+	notificationType models.NotificationType,
+	postID *uuid.UUID,
+	posts map[uuid.UUID]*models.Post,
+	viewer *models.User,
+) (gin.H, bool) {
+	switch notificationType {
+	case models.NotificationTypeLike, models.NotificationTypeReply, models.NotificationTypeRepost, models.NotificationTypeSavedSearch:
+	case models.NotificationTypePostRemoved:
+		// 削除された投稿は取得できないため、どの投稿かだけを示す
+		if postID == nil {
+			return nil, false
+		}
+		return unavailablePostPlaceholder(*postID), true
+	default:
+		return nil, false
+	}
+	if postID == nil {
+		return nil, false
+	}
+
+	post, ok := posts[*postID]
+	if !ok {
+		return nil, false
+	}
+	if !h.contentPolicy.CanView(viewer, post) {
+		return restrictedPostPreview(post), true
+	}
+	return gin.H{
+		"id":         post.ID,
+		"content":    post.Content,
+		"created_at": post.CreatedAt,
+	}, true
+}
+
+// notificationActor 通知のアクション実行者のレスポンスを作成する
+func notificationActor(actor *models.User) gin.H {
+	return gin.H{
+		"id":           actor.ID,
+		"username":     actor.Username,
+		"display_name": actor.Name,
+		"avatar_url":   actor.ProfileImage,
+		"is_supporter": actor.IsSupporter(),
+	}
+}
+
 // GetUnreadCount 未読通知の数を取得する
+// @Summary 未読通知の数を取得する
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/notifications/unread [get]
 func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
 	// 現在のユーザーIDを取得
 	currentUserIDStr, exists := c.Get("userID")
@@ -174,6 +365,17 @@ func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
 }
 
 // MarkAsRead 通知を既読にする
+// @Summary 通知を既読にする
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/notifications/read [put]
 func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 	// 現在のユーザーIDを取得
 	currentUserIDStr, exists := c.Get("userID")
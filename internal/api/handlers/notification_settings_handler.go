@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NotificationSettingsHandler 通知の種類ごとの受け取り方の設定を管理するハンドラーを管理する構造体
+type NotificationSettingsHandler struct {
+	settingsRepo   interfaces.NotificationSettingsRepository
+	emailAvailable bool
+	log            logger.Logger
+}
+
+// NewNotificationSettingsHandler 新しい通知設定ハンドラーを作成する
+// emailAvailableはこのインスタンスでメール通知を送信するか（設定は送信しない場合も保存できる）
+func NewNotificationSettingsHandler(
+	settingsRepo interfaces.NotificationSettingsRepository,
+	emailAvailable bool,
+	log logger.Logger,
+) *NotificationSettingsHandler {
+	return &NotificationSettingsHandler{
+		settingsRepo:   settingsRepo,
+		emailAvailable: emailAvailable,
+		log:            log,
+	}
+}
+
+// UpdateNotificationSettingsRequest 通知設定の更新リクエストの構造体（指定した種類・項目のみ変更する）
+type UpdateNotificationSettingsRequest struct {
+	// 通知の種類（like・repost・follow・reply・mention）ごとの通知の有効・無効（無効にした種類の通知は作成しない）
+	Enabled map[string]bool `json:"enabled"`
+	// 通知の種類（follow・mention）ごとのメール通知の有効・無効
+	Email map[string]bool `json:"email"`
+}
+
+// GetNotificationSettings 自分の通知設定を取得するハンドラー
+// @Summary 自分の通知設定を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/notification-settings [get]
+func (h *NotificationSettingsHandler) GetNotificationSettings(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsRepo.Get(c, currentUserID)
+	if err != nil {
+		h.log.Error("通知設定の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通知設定の取得中にエラーが発生しました")
+		return
+	}
+
+	h.respondSettings(c, settings)
+}
+
+// UpdateNotificationSettings 通知の種類ごとの通知とメール通知の有効・無効を変更するハンドラー
+// @Summary 通知の種類ごとの通知とメール通知の有効・無効を変更する
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateNotificationSettingsRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/notification-settings [put]
+func (h *NotificationSettingsHandler) UpdateNotificationSettings(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	if len(req.Enabled) == 0 && len(req.Email) == 0 {
+		response.BadRequest(c, "enabledまたはemailを指定してください", nil)
+		return
+	}
+
+	enabled := make(map[models.NotificationType]bool, len(req.Enabled))
+	for notificationType, value := range req.Enabled {
+		if !models.IsMutableNotificationType(models.NotificationType(notificationType)) {
+			response.BadRequest(c, "無効にできない種類が指定されています", gin.H{
+				"type":          notificationType,
+				"mutable_types": models.MutableNotificationTypes,
+			})
+			return
+		}
+		enabled[models.NotificationType(notificationType)] = value
+	}
+
+	email := make(map[models.NotificationType]bool, len(req.Email))
+	for notificationType, value := range req.Email {
+		if !models.IsEmailNotificationType(models.NotificationType(notificationType)) {
+			response.BadRequest(c, "メールで通知できない種類が指定されています", gin.H{
+				"type":        notificationType,
+				"email_types": models.EmailNotificationTypes,
+			})
+			return
+		}
+		email[models.NotificationType(notificationType)] = value
+	}
+
+	settings, err := h.settingsRepo.Update(c, currentUserID, enabled, email)
+	if err != nil {
+		h.log.Error("通知設定の保存中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通知設定の保存中にエラーが発生しました")
+		return
+	}
+
+	h.respondSettings(c, settings)
+}
+
+// respondSettings 通知設定と、このインスタンスでメール通知を送信するかを返す
+func (h *NotificationSettingsHandler) respondSettings(c *gin.Context, settings *models.NotificationSettings) {
+	response.Success(c, gin.H{
+		"settings":        settings,
+		"email_available": h.emailAvailable,
+	})
+}
+
+// currentUserID 認証済みユーザーのIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *NotificationSettingsHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
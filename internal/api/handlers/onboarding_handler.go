@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OnboardingHandler 新しいユーザーのオンボーディングのハンドラーを管理する構造体
+type OnboardingHandler struct {
+	onboarding *service.OnboardingService
+	log        logger.Logger
+}
+
+// NewOnboardingHandler 新しいオンボーディングハンドラーを作成する
+func NewOnboardingHandler(onboarding *service.OnboardingService, log logger.Logger) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboarding: onboarding,
+		log:        log,
+	}
+}
+
+// GetProgress オンボーディングの進捗取得ハンドラー
+// @Summary オンボーディングの進捗取得
+// @Tags onboarding
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/onboarding [get]
+func (h *OnboardingHandler) GetProgress(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.onboarding.Progress(c, currentUserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "ユーザーが見つかりません")
+			return
+		}
+		h.log.Error("オンボーディングの進捗の取得中にエラーが発生しました", "error", err, "user_id", currentUserID)
+		response.InternalServerError(c, "オンボーディングの進捗の取得中にエラーが発生しました")
+		return
+	}
+
+	steps := make([]gin.H, 0, len(progress.Steps))
+	for _, step := range progress.Steps {
+		steps = append(steps, gin.H{
+			"name":      step.Name,
+			"completed": step.Completed,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"steps":            steps,
+		"completed":        progress.Completed,
+		"following_count":  progress.FollowingCount,
+		"required_follows": progress.RequiredFollows,
+	})
+}
+
+// GetSuggestions おすすめのユーザー取得ハンドラー
+// @Summary おすすめのユーザー取得
+// @Tags onboarding
+// @Produce json
+// @Security BearerAuth
+// @Param limit query integer false "取得する件数"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/onboarding/suggestions [get]
+func (h *OnboardingHandler) GetSuggestions(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit := h.onboarding.SuggestionLimit()
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > h.onboarding.SuggestionLimit() {
+			response.BadRequest(c, "limitは1から"+strconv.Itoa(h.onboarding.SuggestionLimit())+"の整数で指定してください", nil)
+			return
+		}
+		limit = parsed
+	}
+
+	suggestions, err := h.onboarding.Suggestions(c, currentUserID, limit)
+	if err != nil {
+		h.log.Error("おすすめのユーザーの取得中にエラーが発生しました", "error", err, "user_id", currentUserID)
+		response.InternalServerError(c, "おすすめのユーザーの取得中にエラーが発生しました")
+		return
+	}
+
+	users := make([]gin.H, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		user := suggestion.User
+		users = append(users, gin.H{
+			"id":              user.ID,
+			"username":        user.Username,
+			"display_name":    user.Name,
+			"bio":             user.Bio,
+			"avatar_url":      user.ProfileImage,
+			"verified":        user.IsVerified,
+			"is_supporter":    user.IsSupporter(),
+			"followers_count": user.FollowerCount,
+			"reason":          suggestion.Reason,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"users": users,
+	})
+}
+
+// currentUserID 認証ユーザーのIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *OnboardingHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
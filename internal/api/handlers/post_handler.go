@@ -1,7 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
@@ -17,9 +24,27 @@ type PostHandler struct {
 	postRepo            interfaces.PostRepository
 	userRepo            interfaces.UserRepository
 	likeRepo            interfaces.LikeRepository
+	followRepo          interfaces.FollowRepository
+	reactionRepo        interfaces.ReactionRepository
 	notificationRepo    interfaces.NotificationRepository
+	viewRepo            interfaces.PostViewRepository
 	notificationService *service.NotificationService
-	log                 logger.Logger
+	blockService        *service.BlockService
+	contentPolicy       *service.ContentPolicyService
+	replyPolicy         *service.ReplyPolicyService
+	contentFilter       *service.ContentFilterService
+	conversations       *service.ConversationService
+	threadUnroll        *service.ThreadUnrollService
+	timelineUpdates     *service.TimelineUpdateService
+	timelineFanout      *service.TimelineFanoutService
+	viewCounter         *service.ViewCounterService
+	media               *service.MediaService
+	supporters          *service.SupporterService
+	topics              *service.TopicService
+	searchIndex         *service.SearchIndexService
+	// 共有用の投稿URLの基点（アプリケーションのURL）
+	appURL string
+	log    logger.Logger
 }
 
 // NewPostHandler 新しい投稿ハンドラーを作成する
@@ -27,16 +52,50 @@ func NewPostHandler(
 	postRepo interfaces.PostRepository,
 	userRepo interfaces.UserRepository,
 	likeRepo interfaces.LikeRepository,
+	followRepo interfaces.FollowRepository,
+	reactionRepo interfaces.ReactionRepository,
 	notificationRepo interfaces.NotificationRepository,
+	viewRepo interfaces.PostViewRepository,
 	notificationService *service.NotificationService,
+	blockService *service.BlockService,
+	contentPolicy *service.ContentPolicyService,
+	replyPolicy *service.ReplyPolicyService,
+	contentFilter *service.ContentFilterService,
+	conversations *service.ConversationService,
+	threadUnroll *service.ThreadUnrollService,
+	timelineUpdates *service.TimelineUpdateService,
+	timelineFanout *service.TimelineFanoutService,
+	viewCounter *service.ViewCounterService,
+	media *service.MediaService,
+	supporters *service.SupporterService,
+	topics *service.TopicService,
+	searchIndex *service.SearchIndexService,
+	appURL string,
 	log logger.Logger,
 ) *PostHandler {
 	return &PostHandler{
 		postRepo:            postRepo,
 		userRepo:            userRepo,
 		likeRepo:            likeRepo,
+		followRepo:          followRepo,
+		reactionRepo:        reactionRepo,
 		notificationRepo:    notificationRepo,
+		viewRepo:            viewRepo,
 		notificationService: notificationService,
+		blockService:        blockService,
+		contentPolicy:       contentPolicy,
+		replyPolicy:         replyPolicy,
+		contentFilter:       contentFilter,
+		conversations:       conversations,
+		threadUnroll:        threadUnroll,
+		timelineUpdates:     timelineUpdates,
+		timelineFanout:      timelineFanout,
+		viewCounter:         viewCounter,
+		media:               media,
+		supporters:          supporters,
+		topics:              topics,
+		searchIndex:         searchIndex,
+		appURL:              strings.TrimRight(appURL, "/"),
 		log:                 log,
 	}
 }
@@ -46,9 +105,37 @@ type CreatePostRequest struct {
 	Content   string   `json:"content" binding:"required,max=280"`
 	MediaURLs []string `json:"media_urls" binding:"omitempty,dive,url"`
 	ReplyToID *string  `json:"reply_to_id" binding:"omitempty,uuid"`
+	// コンテンツレーティング（省略時は general）
+	ContentRating string `json:"content_rating" binding:"omitempty,oneof=general sensitive adult"`
+	// 返信できるユーザーの範囲（省略時は everyone）
+	ReplyPolicy string `json:"reply_policy" binding:"omitempty,oneof=everyone followers following"`
+	// 外部への共有を許可するか（省略時は許可する）
+	SharingEnabled *bool `json:"sharing_enabled"`
+	// 添付メディアの代替テキスト（media_urls と同じ順序）
+	MediaAltTexts []string `json:"media_alt_texts" binding:"omitempty,dive,max=1500"`
+	// 投稿の言語（BCP 47 形式、例: ja, en-US）
+	Language string `json:"language" binding:"omitempty,max=35"`
+	// 投稿が消える日時（5分後〜90日後、省略時は消えない）
+	ExpiresAt *time.Time `json:"expires_at"`
+	// 投稿を分類するトピックのスラッグ（最大3件、ハッシュタグから分類されるトピックとは別に指定する）
+	Topics []string `json:"topics" binding:"omitempty,max=3,dive,max=50"`
 }
 
 // CreatePost 投稿作成ハンドラー
+// @Summary 投稿作成
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreatePostRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts [post]
 func (h *PostHandler) CreatePost(c *gin.Context) {
 	var req CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -80,48 +167,258 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 			return
 		}
 
-		// 返信先の投稿が存在するか確認
-		replyToPost, err := h.postRepo.GetByID(c, replyToID)
-		if err != nil {
-			h.log.Error("返信先投稿の取得中にエラーが発生しました", "error", err)
-			response.NotFound(c, "返信先の投稿が見つかりません")
+		// 返信先の投稿を取得し、返信できるか確認
+		if !h.checkReplyTarget(c, currentUserID, replyToID) {
 			return
 		}
 
 		post = models.NewReply(currentUserID, replyToID, req.Content, req.MediaURLs)
-
-		// 返信先の返信数をインクリメント
-		if err := h.postRepo.IncrementReplyCount(c, replyToID); err != nil {
-			h.log.Error("返信カウント更新中にエラーが発生しました", "error", err)
-			// 処理は続行
-		}
-
-		// 通知の作成（自分自身の投稿への返信でない場合）
-		if currentUserID != replyToPost.UserID {
-			// TODO: 通知を作成
-			notification := models.NewNotification(
-				replyToPost.UserID,
-				currentUserID,
-				models.NotificationTypeReply,
-				&post.ID,
-			)
-			if err := h.notificationRepo.Create(c, notification); err != nil {
-				h.log.Error("通知の作成中にエラーが発生しました", "error", err)
-				// 処理は続行
-			}
-		}
 	} else {
 		// 通常の投稿
 		post = models.NewPost(currentUserID, req.Content, req.MediaURLs)
 	}
 
-	// 投稿の保存
-	if err := h.postRepo.Create(c, post); err != nil {
+	if req.ContentRating != "" {
+		post.ContentRating = models.ContentRating(req.ContentRating)
+	}
+	if req.ReplyPolicy != "" {
+		post.ReplyPolicy = models.ReplyPolicy(req.ReplyPolicy)
+	}
+	if req.SharingEnabled != nil {
+		post.SharingEnabled = *req.SharingEnabled
+	}
+	if !checkMediaSet(c, post.MediaURLs) || !h.checkMediaOwner(c, currentUserID, post.MediaURLs) {
+		return
+	}
+	if !applyAccessibility(c, post, req.MediaAltTexts, req.Language) {
+		return
+	}
+	if !applyExpiration(c, post, req.ExpiresAt) {
+		return
+	}
+
+	// 指定されたトピックが存在するか確認
+	topicSlugs, err := h.topics.ResolveSlugs(c.Request.Context(), req.Topics)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownTopic) {
+			response.BadRequest(c, "存在しないトピックが指定されています", nil)
+			return
+		}
+		h.log.Error("トピックの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の作成中にエラーが発生しました")
+		return
+	}
+
+	// 禁止語ルールで審査する（拒否の場合は作成せず、ラベルの場合はより厳しいレーティングを付ける）
+	screen, ok := screenContent(c, h.contentFilter, h.log, post.Content)
+	if !ok {
+		return
+	}
+	post.ContentRating = post.ContentRating.Max(screen.Rating)
+
+	// 投稿の保存（返信の場合は返信数の更新と会話の参加者への通知も同じトランザクションで行う）
+	if post.ReplyToID != nil {
+		err = h.conversations.CreateReply(c.Request.Context(), post)
+	} else {
+		err = h.postRepo.Create(c, post)
+	}
+	if err != nil {
 		h.log.Error("投稿の作成中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "投稿の作成中にエラーが発生しました")
 		return
 	}
 
+	// 審査で通報キューに載せるルールに一致した場合はモデレーターの確認を待つ
+	flagContent(c, h.contentFilter, h.log, models.ReportTargetPost, post.ID, currentUserID, screen)
+
+	// メンションされたユーザーへの通知
+	h.notifyMentions(c, post)
+
+	// 指定されたトピックとハッシュタグから探索タブのトピックに分類する
+	topics := h.topics.TagPost(c.Request.Context(), post, topicSlugs)
+
+	// 返信によって返信先のスレッドが続く場合があるため、スレッドのキャッシュを削除する
+	if post.ReplyToID != nil {
+		h.threadUnroll.Invalidate(c.Request.Context(), *post.ReplyToID)
+	}
+
+	// フォロワーのタイムラインに新着投稿があることを知らせる
+	go h.timelineUpdates.PublishNewPost(context.Background(), post)
+	h.timelineFanout.Enqueue(post)
+	h.searchIndex.IndexPost(post)
+
+	// ユーザー情報を取得
+	user, err := h.userRepo.GetByID(c, currentUserID)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		// 投稿は作成されたのでエラーがあっても処理は続行
+	}
+
+	postResponse := newPostResponse(post, user, h.media.PostAttachments(c, post))
+	postResponse["topics"] = topics
+	h.addShareMeta(postResponse, post)
+	response.Created(c, postResponse)
+}
+
+// ThreadPostRequest スレッド内の各投稿の構造体
+type ThreadPostRequest struct {
+	Content   string   `json:"content" binding:"required,max=280"`
+	MediaURLs []string `json:"media_urls" binding:"omitempty,dive,url"`
+	// コンテンツレーティング（省略時は general）
+	ContentRating string `json:"content_rating" binding:"omitempty,oneof=general sensitive adult"`
+	// 添付メディアの代替テキスト（media_urls と同じ順序）
+	MediaAltTexts []string `json:"media_alt_texts" binding:"omitempty,dive,max=1500"`
+}
+
+// CreateThreadRequest スレッド作成リクエストの構造体
+type CreateThreadRequest struct {
+	// スレッドの投稿（先頭から順に返信でつながる、2〜25件）
+	Posts []ThreadPostRequest `json:"posts" binding:"required,min=2,max=25,dive"`
+	// スレッドの先頭の投稿の返信先（省略時は新しい会話を開始する）
+	ReplyToID *string `json:"reply_to_id" binding:"omitempty,uuid"`
+	// スレッドのすべての投稿に適用する返信設定（省略時は everyone）
+	ReplyPolicy string `json:"reply_policy" binding:"omitempty,oneof=everyone followers following"`
+	// スレッドのすべての投稿に適用する共有設定（省略時は許可する）
+	SharingEnabled *bool `json:"sharing_enabled"`
+	// スレッドのすべての投稿に適用する言語（BCP 47 形式、例: ja, en-US）
+	Language string `json:"language" binding:"omitempty,max=35"`
+	// スレッドのすべての投稿が消える日時（5分後〜90日後、省略時は消えない）
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateThread スレッド作成ハンドラー
+// 返信でつながった複数の投稿を、返信数の更新と会話の参加者への通知も含めて1つのトランザクションで作成する（すべて作成されるか、何も作成されない）
+// @Summary スレッド作成
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateThreadRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/thread [post]
+// @Router /api/v1/threads [post]
+func (h *PostHandler) CreateThread(c *gin.Context) {
+	var req CreateThreadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 返信先がある場合は返信できるか確認
+	var replyToID *uuid.UUID
+	if req.ReplyToID != nil {
+		id, err := uuid.Parse(*req.ReplyToID)
+		if err != nil {
+			response.BadRequest(c, "無効な返信先IDです", nil)
+			return
+		}
+		if !h.checkReplyTarget(c, currentUserID, id) {
+			return
+		}
+		replyToID = &id
+	}
+
+	// 各投稿を直前の投稿への返信としてつなげる
+	// 同じ時刻にならないよう作成時刻を少しずつずらし、スレッドの順序を保つ
+	now := time.Now()
+	posts := make([]*models.Post, 0, len(req.Posts))
+	screens := make([]*service.ContentScreenResult, 0, len(req.Posts))
+	for i, item := range req.Posts {
+		var post *models.Post
+		if replyToID != nil {
+			post = models.NewReply(currentUserID, *replyToID, item.Content, item.MediaURLs)
+		} else {
+			post = models.NewPost(currentUserID, item.Content, item.MediaURLs)
+		}
+
+		post.CreatedAt = now.Add(time.Duration(i) * time.Millisecond)
+		post.UpdatedAt = post.CreatedAt
+		if item.ContentRating != "" {
+			post.ContentRating = models.ContentRating(item.ContentRating)
+		}
+		if req.ReplyPolicy != "" {
+			post.ReplyPolicy = models.ReplyPolicy(req.ReplyPolicy)
+		}
+		if req.SharingEnabled != nil {
+			post.SharingEnabled = *req.SharingEnabled
+		}
+		if !checkMediaSet(c, post.MediaURLs) || !h.checkMediaOwner(c, currentUserID, post.MediaURLs) {
+			return
+		}
+		if !applyAccessibility(c, post, item.MediaAltTexts, req.Language) {
+			return
+		}
+		if !applyExpiration(c, post, req.ExpiresAt) {
+			return
+		}
+
+		// 禁止語ルールで投稿ごとに審査する（1件でも拒否された場合はスレッド全体を作成しない）
+		screen, ok := screenContent(c, h.contentFilter, h.log, post.Content)
+		if !ok {
+			return
+		}
+		post.ContentRating = post.ContentRating.Max(screen.Rating)
+
+		posts = append(posts, post)
+		screens = append(screens, screen)
+		replyToID = &post.ID
+	}
+
+	// スレッドの保存（他のユーザーの会話への返信の場合は会話の参加者への通知も同じトランザクションで行う）
+	if err := h.conversations.CreateThread(c.Request.Context(), posts); err != nil {
+		if err.Error() == "post not found" {
+			response.NotFound(c, "返信先の投稿が見つかりません")
+			return
+		}
+		h.log.Error("スレッドの作成中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "スレッドの作成中にエラーが発生しました")
+		return
+	}
+
+	// スレッドの投稿はハッシュタグからのみトピックに分類する
+	topics := make(map[uuid.UUID][]*models.Topic, len(posts))
+	for i, post := range posts {
+		flagContent(c, h.contentFilter, h.log, models.ReportTargetPost, post.ID, currentUserID, screens[i])
+		h.notifyMentions(c, post)
+		topics[post.ID] = h.topics.TagPost(c.Request.Context(), post, nil)
+	}
+
+	// フォロワーのタイムラインに新着投稿があることを知らせる（スレッドにつき1回）
+	first := posts[0]
+	if first.ReplyToID != nil {
+		h.threadUnroll.Invalidate(c.Request.Context(), *first.ReplyToID)
+	}
+	go h.timelineUpdates.PublishNewPost(context.Background(), first)
+	// 返信やハッシュタグのストリームにはスレッドのすべての投稿を配信する
+	for _, post := range posts[1:] {
+		go h.timelineUpdates.PublishToStreams(post)
+	}
+	// ホームタイムラインにはスレッドのすべての投稿が並ぶため、古い順に配信する
+	for _, post := range posts {
+		h.timelineFanout.Enqueue(post)
+		h.searchIndex.IndexPost(post)
+	}
+
 	// ユーザー情報を取得
 	user, err := h.userRepo.GetByID(c, currentUserID)
 	if err != nil {
@@ -130,16 +427,326 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 	}
 
 	// レスポンスを作成
+	media := h.media.Attachments(c, posts)
+	postResponses := make([]gin.H, 0, len(posts))
+	for i, post := range posts {
+		postResponse := newPostResponse(post, user, media[post.ID])
+		postResponse["topics"] = topics[post.ID]
+		h.addShareMeta(postResponse, post)
+		// 最後の投稿以外は次の投稿が返信としてつながっている
+		if i < len(posts)-1 {
+			postResponse["replies_count"] = 1
+		}
+		postResponses = append(postResponses, postResponse)
+	}
+
+	response.Created(c, gin.H{
+		"posts": postResponses,
+	})
+}
+
+// checkReplyTarget currentUserIDのユーザーが返信先の投稿に返信できるかを確認する
+// 返信できない場合はエラーレスポンスを送信してfalseを返す
+func (h *PostHandler) checkReplyTarget(c *gin.Context, currentUserID, replyToID uuid.UUID) bool {
+	// 返信先の投稿が存在するか確認
+	replyToPost, err := h.postRepo.GetByID(c, replyToID)
+	if err != nil {
+		h.log.Error("返信先投稿の取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "返信先の投稿が見つかりません")
+		return false
+	}
+
+	// ブロック関係にある場合は返信できない
+	if err := h.blockService.CheckInteraction(c, currentUserID, replyToPost.UserID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.Forbidden(c, "この投稿に返信することはできません")
+			return false
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の作成中にエラーが発生しました")
+		return false
+	}
+
+	// 返信先の返信設定を確認
+	if err := h.replyPolicy.CheckReply(c, currentUserID, replyToPost); err != nil {
+		if errors.Is(err, service.ErrReplyRestricted) {
+			respondReplyRestricted(c, replyToPost)
+			return false
+		}
+		if errors.Is(err, service.ErrRepliesLocked) {
+			response.Forbidden(c, "この投稿への返信はロックされています")
+			return false
+		}
+		h.log.Error("返信設定の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の作成中にエラーが発生しました")
+		return false
+	}
+
+	return true
+}
+
+// postMediaExtensions 投稿に添付するためにアップロードできるファイルの拡張子
+// 動画は再生時間を検証できるMP4・MOV形式のみ
+var postMediaExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+	".gif":  true,
+	".mp4":  true,
+	".m4v":  true,
+	".mov":  true,
+}
+
+// UploadMedia 投稿に添付する画像・動画をアップロードするハンドラー
+// 返されたURLを投稿作成時のmedia_urlsに指定する。動画は再生時間の上限を検証し、クライアントがプレイヤーを表示できるよう種類を返す
+// @Summary 投稿に添付する画像・動画をアップロードする
+// @Tags posts
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "アップロードするファイル"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/media [post]
+func (h *PostHandler) UploadMedia(c *gin.Context) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "ファイルのアップロードに失敗しました: "+err.Error(), nil)
+		return
+	}
+	defer file.Close()
+
+	// ファイル形式とファイルサイズを検証（サポーターは上限が高くなる）
+	quota := mediaQuotaFor(c, h.userRepo, h.supporters, h.log, currentUserID)
+	mediaType, ok := checkPostMediaFile(c, header.Filename, header.Size, quota)
+	if !ok {
+		return
+	}
+
+	// メディアの使用量に加える（上限を超える場合は保存しない）
+	if !reserveMediaStorage(c, h.media, h.log, currentUserID, header.Size) {
+		return
+	}
+
+	// ファイルを保存（同じ内容のファイルが既にある場合はそれを再利用する）
+	var fileURL string
+	if mediaType == models.MediaTypeVideo {
+		fileURL, err = h.media.StoreVideo(c.Request.Context(), currentUserID, header.Filename, file, quota.VideoMaxDuration)
+	} else {
+		fileURL, err = h.media.Store(c.Request.Context(), currentUserID, header.Filename, file)
+	}
+	if err != nil {
+		h.media.RefundStorage(c.Request.Context(), currentUserID, header.Size)
+		respondMediaStoreError(c, h.log, err, quota)
+		return
+	}
+
+	response.Created(c, h.media.Attachment(c.Request.Context(), fileURL))
+}
+
+// mediaQuotaFor ユーザーのメディアアップロードの上限を返す
+// ユーザー情報を取得できない場合は通常の上限を使用する
+func mediaQuotaFor(
+	c *gin.Context,
+	userRepo interfaces.UserRepository,
+	supporters *service.SupporterService,
+	log logger.Logger,
+	userID uuid.UUID,
+) service.MediaQuota {
+	user, err := userRepo.GetByID(c, userID)
+	if err != nil {
+		log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		user = nil
+	}
+	return supporters.MediaQuota(user)
+}
+
+// checkPostMediaFile 投稿に添付するファイルの形式と大きさを検証し、メディアの種類を返す
+// 不正な場合はエラーレスポンスを送信してfalseを返す
+func checkPostMediaFile(c *gin.Context, filename string, size int64, quota service.MediaQuota) (models.MediaType, bool) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !postMediaExtensions[ext] {
+		response.BadRequest(c, "サポートされていないファイル形式です。JPG、PNG、WebP、GIF、MP4、MOV形式のみ許可されています", nil)
+		return "", false
+	}
+	mediaType := models.MediaTypeFromExtension(ext)
+
+	maxBytes := quota.PostImageMaxBytes
+	if mediaType == models.MediaTypeVideo {
+		maxBytes = quota.VideoMaxBytes
+	}
+	if size > maxBytes {
+		response.BadRequest(c, fmt.Sprintf("ファイルサイズが大きすぎます。%dMB以下のファイルをアップロードしてください", maxBytes/(1024*1024)), nil)
+		return "", false
+	}
+
+	return mediaType, true
+}
+
+// respondMediaStoreError 投稿に添付するメディアの保存に失敗した場合のエラーレスポンスを送信する
+func respondMediaStoreError(c *gin.Context, log logger.Logger, err error, quota service.MediaQuota) {
+	switch {
+	case errors.Is(err, service.ErrInvalidVideo):
+		response.BadRequest(c, "動画を読み込めませんでした。MP4またはMOV形式の動画をアップロードしてください", nil)
+	case errors.Is(err, service.ErrVideoTooLong):
+		response.BadRequest(c, fmt.Sprintf("動画が長すぎます。%d秒以内の動画をアップロードしてください", int(quota.VideoMaxDuration.Seconds())), nil)
+	default:
+		log.Error("メディアの保存に失敗しました", "error", err)
+		response.InternalServerError(c, "ファイルの保存に失敗しました")
+	}
+}
+
+// reserveMediaStorage アップロードするファイルをユーザーのメディアの使用量に加える
+// 上限を超える場合やエラーの場合はエラーレスポンスを送信してfalseを返す
+func reserveMediaStorage(c *gin.Context, media *service.MediaService, log logger.Logger, userID uuid.UUID, size int64) bool {
+	err := media.ReserveStorage(c.Request.Context(), userID, size)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, service.ErrStorageQuotaExceeded) {
+		respondStorageQuotaExceeded(c, media, log, userID, size)
+		return false
+	}
+	log.Error("メディアの使用量の更新に失敗しました", "error", err)
+	response.InternalServerError(c, "ファイルの保存に失敗しました")
+	return false
+}
+
+// respondStorageQuotaExceeded メディアの使用量が上限を超える場合のエラーレスポンス（413）を現在の使用量とともに送信する
+func respondStorageQuotaExceeded(c *gin.Context, media *service.MediaService, log logger.Logger, userID uuid.UUID, size int64) {
+	details := gin.H{"file_bytes": size}
+	usage, err := media.StorageUsage(c.Request.Context(), userID)
+	if err != nil {
+		log.Warn("メディアの使用量の取得に失敗しました", "error", err)
+	} else {
+		details["used_bytes"] = usage.UsedBytes
+		details["quota_bytes"] = usage.QuotaBytes
+		details["remaining_bytes"] = usage.RemainingBytes()
+	}
+
+	response.JSON(c, http.StatusRequestEntityTooLarge, response.NewErrorResponse(
+		"STORAGE_QUOTA_EXCEEDED", "メディアの保存容量の上限を超えています。不要なメディアを削除してから再度お試しください", details,
+	))
+}
+
+// checkMediaSet 1件の投稿に添付できるメディアか検証する（最大4件、動画は1件のみで他のメディアと一緒に添付できない）
+// 不正な場合はエラーレスポンスを送信してfalseを返す
+func checkMediaSet(c *gin.Context, mediaURLs []string) bool {
+	if !models.IsValidMediaSet(mediaURLs) {
+		response.BadRequest(c, fmt.Sprintf("添付できるメディアは%d件までです。動画は1件のみ、他のメディアと一緒に添付することはできません", models.MaxMediaPerPost), nil)
+		return false
+	}
+	return true
+}
+
+// checkMediaOwner 添付するメディアがユーザー自身のアップロードしたものか検証する（他のユーザーのメディアやアバターは添付できない）
+// 不正な場合はエラーレスポンスを送信してfalseを返す
+func (h *PostHandler) checkMediaOwner(c *gin.Context, userID uuid.UUID, mediaURLs []string) bool {
+	if len(mediaURLs) == 0 {
+		return true
+	}
+
+	uploaded, err := h.media.UploadedBy(c.Request.Context(), userID, mediaURLs)
+	if err != nil {
+		h.log.Error("添付メディアのアップロードしたユーザーの確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の作成中にエラーが発生しました")
+		return false
+	}
+	if !uploaded {
+		response.Forbidden(c, "自分でアップロードしていないメディアは添付できません")
+		return false
+	}
+	return true
+}
+
+// applyAccessibility 添付メディアの代替テキストと投稿の言語を検証して投稿に設定する
+// 不正な場合はエラーレスポンスを送信してfalseを返す
+func applyAccessibility(c *gin.Context, post *models.Post, altTexts []string, language string) bool {
+	if len(altTexts) > len(post.MediaURLs) {
+		response.BadRequest(c, "代替テキストの数が添付メディアの数を超えています", nil)
+		return false
+	}
+	post.SetMediaAltTexts(altTexts)
+
+	if language != "" {
+		normalized, ok := models.NormalizeLanguage(language)
+		if !ok {
+			response.BadRequest(c, "無効な言語コードです", nil)
+			return false
+		}
+		post.Language = normalized
+	}
+
+	return true
+}
+
+// applyExpiration 投稿が消える日時を検証して投稿に設定する
+// 不正な場合はエラーレスポンスを送信してfalseを返す
+func applyExpiration(c *gin.Context, post *models.Post, expiresAt *time.Time) bool {
+	if expiresAt == nil {
+		return true
+	}
+
+	lifetime := expiresAt.Sub(post.CreatedAt)
+	if lifetime < models.MinPostLifetime || lifetime > models.MaxPostLifetime {
+		response.BadRequest(c, "投稿が消える日時は5分後から90日後までの間で指定してください", nil)
+		return false
+	}
+
+	utc := expiresAt.UTC()
+	post.ExpiresAt = &utc
+	return true
+}
+
+// notifyMentions 投稿でメンションされたユーザーに通知する（通知作成のエラーはレスポンスには影響させない）
+func (h *PostHandler) notifyMentions(c *gin.Context, post *models.Post) {
+	if h.notificationService == nil {
+		return
+	}
+	if err := h.notificationService.CreateMentionNotifications(c.Request.Context(), post); err != nil {
+		h.log.Error("メンション通知の作成中にエラーが発生しました", "error", err)
+	}
+}
+
+// newPostResponse 作成直後の投稿のレスポンスを作成する
+func newPostResponse(post *models.Post, user *models.User, media []models.MediaAttachment) gin.H {
 	postResponse := gin.H{
-		"id":            post.ID,
-		"user_id":       post.UserID,
-		"content":       post.Content,
-		"media_urls":    post.MediaURLs,
-		"reply_to_id":   post.ReplyToID,
-		"created_at":    post.CreatedAt,
-		"likes_count":   0,
-		"replies_count": 0,
-		"reposts_count": 0,
+		"id":              post.ID,
+		"user_id":         post.UserID,
+		"content":         post.Content,
+		"media_urls":      post.MediaURLs,
+		"media":           media,
+		"reply_to_id":     post.ReplyToID,
+		"content_rating":  post.ContentRating,
+		"content_warning": post.ContentWarning,
+		"expires_at":      post.ExpiresAt,
+		"expiring":        post.IsExpiring(),
+		"reply_policy":    post.ReplyPolicy,
+		"language":        post.Language,
+		"created_at":      post.CreatedAt,
+		"likes_count":     0,
+		"views_count":     0,
+		"shares_count":    0,
+		"replies_count":   0,
+		"reposts_count":   0,
 	}
 
 	// ユーザー情報があれば追加
@@ -149,13 +756,26 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 			"username":     user.Username,
 			"display_name": user.Name,
 			"avatar_url":   user.ProfileImage,
+			"is_supporter": user.IsSupporter(),
 		}
 	}
 
-	response.Created(c, postResponse)
+	return postResponse
 }
 
 // GetPost 投稿取得ハンドラー
+// @Summary 投稿取得
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id} [get]
 func (h *PostHandler) GetPost(c *gin.Context) {
 	// 投稿IDの取得とバリデーション
 	idParam := c.Param("id")
@@ -178,6 +798,38 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		return
 	}
 
+	// ブロック関係にある場合は投稿を表示しない
+	var viewerID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		viewerID, _ = uuid.Parse(currentUserIDStr.(string))
+		if err := h.blockService.CheckInteraction(c, viewerID, post.UserID); err != nil {
+			if errors.Is(err, service.ErrBlocked) {
+				response.NotFound(c, "投稿が見つかりません")
+				return
+			}
+			h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "投稿の取得中にエラーが発生しました")
+			return
+		}
+	}
+
+	// 年齢制限の確認
+	viewer, err := h.contentPolicy.LoadViewer(c, viewerID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の取得中にエラーが発生しました")
+		return
+	}
+	if !h.contentPolicy.CanView(viewer, post) {
+		respondAgeRestricted(c, post)
+		return
+	}
+
+	// 投稿者本人以外の閲覧を記録する
+	if viewerID != post.UserID {
+		h.viewCounter.RecordView(post.ID)
+	}
+
 	// 投稿ユーザーの情報を取得
 	user, err := h.userRepo.GetByID(c, post.UserID)
 	if err != nil {
@@ -205,17 +857,38 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 
 	// レスポンスを作成
 	postResponse := gin.H{
-		"id":            post.ID,
-		"user_id":       post.UserID,
-		"content":       post.Content,
-		"media_urls":    post.MediaURLs,
-		"reply_to_id":   post.ReplyToID,
-		"created_at":    post.CreatedAt,
-		"likes_count":   post.LikeCount,
-		"replies_count": post.ReplyCount,
-		"reposts_count": post.RepostCount,
-		"is_liked":      isLiked,
-		"is_reposted":   isReposted,
+		"id":              post.ID,
+		"user_id":         post.UserID,
+		"content":         post.Content,
+		"media_urls":      post.MediaURLs,
+		"media":           h.media.PostAttachments(c, post),
+		"reply_to_id":     post.ReplyToID,
+		"content_rating":  post.ContentRating,
+		"content_warning": post.ContentWarning,
+		"expires_at":      post.ExpiresAt,
+		"expiring":        post.IsExpiring(),
+		"reply_policy":    post.ReplyPolicy,
+		"replies_locked":  post.RepliesLocked,
+		"can_reply":       h.replyPolicy.CanReply(c, viewerID, post),
+		"language":        post.Language,
+		"created_at":      post.CreatedAt,
+		"likes_count":     post.LikeCount,
+		"views_count":     post.ViewCount,
+		"shares_count":    post.ShareCount,
+		"replies_count":   post.ReplyCount,
+		"reposts_count":   post.RepostCount,
+		"is_liked":        isLiked,
+		"is_reposted":     isReposted,
+	}
+	h.addShareMeta(postResponse, post)
+
+	// リアクションの集計と閲覧者のリアクション
+	if reactions, myReactions, err := h.reactionState(c, viewerID, post.ID); err != nil {
+		h.log.Error("リアクションの取得中にエラーが発生しました", "error", err)
+		// 処理は続行
+	} else {
+		postResponse["reactions"] = reactions
+		postResponse["my_reactions"] = myReactions
 	}
 
 	// ユーザー情報があれば追加
@@ -225,24 +898,32 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 			"username":     user.Username,
 			"display_name": user.Name,
 			"avatar_url":   user.ProfileImage,
+			"is_supporter": user.IsSupporter(),
 		}
 	}
 
-	// 返信の場合は返信先の情報も追加
+	// 返信の場合は返信先の情報も追加（返信先が削除またはモデレーターにより非表示にされている場合は表示できないことを示す）
 	if post.IsReply && post.ReplyToID != nil {
-		replyToPost, err := h.postRepo.GetByID(c, *post.ReplyToID)
-		if err == nil {
+		replyToPost, err := h.postRepo.GetByIDIncludingDeleted(c, *post.ReplyToID)
+		if err == nil && replyToPost.IsUnavailable() {
+			postResponse["reply_to"] = unavailablePostPlaceholder(replyToPost.ID)
+		} else if err == nil && !h.contentPolicy.CanView(viewer, replyToPost) {
+			postResponse["reply_to"] = restrictedPostPreview(replyToPost)
+		} else if err == nil {
 			replyToUser, err := h.userRepo.GetByID(c, replyToPost.UserID)
 			if err == nil {
 				postResponse["reply_to"] = gin.H{
-					"id":         replyToPost.ID,
-					"user_id":    replyToPost.UserID,
-					"content":    replyToPost.Content,
-					"created_at": replyToPost.CreatedAt,
+					"id":              replyToPost.ID,
+					"user_id":         replyToPost.UserID,
+					"content":         replyToPost.Content,
+					"content_rating":  replyToPost.ContentRating,
+					"content_warning": replyToPost.ContentWarning,
+					"created_at":      replyToPost.CreatedAt,
 					"user": gin.H{
 						"username":     replyToUser.Username,
 						"display_name": replyToUser.Name,
 						"avatar_url":   replyToUser.ProfileImage,
+						"is_supporter": replyToUser.IsSupporter(),
 					},
 				}
 			}
@@ -253,6 +934,18 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 }
 
 // DeletePost 投稿削除ハンドラー
+// @Summary 投稿削除
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id} [delete]
 func (h *PostHandler) DeletePost(c *gin.Context) {
 	// 投稿IDの取得とバリデーション
 	idParam := c.Param("id")
@@ -310,21 +1003,261 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		}
 	}
 
+	// 投稿を含むスレッドの構造が変わるため、スレッドのキャッシュを削除する
+	h.threadUnroll.Invalidate(c.Request.Context(), postID)
+	h.searchIndex.RemovePost(postID)
+
 	response.NoContent(c)
 }
 
-// GetPostReplies 投稿への返信一覧取得ハンドラー
-func (h *PostHandler) GetPostReplies(c *gin.Context) {
+// UpdatePostRequest 投稿の部分更新リクエストの構造体
+type UpdatePostRequest struct {
+	// 投稿から取り除く添付メディアのURL
+	RemoveMediaURLs []string `json:"remove_media_urls" binding:"required,min=1,max=4,dive,url"`
+}
+
+// UpdatePost 投稿の部分更新ハンドラー（投稿者のみ）
+// 投稿を削除せずに添付メディアを取り除き、他の投稿・編集履歴・プロフィール画像から使われていないファイルはストレージからも削除する
+// @Summary 投稿の部分更新（投稿者のみ）
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdatePostRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id} [patch]
+func (h *PostHandler) UpdatePost(c *gin.Context) {
 	// 投稿IDの取得とバリデーション
-	idParam := c.Param("id")
-	if idParam == "" {
-		response.BadRequest(c, "投稿IDが必要です", nil)
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
 		return
 	}
 
-	postID, err := uuid.Parse(idParam)
-	if err != nil {
-		response.BadRequest(c, "無効な投稿IDです", nil)
+	var req UpdatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿の取得
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// 投稿のオーナーかどうか確認
+	if post.UserID != currentUserID {
+		response.Forbidden(c, "この操作を行う権限がありません")
+		return
+	}
+
+	// 投稿者がアップロードしたファイルへの参照を外す（外部のURLはそのまま。他の投稿・編集履歴・プロフィール画像から使われているファイルは残る）
+	deleteObjects := func(ctx context.Context) error {
+		for _, mediaURL := range req.RemoveMediaURLs {
+			if err := h.media.ReleaseFor(ctx, currentUserID, mediaURL, post.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	updated, err := h.postRepo.RemoveMedia(c, post.ID, currentUserID, req.RemoveMediaURLs, deleteObjects)
+	if err != nil {
+		if err.Error() == "media not found" {
+			response.BadRequest(c, "指定されたメディアはこの投稿に添付されていません", nil)
+			return
+		}
+		if err.Error() == "post not found" {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("メディアの削除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"id":         updated.ID,
+		"content":    updated.Content,
+		"media_urls": updated.MediaURLs,
+		"media":      h.media.PostAttachments(c, updated),
+		"updated_at": updated.UpdatedAt,
+	})
+}
+
+// GetPostEdits 投稿の編集履歴取得ハンドラー（投稿者のみ）
+// @Summary 投稿の編集履歴取得（投稿者のみ）
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/edits [get]
+func (h *PostHandler) GetPostEdits(c *gin.Context) {
+	// 投稿IDの取得とバリデーション
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿の取得
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// 取り除いたメディアを含むため、編集履歴は投稿者本人のみ閲覧できる
+	if post.UserID != currentUserID {
+		response.Forbidden(c, "この投稿の編集履歴を閲覧する権限がありません")
+		return
+	}
+
+	edits, err := h.postRepo.GetEdits(c, post.ID)
+	if err != nil {
+		h.log.Error("編集履歴の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "編集履歴の取得中にエラーが発生しました")
+		return
+	}
+	if edits == nil {
+		edits = []*models.PostEdit{}
+	}
+
+	response.Success(c, gin.H{
+		"edits": edits,
+	})
+}
+
+// GetPostAccessibility 投稿のアクセシビリティ情報取得ハンドラー
+// 添付メディアの種類と代替テキストの有無、投稿の言語を返す（投稿を閲覧できる場合のみ）
+// @Summary 投稿のアクセシビリティ情報取得
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/accessibility [get]
+func (h *PostHandler) GetPostAccessibility(c *gin.Context) {
+	// 投稿IDの取得とバリデーション
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	// 投稿の取得
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// ブロック関係にある場合は投稿を表示しない
+	var viewerID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		viewerID, _ = uuid.Parse(currentUserIDStr.(string))
+		if err := h.blockService.CheckInteraction(c, viewerID, post.UserID); err != nil {
+			if errors.Is(err, service.ErrBlocked) {
+				response.NotFound(c, "投稿が見つかりません")
+				return
+			}
+			h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "投稿の取得中にエラーが発生しました")
+			return
+		}
+	}
+
+	// 年齢制限の確認
+	viewer, err := h.contentPolicy.LoadViewer(c, viewerID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の取得中にエラーが発生しました")
+		return
+	}
+	if !h.contentPolicy.CanView(viewer, post) {
+		respondAgeRestricted(c, post)
+		return
+	}
+
+	response.Success(c, post.Accessibility())
+}
+
+// GetPostReplies 投稿への返信一覧取得ハンドラー
+// @Summary 投稿への返信一覧取得
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Param sort query string false "並び順（newest: 新しい順、oldest: 古い順、top: 反応が多い順）" Enums(newest, oldest, top) default(newest)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/replies [get]
+func (h *PostHandler) GetPostReplies(c *gin.Context) {
+	// 投稿IDの取得とバリデーション
+	idParam := c.Param("id")
+	if idParam == "" {
+		response.BadRequest(c, "投稿IDが必要です", nil)
+		return
+	}
+
+	postID, err := uuid.Parse(idParam)
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
 		return
 	}
 
@@ -341,8 +1274,15 @@ func (h *PostHandler) GetPostReplies(c *gin.Context) {
 
 	offset := (page - 1) * perPage
 
-	// 投稿が存在するか確認
-	_, err = h.postRepo.GetByID(c, postID)
+	// 並び順の取得
+	sort := models.ReplySort(c.DefaultQuery("sort", string(models.ReplySortNewest)))
+	if !sort.IsValid() {
+		response.BadRequest(c, "sortはnewest・oldest・topのいずれかで指定してください", nil)
+		return
+	}
+
+	// 投稿が存在するか確認（削除された投稿への返信も会話として表示する）
+	post, err := h.postRepo.GetByIDIncludingDeleted(c, postID)
 	if err != nil {
 		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
 		response.NotFound(c, "投稿が見つかりません")
@@ -350,7 +1290,7 @@ func (h *PostHandler) GetPostReplies(c *gin.Context) {
 	}
 
 	// 返信の取得
-	replies, err := h.postRepo.GetReplies(c, postID, offset, perPage)
+	replies, err := h.postRepo.GetReplies(c, postID, sort, offset, perPage)
 	if err != nil {
 		h.log.Error("返信取得中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "返信の取得中にエラーが発生しました")
@@ -371,37 +1311,69 @@ func (h *PostHandler) GetPostReplies(c *gin.Context) {
 		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
 	}
 
+	// ブロック関係にあるユーザーの返信は表示しない
+	replies, err = h.blockService.FilterPosts(c.Request.Context(), currentUserID, replies)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "返信の取得中にエラーが発生しました")
+		return
+	}
+
+	// 年齢制限のある返信を除外
+	viewer, err := h.contentPolicy.LoadViewer(c.Request.Context(), currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "返信の取得中にエラーが発生しました")
+		return
+	}
+	replies, hiddenCount := h.contentPolicy.FilterPosts(viewer, replies)
+
+	// 返信者・いいね状態をまとめて取得
+	hydrated, err := hydratePosts(c.Request.Context(), h.userRepo, h.postRepo, h.likeRepo, h.reactionRepo, h.media, currentUserID, replies)
+	if err != nil {
+		h.log.Error("返信の関連データ取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "返信の取得中にエラーが発生しました")
+		return
+	}
+
 	// 返信のレスポンスを作成
 	repliesResponse := make([]gin.H, 0, len(replies))
 	for _, reply := range replies {
 		// ユーザー情報を取得
-		user, err := h.userRepo.GetByID(c, reply.UserID)
-		if err != nil {
-			h.log.Error("ユーザー取得中にエラーが発生しました", "error", err, "userID", reply.UserID)
+		user, ok := hydrated.users[reply.UserID]
+		if !ok {
+			h.log.Error("返信ユーザーが見つかりません", "userID", reply.UserID)
 			continue // このユーザーの情報は取得できないのでスキップ
 		}
 
 		// いいね状態の確認
-		isLiked := false
-		if currentUserID != uuid.Nil {
-			isLiked, _ = h.likeRepo.HasLiked(c, currentUserID, reply.ID)
-		}
+		isLiked := hydrated.liked[reply.ID]
 
 		repliesResponse = append(repliesResponse, gin.H{
-			"id":            reply.ID,
-			"user_id":       reply.UserID,
-			"content":       reply.Content,
-			"media_urls":    reply.MediaURLs,
-			"reply_to_id":   reply.ReplyToID,
-			"created_at":    reply.CreatedAt,
-			"likes_count":   reply.LikeCount,
-			"replies_count": reply.ReplyCount,
-			"is_liked":      isLiked,
+			"id":              reply.ID,
+			"user_id":         reply.UserID,
+			"content":         reply.Content,
+			"media_urls":      reply.MediaURLs,
+			"media":           hydrated.mediaAttachments(reply.ID),
+			"reply_to_id":     reply.ReplyToID,
+			"content_rating":  reply.ContentRating,
+			"content_warning": reply.ContentWarning,
+			"expires_at":      reply.ExpiresAt,
+			"expiring":        reply.IsExpiring(),
+			"created_at":      reply.CreatedAt,
+			"likes_count":     reply.LikeCount,
+			"views_count":     reply.ViewCount,
+			"shares_count":    reply.ShareCount,
+			"replies_count":   reply.ReplyCount,
+			"is_liked":        isLiked,
+			"reactions":       hydrated.reactionCounts(reply.ID),
+			"my_reactions":    hydrated.viewerReactions(reply.ID),
 			"user": gin.H{
 				"id":           user.ID,
 				"username":     user.Username,
 				"display_name": user.Name,
 				"avatar_url":   user.ProfileImage,
+				"is_supporter": user.IsSupporter(),
 			},
 		})
 	}
@@ -412,18 +1384,151 @@ func (h *PostHandler) GetPostReplies(c *gin.Context) {
 		totalPages++
 	}
 
-	response.Success(c, gin.H{
-		"replies": repliesResponse,
+	result := gin.H{
+		"replies":        repliesResponse,
+		"sort":           sort,
+		"content_filter": contentFilterMeta(hiddenCount),
 		"pagination": gin.H{
 			"total":       totalReplies,
 			"page":        page,
 			"per_page":    perPage,
 			"total_pages": totalPages,
 		},
+	}
+	if post.IsUnavailable() {
+		result["post"] = unavailablePostPlaceholder(post.ID)
+	}
+
+	response.Success(c, result)
+}
+
+// UnrollThread スレッドの展開ハンドラー（リーダーモード）
+// 投稿者が自分の投稿に返信を続けたスレッドを、先頭から順に1つの文書としてまとめて返す
+// @Summary スレッドの展開（リーダーモード）
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/unroll [get]
+func (h *PostHandler) UnrollThread(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	posts, err := h.threadUnroll.Unroll(c.Request.Context(), postID)
+	if err != nil {
+		if err.Error() == "post not found" {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("スレッドの展開中にエラーが発生しました", "error", err, "post_id", postID)
+		response.InternalServerError(c, "スレッドの取得中にエラーが発生しました")
+		return
+	}
+	root := posts[0]
+
+	// ブロック関係にある場合はスレッドを表示しない
+	var viewerID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		viewerID, _ = uuid.Parse(currentUserIDStr.(string))
+		if err := h.blockService.CheckInteraction(c, viewerID, root.UserID); err != nil {
+			if errors.Is(err, service.ErrBlocked) {
+				response.NotFound(c, "投稿が見つかりません")
+				return
+			}
+			h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "スレッドの取得中にエラーが発生しました")
+			return
+		}
+	}
+
+	// 年齢制限のある投稿は文書から除外する
+	viewer, err := h.contentPolicy.LoadViewer(c, viewerID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "スレッドの取得中にエラーが発生しました")
+		return
+	}
+	visible, hiddenCount := h.contentPolicy.FilterPosts(viewer, posts)
+	if len(visible) == 0 {
+		respondAgeRestricted(c, root)
+		return
+	}
+
+	author, err := h.userRepo.GetByID(c, root.UserID)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// 各投稿の本文を段落としてつなげ、メディアも順にまとめる
+	contents := make([]string, 0, len(visible))
+	mediaURLs := []string{}
+	media := []models.MediaAttachment{}
+	attachments := h.media.Attachments(c, visible)
+	postResponses := make([]gin.H, 0, len(visible))
+	for _, post := range visible {
+		contents = append(contents, post.Content)
+		mediaURLs = append(mediaURLs, post.MediaURLs...)
+		media = append(media, attachments[post.ID]...)
+		postResponses = append(postResponses, gin.H{
+			"id":              post.ID,
+			"content":         post.Content,
+			"media_urls":      post.MediaURLs,
+			"media":           attachments[post.ID],
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"created_at":      post.CreatedAt,
+			"likes_count":     post.LikeCount,
+			"replies_count":   post.ReplyCount,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"root_id":     root.ID,
+		"reply_to_id": root.ReplyToID,
+		"user": gin.H{
+			"id":           author.ID,
+			"username":     author.Username,
+			"display_name": author.Name,
+			"avatar_url":   author.ProfileImage,
+			"is_supporter": author.IsSupporter(),
+		},
+		"content":        strings.Join(contents, "\n\n"),
+		"media_urls":     mediaURLs,
+		"media":          media,
+		"posts":          postResponses,
+		"posts_count":    len(postResponses),
+		"created_at":     root.CreatedAt,
+		"updated_at":     visible[len(visible)-1].CreatedAt,
+		"content_filter": contentFilterMeta(hiddenCount),
 	})
 }
 
 // LikePost 投稿にいいねをするハンドラー
+// @Summary 投稿にいいねをする
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/like [post]
 func (h *PostHandler) LikePost(c *gin.Context) {
 	// 投稿IDのパラメータ取得
 	postIDStr := c.Param("id")
@@ -450,6 +1555,17 @@ func (h *PostHandler) LikePost(c *gin.Context) {
 		return
 	}
 
+	// ブロック関係にある場合はいいねできない
+	if err := h.blockService.CheckInteraction(c.Request.Context(), currentUserID, post.UserID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.Forbidden(c, "この投稿にいいねすることはできません")
+			return
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいね処理中にエラーが発生しました")
+		return
+	}
+
 	// 既にいいね済みかのチェック
 	hasLiked, err := h.likeRepo.HasLiked(c.Request.Context(), currentUserID, postID)
 	if err != nil {
@@ -493,6 +1609,17 @@ func (h *PostHandler) LikePost(c *gin.Context) {
 }
 
 // UnlikePost 投稿へのいいね解除ハンドラー
+// @Summary 投稿へのいいね解除
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/like [delete]
 func (h *PostHandler) UnlikePost(c *gin.Context) {
 	// 投稿IDの取得とバリデーション
 	idParam := c.Param("id")
@@ -550,22 +1677,936 @@ func (h *PostHandler) UnlikePost(c *gin.Context) {
 		return
 	}
 
-	// いいね数を減らす
-	if err := h.postRepo.DecrementLikeCount(c, postID); err != nil {
-		h.log.Error("いいねカウント更新中にエラーが発生しました", "error", err)
-		// 処理は続行
-	}
-
-	// いいね数を確認（0未満にならないように）
-	likeCount := post.LikeCount - 1
-	if likeCount < 0 {
-		likeCount = 0
+	// いいね解除と同時に更新されたいいね数を取得し直す
+	if updated, err := h.postRepo.GetByID(c, postID); err == nil {
+		post = updated
 	}
 
 	response.Success(c, gin.H{
 		"liked":       false,
-		"likes_count": likeCount,
+		"likes_count": post.LikeCount,
 	})
 }
 
-// TODO: RepostPost と CancelRepost の実装
+// GetPostLikes 投稿にいいねしたユーザーの一覧を取得するハンドラー
+// @Summary 投稿にいいねしたユーザーの一覧を取得
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投稿ID"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/likes [get]
+func (h *PostHandler) GetPostLikes(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	// ページネーションパラメータの取得
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	offset := (page - 1) * perPage
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿を閲覧できる場合のみ一覧を返す
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	if err := h.blockService.CheckInteraction(c, currentUserID, post.UserID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	viewer, err := h.contentPolicy.LoadViewer(c, currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+	if !h.contentPolicy.CanView(viewer, post) {
+		respondAgeRestricted(c, post)
+		return
+	}
+
+	likes, err := h.likeRepo.GetLikesByPostID(c.Request.Context(), postID, offset, perPage)
+	if err != nil {
+		h.log.Error("いいね一覧の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	totalLikes, err := h.likeRepo.CountLikesByPostID(c.Request.Context(), postID)
+	if err != nil {
+		h.log.Error("いいね数の取得中にエラーが発生しました", "error", err)
+		// エラーがあっても処理は続行
+		totalLikes = int64(post.LikeCount)
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(likes))
+	for _, like := range likes {
+		userIDs = append(userIDs, like.UserID)
+	}
+
+	// ブロック関係にあるユーザーは一覧に含めない
+	userIDs, err = h.blockService.FilterUserIDs(c.Request.Context(), currentUserID, userIDs)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	// ユーザー情報とフォロー状態をまとめて取得
+	users, err := h.userRepo.GetByIDs(c.Request.Context(), userIDs)
+	if err != nil {
+		h.log.Error("ユーザー情報取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	following, err := h.followRepo.IsFollowingBatch(c.Request.Context(), currentUserID, userIDs)
+	if err != nil {
+		h.log.Error("フォロー状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	likedAt := make(map[uuid.UUID]time.Time, len(likes))
+	for _, like := range likes {
+		likedAt[like.UserID] = like.CreatedAt
+	}
+
+	usersResponse := make([]gin.H, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, ok := users[userID]
+		if !ok {
+			continue
+		}
+
+		usersResponse = append(usersResponse, gin.H{
+			"id":           user.ID,
+			"username":     user.Username,
+			"display_name": user.Name,
+			"avatar_url":   user.ProfileImage,
+			"is_supporter": user.IsSupporter(),
+			"bio":          user.Bio,
+			"is_following": following[user.ID],
+			"liked_at":     likedAt[user.ID],
+		})
+	}
+
+	totalPages := int(totalLikes) / perPage
+	if int(totalLikes)%perPage > 0 {
+		totalPages++
+	}
+
+	response.Success(c, gin.H{
+		"users": usersResponse,
+		"pagination": gin.H{
+			"total":       totalLikes,
+			"page":        page,
+			"per_page":    perPage,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+// GetPostReposters 投稿をリポストしたユーザーの一覧を取得するハンドラー
+// リポストした投稿ではなくユーザーを新しい順に返す（同じユーザーの複数のリポストは1件にまとめる）
+// @Summary 投稿をリポストしたユーザーの一覧を取得
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投稿ID"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/reposts [get]
+func (h *PostHandler) GetPostReposters(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	// ページネーションパラメータの取得
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	offset := (page - 1) * perPage
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿を閲覧できる場合のみ一覧を返す
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	if err := h.blockService.CheckInteraction(c, currentUserID, post.UserID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リポストの取得中にエラーが発生しました")
+		return
+	}
+
+	viewer, err := h.contentPolicy.LoadViewer(c, currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リポストの取得中にエラーが発生しました")
+		return
+	}
+	if !h.contentPolicy.CanView(viewer, post) {
+		respondAgeRestricted(c, post)
+		return
+	}
+
+	// 同じユーザーの複数のリポストはリポジトリで1件にまとめる
+	userIDs, repostedAt, err := h.postRepo.GetReposterIDs(c.Request.Context(), postID, offset, perPage)
+	if err != nil {
+		h.log.Error("リポスト一覧の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リポストの取得中にエラーが発生しました")
+		return
+	}
+
+	totalReposters, err := h.postRepo.CountReposters(c.Request.Context(), postID)
+	if err != nil {
+		h.log.Error("リポストしたユーザー数の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リポストの取得中にエラーが発生しました")
+		return
+	}
+
+	// ブロック関係にあるユーザーは一覧に含めない
+	userIDs, err = h.blockService.FilterUserIDs(c.Request.Context(), currentUserID, userIDs)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リポストの取得中にエラーが発生しました")
+		return
+	}
+
+	// ユーザー情報とフォロー状態をまとめて取得
+	users, err := h.userRepo.GetByIDs(c.Request.Context(), userIDs)
+	if err != nil {
+		h.log.Error("ユーザー情報取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リポストの取得中にエラーが発生しました")
+		return
+	}
+
+	following, err := h.followRepo.IsFollowingBatch(c.Request.Context(), currentUserID, userIDs)
+	if err != nil {
+		h.log.Error("フォロー状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リポストの取得中にエラーが発生しました")
+		return
+	}
+
+	usersResponse := make([]gin.H, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, ok := users[userID]
+		if !ok {
+			continue
+		}
+
+		usersResponse = append(usersResponse, gin.H{
+			"id":           user.ID,
+			"username":     user.Username,
+			"display_name": user.Name,
+			"avatar_url":   user.ProfileImage,
+			"is_supporter": user.IsSupporter(),
+			"bio":          user.Bio,
+			"is_following": following[user.ID],
+			"reposted_at":  repostedAt[user.ID],
+		})
+	}
+
+	totalPages := int(totalReposters) / perPage
+	if int(totalReposters)%perPage > 0 {
+		totalPages++
+	}
+
+	response.Success(c, gin.H{
+		"users": usersResponse,
+		"pagination": gin.H{
+			"total":       totalReposters,
+			"page":        page,
+			"per_page":    perPage,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+// ReactionRequest 投稿へのリアクションのリクエスト
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// ReactToPost 投稿に絵文字でリアクションをするハンドラー
+// @Summary 投稿に絵文字でリアクションをする
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body ReactionRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/reactions [post]
+func (h *PostHandler) ReactToPost(c *gin.Context) {
+	var req ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "無効なリクエストです", err.Error())
+		return
+	}
+	h.setReaction(c, req.Emoji, true)
+}
+
+// RemoveReaction 投稿へのリアクションを取り消すハンドラー
+// @Summary 投稿へのリアクションを取り消す
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param emoji path string true "絵文字"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/reactions/{emoji} [delete]
+func (h *PostHandler) RemoveReaction(c *gin.Context) {
+	h.setReaction(c, c.Param("emoji"), false)
+}
+
+// setReaction 投稿へのリアクションを追加・取り消し、更新後のリアクションの集計を返す
+func (h *PostHandler) setReaction(c *gin.Context, emoji string, reacted bool) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	if !models.IsAllowedReaction(emoji) {
+		response.BadRequest(c, "使用できない絵文字です", gin.H{"allowed": models.AllowedReactions})
+		return
+	}
+
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// ブロック関係にある場合はリアクションできない
+	if reacted {
+		if err := h.blockService.CheckInteraction(c, currentUserID, post.UserID); err != nil {
+			if errors.Is(err, service.ErrBlocked) {
+				response.Forbidden(c, "この投稿にリアクションすることはできません")
+				return
+			}
+			h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "リアクション処理中にエラーが発生しました")
+			return
+		}
+
+		if err := h.reactionRepo.React(c, models.NewReaction(currentUserID, postID, emoji)); err != nil {
+			if err.Error() == "reaction already exists" {
+				response.BadRequest(c, "既にこの絵文字でリアクションしています", nil)
+				return
+			}
+			h.log.Error("リアクション作成中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "リアクション処理中にエラーが発生しました")
+			return
+		}
+	} else {
+		if err := h.reactionRepo.Unreact(c, currentUserID, postID, emoji); err != nil {
+			if err.Error() == "reaction not found" {
+				response.BadRequest(c, "この絵文字でリアクションしていません", nil)
+				return
+			}
+			h.log.Error("リアクション削除中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "リアクション解除処理中にエラーが発生しました")
+			return
+		}
+	}
+
+	reactions, myReactions, err := h.reactionState(c, currentUserID, postID)
+	if err != nil {
+		h.log.Error("リアクションの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "リアクションの取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"reactions":    reactions,
+		"my_reactions": myReactions,
+	})
+}
+
+// reactionState 投稿の絵文字ごとのリアクション数と、閲覧者がリアクションした絵文字を取得する
+func (h *PostHandler) reactionState(ctx context.Context, viewerID, postID uuid.UUID) ([]*models.ReactionCount, []string, error) {
+	summaries, err := h.reactionRepo.GetSummaries(ctx, []uuid.UUID{postID})
+	if err != nil {
+		return nil, nil, err
+	}
+	reactions := summaries[postID]
+	if reactions == nil {
+		reactions = []*models.ReactionCount{}
+	}
+
+	myReactions := []string{}
+	if viewerID != uuid.Nil {
+		reacted, err := h.reactionRepo.GetUserReactions(ctx, viewerID, []uuid.UUID{postID})
+		if err != nil {
+			return nil, nil, err
+		}
+		if emoji, ok := reacted[postID]; ok {
+			myReactions = emoji
+		}
+	}
+
+	return reactions, myReactions, nil
+}
+
+// MuteConversation 投稿が属する会話のミュートハンドラー
+// @Summary 投稿が属する会話のミュート
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/mute [post]
+func (h *PostHandler) MuteConversation(c *gin.Context) {
+	h.setConversationMuted(c, true)
+}
+
+// UnmuteConversation 投稿が属する会話のミュート解除ハンドラー
+// @Summary 投稿が属する会話のミュート解除
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/mute [delete]
+func (h *PostHandler) UnmuteConversation(c *gin.Context) {
+	h.setConversationMuted(c, false)
+}
+
+// setConversationMuted 会話のミュート状態を変更する
+func (h *PostHandler) setConversationMuted(c *gin.Context, muted bool) {
+	// 投稿IDの取得とバリデーション
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿が存在するか確認
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	var rootID uuid.UUID
+	if muted {
+		rootID, err = h.conversations.Mute(c, currentUserID, post)
+		if err != nil && err.Error() == "conversation already muted" {
+			response.BadRequest(c, "既にこの会話をミュートしています", nil)
+			return
+		}
+	} else {
+		rootID, err = h.conversations.Unmute(c, currentUserID, post)
+		if err != nil && err.Error() == "conversation mute not found" {
+			response.BadRequest(c, "この会話はミュートしていません", nil)
+			return
+		}
+	}
+	if err != nil {
+		h.log.Error("会話のミュート状態の変更中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "会話のミュート処理中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"root_post_id": rootID,
+		"muted":        muted,
+	})
+}
+
+// SharePost 外部への共有を記録するハンドラー
+// クライアントがOSの共有シートで投稿を共有したときに呼び出す
+// @Summary 外部への共有を記録する
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/share [post]
+func (h *PostHandler) SharePost(c *gin.Context) {
+	// 投稿IDの取得とバリデーション
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿が存在するか確認
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// ブロック関係にある場合は投稿を表示しない
+	if err := h.blockService.CheckInteraction(c, currentUserID, post.UserID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.NotFound(c, "投稿が見つかりません")
+			return
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "共有の記録中にエラーが発生しました")
+		return
+	}
+
+	if !post.SharingEnabled {
+		response.Forbidden(c, "この投稿は共有が許可されていません")
+		return
+	}
+
+	sharesCount, err := h.postRepo.IncrementShareCount(c, post.ID)
+	if err != nil {
+		if err.Error() == "post not found or sharing disabled" {
+			response.Forbidden(c, "この投稿は共有が許可されていません")
+			return
+		}
+		h.log.Error("共有数の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "共有の記録中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"post_id":      post.ID,
+		"shares_count": sharesCount,
+	})
+}
+
+// UpdatePostSharingRequest 投稿の共有設定の更新リクエストの構造体
+type UpdatePostSharingRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// UpdatePostSharing 投稿の共有を許可するかを切り替えるハンドラー（投稿者のみ）
+// @Summary 投稿の共有を許可するかを切り替える（投稿者のみ）
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdatePostSharingRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/sharing [put]
+func (h *PostHandler) UpdatePostSharing(c *gin.Context) {
+	// 投稿IDの取得とバリデーション
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	var req UpdatePostSharingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿の取得
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// 投稿のオーナーかどうか確認
+	if post.UserID != currentUserID {
+		response.Forbidden(c, "この操作を行う権限がありません")
+		return
+	}
+
+	if err := h.postRepo.SetSharingEnabled(c, post.ID, *req.Enabled); err != nil {
+		h.log.Error("共有設定の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "共有設定の更新中にエラーが発生しました")
+		return
+	}
+	post.SharingEnabled = *req.Enabled
+
+	shareResponse := gin.H{
+		"post_id": post.ID,
+	}
+	h.addShareMeta(shareResponse, post)
+	response.Success(c, shareResponse)
+}
+
+// addShareMeta 投稿の共有設定と、共有が許可されている場合は共有用のURLをレスポンスに追加する
+func (h *PostHandler) addShareMeta(postResponse gin.H, post *models.Post) {
+	postResponse["sharing_enabled"] = post.SharingEnabled
+	if post.SharingEnabled {
+		postResponse["share_url"] = h.appURL + "/posts/" + post.ID.String()
+	}
+}
+
+// GetPostAnalytics 投稿者向けの投稿分析ハンドラー
+// @Summary 投稿者向けの投稿分析
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param days query integer false "集計する日数（1〜90日、デフォルト7日）" default(7)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/analytics [get]
+func (h *PostHandler) GetPostAnalytics(c *gin.Context) {
+	// 投稿IDの取得とバリデーション
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 集計する日数（1〜90日、デフォルト7日）
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 90 {
+		response.BadRequest(c, "日数は1〜90の範囲で指定してください", nil)
+		return
+	}
+
+	// 投稿が存在するか確認
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+
+	// 分析は投稿者本人のみ閲覧できる
+	if post.UserID != currentUserID {
+		response.Forbidden(c, "この投稿の分析を閲覧する権限がありません")
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -(days - 1))
+	recorded, err := h.viewRepo.GetDailyViews(c, post.ID, from, to)
+	if err != nil {
+		h.log.Error("日別閲覧数の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿分析の取得中にエラーが発生しました")
+		return
+	}
+
+	// 閲覧のなかった日も0件として返す
+	viewsByDate := make(map[string]int64, len(recorded))
+	for _, v := range recorded {
+		viewsByDate[v.Date.Format("2006-01-02")] = v.ViewCount
+	}
+	dailyViews := make([]gin.H, 0, days)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		dailyViews = append(dailyViews, gin.H{
+			"date":        date,
+			"views_count": viewsByDate[date],
+		})
+	}
+
+	// まだ書き込まれていない閲覧数も含める
+	viewsCount := post.ViewCount + h.viewCounter.PendingViews(post.ID)
+	engagements := int64(post.LikeCount + post.ReplyCount + post.RepostCount)
+	engagementRate := 0.0
+	if viewsCount > 0 {
+		engagementRate = float64(engagements) / float64(viewsCount)
+	}
+
+	response.Success(c, gin.H{
+		"post_id":         post.ID,
+		"views_count":     viewsCount,
+		"shares_count":    post.ShareCount,
+		"likes_count":     post.LikeCount,
+		"replies_count":   post.ReplyCount,
+		"reposts_count":   post.RepostCount,
+		"engagements":     engagements,
+		"engagement_rate": engagementRate,
+		"daily_views":     dailyViews,
+	})
+}
+
+// TODO: RepostPost と CancelRepost の実装
+
+// 年齢制限により投稿を表示できないことを示すレスポンスを送信する
+func respondAgeRestricted(c *gin.Context, post *models.Post) {
+	response.JSON(c, http.StatusForbidden, response.NewErrorResponse(
+		"AGE_RESTRICTED",
+		"この投稿は年齢制限のため表示できません",
+		gin.H{
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"reason":          service.ContentFilterReasonAgeRestricted,
+		},
+	))
+}
+
+// screenContent テキストを禁止語ルールで審査する
+// 拒否するルールに一致した場合は、一致した語を伏せたエラーレスポンスを送信してfalseを返す
+func screenContent(c *gin.Context, contentFilter *service.ContentFilterService, log logger.Logger, texts ...string) (*service.ContentScreenResult, bool) {
+	if contentFilter == nil {
+		return &service.ContentScreenResult{Rating: models.ContentRatingGeneral}, true
+	}
+
+	result, err := contentFilter.Screen(c.Request.Context(), texts...)
+	if err != nil {
+		log.Error("禁止語ルールによる審査中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "内容の確認中にエラーが発生しました")
+		return nil, false
+	}
+
+	if result.Rejected {
+		// 回避されないよう、どの語に一致したかは返さない
+		response.JSON(c, http.StatusUnprocessableEntity, response.NewErrorResponse(
+			"CONTENT_REJECTED",
+			"利用規約に反する可能性のある内容が含まれているため送信できません",
+			nil,
+		))
+		return nil, false
+	}
+
+	return result, true
+}
+
+// flagContent 審査で通報キューに載せるルールに一致した対象を自動で通報する
+// 通報のエラーは投稿・更新の結果には影響させない
+func flagContent(
+	c *gin.Context,
+	contentFilter *service.ContentFilterService,
+	log logger.Logger,
+	targetType models.ReportTargetType,
+	targetID, targetUserID uuid.UUID,
+	result *service.ContentScreenResult,
+) {
+	if contentFilter == nil || result == nil || !result.Flagged {
+		return
+	}
+
+	if err := contentFilter.Flag(c.Request.Context(), targetType, targetID, targetUserID, result); err != nil {
+		log.Error("禁止語ルールによる自動の通報の作成中にエラーが発生しました", "error", err, "target_type", targetType, "target_id", targetID)
+	}
+}
+
+// 返信設定により返信できない場合のエラーレスポンスを返す
+func respondReplyRestricted(c *gin.Context, parent *models.Post) {
+	message := "この投稿には返信できません"
+	switch parent.ReplyPolicy {
+	case models.ReplyPolicyFollowers:
+		message = "この投稿に返信できるのは投稿者のフォロワーのみです"
+	case models.ReplyPolicyFollowing:
+		message = "この投稿に返信できるのは投稿者がフォローしているユーザーのみです"
+	}
+
+	response.JSON(c, http.StatusForbidden, response.NewErrorResponse(
+		"REPLY_RESTRICTED",
+		message,
+		gin.H{
+			"reply_policy": parent.ReplyPolicy,
+		},
+	))
+}
+
+// 年齢制限により内容を伏せた投稿のプレビューを作成する
+func restrictedPostPreview(post *models.Post) gin.H {
+	return gin.H{
+		"id":              post.ID,
+		"content_rating":  post.ContentRating,
+		"content_warning": post.ContentWarning,
+		"expires_at":      post.ExpiresAt,
+		"expiring":        post.IsExpiring(),
+		"restricted":      true,
+	}
+}
+
+// 削除された投稿の代わりに表示するプレースホルダーを作成する
+func unavailablePostPlaceholder(postID uuid.UUID) gin.H {
+	return gin.H{
+		"id":          postID,
+		"unavailable": true,
+		"message":     "この投稿は表示できません",
+	}
+}
+
+// 年齢制限により一覧から除外した投稿の情報を作成する
+func contentFilterMeta(hiddenCount int) gin.H {
+	return gin.H{
+		"hidden_count": hiddenCount,
+		"reason":       service.ContentFilterReasonAgeRestricted,
+	}
+}
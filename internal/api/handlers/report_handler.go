@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReportHandler 投稿・ユーザーの通報と、モデレーター向けの通報の対応を管理する構造体
+type ReportHandler struct {
+	reportRepo interfaces.ReportRepository
+	postRepo   interfaces.PostRepository
+	userRepo   interfaces.UserRepository
+	auditRepo  interfaces.AuditLogRepository
+	txManager  interfaces.TxManager
+	log        logger.Logger
+}
+
+// NewReportHandler 新しい通報ハンドラーを作成する
+func NewReportHandler(
+	reportRepo interfaces.ReportRepository,
+	postRepo interfaces.PostRepository,
+	userRepo interfaces.UserRepository,
+	auditRepo interfaces.AuditLogRepository,
+	txManager interfaces.TxManager,
+	log logger.Logger,
+) *ReportHandler {
+	return &ReportHandler{
+		reportRepo: reportRepo,
+		postRepo:   postRepo,
+		userRepo:   userRepo,
+		auditRepo:  auditRepo,
+		txManager:  txManager,
+		log:        log,
+	}
+}
+
+// CreateReportRequest 通報リクエストの構造体
+type CreateReportRequest struct {
+	Reason  models.ReportReason `json:"reason" binding:"required"`
+	Comment string              `json:"comment" binding:"max=1000"`
+}
+
+// ResolveReportRequest 通報の対応リクエストの構造体
+type ResolveReportRequest struct {
+	Status models.ReportStatus `json:"status" binding:"required"`
+	Note   string              `json:"note" binding:"max=1000"`
+}
+
+// ReportPost 投稿を通報するハンドラー
+// @Summary 投稿を通報する
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body CreateReportRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/posts/{id}/report [post]
+func (h *ReportHandler) ReportPost(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な投稿IDです", nil)
+		return
+	}
+
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := h.bindReport(c)
+	if !ok {
+		return
+	}
+
+	post, err := h.postRepo.GetByID(c, postID)
+	if err != nil {
+		response.NotFound(c, "投稿が見つかりません")
+		return
+	}
+	if post.UserID == currentUserID {
+		response.BadRequest(c, "自分の投稿は通報できません", nil)
+		return
+	}
+
+	h.createReport(c, models.NewReport(currentUserID, models.ReportTargetPost, post.ID, post.UserID, req.Reason, req.Comment))
+}
+
+// ReportUser ユーザーを通報するハンドラー
+// @Summary ユーザーを通報する
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Param request body CreateReportRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/report [post]
+func (h *ReportHandler) ReportUser(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := h.bindReport(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.GetByUsername(c, c.Param("username"))
+	if err != nil {
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+	if user.ID == currentUserID {
+		response.BadRequest(c, "自分自身は通報できません", nil)
+		return
+	}
+
+	h.createReport(c, models.NewReport(currentUserID, models.ReportTargetUser, user.ID, user.ID, req.Reason, req.Comment))
+}
+
+// ListReports 通報を古い順に一覧するハンドラー（statusで状態を指定、既定は未対応）
+// @Summary 通報を古い順に一覧する（statusで状態を指定、既定は未対応）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/reports [get]
+func (h *ReportHandler) ListReports(c *gin.Context) {
+	status := models.ReportStatus(c.DefaultQuery("status", string(models.ReportStatusOpen)))
+	if !status.IsValid() {
+		response.BadRequest(c, "状態はopen・resolved・dismissedのいずれかを指定してください", nil)
+		return
+	}
+
+	page, perPage, offset := listPagination(c)
+
+	reports, err := h.reportRepo.ListByStatus(c, status, offset, perPage)
+	if err != nil {
+		h.log.Error("通報一覧の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通報一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	total, err := h.reportRepo.CountByStatus(c, status)
+	if err != nil {
+		h.log.Error("通報数の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通報一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	// 通報者と対象のユーザー名を付けて返す
+	userIDs := make([]uuid.UUID, 0, len(reports)*2)
+	for _, report := range reports {
+		if report.ReporterID != nil {
+			userIDs = append(userIDs, *report.ReporterID)
+		}
+		userIDs = append(userIDs, report.TargetUserID)
+	}
+	users, err := h.userRepo.GetByIDs(c, userIDs)
+	if err != nil {
+		h.log.Error("通報に関係するユーザーの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通報一覧の取得中にエラーが発生しました")
+		return
+	}
+
+	reportsResponse := make([]gin.H, 0, len(reports))
+	for _, report := range reports {
+		reportsResponse = append(reportsResponse, reportResponse(report, users))
+	}
+
+	response.Success(c, gin.H{
+		"reports":    reportsResponse,
+		"pagination": paginationMeta(total, page, perPage),
+	})
+}
+
+// ResolveReport 未対応の通報を対応済み（resolved）または却下（dismissed）にするハンドラー
+// 対応は監査ログに記録する
+// @Summary 未対応の通報を対応済み（resolved）または却下（dismissed）にする
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body ResolveReportRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/reports/{id}/resolve [post]
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な通報IDです", nil)
+		return
+	}
+
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	if req.Status != models.ReportStatusResolved && req.Status != models.ReportStatusDismissed {
+		response.BadRequest(c, "状態はresolvedまたはdismissedを指定してください", nil)
+		return
+	}
+
+	var report *models.Report
+	err = h.txManager.WithinTx(c.Request.Context(), func(ctx context.Context) error {
+		if err := h.reportRepo.Resolve(ctx, reportID, currentUserID, req.Status, req.Note); err != nil {
+			return err
+		}
+
+		details := map[string]string{"status": string(req.Status)}
+		entry := models.NewAuditLog(currentUserID, models.AuditReportResolve, models.AuditTargetReport, reportID, details).WithClient(c.ClientIP(), c.Request.UserAgent())
+		if err := h.auditRepo.Create(ctx, entry); err != nil {
+			return err
+		}
+
+		report, err = h.reportRepo.GetByID(ctx, reportID)
+		return err
+	})
+	if err != nil {
+		if err.Error() == "report not found" {
+			response.NotFound(c, "未対応の通報が見つかりません")
+			return
+		}
+		h.log.Error("通報の対応中にエラーが発生しました", "error", err, "report_id", reportID)
+		response.InternalServerError(c, "通報の対応中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, reportResponse(report, nil))
+}
+
+// bindReport 通報リクエストを読み込んで検証する（失敗した場合はエラーレスポンスを送信してfalseを返す）
+func (h *ReportHandler) bindReport(c *gin.Context) (*CreateReportRequest, bool) {
+	var req CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return nil, false
+	}
+	if !req.Reason.IsValid() {
+		response.BadRequest(c, "無効な通報の理由です", gin.H{"reasons": models.ReportReasons})
+		return nil, false
+	}
+	return &req, true
+}
+
+// createReport 通報を保存してレスポンスを返す
+func (h *ReportHandler) createReport(c *gin.Context, report *models.Report) {
+	if err := h.reportRepo.Create(c, report); err != nil {
+		if err.Error() == "report already exists" {
+			response.Conflict(c, "既にこの対象を通報しています", nil)
+			return
+		}
+		h.log.Error("通報の保存中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "通報の保存中にエラーが発生しました")
+		return
+	}
+
+	response.Created(c, gin.H{
+		"id":          report.ID,
+		"target_type": report.TargetType,
+		"target_id":   report.TargetID,
+		"reason":      report.Reason,
+		"status":      report.Status,
+		"created_at":  report.CreatedAt,
+	})
+}
+
+// currentUserID 認証ユーザーのIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *ReportHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
+
+// reportResponse 通報をモデレーター向けのレスポンスに変換する（usersに含まれるユーザーはユーザー名を付ける）
+func reportResponse(report *models.Report, users map[uuid.UUID]*models.User) gin.H {
+	resp := gin.H{
+		"id":              report.ID,
+		"reporter_id":     report.ReporterID,
+		"target_type":     report.TargetType,
+		"target_id":       report.TargetID,
+		"target_user_id":  report.TargetUserID,
+		"reason":          report.Reason,
+		"comment":         report.Comment,
+		"status":          report.Status,
+		"resolved_by":     report.ResolvedBy,
+		"resolution_note": report.ResolutionNote,
+		"resolved_at":     report.ResolvedAt,
+		"automatic":       report.IsAutomatic(),
+		"created_at":      report.CreatedAt,
+	}
+	if report.ReporterID != nil {
+		if reporter, ok := users[*report.ReporterID]; ok {
+			resp["reporter_username"] = reporter.Username
+		}
+	}
+	if target, ok := users[report.TargetUserID]; ok {
+		resp["target_username"] = target.Username
+	}
+	return resp
+}
@@ -0,0 +1,660 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SCIM 2.0（RFC 7643・7644）のスキーマ
+const (
+	scimUserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimErrorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimContentType        = "application/scim+json"
+	scimMaxPageSize        = 100
+)
+
+var (
+	// ユーザー名は登録時と同じく英数字3〜30文字
+	scimUsernamePattern = regexp.MustCompile(`^[A-Za-z0-9]{3,30}$`)
+	// 一覧で対応するフィルター（userName eq "..."）
+	scimUserNameFilter = regexp.MustCompile(`^(?i)userName\s+eq\s+"((?:[^"\\]|\\.)*)"$`)
+	// メールアドレスは登録時のバリデーションと同程度の簡易的な確認のみ行う
+	scimEmailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// SCIMHandler IdPからユーザーを管理するSCIMエンドポイントのハンドラーを管理する構造体
+// SCIMのidはユーザーIDで、activeがfalseのユーザーは停止され、IdPから有効化されるまでログインできない
+type SCIMHandler struct {
+	userRepo       interfaces.UserRepository
+	systemAccounts *service.SystemAccountService
+	onboarding     *service.OnboardingService
+	analytics      *service.AnalyticsService
+	accounts       *service.AccountStatusService
+	hub            *websocket.Hub
+	log            logger.Logger
+}
+
+// NewSCIMHandler 新しいSCIMハンドラーを作成する
+func NewSCIMHandler(
+	userRepo interfaces.UserRepository,
+	systemAccounts *service.SystemAccountService,
+	onboarding *service.OnboardingService,
+	analytics *service.AnalyticsService,
+	accounts *service.AccountStatusService,
+	hub *websocket.Hub,
+	log logger.Logger,
+) *SCIMHandler {
+	return &SCIMHandler{
+		userRepo:       userRepo,
+		systemAccounts: systemAccounts,
+		onboarding:     onboarding,
+		analytics:      analytics,
+		accounts:       accounts,
+		hub:            hub,
+		log:            log,
+	}
+}
+
+// scimName SCIMのユーザーの名前
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// scimEmail SCIMのユーザーのメールアドレス
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// scimUserRequest IdPから送信されるユーザー（作成・置き換え）
+type scimUserRequest struct {
+	UserName    string      `json:"userName"`
+	DisplayName string      `json:"displayName"`
+	Name        *scimName   `json:"name"`
+	Emails      []scimEmail `json:"emails"`
+	Active      *bool       `json:"active"`
+	Password    string      `json:"password"`
+}
+
+// scimPatchRequest IdPから送信される部分更新
+type scimPatchRequest struct {
+	Operations []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	} `json:"Operations"`
+}
+
+// GetServiceProviderConfig 対応しているSCIMの機能を返すハンドラー
+// @Summary 対応しているSCIMの機能を返す
+// @Tags scim
+// @Produce application/scim+json
+// @Security SCIMToken
+// @Success 200 {object} object
+// @Failure 401 {object} object
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (h *SCIMHandler) GetServiceProviderConfig(c *gin.Context) {
+	scimJSON(c, http.StatusOK, gin.H{
+		"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": scimMaxPageSize},
+		"changePassword": gin.H{"supported": false},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "プロビジョニング用のトークンによる認証",
+		}},
+	})
+}
+
+// ListUsers ユーザー一覧取得ハンドラー（userName eq によるフィルターに対応）
+// @Summary ユーザー一覧取得（userName eq によるフィルターに対応）
+// @Tags scim
+// @Produce application/scim+json
+// @Security SCIMToken
+// @Param filter query string false "絞り込み条件（userName eq '...'の形式）"
+// @Param startIndex query integer false "取得を開始する位置（1始まり）" default(1)
+// @Success 200 {object} object
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 500 {object} object
+// @Router /scim/v2/Users [get]
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	username := ""
+	if filter := strings.TrimSpace(c.Query("filter")); filter != "" {
+		match := scimUserNameFilter.FindStringSubmatch(filter)
+		if match == nil {
+			scimError(c, http.StatusBadRequest, "invalidFilter", "対応しているフィルターは userName eq のみです")
+			return
+		}
+		username = strings.ReplaceAll(match[1], `\"`, `"`)
+	}
+
+	// startIndexは1始まり
+	startIndex, _ := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(scimMaxPageSize)))
+	if err != nil || count < 0 {
+		count = scimMaxPageSize
+	}
+	if count > scimMaxPageSize {
+		count = scimMaxPageSize
+	}
+
+	total, err := h.userRepo.CountProvisioned(c, username)
+	if err != nil {
+		h.log.Error("SCIMのユーザー数の取得中にエラーが発生しました", "error", err)
+		scimError(c, http.StatusInternalServerError, "", "ユーザーの取得中にエラーが発生しました")
+		return
+	}
+
+	users := []*models.User{}
+	if count > 0 {
+		users, err = h.userRepo.ListProvisioned(c, username, startIndex-1, count)
+		if err != nil {
+			h.log.Error("SCIMのユーザー一覧の取得中にエラーが発生しました", "error", err)
+			scimError(c, http.StatusInternalServerError, "", "ユーザーの取得中にエラーが発生しました")
+			return
+		}
+	}
+
+	resources := make([]gin.H, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, h.scimUser(c, user))
+	}
+
+	scimJSON(c, http.StatusOK, gin.H{
+		"schemas":      []string{scimListResponseSchema},
+		"totalResults": total,
+		"startIndex":   startIndex,
+		"itemsPerPage": len(resources),
+		"Resources":    resources,
+	})
+}
+
+// GetUser ユーザー取得ハンドラー
+// @Summary ユーザー取得
+// @Tags scim
+// @Produce application/scim+json
+// @Security SCIMToken
+// @Param id path string true "ID"
+// @Success 200 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 500 {object} object
+// @Router /scim/v2/Users/{id} [get]
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	user, ok := h.provisionedUser(c)
+	if !ok {
+		return
+	}
+
+	scimJSON(c, http.StatusOK, h.scimUser(c, user))
+}
+
+// CreateUser ユーザー作成ハンドラー
+// パスワードが送信されない場合はランダムなパスワードを設定する（シングルサインオンでのログインを想定）
+// @Summary ユーザー作成
+// @Tags scim
+// @Accept application/scim+json
+// @Produce application/scim+json
+// @Security SCIMToken
+// @Param request body scimUserRequest true "リクエストの内容"
+// @Success 201 {object} object
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /scim/v2/Users [post]
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var req scimUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, "invalidSyntax", "リクエストの形式が無効です")
+		return
+	}
+
+	email := primaryEmail(req.Emails)
+	name := req.displayName()
+	if !h.validUser(c, req.UserName, email, name) {
+		return
+	}
+
+	password := req.Password
+	if password == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			h.log.Error("SCIMのユーザーのパスワードの生成中にエラーが発生しました", "error", err)
+			scimError(c, http.StatusInternalServerError, "", "ユーザーの作成中にエラーが発生しました")
+			return
+		}
+		password = base64.RawURLEncoding.EncodeToString(secret)
+	} else if len(password) < 6 {
+		scimError(c, http.StatusBadRequest, "invalidValue", "パスワードは6文字以上で指定してください")
+		return
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		h.log.Error("SCIMのユーザーのパスワードのハッシュ化中にエラーが発生しました", "error", err)
+		scimError(c, http.StatusInternalServerError, "", "ユーザーの作成中にエラーが発生しました")
+		return
+	}
+
+	user := models.NewUser(req.UserName, email, string(hashedPassword), name)
+	if err := h.userRepo.Create(c, user); err != nil {
+		if err.Error() == "user with this username or email already exists" {
+			scimError(c, http.StatusConflict, "uniqueness", "このユーザー名またはメールアドレスは既に使用されています")
+			return
+		}
+		h.log.Error("SCIMのユーザーの作成中にエラーが発生しました", "error", err)
+		scimError(c, http.StatusInternalServerError, "", "ユーザーの作成中にエラーが発生しました")
+		return
+	}
+
+	// 無効な状態で作成された場合はすぐに停止する
+	if req.Active != nil && !*req.Active {
+		if !h.setActive(c, user, false) {
+			return
+		}
+	}
+
+	h.onboarding.Welcome(c, user.ID)
+	h.analytics.TrackSignup(user.ID, service.SignupMethodSCIM)
+
+	h.log.Info("SCIMでユーザーを作成しました", "userID", user.ID, "username", user.Username)
+	c.Header("Location", h.userLocation(c, user.ID))
+	scimJSON(c, http.StatusCreated, h.scimUser(c, user))
+}
+
+// ReplaceUser ユーザー置き換えハンドラー（PUT）
+// @Summary ユーザー置き換え（PUT）
+// @Tags scim
+// @Accept application/scim+json
+// @Produce application/scim+json
+// @Security SCIMToken
+// @Param id path string true "ID"
+// @Param request body scimUserRequest true "リクエストの内容"
+// @Success 200 {object} object
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /scim/v2/Users/{id} [put]
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	user, ok := h.provisionedUser(c)
+	if !ok {
+		return
+	}
+
+	var req scimUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, "invalidSyntax", "リクエストの形式が無効です")
+		return
+	}
+
+	email := primaryEmail(req.Emails)
+	if email == "" {
+		email = user.Email
+	}
+	name := req.displayName()
+	if name == "" {
+		name = user.Name
+	}
+
+	active := !user.IsSuspended()
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	h.updateUser(c, user, req.UserName, email, name, active)
+}
+
+// PatchUser ユーザー部分更新ハンドラー（PATCH）
+// userName・displayName・name・emails・activeの変更に対応する
+// @Summary ユーザー部分更新（PATCH）
+// @Tags scim
+// @Accept application/scim+json
+// @Produce application/scim+json
+// @Security SCIMToken
+// @Param id path string true "ID"
+// @Param request body scimPatchRequest true "リクエストの内容"
+// @Success 200 {object} object
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 409 {object} object
+// @Failure 500 {object} object
+// @Router /scim/v2/Users/{id} [patch]
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	user, ok := h.provisionedUser(c)
+	if !ok {
+		return
+	}
+
+	var req scimPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Operations) == 0 {
+		scimError(c, http.StatusBadRequest, "invalidSyntax", "リクエストの形式が無効です")
+		return
+	}
+
+	username, email, name, active := user.Username, user.Email, user.Name, !user.IsSuspended()
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		default:
+			scimError(c, http.StatusBadRequest, "invalidValue", fmt.Sprintf("対応していない操作です: %s", op.Op))
+			return
+		}
+
+		// パスを省略した場合は値が属性のオブジェクトになる
+		values := map[string]json.RawMessage{}
+		if op.Path == "" {
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				scimError(c, http.StatusBadRequest, "invalidValue", "値の形式が無効です")
+				return
+			}
+		} else {
+			values[op.Path] = op.Value
+		}
+
+		for path, value := range values {
+			var err error
+			switch strings.ToLower(path) {
+			case "username":
+				err = json.Unmarshal(value, &username)
+			case "displayname", "name.formatted":
+				err = json.Unmarshal(value, &name)
+			case "name":
+				var n scimName
+				if err = json.Unmarshal(value, &n); err == nil && n.formatted() != "" {
+					name = n.formatted()
+				}
+			case "emails", `emails[type eq "work"].value`, `emails[primary eq true].value`:
+				email, err = patchEmail(value, email)
+			case "active":
+				active, err = patchBool(value)
+			default:
+				// 対応していない属性（IdP固有の拡張など）は無視する
+				continue
+			}
+			if err != nil {
+				scimError(c, http.StatusBadRequest, "invalidValue", fmt.Sprintf("%sの値が無効です", path))
+				return
+			}
+		}
+	}
+
+	h.updateUser(c, user, username, email, name, active)
+}
+
+// DeleteUser ユーザー削除ハンドラー
+// データを残したままアカウントを停止し、IdPから再度有効化できるようにする
+// @Summary ユーザー削除
+// @Tags scim
+// @Produce application/scim+json
+// @Security SCIMToken
+// @Param id path string true "ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 500 {object} object
+// @Router /scim/v2/Users/{id} [delete]
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	user, ok := h.provisionedUser(c)
+	if !ok {
+		return
+	}
+
+	if !user.IsSuspended() && !h.setActive(c, user, false) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// updateUser ユーザー名・メールアドレス・表示名・有効かどうかを更新してユーザーを返す
+func (h *SCIMHandler) updateUser(c *gin.Context, user *models.User, username, email, name string, active bool) {
+	if !h.validUser(c, username, email, name) {
+		return
+	}
+
+	if username != user.Username || email != user.Email || name != user.Name {
+		user.Username = username
+		user.Email = email
+		user.Name = name
+		user.UpdatedAt = time.Now()
+		if err := h.userRepo.Update(c, user); err != nil {
+			if err.Error() == "user with this username or email already exists" {
+				scimError(c, http.StatusConflict, "uniqueness", "このユーザー名またはメールアドレスは既に使用されています")
+				return
+			}
+			h.log.Error("SCIMのユーザーの更新中にエラーが発生しました", "error", err, "userID", user.ID)
+			scimError(c, http.StatusInternalServerError, "", "ユーザーの更新中にエラーが発生しました")
+			return
+		}
+	}
+
+	if active == user.IsSuspended() && !h.setActive(c, user, active) {
+		return
+	}
+
+	scimJSON(c, http.StatusOK, h.scimUser(c, user))
+}
+
+// setActive アカウントを停止または再開する
+// 停止した場合は接続中のWebSocketを切断する
+// 管理者が停止したアカウントはIdPから変更できないため、状態を変えずに成功として扱う
+func (h *SCIMHandler) setActive(c *gin.Context, user *models.User, active bool) bool {
+	if user.IsBanned() {
+		h.log.Info("管理者が停止したアカウントのためSCIMでの状態の変更を無視しました", "userID", user.ID, "active", active)
+		return true
+	}
+
+	var err error
+	if active {
+		err = h.userRepo.Unsuspend(c, user.ID)
+	} else {
+		err = h.userRepo.Suspend(c, user.ID)
+	}
+	if err != nil {
+		h.log.Error("SCIMのユーザーの状態の変更中にエラーが発生しました", "error", err, "userID", user.ID, "active", active)
+		scimError(c, http.StatusInternalServerError, "", "ユーザーの更新中にエラーが発生しました")
+		return false
+	}
+	h.accounts.Forget(user.ID)
+
+	if active {
+		user.Status = models.UserStatusActive
+		user.DeactivatedAt = nil
+		h.log.Info("SCIMでユーザーを有効にしました", "userID", user.ID)
+	} else {
+		user.Status = models.UserStatusSuspended
+		h.hub.DisconnectUser(user.ID)
+		h.log.Info("SCIMでユーザーを停止しました", "userID", user.ID)
+	}
+	return true
+}
+
+// provisionedUser パスのIDのユーザーを取得する（見つからない場合はエラーレスポンスを送信してfalseを返す）
+// システムアカウントと削除手続き中のアカウントはIdPから管理できない
+func (h *SCIMHandler) provisionedUser(c *gin.Context) (*models.User, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		scimError(c, http.StatusNotFound, "", "ユーザーが見つかりません")
+		return nil, false
+	}
+
+	user, err := h.userRepo.GetByIDIncludingInactive(c, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			scimError(c, http.StatusNotFound, "", "ユーザーが見つかりません")
+			return nil, false
+		}
+		h.log.Error("SCIMのユーザーの取得中にエラーが発生しました", "error", err, "userID", userID)
+		scimError(c, http.StatusInternalServerError, "", "ユーザーの取得中にエラーが発生しました")
+		return nil, false
+	}
+	if user.IsSystem || user.IsDeleting() || user.IsMerging() {
+		scimError(c, http.StatusNotFound, "", "ユーザーが見つかりません")
+		return nil, false
+	}
+
+	return user, true
+}
+
+// validUser ユーザー名・メールアドレス・表示名を検証する（無効な場合はエラーレスポンスを送信してfalseを返す）
+func (h *SCIMHandler) validUser(c *gin.Context, username, email, name string) bool {
+	if !scimUsernamePattern.MatchString(username) {
+		scimError(c, http.StatusBadRequest, "invalidValue", "userNameは英数字3〜30文字で指定してください")
+		return false
+	}
+	if h.systemAccounts.IsReserved(username) {
+		scimError(c, http.StatusConflict, "uniqueness", "このユーザー名は使用できません")
+		return false
+	}
+	if !scimEmailPattern.MatchString(email) {
+		scimError(c, http.StatusBadRequest, "invalidValue", "有効なメールアドレスを指定してください")
+		return false
+	}
+	if name == "" || utf8.RuneCountInString(name) > 50 {
+		scimError(c, http.StatusBadRequest, "invalidValue", "displayNameは1〜50文字で指定してください")
+		return false
+	}
+	return true
+}
+
+// scimUser ユーザーをSCIMの形式に変換する
+func (h *SCIMHandler) scimUser(c *gin.Context, user *models.User) gin.H {
+	return gin.H{
+		"schemas":     []string{scimUserSchema},
+		"id":          user.ID,
+		"userName":    user.Username,
+		"displayName": user.Name,
+		"name":        scimName{Formatted: user.Name},
+		"emails":      []scimEmail{{Value: user.Email, Type: "work", Primary: true}},
+		"active":      !user.IsSuspended(),
+		"meta": gin.H{
+			"resourceType": "User",
+			"created":      user.CreatedAt.UTC().Format(time.RFC3339),
+			"lastModified": user.UpdatedAt.UTC().Format(time.RFC3339),
+			"location":     h.userLocation(c, user.ID),
+		},
+	}
+}
+
+// userLocation ユーザーのSCIMのURLを返す
+func (h *SCIMHandler) userLocation(c *gin.Context, userID uuid.UUID) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/scim/v2/Users/%s", scheme, c.Request.Host, userID)
+}
+
+// displayName 表示名（displayName・name.formatted・名と姓の順に使用する）
+func (r scimUserRequest) displayName() string {
+	if name := strings.TrimSpace(r.DisplayName); name != "" {
+		return name
+	}
+	if r.Name != nil {
+		return r.Name.formatted()
+	}
+	return ""
+}
+
+// formatted 表示用の名前
+func (n scimName) formatted() string {
+	if name := strings.TrimSpace(n.Formatted); name != "" {
+		return name
+	}
+	return strings.TrimSpace(strings.TrimSpace(n.GivenName) + " " + strings.TrimSpace(n.FamilyName))
+}
+
+// primaryEmail 主なメールアドレス（primaryがない場合は最初のメールアドレス）
+func primaryEmail(emails []scimEmail) string {
+	for _, email := range emails {
+		if email.Primary {
+			return strings.TrimSpace(email.Value)
+		}
+	}
+	if len(emails) > 0 {
+		return strings.TrimSpace(emails[0].Value)
+	}
+	return ""
+}
+
+// patchEmail PATCHのメールアドレスの値（文字列またはメールアドレスの配列）
+func patchEmail(value json.RawMessage, current string) (string, error) {
+	var email string
+	if err := json.Unmarshal(value, &email); err == nil {
+		return strings.TrimSpace(email), nil
+	}
+
+	var emails []scimEmail
+	if err := json.Unmarshal(value, &emails); err != nil {
+		return "", err
+	}
+	if email := primaryEmail(emails); email != "" {
+		return email, nil
+	}
+	return current, nil
+}
+
+// patchBool PATCHの真偽値（文字列の"True"・"False"を送信するIdPもある）
+func patchBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+// scimJSON SCIMのContent-TypeでJSONを返す
+func scimJSON(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", scimContentType)
+	c.JSON(status, body)
+}
+
+// scimError SCIMのエラー形式でエラーを返す
+func scimError(c *gin.Context, status int, scimType, detail string) {
+	body := gin.H{
+		"schemas": []string{scimErrorSchema},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	}
+	if scimType != "" {
+		body["scimType"] = scimType
+	}
+	scimJSON(c, status, body)
+}
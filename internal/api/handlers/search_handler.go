@@ -0,0 +1,542 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	repointerfaces "github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SaveSearchRequest 検索保存リクエスト
+type SaveSearchRequest struct {
+	Query  string `json:"query" binding:"required"`
+	Notify bool   `json:"notify"`
+}
+
+// UpdateSavedSearchRequest 保存した検索の更新リクエスト
+type UpdateSavedSearchRequest struct {
+	Notify *bool `json:"notify" binding:"required"`
+}
+
+// SearchHandler 検索関連のハンドラーを管理する構造体
+type SearchHandler struct {
+	search        *service.SearchService
+	searchIndex   *service.SearchIndexService
+	userRepo      repointerfaces.UserRepository
+	postRepo      repointerfaces.PostRepository
+	likeRepo      repointerfaces.LikeRepository
+	reactionRepo  repointerfaces.ReactionRepository
+	blockService  *service.BlockService
+	contentPolicy *service.ContentPolicyService
+	media         *service.MediaService
+	log           logger.Logger
+}
+
+// NewSearchHandler 新しい検索ハンドラーを作成する
+func NewSearchHandler(
+	search *service.SearchService,
+	searchIndex *service.SearchIndexService,
+	userRepo repointerfaces.UserRepository,
+	postRepo repointerfaces.PostRepository,
+	likeRepo repointerfaces.LikeRepository,
+	reactionRepo repointerfaces.ReactionRepository,
+	blockService *service.BlockService,
+	contentPolicy *service.ContentPolicyService,
+	media *service.MediaService,
+	log logger.Logger,
+) *SearchHandler {
+	return &SearchHandler{
+		search:        search,
+		searchIndex:   searchIndex,
+		userRepo:      userRepo,
+		postRepo:      postRepo,
+		likeRepo:      likeRepo,
+		reactionRepo:  reactionRepo,
+		blockService:  blockService,
+		contentPolicy: contentPolicy,
+		media:         media,
+		log:           log,
+	}
+}
+
+// Search 投稿またはユーザーを検索するハンドラー（検索語は履歴に記録する）
+// 検索エンジンを使用する場合は関連度の高い順、データベースで検索する場合は新しい順に並ぶ
+// @Summary 投稿またはユーザーを検索する（検索語は履歴に記録する）
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "検索キーワード"
+// @Param type query string false "検索対象（posts・users）" default(posts)
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	query, err := service.NormalizeQuery(c.Query("q"))
+	if err != nil {
+		response.BadRequest(c, "検索語は1〜100文字で指定してください", nil)
+		return
+	}
+
+	searchType := c.DefaultQuery("type", "posts")
+	if searchType != "posts" && searchType != "users" {
+		response.BadRequest(c, "検索の種類はpostsまたはusersを指定してください", nil)
+		return
+	}
+
+	// 履歴の記録に失敗しても検索結果は返す
+	if err := h.search.RecordSearch(c, currentUserID, query); err != nil {
+		h.log.Error("検索履歴の記録中にエラーが発生しました", "error", err)
+	}
+
+	if searchType == "users" {
+		h.searchUsers(c, currentUserID, query)
+		return
+	}
+	h.searchPosts(c, currentUserID, query)
+}
+
+func (h *SearchHandler) searchPosts(c *gin.Context, currentUserID uuid.UUID, query string) {
+	page, perPage, offset := listPagination(c)
+
+	posts, err := h.searchIndex.SearchPosts(c, query, offset, perPage)
+	if err != nil {
+		h.log.Error("投稿の検索中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索中にエラーが発生しました")
+		return
+	}
+	hasMore := len(posts) == perPage
+
+	// ブロック関係にあるユーザーの投稿を除外
+	posts, err = h.blockService.FilterPosts(c, currentUserID, posts)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索中にエラーが発生しました")
+		return
+	}
+
+	// 年齢制限のある投稿を除外
+	viewer, err := h.contentPolicy.LoadViewer(c, currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索中にエラーが発生しました")
+		return
+	}
+	posts, hiddenCount := h.contentPolicy.FilterPosts(viewer, posts)
+
+	// 投稿者・いいね状態をまとめて取得
+	hydrated, err := hydratePosts(c, h.userRepo, h.postRepo, h.likeRepo, h.reactionRepo, h.media, currentUserID, posts)
+	if err != nil {
+		h.log.Error("投稿の関連データ取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索中にエラーが発生しました")
+		return
+	}
+
+	postsResponse := make([]gin.H, 0, len(posts))
+	for _, post := range posts {
+		user, ok := hydrated.users[post.UserID]
+		if !ok {
+			continue
+		}
+
+		postsResponse = append(postsResponse, gin.H{
+			"id":              post.ID,
+			"user_id":         post.UserID,
+			"content":         post.Content,
+			"media_urls":      post.MediaURLs,
+			"media":           hydrated.mediaAttachments(post.ID),
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"created_at":      post.CreatedAt,
+			"likes_count":     post.LikeCount,
+			"views_count":     post.ViewCount,
+			"shares_count":    post.ShareCount,
+			"sharing_enabled": post.SharingEnabled,
+			"replies_count":   post.ReplyCount,
+			"reposts_count":   post.RepostCount,
+			"is_liked":        hydrated.liked[post.ID],
+			"reactions":       hydrated.reactionCounts(post.ID),
+			"my_reactions":    hydrated.viewerReactions(post.ID),
+			"user": gin.H{
+				"id":           user.ID,
+				"username":     user.Username,
+				"display_name": user.Name,
+				"avatar_url":   user.ProfileImage,
+				"is_supporter": user.IsSupporter(),
+			},
+		})
+	}
+
+	response.Success(c, gin.H{
+		"query":          query,
+		"posts":          postsResponse,
+		"content_filter": contentFilterMeta(hiddenCount),
+		"pagination": gin.H{
+			"page":     page,
+			"per_page": perPage,
+			"has_more": hasMore,
+		},
+	})
+}
+
+func (h *SearchHandler) searchUsers(c *gin.Context, currentUserID uuid.UUID, query string) {
+	page, perPage, offset := listPagination(c)
+
+	users, err := h.searchIndex.SearchUsers(c, query, offset, perPage)
+	if err != nil {
+		h.log.Error("ユーザーの検索中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索中にエラーが発生しました")
+		return
+	}
+	hasMore := len(users) == perPage
+
+	// ブロック関係にあるユーザーを除外
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	visibleIDs, err := h.blockService.FilterUserIDs(c, currentUserID, userIDs)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索中にエラーが発生しました")
+		return
+	}
+	visible := make(map[uuid.UUID]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+
+	usersResponse := make([]gin.H, 0, len(visibleIDs))
+	for _, user := range users {
+		if !visible[user.ID] {
+			continue
+		}
+		usersResponse = append(usersResponse, gin.H{
+			"id":           user.ID,
+			"username":     user.Username,
+			"display_name": user.Name,
+			"bio":          user.Bio,
+			"avatar_url":   user.ProfileImage,
+			"is_supporter": user.IsSupporter(),
+			"verified":     user.IsVerified,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"query": query,
+		"users": usersResponse,
+		"pagination": gin.H{
+			"page":     page,
+			"per_page": perPage,
+			"has_more": hasMore,
+		},
+	})
+}
+
+// GetSearchHistory 最近の検索を取得するハンドラー
+// @Summary 最近の検索を取得する
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search/history [get]
+func (h *SearchHandler) GetSearchHistory(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.search.History(c, currentUserID)
+	if err != nil {
+		h.log.Error("検索履歴の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索履歴の取得中にエラーが発生しました")
+		return
+	}
+
+	historyResponse := make([]gin.H, 0, len(history))
+	for _, entry := range history {
+		historyResponse = append(historyResponse, gin.H{
+			"id":          entry.ID,
+			"query":       entry.Query,
+			"searched_at": entry.SearchedAt,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"history": historyResponse,
+	})
+}
+
+// DeleteSearchHistoryEntry 検索履歴を1件削除するハンドラー
+// @Summary 検索履歴を1件削除する
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search/history/{id} [delete]
+func (h *SearchHandler) DeleteSearchHistoryEntry(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な検索履歴IDです", nil)
+		return
+	}
+
+	if err := h.search.DeleteHistoryEntry(c, currentUserID, entryID); err != nil {
+		if err.Error() == "search history entry not found" {
+			response.NotFound(c, "検索履歴が見つかりません")
+			return
+		}
+		h.log.Error("検索履歴の削除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索履歴の削除中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"message": "検索履歴を削除しました",
+	})
+}
+
+// ClearSearchHistory 検索履歴をすべて削除するハンドラー
+// @Summary 検索履歴をすべて削除する
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search/history [delete]
+func (h *SearchHandler) ClearSearchHistory(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.search.ClearHistory(c, currentUserID); err != nil {
+		h.log.Error("検索履歴の削除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "検索履歴の削除中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"message": "検索履歴をすべて削除しました",
+	})
+}
+
+// GetSavedSearches 保存した検索を取得するハンドラー
+// @Summary 保存した検索を取得する
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search/saved [get]
+func (h *SearchHandler) GetSavedSearches(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	searches, err := h.search.SavedSearches(c, currentUserID)
+	if err != nil {
+		h.log.Error("保存した検索の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "保存した検索の取得中にエラーが発生しました")
+		return
+	}
+
+	searchesResponse := make([]gin.H, 0, len(searches))
+	for _, search := range searches {
+		searchesResponse = append(searchesResponse, savedSearchResponse(search))
+	}
+
+	response.Success(c, gin.H{
+		"saved_searches": searchesResponse,
+	})
+}
+
+// SaveSearch 検索を保存するハンドラー
+// @Summary 検索を保存する
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SaveSearchRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search/saved [post]
+func (h *SearchHandler) SaveSearch(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SaveSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	query, err := service.NormalizeQuery(req.Query)
+	if err != nil {
+		response.BadRequest(c, "検索語は1〜100文字で指定してください", nil)
+		return
+	}
+
+	search, err := h.search.SaveSearch(c, currentUserID, query, req.Notify)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSavedSearchLimit):
+			response.BadRequest(c, "保存できる検索の上限に達しています", nil)
+		case err.Error() == "saved search already exists":
+			response.BadRequest(c, "この検索はすでに保存されています", nil)
+		default:
+			h.log.Error("検索の保存中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "検索の保存中にエラーが発生しました")
+		}
+		return
+	}
+
+	response.Created(c, savedSearchResponse(search))
+}
+
+// UpdateSavedSearch 保存した検索の通知設定を変更するハンドラー
+// @Summary 保存した検索の通知設定を変更する
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateSavedSearchRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search/saved/{id} [patch]
+func (h *SearchHandler) UpdateSavedSearch(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	searchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な保存した検索IDです", nil)
+		return
+	}
+
+	var req UpdateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	search, err := h.search.SetNotify(c, currentUserID, searchID, *req.Notify)
+	if err != nil {
+		if err.Error() == "saved search not found" {
+			response.NotFound(c, "保存した検索が見つかりません")
+			return
+		}
+		h.log.Error("保存した検索の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "保存した検索の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, savedSearchResponse(search))
+}
+
+// DeleteSavedSearch 保存した検索を削除するハンドラー
+// @Summary 保存した検索を削除する
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/search/saved/{id} [delete]
+func (h *SearchHandler) DeleteSavedSearch(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	searchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効な保存した検索IDです", nil)
+		return
+	}
+
+	if err := h.search.DeleteSavedSearch(c, currentUserID, searchID); err != nil {
+		if err.Error() == "saved search not found" {
+			response.NotFound(c, "保存した検索が見つかりません")
+			return
+		}
+		h.log.Error("保存した検索の削除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "保存した検索の削除中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"message": "保存した検索を削除しました",
+	})
+}
+
+// 保存した検索のレスポンスを作成する
+func savedSearchResponse(search *models.SavedSearch) gin.H {
+	return gin.H{
+		"id":         search.ID,
+		"query":      search.Query,
+		"notify":     search.Notify,
+		"created_at": search.CreatedAt,
+	}
+}
+
+// currentUserID 認証済みユーザーのIDを取得する（取得できない場合はエラーレスポンスを返す）
+func (h *SearchHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
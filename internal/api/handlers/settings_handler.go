@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	repointerfaces "github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// 探索タイムラインから除外できるキーワードの最大数
+	maxExploreExcludedKeywords = 100
+	// 除外するキーワード1件の最大文字数
+	maxExploreExcludedKeywordLength = 100
+)
+
+// UpdateExploreExcludedKeywordsRequest 探索タイムラインの除外キーワード更新リクエスト
+type UpdateExploreExcludedKeywordsRequest struct {
+	Keywords []string `json:"keywords" binding:"required"`
+}
+
+// UpdateProfileVisitorsRequest プロフィール訪問者の表示の設定リクエスト
+type UpdateProfileVisitorsRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// UpdatePresenceRequest オンライン状態の表示の設定リクエスト
+type UpdatePresenceRequest struct {
+	Visible *bool `json:"visible" binding:"required"`
+}
+
+// UpdateLikesVisibilityRequest いいねした投稿の公開の設定リクエスト
+type UpdateLikesVisibilityRequest struct {
+	Visible *bool `json:"visible" binding:"required"`
+}
+
+// UpdateHomeTimelineOrderRequest ホームタイムラインの既定の並び順の設定リクエスト
+type UpdateHomeTimelineOrderRequest struct {
+	Order string `json:"order" binding:"required,oneof=latest ranked"`
+}
+
+// UpdateNotificationGroupingRequest クライアントの種類ごとの通知の表示形式の設定リクエスト
+type UpdateNotificationGroupingRequest struct {
+	ClientType string `json:"client_type" binding:"required"`
+	Grouping   string `json:"grouping" binding:"required,oneof=grouped flat"`
+}
+
+// UpdateProfileThemeRequest プロフィールのカスタマイズの設定リクエスト（指定した内容で置き換える）
+type UpdateProfileThemeRequest struct {
+	// アクセントカラー（#rrggbb または #rgb 形式。空の場合はクライアントのデフォルト）
+	AccentColor string `json:"accent_color"`
+	// プロフィールに表示するハッシュタグ（先頭の#は省略可、表示順）
+	PinnedHashtags []string `json:"pinned_hashtags"`
+}
+
+// SettingsHandler ユーザー設定関連のハンドラーを管理する構造体
+type SettingsHandler struct {
+	settingsRepo    repointerfaces.SettingsRepository
+	profileVisitors *service.ProfileVisitorService
+	log             logger.Logger
+}
+
+// NewSettingsHandler 新しい設定ハンドラーを作成する
+func NewSettingsHandler(
+	settingsRepo repointerfaces.SettingsRepository,
+	profileVisitors *service.ProfileVisitorService,
+	log logger.Logger,
+) *SettingsHandler {
+	return &SettingsHandler{
+		settingsRepo:    settingsRepo,
+		profileVisitors: profileVisitors,
+		log:             log,
+	}
+}
+
+// GetSettings 自分の設定を取得するハンドラー
+// @Summary 自分の設定を取得する
+// @Tags settings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings [get]
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsRepo.Get(c, currentUserID)
+	if err != nil {
+		h.log.Error("設定の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateExploreExcludedKeywords 探索タイムラインから除外するキーワード・ハッシュタグを置き換えるハンドラー
+// ミュートと異なりホームタイムラインには影響しない
+// @Summary 探索タイムラインから除外するキーワード・ハッシュタグを置き換える
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateExploreExcludedKeywordsRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings/explore/excluded-keywords [put]
+func (h *SettingsHandler) UpdateExploreExcludedKeywords(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateExploreExcludedKeywordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	keywords, ok := normalizeExcludedKeywords(req.Keywords)
+	if !ok {
+		response.BadRequest(c, "除外キーワードは1〜100文字で、最大100件まで指定できます", nil)
+		return
+	}
+
+	settings, err := h.settingsRepo.UpdateExploreExcludedKeywords(c, currentUserID, keywords)
+	if err != nil {
+		h.log.Error("除外キーワードの更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateProfileVisitors プロフィール訪問者の表示を切り替えるハンドラー
+// 無効にするとこれまでの訪問履歴（訪問した・された両方）は削除される
+// @Summary プロフィール訪問者の表示を切り替える
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileVisitorsRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings/profile-visitors [put]
+func (h *SettingsHandler) UpdateProfileVisitors(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfileVisitorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	settings, err := h.profileVisitors.SetEnabled(c, currentUserID, *req.Enabled)
+	if err != nil {
+		h.log.Error("プロフィール訪問者の設定の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdatePresence オンライン状態と最終ログイン日時を他のユーザーに表示するかを切り替えるハンドラー
+// @Summary オンライン状態と最終ログイン日時を他のユーザーに表示するかを切り替える
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdatePresenceRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings/presence [put]
+func (h *SettingsHandler) UpdatePresence(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePresenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	settings, err := h.settingsRepo.UpdatePresenceVisible(c, currentUserID, *req.Visible)
+	if err != nil {
+		h.log.Error("オンライン状態の表示の設定の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateLikesVisibility いいねした投稿の一覧を他のユーザーに公開するかを切り替えるハンドラー
+// 非公開にしても本人は一覧を取得できる
+// @Summary いいねした投稿の一覧を他のユーザーに公開するかを切り替える
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateLikesVisibilityRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings/likes [put]
+func (h *SettingsHandler) UpdateLikesVisibility(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateLikesVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	settings, err := h.settingsRepo.UpdateLikesVisible(c, currentUserID, *req.Visible)
+	if err != nil {
+		h.log.Error("いいねの公開の設定の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateHomeTimelineOrder ホームタイムラインの既定の並び順を設定するハンドラー
+// タイムラインの取得時にorderクエリパラメータを指定しない場合に使用される
+// @Summary ホームタイムラインの既定の並び順を設定する
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateHomeTimelineOrderRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings/timeline [put]
+func (h *SettingsHandler) UpdateHomeTimelineOrder(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateHomeTimelineOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	settings, err := h.settingsRepo.UpdateHomeTimelineOrder(c, currentUserID, models.TimelineOrder(req.Order))
+	if err != nil {
+		h.log.Error("タイムラインの並び順の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateNotificationGrouping クライアントの種類ごとに通知をまとめて表示するかを設定するハンドラー
+// 通知一覧の取得時にgroupingクエリパラメータを指定しない場合に使用される
+// @Summary クライアントの種類ごとに通知をまとめて表示するかを設定する
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateNotificationGroupingRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings/notifications/grouping [put]
+func (h *SettingsHandler) UpdateNotificationGrouping(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateNotificationGroupingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	if !models.IsValidClientType(req.ClientType) {
+		response.BadRequest(c, "client_typeは"+strings.Join(models.ClientTypes, "・")+"のいずれかで指定してください", nil)
+		return
+	}
+
+	settings, err := h.settingsRepo.UpdateNotificationGrouping(c, currentUserID, req.ClientType, models.NotificationGrouping(req.Grouping))
+	if err != nil {
+		h.log.Error("通知の表示形式の更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateProfileTheme プロフィールのアクセントカラーと表示するハッシュタグを設定するハンドラー
+// 見た目のみの設定で、プロフィールの取得時にクライアントが描画するために返される
+// @Summary プロフィールのアクセントカラーと表示するハッシュタグを設定する
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileThemeRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/settings/profile-theme [put]
+func (h *SettingsHandler) UpdateProfileTheme(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfileThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	accentColor, ok := models.NormalizeAccentColor(req.AccentColor)
+	if !ok {
+		response.BadRequest(c, "アクセントカラーは#rrggbb形式で指定してください", nil)
+		return
+	}
+
+	hashtags, ok := models.NormalizePinnedHashtags(req.PinnedHashtags)
+	if !ok {
+		response.BadRequest(c, fmt.Sprintf("ハッシュタグは文字・数字・_で%d文字以内、最大%d件まで指定できます",
+			models.MaxPinnedHashtagLength, models.MaxPinnedHashtags), nil)
+		return
+	}
+
+	theme := models.ProfileTheme{AccentColor: accentColor, PinnedHashtags: hashtags}
+	settings, err := h.settingsRepo.UpdateProfileTheme(c, currentUserID, theme)
+	if err != nil {
+		h.log.Error("プロフィールのカスタマイズの更新中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "設定の更新中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// normalizeExcludedKeywords キーワードの前後の空白を取り除き、大文字小文字を区別せずに重複を除く
+// 空のキーワードは無視し、長さや件数が上限を超える場合はfalseを返す
+func normalizeExcludedKeywords(keywords []string) ([]string, bool) {
+	normalized := make([]string, 0, len(keywords))
+	seen := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" {
+			continue
+		}
+		if utf8.RuneCountInString(keyword) > maxExploreExcludedKeywordLength {
+			return nil, false
+		}
+
+		key := strings.ToLower(keyword)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, keyword)
+	}
+
+	if len(normalized) > maxExploreExcludedKeywords {
+		return nil, false
+	}
+	return normalized, true
+}
+
+func (h *SettingsHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// SupporterHandler サポーター関連のハンドラーを管理する構造体
+type SupporterHandler struct {
+	supporters *service.SupporterService
+	log        logger.Logger
+}
+
+// NewSupporterHandler 新しいサポーターハンドラーを作成する
+func NewSupporterHandler(supporters *service.SupporterService, log logger.Logger) *SupporterHandler {
+	return &SupporterHandler{
+		supporters: supporters,
+		log:        log,
+	}
+}
+
+// HandleWebhook 決済サービスからのWebhookを受信するハンドラー
+// @Summary 決済サービスからのWebhookを受信する
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param payload body object true "Webhookのペイロード"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/webhooks/supporters [post]
+func (h *SupporterHandler) HandleWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		response.BadRequest(c, "リクエスト本文の読み取りに失敗しました", nil)
+		return
+	}
+
+	applied, err := h.supporters.HandleWebhook(
+		c,
+		c.GetHeader("X-Supporter-Timestamp"),
+		c.GetHeader("X-Supporter-Signature"),
+		body,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSignature):
+			h.log.Warn("サポーターWebhookの署名が無効です", "remote_addr", c.ClientIP())
+			response.Unauthorized(c, "署名が無効です")
+		case errors.Is(err, service.ErrInvalidSupporterEvent):
+			response.BadRequest(c, "イベントの内容が無効です", nil)
+		case err.Error() == "user not found" || err.Error() == "expires_at is required":
+			response.BadRequest(c, "イベントを反映できません", gin.H{"reason": err.Error()})
+		default:
+			h.log.Error("サポーターWebhookの処理中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "イベントの処理中にエラーが発生しました")
+		}
+		return
+	}
+
+	response.Success(c, gin.H{
+		"received": true,
+		"applied":  applied,
+	})
+}
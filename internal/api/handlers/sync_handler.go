@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// 1回の同期で返す変更のデフォルトと最大の件数
+	defaultSyncLimit = 100
+	maxSyncLimit     = 500
+)
+
+// SyncHandler オフラインファーストのクライアント向けの差分同期のハンドラーを管理する構造体
+type SyncHandler struct {
+	syncService *service.SyncService
+	log         logger.Logger
+}
+
+// NewSyncHandler 新しい差分同期のハンドラーを作成する
+func NewSyncHandler(syncService *service.SyncService, log logger.Logger) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+		log:         log,
+	}
+}
+
+// GetChanges 前回の同期以降の変更（タイムラインの新しい投稿、削除された投稿、フォローの変更、通知の変更）を取得するハンドラー
+// 変更にはIDのみを含めるため、クライアントは必要な投稿や通知を個別に取得する
+// cursorを指定しない場合やreset_requiredが返された場合は、すべて取得し直してからnext_cursorで同期を続ける
+// @Summary 前回の同期以降の変更（タイムラインの新しい投稿、削除された投稿、フォローの変更、通知の変更）を取得する
+// @Tags sync
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "前回のレスポンスのカーソル"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/sync [get]
+func (h *SyncHandler) GetChanges(c *gin.Context) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	var cursor *int64
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		value, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "無効なカーソルです", nil)
+			return
+		}
+		cursor = &value
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSyncLimit)))
+	if err != nil || limit < 1 || limit > maxSyncLimit {
+		limit = defaultSyncLimit
+	}
+
+	page, err := h.syncService.Changes(c.Request.Context(), currentUserID, cursor, limit)
+	if err != nil {
+		h.log.Error("同期する変更の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "変更の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, page)
+}
@@ -1,24 +1,37 @@
 package handlers
 
 import (
-	"sort"
 	"strconv"
+	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
 	"github.com/TakuyaAizawa/gox/internal/util/response"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// 人気の投稿として探索タイムラインに表示する投稿の期間
+const explorePopularWindow = 7 * 24 * time.Hour
+
 // TimelineHandler タイムライン関連のハンドラーを管理する構造体
 type TimelineHandler struct {
-	postRepo   interfaces.PostRepository
-	userRepo   interfaces.UserRepository
-	followRepo interfaces.FollowRepository
-	likeRepo   interfaces.LikeRepository
-	log        logger.Logger
+	postRepo       interfaces.PostRepository
+	userRepo       interfaces.UserRepository
+	followRepo     interfaces.FollowRepository
+	likeRepo       interfaces.LikeRepository
+	reactionRepo   interfaces.ReactionRepository
+	blockService   *service.BlockService
+	contentPolicy  *service.ContentPolicyService
+	media          *service.MediaService
+	settingsRepo   interfaces.SettingsRepository
+	fanout         *service.TimelineFanoutService
+	ranking        *service.TimelineRankingService
+	topicRepo      interfaces.TopicRepository
+	systemAccounts *service.SystemAccountService
+	log            logger.Logger
 }
 
 // NewTimelineHandler 新しいタイムラインハンドラーを作成する
@@ -27,19 +40,49 @@ func NewTimelineHandler(
 	userRepo interfaces.UserRepository,
 	followRepo interfaces.FollowRepository,
 	likeRepo interfaces.LikeRepository,
+	reactionRepo interfaces.ReactionRepository,
+	blockService *service.BlockService,
+	contentPolicy *service.ContentPolicyService,
+	media *service.MediaService,
+	settingsRepo interfaces.SettingsRepository,
+	fanout *service.TimelineFanoutService,
+	ranking *service.TimelineRankingService,
+	topicRepo interfaces.TopicRepository,
+	systemAccounts *service.SystemAccountService,
 	log logger.Logger,
 ) *TimelineHandler {
 	return &TimelineHandler{
-		postRepo:   postRepo,
-		userRepo:   userRepo,
-		followRepo: followRepo,
-		likeRepo:   likeRepo,
-		log:        log,
+		postRepo:       postRepo,
+		userRepo:       userRepo,
+		followRepo:     followRepo,
+		likeRepo:       likeRepo,
+		reactionRepo:   reactionRepo,
+		blockService:   blockService,
+		contentPolicy:  contentPolicy,
+		media:          media,
+		settingsRepo:   settingsRepo,
+		fanout:         fanout,
+		ranking:        ranking,
+		topicRepo:      topicRepo,
+		systemAccounts: systemAccounts,
+		log:            log,
 	}
 }
 
 // GetHomeTimeline ホームタイムライン取得ハンドラー
-// フォローしているユーザーの投稿を時系列順で取得する
+// フォローしているユーザーの投稿を、指定した並び順（省略時は設定した並び順）で取得する
+// @Summary ホームタイムライン取得
+// @Tags timeline
+// @Produce json
+// @Security BearerAuth
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Param order query string false "並び順（latest: 新しい順、ranked: おすすめ順。省略時は設定した並び順）" Enums(latest, ranked)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/timeline/home [get]
 func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 	// 現在のユーザーIDを取得
 	currentUserIDStr, exists := c.Get("userID")
@@ -68,60 +111,67 @@ func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 
 	offset := (page - 1) * perPage
 
+	// 並び順の取得
+	order, ok := h.homeTimelineOrder(c, currentUserID)
+	if !ok {
+		return
+	}
+
 	// フォローしているユーザーのIDを取得
-	following, err := h.followRepo.GetFollowing(c.Request.Context(), currentUserID, 0, 1000) // 一度に取得するフォロー数に制限を設ける
+	following, err := h.followRepo.GetFollowing(c.Request.Context(), currentUserID, currentUserID, 0, 1000) // 一度に取得するフォロー数に制限を設ける
 	if err != nil {
 		h.log.Error("フォロー中ユーザーID取得中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "タイムラインの取得中にエラーが発生しました")
 		return
 	}
 
-	// 自分の投稿も含める
+	// 自分の投稿とシステムアカウントのお知らせも含める
 	userIDs := append(following, currentUserID)
+	userIDs = append(userIDs, h.systemAccounts.UserIDs()...)
 
-	// 各ユーザーの投稿を取得して結合
-	var allPosts []*models.Post
-	for _, userID := range userIDs {
-		userPosts, err := h.postRepo.GetByUserID(c.Request.Context(), userID, offset, perPage)
-		if err != nil {
-			h.log.Error("投稿取得中にエラーが発生しました", "error", err, "userID", userID)
-			continue
-		}
-		allPosts = append(allPosts, userPosts...)
+	// おすすめ順の場合は最新の投稿を並べ替え、それより古い投稿は新しい順で続ける
+	var posts []*models.Post
+	var totalPosts int64
+	if order == models.TimelineOrderRanked && offset < service.RankedTimelineWindow {
+		posts, totalPosts, err = h.rankedHomeTimelinePosts(c, currentUserID, userIDs, offset, perPage)
+	} else {
+		posts, totalPosts, err = h.homeTimelinePosts(c, currentUserID, userIDs, offset, perPage)
+	}
+	if err != nil {
+		h.log.Error("投稿取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "タイムラインの取得中にエラーが発生しました")
+		return
 	}
 
-	// 投稿を時系列順にソート
-	sort.Slice(allPosts, func(i, j int) bool {
-		return allPosts[i].CreatedAt.After(allPosts[j].CreatedAt)
-	})
-
-	// ページネーションの範囲に限定
-	var posts []*models.Post
-	if len(allPosts) > 0 {
-		end := offset + perPage
-		if end > len(allPosts) {
-			end = len(allPosts)
-		}
-		if offset < len(allPosts) {
-			posts = allPosts[offset:end]
-		}
+	// 年齢制限のある投稿を除外
+	viewer, err := h.contentPolicy.LoadViewer(c.Request.Context(), currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "タイムラインの取得中にエラーが発生しました")
+		return
 	}
+	posts, hiddenCount := h.contentPolicy.FilterPosts(viewer, posts)
 
-	// 総投稿数は取得した投稿の数をそのまま使用
-	totalPosts := int64(len(allPosts))
+	// 投稿者・返信先・リポスト元・いいね状態をまとめて取得
+	hydrated, err := hydratePosts(c.Request.Context(), h.userRepo, h.postRepo, h.likeRepo, h.reactionRepo, h.media, currentUserID, posts)
+	if err != nil {
+		h.log.Error("投稿の関連データ取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "タイムラインの取得中にエラーが発生しました")
+		return
+	}
 
 	// 投稿のレスポンスを作成
 	postsResponse := make([]gin.H, 0, len(posts))
 	for _, post := range posts {
 		// 投稿ユーザーの情報を取得
-		user, err := h.userRepo.GetByID(c, post.UserID)
-		if err != nil {
-			h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		user, ok := hydrated.users[post.UserID]
+		if !ok {
+			h.log.Error("投稿ユーザーが見つかりません", "userID", post.UserID)
 			continue // このユーザーの情報は取得できないのでスキップ
 		}
 
 		// いいね状態の確認
-		isLiked, _ := h.likeRepo.HasLiked(c, currentUserID, post.ID)
+		isLiked := hydrated.liked[post.ID]
 
 		// リポスト状態の確認
 		// TODO: リポジトリにHasRepostedメソッドを追加する必要があります
@@ -129,30 +179,44 @@ func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 
 		// 投稿レスポンスを作成
 		postResponse := gin.H{
-			"id":            post.ID,
-			"user_id":       post.UserID,
-			"content":       post.Content,
-			"media_urls":    post.MediaURLs,
-			"created_at":    post.CreatedAt,
-			"likes_count":   post.LikeCount,
-			"replies_count": post.ReplyCount,
-			"reposts_count": post.RepostCount,
-			"is_liked":      isLiked,
-			"is_reposted":   isReposted,
+			"id":              post.ID,
+			"user_id":         post.UserID,
+			"content":         post.Content,
+			"media_urls":      post.MediaURLs,
+			"media":           hydrated.mediaAttachments(post.ID),
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"created_at":      post.CreatedAt,
+			"likes_count":     post.LikeCount,
+			"views_count":     post.ViewCount,
+			"shares_count":    post.ShareCount,
+			"sharing_enabled": post.SharingEnabled,
+			"replies_count":   post.ReplyCount,
+			"reposts_count":   post.RepostCount,
+			"is_liked":        isLiked,
+			"reactions":       hydrated.reactionCounts(post.ID),
+			"my_reactions":    hydrated.viewerReactions(post.ID),
+			"is_reposted":     isReposted,
 			"user": gin.H{
 				"id":           user.ID,
 				"username":     user.Username,
 				"display_name": user.Name,
 				"avatar_url":   user.ProfileImage,
+				"is_supporter": user.IsSupporter(),
 			},
 		}
 
-		// 返信の場合は返信先の情報も追加
+		// 返信の場合は返信先の情報も追加（削除された返信先は取得されないため、表示できないことを示す）
 		if post.IsReply && post.ReplyToID != nil {
-			replyToPost, err := h.postRepo.GetByID(c, *post.ReplyToID)
-			if err == nil {
-				replyToUser, err := h.userRepo.GetByID(c, replyToPost.UserID)
-				if err == nil {
+			replyToPost, ok := hydrated.related[*post.ReplyToID]
+			if !ok {
+				postResponse["reply_to"] = unavailablePostPlaceholder(*post.ReplyToID)
+			} else if !h.contentPolicy.CanView(viewer, replyToPost) {
+				postResponse["reply_to"] = restrictedPostPreview(replyToPost)
+			} else {
+				if replyToUser, ok := hydrated.users[replyToPost.UserID]; ok {
 					postResponse["reply_to"] = gin.H{
 						"id":         replyToPost.ID,
 						"user_id":    replyToPost.UserID,
@@ -162,6 +226,7 @@ func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 							"username":     replyToUser.Username,
 							"display_name": replyToUser.Name,
 							"avatar_url":   replyToUser.ProfileImage,
+							"is_supporter": replyToUser.IsSupporter(),
 						},
 					}
 				}
@@ -170,10 +235,13 @@ func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 
 		// リポストの場合はリポスト元の情報も追加
 		if post.IsRepost && post.RepostID != nil {
-			repostPost, err := h.postRepo.GetByID(c, *post.RepostID)
-			if err == nil {
-				repostUser, err := h.userRepo.GetByID(c, repostPost.UserID)
-				if err == nil {
+			repostPost, ok := hydrated.related[*post.RepostID]
+			if !ok {
+				postResponse["repost"] = unavailablePostPlaceholder(*post.RepostID)
+			} else if !h.contentPolicy.CanView(viewer, repostPost) {
+				postResponse["repost"] = restrictedPostPreview(repostPost)
+			} else {
+				if repostUser, ok := hydrated.users[repostPost.UserID]; ok {
 					postResponse["repost"] = gin.H{
 						"id":         repostPost.ID,
 						"user_id":    repostPost.UserID,
@@ -183,6 +251,7 @@ func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 							"username":     repostUser.Username,
 							"display_name": repostUser.Name,
 							"avatar_url":   repostUser.ProfileImage,
+							"is_supporter": repostUser.IsSupporter(),
 						},
 					}
 				}
@@ -199,7 +268,9 @@ func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 	}
 
 	response.Success(c, gin.H{
-		"posts": postsResponse,
+		"posts":          postsResponse,
+		"order":          order,
+		"content_filter": contentFilterMeta(hiddenCount),
 		"pagination": gin.H{
 			"total":       totalPosts,
 			"page":        page,
@@ -209,8 +280,150 @@ func (h *TimelineHandler) GetHomeTimeline(c *gin.Context) {
 	})
 }
 
+// homeTimelineOrder クエリパラメータで指定された並び順を返す（省略時はユーザーの設定、取得できない場合は新しい順）
+// 不正な値の場合はエラーレスポンスを返してfalseを返す
+func (h *TimelineHandler) homeTimelineOrder(c *gin.Context, userID uuid.UUID) (models.TimelineOrder, bool) {
+	if value := c.Query("order"); value != "" {
+		order := models.TimelineOrder(value)
+		if !order.IsValid() {
+			response.BadRequest(c, "orderはlatestまたはrankedで指定してください", nil)
+			return "", false
+		}
+		return order, true
+	}
+
+	settings, err := h.settingsRepo.Get(c.Request.Context(), userID)
+	if err != nil {
+		h.log.Error("設定の取得中にエラーが発生しました", "error", err, "user_id", userID)
+		return models.TimelineOrderLatest, true
+	}
+	return settings.HomeTimelineOrder, true
+}
+
+// homeTimelinePosts ホームタイムラインの投稿を新しい順に取得する
+// 多くのユーザーをフォローしている場合はキャッシュから取得し、使用できない場合はデータベースから取得する
+func (h *TimelineHandler) homeTimelinePosts(c *gin.Context, userID uuid.UUID, userIDs []uuid.UUID, offset, limit int) ([]*models.Post, int64, error) {
+	posts, total, cached := h.fanout.HomeTimeline(c.Request.Context(), userID, userIDs, offset, limit)
+	if cached {
+		return posts, total, nil
+	}
+
+	// フォロー中ユーザーと自分の投稿をまとめて取得（ブロック関係にあるユーザーの投稿は除く）
+	posts, err := h.postRepo.GetByUserIDs(c.Request.Context(), userIDs, userID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err = h.postRepo.CountByUserIDs(c.Request.Context(), userIDs, userID)
+	if err != nil {
+		h.log.Error("投稿数の取得中にエラーが発生しました", "error", err)
+		total = int64(len(posts))
+	}
+	return posts, total, nil
+}
+
+// rankedHomeTimelinePosts 最新の投稿をおすすめ順に並べ替えたホームタイムラインを取得する
+// 並べ替える範囲を超えるページは新しい順の続きで埋める
+func (h *TimelineHandler) rankedHomeTimelinePosts(c *gin.Context, userID uuid.UUID, userIDs []uuid.UUID, offset, limit int) ([]*models.Post, int64, error) {
+	candidates, total, err := h.homeTimelinePosts(c, userID, userIDs, 0, service.RankedTimelineWindow)
+	if err != nil {
+		return nil, 0, err
+	}
+	ranked := h.ranking.Rank(c.Request.Context(), userID, candidates)
+
+	posts := make([]*models.Post, 0, limit)
+	if offset < len(ranked) {
+		posts = append(posts, ranked[offset:min(offset+limit, len(ranked))]...)
+	}
+
+	if rest := limit - len(posts); rest > 0 && len(candidates) == service.RankedTimelineWindow {
+		older, _, err := h.homeTimelinePosts(c, userID, userIDs, service.RankedTimelineWindow, rest)
+		if err != nil {
+			return nil, 0, err
+		}
+		posts = append(posts, older...)
+	}
+	return posts, total, nil
+}
+
+// GetHomeTimelineUpdates ホームタイムラインの新着投稿数取得ハンドラー
+// since_idで指定した投稿より新しい、フォロー中ユーザーの投稿数のみを返す
+// @Summary ホームタイムラインの新着投稿数取得
+// @Tags timeline
+// @Produce json
+// @Security BearerAuth
+// @Param since_id query string false "このIDより新しい投稿のみ取得する"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/timeline/home/updates [get]
+func (h *TimelineHandler) GetHomeTimelineUpdates(c *gin.Context) {
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	sinceID, err := uuid.Parse(c.Query("since_id"))
+	if err != nil {
+		response.BadRequest(c, "since_idには投稿IDを指定してください", nil)
+		return
+	}
+
+	// フォローしているユーザーのIDを取得
+	// ブロック関係にあるユーザーの投稿は数えない
+	following, err := h.followRepo.GetFollowing(c.Request.Context(), currentUserID, currentUserID, 0, 1000)
+	if err != nil {
+		h.log.Error("フォロー中ユーザーID取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "新着投稿数の取得中にエラーが発生しました")
+		return
+	}
+
+	// システムアカウントのお知らせも数える
+	following = append(following, h.systemAccounts.UserIDs()...)
+
+	count, err := h.postRepo.CountNewerByUserIDs(c.Request.Context(), following, sinceID)
+	if err != nil {
+		if err.Error() == "post not found" {
+			response.NotFound(c, "since_idの投稿が見つかりません")
+			return
+		}
+		h.log.Error("新着投稿数の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "新着投稿数の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"count":    count,
+		"since_id": sinceID,
+	})
+}
+
 // GetExploreTimeline 探索タイムライン取得ハンドラー
-// 人気の投稿や新着投稿を取得する
+// 人気の投稿や新着投稿を取得する（トピックを指定した場合はそのトピックに分類された投稿のみ）
+// @Summary 探索タイムライン取得
+// @Tags timeline
+// @Produce json
+// @Security BearerAuth
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Param sort_by query string false "ソート方法を取得（デフォルトは人気順）" default(popular)
+// @Param topic query string false "トピックのスラッグ（省略時はすべての投稿）"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/timeline/explore [get]
 func (h *TimelineHandler) GetExploreTimeline(c *gin.Context) {
 	// ページネーションパラメータの取得
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -231,13 +444,56 @@ func (h *TimelineHandler) GetExploreTimeline(c *gin.Context) {
 	var posts []*models.Post
 	var err error
 
-	// ソート方法に応じた投稿を取得
-	if sortBy == "latest" {
+	// トピックを取得（指定された場合）
+	var topic *models.Topic
+	if slug := c.Query("topic"); slug != "" {
+		normalized, ok := models.NormalizeTopicSlug(slug)
+		if !ok {
+			response.NotFound(c, "トピックが見つかりません")
+			return
+		}
+		topic, err = h.topicRepo.GetBySlug(c.Request.Context(), normalized)
+		if err != nil {
+			if err.Error() == "topic not found" {
+				response.NotFound(c, "トピックが見つかりません")
+				return
+			}
+			h.log.Error("トピックの取得中にエラーが発生しました", "error", err, "topic", normalized)
+			response.InternalServerError(c, "探索タイムラインの取得中にエラーが発生しました")
+			return
+		}
+	}
+
+	// 現在のユーザーID（認証済みの場合）
+	var currentUserID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
+	}
+
+	// 探索タイムラインから除外するキーワード（ホームタイムラインには適用しない）
+	var excludedKeywords []string
+	if currentUserID != uuid.Nil {
+		settings, err := h.settingsRepo.Get(c.Request.Context(), currentUserID)
+		if err != nil {
+			h.log.Error("設定の取得中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "探索タイムラインの取得中にエラーが発生しました")
+			return
+		}
+		excludedKeywords = settings.ExploreExcludedKeywords
+	}
+
+	// ソート方法とトピックに応じた投稿を取得（ブロック関係にあるユーザーの投稿は除く）
+	switch {
+	case topic != nil && sortBy == "latest":
+		posts, err = h.postRepo.ListByTopic(c.Request.Context(), currentUserID, topic.ID, excludedKeywords, offset, perPage)
+	case topic != nil:
+		posts, err = h.postRepo.ListPopularByTopic(c.Request.Context(), currentUserID, topic.ID, explorePopularWindow, excludedKeywords, offset, perPage)
+	case sortBy == "latest":
 		// 最新の投稿を取得
-		posts, err = h.postRepo.List(c, offset, perPage)
-	} else {
-		// 人気の投稿を取得（いいねとリポストの合計数でソート）
-		posts, err = h.postRepo.List(c.Request.Context(), offset, perPage)
+		posts, err = h.postRepo.ListExcluding(c.Request.Context(), currentUserID, excludedKeywords, offset, perPage)
+	default:
+		// 人気の投稿を取得（ページをまたいで順位が一貫するようデータベースでスコア順に並べる）
+		posts, err = h.postRepo.ListPopular(c.Request.Context(), currentUserID, explorePopularWindow, excludedKeywords, offset, perPage)
 	}
 
 	if err != nil {
@@ -246,18 +502,14 @@ func (h *TimelineHandler) GetExploreTimeline(c *gin.Context) {
 		return
 	}
 
-	// 投稿をいいね数+リポスト数の多い順にソート
-	sort.Slice(posts, func(i, j int) bool {
-		likesAndRepostsI := posts[i].LikeCount + posts[i].RepostCount
-		likesAndRepostsJ := posts[j].LikeCount + posts[j].RepostCount
-		return likesAndRepostsI > likesAndRepostsJ
-	})
-
-	// 現在のユーザーID（認証済みの場合）
-	var currentUserID uuid.UUID
-	if currentUserIDStr, exists := c.Get("userID"); exists {
-		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
+	// 年齢制限のある投稿を除外
+	viewer, err := h.contentPolicy.LoadViewer(c.Request.Context(), currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "探索タイムラインの取得中にエラーが発生しました")
+		return
 	}
+	posts, hiddenCount := h.contentPolicy.FilterPosts(viewer, posts)
 
 	// 投稿の総数を概算
 	// 探索タイムラインの場合は簡略化して投稿数をカウント
@@ -267,37 +519,53 @@ func (h *TimelineHandler) GetExploreTimeline(c *gin.Context) {
 
 	// Note: 正確な数はパフォーマンス上の理由から計算しない
 
+	// 投稿者・いいね状態をまとめて取得
+	hydrated, err := hydratePosts(c.Request.Context(), h.userRepo, h.postRepo, h.likeRepo, h.reactionRepo, h.media, currentUserID, posts)
+	if err != nil {
+		h.log.Error("投稿の関連データ取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "探索タイムラインの取得中にエラーが発生しました")
+		return
+	}
+
 	// 投稿のレスポンスを作成
 	postsResponse := make([]gin.H, 0, len(posts))
 	for _, post := range posts {
 		// 投稿ユーザーの情報を取得
-		user, err := h.userRepo.GetByID(c, post.UserID)
-		if err != nil {
-			h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		user, ok := hydrated.users[post.UserID]
+		if !ok {
+			h.log.Error("投稿ユーザーが見つかりません", "userID", post.UserID)
 			continue // このユーザーの情報は取得できないのでスキップ
 		}
 
 		// いいね状態の確認
-		isLiked := false
-		if currentUserID != uuid.Nil {
-			isLiked, _ = h.likeRepo.HasLiked(c, currentUserID, post.ID)
-		}
+		isLiked := hydrated.liked[post.ID]
 
 		postsResponse = append(postsResponse, gin.H{
-			"id":            post.ID,
-			"user_id":       post.UserID,
-			"content":       post.Content,
-			"media_urls":    post.MediaURLs,
-			"created_at":    post.CreatedAt,
-			"likes_count":   post.LikeCount,
-			"replies_count": post.ReplyCount,
-			"reposts_count": post.RepostCount,
-			"is_liked":      isLiked,
+			"id":              post.ID,
+			"user_id":         post.UserID,
+			"content":         post.Content,
+			"media_urls":      post.MediaURLs,
+			"media":           hydrated.mediaAttachments(post.ID),
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"created_at":      post.CreatedAt,
+			"likes_count":     post.LikeCount,
+			"views_count":     post.ViewCount,
+			"shares_count":    post.ShareCount,
+			"sharing_enabled": post.SharingEnabled,
+			"replies_count":   post.ReplyCount,
+			"reposts_count":   post.RepostCount,
+			"is_liked":        isLiked,
+			"reactions":       hydrated.reactionCounts(post.ID),
+			"my_reactions":    hydrated.viewerReactions(post.ID),
 			"user": gin.H{
 				"id":           user.ID,
 				"username":     user.Username,
 				"display_name": user.Name,
 				"avatar_url":   user.ProfileImage,
+				"is_supporter": user.IsSupporter(),
 			},
 		})
 	}
@@ -309,7 +577,9 @@ func (h *TimelineHandler) GetExploreTimeline(c *gin.Context) {
 	}
 
 	response.Success(c, gin.H{
-		"posts": postsResponse,
+		"posts":          postsResponse,
+		"topic":          topic,
+		"content_filter": contentFilterMeta(hiddenCount),
 		"pagination": gin.H{
 			"total":       totalPosts,
 			"page":        page,
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// TopicHandler 探索タブのトピックに関するハンドラーを管理する構造体
+type TopicHandler struct {
+	topicRepo interfaces.TopicRepository
+	log       logger.Logger
+}
+
+// NewTopicHandler 新しいトピックハンドラーを作成する
+func NewTopicHandler(topicRepo interfaces.TopicRepository, log logger.Logger) *TopicHandler {
+	return &TopicHandler{
+		topicRepo: topicRepo,
+		log:       log,
+	}
+}
+
+// ListTopics トピックの一覧を取得するハンドラー
+// 探索タイムラインの topic パラメータや投稿作成時の topics にはスラッグを指定する
+// @Summary トピックの一覧を取得する
+// @Tags topics
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/topics [get]
+func (h *TopicHandler) ListTopics(c *gin.Context) {
+	topics, err := h.topicRepo.List(c)
+	if err != nil {
+		h.log.Error("トピックの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "トピックの取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{"topics": topics})
+}
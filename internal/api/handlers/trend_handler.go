@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// トレンドを集計する期間の上限（時間）
+	maxTrendHours = 168
+	// 1回に取得するトレンドの最大数
+	maxTrendLimit = 50
+)
+
+// TrendHandler ハッシュタグのトレンドに関するハンドラーを管理する構造体
+type TrendHandler struct {
+	trendRepo interfaces.TrendRepository
+	log       logger.Logger
+}
+
+// NewTrendHandler 新しいトレンドハンドラーを作成する
+func NewTrendHandler(trendRepo interfaces.TrendRepository, log logger.Logger) *TrendHandler {
+	return &TrendHandler{
+		trendRepo: trendRepo,
+		log:       log,
+	}
+}
+
+// GetTrends 直近によく使われたハッシュタグを取得するハンドラー
+// 集計は保守タスクが定期的に行うため、直近の投稿は反映されていない場合がある
+// @Summary 直近によく使われたハッシュタグを取得する
+// @Tags trends
+// @Produce json
+// @Security BearerAuth
+// @Param hours query integer false "集計する期間（1〜168時間、デフォルト24時間）" default(24)
+// @Param limit query integer false "取得する件数（1〜50件、デフォルト10件）" default(10)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/trends [get]
+func (h *TrendHandler) GetTrends(c *gin.Context) {
+	// 集計する期間（1〜168時間、デフォルト24時間）
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours < 1 || hours > maxTrendHours {
+		response.BadRequest(c, "hoursは1〜168の範囲で指定してください", nil)
+		return
+	}
+
+	// 取得する件数（1〜50件、デフォルト10件）
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxTrendLimit {
+		response.BadRequest(c, "limitは1〜50の範囲で指定してください", nil)
+		return
+	}
+
+	since := time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
+	trends, err := h.trendRepo.ListTop(c, since, limit)
+	if err != nil {
+		h.log.Error("トレンドの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "トレンドの取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"trends": trends,
+		"hours":  hours,
+	})
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UploadHandler 投稿に添付するメディアを複数のリクエストに分けてアップロードするハンドラーを管理する構造体
+// 接続が不安定な環境でも、途中で失敗したパートのみを送り直してアップロードを再開できる
+type UploadHandler struct {
+	sessionRepo interfaces.UploadSessionRepository
+	userRepo    interfaces.UserRepository
+	uploads     *service.UploadSessionService
+	supporters  *service.SupporterService
+	media       *service.MediaService
+	log         logger.Logger
+}
+
+// NewUploadHandler 新しい分割アップロードのハンドラーを作成する
+func NewUploadHandler(
+	sessionRepo interfaces.UploadSessionRepository,
+	userRepo interfaces.UserRepository,
+	uploads *service.UploadSessionService,
+	supporters *service.SupporterService,
+	media *service.MediaService,
+	log logger.Logger,
+) *UploadHandler {
+	return &UploadHandler{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		uploads:     uploads,
+		supporters:  supporters,
+		media:       media,
+		log:         log,
+	}
+}
+
+// InitiateUploadRequest 分割アップロードの開始リクエストの構造体
+type InitiateUploadRequest struct {
+	Filename  string `json:"filename" binding:"required,max=255"`
+	TotalSize int64  `json:"total_size" binding:"required,min=1"`
+}
+
+// InitiateUpload 分割アップロードを開始するハンドラー
+// ファイル形式と大きさは通常のアップロードと同じ上限で検証し、パートの大きさと数を返す
+// @Summary 分割アップロードを開始する
+// @Tags uploads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body InitiateUploadRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/uploads [post]
+func (h *UploadHandler) InitiateUpload(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req InitiateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	quota := mediaQuotaFor(c, h.userRepo, h.supporters, h.log, currentUserID)
+	if _, ok := checkPostMediaFile(c, req.Filename, req.TotalSize, quota); !ok {
+		return
+	}
+
+	// メディアの使用量の上限を超える場合は、パートを送信する前に拒否する（使用量には完了時に加える）
+	if err := h.media.CheckStorage(c, currentUserID, req.TotalSize); err != nil {
+		if errors.Is(err, service.ErrStorageQuotaExceeded) {
+			respondStorageQuotaExceeded(c, h.media, h.log, currentUserID, req.TotalSize)
+			return
+		}
+		h.log.Error("メディアの使用量の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "アップロードの開始中にエラーが発生しました")
+		return
+	}
+
+	session, err := h.uploads.Initiate(c, currentUserID, req.Filename, req.TotalSize)
+	if err != nil {
+		h.log.Error("分割アップロードの開始中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "アップロードの開始中にエラーが発生しました")
+		return
+	}
+
+	response.Created(c, uploadSessionResponse(session))
+}
+
+// GetUpload 分割アップロードの状態を取得するハンドラー（再開時に不足しているパートを確認する）
+// @Summary 分割アップロードの状態を取得する（再開時に不足しているパートを確認する）
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/uploads/{id} [get]
+func (h *UploadHandler) GetUpload(c *gin.Context) {
+	session, ok := h.targetSession(c)
+	if !ok {
+		return
+	}
+
+	response.Success(c, uploadSessionResponse(session))
+}
+
+// UploadPart パートを送信するハンドラー（リクエストの本文がパートの内容）
+// 同じパートは何度送信してもよく、最後以外のパートはpart_sizeバイト、最後のパートは残りのバイト数で送信する
+// @Summary パートを送信する（リクエストの本文がパートの内容）
+// @Tags uploads
+// @Accept octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param index path integer true "パートの番号（0から）"
+// @Param data body string true "パートの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/uploads/{id}/parts/{index} [put]
+func (h *UploadHandler) UploadPart(c *gin.Context) {
+	session, ok := h.targetSession(c)
+	if !ok {
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		response.BadRequest(c, "無効なパート番号です", nil)
+		return
+	}
+
+	updated, err := h.uploads.UploadPart(c, session, index, c.Request.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidUploadPart):
+			response.BadRequest(c, "パート番号がアップロードの範囲外です", nil)
+		case errors.Is(err, coreinterfaces.ErrUploadPartSize):
+			response.BadRequest(c, "パートの大きさが正しくありません", gin.H{"expected_size": session.PartLength(index)})
+		case errors.Is(err, service.ErrUploadCompleted):
+			response.BadRequest(c, "このアップロードは既に完了しています", nil)
+		default:
+			h.log.Error("パートの保存中にエラーが発生しました", "error", err, "session_id", session.ID, "index", index)
+			response.InternalServerError(c, "パートの保存中にエラーが発生しました")
+		}
+		return
+	}
+
+	response.Success(c, uploadSessionResponse(updated))
+}
+
+// CompleteUpload パートを結合してメディアとして保存するハンドラー
+// 返されたURLを投稿作成時のmedia_urlsに指定する。完了済みのアップロードに再度リクエストした場合は同じ結果を返す
+// @Summary パートを結合してメディアとして保存する
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/uploads/{id}/complete [post]
+func (h *UploadHandler) CompleteUpload(c *gin.Context) {
+	session, ok := h.targetSession(c)
+	if !ok {
+		return
+	}
+
+	quota := mediaQuotaFor(c, h.userRepo, h.supporters, h.log, session.UserID)
+	completed, err := h.uploads.Complete(c.Request.Context(), session, quota.VideoMaxDuration)
+	if err != nil {
+		if errors.Is(err, service.ErrUploadIncomplete) {
+			response.BadRequest(c, "受信していないパートがあります", gin.H{"missing_parts": session.MissingParts()})
+			return
+		}
+		if errors.Is(err, service.ErrStorageQuotaExceeded) {
+			respondStorageQuotaExceeded(c, h.media, h.log, session.UserID, session.TotalSize)
+			return
+		}
+		respondMediaStoreError(c, h.log, err, quota)
+		return
+	}
+
+	result := uploadSessionResponse(completed)
+	result["media"] = h.media.Attachment(c.Request.Context(), completed.URL)
+	response.Success(c, result)
+}
+
+// AbortUpload 分割アップロードを中止し、受信済みのパートを削除するハンドラー
+// @Summary 分割アップロードを中止し、受信済みのパートを削除する
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/uploads/{id} [delete]
+func (h *UploadHandler) AbortUpload(c *gin.Context) {
+	session, ok := h.targetSession(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uploads.Abort(c, session); err != nil {
+		if err.Error() == "upload session not found" {
+			response.NotFound(c, "アップロードが見つかりません")
+			return
+		}
+		h.log.Error("分割アップロードの中止中にエラーが発生しました", "error", err, "session_id", session.ID)
+		response.InternalServerError(c, "アップロードの中止中にエラーが発生しました")
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// uploadSessionResponse 分割アップロードの状態のレスポンスを作成する
+func uploadSessionResponse(session *models.UploadSession) gin.H {
+	return gin.H{
+		"upload":        session,
+		"missing_parts": session.MissingParts(),
+		"completed":     session.IsCompleted(),
+	}
+}
+
+func (h *UploadHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
+
+// targetSession パスで指定された自分の分割アップロードを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+// 他のユーザーのアップロードや期限切れのアップロードは存在しないものとして扱う
+func (h *UploadHandler) targetSession(c *gin.Context) (*models.UploadSession, bool) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効なアップロードIDです", nil)
+		return nil, false
+	}
+
+	session, err := h.sessionRepo.GetByID(c, sessionID)
+	if err != nil {
+		if err.Error() == "upload session not found" {
+			response.NotFound(c, "アップロードが見つかりません")
+			return nil, false
+		}
+		h.log.Error("分割アップロードの取得中にエラーが発生しました", "error", err, "session_id", sessionID)
+		response.InternalServerError(c, "アップロードの取得中にエラーが発生しました")
+		return nil, false
+	}
+	if session.UserID != currentUserID || !session.ExpiresAt.After(time.Now()) {
+		response.NotFound(c, "アップロードが見つかりません")
+		return nil, false
+	}
+
+	return session, true
+}
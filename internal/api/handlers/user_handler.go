@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	repointerfaces "github.com/TakuyaAizawa/gox/internal/repository/interfaces"
 	"github.com/TakuyaAizawa/gox/internal/service"
 	"github.com/TakuyaAizawa/gox/internal/util/response"
@@ -18,8 +22,20 @@ type UserHandler struct {
 	userRepo            repointerfaces.UserRepository
 	followRepo          repointerfaces.FollowRepository
 	postRepo            repointerfaces.PostRepository
+	likeRepo            repointerfaces.LikeRepository
+	reactionRepo        repointerfaces.ReactionRepository
 	notificationService *service.NotificationService
-	storageProvider     interfaces.StorageProvider
+	blockService        *service.BlockService
+	contentPolicy       *service.ContentPolicyService
+	contentFilter       *service.ContentFilterService
+	supporters          *service.SupporterService
+	profileCards        *service.ProfileCardService
+	timelineFanout      *service.TimelineFanoutService
+	profileVisitors     *service.ProfileVisitorService
+	media               *service.MediaService
+	settingsRepo        repointerfaces.SettingsRepository
+	presence            *service.PresenceService
+	searchIndex         *service.SearchIndexService
 	log                 logger.Logger
 }
 
@@ -28,21 +44,58 @@ func NewUserHandler(
 	userRepo repointerfaces.UserRepository,
 	followRepo repointerfaces.FollowRepository,
 	postRepo repointerfaces.PostRepository,
+	likeRepo repointerfaces.LikeRepository,
+	reactionRepo repointerfaces.ReactionRepository,
 	notificationService *service.NotificationService,
-	storageProvider interfaces.StorageProvider,
+	blockService *service.BlockService,
+	contentPolicy *service.ContentPolicyService,
+	contentFilter *service.ContentFilterService,
+	supporters *service.SupporterService,
+	profileCards *service.ProfileCardService,
+	timelineFanout *service.TimelineFanoutService,
+	profileVisitors *service.ProfileVisitorService,
+	media *service.MediaService,
+	settingsRepo repointerfaces.SettingsRepository,
+	presence *service.PresenceService,
+	searchIndex *service.SearchIndexService,
 	log logger.Logger,
 ) *UserHandler {
 	return &UserHandler{
 		userRepo:            userRepo,
 		followRepo:          followRepo,
 		postRepo:            postRepo,
+		likeRepo:            likeRepo,
+		reactionRepo:        reactionRepo,
 		notificationService: notificationService,
-		storageProvider:     storageProvider,
+		blockService:        blockService,
+		contentPolicy:       contentPolicy,
+		contentFilter:       contentFilter,
+		supporters:          supporters,
+		profileCards:        profileCards,
+		timelineFanout:      timelineFanout,
+		profileVisitors:     profileVisitors,
+		media:               media,
+		settingsRepo:        settingsRepo,
+		presence:            presence,
+		searchIndex:         searchIndex,
 		log:                 log,
 	}
 }
 
 // GetUserProfile ユーザープロフィール取得ハンドラー
+// @Summary ユーザープロフィール取得
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Success 200 {object} response.Response
+// @Success 301 "Moved Permanently"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username} [get]
 func (h *UserHandler) GetUserProfile(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
@@ -52,22 +105,64 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 
 	// ユーザーをユーザー名で検索
 	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil && h.redirectMergedUsername(c, username) {
+		return
+	}
 	if err != nil {
 		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
 		response.NotFound(c, "ユーザーが見つかりません")
 		return
 	}
 
-	// 現在のユーザーがフォローしているかどうかを確認
+	// 現在のユーザーがフォローしているかどうかと、フォローを開始した日時を確認
 	isFollowing := false
+	var followedSince *time.Time
+	var viewerID uuid.UUID
 	if currentUserIDStr, exists := c.Get("userID"); exists {
 		currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+		if err == nil {
+			viewerID = currentUserID
+		}
 		if err == nil && currentUserID != user.ID {
-			isFollowing, err = h.followRepo.IsFollowing(c, currentUserID, user.ID)
+			// ブロック関係にある場合はプロフィールを表示しない
+			if err := h.blockService.CheckInteraction(c, currentUserID, user.ID); err != nil {
+				if errors.Is(err, service.ErrBlocked) {
+					response.Forbidden(c, "このユーザーのプロフィールは表示できません")
+					return
+				}
+				h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+				response.InternalServerError(c, "プロフィールの取得中にエラーが発生しました")
+				return
+			}
+
+			followedSince, err = h.followRepo.GetFollowedSince(c, currentUserID, user.ID)
 			if err != nil {
 				h.log.Error("フォロー状態の確認中にエラーが発生しました", "error", err)
 				// エラーがあってもプロフィール表示は続行
 			}
+			isFollowing = followedSince != nil
+
+			// 両方が有効にしている場合のみ訪問者として記録される
+			h.profileVisitors.RecordVisit(user.ID, currentUserID)
+		}
+	}
+
+	// プロフィールのカスタマイズ（取得できない場合はデフォルトの見た目で表示する）
+	settings, err := h.settingsRepo.Get(c, user.ID)
+	if err != nil {
+		h.log.Error("プロフィールのカスタマイズの取得中にエラーが発生しました", "error", err, "user_id", user.ID)
+		settings = models.NewUserSettings(user.ID)
+	}
+
+	// オンライン状態と最終ログイン日時（本人が非表示にしている場合と、取得できない場合は返さない）
+	var isOnline *bool
+	var lastSeenAt *time.Time
+	if settings.PresenceVisibleTo(viewerID) {
+		if presence, err := h.presence.Get(c, user.ID); err != nil {
+			h.log.Error("オンライン状態の取得中にエラーが発生しました", "error", err, "user_id", user.ID)
+		} else {
+			isOnline = &presence.IsOnline
+			lastSeenAt = presence.LastSeenAt
 		}
 	}
 
@@ -82,11 +177,179 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 		"location":        user.Location,
 		"website_url":     user.WebsiteURL,
 		"verified":        user.IsVerified,
+		"is_supporter":    user.IsSupporter(),
+		"is_system":       user.IsSystem,
 		"created_at":      user.CreatedAt,
 		"followers_count": user.FollowerCount,
 		"following_count": user.FollowingCount,
 		"posts_count":     user.PostCount,
 		"is_following":    isFollowing,
+		"followed_since":  followedSince,
+		"theme":           settings.ProfileTheme(),
+		"is_online":       isOnline,
+		"last_seen_at":    lastSeenAt,
+		"likes_visible":   settings.LikesVisibleTo(viewerID),
+	})
+}
+
+// GetFollowerChurn 自分のフォロワーの日ごとの増減（フォローされた数・フォロー解除された数）を取得するハンドラー
+// @Summary 自分のフォロワーの日ごとの増減（フォローされた数・フォロー解除された数）を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param to query string false "期間の終了日（YYYY-MM-DD）"
+// @Param from query string false "期間の開始日（YYYY-MM-DD）"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/followers/churn [get]
+func (h *UserHandler) GetFollowerChurn(c *gin.Context) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 期間の取得（デフォルトは今日までの30日間）
+	from, to, ok := statsDateRange(c, time.Now().UTC())
+	if !ok {
+		return
+	}
+
+	days, err := h.followRepo.GetChurn(c.Request.Context(), &currentUserID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		h.log.Error("フォロワー数の推移の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロワー数の推移の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, followChurnResponse(from, to, days))
+}
+
+// GetStorageUsage 自分のメディアの使用量と上限を取得するハンドラー
+// @Summary 自分のメディアの使用量と上限を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/storage [get]
+func (h *UserHandler) GetStorageUsage(c *gin.Context) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	usage, err := h.media.StorageUsage(c.Request.Context(), currentUserID)
+	if err != nil {
+		h.log.Error("メディアの使用量の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "使用量の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"usage":           usage,
+		"remaining_bytes": usage.RemainingBytes(),
+		"unlimited":       usage.QuotaBytes <= 0,
+	})
+}
+
+// GetProfileVisitors 自分のプロフィールの最近の訪問者を取得するハンドラー
+// 自分と訪問者の両方がプロフィール訪問者の表示を有効にしている場合のみ表示される
+// @Summary 自分のプロフィールの最近の訪問者を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param limit query integer false "取得する件数" default(50)
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/visitors [get]
+func (h *UserHandler) GetProfileVisitors(c *gin.Context) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	visits, err := h.profileVisitors.Visitors(c.Request.Context(), currentUserID, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrProfileVisitorsDisabled) {
+			response.Forbidden(c, "プロフィール訪問者を見るには設定で有効にしてください")
+			return
+		}
+		h.log.Error("訪問者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "訪問者の取得中にエラーが発生しました")
+		return
+	}
+
+	visitorIDs := make([]uuid.UUID, 0, len(visits))
+	for _, visit := range visits {
+		visitorIDs = append(visitorIDs, visit.VisitorID)
+	}
+
+	// ブロック関係にあるユーザーは一覧に含めない
+	visitorIDs, err = h.blockService.FilterUserIDs(c.Request.Context(), currentUserID, visitorIDs)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "訪問者の取得中にエラーが発生しました")
+		return
+	}
+
+	// ユーザー情報をまとめて取得
+	users, err := h.userRepo.GetByIDs(c.Request.Context(), visitorIDs)
+	if err != nil {
+		h.log.Error("訪問者情報取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "訪問者の取得中にエラーが発生しました")
+		return
+	}
+
+	visitorsResponse := make([]gin.H, 0, len(visits))
+	for _, visit := range visits {
+		visitor, ok := users[visit.VisitorID]
+		if !ok {
+			continue
+		}
+
+		visitorsResponse = append(visitorsResponse, gin.H{
+			"id":           visitor.ID,
+			"username":     visitor.Username,
+			"display_name": visitor.Name,
+			"avatar_url":   visitor.ProfileImage,
+			"is_supporter": visitor.IsSupporter(),
+			"visited_at":   visit.VisitedAt,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"visitors": visitorsResponse,
 	})
 }
 
@@ -96,9 +359,24 @@ type UpdateProfileRequest struct {
 	Bio         string `json:"bio" binding:"omitempty,max=160"`
 	Location    string `json:"location" binding:"omitempty,max=30"`
 	WebsiteURL  string `json:"website_url" binding:"omitempty,max=100,url"`
+	// 変更すると年齢確認は取り消される
+	CountryCode string `json:"country_code" binding:"omitempty,len=2,alpha"`
 }
 
 // UpdateProfile プロフィール更新ハンドラー
+// @Summary プロフィール更新
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me [put]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	var req UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -151,13 +429,30 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		updated = true
 	}
 
-	// 変更があれば更新
+	// 国コードは年齢制限の判定に使用するため大文字で保存
+	// 国によって最低年齢が異なるため、変更した場合は年齢確認を取り消す（管理者による再度の確認が必要）
+	if countryCode := strings.ToUpper(req.CountryCode); countryCode != "" && countryCode != user.CountryCode {
+		user.CountryCode = countryCode
+		user.IsAgeVerified = false
+		user.BirthDate = nil
+		updated = true
+	}
+
+	// 変更があれば禁止語ルールで審査してから更新する（プロフィールにはレーティングがないためラベルのルールは無視する）
 	if updated {
+		screen, ok := screenContent(c, h.contentFilter, h.log, user.Name, user.Bio, user.Location, user.WebsiteURL)
+		if !ok {
+			return
+		}
+
 		if err := h.userRepo.Update(c, user); err != nil {
 			h.log.Error("ユーザー更新中にエラーが発生しました", "error", err)
 			response.InternalServerError(c, "プロフィールの更新中にエラーが発生しました")
 			return
 		}
+
+		flagContent(c, h.contentFilter, h.log, models.ReportTargetUser, user.ID, user.ID, screen)
+		h.searchIndex.IndexUser(user)
 	}
 
 	// 更新後のユーザー情報を返す
@@ -170,13 +465,71 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		"banner_url":   user.BannerImage,
 		"location":     user.Location,
 		"website_url":  user.WebsiteURL,
+		"country_code": user.CountryCode,
 		"verified":     user.IsVerified,
+		"is_supporter": user.IsSupporter(),
+		"age_verified": user.IsAgeVerified,
 		"created_at":   user.CreatedAt,
 		"updated_at":   user.UpdatedAt,
 	})
 }
 
+// DeactivateAccount アカウント無効化ハンドラー
+// プロフィール・投稿・フォロー関係をすべてのエンドポイントから隠す。猶予期間内に再度ログインするとアカウントを再開する
+// @Summary アカウント無効化
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/deactivate [post]
+func (h *UserHandler) DeactivateAccount(c *gin.Context) {
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	if err := h.userRepo.Deactivate(c, currentUserID); err != nil {
+		if err.Error() == "user not found" {
+			response.NotFound(c, "ユーザーが見つかりません")
+			return
+		}
+		h.log.Error("アカウントの無効化中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "アカウントの無効化中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"status": models.UserStatusDeactivated,
+	})
+}
+
 // GetFollowers フォロワー一覧取得ハンドラー
+// @Summary フォロワー一覧取得
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Success 301 "Moved Permanently"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/followers [get]
 func (h *UserHandler) GetFollowers(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
@@ -199,14 +552,23 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 
 	// ユーザーをユーザー名で検索
 	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil && h.redirectMergedUsername(c, username) {
+		return
+	}
 	if err != nil {
 		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
 		response.NotFound(c, "ユーザーが見つかりません")
 		return
 	}
 
-	// ユーザーのフォロワーを取得
-	followerIDs, err := h.followRepo.GetFollowers(c.Request.Context(), user.ID, offset, perPage)
+	// 現在のユーザーID（認証済みの場合）
+	var currentUserID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
+	}
+
+	// ユーザーのフォロワーを取得（ブロック関係にあるユーザーは一覧・総数に含めない）
+	followerIDs, err := h.followRepo.GetFollowers(c.Request.Context(), user.ID, currentUserID, offset, perPage)
 	if err != nil {
 		h.log.Error("フォロワー取得中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "フォロワーの取得中にエラーが発生しました")
@@ -214,27 +576,27 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 	}
 
 	// フォロワーの総数を取得
-	totalFollowers, err := h.followRepo.CountFollowers(c.Request.Context(), user.ID)
+	totalFollowers, err := h.followRepo.CountFollowers(c.Request.Context(), user.ID, currentUserID)
 	if err != nil {
 		h.log.Error("フォロワー数取得中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "フォロワーの取得中にエラーが発生しました")
 		return
 	}
 
-	// 現在のユーザーID（認証済みの場合）
-	var currentUserID uuid.UUID
-	currentUserIDInterface, exists := c.Get("userID")
-	if exists {
-		currentUserID = currentUserIDInterface.(uuid.UUID)
+	// ユーザー情報をまとめて取得
+	users, err := h.userRepo.GetByIDs(c.Request.Context(), followerIDs)
+	if err != nil {
+		h.log.Error("フォロワー情報取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロワーの取得中にエラーが発生しました")
+		return
 	}
 
 	// フォロワーのレスポンスを作成
 	followersResponse := make([]gin.H, 0, len(followerIDs))
 	for _, followerID := range followerIDs {
 		// ユーザー情報を取得
-		follower, err := h.userRepo.GetByID(c.Request.Context(), followerID)
-		if err != nil {
-			h.log.Error("フォロワー情報取得中にエラーが発生しました", "error", err, "followerID", followerID)
+		follower, ok := users[followerID]
+		if !ok {
 			continue
 		}
 
@@ -249,6 +611,7 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 			"username":     follower.Username,
 			"display_name": follower.Name,
 			"avatar_url":   follower.ProfileImage,
+			"is_supporter": follower.IsSupporter(),
 			"bio":          follower.Bio,
 			"is_following": isFollowing,
 		})
@@ -271,7 +634,136 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 	})
 }
 
+// GetMutualFollowers ユーザーのフォロワーのうち、自分がフォローしているユーザーの一覧を取得するハンドラー
+// プロフィールの「○○さんと△△さんがフォローしています」の表示に使う
+// @Summary 自分もフォローしているフォロワーの一覧を取得
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/followers/mutual [get]
+func (h *UserHandler) GetMutualFollowers(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// ページネーションパラメータの取得
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	offset := (page - 1) * perPage
+
+	// ユーザーをユーザー名で検索
+	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	// フォロワーとフォロー中のユーザーの共通部分を1つのクエリで取得（ブロック関係にあるユーザーは含めない）
+	followerIDs, err := h.followRepo.GetMutualFollowers(c.Request.Context(), user.ID, currentUserID, offset, perPage)
+	if err != nil {
+		h.log.Error("共通のフォロワー取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロワーの取得中にエラーが発生しました")
+		return
+	}
+
+	total, err := h.followRepo.CountMutualFollowers(c.Request.Context(), user.ID, currentUserID)
+	if err != nil {
+		h.log.Error("共通のフォロワー数取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロワーの取得中にエラーが発生しました")
+		return
+	}
+
+	// ユーザー情報をまとめて取得
+	users, err := h.userRepo.GetByIDs(c.Request.Context(), followerIDs)
+	if err != nil {
+		h.log.Error("フォロワー情報取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロワーの取得中にエラーが発生しました")
+		return
+	}
+
+	followersResponse := make([]gin.H, 0, len(followerIDs))
+	for _, followerID := range followerIDs {
+		follower, ok := users[followerID]
+		if !ok {
+			continue
+		}
+
+		// 共通のフォロワーは自分がフォローしているユーザーのみ
+		followersResponse = append(followersResponse, gin.H{
+			"id":           follower.ID,
+			"username":     follower.Username,
+			"display_name": follower.Name,
+			"avatar_url":   follower.ProfileImage,
+			"is_supporter": follower.IsSupporter(),
+			"bio":          follower.Bio,
+			"is_following": true,
+		})
+	}
+
+	totalPages := int(total) / perPage
+	if int(total)%perPage > 0 {
+		totalPages++
+	}
+
+	response.Success(c, gin.H{
+		"users": followersResponse,
+		"pagination": gin.H{
+			"total":       total,
+			"page":        page,
+			"per_page":    perPage,
+			"total_pages": totalPages,
+		},
+	})
+}
+
 // GetFollowing フォロー中ユーザー一覧取得ハンドラー
+// @Summary フォロー中ユーザー一覧取得
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Success 301 "Moved Permanently"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/following [get]
 func (h *UserHandler) GetFollowing(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
@@ -294,14 +786,23 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 
 	// ユーザーをユーザー名で検索
 	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil && h.redirectMergedUsername(c, username) {
+		return
+	}
 	if err != nil {
 		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
 		response.NotFound(c, "ユーザーが見つかりません")
 		return
 	}
 
-	// ユーザーがフォローしているユーザーを取得
-	followingIDs, err := h.followRepo.GetFollowing(c.Request.Context(), user.ID, offset, perPage)
+	// 現在のユーザーID（認証済みの場合）
+	var currentUserID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
+	}
+
+	// ユーザーがフォローしているユーザーを取得（ブロック関係にあるユーザーは一覧・総数に含めない）
+	followingIDs, err := h.followRepo.GetFollowing(c.Request.Context(), user.ID, currentUserID, offset, perPage)
 	if err != nil {
 		h.log.Error("フォロー中ユーザー取得中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "フォロー中ユーザーの取得中にエラーが発生しました")
@@ -309,27 +810,27 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 	}
 
 	// フォロー中ユーザーの総数を取得
-	totalFollowing, err := h.followRepo.CountFollowing(c.Request.Context(), user.ID)
+	totalFollowing, err := h.followRepo.CountFollowing(c.Request.Context(), user.ID, currentUserID)
 	if err != nil {
 		h.log.Error("フォロー中ユーザー数取得中にエラーが発生しました", "error", err)
 		response.InternalServerError(c, "フォロー中ユーザーの取得中にエラーが発生しました")
 		return
 	}
 
-	// 現在のユーザーID（認証済みの場合）
-	var currentUserID uuid.UUID
-	currentUserIDInterface, exists := c.Get("userID")
-	if exists {
-		currentUserID = currentUserIDInterface.(uuid.UUID)
+	// ユーザー情報をまとめて取得
+	users, err := h.userRepo.GetByIDs(c.Request.Context(), followingIDs)
+	if err != nil {
+		h.log.Error("フォロー中ユーザー情報取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロー中ユーザーの取得中にエラーが発生しました")
+		return
 	}
 
 	// フォロー中ユーザーのレスポンスを作成
 	followingResponse := make([]gin.H, 0, len(followingIDs))
 	for _, followingID := range followingIDs {
 		// ユーザー情報を取得
-		followedUser, err := h.userRepo.GetByID(c.Request.Context(), followingID)
-		if err != nil {
-			h.log.Error("フォロー中ユーザー情報取得中にエラーが発生しました", "error", err, "followingID", followingID)
+		followedUser, ok := users[followingID]
+		if !ok {
 			continue
 		}
 
@@ -344,6 +845,7 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 			"username":     followedUser.Username,
 			"display_name": followedUser.Name,
 			"avatar_url":   followedUser.ProfileImage,
+			"is_supporter": followedUser.IsSupporter(),
 			"bio":          followedUser.Bio,
 			"is_following": isFollowing,
 		})
@@ -367,6 +869,18 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 }
 
 // FollowUser ユーザーをフォローするハンドラー
+// @Summary ユーザーをフォローする
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/follow [post]
 func (h *UserHandler) FollowUser(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
@@ -402,6 +916,23 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 		return
 	}
 
+	// システムアカウントの投稿は全ユーザーのタイムラインに表示されるため、フォローできない
+	if targetUser.IsSystem {
+		response.BadRequest(c, "システムアカウントはフォローできません", nil)
+		return
+	}
+
+	// ブロック関係にある場合はフォローできない
+	if err := h.blockService.CheckInteraction(c, currentUserID, targetUser.ID); err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			response.Forbidden(c, "このユーザーをフォローすることはできません")
+			return
+		}
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロー情報の確認中にエラーが発生しました")
+		return
+	}
+
 	// 既にフォローしているかどうかを確認
 	isFollowing, err := h.followRepo.IsFollowing(c, currentUserID, targetUser.ID)
 	if err != nil {
@@ -424,13 +955,8 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 		return
 	}
 
-	// フォロワー数を更新
-	targetUser.FollowerCount++
-	err = h.userRepo.Update(c.Request.Context(), targetUser)
-	if err != nil {
-		h.log.Error("ユーザー更新中にエラーが発生しました", "error", err)
-		// エラーがあってもレスポンスは返す
-	}
+	// フォロー中のユーザーが変わったため、ホームタイムラインのキャッシュを作り直す
+	h.timelineFanout.Invalidate(c.Request.Context(), currentUserID)
 
 	// 通知の作成
 	if h.notificationService != nil {
@@ -447,11 +973,22 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 
 	response.Success(c, gin.H{
 		"following":       true,
-		"followers_count": targetUser.FollowerCount,
+		"followers_count": h.currentFollowerCount(c, targetUser),
 	})
 }
 
 // UnfollowUser ユーザーのフォローを解除するハンドラー
+// @Summary ユーザーのフォローを解除する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/follow [delete]
 func (h *UserHandler) UnfollowUser(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
@@ -495,36 +1032,270 @@ func (h *UserHandler) UnfollowUser(c *gin.Context) {
 		return
 	}
 
-	// フォロワー数を更新
-	if targetUser.FollowerCount > 0 {
-		targetUser.FollowerCount--
-		err = h.userRepo.Update(c.Request.Context(), targetUser)
-		if err != nil {
-			h.log.Error("ユーザー更新中にエラーが発生しました", "error", err)
-			// エラーがあってもレスポンスは返す
-		}
-	}
+	// フォロー中のユーザーが変わったため、ホームタイムラインのキャッシュを作り直す
+	h.timelineFanout.Invalidate(c.Request.Context(), currentUserID)
 
 	response.Success(c, gin.H{
 		"following":       false,
-		"followers_count": targetUser.FollowerCount,
+		"followers_count": h.currentFollowerCount(c, targetUser),
 	})
 }
 
-// GetUserPosts ユーザーの投稿一覧取得ハンドラー
-func (h *UserHandler) GetUserPosts(c *gin.Context) {
+// RemoveFollower 自分のフォロワーを削除する（相手のフォローを解除させる）ハンドラー
+// 相手には通知せず、相手は再びフォローできる（フォローさせたくない場合はブロックする）
+// @Summary 自分のフォロワーを削除する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "削除するフォロワーのユーザー名"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/followers/{username} [delete]
+func (h *UserHandler) RemoveFollower(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
 		response.BadRequest(c, "ユーザー名が必要です", nil)
 		return
 	}
 
-	// ページネーションパラメータの取得
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if page < 1 {
-		page = 1
-	}
-
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// 削除するフォロワーを取得
+	follower, err := h.userRepo.GetByUsername(c, username)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	if currentUserID == follower.ID {
+		response.BadRequest(c, "自分自身をフォロワーから削除することはできません", nil)
+		return
+	}
+
+	// 相手から自分へのフォロー関係を削除（フォロワー数・フォロー数も同じトランザクションで更新される）
+	err = h.followRepo.Unfollow(c.Request.Context(), follower.ID, currentUserID)
+	if err != nil {
+		if err.Error() == "follow relationship not found" {
+			response.NotFound(c, "このユーザーはフォロワーではありません")
+			return
+		}
+		h.log.Error("フォロワーの削除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "フォロワーの削除中にエラーが発生しました")
+		return
+	}
+
+	// 相手のフォロー中のユーザーが変わったため、相手のホームタイムラインのキャッシュを作り直す
+	h.timelineFanout.Invalidate(c.Request.Context(), follower.ID)
+
+	me, err := h.userRepo.GetByID(c.Request.Context(), currentUserID)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"removed":         true,
+		"followers_count": me.FollowerCount,
+	})
+}
+
+// currentFollowerCount フォロー・フォロー解除を反映したユーザーのフォロワー数を返す
+func (h *UserHandler) currentFollowerCount(c *gin.Context, user *models.User) int {
+	// フォロー・フォロー解除と同時に更新されたフォロワー数を取得し直す
+	if updated, err := h.userRepo.GetByID(c.Request.Context(), user.ID); err == nil {
+		user = updated
+	}
+	return user.FollowerCount
+}
+
+// BlockUser ユーザーをブロックするハンドラー
+// @Summary ユーザーをブロックする
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/block [post]
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// ブロックするユーザーを取得
+	targetUser, err := h.userRepo.GetByUsername(c, username)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	// 自分自身をブロックしようとしている場合
+	if currentUserID == targetUser.ID {
+		response.BadRequest(c, "自分自身をブロックすることはできません", nil)
+		return
+	}
+
+	// 既にブロックしているかどうかを確認
+	isBlocking, err := h.blockService.IsBlocking(c, currentUserID, targetUser.ID)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ブロック情報の確認中にエラーが発生しました")
+		return
+	}
+
+	if isBlocking {
+		response.BadRequest(c, "既にブロックしています", nil)
+		return
+	}
+
+	// ブロックを作成（フォロー関係も解除される）
+	if err := h.blockService.BlockUser(c.Request.Context(), currentUserID, targetUser.ID); err != nil {
+		h.log.Error("ブロック作成中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ブロック処理中にエラーが発生しました")
+		return
+	}
+
+	// 双方のフォロー中のユーザーが変わり、互いの投稿も表示しなくなるため、双方のホームタイムラインのキャッシュを作り直す
+	h.timelineFanout.Invalidate(c.Request.Context(), currentUserID)
+	h.timelineFanout.Invalidate(c.Request.Context(), targetUser.ID)
+
+	response.Success(c, gin.H{
+		"blocking": true,
+	})
+}
+
+// UnblockUser ユーザーのブロックを解除するハンドラー
+// @Summary ユーザーのブロックを解除する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/block [delete]
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	// 現在のユーザーIDを取得
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return
+	}
+
+	// ブロック解除するユーザーを取得
+	targetUser, err := h.userRepo.GetByUsername(c, username)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	// ブロックしているかどうかを確認
+	isBlocking, err := h.blockService.IsBlocking(c, currentUserID, targetUser.ID)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ブロック情報の確認中にエラーが発生しました")
+		return
+	}
+
+	if !isBlocking {
+		response.BadRequest(c, "ブロックしていません", nil)
+		return
+	}
+
+	// ブロックを削除
+	if err := h.blockService.UnblockUser(c.Request.Context(), currentUserID, targetUser.ID); err != nil {
+		h.log.Error("ブロック解除中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ブロック解除処理中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"blocking": false,
+	})
+}
+
+// GetUserPosts ユーザーの投稿一覧取得ハンドラー
+// @Summary ユーザーの投稿一覧取得
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Success 301 "Moved Permanently"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/posts [get]
+func (h *UserHandler) GetUserPosts(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	// ページネーションパラメータの取得
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
 	if perPage < 1 || perPage > 100 {
 		perPage = 20
@@ -534,12 +1305,30 @@ func (h *UserHandler) GetUserPosts(c *gin.Context) {
 
 	// ユーザーをユーザー名で検索
 	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil && h.redirectMergedUsername(c, username) {
+		return
+	}
 	if err != nil {
 		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
 		response.NotFound(c, "ユーザーが見つかりません")
 		return
 	}
 
+	// ブロック関係にある場合は投稿を表示しない
+	var currentUserID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
+		if err := h.blockService.CheckInteraction(c, currentUserID, user.ID); err != nil {
+			if errors.Is(err, service.ErrBlocked) {
+				response.Forbidden(c, "このユーザーの投稿は表示できません")
+				return
+			}
+			h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "投稿の取得中にエラーが発生しました")
+			return
+		}
+	}
+
 	// ユーザーの投稿を取得
 	posts, err := h.postRepo.GetByUserID(c, user.ID, offset, perPage)
 	if err != nil {
@@ -556,26 +1345,54 @@ func (h *UserHandler) GetUserPosts(c *gin.Context) {
 		totalPosts = int64(len(posts))
 	}
 
+	// 年齢制限のある投稿を除外
+	viewer, err := h.contentPolicy.LoadViewer(c, currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の取得中にエラーが発生しました")
+		return
+	}
+	posts, hiddenCount := h.contentPolicy.FilterPosts(viewer, posts)
+
+	// いいねとリアクションの状態をまとめて取得
+	hydrated, err := hydratePosts(c, h.userRepo, h.postRepo, h.likeRepo, h.reactionRepo, h.media, currentUserID, posts)
+	if err != nil {
+		h.log.Error("投稿の関連データ取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "投稿の取得中にエラーが発生しました")
+		return
+	}
+
 	// 投稿のレスポンスを作成
 	postsResponse := make([]gin.H, 0, len(posts))
 	for _, post := range posts {
 		postsResponse = append(postsResponse, gin.H{
-			"id":            post.ID,
-			"user_id":       post.UserID,
-			"content":       post.Content,
-			"media_urls":    post.MediaURLs,
-			"created_at":    post.CreatedAt,
-			"likes_count":   post.LikeCount,
-			"replies_count": post.ReplyCount,
-			"reposts_count": post.RepostCount,
+			"id":              post.ID,
+			"user_id":         post.UserID,
+			"content":         post.Content,
+			"media_urls":      post.MediaURLs,
+			"media":           hydrated.mediaAttachments(post.ID),
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"created_at":      post.CreatedAt,
+			"likes_count":     post.LikeCount,
+			"views_count":     post.ViewCount,
+			"shares_count":    post.ShareCount,
+			"sharing_enabled": post.SharingEnabled,
+			"replies_count":   post.ReplyCount,
+			"reposts_count":   post.RepostCount,
 			"user": gin.H{
 				"id":           user.ID,
 				"username":     user.Username,
 				"display_name": user.Name,
 				"avatar_url":   user.ProfileImage,
+				"is_supporter": user.IsSupporter(),
 			},
-			"is_liked":    false, // TODO: 現在のユーザーがいいねしているかどうかを確認
-			"is_reposted": false, // TODO: 現在のユーザーがリポストしているかどうかを確認
+			"is_liked":     hydrated.liked[post.ID],
+			"reactions":    hydrated.reactionCounts(post.ID),
+			"my_reactions": hydrated.viewerReactions(post.ID),
+			"is_reposted":  false, // TODO: 現在のユーザーがリポストしているかどうかを確認
 		})
 	}
 
@@ -586,7 +1403,8 @@ func (h *UserHandler) GetUserPosts(c *gin.Context) {
 	}
 
 	response.Success(c, gin.H{
-		"posts": postsResponse,
+		"posts":          postsResponse,
+		"content_filter": contentFilterMeta(hiddenCount),
 		"pagination": gin.H{
 			"total":       totalPosts,
 			"page":        page,
@@ -596,7 +1414,342 @@ func (h *UserHandler) GetUserPosts(c *gin.Context) {
 	})
 }
 
+// GetLikedPosts ユーザーがいいねした投稿の一覧を取得するハンドラー
+// 本人がいいねを非公開にしている場合は本人以外には返さない
+// @Summary ユーザーがいいねした投稿の一覧を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Success 301 "Moved Permanently"
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/likes [get]
+func (h *UserHandler) GetLikedPosts(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	// ページネーションパラメータの取得
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	offset := (page - 1) * perPage
+
+	// ユーザーをユーザー名で検索
+	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil && h.redirectMergedUsername(c, username) {
+		return
+	}
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	// ブロック関係にある場合はいいねを表示しない
+	var currentUserID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
+		if err := h.blockService.CheckInteraction(c, currentUserID, user.ID); err != nil {
+			if errors.Is(err, service.ErrBlocked) {
+				response.Forbidden(c, "このユーザーのいいねは表示できません")
+				return
+			}
+			h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+			return
+		}
+	}
+
+	// 本人がいいねを公開しているかを確認
+	settings, err := h.settingsRepo.Get(c, user.ID)
+	if err != nil {
+		h.log.Error("設定の取得中にエラーが発生しました", "error", err, "user_id", user.ID)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+	if !settings.LikesVisibleTo(currentUserID) {
+		response.Forbidden(c, "このユーザーはいいねを公開していません")
+		return
+	}
+
+	// いいねした投稿を取得
+	posts, err := h.postRepo.GetLikedByUserID(c, user.ID, offset, perPage)
+	if err != nil {
+		h.log.Error("いいねした投稿の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	totalPosts, err := h.postRepo.CountLikedByUserID(c, user.ID)
+	if err != nil {
+		h.log.Error("いいねした投稿数の取得中にエラーが発生しました", "error", err)
+		// エラーがあっても処理は続行
+		totalPosts = int64(len(posts))
+	}
+
+	// 閲覧者とブロック関係にあるユーザーの投稿を除外
+	posts, err = h.blockService.FilterPosts(c, currentUserID, posts)
+	if err != nil {
+		h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	// 年齢制限のある投稿を除外
+	viewer, err := h.contentPolicy.LoadViewer(c, currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+	posts, hiddenCount := h.contentPolicy.FilterPosts(viewer, posts)
+
+	// 投稿者・いいねとリアクションの状態をまとめて取得
+	hydrated, err := hydratePosts(c, h.userRepo, h.postRepo, h.likeRepo, h.reactionRepo, h.media, currentUserID, posts)
+	if err != nil {
+		h.log.Error("投稿の関連データ取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "いいねの取得中にエラーが発生しました")
+		return
+	}
+
+	// 投稿のレスポンスを作成
+	postsResponse := make([]gin.H, 0, len(posts))
+	for _, post := range posts {
+		author, ok := hydrated.users[post.UserID]
+		if !ok {
+			h.log.Error("投稿ユーザーが見つかりません", "userID", post.UserID)
+			continue
+		}
+
+		postsResponse = append(postsResponse, gin.H{
+			"id":              post.ID,
+			"user_id":         post.UserID,
+			"content":         post.Content,
+			"media_urls":      post.MediaURLs,
+			"media":           hydrated.mediaAttachments(post.ID),
+			"content_rating":  post.ContentRating,
+			"content_warning": post.ContentWarning,
+			"expires_at":      post.ExpiresAt,
+			"expiring":        post.IsExpiring(),
+			"created_at":      post.CreatedAt,
+			"likes_count":     post.LikeCount,
+			"views_count":     post.ViewCount,
+			"shares_count":    post.ShareCount,
+			"sharing_enabled": post.SharingEnabled,
+			"replies_count":   post.ReplyCount,
+			"reposts_count":   post.RepostCount,
+			"user": gin.H{
+				"id":           author.ID,
+				"username":     author.Username,
+				"display_name": author.Name,
+				"avatar_url":   author.ProfileImage,
+				"is_supporter": author.IsSupporter(),
+			},
+			"is_liked":     hydrated.liked[post.ID],
+			"reactions":    hydrated.reactionCounts(post.ID),
+			"my_reactions": hydrated.viewerReactions(post.ID),
+			"is_reposted":  false, // TODO: 現在のユーザーがリポストしているかどうかを確認
+		})
+	}
+
+	// ページネーション情報を含むレスポンスを返す
+	totalPages := int(totalPosts) / perPage
+	if int(totalPosts)%perPage > 0 {
+		totalPages++
+	}
+
+	response.Success(c, gin.H{
+		"posts":          postsResponse,
+		"content_filter": contentFilterMeta(hiddenCount),
+		"pagination": gin.H{
+			"total":       totalPosts,
+			"page":        page,
+			"per_page":    perPage,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+// GetRepliesBetween 2人のユーザーが互いに送った返信（やり取り）を取得するハンドラー
+// モデレーションや会話の前後関係の確認に使う
+// @Summary 2人のユーザーが互いに送った返信（やり取り）を取得する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "ユーザー名"
+// @Param other path string true "相手のユーザー名"
+// @Param page query integer false "ページ番号" default(1)
+// @Param per_page query integer false "1ページあたりの件数" default(20)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/replies/{other} [get]
+func (h *UserHandler) GetRepliesBetween(c *gin.Context) {
+	username := c.Param("username")
+	otherUsername := c.Param("other")
+	if username == "" || otherUsername == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	// ページネーションパラメータの取得
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	offset := (page - 1) * perPage
+
+	// 2人のユーザーをユーザー名で検索
+	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	other, err := h.userRepo.GetByUsername(c, otherUsername)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	if user.ID == other.ID {
+		response.BadRequest(c, "異なる2人のユーザーを指定してください", nil)
+		return
+	}
+
+	// どちらかのユーザーとブロック関係にある場合は表示しない
+	var currentUserID uuid.UUID
+	if currentUserIDStr, exists := c.Get("userID"); exists {
+		currentUserID, _ = uuid.Parse(currentUserIDStr.(string))
+		for _, target := range []uuid.UUID{user.ID, other.ID} {
+			if err := h.blockService.CheckInteraction(c, currentUserID, target); err != nil {
+				if errors.Is(err, service.ErrBlocked) {
+					response.Forbidden(c, "このユーザーの投稿は表示できません")
+					return
+				}
+				h.log.Error("ブロック状態の確認中にエラーが発生しました", "error", err)
+				response.InternalServerError(c, "返信の取得中にエラーが発生しました")
+				return
+			}
+		}
+	}
+
+	// 互いに送った返信を取得
+	replies, err := h.postRepo.GetRepliesBetween(c, user.ID, other.ID, offset, perPage)
+	if err != nil {
+		h.log.Error("返信取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "返信の取得中にエラーが発生しました")
+		return
+	}
+
+	// 返信の総数を取得
+	totalReplies, err := h.postRepo.CountRepliesBetween(c, user.ID, other.ID)
+	if err != nil {
+		h.log.Error("返信数の取得中にエラーが発生しました", "error", err)
+		// エラーがあっても処理は続行
+		totalReplies = int64(len(replies))
+	}
+
+	// 年齢制限のある返信を除外
+	viewer, err := h.contentPolicy.LoadViewer(c, currentUserID)
+	if err != nil {
+		h.log.Error("閲覧者の取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "返信の取得中にエラーが発生しました")
+		return
+	}
+	replies, hiddenCount := h.contentPolicy.FilterPosts(viewer, replies)
+
+	// 返信のレスポンスを作成（返信者は2人のどちらか）
+	users := map[uuid.UUID]*models.User{user.ID: user, other.ID: other}
+	media := h.media.Attachments(c, replies)
+	repliesResponse := make([]gin.H, 0, len(replies))
+	for _, reply := range replies {
+		author := users[reply.UserID]
+		repliesResponse = append(repliesResponse, gin.H{
+			"id":              reply.ID,
+			"user_id":         reply.UserID,
+			"content":         reply.Content,
+			"media_urls":      reply.MediaURLs,
+			"media":           media[reply.ID],
+			"reply_to_id":     reply.ReplyToID,
+			"content_rating":  reply.ContentRating,
+			"content_warning": reply.ContentWarning,
+			"expires_at":      reply.ExpiresAt,
+			"expiring":        reply.IsExpiring(),
+			"created_at":      reply.CreatedAt,
+			"likes_count":     reply.LikeCount,
+			"views_count":     reply.ViewCount,
+			"shares_count":    reply.ShareCount,
+			"replies_count":   reply.ReplyCount,
+			"user": gin.H{
+				"id":           author.ID,
+				"username":     author.Username,
+				"display_name": author.Name,
+				"avatar_url":   author.ProfileImage,
+				"is_supporter": author.IsSupporter(),
+			},
+		})
+	}
+
+	// ページネーション情報を含むレスポンスを返す
+	totalPages := int(totalReplies) / perPage
+	if int(totalReplies)%perPage > 0 {
+		totalPages++
+	}
+
+	response.Success(c, gin.H{
+		"replies":        repliesResponse,
+		"content_filter": contentFilterMeta(hiddenCount),
+		"pagination": gin.H{
+			"total":       totalReplies,
+			"page":        page,
+			"per_page":    perPage,
+			"total_pages": totalPages,
+		},
+	})
+}
+
 // UploadAvatar プロフィールアバター画像をアップロードするハンドラー
+// @Summary プロフィールアバター画像をアップロードする
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file true "アップロードするファイル"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/avatar [post]
 func (h *UserHandler) UploadAvatar(c *gin.Context) {
 	// リクエストからJWTのユーザーIDを取得
 	userIDStr, exists := c.Get("userID")
@@ -633,18 +1786,30 @@ func (h *UserHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
-	// ファイルサイズを検証 (2MB以下)
-	if header.Size > 2*1024*1024 {
-		response.BadRequest(c, "ファイルサイズが大きすぎます。2MB以下のファイルをアップロードしてください", nil)
+	// ファイルサイズを検証（サポーターは上限が高くなる）
+	if maxBytes := h.mediaQuota(c, userID).AvatarMaxBytes; header.Size > maxBytes {
+		response.BadRequest(c, fmt.Sprintf("ファイルサイズが大きすぎます。%dMB以下のファイルをアップロードしてください", maxBytes/(1024*1024)), nil)
+		return
+	}
+
+	// 置き換える前の画像（更新後に参照を外す）
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "プロフィールの更新に失敗しました")
 		return
 	}
+	previousURL := user.ProfileImage
 
-	// ストレージに保存するパスを生成
-	path := fmt.Sprintf("users/%s/avatar", userID.String())
+	// メディアの使用量に加える（上限を超える場合は保存しない）
+	if !reserveMediaStorage(c, h.media, h.log, userID, header.Size) {
+		return
+	}
 
-	// ストレージプロバイダを使ってファイルを保存
-	fileURL, err := h.storageProvider.SaveFile(c.Request.Context(), path, header.Filename, file, header.Size)
+	// ファイルを保存（同じ内容のファイルが既にある場合はそれを再利用する）
+	fileURL, err := h.media.Store(c.Request.Context(), userID, header.Filename, file)
 	if err != nil {
+		h.media.RefundStorage(c.Request.Context(), userID, header.Size)
 		h.log.Error("アバター画像の保存に失敗しました", "error", err)
 		response.InternalServerError(c, "ファイルの保存に失敗しました")
 		return
@@ -653,10 +1818,21 @@ func (h *UserHandler) UploadAvatar(c *gin.Context) {
 	// ユーザープロフィールのアバターURLを更新
 	if err := h.userRepo.UpdateAvatar(c.Request.Context(), userID, fileURL); err != nil {
 		h.log.Error("アバターURLの更新に失敗しました", "error", err)
+		// 保存したファイルへの参照を戻す
+		if err := h.media.ReleaseFor(c.Request.Context(), userID, fileURL, uuid.Nil); err != nil {
+			h.log.Warn("保存したファイルへの参照を外せませんでした", "error", err, "url", fileURL)
+		}
 		response.InternalServerError(c, "プロフィールの更新に失敗しました")
 		return
 	}
 
+	// 以前の画像への参照を外す（他のユーザーと共有していなければ削除される）
+	if previousURL != "" {
+		if err := h.media.ReleaseFor(c.Request.Context(), userID, previousURL, uuid.Nil); err != nil {
+			h.log.Warn("以前のアバター画像への参照を外せませんでした", "error", err, "url", previousURL)
+		}
+	}
+
 	response.Success(c, gin.H{
 		"message":    "アバター画像を正常にアップロードしました",
 		"avatar_url": fileURL,
@@ -664,6 +1840,18 @@ func (h *UserHandler) UploadAvatar(c *gin.Context) {
 }
 
 // UploadBanner プロフィールバナー画像をアップロードするハンドラー
+// @Summary プロフィールバナー画像をアップロードする
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param banner formData file true "アップロードするファイル"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/banner [post]
 func (h *UserHandler) UploadBanner(c *gin.Context) {
 	// リクエストからJWTのユーザーIDを取得
 	userIDStr, exists := c.Get("userID")
@@ -700,18 +1888,30 @@ func (h *UserHandler) UploadBanner(c *gin.Context) {
 		return
 	}
 
-	// ファイルサイズを検証 (5MB以下)
-	if header.Size > 5*1024*1024 {
-		response.BadRequest(c, "ファイルサイズが大きすぎます。5MB以下のファイルをアップロードしてください", nil)
+	// ファイルサイズを検証（サポーターは上限が高くなる）
+	if maxBytes := h.mediaQuota(c, userID).BannerMaxBytes; header.Size > maxBytes {
+		response.BadRequest(c, fmt.Sprintf("ファイルサイズが大きすぎます。%dMB以下のファイルをアップロードしてください", maxBytes/(1024*1024)), nil)
+		return
+	}
+
+	// 置き換える前の画像（更新後に参照を外す）
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "プロフィールの更新に失敗しました")
 		return
 	}
+	previousURL := user.BannerImage
 
-	// ストレージに保存するパスを生成
-	path := fmt.Sprintf("users/%s/banner", userID.String())
+	// メディアの使用量に加える（上限を超える場合は保存しない）
+	if !reserveMediaStorage(c, h.media, h.log, userID, header.Size) {
+		return
+	}
 
-	// ストレージプロバイダを使ってファイルを保存
-	fileURL, err := h.storageProvider.SaveFile(c.Request.Context(), path, header.Filename, file, header.Size)
+	// ファイルを保存（同じ内容のファイルが既にある場合はそれを再利用する）
+	fileURL, err := h.media.Store(c.Request.Context(), userID, header.Filename, file)
 	if err != nil {
+		h.media.RefundStorage(c.Request.Context(), userID, header.Size)
 		h.log.Error("バナー画像の保存に失敗しました", "error", err)
 		response.InternalServerError(c, "ファイルの保存に失敗しました")
 		return
@@ -720,16 +1920,99 @@ func (h *UserHandler) UploadBanner(c *gin.Context) {
 	// ユーザープロフィールのバナーURLを更新
 	if err := h.userRepo.UpdateBanner(c.Request.Context(), userID, fileURL); err != nil {
 		h.log.Error("バナーURLの更新に失敗しました", "error", err)
+		// 保存したファイルへの参照を戻す
+		if err := h.media.ReleaseFor(c.Request.Context(), userID, fileURL, uuid.Nil); err != nil {
+			h.log.Warn("保存したファイルへの参照を外せませんでした", "error", err, "url", fileURL)
+		}
 		response.InternalServerError(c, "プロフィールの更新に失敗しました")
 		return
 	}
 
+	// 以前の画像への参照を外す（他のユーザーと共有していなければ削除される）
+	if previousURL != "" {
+		if err := h.media.ReleaseFor(c.Request.Context(), userID, previousURL, uuid.Nil); err != nil {
+			h.log.Warn("以前のバナー画像への参照を外せませんでした", "error", err, "url", previousURL)
+		}
+	}
+
 	response.Success(c, gin.H{
 		"message":    "バナー画像を正常にアップロードしました",
 		"banner_url": fileURL,
 	})
 }
 
+// GetProfileCard プロフィール共有用のカード画像（PNG）を返すハンドラー
+// @Summary プロフィール共有用のカード画像（PNG）を返す
+// @Tags users
+// @Produce png
+// @Param username path string true "ユーザー名"
+// @Success 200 {file} binary
+// @Success 301 "Moved Permanently"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{username}/card.png [get]
+func (h *UserHandler) GetProfileCard(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		response.BadRequest(c, "ユーザー名が必要です", nil)
+		return
+	}
+
+	user, err := h.userRepo.GetByUsername(c, username)
+	if err != nil && h.redirectMergedUsername(c, username) {
+		return
+	}
+	if err != nil {
+		response.NotFound(c, "ユーザーが見つかりません")
+		return
+	}
+
+	card, err := h.profileCards.Card(c.Request.Context(), user)
+	if err != nil {
+		h.log.Error("プロフィールカードの生成中にエラーが発生しました", "error", err, "user_id", user.ID)
+		response.InternalServerError(c, "プロフィールカードの生成中にエラーが発生しました")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("ETag", card.ETag)
+	if c.GetHeader("If-None-Match") == card.ETag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", card.PNG)
+}
+
+// redirectMergedUsername 統合により転送されているユーザー名の場合は、統合先のユーザー名のURLへリダイレクトする
+// リダイレクトした場合はtrueを返す（GETのエンドポイントでのみ使用する）
+func (h *UserHandler) redirectMergedUsername(c *gin.Context, username string) bool {
+	user, err := h.userRepo.GetByRedirectedUsername(c, username)
+	if err != nil {
+		return false
+	}
+
+	location := strings.Replace(c.Request.URL.Path, "/users/"+username, "/users/"+user.Username, 1)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+	return true
+}
+
+// mediaQuota ユーザーのメディアアップロードの上限を返す
+// ユーザー情報を取得できない場合は通常の上限を使用する
+func (h *UserHandler) mediaQuota(c *gin.Context, userID uuid.UUID) service.MediaQuota {
+	user, err := h.userRepo.GetByID(c, userID)
+	if err != nil {
+		h.log.Error("ユーザー取得中にエラーが発生しました", "error", err)
+		user = nil
+	}
+	return h.supporters.MediaQuota(user)
+}
+
 // 画像ファイルの拡張子が有効かどうかを確認
 func isValidImageType(filename string) bool {
 	validExtensions := map[string]bool{
@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler ユーザーが自分宛てのイベントを受け取る個人用Webhookを管理するハンドラーを管理する構造体
+type WebhookHandler struct {
+	webhookRepo interfaces.WebhookRepository
+	webhooks    *service.WebhookService
+	log         logger.Logger
+}
+
+// NewWebhookHandler 新しい個人用Webhookハンドラーを作成する
+func NewWebhookHandler(
+	webhookRepo interfaces.WebhookRepository,
+	webhooks *service.WebhookService,
+	log logger.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+		webhooks:    webhooks,
+		log:         log,
+	}
+}
+
+// CreateWebhookRequest 個人用Webhookの登録リクエストの構造体
+type CreateWebhookRequest struct {
+	URL string `json:"url" binding:"required,url,max=2048"`
+	// 受け取るイベント（follow・mention）
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=follow mention"`
+}
+
+// UpdateWebhookRequest 個人用Webhookの更新リクエストの構造体（指定した項目のみ変更する）
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url" binding:"omitempty,url,max=2048"`
+	Events []string `json:"events" binding:"omitempty,min=1,dive,oneof=follow mention"`
+	// 連続した送信の失敗で無効になったWebhookを再び有効にする場合はtrue
+	Active *bool `json:"active"`
+}
+
+// ListWebhooks 自分の個人用Webhookの一覧を取得するハンドラー（シークレットは含めない）
+// @Summary 自分の個人用Webhookの一覧を取得する（シークレットは含めない）
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookRepo.ListByUser(c, currentUserID)
+	if err != nil {
+		h.log.Error("Webhookの取得中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "Webhookの取得中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"webhooks": webhooks,
+		"events":   models.WebhookEvents,
+	})
+}
+
+// CreateWebhook 個人用Webhookを登録するハンドラー
+// 署名用のシークレットはこのレスポンスでのみ返す
+// @Summary 個人用Webhookを登録する
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateWebhookRequest true "リクエストの内容"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	webhook, err := h.webhooks.Register(c, currentUserID, req.URL, webhookEvents(req.Events))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWebhookURL):
+			response.BadRequest(c, "WebhookのURLはhttpまたはhttpsのURLを指定してください", nil)
+		case errors.Is(err, service.ErrWebhookLimitReached):
+			response.BadRequest(c, "登録できるWebhookの上限に達しています", nil)
+		default:
+			h.log.Error("Webhookの登録中にエラーが発生しました", "error", err)
+			response.InternalServerError(c, "Webhookの登録中にエラーが発生しました")
+		}
+		return
+	}
+
+	response.Created(c, gin.H{
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	})
+}
+
+// UpdateWebhook 個人用WebhookのURL・イベント・有効状態を変更するハンドラー
+// @Summary 個人用WebhookのURL・イベント・有効状態を変更する
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body UpdateWebhookRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	webhook, ok := h.targetWebhook(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		webhook.Events = webhookEvents(req.Events)
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := h.webhooks.Update(c, webhook); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWebhookURL):
+			response.BadRequest(c, "WebhookのURLはhttpまたはhttpsのURLを指定してください", nil)
+		case err.Error() == "webhook not found":
+			response.NotFound(c, "Webhookが見つかりません")
+		default:
+			h.log.Error("Webhookの更新中にエラーが発生しました", "error", err, "webhook_id", webhook.ID)
+			response.InternalServerError(c, "Webhookの更新中にエラーが発生しました")
+		}
+		return
+	}
+
+	response.Success(c, webhook)
+}
+
+// DeleteWebhook 個人用Webhookを削除するハンドラー
+// @Summary 個人用Webhookを削除する
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhook, ok := h.targetWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookRepo.Delete(c, webhook.ID); err != nil {
+		if err.Error() == "webhook not found" {
+			response.NotFound(c, "Webhookが見つかりません")
+			return
+		}
+		h.log.Error("Webhookの削除中にエラーが発生しました", "error", err, "webhook_id", webhook.ID)
+		response.InternalServerError(c, "Webhookの削除中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{"id": webhook.ID, "deleted": true})
+}
+
+// RotateSecret 個人用Webhookの署名用シークレットを新しくするハンドラー
+// 以前のシークレットはすぐに使えなくなり、新しいシークレットはこのレスポンスでのみ返す
+// @Summary 個人用Webhookの署名用シークレットを新しくする
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/webhooks/{id}/secret [post]
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	webhook, ok := h.targetWebhook(c)
+	if !ok {
+		return
+	}
+
+	secret, err := h.webhooks.RotateSecret(c, webhook)
+	if err != nil {
+		if err.Error() == "webhook not found" {
+			response.NotFound(c, "Webhookが見つかりません")
+			return
+		}
+		h.log.Error("Webhookのシークレットの変更中にエラーが発生しました", "error", err, "webhook_id", webhook.ID)
+		response.InternalServerError(c, "Webhookのシークレットの変更中にエラーが発生しました")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"id":     webhook.ID,
+		"secret": secret,
+	})
+}
+
+// TestWebhook 個人用Webhookにテスト用のイベントを送信し、送信結果を返すハンドラー
+// 無効になっているWebhookにも送信できる（送信先の修正を確認するため）
+// @Summary 個人用Webhookにテスト用のイベントを送信し、送信結果を返す
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/me/webhooks/{id}/test [post]
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	webhook, ok := h.targetWebhook(c)
+	if !ok {
+		return
+	}
+
+	delivery := h.webhooks.SendTest(c.Request.Context(), webhook)
+	response.Success(c, delivery)
+}
+
+// currentUserID 現在のユーザーIDを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+func (h *WebhookHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	currentUserIDStr, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "認証が必要です")
+		return uuid.Nil, false
+	}
+
+	currentUserID, err := uuid.Parse(currentUserIDStr.(string))
+	if err != nil {
+		h.log.Error("ユーザーIDのパース中にエラーが発生しました", "error", err)
+		response.InternalServerError(c, "ユーザー情報の取得中にエラーが発生しました")
+		return uuid.Nil, false
+	}
+
+	return currentUserID, true
+}
+
+// targetWebhook パスで指定された自分のWebhookを取得する（取得できない場合はエラーレスポンスを送信してfalseを返す）
+// 他のユーザーのWebhookは存在しないものとして扱う
+func (h *WebhookHandler) targetWebhook(c *gin.Context) (*models.UserWebhook, bool) {
+	currentUserID, ok := h.currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "無効なWebhook IDです", nil)
+		return nil, false
+	}
+
+	webhook, err := h.webhookRepo.GetByID(c, webhookID)
+	if err != nil {
+		if err.Error() == "webhook not found" {
+			response.NotFound(c, "Webhookが見つかりません")
+			return nil, false
+		}
+		h.log.Error("Webhookの取得中にエラーが発生しました", "error", err, "webhook_id", webhookID)
+		response.InternalServerError(c, "Webhookの取得中にエラーが発生しました")
+		return nil, false
+	}
+	if webhook.UserID != currentUserID {
+		response.NotFound(c, "Webhookが見つかりません")
+		return nil, false
+	}
+
+	return webhook, true
+}
+
+// webhookEvents リクエストのイベント名を重複を除いてイベントの一覧に変換する
+func webhookEvents(names []string) []models.WebhookEvent {
+	events := make([]models.WebhookEvent, 0, len(names))
+	seen := make(map[models.WebhookEvent]bool, len(names))
+	for _, name := range names {
+		event := models.WebhookEvent(name)
+		if event.IsValid() && !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	return events
+}
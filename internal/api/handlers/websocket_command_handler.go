@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebSocketCommandHandler WebSocketでクライアントから送信されるコマンドを各サービスに振り分けるハンドラー
+type WebSocketCommandHandler struct {
+	notifications *service.NotificationService
+}
+
+// MarkReadCommand 通知の既読化コマンドの引数
+type MarkReadCommand struct {
+	// 既読にする通知ID（省略時はすべての通知を既読にする）
+	NotificationID *uuid.UUID `json:"notification_id"`
+}
+
+// NewWebSocketCommandHandler 新しいWebSocketコマンドハンドラーを作成する
+func NewWebSocketCommandHandler(notifications *service.NotificationService) *WebSocketCommandHandler {
+	return &WebSocketCommandHandler{
+		notifications: notifications,
+	}
+}
+
+// Register ハブにコマンドを登録する
+func (h *WebSocketCommandHandler) Register(hub *websocket.Hub) {
+	hub.HandleCommand("mark_read", h.MarkRead)
+}
+
+// MarkRead 通知を既読にし、残りの未読通知数を返すコマンド
+// {"type":"mark_read","id":"1","data":{"notification_id":"..."}}
+func (h *WebSocketCommandHandler) MarkRead(ctx context.Context, userID uuid.UUID, data json.RawMessage) (interface{}, error) {
+	var command MarkReadCommand
+	if err := websocket.DecodeCommand(data, &command); err != nil {
+		return nil, err
+	}
+
+	unreadCount, err := h.notifications.MarkAsRead(ctx, userID, command.NotificationID)
+	if err != nil {
+		if err.Error() == "notification not found" {
+			return nil, websocket.NewCommandError(websocket.ErrorCodeNotFound, "notification not found")
+		}
+		// 内部エラーの詳細はハブがログに記録する
+		return nil, err
+	}
+
+	return gin.H{
+		"unread_count": unreadCount,
+	}, nil
+}
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/util/response"
 	"github.com/TakuyaAizawa/gox/internal/websocket"
@@ -9,58 +13,132 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	gorillaWs "github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // WebSocketHandler WebSocket接続を管理するハンドラー
 type WebSocketHandler struct {
 	hub *websocket.Hub
-	log logger.Logger
+	// 接続を許可するオリジン（"*"の場合はすべて許可）
+	allowedOrigins []string
+	metrics        *websocket.UpgradeMetrics
+	log            logger.Logger
+}
+
+const (
+	// ドレイン中に接続を拒否したクライアントに再試行を促すまでの秒数
+	drainRetryAfterSeconds = 5
+	// 再接続の指示を分散させる時間の既定値と上限
+	defaultDrainWindow = time.Minute
+	maxDrainWindow     = 30 * time.Minute
+)
+
+// StartDrainRequest ドレインの開始リクエストの構造体
+type StartDrainRequest struct {
+	// 接続中のクライアントに再接続を指示し終えるまでの秒数（省略時は60秒）
+	WindowSeconds int `json:"window_seconds" binding:"min=0"`
 }
 
 // WebSocketのアップグレード設定
 var upgrader = gorillaWs.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	// CORS対応のため、接続元をチェックしない
-	// 本番環境では適切なオリジン検証を行うべき
+	// オリジンはアップグレード前にWebSocketHandler.checkOriginで検証する
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
 }
 
-// NewWebSocketHandler 新しいWebSocketハンドラーを作成する
-func NewWebSocketHandler(log logger.Logger) *WebSocketHandler {
-	hub := websocket.NewHub(log)
-	go hub.Run()
-
+// NewWebSocketHandler 新しいWebSocketハンドラーを作成する（hubは起動済みであること）
+func NewWebSocketHandler(hub *websocket.Hub, allowedOrigins []string, log logger.Logger) *WebSocketHandler {
 	return &WebSocketHandler{
-		hub: hub,
-		log: log,
+		hub:            hub,
+		allowedOrigins: allowedOrigins,
+		metrics:        websocket.NewUpgradeMetrics(),
+		log:            log,
+	}
+}
+
+// TrackAuthFailures 認証ミドルウェアで拒否された接続をアップグレードの失敗として記録するミドルウェア
+// 認証ミドルウェアより前に設定する
+func (h *WebSocketHandler) TrackAuthFailures() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Status() != http.StatusUnauthorized {
+			return
+		}
+		reason := websocket.UpgradeFailureAuthInvalid
+		if c.GetHeader("Authorization") == "" {
+			reason = websocket.UpgradeFailureAuthMissing
+		}
+		h.recordFailure(c, reason, nil)
 	}
 }
 
 // HandleWSConnection WebSocket接続をハンドリングする
+// 接続後、クライアントは {"type":"subscribe","data":{"stream":"home"|"replies"|"hashtag","post_id":"...","tag":"..."}} を送信して
+// ストリームを購読でき、新着投稿は stream_post イベントで届く（購読解除は "type":"unsubscribe"）
+// 通知には連番（seq）が付き、{"type":"ack","data":{"seq":N}} で受信を確認し、再接続後に {"type":"resume","data":{"last_seq":N}} で切断中の通知を再送させる
+// ほかに ping（dataをpongで返す）と mark_read（通知の既読化）のコマンドがあり、処理できないコマンドには error フレームを返す
+// @Summary WebSocket接続をハンドリングする
+// @Description 接続後に subscribe / unsubscribe メッセージを送信すると、ホームタイムライン・投稿への返信・ハッシュタグの新着投稿を stream_post イベントで受け取れる。通知の連番を ack で確認し、再接続後に resume を送信すると切断中の通知が再送される。コマンドは {"type","id","data"} の形式で、ping・mark_read にも対応し、処理できない場合は error フレームを返す
+// @Tags websocket
+// @Produce json
+// @Security BearerAuth
+// @Success 101 "Switching Protocols"
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/ws [get]
 func (h *WebSocketHandler) HandleWSConnection(c *gin.Context) {
 	// ユーザー認証の確認
 	userIDStr, exists := c.Get("userID")
 	if !exists {
+		h.recordFailure(c, websocket.UpgradeFailureAuthMissing, nil)
 		response.Unauthorized(c, "認証が必要です")
 		return
 	}
 
-	userID, ok := userIDStr.(uuid.UUID)
-	if !ok {
-		h.log.Error("ユーザーIDのフォーマットが不正です", "user_id", userIDStr)
-		response.InternalServerError(c, "内部エラーが発生しました")
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		h.recordFailure(c, websocket.UpgradeFailureAuthInvalid, err)
+		response.Unauthorized(c, "無効なトークンです")
+		return
+	}
+
+	// ドレイン中のインスタンスは新しい接続を受け付けず、別のインスタンスへの再試行を促す
+	if h.hub.Draining() {
+		h.recordFailure(c, websocket.UpgradeFailureDraining, nil)
+		c.Header("Retry-After", strconv.Itoa(drainRetryAfterSeconds))
+		response.JSON(c, http.StatusServiceUnavailable, response.NewErrorResponse(
+			"DRAINING", "このサーバーは停止の準備中です。しばらくしてから再接続してください",
+			gin.H{"retry_after_seconds": drainRetryAfterSeconds},
+		))
 		return
 	}
 
-	// WebSocketへのアップグレード
+	// 許可されていないオリジンからの接続を拒否
+	if !h.checkOrigin(c.Request) {
+		h.recordFailure(c, websocket.UpgradeFailureOriginRejected, nil)
+		response.Forbidden(c, "このオリジンからの接続は許可されていません")
+		return
+	}
+
+	// WebSocketへのアップグレード（失敗した場合はupgraderがエラーレスポンスを書き込む）
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		h.log.Error("WebSocketアップグレードに失敗しました", "error", err)
+		reason := websocket.UpgradeFailureUpgradeError
+		var handshakeErr gorillaWs.HandshakeError
+		if errors.As(err, &handshakeErr) {
+			reason = websocket.UpgradeFailureHandshake
+		}
+		h.recordFailure(c, reason, err)
 		return
 	}
+	h.metrics.RecordSuccess(c.FullPath())
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("enduser.id", userID.String()))
 
 	// 新しいクライアントの作成
 	client := websocket.NewClient(h.hub, conn, userID, h.log)
@@ -80,7 +158,145 @@ func (h *WebSocketHandler) HandleWSConnection(c *gin.Context) {
 	go client.ReadPump()
 }
 
+// GetUpgradeMetrics ルートごとのWebSocketアップグレードの成功数と理由別の失敗数を取得するハンドラー（管理者向け）
+// @Summary ルートごとのWebSocketアップグレードの成功数と理由別の失敗数を取得する（管理者向け）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/v1/admin/websocket/metrics [get]
+func (h *WebSocketHandler) GetUpgradeMetrics(c *gin.Context) {
+	since, routes := h.metrics.Snapshot()
+
+	response.Success(c, gin.H{
+		"since":  since,
+		"routes": routes,
+	})
+}
+
+// StartDrain このインスタンスのWebSocket接続のドレインを開始するハンドラー（管理者向け）
+// ローリングデプロイの前に、停止するインスタンスへ直接リクエストして使用する
+// 新しい接続の受け付けとレディネスを止め、接続中のクライアントにはwindow_secondsの間に分散して再接続を指示する
+// @Summary このインスタンスのWebSocket接続のドレインを開始する（管理者向け）
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body StartDrainRequest true "リクエストの内容"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /api/v1/admin/websocket/drain [post]
+func (h *WebSocketHandler) StartDrain(c *gin.Context) {
+	var req StartDrainRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, err)
+			return
+		}
+	}
+
+	window := defaultDrainWindow
+	if req.WindowSeconds > 0 {
+		window = time.Duration(req.WindowSeconds) * time.Second
+	}
+	if window > maxDrainWindow {
+		response.BadRequest(c, "window_secondsは1800秒以下で指定してください", nil)
+		return
+	}
+
+	if !h.hub.StartDrain(window) {
+		response.Conflict(c, "既にドレイン中です", drainStatusResponse(h.hub.GetDrainStatus()))
+		return
+	}
+
+	response.Success(c, drainStatusResponse(h.hub.GetDrainStatus()))
+}
+
+// GetDrainStatus ドレインの状態と残っている接続の数を取得するハンドラー（管理者向け）
+// safe_to_terminateがtrueになればインスタンスを停止できる
+// @Summary ドレインの状態と残っている接続の数を取得する（管理者向け）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/v1/admin/websocket/drain [get]
+func (h *WebSocketHandler) GetDrainStatus(c *gin.Context) {
+	response.Success(c, drainStatusResponse(h.hub.GetDrainStatus()))
+}
+
+// CancelDrain ドレインを取り消して新しい接続の受け付けを再開するハンドラー（管理者向け）
+// @Summary ドレインを取り消して新しい接続の受け付けを再開する（管理者向け）
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /api/v1/admin/websocket/drain [delete]
+func (h *WebSocketHandler) CancelDrain(c *gin.Context) {
+	h.hub.CancelDrain()
+	response.Success(c, drainStatusResponse(h.hub.GetDrainStatus()))
+}
+
 // GetNotificationHub 通知ハブを取得する（他のサービスからの利用用）
 func (h *WebSocketHandler) GetNotificationHub() *websocket.Hub {
 	return h.hub
 }
+
+// checkOrigin 接続元のオリジンが許可されているかを確認する
+// Originヘッダーを送らないブラウザ以外のクライアントは許可する
+func (h *WebSocketHandler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowedOrigin := range h.allowedOrigins {
+		if origin == allowedOrigin || allowedOrigin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// recordFailure アップグレードの失敗を記録し、クライアントの不具合と攻撃を見分けられるよう接続元の情報とともにログに出力する
+func (h *WebSocketHandler) recordFailure(c *gin.Context, reason websocket.UpgradeFailureReason, err error) {
+	route := c.FullPath()
+	h.metrics.RecordFailure(route, reason)
+
+	fields := []interface{}{
+		"route", route,
+		"reason", string(reason),
+		"client_ip", c.ClientIP(),
+		"origin", c.GetHeader("Origin"),
+		"user_agent", c.Request.UserAgent(),
+	}
+	if err != nil {
+		fields = append(fields, "error", err)
+	}
+	h.log.Warn("WebSocketアップグレードに失敗しました", fields...)
+
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(attribute.String("websocket.upgrade_failure", string(reason)))
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+// drainStatusResponse ドレインの状態をレスポンス用に変換する（どのインスタンスの状態かわかるようホスト名を含める）
+func drainStatusResponse(status websocket.DrainStatus) gin.H {
+	hostname, _ := os.Hostname()
+	resp := gin.H{
+		"instance":              hostname,
+		"draining":              status.Draining,
+		"remaining_connections": status.RemainingConnections,
+		"safe_to_terminate":     status.SafeToTerminate(),
+	}
+	if status.Draining {
+		resp["started_at"] = status.StartedAt
+		resp["window_seconds"] = int(status.Window.Seconds())
+	}
+	return resp
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 認証済みユーザーの活動を記録するミドルウェア（Authミドルウェアの後に使用する）
+func TrackActivity(stats *service.UserStatsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, exists := c.Get("userID"); exists {
+			if id, err := uuid.Parse(userID.(string)); err == nil {
+				stats.RecordActivity(id)
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	// "net/http"
 	"strings"
 
+	"github.com/TakuyaAizawa/gox/internal/service"
 	"github.com/TakuyaAizawa/gox/internal/util/jwt"
 	"github.com/TakuyaAizawa/gox/internal/util/response"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
@@ -11,7 +12,8 @@ import (
 )
 
 // JWT認証のためのミドルウェア
-func Auth(jwtUtil *jwt.JWTUtil, log logger.Logger) gin.HandlerFunc {
+// 停止されたアカウントのトークンは有効期限内でも拒否する
+func Auth(jwtUtil *jwt.JWTUtil, accounts *service.AccountStatusService, log logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Authorization ヘッダーの取得
 		authHeader := c.GetHeader("Authorization")
@@ -39,8 +41,29 @@ func Auth(jwtUtil *jwt.JWTUtil, log logger.Logger) gin.HandlerFunc {
 			return
 		}
 
+		// 停止されたアカウントは拒否する
+		userID, err := jwt.GetUserIDFromToken(claims)
+		if err != nil {
+			response.Unauthorized(c, "無効なトークンです")
+			c.Abort()
+			return
+		}
+		suspended, err := accounts.IsSuspended(c, userID)
+		if err != nil {
+			log.Error("アカウントの状態の確認中にエラーが発生しました", "error", err, "userID", userID)
+			response.InternalServerError(c, "アカウントの状態の確認中にエラーが発生しました")
+			c.Abort()
+			return
+		}
+		if suspended {
+			response.Forbidden(c, "このアカウントは停止されています")
+			c.Abort()
+			return
+		}
+
 		// ユーザーIDをコンテキストに設定
 		c.Set("userID", claims.UserID)
+		c.Set("tokenKey", jwt.TokenKey(tokenString))
 
 		// その他のユーザー情報を必要に応じて設定
 		if claims.Username != "" {
@@ -49,6 +72,9 @@ func Auth(jwtUtil *jwt.JWTUtil, log logger.Logger) gin.HandlerFunc {
 		if claims.Email != "" {
 			c.Set("email", claims.Email)
 		}
+		if claims.Role != "" {
+			c.Set("role", claims.Role)
+		}
 
 		c.Next()
 	}
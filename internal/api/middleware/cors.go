@@ -26,7 +26,7 @@ func CORS(allowedOrigins []string) gin.HandlerFunc {
 		}
 		
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Client-App")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		
 		// プリフライトリクエストを処理
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // リクエスト詳細をログに記録するミドルウェアを返す
@@ -29,6 +30,12 @@ func Logger(log logger.Logger) gin.HandlerFunc {
 		// ハンドラー処理後
 		latency := time.Since(start)
 		statusCode := c.Writer.Status()
+
+		// トレースと突き合わせられるよう、記録したトレースのIDを残す
+		traceID := ""
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsSampled() {
+			traceID = spanContext.TraceID().String()
+		}
 		
 		// レスポンスのログレベルはステータスコードに基づく
 		if statusCode >= 500 {
@@ -37,6 +44,7 @@ func Logger(log logger.Logger) gin.HandlerFunc {
 				"path", path,
 				"status", statusCode,
 				"latency", latency,
+				"trace_id", traceID,
 				"error", c.Errors.String(),
 			)
 		} else if statusCode >= 400 {
@@ -45,6 +53,7 @@ func Logger(log logger.Logger) gin.HandlerFunc {
 				"path", path,
 				"status", statusCode,
 				"latency", latency,
+				"trace_id", traceID,
 				"error", c.Errors.String(),
 			)
 		} else {
@@ -53,6 +62,7 @@ func Logger(log logger.Logger) gin.HandlerFunc {
 				"path", path,
 				"status", statusCode,
 				"latency", latency,
+				"trace_id", traceID,
 			)
 		}
 	}
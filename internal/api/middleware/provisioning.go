@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMのエラーレスポンスのスキーマ
+const scimErrorSchema = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+// IdPからのSCIMリクエストをプロビジョニング用のトークンで認証するミドルウェア
+// エラーはSCIMのエラー形式で返す
+func RequireProvisioningToken(token string, log logger.Logger) gin.HandlerFunc {
+	expected := []byte(token)
+
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		provided, found := strings.CutPrefix(authHeader, "Bearer ")
+		if !found || token == "" || subtle.ConstantTimeCompare([]byte(provided), expected) != 1 {
+			log.Warn("SCIMリクエストのトークンが無効です", "path", c.Request.URL.Path, "ip", c.ClientIP())
+			c.Header("WWW-Authenticate", `Bearer realm="scim"`)
+			c.Header("Content-Type", "application/scim+json")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"schemas": []string{scimErrorSchema},
+				"status":  "401",
+				"detail":  "プロビジョニング用のトークンが無効です",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
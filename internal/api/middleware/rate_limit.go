@@ -1,73 +1,106 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
 // クライアントのレート制限データを表す構造体
 type RateLimitClient struct {
-	Count      int       // リクエスト数
-	ResetTime  time.Time // リセット時刻
+	Count     int       // リクエスト数
+	ResetTime time.Time // リセット時刻
+}
+
+// 期限切れのクライアントを削除する間隔
+const rateLimitSweepInterval = time.Minute
+
+// プロセス内のマップでリクエスト数を数えるレート制限（固定ウィンドウ）
+type MemoryRateLimiter struct {
+	clients   map[string]*RateLimitClient
+	nextSweep time.Time
+	mutex     sync.Mutex
+}
+
+// プロセス内のレート制限を作成
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		clients:   make(map[string]*RateLimitClient),
+		nextSweep: time.Now().Add(rateLimitSweepInterval),
+	}
+}
+
+// リクエストを1件記録し、許可するかどうか・残りのリクエスト数・制限が解除される時刻を返す
+func (l *MemoryRateLimiter) Allow(_ context.Context, key string, limit int, window time.Duration) (bool, int, time.Time, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+
+	// リセット時刻を過ぎたクライアントを定期的に削除する（アクセスの途絶えたクライアントが残り続けないようにする）
+	if now.After(l.nextSweep) {
+		for k, client := range l.clients {
+			if now.After(client.ResetTime) {
+				delete(l.clients, k)
+			}
+		}
+		l.nextSweep = now.Add(rateLimitSweepInterval)
+	}
+
+	// 新しいクライアントの場合、またはリセット時間を過ぎていればカウンターをリセット
+	client, exists := l.clients[key]
+	if !exists || now.After(client.ResetTime) {
+		client = &RateLimitClient{ResetTime: now.Add(window)}
+		l.clients[key] = client
+	}
+
+	if client.Count >= limit {
+		return false, 0, client.ResetTime, nil
+	}
+	client.Count++
+
+	return true, limit - client.Count, client.ResetTime, nil
 }
 
 // リクエスト数を制限するミドルウェアを返す
-func RateLimit(limit int, duration time.Duration) gin.HandlerFunc {
-	// IPアドレスごとのリクエスト数を保持するマップ
-	clients := make(map[string]*RateLimitClient)
-	var mutex sync.Mutex
-	
+// limiterがnilの場合はプロセス内で数え、limiter（Redisなど）でエラーが発生した場合もプロセス内の制限にフォールバックする
+func RateLimit(limiter interfaces.RateLimiter, limit int, duration time.Duration, log logger.Logger) gin.HandlerFunc {
+	fallback := NewMemoryRateLimiter()
+	if limiter == nil {
+		limiter = fallback
+	}
+
 	return func(c *gin.Context) {
-		// クライアントIPを取得
+		// クライアントIPごとに数える
 		clientIP := c.ClientIP()
-		
-		mutex.Lock()
-		defer mutex.Unlock()
-		
-		// 新しいクライアントの場合は初期化
-		if _, exists := clients[clientIP]; !exists {
-			clients[clientIP] = &RateLimitClient{
-				Count:      0,
-				ResetTime:  time.Now().Add(duration),
-			}
-		}
-		
-		client := clients[clientIP]
-		now := time.Now()
-		
-		// リセット時間を過ぎていれば、カウンターをリセット
-		if now.After(client.ResetTime) {
-			client.Count = 0
-			client.ResetTime = now.Add(duration)
+
+		allowed, remaining, resetAt, err := limiter.Allow(c.Request.Context(), clientIP, limit, duration)
+		if err != nil {
+			log.Warn("レート制限の判定に失敗したため、プロセス内の制限を使用します", "error", err)
+			allowed, remaining, resetAt, _ = fallback.Allow(c.Request.Context(), clientIP, limit, duration)
 		}
-		
-		// レート制限チェック
-		if client.Count >= limit {
-			// レスポンスヘッダーを設定
-			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
-			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", client.ResetTime.Unix()))
-			c.Header("Retry-After", fmt.Sprintf("%d", int(client.ResetTime.Sub(now).Seconds())))
-			
+
+		// レスポンスヘッダーを設定
+		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
+		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", resetAt.Unix()))
+
+		if !allowed {
+			c.Header("Retry-After", fmt.Sprintf("%d", int(time.Until(resetAt).Seconds())))
+
 			// リクエスト過多エラーを返す
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"error": "レート制限を超過しました",
 			})
 			return
 		}
-		
-		// リクエストカウンターをインクリメント
-		client.Count++
-		
-		// レスポンスヘッダーを設定
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", limit-client.Count))
-		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", client.ResetTime.Unix()))
-		
+
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
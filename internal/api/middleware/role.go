@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/util/response"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// 指定した権限のいずれかを持つユーザーのみアクセスできるようにするミドルウェア（Authミドルウェアの後に使用する）
+// 権限はトークンのクレームから判定するため、リクエストごとにデータベースを参照しない（権限の変更は次のトークンの発行から反映される）
+// 上位の権限は下位の権限を含み、設定で指定されたユーザー（adminUserIDs）は管理者として扱う
+func RequireRole(adminUserIDs []string, log logger.Logger, roles ...models.UserRole) gin.HandlerFunc {
+	admins := make(map[string]struct{}, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = struct{}{}
+	}
+
+	hasRole := func(id string, role models.UserRole) bool {
+		if id == "" {
+			return false
+		}
+		if _, ok := admins[id]; ok {
+			role = models.UserRoleAdmin
+		}
+		for _, required := range roles {
+			if role.Includes(required) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		id, _ := userID.(string)
+		if !hasRole(id, UserRoleFromContext(c)) {
+			log.Warn("権限のないユーザーが保護されたAPIにアクセスしました", "user_id", id, "path", c.Request.URL.Path, "required", roles)
+			response.Forbidden(c, "この操作を行う権限がありません")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// トークンのクレームから設定された権限を返す（権限を含まないトークンの場合は一般ユーザー）
+func UserRoleFromContext(c *gin.Context) models.UserRole {
+	role, _ := c.Get("role")
+	if value, ok := role.(string); ok && value != "" {
+		return models.UserRole(value)
+	}
+	return models.UserRoleUser
+}
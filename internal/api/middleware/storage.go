@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+// プライマリのストレージが利用できない間、メディアの読み込みをセカンダリのURLへリダイレクトするミドルウェア
+// prefixは静的配信のパス、baseURLはプライマリの公開URL
+func MediaFailover(failover coreinterfaces.ReadFailover, prefix string, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fileURL := strings.TrimRight(baseURL, "/") + strings.TrimPrefix(c.Request.URL.Path, prefix)
+		if target, ok := failover.FailoverURL(c.Request.Context(), fileURL); ok {
+			c.Redirect(http.StatusTemporaryRedirect, target)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
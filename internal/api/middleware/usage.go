@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/TakuyaAizawa/gox/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// クライアントアプリを識別するヘッダー
+const clientAppHeader = "X-Client-App"
+
+// クライアントアプリ名の最大長
+const maxClientAppLength = 50
+
+// 認証済みリクエストのAPI利用状況を記録するミドルウェア（Authミドルウェアの後に使用する）
+func TrackAPIUsage(usage *service.APIUsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID, exists := c.Get("userID")
+		if !exists {
+			return
+		}
+		id, err := uuid.Parse(userID.(string))
+		if err != nil {
+			return
+		}
+
+		// ルートのパターンで集計する（パスに含まれるIDごとに分かれないようにする）
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = "(unmatched)"
+		}
+
+		var requestBytes int64
+		if c.Request.ContentLength > 0 {
+			requestBytes = c.Request.ContentLength
+		}
+		var responseBytes int64
+		if size := c.Writer.Size(); size > 0 {
+			responseBytes = int64(size)
+		}
+
+		usage.RecordRequest(id, clientApp(c), c.GetString("tokenKey"), c.Request.Method+" "+endpoint, requestBytes, responseBytes)
+	}
+}
+
+// clientApp リクエスト元のクライアントアプリ名を返す（指定がない場合はunknown）
+func clientApp(c *gin.Context) string {
+	app := strings.TrimSpace(c.GetHeader(clientAppHeader))
+	if app == "" {
+		return "unknown"
+	}
+	if len(app) > maxClientAppLength {
+		app = app[:maxClientAppLength]
+	}
+	return strings.ToValidUTF8(app, "")
+}
@@ -4,16 +4,21 @@ import (
 	"net/http"
 	"strings"
 
+	swaggerdocs "github.com/TakuyaAizawa/gox/docs/swagger"
 	"github.com/TakuyaAizawa/gox/internal/api/handlers"
 	"github.com/TakuyaAizawa/gox/internal/api/middleware"
 	"github.com/TakuyaAizawa/gox/internal/config"
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
 	repointerfaces "github.com/TakuyaAizawa/gox/internal/repository/interfaces"
 	"github.com/TakuyaAizawa/gox/internal/service"
-	"github.com/TakuyaAizawa/gox/internal/storage"
 	"github.com/TakuyaAizawa/gox/internal/util/jwt"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
 	"github.com/gin-gonic/gin"
+	swaggerfiles "github.com/swaggo/files"
+	ginswagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // SetupRouter APIルートを設定する
@@ -24,7 +29,51 @@ func SetupRouter(
 	postRepo repointerfaces.PostRepository,
 	followRepo repointerfaces.FollowRepository,
 	likeRepo repointerfaces.LikeRepository,
+	reactionRepo repointerfaces.ReactionRepository,
 	notificationRepo repointerfaces.NotificationRepository,
+	blockRepo repointerfaces.BlockRepository,
+	listRepo repointerfaces.ListRepository,
+	conversationMuteRepo repointerfaces.ConversationMuteRepository,
+	supporterRepo repointerfaces.SupporterRepository,
+	postViewRepo repointerfaces.PostViewRepository,
+	auditRepo repointerfaces.AuditLogRepository,
+	reportRepo repointerfaces.ReportRepository,
+	contentFilterRepo repointerfaces.ContentFilterRepository,
+	webhookRepo repointerfaces.WebhookRepository,
+	uploadSessionRepo repointerfaces.UploadSessionRepository,
+	followProjector *service.FollowProjectorService,
+	viewCounter *service.ViewCounterService,
+	userStats *service.UserStatsService,
+	apiUsage *service.APIUsageService,
+	settingsRepo repointerfaces.SettingsRepository,
+	txManager repointerfaces.TxManager,
+	searchService *service.SearchService,
+	timelineFanout *service.TimelineFanoutService,
+	profileVisitors *service.ProfileVisitorService,
+	systemAccounts *service.SystemAccountService,
+	dbHealth repointerfaces.HealthChecker,
+	rateLimiter repointerfaces.RateLimiter,
+	storageProvider coreinterfaces.StorageProvider,
+	mediaService *service.MediaService,
+	hub *websocket.Hub,
+	accountDeletion *service.AccountDeletionService,
+	accountMerge *service.AccountMergeService,
+	sso *service.SSOService,
+	onboarding *service.OnboardingService,
+	threadUnroll *service.ThreadUnrollService,
+	analytics *service.AnalyticsService,
+	webhookService *service.WebhookService,
+	uploadSessions *service.UploadSessionService,
+	instanceStats *service.InstanceStatsService,
+	syncService *service.SyncService,
+	pushService *service.PushService,
+	emailNotifications *service.EmailNotificationService,
+	notificationSettingsRepo repointerfaces.NotificationSettingsRepository,
+	outbox *service.OutboxService,
+	trendRepo repointerfaces.TrendRepository,
+	presence *service.PresenceService,
+	topicRepo repointerfaces.TopicRepository,
+	searchIndex *service.SearchIndexService,
 ) *gin.Engine {
 	// プロダクションモードの場合はデバッグモードを無効化
 	if cfg.App.Env == "production" {
@@ -36,14 +85,23 @@ func SetupRouter(
 
 	r := gin.New()
 
-	// ミドルウェアの設定
+	// ハンドラーからリポジトリへ渡すgin.Contextで、リクエストのコンテキスト（トレースのスパンなど）を参照する
+	r.ContextWithFallback = true
+
+	// ミドルウェアの設定（トレースIDをログに記録できるよう、トレースを最初に開始する）
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(tracedRequest)))
 	r.Use(middleware.Logger(log))
 	r.Use(middleware.Recovery(log))
 	r.Use(middleware.CORS(cfg.CORS.AllowedOrigins))
-	r.Use(middleware.RateLimit(cfg.RateLimit.Requests, cfg.RateLimit.Duration))
+	r.Use(middleware.RateLimit(rateLimiter, cfg.RateLimit.Requests, cfg.RateLimit.Duration, log))
 
-	// メディアファイルの静的配信
-	r.Static("/media", cfg.Storage.BaseDir)
+	// メディアファイルの静的配信（複製が有効な場合はプライマリの障害時にセカンダリへリダイレクトする）
+	media := r.Group("/media")
+	if failover, ok := storageProvider.(coreinterfaces.ReadFailover); ok {
+		media.Use(middleware.MediaFailover(failover, "/media", cfg.Storage.BaseURL))
+		r.Static("/media-replica", cfg.Storage.ReplicaBaseDir)
+	}
+	media.Static("/", cfg.Storage.BaseDir)
 
 	// ヘルスチェックエンドポイント
 	r.GET("/health", func(c *gin.Context) {
@@ -52,57 +110,173 @@ func SetupRouter(
 		})
 	})
 
+	// レディネスチェックエンドポイント（データベースに接続できない間は503を返す）
+	r.GET("/ready", func(c *gin.Context) {
+		if !dbHealth.Ready() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":   "unavailable",
+				"database": "unavailable",
+			})
+			return
+		}
+		// ドレイン中はロードバランサーから外して新しい接続を振り分けさせない
+		if hub.Draining() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "draining",
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ready",
+		})
+	})
+
+	// APIドキュメント（make swaggerで生成したOpenAPIドキュメントとSwagger UI）
+	if cfg.Swagger.Enabled {
+		// 試しに送信するリクエストは、ドキュメントを配信しているホストへ送る
+		swaggerdocs.SwaggerInfo.Host = ""
+		r.GET("/swagger/*any", ginswagger.WrapHandler(swaggerfiles.Handler))
+	}
+
 	// API v1 ルート
 	v1 := r.Group("/api/v1")
 
-	// ストレージプロバイダーの作成
-	var storageProvider coreinterfaces.StorageProvider
-	if cfg.Storage.Provider == "local" {
-		storageProvider = storage.NewLocalStorage(cfg.Storage.BaseDir, cfg.Storage.BaseURL, log)
-	} else {
-		log.Warn("ストレージプロバイダー設定が無効です。ローカルストレージを使用します", "provider", cfg.Storage.Provider)
-		storageProvider = storage.NewLocalStorage(cfg.Storage.BaseDir, cfg.Storage.BaseURL, log)
-	}
+	// 停止されたアカウントのトークンを拒否するためのアカウント状態サービス
+	accounts := service.NewAccountStatusService(userRepo)
 
 	// ハンドラーの作成
-	authHandler := handlers.NewAuthHandler(userRepo, log, jwtUtil)
-	wsHandler := handlers.NewWebSocketHandler(log)
+	authHandler := handlers.NewAuthHandler(userRepo, auditRepo, systemAccounts, sso, onboarding, analytics, searchIndex, cfg.Accounts.ReactivationGracePeriod, log, jwtUtil)
+	wsHandler := handlers.NewWebSocketHandler(hub, cfg.CORS.AllowedOrigins, log)
 
 	// 通知サービス
 	notificationService := service.NewNotificationService(
 		notificationRepo,
 		userRepo,
 		postRepo,
+		blockRepo,
+		notificationSettingsRepo,
+		txManager,
+		outbox,
+		log,
+	)
+
+	// WebSocketでクライアントから送信されるコマンド（通知の既読化など）
+	handlers.NewWebSocketCommandHandler(notificationService).Register(hub)
+
+	// ブロックサービス
+	blockService := service.NewBlockService(blockRepo, log)
+
+	// コンテンツポリシーサービス（年齢制限）
+	contentPolicy := service.NewContentPolicyService(
+		userRepo,
+		cfg.Content.MinimumAge,
+		cfg.Content.CountryMinimumAges,
+		log,
+	)
+
+	// コンテンツフィルターサービス（管理者が登録した禁止語による投稿・プロフィールの審査）
+	contentFilterService := service.NewContentFilterService(
+		contentFilterRepo,
+		reportRepo,
+		cfg.Content.FilterRefreshInterval,
+		log,
+	)
+
+	// 返信設定サービス
+	replyPolicyService := service.NewReplyPolicyService(followRepo, log)
+
+	// 会話サービス（返信時の参加者への通知と会話のミュート）
+	conversationService := service.NewConversationService(
+		postRepo,
+		txManager,
+		conversationMuteRepo,
+		blockService,
+		notificationService,
+		log,
+	)
+
+	// タイムライン更新サービス（新着投稿のWebSocketヒント）
+	timelineUpdateService := service.NewTimelineUpdateService(
+		followRepo,
+		postRepo,
+		blockService,
+		contentPolicy,
 		wsHandler.GetNotificationHub(),
 		log,
 	)
 
+	// サポーターサービス（決済サービスのWebhookとサポーター特典）
+	supporterService := service.NewSupporterService(supporterRepo, cfg.Supporters.WebhookSecret, log)
+
+	// プロフィールカードサービス（共有用のカード画像の生成）
+	profileCardService := service.NewProfileCardService(storageProvider, cfg.App.URL, log)
+
 	// ユーザーハンドラー
 	userHandler := handlers.NewUserHandler(
 		userRepo,
 		followRepo,
 		postRepo,
+		likeRepo,
+		reactionRepo,
 		notificationService,
-		storageProvider,
+		blockService,
+		contentPolicy,
+		contentFilterService,
+		supporterService,
+		profileCardService,
+		timelineFanout,
+		profileVisitors,
+		mediaService,
+		settingsRepo,
+		presence,
+		searchIndex,
 		log,
 	)
 
-	// 投稿ハンドラー
+	// 投稿ハンドラー（探索タブのトピックへの分類を含む）
+	topicService := service.NewTopicService(topicRepo, log)
 	postHandler := handlers.NewPostHandler(
 		postRepo,
 		userRepo,
 		likeRepo,
+		followRepo,
+		reactionRepo,
 		notificationRepo,
+		postViewRepo,
 		notificationService,
+		blockService,
+		contentPolicy,
+		replyPolicyService,
+		contentFilterService,
+		conversationService,
+		threadUnroll,
+		timelineUpdateService,
+		timelineFanout,
+		viewCounter,
+		mediaService,
+		supporterService,
+		topicService,
+		searchIndex,
+		cfg.App.URL,
 		log,
 	)
 
-	// タイムラインハンドラー
+	// タイムラインハンドラー（おすすめ順の並べ替えを含む）
+	timelineRanking := service.NewTimelineRankingService(postRepo, log)
 	timelineHandler := handlers.NewTimelineHandler(
 		postRepo,
 		userRepo,
 		followRepo,
 		likeRepo,
+		reactionRepo,
+		blockService,
+		contentPolicy,
+		mediaService,
+		settingsRepo,
+		timelineFanout,
+		timelineRanking,
+		topicRepo,
+		systemAccounts,
 		log,
 	)
 
@@ -111,21 +285,128 @@ func SetupRouter(
 		notificationRepo,
 		userRepo,
 		postRepo,
+		settingsRepo,
+		contentPolicy,
+		log,
+	)
+
+	// リストハンドラー
+	listHandler := handlers.NewListHandler(
+		listRepo,
+		userRepo,
+		postRepo,
+		likeRepo,
+		reactionRepo,
+		blockService,
+		contentPolicy,
+		mediaService,
 		log,
 	)
 
+	// サポーターハンドラー
+	supporterHandler := handlers.NewSupporterHandler(supporterService, log)
+
+	// 検索ハンドラー
+	searchHandler := handlers.NewSearchHandler(
+		searchService,
+		searchIndex,
+		userRepo,
+		postRepo,
+		likeRepo,
+		reactionRepo,
+		blockService,
+		contentPolicy,
+		mediaService,
+		log,
+	)
+
+	// 設定ハンドラー
+	settingsHandler := handlers.NewSettingsHandler(settingsRepo, profileVisitors, log)
+
+	// 個人用Webhookハンドラー
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo, webhookService, log)
+
+	// 分割アップロードハンドラー
+	uploadHandler := handlers.NewUploadHandler(uploadSessionRepo, userRepo, uploadSessions, supporterService, mediaService, log)
+
+	// 差分同期ハンドラー
+	syncHandler := handlers.NewSyncHandler(syncService, log)
+
+	// 端末（プッシュ通知）ハンドラー
+	deviceHandler := handlers.NewDeviceHandler(pushService, log)
+
+	// 通知設定ハンドラー
+	notificationSettingsHandler := handlers.NewNotificationSettingsHandler(notificationSettingsRepo, emailNotifications != nil, log)
+
+	// トレンドハンドラー
+	trendHandler := handlers.NewTrendHandler(trendRepo, log)
+
+	// 探索タブのトピックハンドラー
+	topicHandler := handlers.NewTopicHandler(topicRepo, log)
+
+	// 管理者向け統計ハンドラー
+	adminStatsHandler := handlers.NewAdminStatsHandler(postRepo, userStats, followProjector, log)
+
+	// 管理者向けお知らせハンドラー
+	adminAnnouncementHandler := handlers.NewAdminAnnouncementHandler(systemAccounts, log)
+
+	// 管理者向けユーザー管理ハンドラー
+	adminUserHandler := handlers.NewAdminUserHandler(userRepo, auditRepo, txManager, accountMerge, accounts, hub, log)
+
+	// 管理者向けメディア管理ハンドラー
+	adminMediaHandler := handlers.NewAdminMediaHandler(mediaService, auditRepo, txManager, cfg.Scheduler.MediaGracePeriod, log)
+
+	// 管理者向け禁止語ルール管理ハンドラー
+	adminContentFilterHandler := handlers.NewAdminContentFilterHandler(contentFilterRepo, auditRepo, txManager, contentFilterService, log)
+
+	// 管理者向けトピック管理ハンドラー
+	adminTopicHandler := handlers.NewAdminTopicHandler(topicRepo, auditRepo, txManager, log)
+
+	// 通報ハンドラー
+	reportHandler := handlers.NewReportHandler(reportRepo, postRepo, userRepo, auditRepo, txManager, log)
+	adminPostHandler := handlers.NewAdminPostHandler(postRepo, auditRepo, txManager, notificationService, systemAccounts, log)
+
+	// API利用状況ハンドラーの作成
+	apiUsageHandler := handlers.NewAPIUsageHandler(apiUsage, log)
+
+	// アカウント削除ハンドラー
+	accountDeletionHandler := handlers.NewAccountDeletionHandler(accountDeletion, log)
+
+	// オンボーディングハンドラー
+	onboardingHandler := handlers.NewOnboardingHandler(onboarding, log)
+
+	// 分析イベントハンドラー
+	analyticsHandler := handlers.NewAnalyticsHandler(analytics, log)
+
 	// 認証エンドポイント
 	auth := v1.Group("/auth")
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
+		auth.POST("/password/reset", authHandler.ResetPassword)
 		auth.POST("/refresh", authHandler.RefreshToken)
 		auth.POST("/logout", authHandler.Logout)
+		auth.GET("/methods", authHandler.GetMethods)
+		auth.GET("/sso/login", authHandler.SSOLogin)
+		auth.GET("/sso/callback", authHandler.SSOCallback)
+	}
+
+	// プロフィールカード（外部サイトから画像として埋め込むため認証不要）
+	v1.GET("/users/:username/card.png", userHandler.GetProfileCard)
+
+	// インスタンスディレクトリ向けの公開統計（定期的な集計の結果のみを返すため認証不要）
+	instanceStatsHandler := handlers.NewInstanceStatsHandler(instanceStats, cfg.Stats.PublicRefreshInterval)
+	v1.GET("/instance/stats", instanceStatsHandler.GetInstanceStats)
+
+	// 外部サービスからのWebhook（署名で検証するため認証不要）
+	webhooks := v1.Group("/webhooks")
+	{
+		webhooks.POST("/supporters", supporterHandler.HandleWebhook)
 	}
 
 	// 認証が必要なエンドポイント
 	secured := v1.Group("")
-	secured.Use(middleware.Auth(jwtUtil, log))
+	secured.Use(middleware.Auth(jwtUtil, accounts, log), middleware.TrackActivity(userStats), middleware.TrackAPIUsage(apiUsage))
 	{
 		// ユーザー関連
 		users := secured.Group("/users")
@@ -133,6 +414,31 @@ func SetupRouter(
 			// ユーザープロフィール
 			users.GET("/:username", userHandler.GetUserProfile)
 			users.PUT("/me", userHandler.UpdateProfile)
+			users.POST("/me/deactivate", userHandler.DeactivateAccount)
+			users.DELETE("/me", accountDeletionHandler.DeleteAccount)
+			users.GET("/me/deletion", accountDeletionHandler.GetAccountDeletion)
+			users.GET("/me/visitors", userHandler.GetProfileVisitors)
+			users.GET("/me/usage", apiUsageHandler.GetMyUsage)
+			users.GET("/me/storage", userHandler.GetStorageUsage)
+			users.GET("/me/followers/churn", userHandler.GetFollowerChurn)
+			users.DELETE("/me/followers/:username", userHandler.RemoveFollower)
+
+			// 個人用Webhook（自分へのフォロー・メンションを登録したURLへ送信する）
+			users.GET("/me/webhooks", webhookHandler.ListWebhooks)
+			users.POST("/me/webhooks", webhookHandler.CreateWebhook)
+			users.PATCH("/me/webhooks/:id", webhookHandler.UpdateWebhook)
+			users.DELETE("/me/webhooks/:id", webhookHandler.DeleteWebhook)
+			users.POST("/me/webhooks/:id/secret", webhookHandler.RotateSecret)
+			users.POST("/me/webhooks/:id/test", webhookHandler.TestWebhook)
+
+			// プッシュ通知を受け取る端末
+			users.GET("/me/devices", deviceHandler.ListDevices)
+			users.POST("/me/devices", deviceHandler.RegisterDevice)
+			users.DELETE("/me/devices/:token", deviceHandler.UnregisterDevice)
+
+			// 通知の種類ごとの受け取り方（メール通知）
+			users.GET("/me/notification-settings", notificationSettingsHandler.GetNotificationSettings)
+			users.PUT("/me/notification-settings", notificationSettingsHandler.UpdateNotificationSettings)
 
 			// プロフィール画像アップロード
 			users.POST("/me/avatar", userHandler.UploadAvatar)
@@ -142,38 +448,92 @@ func SetupRouter(
 			users.POST("/:username/follow", userHandler.FollowUser)
 			users.DELETE("/:username/follow", userHandler.UnfollowUser)
 			users.GET("/:username/followers", userHandler.GetFollowers)
+			users.GET("/:username/followers/mutual", userHandler.GetMutualFollowers)
 			users.GET("/:username/following", userHandler.GetFollowing)
 
+			// ブロック関連
+			users.POST("/:username/block", userHandler.BlockUser)
+			users.DELETE("/:username/block", userHandler.UnblockUser)
+
 			// ユーザーの投稿
 			users.GET("/:username/posts", userHandler.GetUserPosts)
+			users.GET("/:username/likes", userHandler.GetLikedPosts)
+			users.GET("/:username/replies/:other", userHandler.GetRepliesBetween)
+
+			// 通報
+			users.POST("/:username/report", reportHandler.ReportUser)
 		}
 
 		// 投稿関連
 		posts := secured.Group("/posts")
 		{
 			posts.POST("", postHandler.CreatePost)
+			posts.POST("/thread", postHandler.CreateThread)
+			posts.POST("/media", postHandler.UploadMedia)
 			posts.GET("/:id", postHandler.GetPost)
+			posts.PATCH("/:id", postHandler.UpdatePost)
 			posts.DELETE("/:id", postHandler.DeletePost)
+			posts.GET("/:id/edits", postHandler.GetPostEdits)
+			posts.GET("/:id/accessibility", postHandler.GetPostAccessibility)
 
 			// 返信
 			posts.GET("/:id/replies", postHandler.GetPostReplies)
+			posts.GET("/:id/unroll", postHandler.UnrollThread)
 
 			// いいね
 			posts.POST("/:id/like", postHandler.LikePost)
 			posts.DELETE("/:id/like", postHandler.UnlikePost)
+			posts.GET("/:id/likes", postHandler.GetPostLikes)
+			posts.GET("/:id/reposts", postHandler.GetPostReposters)
+			posts.POST("/:id/mute", postHandler.MuteConversation)
+			posts.DELETE("/:id/mute", postHandler.UnmuteConversation)
+			posts.GET("/:id/analytics", postHandler.GetPostAnalytics)
+
+			// リアクション
+			posts.POST("/:id/reactions", postHandler.ReactToPost)
+			posts.DELETE("/:id/reactions/:emoji", postHandler.RemoveReaction)
+
+			// 通報
+			posts.POST("/:id/report", reportHandler.ReportPost)
+
+			// 共有
+			posts.POST("/:id/share", postHandler.SharePost)
+			posts.PUT("/:id/sharing", postHandler.UpdatePostSharing)
 
 			// TODO: リポスト機能
 			// posts.POST("/:id/repost", postHandler.RepostPost)
 			// posts.DELETE("/:id/repost", postHandler.CancelRepost)
 		}
 
+		// スレッドの公開（返信でつながった投稿をまとめて作成する。/posts/threadと同じ）
+		secured.POST("/threads", postHandler.CreateThread)
+
 		// タイムライン関連
 		timeline := secured.Group("/timeline")
 		{
 			timeline.GET("/home", timelineHandler.GetHomeTimeline)
+			timeline.GET("/home/updates", timelineHandler.GetHomeTimelineUpdates)
 			timeline.GET("/explore", timelineHandler.GetExploreTimeline)
 		}
 
+		// リスト関連
+		lists := secured.Group("/lists")
+		{
+			lists.POST("", listHandler.CreateList)
+			lists.GET("", listHandler.GetMyLists)
+			lists.GET("/:id", listHandler.GetList)
+			lists.PUT("/:id", listHandler.UpdateList)
+			lists.DELETE("/:id", listHandler.DeleteList)
+
+			// メンバー管理
+			lists.GET("/:id/members", listHandler.GetListMembers)
+			lists.POST("/:id/members", listHandler.AddListMember)
+			lists.DELETE("/:id/members/:username", listHandler.RemoveListMember)
+
+			// リストタイムライン
+			lists.GET("/:id/timeline", listHandler.GetListTimeline)
+		}
+
 		// 通知エンドポイント
 		notifications := secured.Group("/notifications")
 		{
@@ -181,10 +541,143 @@ func SetupRouter(
 			notifications.GET("/unread", notificationHandler.GetUnreadCount)
 			notifications.PUT("/read", notificationHandler.MarkAsRead)
 		}
+
+		// 検索関連
+		search := secured.Group("/search")
+		{
+			search.GET("", searchHandler.Search)
+
+			// 検索履歴
+			search.GET("/history", searchHandler.GetSearchHistory)
+			search.DELETE("/history", searchHandler.ClearSearchHistory)
+			search.DELETE("/history/:id", searchHandler.DeleteSearchHistoryEntry)
+
+			// 保存した検索
+			search.GET("/saved", searchHandler.GetSavedSearches)
+			search.POST("/saved", searchHandler.SaveSearch)
+			search.PATCH("/saved/:id", searchHandler.UpdateSavedSearch)
+			search.DELETE("/saved/:id", searchHandler.DeleteSavedSearch)
+		}
+
+		// ハッシュタグのトレンド
+		secured.GET("/trends", trendHandler.GetTrends)
+
+		// 探索タブのトピック
+		secured.GET("/topics", topicHandler.ListTopics)
+
+		// オンボーディング（おすすめのユーザーと進捗）
+		onboardingGroup := secured.Group("/onboarding")
+		{
+			onboardingGroup.GET("", onboardingHandler.GetProgress)
+			onboardingGroup.GET("/suggestions", onboardingHandler.GetSuggestions)
+		}
+
+		// 分析イベント（クライアントで発生したインプレッション・クリック）
+		secured.POST("/analytics/events", analyticsHandler.TrackEvents)
+
+		// 設定関連
+		settings := secured.Group("/settings")
+		{
+			settings.GET("", settingsHandler.GetSettings)
+			settings.PUT("/explore/excluded-keywords", settingsHandler.UpdateExploreExcludedKeywords)
+			settings.PUT("/profile-visitors", settingsHandler.UpdateProfileVisitors)
+			settings.PUT("/notifications/grouping", settingsHandler.UpdateNotificationGrouping)
+			settings.PUT("/profile-theme", settingsHandler.UpdateProfileTheme)
+			settings.PUT("/presence", settingsHandler.UpdatePresence)
+			settings.PUT("/likes", settingsHandler.UpdateLikesVisibility)
+			settings.PUT("/timeline", settingsHandler.UpdateHomeTimelineOrder)
+		}
+
+		// 差分同期（オフラインファーストのクライアントが前回の同期以降の変更のみを取得する）
+		secured.GET("/sync", syncHandler.GetChanges)
+
+		// 分割アップロード（開始・パートの送信・完了。途中で失敗した場合は状態を確認して再開する）
+		uploads := secured.Group("/uploads")
+		{
+			uploads.POST("", uploadHandler.InitiateUpload)
+			uploads.GET("/:id", uploadHandler.GetUpload)
+			uploads.PUT("/:id/parts/:index", uploadHandler.UploadPart)
+			uploads.POST("/:id/complete", uploadHandler.CompleteUpload)
+			uploads.DELETE("/:id", uploadHandler.AbortUpload)
+		}
+
+		// 管理者向けエンドポイント（権限はトークンのクレームから判定する）
+		admin := secured.Group("/admin")
+		admin.Use(middleware.RequireRole(cfg.Admin.UserIDs, log, models.UserRoleAdmin))
+		{
+			admin.GET("/stats/cohorts", adminStatsHandler.GetCohorts)
+			admin.POST("/stats/cohorts/rollup", adminStatsHandler.RollupCohorts)
+			admin.GET("/stats/follows", adminStatsHandler.GetFollowChurn)
+			admin.GET("/stats/accessibility", adminStatsHandler.GetAccessibilityReport)
+			admin.POST("/follows/rebuild", adminStatsHandler.RebuildFollows)
+			admin.POST("/announcements", adminAnnouncementHandler.CreateAnnouncement)
+			admin.GET("/websocket/metrics", wsHandler.GetUpgradeMetrics)
+			admin.GET("/websocket/drain", wsHandler.GetDrainStatus)
+			admin.POST("/websocket/drain", wsHandler.StartDrain)
+			admin.DELETE("/websocket/drain", wsHandler.CancelDrain)
+			admin.GET("/usage", apiUsageHandler.GetUsageSummary)
+			admin.GET("/usage/keys/:key_id", apiUsageHandler.GetKeyUsage)
+			admin.GET("/users", adminUserHandler.ListUsers)
+			admin.POST("/users/:id/suspend", adminUserHandler.SuspendUser)
+			admin.DELETE("/users/:id/suspend", adminUserHandler.UnsuspendUser)
+			admin.PUT("/users/:id/verified", adminUserHandler.UpdateUserVerified)
+			admin.PUT("/users/:id/age-verification", adminUserHandler.UpdateUserAgeVerification)
+			admin.POST("/users/:id/password-reset", adminUserHandler.ForcePasswordReset)
+			admin.PUT("/users/:id/role", adminUserHandler.UpdateUserRole)
+			admin.POST("/users/:id/merge", adminUserHandler.MergeUser)
+			admin.GET("/merges/:id", adminUserHandler.GetAccountMerge)
+			admin.GET("/audit-logs", adminUserHandler.ListAuditLogs)
+			admin.POST("/media/purge", adminMediaHandler.PurgeMedia)
+			admin.GET("/content-filter/rules", adminContentFilterHandler.ListRules)
+			admin.POST("/content-filter/rules", adminContentFilterHandler.CreateRule)
+			admin.PUT("/content-filter/rules/:id", adminContentFilterHandler.UpdateRule)
+			admin.DELETE("/content-filter/rules/:id", adminContentFilterHandler.DeleteRule)
+			admin.POST("/content-filter/test", adminContentFilterHandler.TestRules)
+			admin.POST("/topics", adminTopicHandler.CreateTopic)
+			admin.PUT("/topics/:id", adminTopicHandler.UpdateTopic)
+			admin.DELETE("/topics/:id", adminTopicHandler.DeleteTopic)
+		}
+
+		// 通報の対応（モデレーター以上）
+		reports := secured.Group("/admin/reports")
+		reports.Use(middleware.RequireRole(cfg.Admin.UserIDs, log, models.UserRoleModerator))
+		{
+			reports.GET("", reportHandler.ListReports)
+			reports.POST("/:id/resolve", reportHandler.ResolveReport)
+		}
+
+		// 投稿のモデレーション（モデレーター以上）
+		moderatedPosts := secured.Group("/admin/posts")
+		moderatedPosts.Use(middleware.RequireRole(cfg.Admin.UserIDs, log, models.UserRoleModerator))
+		{
+			moderatedPosts.GET("", adminPostHandler.ListModeratedPosts)
+			moderatedPosts.POST("/:id/hide", adminPostHandler.HidePost)
+			moderatedPosts.POST("/:id/remove", adminPostHandler.RemovePost)
+			moderatedPosts.POST("/:id/restore", adminPostHandler.RestorePost)
+			moderatedPosts.PUT("/:id/content-warning", adminPostHandler.UpdateContentWarning)
+			moderatedPosts.PUT("/:id/replies-locked", adminPostHandler.UpdateRepliesLocked)
+		}
 	}
 
-	// WebSocketエンドポイント
-	v1.GET("/ws", middleware.Auth(jwtUtil, log), wsHandler.HandleWSConnection)
+	// WebSocketエンドポイント（認証で拒否された接続もアップグレードの失敗として記録する）
+	v1.GET("/ws", wsHandler.TrackAuthFailures(), middleware.Auth(jwtUtil, accounts, log), wsHandler.HandleWSConnection)
+
+	// SCIMプロビジョニング（IdPからプロビジョニング用のトークンで呼び出す）
+	if cfg.SCIM.Enabled {
+		scimHandler := handlers.NewSCIMHandler(userRepo, systemAccounts, onboarding, analytics, accounts, hub, log)
+
+		scim := r.Group("/scim/v2")
+		scim.Use(middleware.RequireProvisioningToken(cfg.SCIM.Token, log))
+		{
+			scim.GET("/ServiceProviderConfig", scimHandler.GetServiceProviderConfig)
+			scim.GET("/Users", scimHandler.ListUsers)
+			scim.POST("/Users", scimHandler.CreateUser)
+			scim.GET("/Users/:id", scimHandler.GetUser)
+			scim.PUT("/Users/:id", scimHandler.ReplaceUser)
+			scim.PATCH("/Users/:id", scimHandler.PatchUser)
+			scim.DELETE("/Users/:id", scimHandler.DeleteUser)
+		}
+	}
 
 	// 404ハンドラー
 	r.NoRoute(func(c *gin.Context) {
@@ -204,3 +697,16 @@ func SetupRouter(
 
 	return r
 }
+
+// tracedRequest トレースを記録するリクエストか（ヘルスチェック・メディアの配信・APIドキュメントは記録しない）
+func tracedRequest(req *http.Request) bool {
+	path := req.URL.Path
+	switch {
+	case path == "/health", path == "/ready":
+		return false
+	case strings.HasPrefix(path, "/media"), strings.HasPrefix(path, "/swagger/"):
+		return false
+	default:
+		return true
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,14 +11,43 @@ import (
 
 // アプリケーション設定を表す構造体
 type Config struct {
-	App       AppConfig
-	DB        DBConfig
-	Redis     RedisConfig
-	JWT       JWTConfig
-	CORS      CORSConfig
-	Log       LogConfig
-	RateLimit RateLimitConfig
-	Storage   StorageConfig
+	App        AppConfig
+	DB         DBConfig
+	Redis      RedisConfig
+	JWT        JWTConfig
+	CORS       CORSConfig
+	Log        LogConfig
+	Tracing    TracingConfig
+	RateLimit  RateLimitConfig
+	WebSocket  WebSocketConfig
+	Presence   PresenceConfig
+	Storage    StorageConfig
+	Content    ContentConfig
+	Views      ViewsConfig
+	Usage      UsageConfig
+	Admin      AdminConfig
+	Stats      StatsConfig
+	Supporters SupportersConfig
+	Search     SearchConfig
+	Timeline   TimelineConfig
+	Threads    ThreadsConfig
+	Visitors   VisitorsConfig
+	Follows    FollowsConfig
+	System     SystemConfig
+	Accounts   AccountsConfig
+	SSO        SSOConfig
+	SCIM       SCIMConfig
+	Onboarding OnboardingConfig
+	Analytics  AnalyticsConfig
+	Webhooks   WebhooksConfig
+	Posts      PostsConfig
+	Sync       SyncConfig
+	Push       PushConfig
+	Email      EmailConfig
+	Outbox     OutboxConfig
+	Jobs       JobsConfig
+	Scheduler  SchedulerConfig
+	Swagger    SwaggerConfig
 }
 
 // アプリケーション固有の設定を保持する構造体
@@ -36,6 +66,28 @@ type DBConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// EXPLAINをログに出力するクエリの割合（0〜1、0で無効）
+	QuerySampleRate float64
+	// EXPLAIN ANALYZEで実際にクエリを実行して計測するか（開発環境のみ有効）
+	ExplainAnalyze bool
+	// 一時的なエラー（シリアライズ失敗や接続のリセットなど）で失敗したクエリの最大試行回数
+	RetryMaxAttempts int
+	// 1回目の再試行までの待ち時間（以降は2倍ずつ増やす）
+	RetryBaseDelay time.Duration
+	// 接続プールの死活確認の間隔
+	HealthCheckInterval time.Duration
+	// レディネスを落として接続を張り直すまでに許容する死活確認の連続失敗回数
+	HealthFailureThreshold int
+	// 接続プーラーとの互換モード（session: 無効、transaction: pgbouncerのトランザクションプーリング向けに有効、auto: 起動時に検出した場合のみ有効）
+	PoolMode string
+	// 読み取り専用のレプリカの接続文字列（DSN）。設定した場合はタイムライン・プロフィール・検索の読み取りをレプリカで実行する（空の場合はすべてプライマリ）
+	ReplicaURL string
+	// PostgreSQLのstatement_timeout（サーバー側で文を打ち切るまでの時間、0で無効）
+	StatementTimeout time.Duration
+	// トランザクション外のクエリ1回あたりの最大実行時間（コンテキストの期限、0で無効）
+	QueryTimeout time.Duration
+	// ログに出力する低速なクエリの実行時間のしきい値（0で無効）
+	SlowQueryThreshold time.Duration
 }
 
 // Redis接続設定を保持する構造体
@@ -64,10 +116,46 @@ type LogConfig struct {
 	Format string
 }
 
+// 分散トレーシング（OpenTelemetry）の設定を保持する構造体
+type TracingConfig struct {
+	// OTLPでトレースを送信するか（無効の場合もトレースIDの伝播は行う）
+	Enabled bool
+	// OTLP/HTTPの送信先（例: http://localhost:4318、空の場合はOTEL_EXPORTER_OTLP_ENDPOINTに従う）
+	Endpoint string
+	// トレースに記録するサービス名
+	ServiceName string
+	// トレースを記録するリクエストの割合（0〜1、上流でサンプリングされたリクエストは常に記録する）
+	SampleRate float64
+}
+
 // レート制限設定を保持する構造体
 type RateLimitConfig struct {
 	Requests int
 	Duration time.Duration
+	// リクエスト数の保存先（memory: プロセス内、redis: 複数のAPIサーバーで共有。Redisに接続できない場合はmemoryにフォールバックする）
+	Backend string
+}
+
+// WebSocket設定を保持する構造体
+type WebSocketConfig struct {
+	// インスタンス間でメッセージを中継するブローカー（memory: 中継しない、redis: Redisのpub/subで複数のAPIサーバーのクライアントに配信する）
+	Broker string
+	// 中継に使うRedisのチャネル
+	Channel string
+	// 再接続したクライアントに再送するため通知を保存する期間と、ユーザーごとの件数の上限
+	// （brokerがredisの場合はRedisに保存して複数のAPIサーバーで共有する）
+	ReplayTTL       time.Duration
+	ReplayMaxEvents int
+	// ユーザーごとの同時接続数の上限（APIサーバーごと、0の場合は制限しない。超えた場合は古い接続から切断する）
+	MaxConnectionsPerUser int
+}
+
+// オンライン状態の設定を保持する構造体
+type PresenceConfig struct {
+	// 最後に記録してからオンラインとして扱う期間（Redisで複数のAPIサーバーの接続を共有する場合）
+	TTL time.Duration
+	// 接続中のユーザーのオンライン状態を延長し、最終ログイン日時を更新する間隔
+	RefreshInterval time.Duration
 }
 
 // ストレージ設定を保持する構造体
@@ -75,6 +163,345 @@ type StorageConfig struct {
 	Provider string
 	BaseDir  string
 	BaseURL  string
+	// 動画のサムネイルの抽出に使うffmpegのパス（空の場合はサムネイルを抽出しない）
+	FFmpegPath string
+	// 分割アップロードのパートを結合までの間保存するディレクトリ、1パートの大きさ、アップロードの有効期間
+	UploadPartsDir   string
+	UploadPartSize   int64
+	UploadSessionTTL time.Duration
+	// ユーザーごとのメディアの使用量の上限（0の場合は上限なし）
+	UserQuota int64
+	// セカンダリへの非同期複製を有効にするか
+	ReplicaEnabled bool
+	// セカンダリの保存先ディレクトリと公開URL
+	ReplicaBaseDir string
+	ReplicaBaseURL string
+	// 複製のワーカー数・キューの長さ・1件あたりの最大試行回数
+	ReplicaWorkers     int
+	ReplicaQueueSize   int
+	ReplicaMaxAttempts int
+	// プライマリの死活確認の間隔
+	ReplicaHealthInterval time.Duration
+}
+
+// コンテンツ閲覧制限の設定を保持する構造体
+type ContentConfig struct {
+	// 制限付きコンテンツを閲覧できる最低年齢
+	MinimumAge int
+	// 国コードごとの最低年齢（既定値を上書きする）
+	CountryMinimumAges map[string]int
+	// 禁止語ルールをデータベースから読み直す間隔
+	FilterRefreshInterval time.Duration
+}
+
+// 投稿の閲覧数集計の設定を保持する構造体
+type ViewsConfig struct {
+	// 閲覧数をデータベースへ書き込む間隔
+	FlushInterval time.Duration
+	// この件数の投稿が溜まった場合は間隔を待たずに書き込む
+	MaxPending int
+}
+
+// APIの利用状況の集計の設定を保持する構造体
+type UsageConfig struct {
+	// 利用状況をデータベースへ書き込む間隔
+	FlushInterval time.Duration
+	// この件数の集計が溜まった場合は間隔を待たずに書き込む
+	MaxPending int
+}
+
+// 管理者の設定を保持する構造体
+type AdminConfig struct {
+	// 管理者として扱うユーザーのID
+	UserIDs []string
+}
+
+// 統計集計の設定を保持する構造体
+type StatsConfig struct {
+	// コホート統計を毎日集計する時刻（UTCの時）
+	RollupHour int
+	// インスタンスディレクトリ向けに統計（ユーザー数・投稿数・アクティブユーザー数）を公開するか
+	PublicEnabled bool
+	// 公開統計を集計し直す間隔（公開エンドポイントはこの間隔でキャッシュしてよい）
+	PublicRefreshInterval time.Duration
+}
+
+// サポーター（寄付者）機能の設定を保持する構造体
+type SupportersConfig struct {
+	// 決済サービスのWebhook署名の検証に使用するシークレット（未設定の場合はWebhookをすべて拒否する）
+	WebhookSecret string
+}
+
+// 検索機能の設定を保持する構造体
+type SearchConfig struct {
+	// 保存した検索の新着投稿を確認する間隔
+	SavedCheckInterval time.Duration
+	// 投稿・ユーザーの検索に使う検索エンジン（postgres・elasticsearch）
+	Backend string
+	// Backendがelasticsearchの場合の接続先（OpenSearchも可）と認証情報（ユーザー名が空の場合は認証しない）
+	ElasticsearchURL      string
+	ElasticsearchUsername string
+	ElasticsearchPassword string
+	// 索引の名前の接頭辞（「接頭辞+posts」「接頭辞+users」の索引を使う）
+	IndexPrefix string
+	// 索引を更新するワーカー数と、更新を待つキューの大きさ
+	IndexWorkers   int
+	IndexQueueSize int
+	// 起動時にすべての投稿・ユーザーを索引に登録し直すかどうか
+	ReindexOnStart bool
+}
+
+// ホームタイムラインのキャッシュ（Redis）の設定を保持する構造体
+type TimelineConfig struct {
+	// 投稿時にフォロワーのタイムラインのキャッシュへ配信するかどうか
+	CacheEnabled bool
+	// キャッシュからホームタイムラインを読み出すフォロー数の下限
+	CacheMinFollowing int
+	// ユーザーごとにキャッシュする投稿の最大件数
+	CacheMaxLength int
+	// キャッシュの有効期間
+	CacheTTL time.Duration
+	// 配信を行うワーカー数
+	FanoutWorkers int
+	// 配信待ちの投稿を保持するキューの大きさ
+	FanoutQueueSize int
+}
+
+// スレッドの展開（リーダーモード）の設定を保持する構造体
+type ThreadsConfig struct {
+	// 1つのスレッドとして展開する投稿の最大件数
+	UnrollMaxPosts int
+	// スレッドの構造のキャッシュ（Redis）の有効期間（タイムラインのキャッシュが無効な場合はキャッシュしない）
+	UnrollCacheTTL time.Duration
+}
+
+// プロフィール訪問者の表示の設定を保持する構造体
+type VisitorsConfig struct {
+	// プロフィールの閲覧を訪問として記録する割合（0〜1）
+	SampleRate float64
+	// 訪問履歴を保持する期間
+	Retention time.Duration
+	// プロフィールごとに保持する訪問者の最大数
+	MaxPerUser int
+}
+
+// フォローイベントの射影の設定を保持する構造体
+type FollowsConfig struct {
+	// 未反映のフォローイベントを反映する間隔
+	ProjectInterval time.Duration
+	// 1回に反映するイベントの最大数
+	ProjectBatchSize int
+}
+
+// システムアカウントの設定を保持する構造体
+type SystemConfig struct {
+	// 起動時に作成するシステムアカウントのユーザー名
+	Accounts []string
+	// お知らせを投稿するシステムアカウントのユーザー名
+	AnnouncementsAccount string
+	// システムアカウント以外に登録できないユーザー名
+	ReservedUsernames []string
+}
+
+// アカウントの無効化・削除・統合の設定を保持する構造体
+type AccountsConfig struct {
+	// 無効化したアカウントにログインして再開できる期間
+	ReactivationGracePeriod time.Duration
+	// 削除待ちのアカウントを確認する間隔
+	DeletionPollInterval time.Duration
+	// 削除に失敗した場合に再試行する最大回数
+	DeletionMaxAttempts int
+	// 統合待ちのアカウントを確認する間隔
+	MergePollInterval time.Duration
+	// 統合に失敗した場合に再試行する最大回数
+	MergeMaxAttempts int
+}
+
+// 外部のOpenIDプロバイダーによるシングルサインオンの設定を保持する構造体
+// 有効な場合はシングルサインオンが唯一のログイン方法になり、パスワードでの登録・ログインはできない
+type SSOConfig struct {
+	Enabled bool
+	// OpenIDプロバイダーの発行者URL（ディスカバリーに使用する）
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// プロバイダーから戻るコールバックURL（/api/v1/auth/sso/callback）
+	RedirectURL string
+	Scopes      []string
+	// グループの一覧を含むIDトークンのクレーム名
+	GroupsClaim string
+	// ログインを許可するグループ（空の場合はすべてのユーザーを許可する）
+	AllowedGroups []string
+	// 管理者の権限を付与するグループ
+	AdminGroups []string
+	// モデレーターの権限を付与するグループ（管理者のグループが優先される）
+	ModeratorGroups []string
+	// ログイン後にトークンを付けてリダイレクトするフロントエンドのURL（空の場合はJSONで返す）
+	PostLoginRedirectURL string
+}
+
+// IdPからユーザーを管理するSCIM 2.0エンドポイント（/scim/v2）の設定を保持する構造体
+type SCIMConfig struct {
+	Enabled bool
+	// IdPがBearerトークンとして送信するプロビジョニング用のトークン
+	Token string
+}
+
+// 新しいユーザーのオンボーディングの設定を保持する構造体
+// システムアカウント（お知らせなど）の投稿はフォローしなくても全ユーザーのホームタイムラインに表示されるため、ここに指定する必要はない
+type OnboardingConfig struct {
+	// 運営が選んだおすすめのアカウント（ユーザー名）。フォロワーの多いアカウントより先に提案する
+	SuggestedAccounts []string
+	// 登録時に自動でフォローするアカウント（ユーザー名）
+	AutoFollowAccounts []string
+	// 一度に提案するユーザー数の上限
+	SuggestionLimit int
+	// フォローの手順の完了に必要なフォロー数
+	MinFollows int
+}
+
+// 分析イベント（インプレッション・クリック・登録）の送信先の設定を保持する構造体
+type AnalyticsConfig struct {
+	// 送信先（none・postgres・log・http）
+	Sink string
+	// Sinkがlogの場合にJSON Linesで追記するファイル
+	LogPath string
+	// Sinkがhttpの場合の収集サーバーのURLと、Bearerトークンとして送信するトークン
+	HTTPEndpoint string
+	HTTPToken    string
+	// 送信を待つイベントの最大数（超えた場合は破棄する）
+	BufferSize int
+	// 一度に送信するイベント数と、送信する間隔
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// ユーザーが自分宛てのイベント（フォロー・メンション）を受け取る個人用Webhookの設定を保持する構造体
+type WebhooksConfig struct {
+	// ユーザーごとに登録できるWebhookの最大数
+	MaxPerUser int
+	// 1回の送信にかける最大時間
+	Timeout time.Duration
+	// 連続して送信に失敗した場合にWebhookを無効にする回数
+	MaxFailures int
+	// 送信ワーカー数と、送信を待つイベントの最大数（超えた場合は破棄する）
+	Workers   int
+	QueueSize int
+	// プライベートネットワーク（localhostなど）のURLへの送信を許可するか（開発用）
+	AllowPrivateNetworks bool
+}
+
+// 投稿の設定を保持する構造体
+type PostsConfig struct {
+	// 有効期限を過ぎた投稿を削除する間隔と、1回に削除する最大件数
+	ExpirationPurgeInterval  time.Duration
+	ExpirationPurgeBatchSize int
+}
+
+// クライアントの差分同期の設定を保持する構造体
+type SyncConfig struct {
+	// 変更のイベントを保持する期間（これより古いカーソルのクライアントはすべて取得し直す）
+	Retention time.Duration
+	// 記録されてからクライアントに返すまでの待ち時間（コミットの順序の前後による取りこぼしを防ぐ）
+	SettleDelay time.Duration
+}
+
+// モバイル端末へのプッシュ通知の設定を保持する構造体
+// FCM・APNsのどちらも設定されていない場合はプッシュ通知を無効とする
+type PushConfig struct {
+	// FCM（Android）のサービスアカウントの鍵ファイル（JSON）のパス
+	FCMCredentialsFile string
+	// APNs（iOS）の認証キー（.p8）のパス・キーID・チームID・アプリのバンドルID
+	APNsKeyFile  string
+	APNsKeyID    string
+	APNsTeamID   string
+	APNsBundleID string
+	// APNsの開発用の環境へ送信するか
+	APNsSandbox bool
+	// 1回の送信にかける最大時間
+	Timeout time.Duration
+	// 一時的な失敗を含めて送信を試みる最大回数と、最初の再送までの待ち時間（再送するたびに倍にする）
+	MaxAttempts int
+	RetryDelay  time.Duration
+	// 送信ワーカー数と、送信を待つ通知の最大数（超えた場合は破棄する）
+	Workers   int
+	QueueSize int
+}
+
+// メール通知の設定を保持する構造体
+// SMTPサーバーが設定されていない場合はメール通知を無効とする
+type EmailConfig struct {
+	// SMTPサーバーのホスト名・ポート番号と認証情報（ユーザー名が空の場合は認証しない）
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// 送信元のメールアドレスと表示名
+	FromAddress string
+	FromName    string
+	// 1通の送信にかける最大時間
+	Timeout time.Duration
+	// 送信ワーカー数と、送信を待つ通知の最大数（超えた場合は破棄する）
+	Workers   int
+	QueueSize int
+}
+
+// 通知の配信（アウトボックス）の設定を保持する構造体
+type OutboxConfig struct {
+	// 配信待ちのイベントを確認する間隔（通知の作成時はコミット後すぐにも確認する）
+	PollInterval time.Duration
+	// 一度に取得するイベントの最大数
+	BatchSize int
+	// 配信を試みる最大回数と、最初の再試行までの待ち時間（再試行するたびに倍にする）
+	MaxAttempts int
+	RetryDelay  time.Duration
+}
+
+// バックグラウンドジョブのキューの設定を保持する構造体
+type JobsConfig struct {
+	// このインスタンスで起動するワーカー数（0の場合はジョブを登録するのみで実行しない）
+	Workers int
+	// 実行待ちのジョブを確認する間隔（ジョブの登録時はコミット後すぐにも確認する）
+	PollInterval time.Duration
+	// 1つのジョブの実行にかける最大時間
+	Timeout time.Duration
+	// 実行を試みる最大回数と、最初の再試行までの待ち時間（再試行するたびに倍にする）
+	MaxAttempts int
+	RetryDelay  time.Duration
+	// 完了・失敗したジョブを保持する期間
+	Retention time.Duration
+}
+
+// 定期実行する保守タスクの設定を保持する構造体
+type ScheduledTaskConfig struct {
+	// タスクを定期実行するか
+	Enabled bool
+	// cron形式の実行時刻（分 時 日 月 曜日、UTC）
+	Schedule string
+}
+
+// 保守タスクのスケジューラーの設定を保持する構造体
+type SchedulerConfig struct {
+	// いいね数・フォロワー数などのカウンターの再計算
+	CounterReconcile ScheduledTaskConfig
+	// 保持期間を過ぎた通知の削除
+	NotificationCleanup ScheduledTaskConfig
+	// ハッシュタグのトレンドの集計
+	TrendAggregation ScheduledTaskConfig
+	// どこからも参照されていないメディアの削除
+	MediaSweep ScheduledTaskConfig
+	// 通知を保持する期間
+	NotificationRetention time.Duration
+	// トレンドの集計を保持する期間
+	TrendRetention time.Duration
+	// アップロード後、参照されていないメディアを削除するまでの猶予
+	MediaGracePeriod time.Duration
+}
+
+// APIドキュメントの設定を保持する構造体
+type SwaggerConfig struct {
+	// /swagger/index.htmlでSwagger UIと生成したOpenAPIドキュメントを配信するか
+	Enabled bool
 }
 
 // 環境変数と.envファイルから設定を読み込む
@@ -109,6 +536,27 @@ func Load() (*Config, error) {
 		Password: viper.GetString("db.password"),
 		Name:     viper.GetString("db.name"),
 		SSLMode:  viper.GetString("db.sslmode"),
+
+		QuerySampleRate: viper.GetFloat64("db.query_sample_rate"),
+		ExplainAnalyze:  viper.GetBool("db.explain_analyze"),
+
+		RetryMaxAttempts:       viper.GetInt("db.retry_max_attempts"),
+		RetryBaseDelay:         time.Duration(viper.GetInt("db.retry_base_delay_ms")) * time.Millisecond,
+		HealthCheckInterval:    time.Duration(viper.GetInt("db.health_check_interval")) * time.Second,
+		HealthFailureThreshold: viper.GetInt("db.health_failure_threshold"),
+
+		PoolMode: strings.ToLower(viper.GetString("db.pool_mode")),
+
+		ReplicaURL: viper.GetString("db.replica_url"),
+
+		StatementTimeout:   time.Duration(viper.GetInt("db.statement_timeout_ms")) * time.Millisecond,
+		QueryTimeout:       time.Duration(viper.GetInt("db.query_timeout_ms")) * time.Millisecond,
+		SlowQueryThreshold: time.Duration(viper.GetInt("db.slow_query_threshold_ms")) * time.Millisecond,
+	}
+	switch config.DB.PoolMode {
+	case "session", "transaction", "auto":
+	default:
+		return nil, fmt.Errorf("DB_POOL_MODEにはsession・transaction・autoのいずれかを指定してください: %s", config.DB.PoolMode)
 	}
 
 	config.Redis = RedisConfig{
@@ -133,20 +581,306 @@ func Load() (*Config, error) {
 		Format: viper.GetString("log.format"),
 	}
 
+	config.Tracing = TracingConfig{
+		Enabled:     viper.GetBool("tracing.enabled"),
+		Endpoint:    viper.GetString("tracing.endpoint"),
+		ServiceName: viper.GetString("tracing.service_name"),
+		SampleRate:  viper.GetFloat64("tracing.sample_rate"),
+	}
+
 	config.RateLimit = RateLimitConfig{
 		Requests: viper.GetInt("rate_limit.requests"),
 		Duration: time.Duration(viper.GetInt("rate_limit.duration")) * time.Second,
+		Backend:  viper.GetString("rate_limit.backend"),
+	}
+
+	config.WebSocket = WebSocketConfig{
+		Broker:                viper.GetString("websocket.broker"),
+		Channel:               viper.GetString("websocket.channel"),
+		ReplayTTL:             time.Duration(viper.GetInt("websocket.replay_ttl")) * time.Second,
+		ReplayMaxEvents:       viper.GetInt("websocket.replay_max_events"),
+		MaxConnectionsPerUser: viper.GetInt("websocket.max_connections_per_user"),
+	}
+
+	config.Presence = PresenceConfig{
+		TTL:             time.Duration(viper.GetInt("presence.ttl")) * time.Second,
+		RefreshInterval: time.Duration(viper.GetInt("presence.refresh_interval")) * time.Second,
 	}
 
 	config.Storage = StorageConfig{
 		Provider: viper.GetString("storage.provider"),
 		BaseDir:  viper.GetString("storage.base_dir"),
 		BaseURL:  viper.GetString("storage.base_url"),
+
+		FFmpegPath:       viper.GetString("storage.ffmpeg_path"),
+		UploadPartsDir:   viper.GetString("storage.upload_parts_dir"),
+		UploadPartSize:   viper.GetInt64("storage.upload_part_size") * 1024 * 1024,
+		UploadSessionTTL: time.Duration(viper.GetInt("storage.upload_session_ttl")) * time.Hour,
+		UserQuota:        viper.GetInt64("storage.user_quota") * 1024 * 1024,
+
+		ReplicaEnabled:        viper.GetBool("storage.replica_enabled"),
+		ReplicaBaseDir:        viper.GetString("storage.replica_base_dir"),
+		ReplicaBaseURL:        viper.GetString("storage.replica_base_url"),
+		ReplicaWorkers:        viper.GetInt("storage.replica_workers"),
+		ReplicaQueueSize:      viper.GetInt("storage.replica_queue_size"),
+		ReplicaMaxAttempts:    viper.GetInt("storage.replica_max_attempts"),
+		ReplicaHealthInterval: time.Duration(viper.GetInt("storage.replica_health_interval")) * time.Second,
+	}
+
+	config.Content = ContentConfig{
+		MinimumAge:            viper.GetInt("content.minimum_age"),
+		CountryMinimumAges:    parseCountryAges(viper.GetStringSlice("content.country_minimum_ages")),
+		FilterRefreshInterval: time.Duration(viper.GetInt("content.filter_refresh_interval")) * time.Second,
+	}
+
+	config.Views = ViewsConfig{
+		FlushInterval: time.Duration(viper.GetInt("views.flush_interval")) * time.Second,
+		MaxPending:    viper.GetInt("views.max_pending"),
+	}
+
+	config.Usage = UsageConfig{
+		FlushInterval: time.Duration(viper.GetInt("usage.flush_interval")) * time.Second,
+		MaxPending:    viper.GetInt("usage.max_pending"),
+	}
+
+	config.Admin = AdminConfig{
+		UserIDs: parseList(viper.GetStringSlice("admin.user_ids")),
+	}
+
+	config.Stats = StatsConfig{
+		RollupHour:            viper.GetInt("stats.rollup_hour"),
+		PublicEnabled:         viper.GetBool("stats.public_enabled"),
+		PublicRefreshInterval: time.Duration(viper.GetInt("stats.public_refresh_interval")) * time.Second,
+	}
+
+	config.Supporters = SupportersConfig{
+		WebhookSecret: viper.GetString("supporters.webhook_secret"),
+	}
+
+	config.Search = SearchConfig{
+		SavedCheckInterval:    time.Duration(viper.GetInt("search.saved_check_interval")) * time.Second,
+		Backend:               viper.GetString("search.backend"),
+		ElasticsearchURL:      viper.GetString("search.elasticsearch_url"),
+		ElasticsearchUsername: viper.GetString("search.elasticsearch_username"),
+		ElasticsearchPassword: viper.GetString("search.elasticsearch_password"),
+		IndexPrefix:           viper.GetString("search.index_prefix"),
+		IndexWorkers:          viper.GetInt("search.index_workers"),
+		IndexQueueSize:        viper.GetInt("search.index_queue_size"),
+		ReindexOnStart:        viper.GetBool("search.reindex_on_start"),
+	}
+	if config.Search.Backend == "elasticsearch" && config.Search.ElasticsearchURL == "" {
+		return nil, fmt.Errorf("SEARCH_BACKEND=elasticsearchの場合はSEARCH_ELASTICSEARCH_URLを設定してください")
+	}
+
+	config.Timeline = TimelineConfig{
+		CacheEnabled:      viper.GetBool("timeline.cache_enabled"),
+		CacheMinFollowing: viper.GetInt("timeline.cache_min_following"),
+		CacheMaxLength:    viper.GetInt("timeline.cache_max_length"),
+		CacheTTL:          time.Duration(viper.GetInt("timeline.cache_ttl")) * time.Second,
+		FanoutWorkers:     viper.GetInt("timeline.fanout_workers"),
+		FanoutQueueSize:   viper.GetInt("timeline.fanout_queue_size"),
+	}
+
+	config.Threads = ThreadsConfig{
+		UnrollMaxPosts: viper.GetInt("threads.unroll_max_posts"),
+		UnrollCacheTTL: time.Duration(viper.GetInt("threads.unroll_cache_ttl")) * time.Second,
+	}
+
+	config.Visitors = VisitorsConfig{
+		SampleRate: viper.GetFloat64("visitors.sample_rate"),
+		Retention:  time.Duration(viper.GetInt("visitors.retention_days")) * 24 * time.Hour,
+		MaxPerUser: viper.GetInt("visitors.max_per_user"),
+	}
+
+	config.Follows = FollowsConfig{
+		ProjectInterval:  time.Duration(viper.GetInt("follows.project_interval")) * time.Second,
+		ProjectBatchSize: viper.GetInt("follows.project_batch_size"),
+	}
+
+	config.System = SystemConfig{
+		Accounts:             parseList(viper.GetStringSlice("system.accounts")),
+		AnnouncementsAccount: viper.GetString("system.announcements_account"),
+		ReservedUsernames:    parseList(viper.GetStringSlice("system.reserved_usernames")),
+	}
+
+	config.Accounts = AccountsConfig{
+		ReactivationGracePeriod: time.Duration(viper.GetInt("accounts.reactivation_grace_days")) * 24 * time.Hour,
+		DeletionPollInterval:    time.Duration(viper.GetInt("accounts.deletion_poll_interval")) * time.Second,
+		DeletionMaxAttempts:     viper.GetInt("accounts.deletion_max_attempts"),
+		MergePollInterval:       time.Duration(viper.GetInt("accounts.merge_poll_interval")) * time.Second,
+		MergeMaxAttempts:        viper.GetInt("accounts.merge_max_attempts"),
+	}
+
+	config.SSO = SSOConfig{
+		Enabled:              viper.GetBool("sso.enabled"),
+		Issuer:               viper.GetString("sso.issuer"),
+		ClientID:             viper.GetString("sso.client_id"),
+		ClientSecret:         viper.GetString("sso.client_secret"),
+		RedirectURL:          viper.GetString("sso.redirect_url"),
+		Scopes:               parseList(viper.GetStringSlice("sso.scopes")),
+		GroupsClaim:          viper.GetString("sso.groups_claim"),
+		AllowedGroups:        parseList(viper.GetStringSlice("sso.allowed_groups")),
+		AdminGroups:          parseList(viper.GetStringSlice("sso.admin_groups")),
+		ModeratorGroups:      parseList(viper.GetStringSlice("sso.moderator_groups")),
+		PostLoginRedirectURL: viper.GetString("sso.post_login_redirect_url"),
+	}
+	if config.SSO.Enabled && (config.SSO.Issuer == "" || config.SSO.ClientID == "" || config.SSO.RedirectURL == "") {
+		return nil, fmt.Errorf("シングルサインオンを有効にする場合はSSO_ISSUER・SSO_CLIENT_ID・SSO_REDIRECT_URLを設定してください")
+	}
+
+	config.SCIM = SCIMConfig{
+		Enabled: viper.GetBool("scim.enabled"),
+		Token:   viper.GetString("scim.token"),
+	}
+	if config.SCIM.Enabled && len(config.SCIM.Token) < 32 {
+		return nil, fmt.Errorf("SCIMを有効にする場合はSCIM_TOKENに32文字以上のトークンを設定してください")
+	}
+
+	config.Onboarding = OnboardingConfig{
+		SuggestedAccounts:  parseList(viper.GetStringSlice("onboarding.suggested_accounts")),
+		AutoFollowAccounts: parseList(viper.GetStringSlice("onboarding.auto_follow_accounts")),
+		SuggestionLimit:    viper.GetInt("onboarding.suggestion_limit"),
+		MinFollows:         viper.GetInt("onboarding.min_follows"),
+	}
+
+	config.Analytics = AnalyticsConfig{
+		Sink:          viper.GetString("analytics.sink"),
+		LogPath:       viper.GetString("analytics.log_path"),
+		HTTPEndpoint:  viper.GetString("analytics.http_endpoint"),
+		HTTPToken:     viper.GetString("analytics.http_token"),
+		BufferSize:    viper.GetInt("analytics.buffer_size"),
+		BatchSize:     viper.GetInt("analytics.batch_size"),
+		FlushInterval: time.Duration(viper.GetInt("analytics.flush_interval")) * time.Second,
+	}
+	if config.Analytics.Sink == "http" && config.Analytics.HTTPEndpoint == "" {
+		return nil, fmt.Errorf("ANALYTICS_SINK=httpの場合はANALYTICS_HTTP_ENDPOINTを設定してください")
+	}
+
+	config.Webhooks = WebhooksConfig{
+		MaxPerUser:           viper.GetInt("webhooks.max_per_user"),
+		Timeout:              time.Duration(viper.GetInt("webhooks.timeout")) * time.Second,
+		MaxFailures:          viper.GetInt("webhooks.max_failures"),
+		Workers:              viper.GetInt("webhooks.workers"),
+		QueueSize:            viper.GetInt("webhooks.queue_size"),
+		AllowPrivateNetworks: viper.GetBool("webhooks.allow_private_networks"),
+	}
+
+	config.Posts = PostsConfig{
+		ExpirationPurgeInterval:  time.Duration(viper.GetInt("posts.expiration_purge_interval")) * time.Second,
+		ExpirationPurgeBatchSize: viper.GetInt("posts.expiration_purge_batch_size"),
+	}
+
+	config.Sync = SyncConfig{
+		Retention:   time.Duration(viper.GetInt("sync.retention_days")) * 24 * time.Hour,
+		SettleDelay: time.Duration(viper.GetInt("sync.settle_delay")) * time.Second,
+	}
+
+	config.Push = PushConfig{
+		FCMCredentialsFile: viper.GetString("push.fcm_credentials_file"),
+		APNsKeyFile:        viper.GetString("push.apns_key_file"),
+		APNsKeyID:          viper.GetString("push.apns_key_id"),
+		APNsTeamID:         viper.GetString("push.apns_team_id"),
+		APNsBundleID:       viper.GetString("push.apns_bundle_id"),
+		APNsSandbox:        viper.GetBool("push.apns_sandbox"),
+		Timeout:            time.Duration(viper.GetInt("push.timeout")) * time.Second,
+		MaxAttempts:        viper.GetInt("push.max_attempts"),
+		RetryDelay:         time.Duration(viper.GetInt("push.retry_delay")) * time.Second,
+		Workers:            viper.GetInt("push.workers"),
+		QueueSize:          viper.GetInt("push.queue_size"),
+	}
+
+	config.Email = EmailConfig{
+		SMTPHost:     viper.GetString("email.smtp_host"),
+		SMTPPort:     viper.GetInt("email.smtp_port"),
+		SMTPUsername: viper.GetString("email.smtp_username"),
+		SMTPPassword: viper.GetString("email.smtp_password"),
+		FromAddress:  viper.GetString("email.from_address"),
+		FromName:     viper.GetString("email.from_name"),
+		Timeout:      time.Duration(viper.GetInt("email.timeout")) * time.Second,
+		Workers:      viper.GetInt("email.workers"),
+		QueueSize:    viper.GetInt("email.queue_size"),
+	}
+	if config.Email.SMTPHost != "" && config.Email.FromAddress == "" {
+		return nil, fmt.Errorf("EMAIL_SMTP_HOSTを設定した場合はEMAIL_FROM_ADDRESSを設定してください")
+	}
+
+	config.Outbox = OutboxConfig{
+		PollInterval: time.Duration(viper.GetInt("outbox.poll_interval")) * time.Second,
+		BatchSize:    viper.GetInt("outbox.batch_size"),
+		MaxAttempts:  viper.GetInt("outbox.max_attempts"),
+		RetryDelay:   time.Duration(viper.GetInt("outbox.retry_delay")) * time.Second,
+	}
+
+	config.Jobs = JobsConfig{
+		Workers:      viper.GetInt("jobs.workers"),
+		PollInterval: time.Duration(viper.GetInt("jobs.poll_interval")) * time.Second,
+		Timeout:      time.Duration(viper.GetInt("jobs.timeout")) * time.Second,
+		MaxAttempts:  viper.GetInt("jobs.max_attempts"),
+		RetryDelay:   time.Duration(viper.GetInt("jobs.retry_delay")) * time.Second,
+		Retention:    time.Duration(viper.GetInt("jobs.retention_days")) * 24 * time.Hour,
+	}
+
+	config.Scheduler = SchedulerConfig{
+		CounterReconcile: ScheduledTaskConfig{
+			Enabled:  viper.GetBool("scheduler.counter_reconcile_enabled"),
+			Schedule: viper.GetString("scheduler.counter_reconcile_schedule"),
+		},
+		NotificationCleanup: ScheduledTaskConfig{
+			Enabled:  viper.GetBool("scheduler.notification_cleanup_enabled"),
+			Schedule: viper.GetString("scheduler.notification_cleanup_schedule"),
+		},
+		TrendAggregation: ScheduledTaskConfig{
+			Enabled:  viper.GetBool("scheduler.trend_aggregation_enabled"),
+			Schedule: viper.GetString("scheduler.trend_aggregation_schedule"),
+		},
+		MediaSweep: ScheduledTaskConfig{
+			Enabled:  viper.GetBool("scheduler.media_sweep_enabled"),
+			Schedule: viper.GetString("scheduler.media_sweep_schedule"),
+		},
+		NotificationRetention: time.Duration(viper.GetInt("scheduler.notification_retention_days")) * 24 * time.Hour,
+		TrendRetention:        time.Duration(viper.GetInt("scheduler.trend_retention_days")) * 24 * time.Hour,
+		MediaGracePeriod:      time.Duration(viper.GetInt("scheduler.media_grace_period")) * time.Hour,
+	}
+
+	config.Swagger = SwaggerConfig{
+		Enabled: viper.GetBool("swagger.enabled"),
 	}
 
 	return &config, nil
 }
 
+// カンマ区切りの設定値をリストに変換する
+func parseList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				list = append(list, entry)
+			}
+		}
+	}
+	return list
+}
+
+// "KR:19" 形式の設定値を国コードと年齢のマップに変換する
+func parseCountryAges(values []string) map[string]int {
+	ages := make(map[string]int)
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			age, err := strconv.Atoi(parts[1])
+			if err != nil {
+				continue
+			}
+			ages[strings.ToUpper(parts[0])] = age
+		}
+	}
+	return ages
+}
+
 // 設定のデフォルト値を設定する
 func setDefaults() {
 	// アプリケーションのデフォルト値
@@ -162,6 +896,17 @@ func setDefaults() {
 	viper.SetDefault("db.password", "postgres")
 	viper.SetDefault("db.name", "gox")
 	viper.SetDefault("db.sslmode", "disable")
+	viper.SetDefault("db.query_sample_rate", 0)
+	viper.SetDefault("db.explain_analyze", true)
+	viper.SetDefault("db.retry_max_attempts", 3)
+	viper.SetDefault("db.retry_base_delay_ms", 50)
+	viper.SetDefault("db.health_check_interval", 5)
+	viper.SetDefault("db.health_failure_threshold", 3)
+	viper.SetDefault("db.pool_mode", "session")
+	viper.SetDefault("db.replica_url", "")
+	viper.SetDefault("db.statement_timeout_ms", 5000)
+	viper.SetDefault("db.query_timeout_ms", 10000)
+	viper.SetDefault("db.slow_query_threshold_ms", 500)
 
 	// Redisのデフォルト値
 	viper.SetDefault("redis.host", "localhost")
@@ -180,12 +925,186 @@ func setDefaults() {
 	viper.SetDefault("log.level", "debug")
 	viper.SetDefault("log.format", "json")
 
+	// 分散トレーシングのデフォルト値
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.service_name", "gox")
+	viper.SetDefault("tracing.sample_rate", 0.1)
+
 	// レート制限のデフォルト値
 	viper.SetDefault("rate_limit.requests", 100)
 	viper.SetDefault("rate_limit.duration", 60)
+	viper.SetDefault("rate_limit.backend", "memory")
+
+	// WebSocketのデフォルト値
+	viper.SetDefault("websocket.broker", "memory")
+	viper.SetDefault("websocket.channel", "gox:websocket")
+	viper.SetDefault("websocket.replay_ttl", 300)
+	viper.SetDefault("websocket.replay_max_events", 100)
+	viper.SetDefault("websocket.max_connections_per_user", 5)
+
+	// オンライン状態のデフォルト値
+	viper.SetDefault("presence.ttl", 90)
+	viper.SetDefault("presence.refresh_interval", 30)
 
 	// ストレージのデフォルト値
 	viper.SetDefault("storage.provider", "local")
 	viper.SetDefault("storage.base_dir", "./uploads")
 	viper.SetDefault("storage.base_url", "http://localhost:8080/media")
+	viper.SetDefault("storage.replica_enabled", false)
+	viper.SetDefault("storage.replica_base_dir", "./uploads-replica")
+	viper.SetDefault("storage.replica_base_url", "http://localhost:8080/media-replica")
+	viper.SetDefault("storage.replica_workers", 2)
+	viper.SetDefault("storage.replica_queue_size", 1000)
+	viper.SetDefault("storage.replica_max_attempts", 5)
+	viper.SetDefault("storage.replica_health_interval", 30)
+	viper.SetDefault("storage.ffmpeg_path", "")
+	viper.SetDefault("storage.upload_parts_dir", "./uploads-parts")
+	viper.SetDefault("storage.upload_part_size", 5)
+	viper.SetDefault("storage.upload_session_ttl", 24)
+	viper.SetDefault("storage.user_quota", 1024)
+
+	// コンテンツ閲覧制限のデフォルト値
+	viper.SetDefault("content.minimum_age", 18)
+	viper.SetDefault("content.country_minimum_ages", []string{})
+	viper.SetDefault("content.filter_refresh_interval", 60)
+
+	// 閲覧数集計のデフォルト値
+	viper.SetDefault("views.flush_interval", 10)
+	viper.SetDefault("views.max_pending", 1000)
+	viper.SetDefault("usage.flush_interval", 60)
+	viper.SetDefault("usage.max_pending", 5000)
+
+	// 管理者のデフォルト値
+	viper.SetDefault("admin.user_ids", []string{})
+
+	// 統計集計のデフォルト値
+	viper.SetDefault("stats.rollup_hour", 3)
+	viper.SetDefault("stats.public_enabled", true)
+	viper.SetDefault("stats.public_refresh_interval", 3600)
+
+	// サポーター機能のデフォルト値
+	viper.SetDefault("supporters.webhook_secret", "")
+
+	// 検索機能のデフォルト値
+	viper.SetDefault("search.saved_check_interval", 300)
+	viper.SetDefault("search.backend", "postgres")
+	viper.SetDefault("search.elasticsearch_url", "")
+	viper.SetDefault("search.elasticsearch_username", "")
+	viper.SetDefault("search.elasticsearch_password", "")
+	viper.SetDefault("search.index_prefix", "gox_")
+	viper.SetDefault("search.index_workers", 2)
+	viper.SetDefault("search.index_queue_size", 1000)
+	viper.SetDefault("search.reindex_on_start", false)
+
+	// ホームタイムラインのキャッシュのデフォルト値
+	viper.SetDefault("timeline.cache_enabled", true)
+	viper.SetDefault("timeline.cache_min_following", 100)
+	viper.SetDefault("timeline.cache_max_length", 800)
+	viper.SetDefault("timeline.cache_ttl", 86400)
+	viper.SetDefault("timeline.fanout_workers", 4)
+	viper.SetDefault("timeline.fanout_queue_size", 1000)
+
+	// スレッドの展開のデフォルト値
+	viper.SetDefault("threads.unroll_max_posts", 100)
+	viper.SetDefault("threads.unroll_cache_ttl", 600)
+
+	// プロフィール訪問者の表示のデフォルト値
+	viper.SetDefault("visitors.sample_rate", 1.0)
+	viper.SetDefault("visitors.retention_days", 30)
+	viper.SetDefault("visitors.max_per_user", 100)
+
+	// フォローイベントの射影のデフォルト値
+	viper.SetDefault("follows.project_interval", 5)
+	viper.SetDefault("follows.project_batch_size", 500)
+
+	// システムアカウントのデフォルト値
+	viper.SetDefault("system.accounts", []string{"gox"})
+	viper.SetDefault("system.announcements_account", "gox")
+	viper.SetDefault("system.reserved_usernames", []string{"admin", "administrator", "support", "system", "official", "security", "help"})
+
+	// アカウントの無効化のデフォルト値
+	viper.SetDefault("accounts.reactivation_grace_days", 30)
+	viper.SetDefault("accounts.deletion_poll_interval", 30)
+	viper.SetDefault("accounts.deletion_max_attempts", 5)
+	viper.SetDefault("accounts.merge_poll_interval", 30)
+	viper.SetDefault("accounts.merge_max_attempts", 5)
+
+	// シングルサインオンのデフォルト値
+	viper.SetDefault("sso.enabled", false)
+	viper.SetDefault("sso.scopes", []string{"openid", "profile", "email"})
+	viper.SetDefault("sso.groups_claim", "groups")
+
+	// SCIMのデフォルト値
+	viper.SetDefault("scim.enabled", false)
+
+	// オンボーディングのデフォルト値
+	viper.SetDefault("onboarding.suggestion_limit", 10)
+	viper.SetDefault("onboarding.min_follows", 3)
+
+	// 分析イベントのデフォルト値
+	viper.SetDefault("analytics.sink", "none")
+	viper.SetDefault("analytics.log_path", "./logs/analytics.jsonl")
+	viper.SetDefault("analytics.buffer_size", 10000)
+	viper.SetDefault("analytics.batch_size", 500)
+	viper.SetDefault("analytics.flush_interval", 10)
+
+	// 個人用Webhookのデフォルト値
+	viper.SetDefault("webhooks.max_per_user", 5)
+	viper.SetDefault("webhooks.timeout", 10)
+	viper.SetDefault("webhooks.max_failures", 10)
+	viper.SetDefault("webhooks.workers", 4)
+	viper.SetDefault("webhooks.queue_size", 1000)
+	viper.SetDefault("webhooks.allow_private_networks", false)
+
+	// 有効期限付きの投稿のデフォルト値
+	viper.SetDefault("posts.expiration_purge_interval", 60)
+	viper.SetDefault("posts.expiration_purge_batch_size", 100)
+
+	// 差分同期のデフォルト値
+	viper.SetDefault("sync.retention_days", 30)
+	viper.SetDefault("sync.settle_delay", 2)
+
+	// プッシュ通知のデフォルト値
+	viper.SetDefault("push.apns_sandbox", false)
+	viper.SetDefault("push.timeout", 10)
+	viper.SetDefault("push.max_attempts", 3)
+	viper.SetDefault("push.retry_delay", 1)
+	viper.SetDefault("push.workers", 4)
+	viper.SetDefault("push.queue_size", 1000)
+
+	// メール通知のデフォルト値
+	viper.SetDefault("email.smtp_port", 587)
+	viper.SetDefault("email.timeout", 30)
+	viper.SetDefault("email.workers", 2)
+	viper.SetDefault("email.queue_size", 1000)
+
+	// 通知の配信のデフォルト値
+	viper.SetDefault("outbox.poll_interval", 5)
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.max_attempts", 10)
+	viper.SetDefault("outbox.retry_delay", 5)
+
+	// バックグラウンドジョブのデフォルト値
+	viper.SetDefault("jobs.workers", 4)
+	viper.SetDefault("jobs.poll_interval", 5)
+	viper.SetDefault("jobs.timeout", 300)
+	viper.SetDefault("jobs.max_attempts", 5)
+	viper.SetDefault("jobs.retry_delay", 10)
+	viper.SetDefault("jobs.retention_days", 7)
+
+	// 保守タスクのスケジューラーのデフォルト値
+	viper.SetDefault("scheduler.counter_reconcile_enabled", true)
+	viper.SetDefault("scheduler.counter_reconcile_schedule", "0 4 * * *")
+	viper.SetDefault("scheduler.notification_cleanup_enabled", true)
+	viper.SetDefault("scheduler.notification_cleanup_schedule", "30 3 * * *")
+	viper.SetDefault("scheduler.trend_aggregation_enabled", true)
+	viper.SetDefault("scheduler.trend_aggregation_schedule", "*/10 * * * *")
+	viper.SetDefault("scheduler.media_sweep_enabled", true)
+	viper.SetDefault("scheduler.media_sweep_schedule", "15 * * * *")
+	viper.SetDefault("scheduler.notification_retention_days", 90)
+	viper.SetDefault("scheduler.trend_retention_days", 7)
+	viper.SetDefault("scheduler.media_grace_period", 24)
+
+	// APIドキュメントのデフォルト値
+	viper.SetDefault("swagger.enabled", false)
 }
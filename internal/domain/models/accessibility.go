@@ -0,0 +1,198 @@
+package models
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxAltTextLength is the maximum number of characters of a media alt text
+const MaxAltTextLength = 1500
+
+// languageTagPattern matches BCP 47 style language tags such as "ja", "en-US" or "zh-Hant-TW"
+var languageTagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLanguage lowercases a language tag and reports whether it is well-formed
+func NormalizeLanguage(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if len(tag) > 35 || !languageTagPattern.MatchString(tag) {
+		return "", false
+	}
+	return tag, true
+}
+
+// MediaType represents the kind of an attached media file
+type MediaType string
+
+const (
+	MediaTypeImage   MediaType = "image"
+	MediaTypeGIF     MediaType = "gif"
+	MediaTypeVideo   MediaType = "video"
+	MediaTypeAudio   MediaType = "audio"
+	MediaTypeUnknown MediaType = "unknown"
+)
+
+// mediaTypesByExtension maps lowercase file extensions without the dot to media types
+var mediaTypesByExtension = map[string]MediaType{
+	"jpg":  MediaTypeImage,
+	"jpeg": MediaTypeImage,
+	"png":  MediaTypeImage,
+	"webp": MediaTypeImage,
+	"avif": MediaTypeImage,
+	"heic": MediaTypeImage,
+	"gif":  MediaTypeGIF,
+	"mp4":  MediaTypeVideo,
+	"m4v":  MediaTypeVideo,
+	"mov":  MediaTypeVideo,
+	"webm": MediaTypeVideo,
+	"mp3":  MediaTypeAudio,
+	"m4a":  MediaTypeAudio,
+	"ogg":  MediaTypeAudio,
+	"wav":  MediaTypeAudio,
+}
+
+// mimeTypesByExtension maps lowercase file extensions without the dot to MIME types
+var mimeTypesByExtension = map[string]string{
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"webp": "image/webp",
+	"avif": "image/avif",
+	"heic": "image/heic",
+	"gif":  "image/gif",
+	"mp4":  "video/mp4",
+	"m4v":  "video/x-m4v",
+	"mov":  "video/quicktime",
+	"webm": "video/webm",
+	"mp3":  "audio/mpeg",
+	"m4a":  "audio/mp4",
+	"ogg":  "audio/ogg",
+	"wav":  "audio/wav",
+}
+
+// MediaTypeFromExtension returns the media type for a file extension, with or without the dot
+func MediaTypeFromExtension(ext string) MediaType {
+	if mediaType, ok := mediaTypesByExtension[strings.ToLower(strings.TrimPrefix(ext, "."))]; ok {
+		return mediaType
+	}
+	return MediaTypeUnknown
+}
+
+// MediaTypeFromURL returns the media type for the extension of a media URL's path
+func MediaTypeFromURL(rawURL string) MediaType {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return MediaTypeUnknown
+	}
+	return MediaTypeFromExtension(path.Ext(parsed.Path))
+}
+
+// MimeTypeFromExtension returns the MIME type for a file extension, or an empty string if it is unknown
+func MimeTypeFromExtension(ext string) string {
+	return mimeTypesByExtension[strings.ToLower(strings.TrimPrefix(ext, "."))]
+}
+
+// MimeTypeFromURL returns the MIME type for the extension of a media URL's path
+func MimeTypeFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return MimeTypeFromExtension(path.Ext(parsed.Path))
+}
+
+// SetMediaAltTexts stores the alt texts of the attached media in the same order as MediaURLs
+func (p *Post) SetMediaAltTexts(altTexts []string) {
+	p.MediaAltTexts = make([]string, len(p.MediaURLs))
+	for i := range p.MediaAltTexts {
+		if i < len(altTexts) {
+			p.MediaAltTexts[i] = strings.TrimSpace(altTexts[i])
+		}
+	}
+}
+
+// AltText returns the alt text of the i-th attached media, or an empty string if it has none
+func (p *Post) AltText(i int) string {
+	if i < 0 || i >= len(p.MediaAltTexts) {
+		return ""
+	}
+	return p.MediaAltTexts[i]
+}
+
+// MediaAccessibility describes the accessibility of a single attached media file
+type MediaAccessibility struct {
+	URL        string    `json:"url"`
+	Type       MediaType `json:"type"`
+	HasAltText bool      `json:"has_alt_text"`
+}
+
+// PostAccessibility describes the accessibility of a post and its attached media
+type PostAccessibility struct {
+	PostID uuid.UUID `json:"post_id"`
+	// Language is the BCP 47 language tag chosen by the author, empty if not set
+	Language string `json:"language"`
+	// HasAltText reports whether every attached media file has alt text
+	HasAltText bool                 `json:"has_alt_text"`
+	MediaTypes []MediaType          `json:"media_types"`
+	Media      []MediaAccessibility `json:"media"`
+}
+
+// Accessibility returns the accessibility metadata of the post
+func (p *Post) Accessibility() *PostAccessibility {
+	accessibility := &PostAccessibility{
+		PostID:     p.ID,
+		Language:   p.Language,
+		HasAltText: true,
+		MediaTypes: []MediaType{},
+		Media:      make([]MediaAccessibility, 0, len(p.MediaURLs)),
+	}
+
+	seen := make(map[MediaType]bool)
+	for i, mediaURL := range p.MediaURLs {
+		media := MediaAccessibility{
+			URL:        mediaURL,
+			Type:       MediaTypeFromURL(mediaURL),
+			HasAltText: p.AltText(i) != "",
+		}
+		accessibility.Media = append(accessibility.Media, media)
+		accessibility.HasAltText = accessibility.HasAltText && media.HasAltText
+
+		if !seen[media.Type] {
+			seen[media.Type] = true
+			accessibility.MediaTypes = append(accessibility.MediaTypes, media.Type)
+		}
+	}
+
+	return accessibility
+}
+
+// AccessibilityMediaCount is the number of attached media files with a file extension and how many have alt text
+type AccessibilityMediaCount struct {
+	Extension   string `json:"extension"`
+	Total       int64  `json:"total"`
+	WithAltText int64  `json:"with_alt_text"`
+}
+
+// AccessibilityStats aggregates the accessibility of posts created in a period
+type AccessibilityStats struct {
+	Posts             int64                     `json:"posts"`
+	PostsWithLanguage int64                     `json:"posts_with_language"`
+	PostsWithMedia    int64                     `json:"posts_with_media"`
+	Media             []AccessibilityMediaCount `json:"media"`
+}
+
+// MediaByType sums the media counts by media type
+func (s *AccessibilityStats) MediaByType() map[MediaType]AccessibilityMediaCount {
+	byType := make(map[MediaType]AccessibilityMediaCount)
+	for _, count := range s.Media {
+		mediaType := MediaTypeFromExtension(count.Extension)
+		total := byType[mediaType]
+		total.Total += count.Total
+		total.WithAltText += count.WithAltText
+		byType[mediaType] = total
+	}
+	return byType
+}
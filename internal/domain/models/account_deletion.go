@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountDeletionStatus represents the state of an account deletion job
+type AccountDeletionStatus string
+
+const (
+	// AccountDeletionPending is waiting for the worker (or for the next retry)
+	AccountDeletionPending AccountDeletionStatus = "pending"
+	// AccountDeletionRunning is being processed by the worker
+	AccountDeletionRunning AccountDeletionStatus = "running"
+	// AccountDeletionCompleted has deleted all the account data
+	AccountDeletionCompleted AccountDeletionStatus = "completed"
+	// AccountDeletionFailed has given up after too many failed attempts
+	AccountDeletionFailed AccountDeletionStatus = "failed"
+)
+
+// AccountDeletionStep represents a step of an account deletion
+type AccountDeletionStep string
+
+const (
+	// AccountDeletionStepDisconnect closes the WebSocket connections of the account
+	AccountDeletionStepDisconnect AccountDeletionStep = "disconnect"
+	// AccountDeletionStepMedia deletes the uploaded media files from the storage
+	AccountDeletionStepMedia AccountDeletionStep = "media"
+	// AccountDeletionStepLikes deletes the likes the account has given
+	AccountDeletionStepLikes AccountDeletionStep = "likes"
+	// AccountDeletionStepFollows deletes the follows from and to the account
+	AccountDeletionStepFollows AccountDeletionStep = "follows"
+	// AccountDeletionStepNotifications deletes the notifications sent to and from the account
+	AccountDeletionStepNotifications AccountDeletionStep = "notifications"
+	// AccountDeletionStepPosts deletes the posts of the account
+	AccountDeletionStepPosts AccountDeletionStep = "posts"
+	// AccountDeletionStepAccount deletes the account itself with the remaining data
+	AccountDeletionStepAccount AccountDeletionStep = "account"
+	// AccountDeletionStepDone is set once every step has finished
+	AccountDeletionStepDone AccountDeletionStep = "done"
+)
+
+// AccountDeletionSteps lists the deletion steps in the order they are run
+var AccountDeletionSteps = []AccountDeletionStep{
+	AccountDeletionStepDisconnect,
+	AccountDeletionStepMedia,
+	AccountDeletionStepLikes,
+	AccountDeletionStepFollows,
+	AccountDeletionStepNotifications,
+	AccountDeletionStepPosts,
+	AccountDeletionStepAccount,
+}
+
+// AccountDeletion represents a scheduled deletion of an account and its progress
+type AccountDeletion struct {
+	UserID        uuid.UUID             `json:"user_id"`
+	Status        AccountDeletionStatus `json:"status"`
+	Step          AccountDeletionStep   `json:"step"` // 次に実行する手順
+	MediaTotal    int                   `json:"media_total"`
+	MediaDeleted  int                   `json:"media_deleted"`
+	Attempts      int                   `json:"attempts"`
+	LastError     *string               `json:"-"`
+	NextAttemptAt time.Time             `json:"-"`
+	RequestedAt   time.Time             `json:"requested_at"`
+	StartedAt     *time.Time            `json:"started_at,omitempty"`
+	CompletedAt   *time.Time            `json:"completed_at,omitempty"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+// NewAccountDeletion creates a pending deletion of the given account
+func NewAccountDeletion(userID uuid.UUID) *AccountDeletion {
+	now := time.Now().UTC()
+	return &AccountDeletion{
+		UserID:        userID,
+		Status:        AccountDeletionPending,
+		Step:          AccountDeletionSteps[0],
+		NextAttemptAt: now,
+		RequestedAt:   now,
+		UpdatedAt:     now,
+	}
+}
+
+// StepsCompleted returns how many deletion steps have finished
+func (d *AccountDeletion) StepsCompleted() int {
+	if d.Step == AccountDeletionStepDone {
+		return len(AccountDeletionSteps)
+	}
+	for i, step := range AccountDeletionSteps {
+		if step == d.Step {
+			return i
+		}
+	}
+	return 0
+}
+
+// NextStep returns the step that follows the current one
+func (d *AccountDeletion) NextStep() AccountDeletionStep {
+	completed := d.StepsCompleted()
+	if completed+1 >= len(AccountDeletionSteps) {
+		return AccountDeletionStepDone
+	}
+	return AccountDeletionSteps[completed+1]
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountMergeStatus represents the state of an account merge job
+type AccountMergeStatus string
+
+const (
+	// AccountMergePending is waiting for the worker (or for the next retry)
+	AccountMergePending AccountMergeStatus = "pending"
+	// AccountMergeRunning is being processed by the worker
+	AccountMergeRunning AccountMergeStatus = "running"
+	// AccountMergeCompleted has moved all the data and removed the source account
+	AccountMergeCompleted AccountMergeStatus = "completed"
+	// AccountMergeFailed has given up after too many failed attempts
+	AccountMergeFailed AccountMergeStatus = "failed"
+)
+
+// AccountMergeStep represents a step of an account merge
+type AccountMergeStep string
+
+const (
+	// AccountMergeStepDisconnect closes the WebSocket connections of the source account
+	AccountMergeStepDisconnect AccountMergeStep = "disconnect"
+	// AccountMergeStepPosts moves the posts of the source account
+	AccountMergeStepPosts AccountMergeStep = "posts"
+	// AccountMergeStepFollows moves the follows from and to the source account
+	AccountMergeStepFollows AccountMergeStep = "follows"
+	// AccountMergeStepLikes moves the likes the source account has given
+	AccountMergeStepLikes AccountMergeStep = "likes"
+	// AccountMergeStepNotifications moves the notifications sent to and from the source account
+	AccountMergeStepNotifications AccountMergeStep = "notifications"
+	// AccountMergeStepRedirect redirects the source username and deletes the source account
+	AccountMergeStepRedirect AccountMergeStep = "redirect"
+	// AccountMergeStepDone is set once every step has finished
+	AccountMergeStepDone AccountMergeStep = "done"
+)
+
+// AccountMergeSteps lists the merge steps in the order they are run
+var AccountMergeSteps = []AccountMergeStep{
+	AccountMergeStepDisconnect,
+	AccountMergeStepPosts,
+	AccountMergeStepFollows,
+	AccountMergeStepLikes,
+	AccountMergeStepNotifications,
+	AccountMergeStepRedirect,
+}
+
+// AccountMergeCounts holds a row count for each step that moves data
+type AccountMergeCounts map[AccountMergeStep]int64
+
+// AccountMerge represents an admin-initiated merge of a duplicate account into another account
+type AccountMerge struct {
+	ID             uuid.UUID          `json:"id"`
+	SourceUserID   uuid.UUID          `json:"source_user_id"`
+	SourceUsername string             `json:"source_username"`
+	TargetUserID   uuid.UUID          `json:"target_user_id"`
+	RequestedBy    *uuid.UUID         `json:"requested_by,omitempty"`
+	Status         AccountMergeStatus `json:"status"`
+	Step           AccountMergeStep   `json:"step"` // 次に実行する手順
+	// Moved counts the rows moved to the target account in each step
+	Moved AccountMergeCounts `json:"moved"`
+	// Dropped counts the rows removed instead of moved because the target already had them
+	Dropped       AccountMergeCounts `json:"dropped"`
+	Attempts      int                `json:"attempts"`
+	LastError     *string            `json:"-"`
+	NextAttemptAt time.Time          `json:"-"`
+	RequestedAt   time.Time          `json:"requested_at"`
+	StartedAt     *time.Time         `json:"started_at,omitempty"`
+	CompletedAt   *time.Time         `json:"completed_at,omitempty"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// NewAccountMerge creates a pending merge of the source account into the target account
+func NewAccountMerge(source *User, targetUserID, requestedBy uuid.UUID) *AccountMerge {
+	now := time.Now().UTC()
+	return &AccountMerge{
+		ID:             uuid.New(),
+		SourceUserID:   source.ID,
+		SourceUsername: source.Username,
+		TargetUserID:   targetUserID,
+		RequestedBy:    &requestedBy,
+		Status:         AccountMergePending,
+		Step:           AccountMergeSteps[0],
+		Moved:          AccountMergeCounts{},
+		Dropped:        AccountMergeCounts{},
+		NextAttemptAt:  now,
+		RequestedAt:    now,
+		UpdatedAt:      now,
+	}
+}
+
+// StepsCompleted returns how many merge steps have finished
+func (m *AccountMerge) StepsCompleted() int {
+	if m.Step == AccountMergeStepDone {
+		return len(AccountMergeSteps)
+	}
+	for i, step := range AccountMergeSteps {
+		if step == m.Step {
+			return i
+		}
+	}
+	return 0
+}
+
+// NextStep returns the step that follows the current one
+func (m *AccountMerge) NextStep() AccountMergeStep {
+	completed := m.StepsCompleted()
+	if completed+1 >= len(AccountMergeSteps) {
+		return AccountMergeStepDone
+	}
+	return AccountMergeSteps[completed+1]
+}
+
+// AccountMergeReport is the dry-run result of a merge: what would be moved and
+// what would be dropped as a conflict with the data of the target account
+type AccountMergeReport struct {
+	SourceUserID   uuid.UUID `json:"source_user_id"`
+	SourceUsername string    `json:"source_username"`
+	TargetUserID   uuid.UUID `json:"target_user_id"`
+	Posts          int64     `json:"posts"`
+	// Follows moved, and follows dropped because the target already follows the same account (or it is the target)
+	Following        int64 `json:"following"`
+	FollowingDropped int64 `json:"following_dropped"`
+	// Followers moved, and followers dropped because they already follow the target (or it is the target)
+	Followers        int64 `json:"followers"`
+	FollowersDropped int64 `json:"followers_dropped"`
+	// Likes moved, and likes dropped because the target already liked the same post
+	Likes        int64 `json:"likes"`
+	LikesDropped int64 `json:"likes_dropped"`
+	// Notifications moved, and notifications dropped because they would become notifications from the target to itself
+	Notifications        int64 `json:"notifications"`
+	NotificationsDropped int64 `json:"notifications_dropped"`
+	// RedirectsMoved counts the existing redirects to the source account that will point to the target
+	RedirectsMoved int64 `json:"redirects_moved"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsEventType represents the kind of analytics event
+type AnalyticsEventType string
+
+const (
+	// AnalyticsEventImpression is sent when a post is shown to a user
+	AnalyticsEventImpression AnalyticsEventType = "impression"
+	// AnalyticsEventClick is sent when a user opens a post, link or profile
+	AnalyticsEventClick AnalyticsEventType = "click"
+	// AnalyticsEventSignup is sent when a new account is created
+	AnalyticsEventSignup AnalyticsEventType = "signup"
+)
+
+// AnalyticsEvent represents a single analytics event delivered to the configured sink
+type AnalyticsEvent struct {
+	ID     uuid.UUID          `json:"id"`
+	Type   AnalyticsEventType `json:"type"`
+	UserID *uuid.UUID         `json:"user_id,omitempty"`
+	PostID *uuid.UUID         `json:"post_id,omitempty"`
+	// Properties holds event specific values such as the signup method or the clicked target
+	Properties map[string]string `json:"properties"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// NewAnalyticsEvent creates a new analytics event with default values
+func NewAnalyticsEvent(eventType AnalyticsEventType, userID, postID *uuid.UUID, properties map[string]string) *AnalyticsEvent {
+	if properties == nil {
+		properties = map[string]string{}
+	}
+	return &AnalyticsEvent{
+		ID:         uuid.New(),
+		Type:       eventType,
+		UserID:     userID,
+		PostID:     postID,
+		Properties: properties,
+		OccurredAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsage represents the API usage of a single token and endpoint within one hour
+type APIUsage struct {
+	UserID        uuid.UUID `json:"user_id"`
+	App           string    `json:"app"`
+	KeyID         string    `json:"key_id"`
+	Endpoint      string    `json:"endpoint"`
+	Hour          time.Time `json:"hour"`
+	RequestCount  int64     `json:"request_count"`
+	RequestBytes  int64     `json:"request_bytes"`
+	ResponseBytes int64     `json:"response_bytes"`
+}
+
+// APIUsageSummary represents the total API usage of a single token over a period
+type APIUsageSummary struct {
+	UserID        uuid.UUID `json:"user_id"`
+	App           string    `json:"app"`
+	KeyID         string    `json:"key_id"`
+	RequestCount  int64     `json:"request_count"`
+	RequestBytes  int64     `json:"request_bytes"`
+	ResponseBytes int64     `json:"response_bytes"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction represents a sensitive operation recorded in the audit log
+type AuditAction string
+
+const (
+	// AuditUserSuspend is recorded when an admin suspends an account
+	AuditUserSuspend AuditAction = "user.suspend"
+	// AuditUserUnsuspend is recorded when an admin lifts a suspension
+	AuditUserUnsuspend AuditAction = "user.unsuspend"
+	// AuditUserVerify is recorded when an admin changes the verified badge of an account
+	AuditUserVerify AuditAction = "user.verify"
+	// AuditUserAgeVerify is recorded when an admin records or revokes the age verification of an account
+	AuditUserAgeVerify AuditAction = "user.age_verify"
+	// AuditUserPasswordReset is recorded when an admin requires a user to set a new password
+	AuditUserPasswordReset AuditAction = "user.password_reset"
+	// AuditUserRole is recorded when an admin changes the role of an account
+	AuditUserRole AuditAction = "user.role"
+	// AuditUserMerge is recorded when an admin starts merging a duplicate account into another account
+	AuditUserMerge AuditAction = "user.merge"
+	// AuditUserPurge is recorded when an operator schedules the deletion of an account and its data
+	AuditUserPurge AuditAction = "user.purge"
+	// AuditReportResolve is recorded when a moderator resolves or dismisses a report
+	AuditReportResolve AuditAction = "report.resolve"
+	// AuditPostHide is recorded when a moderator hides a post
+	AuditPostHide AuditAction = "post.hide"
+	// AuditPostRemove is recorded when a moderator removes a post
+	AuditPostRemove AuditAction = "post.remove"
+	// AuditPostRestore is recorded when a moderator makes a hidden or removed post visible again
+	AuditPostRestore AuditAction = "post.restore"
+	// AuditPostContentWarning is recorded when a moderator sets or clears the content warning of a post
+	AuditPostContentWarning AuditAction = "post.content_warning"
+	// AuditPostLockReplies is recorded when a moderator locks or unlocks replies to a post
+	AuditPostLockReplies AuditAction = "post.lock_replies"
+	// AuditContentFilterCreate is recorded when an admin adds a content filter rule
+	AuditContentFilterCreate AuditAction = "content_filter.create"
+	// AuditContentFilterUpdate is recorded when an admin changes a content filter rule
+	AuditContentFilterUpdate AuditAction = "content_filter.update"
+	// AuditContentFilterDelete is recorded when an admin deletes a content filter rule
+	AuditContentFilterDelete AuditAction = "content_filter.delete"
+	// AuditTopicCreate is recorded when an admin adds an explore topic
+	AuditTopicCreate AuditAction = "topic.create"
+	// AuditTopicUpdate is recorded when an admin changes an explore topic
+	AuditTopicUpdate AuditAction = "topic.update"
+	// AuditTopicDelete is recorded when an admin deletes an explore topic
+	AuditTopicDelete AuditAction = "topic.delete"
+	// AuditMediaPurge is recorded when an admin deletes media files that nothing references
+	AuditMediaPurge AuditAction = "media.purge"
+	// AuditLoginSucceeded is recorded when a user logs in
+	AuditLoginSucceeded AuditAction = "auth.login"
+	// AuditLoginFailed is recorded when a login attempt is rejected
+	AuditLoginFailed AuditAction = "auth.login_failed"
+	// AuditPasswordChange is recorded when a user sets a new password
+	AuditPasswordChange AuditAction = "auth.password_change"
+	// AuditDataExport is recorded when a user or an admin exports account data
+	AuditDataExport AuditAction = "data.export"
+)
+
+const (
+	// AuditTargetUser is the target type of operations on accounts
+	AuditTargetUser = "user"
+	// AuditTargetReport is the target type of operations on reports
+	AuditTargetReport = "report"
+	// AuditTargetPost is the target type of operations on posts
+	AuditTargetPost = "post"
+	// AuditTargetContentFilterRule is the target type of operations on content filter rules
+	AuditTargetContentFilterRule = "content_filter_rule"
+	// AuditTargetTopic is the target type of operations on explore topics
+	AuditTargetTopic = "topic"
+)
+
+// AuditLog represents a single entry of the append-only audit trail
+type AuditLog struct {
+	ID uuid.UUID `json:"id"`
+	// ActorID is nil when the acting account has been deleted or was not identified, e.g. a login with an unknown email
+	ActorID *uuid.UUID  `json:"actor_id,omitempty"`
+	Action  AuditAction `json:"action"`
+	// TargetType and TargetID are empty when the operation has no target
+	TargetType string     `json:"target_type,omitempty"`
+	TargetID   *uuid.UUID `json:"target_id,omitempty"`
+	// IPAddress and UserAgent identify the client that sent the request
+	IPAddress string `json:"ip_address,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	// Details holds operation specific values such as the new role or whether it was a dry run
+	Details   map[string]string `json:"details"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuditLogFilter narrows down the audit log entries. Zero values match every entry.
+type AuditLogFilter struct {
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	Action   AuditAction
+	// IPAddress matches a single address or every address in a CIDR range
+	IPAddress string
+	Since     *time.Time
+	Until     *time.Time
+}
+
+// NewAuditLog creates a new audit log entry with default values
+func NewAuditLog(actorID uuid.UUID, action AuditAction, targetType string, targetID uuid.UUID, details map[string]string) *AuditLog {
+	if details == nil {
+		details = map[string]string{}
+	}
+	return &AuditLog{
+		ID:         uuid.New(),
+		ActorID:    &actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   &targetID,
+		Details:    details,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// NewAuthAuditLog creates a new audit log entry for an authentication event of a user.
+// userID is nil when the attempt could not be matched to an account.
+func NewAuthAuditLog(userID *uuid.UUID, action AuditAction, details map[string]string) *AuditLog {
+	entry := &AuditLog{
+		ID:        uuid.New(),
+		ActorID:   userID,
+		Action:    action,
+		Details:   details,
+		CreatedAt: time.Now().UTC(),
+	}
+	if entry.Details == nil {
+		entry.Details = map[string]string{}
+	}
+	if userID != nil {
+		entry.TargetType = AuditTargetUser
+		entry.TargetID = userID
+	}
+	return entry
+}
+
+// NewOperatorAuditLog creates a new audit log entry for an operation run from the admin CLI.
+// The operator has no account, so ActorID is nil and details["via"] is "cli".
+func NewOperatorAuditLog(action AuditAction, targetType string, targetID uuid.UUID, details map[string]string) *AuditLog {
+	entry := NewAuditLog(uuid.Nil, action, targetType, targetID, details)
+	entry.ActorID = nil
+	entry.Details["via"] = "cli"
+	return entry
+}
+
+// WithClient sets the IP address and user agent of the client that sent the request
+func (l *AuditLog) WithClient(ipAddress, userAgent string) *AuditLog {
+	l.IPAddress = ipAddress
+	l.UserAgent = userAgent
+	return l
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxContentFilterPatternLength is the maximum number of characters in a content filter pattern
+const MaxContentFilterPatternLength = 100
+
+// ContentFilterAction represents what happens when a content filter rule matches
+type ContentFilterAction string
+
+const (
+	// ContentFilterActionReject rejects the post or profile update
+	ContentFilterActionReject ContentFilterAction = "reject"
+	// ContentFilterActionFlag accepts the content and puts it in the moderation queue
+	ContentFilterActionFlag ContentFilterAction = "flag"
+	// ContentFilterActionLabel accepts the content and applies the rule's content rating
+	ContentFilterActionLabel ContentFilterAction = "label"
+)
+
+// IsValid reports whether the action is one of the known actions
+func (a ContentFilterAction) IsValid() bool {
+	return a == ContentFilterActionReject || a == ContentFilterActionFlag || a == ContentFilterActionLabel
+}
+
+// ContentFilterRule represents a banned word or phrase managed by admins
+type ContentFilterRule struct {
+	ID uuid.UUID `json:"id"`
+	// Pattern is stored lowercased and matched as a case-insensitive substring
+	Pattern string              `json:"pattern"`
+	Action  ContentFilterAction `json:"action"`
+	// Rating is the content rating applied by label rules
+	Rating    ContentRating `json:"rating"`
+	CreatedBy *uuid.UUID    `json:"created_by,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// NewContentFilterRule creates a new content filter rule with a normalized pattern
+func NewContentFilterRule(pattern string, action ContentFilterAction, rating ContentRating, createdBy uuid.UUID) *ContentFilterRule {
+	if action != ContentFilterActionLabel {
+		rating = ContentRatingGeneral
+	}
+	now := time.Now().UTC()
+	return &ContentFilterRule{
+		ID:        uuid.New(),
+		Pattern:   NormalizeContentFilterPattern(pattern),
+		Action:    action,
+		Rating:    rating,
+		CreatedBy: &createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NormalizeContentFilterPattern trims and lowercases a pattern so that
+// duplicates differing only in case are rejected by the unique constraint
+func NormalizeContentFilterPattern(pattern string) string {
+	return strings.ToLower(strings.TrimSpace(pattern))
+}
+
+// Matches reports whether the text contains the pattern. Substring matching is
+// used instead of word boundaries because Japanese text has no spaces.
+func (r *ContentFilterRule) Matches(text string) bool {
+	return r.Pattern != "" && strings.Contains(strings.ToLower(text), r.Pattern)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DevicePlatform represents the push service a device token belongs to
+type DevicePlatform string
+
+const (
+	// DevicePlatformFCM is an Android (or web) device registered with Firebase Cloud Messaging
+	DevicePlatformFCM DevicePlatform = "fcm"
+	// DevicePlatformAPNs is an iOS device registered with the Apple Push Notification service
+	DevicePlatformAPNs DevicePlatform = "apns"
+)
+
+// IsValid reports whether the platform is a supported push service
+func (p DevicePlatform) IsValid() bool {
+	return p == DevicePlatformFCM || p == DevicePlatformAPNs
+}
+
+// DeviceToken represents a mobile device that receives a user's push notifications
+type DeviceToken struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Platform  DevicePlatform `json:"platform"`
+	Token     string         `json:"token"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// NewDeviceToken creates a new device token
+func NewDeviceToken(userID uuid.UUID, platform DevicePlatform, token string) *DeviceToken {
+	now := time.Now().UTC()
+	return &DeviceToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Platform:  platform,
+		Token:     token,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// PushMessage is the notification shown on a device
+type PushMessage struct {
+	Title string
+	Body  string
+	// Data is passed to the app so it can open the notification's target
+	Data map[string]string
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FollowEventType represents whether a follow event started or ended a follow
+type FollowEventType string
+
+const (
+	// FollowEventFollow is recorded when a user follows another user
+	FollowEventFollow FollowEventType = "follow"
+	// FollowEventUnfollow is recorded when a user unfollows another user
+	FollowEventUnfollow FollowEventType = "unfollow"
+)
+
+// FollowEvent represents an append-only follow or unfollow event
+// The follows table is a projection of these events
+type FollowEvent struct {
+	Seq         int64           `json:"seq"`
+	FollowerID  uuid.UUID       `json:"follower_id"`
+	FolloweeID  uuid.UUID       `json:"followee_id"`
+	Type        FollowEventType `json:"type"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	ProjectedAt *time.Time      `json:"projected_at,omitempty"`
+}
+
+// NewFollowEvent creates a new follow event that has not been recorded yet
+func NewFollowEvent(followerID, followeeID uuid.UUID, eventType FollowEventType) *FollowEvent {
+	return &FollowEvent{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// FollowChurnDay represents the follows gained and lost on a single day
+type FollowChurnDay struct {
+	Date   time.Time `json:"date"`
+	Gained int64     `json:"gained"`
+	Lost   int64     `json:"lost"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// InstanceStats represents the public totals of this instance for instance directories.
+// The values are computed periodically, so they may lag behind the live data.
+type InstanceStats struct {
+	TotalUsers           int64     `json:"total_users"`
+	TotalPosts           int64     `json:"total_posts"`
+	ActiveUsersLastMonth int64     `json:"active_users_last_month"`
+	ComputedAt           time.Time `json:"computed_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus represents the state of a background job
+type JobStatus string
+
+const (
+	// JobPending is waiting for a worker (or for the next retry)
+	JobPending JobStatus = "pending"
+	// JobRunning is being processed by a worker
+	JobRunning JobStatus = "running"
+	// JobCompleted has finished successfully
+	JobCompleted JobStatus = "completed"
+	// JobFailed has given up after too many failed attempts or a permanent error
+	JobFailed JobStatus = "failed"
+)
+
+// Job represents a unit of work run asynchronously by the job queue
+type Job struct {
+	ID          uuid.UUID       `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      JobStatus       `json:"status"`
+	UniqueKey   *string         `json:"-"` // 設定した場合は同じキーのジョブを1件のみ登録する
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   *string         `json:"last_error,omitempty"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedUntil *time.Time      `json:"-"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+// NewJob creates a pending job that runs at runAt
+func NewJob(kind string, payload json.RawMessage, maxAttempts int, runAt time.Time) *Job {
+	now := time.Now().UTC()
+	return &Job{
+		ID:          uuid.New(),
+		Kind:        kind,
+		Payload:     payload,
+		Status:      JobPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt.UTC(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsFinished reports whether the job will not run again
+func (j *Job) IsFinished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed
+}
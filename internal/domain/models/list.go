@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// List represents a user-curated list of accounts
+type List struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsPrivate   bool      `json:"is_private"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewList creates a new list with default values
+func NewList(ownerID uuid.UUID, name, description string, isPrivate bool) *List {
+	now := time.Now().UTC()
+	return &List{
+		ID:          uuid.New(),
+		OwnerID:     ownerID,
+		Name:        name,
+		Description: description,
+		IsPrivate:   isPrivate,
+		MemberCount: 0,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// CanView reports whether the given user can see the list
+func (l *List) CanView(userID uuid.UUID) bool {
+	return !l.IsPrivate || l.OwnerID == userID
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaObject represents an uploaded file shared by every upload with the same content
+type MediaObject struct {
+	ID uuid.UUID `json:"id"`
+	// Hash is the hex-encoded SHA-256 of the file content
+	Hash string `json:"hash"`
+	URL  string `json:"url"`
+	Size int64  `json:"size"`
+	// MimeType is detected from the file content when uploaded
+	MimeType string `json:"mime_type"`
+	// Blurhash, Width and Height describe images; they are empty for files that are not images
+	Blurhash string `json:"blurhash"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	// DurationMS and ThumbnailURL describe videos; the thumbnail is empty when it could not be extracted
+	DurationMS   int    `json:"duration_ms"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	// RefCount is the number of uploads that have not been released; the file is deleted when it
+	// reaches zero and nothing refers to the URL any more
+	RefCount  int       `json:"ref_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewMediaObject creates a new media object referenced by a single upload
+func NewMediaObject(hash, url string, size int64) *MediaObject {
+	now := time.Now().UTC()
+	return &MediaObject{
+		ID:        uuid.New(),
+		Hash:      hash,
+		URL:       url,
+		Size:      size,
+		RefCount:  1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// MediaUpload records how many times a user uploaded the content of a media object.
+// Each upload counts toward the user's storage usage until it is released
+type MediaUpload struct {
+	MediaID     uuid.UUID `json:"media_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	UploadCount int       `json:"upload_count"`
+}
+
+// MediaRelease is the result of releasing a media object
+type MediaRelease struct {
+	Object *MediaObject
+	// Refunded reports whether one of the user's uploads was released, so its size goes back to the user
+	Refunded bool
+	// Deleted reports whether the file and the object were deleted
+	Deleted bool
+}
+
+// Attachment returns the media entity sent to clients for the object
+func (o *MediaObject) Attachment() MediaAttachment {
+	mimeType := o.MimeType
+	if mimeType == "" {
+		mimeType = MimeTypeFromURL(o.URL)
+	}
+
+	return MediaAttachment{
+		URL:          o.URL,
+		Type:         MediaTypeFromURL(o.URL),
+		MimeType:     mimeType,
+		Blurhash:     o.Blurhash,
+		Width:        o.Width,
+		Height:       o.Height,
+		DurationMS:   o.DurationMS,
+		ThumbnailURL: o.ThumbnailURL,
+	}
+}
+
+// MaxMediaPerPost is the maximum number of media files attached to a post
+const MaxMediaPerPost = 4
+
+// IsValidMediaSet reports whether the media can be attached to a single post:
+// at most MaxMediaPerPost files, and a video only on its own
+func IsValidMediaSet(mediaURLs []string) bool {
+	if len(mediaURLs) > MaxMediaPerPost {
+		return false
+	}
+	if len(mediaURLs) > 1 {
+		for _, mediaURL := range mediaURLs {
+			if MediaTypeFromURL(mediaURL) == MediaTypeVideo {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MediaAttachment represents a media entity attached to a post in API responses
+type MediaAttachment struct {
+	URL string `json:"url"`
+	// Type tells clients how to render the media, such as a player for videos
+	Type     MediaType `json:"type"`
+	MimeType string    `json:"mime_type,omitempty"`
+	// Blurhash is a compact placeholder clients can render before the image loads
+	Blurhash string `json:"blurhash,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	// DurationMS is the length of a video in milliseconds
+	DurationMS int `json:"duration_ms,omitempty"`
+	// ThumbnailURL is a still image of a video shown before it plays
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	// AltText is the description of the media written by the post author for screen readers
+	AltText string `json:"alt_text,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaxMentionsPerPost is the maximum number of mentioned users notified for a post
+const MaxMentionsPerPost = 10
+
+// mentionPattern matches "@username"; usernames are 3 to 30 alphanumeric characters
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9]+)`)
+
+// ExtractMentions returns the usernames mentioned in the content in order of
+// appearance, without duplicates (case-insensitive). An "@" preceded by a word
+// character, such as in an email address, and a name followed by "_" or "@"
+// are not mentions.
+func ExtractMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		if match[0] > 0 && isMentionWordByte(content[match[0]-1]) {
+			continue
+		}
+		if match[1] < len(content) && (content[match[1]] == '_' || content[match[1]] == '@') {
+			continue
+		}
+
+		username := content[match[2]:match[3]]
+		if len(username) < 3 || len(username) > 30 || seen[strings.ToLower(username)] {
+			continue
+		}
+		seen[strings.ToLower(username)] = true
+		usernames = append(usernames, username)
+		if len(usernames) == MaxMentionsPerPost {
+			break
+		}
+	}
+	return usernames
+}
+
+func isMentionWordByte(b byte) bool {
+	return b == '_' || b == '@' || b == '.' ||
+		('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
@@ -15,8 +15,74 @@ const (
 	NotificationTypeRepost  NotificationType = "repost"
 	NotificationTypeReply   NotificationType = "reply"
 	NotificationTypeMention NotificationType = "mention"
+	// NotificationTypeSavedSearch is sent when new posts match a saved search
+	NotificationTypeSavedSearch NotificationType = "saved_search"
+	// NotificationTypePostRemoved is sent to the author when a moderator removes their post
+	NotificationTypePostRemoved NotificationType = "post_removed"
 )
 
+// NotificationGrouping represents how a client displays notifications
+type NotificationGrouping string
+
+const (
+	// NotificationGroupingFlat lists every notification separately
+	NotificationGroupingFlat NotificationGrouping = "flat"
+	// NotificationGroupingGrouped merges notifications of the same kind about the same target
+	NotificationGroupingGrouped NotificationGrouping = "grouped"
+)
+
+// IsValid reports whether the grouping is a known representation
+func (g NotificationGrouping) IsValid() bool {
+	return g == NotificationGroupingFlat || g == NotificationGroupingGrouped
+}
+
+// notificationGroupNamespace is the namespace of the deterministic notification group IDs
+var notificationGroupNamespace = uuid.MustParse("6f1c2a4e-8d3b-4f57-9a0e-3c5b7d9e1f20")
+
+// NotificationGroup is the notifications of the same type about the same target on the same day (UTC)
+// Likes and reposts are grouped per post and follows per day; other notifications form a group of their own
+type NotificationGroup struct {
+	// ID is the same for the same type, target and day, so clients can merge a group returned across pages
+	// A group of a single ungrouped notification has the notification's ID
+	ID       uuid.UUID        `json:"id"`
+	Type     NotificationType `json:"type"`
+	PostID   *uuid.UUID       `json:"post_id,omitempty"`
+	LatestAt time.Time        `json:"latest_at"`
+	// Read is true when every notification in the group has been read
+	Read              bool `json:"read"`
+	NotificationCount int  `json:"notification_count"`
+	ActorCount        int  `json:"actor_count"`
+	// ActorIDs are the most recent distinct actors, newest first, up to the requested preview size
+	ActorIDs []uuid.UUID `json:"actor_ids"`
+}
+
+// IsGroupedNotificationType reports whether notifications of the type are merged into groups
+func IsGroupedNotificationType(notificationType NotificationType) bool {
+	switch notificationType {
+	case NotificationTypeLike, NotificationTypeRepost, NotificationTypeFollow:
+		return true
+	}
+	return false
+}
+
+// NotificationGroupID returns the ID of the user's group identified by the key ("type:day[:post_id]")
+func NotificationGroupID(userID uuid.UUID, key string) uuid.UUID {
+	return uuid.NewSHA1(notificationGroupNamespace, []byte(userID.String()+":"+key))
+}
+
+// ClientTypes lists the client types that can have their own notification preferences
+var ClientTypes = []string{"web", "ios", "android", "desktop"}
+
+// IsValidClientType reports whether the client type is known
+func IsValidClientType(clientType string) bool {
+	for _, known := range ClientTypes {
+		if clientType == known {
+			return true
+		}
+	}
+	return false
+}
+
 // Notification represents a notification in the system
 type Notification struct {
 	ID        uuid.UUID        `json:"id"`
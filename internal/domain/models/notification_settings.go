@@ -0,0 +1,73 @@
+package models
+
+import "github.com/google/uuid"
+
+// MutableNotificationTypes lists the notification types a user can turn off
+// Notices from moderators (post_removed) are always delivered
+var MutableNotificationTypes = []NotificationType{
+	NotificationTypeLike,
+	NotificationTypeRepost,
+	NotificationTypeFollow,
+	NotificationTypeReply,
+	NotificationTypeMention,
+	NotificationTypeSavedSearch,
+}
+
+// EmailNotificationTypes lists the notification types that can be sent by email
+var EmailNotificationTypes = []NotificationType{NotificationTypeFollow, NotificationTypeMention}
+
+// defaultEmailNotifications is used for the types a user has not configured
+var defaultEmailNotifications = map[NotificationType]bool{
+	NotificationTypeFollow:  true,
+	NotificationTypeMention: true,
+}
+
+// IsMutableNotificationType reports whether the notification type can be turned off
+func IsMutableNotificationType(notificationType NotificationType) bool {
+	for _, mutable := range MutableNotificationTypes {
+		if notificationType == mutable {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmailNotificationType reports whether the notification type can be sent by email
+func IsEmailNotificationType(notificationType NotificationType) bool {
+	_, ok := defaultEmailNotifications[notificationType]
+	return ok
+}
+
+// NotificationSettings represents how a user receives each type of notification
+type NotificationSettings struct {
+	UserID uuid.UUID `json:"-"`
+	// Enabled maps each mutable notification type to whether its notifications are created at all
+	Enabled map[NotificationType]bool `json:"enabled"`
+	// Email maps each emailable notification type to whether it is sent by email while the user is offline
+	Email map[NotificationType]bool `json:"email"`
+}
+
+// NewNotificationSettings creates the default settings
+func NewNotificationSettings(userID uuid.UUID) *NotificationSettings {
+	enabled := make(map[NotificationType]bool, len(MutableNotificationTypes))
+	for _, notificationType := range MutableNotificationTypes {
+		enabled[notificationType] = true
+	}
+	email := make(map[NotificationType]bool, len(defaultEmailNotifications))
+	for notificationType, emailEnabled := range defaultEmailNotifications {
+		email[notificationType] = emailEnabled
+	}
+	return &NotificationSettings{UserID: userID, Enabled: enabled, Email: email}
+}
+
+// IsEnabled reports whether notifications of the type are delivered (types that cannot be turned off always are)
+func (s *NotificationSettings) IsEnabled(notificationType NotificationType) bool {
+	enabled, ok := s.Enabled[notificationType]
+	return !ok || enabled
+}
+
+// EmailEnabled reports whether the notification type is sent by email
+// A type that is turned off is not sent by email either
+func (s *NotificationSettings) EmailEnabled(notificationType NotificationType) bool {
+	return s.IsEnabled(notificationType) && s.Email[notificationType]
+}
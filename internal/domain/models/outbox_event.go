@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxChannel represents where an outbox event is delivered
+type OutboxChannel string
+
+const (
+	// OutboxChannelWebSocket delivers the event to the recipient's WebSocket connections
+	OutboxChannelWebSocket OutboxChannel = "websocket"
+	// OutboxChannelPush delivers the event to the recipient's mobile devices
+	OutboxChannelPush OutboxChannel = "push"
+	// OutboxChannelEmail sends the event by email while the recipient is offline
+	OutboxChannelEmail OutboxChannel = "email"
+	// OutboxChannelWebhook sends the event to the recipient's personal webhooks
+	OutboxChannelWebhook OutboxChannel = "webhook"
+)
+
+// OutboxEvent represents an event stored with the write that caused it and delivered in the background
+type OutboxEvent struct {
+	ID            int64           `json:"id"`
+	Channel       OutboxChannel   `json:"channel"`
+	UserID        uuid.UUID       `json:"user_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     *string         `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// NewOutboxEvent creates an event that is due immediately
+func NewOutboxEvent(channel OutboxChannel, userID uuid.UUID, payload json.RawMessage) *OutboxEvent {
+	now := time.Now().UTC()
+	return &OutboxEvent{
+		Channel:       channel,
+		UserID:        userID,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
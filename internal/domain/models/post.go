@@ -1,45 +1,219 @@
 package models
 
 import (
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+// ContentRating represents the content rating of a post
+type ContentRating string
+
+const (
+	ContentRatingGeneral   ContentRating = "general"
+	ContentRatingSensitive ContentRating = "sensitive"
+	ContentRatingAdult     ContentRating = "adult"
+)
+
+// IsValid reports whether the rating is one of the known ratings
+func (r ContentRating) IsValid() bool {
+	switch r {
+	case ContentRatingGeneral, ContentRatingSensitive, ContentRatingAdult:
+		return true
+	}
+	return false
+}
+
+// IsRestricted reports whether the rating requires an age-verified viewer
+func (r ContentRating) IsRestricted() bool {
+	return r == ContentRatingSensitive || r == ContentRatingAdult
+}
+
+// Max returns the stricter of the two ratings
+func (r ContentRating) Max(other ContentRating) ContentRating {
+	rank := map[ContentRating]int{ContentRatingGeneral: 0, ContentRatingSensitive: 1, ContentRatingAdult: 2}
+	if rank[other] > rank[r] {
+		return other
+	}
+	return r
+}
+
+// ReplyPolicy represents who may reply to a post
+type ReplyPolicy string
+
+const (
+	// ReplyPolicyEveryone allows anyone to reply
+	ReplyPolicyEveryone ReplyPolicy = "everyone"
+	// ReplyPolicyFollowers allows only the author's followers to reply
+	ReplyPolicyFollowers ReplyPolicy = "followers"
+	// ReplyPolicyFollowing allows only accounts the author follows to reply
+	ReplyPolicyFollowing ReplyPolicy = "following"
+)
+
+// IsValid reports whether the policy is one of the known policies
+func (p ReplyPolicy) IsValid() bool {
+	switch p {
+	case ReplyPolicyEveryone, ReplyPolicyFollowers, ReplyPolicyFollowing:
+		return true
+	}
+	return false
+}
+
+// MaxContentWarningLength is the maximum number of characters of a content warning
+const MaxContentWarningLength = 100
+
+// MaxPostContentLength is the maximum length of the content of a post
+const MaxPostContentLength = 280
+
+const (
+	// MinPostLifetime is the shortest time an expiring post stays visible
+	MinPostLifetime = 5 * time.Minute
+	// MaxPostLifetime is the longest time an expiring post stays visible
+	MaxPostLifetime = 90 * 24 * time.Hour
+)
+
+// ReplySort represents the order in which the replies to a post are listed
+type ReplySort string
+
+const (
+	// ReplySortNewest lists the most recent replies first
+	ReplySortNewest ReplySort = "newest"
+	// ReplySortOldest lists replies in the order they were posted
+	ReplySortOldest ReplySort = "oldest"
+	// ReplySortTop lists the replies with the most likes, reposts and replies first
+	ReplySortTop ReplySort = "top"
+)
+
+// IsValid returns whether the sort is a known sort
+func (s ReplySort) IsValid() bool {
+	return s == ReplySortNewest || s == ReplySortOldest || s == ReplySortTop
+}
+
+// ModerationStatus represents whether a post has been taken down by a moderator
+type ModerationStatus string
+
+const (
+	// ModerationStatusVisible is the status of posts no moderator has acted on
+	ModerationStatusVisible ModerationStatus = "visible"
+	// ModerationStatusHidden is the status of posts temporarily hidden, e.g. while a report is reviewed
+	ModerationStatusHidden ModerationStatus = "hidden"
+	// ModerationStatusRemoved is the status of posts removed for violating the rules
+	ModerationStatusRemoved ModerationStatus = "removed"
+)
+
 // Post represents a post in the system
 type Post struct {
-	ID          uuid.UUID `json:"id"`
-	UserID      uuid.UUID `json:"user_id"`
-	Content     string    `json:"content"`
-	MediaURLs   []string  `json:"media_urls"`
-	LikeCount   int       `json:"like_count"`
-	RepostCount int       `json:"repost_count"`
-	ReplyCount  int       `json:"reply_count"`
-	IsRepost    bool      `json:"is_repost"`
-	RepostID    *uuid.UUID `json:"repost_id,omitempty"`
-	IsReply     bool      `json:"is_reply"`
-	ReplyToID   *uuid.UUID `json:"reply_to_id,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            uuid.UUID     `json:"id"`
+	UserID        uuid.UUID     `json:"user_id"`
+	Content       string        `json:"content"`
+	MediaURLs     []string      `json:"media_urls"`
+	MediaAltTexts []string      `json:"media_alt_texts"`
+	LikeCount     int           `json:"like_count"`
+	RepostCount   int           `json:"repost_count"`
+	ReplyCount    int           `json:"reply_count"`
+	ViewCount     int64         `json:"views_count"`
+	ShareCount    int64         `json:"share_count"`
+	IsRepost      bool          `json:"is_repost"`
+	RepostID      *uuid.UUID    `json:"repost_id,omitempty"`
+	IsReply       bool          `json:"is_reply"`
+	ReplyToID     *uuid.UUID    `json:"reply_to_id,omitempty"`
+	ContentRating ContentRating `json:"content_rating"`
+	ReplyPolicy   ReplyPolicy   `json:"reply_policy"`
+	// SharingEnabled reports whether the author allows external sharing of the post
+	SharingEnabled bool      `json:"sharing_enabled"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	// DeletedAt is set when the post has been soft-deleted
+	DeletedAt *time.Time `json:"-"`
+
+	// ModerationStatus, ModerationReason, ModeratedBy and ModeratedAt record the last moderator action
+	ModerationStatus ModerationStatus `json:"-"`
+	ModerationReason string           `json:"-"`
+	ModeratedBy      *uuid.UUID       `json:"-"`
+	ModeratedAt      *time.Time       `json:"-"`
+	// ContentWarning is shown in place of the content until the viewer chooses to see it
+	ContentWarning string `json:"content_warning"`
+	// RepliesLocked is set when a moderator has locked replies to the post
+	RepliesLocked bool `json:"replies_locked"`
+	// Language is the BCP 47 language tag chosen by the author, empty if not set
+	Language string `json:"language"`
+	// ExpiresAt is when the post disappears, nil for posts that never expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// IsDeleted reports whether the post has been soft-deleted
+func (p *Post) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// IsModerated reports whether a moderator has hidden or removed the post
+func (p *Post) IsModerated() bool {
+	return p.ModerationStatus == ModerationStatusHidden || p.ModerationStatus == ModerationStatusRemoved
+}
+
+// IsExpiring reports whether the author set the post to disappear
+func (p *Post) IsExpiring() bool {
+	return p.ExpiresAt != nil
+}
+
+// IsExpiredAt reports whether the post has disappeared at t
+func (p *Post) IsExpiredAt(t time.Time) bool {
+	return p.ExpiresAt != nil && !p.ExpiresAt.After(t)
+}
+
+// IsUnavailable reports whether the post is deleted, taken down or expired and its content must not be shown
+func (p *Post) IsUnavailable() bool {
+	return p.IsDeleted() || p.IsModerated() || p.IsExpiredAt(time.Now())
+}
+
+// contentHashtagPattern matches a hashtag in post content that is not preceded by a word
+// character (as in "C#"), the same rule the trend aggregation applies in SQL
+var contentHashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&])#([\p{L}\p{N}_]+)`)
+
+// MaxHashtagLength is the longest hashtag that is aggregated into trends and streamed
+const MaxHashtagLength = 100
+
+// Hashtags returns the distinct lowercased hashtags in the content, without "#"
+func (p *Post) Hashtags() []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, match := range contentHashtagPattern.FindAllStringSubmatch(p.Content, -1) {
+		tag := strings.ToLower(match[1])
+		if seen[tag] || utf8.RuneCountInString(tag) > MaxHashtagLength {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
 }
 
 // NewPost creates a new post with default values
 func NewPost(userID uuid.UUID, content string, mediaURLs []string) *Post {
 	now := time.Now()
 	return &Post{
-		ID:          uuid.New(),
-		UserID:      userID,
-		Content:     content,
-		MediaURLs:   mediaURLs,
-		LikeCount:   0,
-		RepostCount: 0,
-		ReplyCount:  0,
-		IsRepost:    false,
-		RepostID:    nil,
-		IsReply:     false,
-		ReplyToID:   nil,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:             uuid.New(),
+		UserID:         userID,
+		Content:        content,
+		MediaURLs:      mediaURLs,
+		MediaAltTexts:  make([]string, len(mediaURLs)),
+		LikeCount:      0,
+		RepostCount:    0,
+		ReplyCount:     0,
+		IsRepost:       false,
+		RepostID:       nil,
+		IsReply:        false,
+		ReplyToID:      nil,
+		ContentRating:  ContentRatingGeneral,
+		ReplyPolicy:    ReplyPolicyEveryone,
+		SharingEnabled: true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+
+		ModerationStatus: ModerationStatusVisible,
 	}
 }
 
@@ -61,41 +235,57 @@ func NewRepost(userID uuid.UUID, repostID uuid.UUID, content string) *Post {
 
 // PostResponse represents the post data sent to clients
 type PostResponse struct {
-	ID          uuid.UUID    `json:"id"`
-	UserID      uuid.UUID    `json:"user_id"`
-	User        *UserResponse `json:"user,omitempty"`
-	Content     string       `json:"content"`
-	MediaURLs   []string     `json:"media_urls"`
-	LikeCount   int          `json:"like_count"`
-	RepostCount int          `json:"repost_count"`
-	ReplyCount  int          `json:"reply_count"`
-	IsRepost    bool         `json:"is_repost"`
-	RepostID    *uuid.UUID   `json:"repost_id,omitempty"`
-	Repost      *PostResponse `json:"repost,omitempty"`
-	IsReply     bool         `json:"is_reply"`
-	ReplyToID   *uuid.UUID   `json:"reply_to_id,omitempty"`
-	ReplyTo     *PostResponse `json:"reply_to,omitempty"`
-	IsLiked     bool         `json:"is_liked"`
-	IsReposted  bool         `json:"is_reposted"`
-	CreatedAt   time.Time    `json:"created_at"`
+	ID             uuid.UUID     `json:"id"`
+	UserID         uuid.UUID     `json:"user_id"`
+	User           *UserResponse `json:"user,omitempty"`
+	Content        string        `json:"content"`
+	MediaURLs      []string      `json:"media_urls"`
+	LikeCount      int           `json:"like_count"`
+	RepostCount    int           `json:"repost_count"`
+	ReplyCount     int           `json:"reply_count"`
+	ViewCount      int64         `json:"views_count"`
+	ShareCount     int64         `json:"share_count"`
+	IsRepost       bool          `json:"is_repost"`
+	RepostID       *uuid.UUID    `json:"repost_id,omitempty"`
+	Repost         *PostResponse `json:"repost,omitempty"`
+	IsReply        bool          `json:"is_reply"`
+	ReplyToID      *uuid.UUID    `json:"reply_to_id,omitempty"`
+	ReplyTo        *PostResponse `json:"reply_to,omitempty"`
+	ContentRating  ContentRating `json:"content_rating"`
+	ReplyPolicy    ReplyPolicy   `json:"reply_policy"`
+	SharingEnabled bool          `json:"sharing_enabled"`
+	ContentWarning string        `json:"content_warning"`
+	RepliesLocked  bool          `json:"replies_locked"`
+	ExpiresAt      *time.Time    `json:"expires_at,omitempty"`
+	IsLiked        bool          `json:"is_liked"`
+	IsReposted     bool          `json:"is_reposted"`
+	CreatedAt      time.Time     `json:"created_at"`
 }
 
 // ToResponse converts a Post to PostResponse
 func (p *Post) ToResponse() *PostResponse {
 	return &PostResponse{
-		ID:          p.ID,
-		UserID:      p.UserID,
-		Content:     p.Content,
-		MediaURLs:   p.MediaURLs,
-		LikeCount:   p.LikeCount,
-		RepostCount: p.RepostCount,
-		ReplyCount:  p.ReplyCount,
-		IsRepost:    p.IsRepost,
-		RepostID:    p.RepostID,
-		IsReply:     p.IsReply,
-		ReplyToID:   p.ReplyToID,
-		IsLiked:     false, // このフィールドはサービス層で設定する
-		IsReposted:  false, // このフィールドはサービス層で設定する
-		CreatedAt:   p.CreatedAt,
+		ID:             p.ID,
+		UserID:         p.UserID,
+		Content:        p.Content,
+		MediaURLs:      p.MediaURLs,
+		LikeCount:      p.LikeCount,
+		RepostCount:    p.RepostCount,
+		ReplyCount:     p.ReplyCount,
+		ViewCount:      p.ViewCount,
+		ShareCount:     p.ShareCount,
+		IsRepost:       p.IsRepost,
+		RepostID:       p.RepostID,
+		IsReply:        p.IsReply,
+		ReplyToID:      p.ReplyToID,
+		ContentRating:  p.ContentRating,
+		ReplyPolicy:    p.ReplyPolicy,
+		SharingEnabled: p.SharingEnabled,
+		ContentWarning: p.ContentWarning,
+		RepliesLocked:  p.RepliesLocked,
+		ExpiresAt:      p.ExpiresAt,
+		IsLiked:        false, // このフィールドはサービス層で設定する
+		IsReposted:     false, // このフィールドはサービス層で設定する
+		CreatedAt:      p.CreatedAt,
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostEditAction represents the kind of change made to a post
+type PostEditAction string
+
+const (
+	// PostEditRemoveMedia removes attachments from a post
+	PostEditRemoveMedia PostEditAction = "remove_media"
+)
+
+// PostEdit represents an entry in the edit history of a post, holding the state before the edit
+type PostEdit struct {
+	ID                uuid.UUID      `json:"id"`
+	PostID            uuid.UUID      `json:"post_id"`
+	EditorID          uuid.UUID      `json:"editor_id"`
+	Action            PostEditAction `json:"action"`
+	PreviousContent   string         `json:"previous_content"`
+	PreviousMediaURLs []string       `json:"previous_media_urls"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
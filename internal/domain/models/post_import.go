@@ -0,0 +1,13 @@
+package models
+
+// PostImportSourceTwitter is the source of posts imported from a Twitter/X archive
+const PostImportSourceTwitter = "twitter"
+
+// PostImport is a post imported from an archive of another service
+type PostImport struct {
+	// Source is the service the post was imported from, e.g. PostImportSourceTwitter
+	Source string
+	// ExternalID is the ID of the post in the source service
+	ExternalID string
+	Post       *Post
+}
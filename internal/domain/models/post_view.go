@@ -0,0 +1,11 @@
+package models
+
+import (
+	"time"
+)
+
+// PostDailyViews represents the number of views a post received on a single day
+type PostDailyViews struct {
+	Date      time.Time `json:"date"`
+	ViewCount int64     `json:"views_count"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPinnedHashtags is the maximum number of hashtags pinned to a profile
+	MaxPinnedHashtags = 5
+	// MaxPinnedHashtagLength is the maximum number of characters of a pinned hashtag, without "#"
+	MaxPinnedHashtagLength = 50
+)
+
+// accentColorPattern matches "#rgb" and "#rrggbb" hex colors (lowercased)
+var accentColorPattern = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// hashtagPattern matches a hashtag without "#": letters, digits and "_"
+var hashtagPattern = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+
+// ProfileTheme is the cosmetic profile customization rendered by clients
+type ProfileTheme struct {
+	// AccentColor is a "#rrggbb" color, empty for the client's default
+	AccentColor string `json:"accent_color"`
+	// PinnedHashtags are hashtags shown on the profile, without "#", in display order
+	PinnedHashtags []string `json:"pinned_hashtags"`
+}
+
+// NormalizeAccentColor lowercases a hex color and expands "#rgb" to "#rrggbb".
+// An empty color is valid and resets to the default; it reports false for
+// anything else that is not a hex color.
+func NormalizeAccentColor(color string) (string, bool) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "" {
+		return "", true
+	}
+	if !accentColorPattern.MatchString(color) {
+		return "", false
+	}
+
+	if len(color) == 4 {
+		color = "#" + strings.Repeat(color[1:2], 2) + strings.Repeat(color[2:3], 2) + strings.Repeat(color[3:4], 2)
+	}
+	return color, true
+}
+
+// NormalizeHashtag trims a hashtag and a leading "#" and lowercases it, as
+// hashtags in posts are matched case-insensitively. It reports false when the
+// hashtag is empty, too long or contains other than letters, digits and "_".
+func NormalizeHashtag(hashtag string) (string, bool) {
+	hashtag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hashtag), "#"))
+	if hashtag == "" || utf8.RuneCountInString(hashtag) > MaxHashtagLength || !hashtagPattern.MatchString(hashtag) {
+		return "", false
+	}
+	return hashtag, true
+}
+
+// NormalizePinnedHashtags trims the hashtags and a leading "#", ignores empty
+// ones and removes duplicates (case-insensitive), keeping the first spelling.
+// It reports false when a hashtag contains other than letters, digits and "_",
+// is too long, or there are more than MaxPinnedHashtags.
+func NormalizePinnedHashtags(hashtags []string) ([]string, bool) {
+	normalized := make([]string, 0, len(hashtags))
+	seen := make(map[string]bool, len(hashtags))
+	for _, hashtag := range hashtags {
+		hashtag = strings.TrimPrefix(strings.TrimSpace(hashtag), "#")
+		if hashtag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(hashtag) > MaxPinnedHashtagLength || !hashtagPattern.MatchString(hashtag) {
+			return nil, false
+		}
+
+		key := strings.ToLower(hashtag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, hashtag)
+	}
+
+	if len(normalized) > MaxPinnedHashtags {
+		return nil, false
+	}
+	return normalized, true
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AllowedReactions lists the emoji that can be used as post reactions
+var AllowedReactions = []string{"👍", "❤️", "😂", "😮", "😢", "🎉"}
+
+// Reaction represents an emoji reaction to a post
+type Reaction struct {
+	UserID    uuid.UUID `json:"user_id"`
+	PostID    uuid.UUID `json:"post_id"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionCount represents the number of reactions with a single emoji
+type ReactionCount struct {
+	Emoji string `json:"emoji"`
+	Count int64  `json:"count"`
+}
+
+// NewReaction creates a new reaction with default values
+func NewReaction(userID, postID uuid.UUID, emoji string) *Reaction {
+	return &Reaction{
+		UserID:    userID,
+		PostID:    postID,
+		Emoji:     emoji,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// IsAllowedReaction reports whether the emoji can be used as a reaction
+func IsAllowedReaction(emoji string) bool {
+	for _, allowed := range AllowedReactions {
+		if emoji == allowed {
+			return true
+		}
+	}
+	return false
+}
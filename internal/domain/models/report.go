@@ -0,0 +1,124 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportTargetType represents what kind of content was reported
+type ReportTargetType string
+
+const (
+	// ReportTargetPost is a report about a single post
+	ReportTargetPost ReportTargetType = "post"
+	// ReportTargetUser is a report about an account
+	ReportTargetUser ReportTargetType = "user"
+)
+
+// ReportReason represents the category chosen by the reporter
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonHateSpeech     ReportReason = "hate_speech"
+	ReportReasonViolence       ReportReason = "violence"
+	ReportReasonSexualContent  ReportReason = "sexual_content"
+	ReportReasonSelfHarm       ReportReason = "self_harm"
+	ReportReasonMisinformation ReportReason = "misinformation"
+	ReportReasonImpersonation  ReportReason = "impersonation"
+	ReportReasonOther          ReportReason = "other"
+)
+
+// ReportReasons lists the categories a user can choose from
+var ReportReasons = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHateSpeech,
+	ReportReasonViolence,
+	ReportReasonSexualContent,
+	ReportReasonSelfHarm,
+	ReportReasonMisinformation,
+	ReportReasonImpersonation,
+	ReportReasonOther,
+}
+
+// IsValid returns whether the reason is one of ReportReasons
+func (r ReportReason) IsValid() bool {
+	for _, reason := range ReportReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// ReportStatus represents the moderation state of a report
+type ReportStatus string
+
+const (
+	// ReportStatusOpen is a report waiting for a moderator
+	ReportStatusOpen ReportStatus = "open"
+	// ReportStatusResolved is a report that led to an action
+	ReportStatusResolved ReportStatus = "resolved"
+	// ReportStatusDismissed is a report that was reviewed without action
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// IsValid returns whether the status is a known report status
+func (s ReportStatus) IsValid() bool {
+	return s == ReportStatusOpen || s == ReportStatusResolved || s == ReportStatusDismissed
+}
+
+// Report represents a user's report about a post or an account
+type Report struct {
+	ID uuid.UUID `json:"id"`
+	// ReporterID is nil for reports created automatically by the content filter
+	ReporterID *uuid.UUID       `json:"reporter_id"`
+	TargetType ReportTargetType `json:"target_type"`
+	TargetID   uuid.UUID        `json:"target_id"`
+	// TargetUserID is the author of the reported post or the reported account
+	TargetUserID   uuid.UUID    `json:"target_user_id"`
+	Reason         ReportReason `json:"reason"`
+	Comment        string       `json:"comment"`
+	Status         ReportStatus `json:"status"`
+	ResolvedBy     *uuid.UUID   `json:"resolved_by,omitempty"`
+	ResolutionNote string       `json:"resolution_note"`
+	ResolvedAt     *time.Time   `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// NewReport creates a new open report
+func NewReport(reporterID uuid.UUID, targetType ReportTargetType, targetID, targetUserID uuid.UUID, reason ReportReason, comment string) *Report {
+	return &Report{
+		ID:           uuid.New(),
+		ReporterID:   &reporterID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: targetUserID,
+		Reason:       reason,
+		Comment:      comment,
+		Status:       ReportStatusOpen,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+// NewAutomaticReport creates a new open report without a reporter
+func NewAutomaticReport(targetType ReportTargetType, targetID, targetUserID uuid.UUID, reason ReportReason, comment string) *Report {
+	return &Report{
+		ID:           uuid.New(),
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: targetUserID,
+		Reason:       reason,
+		Comment:      comment,
+		Status:       ReportStatusOpen,
+		CreatedAt:    time.Now().UTC(),
+	}
+}
+
+// IsAutomatic reports whether the report was created by the content filter
+func (r *Report) IsAutomatic() bool {
+	return r.ReporterID == nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchHistoryEntry represents a recent search by a user
+type SearchHistoryEntry struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Query      string    `json:"query"`
+	SearchedAt time.Time `json:"searched_at"`
+}
+
+// SavedSearch represents a search query saved by a user
+type SavedSearch struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Query  string    `json:"query"`
+	// Notify enables notifications when new posts match the query
+	Notify bool `json:"notify"`
+	// LastCheckedAt is the time up to which new posts have been checked
+	LastCheckedAt time.Time `json:"last_checked_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewSavedSearch creates a new saved search with default values
+func NewSavedSearch(userID uuid.UUID, query string, notify bool) *SavedSearch {
+	now := time.Now().UTC()
+	return &SavedSearch{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Query:         query,
+		Notify:        notify,
+		LastCheckedAt: now,
+		CreatedAt:     now,
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimelineOrder represents how the home timeline is ordered
+type TimelineOrder string
+
+const (
+	// TimelineOrderLatest lists posts from newest to oldest
+	TimelineOrderLatest TimelineOrder = "latest"
+	// TimelineOrderRanked lists recent posts from accounts the user interacts with first
+	TimelineOrderRanked TimelineOrder = "ranked"
+)
+
+// IsValid returns whether the order is a known order
+func (o TimelineOrder) IsValid() bool {
+	return o == TimelineOrderLatest || o == TimelineOrderRanked
+}
+
+// UserSettings represents per-user preferences
+type UserSettings struct {
+	UserID uuid.UUID `json:"-"`
+	// ExploreExcludedKeywords are keywords and hashtags hidden from explore (home is not affected)
+	ExploreExcludedKeywords []string `json:"explore_excluded_keywords"`
+	// ProfileVisitorsEnabled opts in to recording and seeing profile visitors (both users must opt in)
+	ProfileVisitorsEnabled bool `json:"profile_visitors_enabled"`
+	// NotificationGrouping maps a client type to its notification representation
+	NotificationGrouping map[string]NotificationGrouping `json:"notification_grouping"`
+	// ProfileAccentColor is the "#rrggbb" accent color of the profile, empty for the default
+	ProfileAccentColor string `json:"profile_accent_color"`
+	// PinnedHashtags are hashtags shown on the profile, without "#"
+	PinnedHashtags []string `json:"pinned_hashtags"`
+	// PresenceVisible shows the online status and last seen time to other users
+	PresenceVisible bool `json:"presence_visible"`
+	// LikesVisible shows the posts the user has liked to other users
+	LikesVisible bool `json:"likes_visible"`
+	// HomeTimelineOrder is the order of the home timeline when the client does not specify one
+	HomeTimelineOrder TimelineOrder `json:"home_timeline_order"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// NewUserSettings creates settings with default values for the given user
+func NewUserSettings(userID uuid.UUID) *UserSettings {
+	return &UserSettings{
+		UserID:                  userID,
+		ExploreExcludedKeywords: []string{},
+		NotificationGrouping:    map[string]NotificationGrouping{},
+		PinnedHashtags:          []string{},
+		PresenceVisible:         true,
+		LikesVisible:            true,
+		HomeTimelineOrder:       TimelineOrderLatest,
+		UpdatedAt:               time.Now().UTC(),
+	}
+}
+
+// NotificationGroupingFor returns the notification representation for the client type (flat when not set)
+func (s *UserSettings) NotificationGroupingFor(clientType string) NotificationGrouping {
+	if grouping, ok := s.NotificationGrouping[clientType]; ok {
+		return grouping
+	}
+	return NotificationGroupingFlat
+}
+
+// ProfileTheme returns the profile customization shown to other users
+func (s *UserSettings) ProfileTheme() ProfileTheme {
+	return ProfileTheme{
+		AccentColor:    s.ProfileAccentColor,
+		PinnedHashtags: s.PinnedHashtags,
+	}
+}
+
+// PresenceVisibleTo reports whether the online status is shown to the viewer (always shown to the user themselves)
+func (s *UserSettings) PresenceVisibleTo(viewerID uuid.UUID) bool {
+	return s.PresenceVisible || viewerID == s.UserID
+}
+
+// LikesVisibleTo reports whether the liked posts are shown to the viewer (always shown to the user themselves)
+func (s *UserSettings) LikesVisibleTo(viewerID uuid.UUID) bool {
+	return s.LikesVisible || viewerID == s.UserID
+}
+
+// ProfileVisit represents the most recent visit of a user to another user's profile
+type ProfileVisit struct {
+	ProfileUserID uuid.UUID `json:"profile_user_id"`
+	VisitorID     uuid.UUID `json:"visitor_id"`
+	VisitedAt     time.Time `json:"visited_at"`
+}
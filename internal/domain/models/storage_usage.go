@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StorageUsage represents the total size of the media a user has uploaded and still references
+type StorageUsage struct {
+	UserID    uuid.UUID `json:"user_id"`
+	UsedBytes int64     `json:"used_bytes"`
+	FileCount int       `json:"file_count"`
+	// QuotaBytes is the maximum total size, or zero when uploads are unlimited
+	QuotaBytes int64     `json:"quota_bytes"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RemainingBytes returns the size that can still be uploaded, or -1 when uploads are unlimited
+func (u *StorageUsage) RemainingBytes() int64 {
+	if u.QuotaBytes <= 0 {
+		return -1
+	}
+	if u.UsedBytes >= u.QuotaBytes {
+		return 0
+	}
+	return u.QuotaBytes - u.UsedBytes
+}
+
+// Allows reports whether a file of size bytes fits in the quota
+func (u *StorageUsage) Allows(size int64) bool {
+	return u.QuotaBytes <= 0 || u.UsedBytes+size <= u.QuotaBytes
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupporterEventType represents the kind of event received from the payment provider
+type SupporterEventType string
+
+const (
+	// SupporterEventActivated is sent when a user starts supporting
+	SupporterEventActivated SupporterEventType = "supporter.activated"
+	// SupporterEventRenewed is sent when a support period is extended
+	SupporterEventRenewed SupporterEventType = "supporter.renewed"
+	// SupporterEventCancelled is sent when a user stops supporting (perks last until expires_at)
+	SupporterEventCancelled SupporterEventType = "supporter.cancelled"
+	// SupporterEventRefunded is sent when a payment is refunded (perks end immediately)
+	SupporterEventRefunded SupporterEventType = "supporter.refunded"
+)
+
+// IsValid returns whether the event type is one of the defined values
+func (t SupporterEventType) IsValid() bool {
+	switch t {
+	case SupporterEventActivated, SupporterEventRenewed, SupporterEventCancelled, SupporterEventRefunded:
+		return true
+	}
+	return false
+}
+
+// SupporterEvent represents a webhook event from the payment provider
+type SupporterEvent struct {
+	ID        string             `json:"id"`
+	Type      SupporterEventType `json:"type"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Tier      string             `json:"tier"`
+	ExpiresAt *time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncEventType represents the kind of change recorded for client delta sync
+type SyncEventType string
+
+const (
+	// SyncEventPostCreated is recorded when a user publishes a post
+	SyncEventPostCreated SyncEventType = "post_created"
+	// SyncEventPostDeleted is recorded when a post is deleted or purged after expiring
+	SyncEventPostDeleted SyncEventType = "post_deleted"
+	// SyncEventFollowed is recorded when a user starts following another user
+	SyncEventFollowed SyncEventType = "followed"
+	// SyncEventUnfollowed is recorded when a user stops following another user
+	SyncEventUnfollowed SyncEventType = "unfollowed"
+	// SyncEventNotificationCreated is recorded when a user receives a notification
+	SyncEventNotificationCreated SyncEventType = "notification_created"
+	// SyncEventNotificationRead is recorded when a single notification is marked as read
+	SyncEventNotificationRead SyncEventType = "notification_read"
+	// SyncEventNotificationsRead is recorded when all of a user's notifications are marked as read
+	SyncEventNotificationsRead SyncEventType = "notifications_read"
+	// SyncEventNotificationDeleted is recorded when a notification is deleted
+	SyncEventNotificationDeleted SyncEventType = "notification_deleted"
+)
+
+// SyncEvent represents a change that offline-first clients apply to their local state.
+// UserID is the post author, the follower or the notification recipient; TargetUserID
+// is the followed user; SubjectID is the post or notification the change is about.
+type SyncEvent struct {
+	Seq          int64         `json:"seq"`
+	Type         SyncEventType `json:"type"`
+	UserID       uuid.UUID     `json:"user_id"`
+	TargetUserID *uuid.UUID    `json:"target_user_id,omitempty"`
+	SubjectID    *uuid.UUID    `json:"subject_id,omitempty"`
+	OccurredAt   time.Time     `json:"occurred_at"`
+}
+
+// SyncPage represents the changes returned to a client after its cursor
+type SyncPage struct {
+	Events []*SyncEvent `json:"events"`
+	// NextCursor is the cursor to send in the next request
+	NextCursor int64 `json:"next_cursor,string"`
+	// HasMore reports whether more changes are available right away
+	HasMore bool `json:"has_more"`
+	// ResetRequired tells the client to refetch everything because its cursor is
+	// missing, invalid or older than the retained changes
+	ResetRequired bool `json:"reset_required"`
+}
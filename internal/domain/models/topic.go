@@ -0,0 +1,103 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxTopicsPerPost is the maximum number of topics an author can choose for a post
+	MaxTopicsPerPost = 3
+	// MaxTopicHashtags is the maximum number of hashtags that classify posts into a topic
+	MaxTopicHashtags = 20
+	// MaxTopicNameLength is the maximum number of characters of a topic name
+	MaxTopicNameLength = 100
+)
+
+// topicSlugPattern matches a lowercase slug such as "tech" or "video-games"
+var topicSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// TopicSource represents how a post was classified into a topic
+type TopicSource string
+
+const (
+	// TopicSourceManual is a topic chosen by the author
+	TopicSourceManual TopicSource = "manual"
+	// TopicSourceHashtag is a topic derived from a hashtag in the post
+	TopicSourceHashtag TopicSource = "hashtag"
+)
+
+// Topic represents a category of the explore tab managed by admins
+type Topic struct {
+	ID          uuid.UUID `json:"id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// Hashtags are the lowercased hashtags, without "#", that classify new posts into the topic
+	Hashtags  []string   `json:"hashtags"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// NewTopic creates a new topic created by the given admin
+func NewTopic(slug, name, description string, hashtags []string, createdBy uuid.UUID) *Topic {
+	if hashtags == nil {
+		hashtags = []string{}
+	}
+	now := time.Now().UTC()
+	return &Topic{
+		ID:          uuid.New(),
+		Slug:        slug,
+		Name:        name,
+		Description: description,
+		Hashtags:    hashtags,
+		CreatedBy:   &createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// NormalizeTopicSlug trims and lowercases a slug. It reports false when the
+// slug is not 1 to 50 letters, digits and "-" starting with a letter or digit.
+func NormalizeTopicSlug(slug string) (string, bool) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if !topicSlugPattern.MatchString(slug) {
+		return "", false
+	}
+	return slug, true
+}
+
+// NormalizeTopicHashtags normalizes the hashtags of a topic and removes
+// duplicates. It reports false when a hashtag is invalid or there are more
+// than MaxTopicHashtags.
+func NormalizeTopicHashtags(hashtags []string) ([]string, bool) {
+	normalized := make([]string, 0, len(hashtags))
+	seen := make(map[string]bool, len(hashtags))
+	for _, hashtag := range hashtags {
+		tag, ok := NormalizeHashtag(hashtag)
+		if !ok {
+			return nil, false
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > MaxTopicHashtags {
+		return nil, false
+	}
+	return normalized, true
+}
+
+// IsValidTopicName reports whether the name is not empty and not too long
+func IsValidTopicName(name string) bool {
+	name = strings.TrimSpace(name)
+	return name != "" && utf8.RuneCountInString(name) <= MaxTopicNameLength
+}
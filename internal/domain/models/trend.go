@@ -0,0 +1,11 @@
+package models
+
+// Trend represents how much a hashtag has been used over a period
+type Trend struct {
+	// Hashtag is the lower-cased hashtag without "#"
+	Hashtag string `json:"hashtag"`
+	// PostCount is the number of posts containing the hashtag
+	PostCount int64 `json:"post_count"`
+	// UserCount is the number of authors of those posts, counted once per hour
+	UserCount int64 `json:"user_count"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadSession represents a file uploaded in parts over several requests,
+// assembled on the server once every part has been received
+type UploadSession struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Filename  string    `json:"filename"`
+	TotalSize int64     `json:"total_size"`
+	// PartSize is the size of every part except the last, which holds the remainder
+	PartSize  int64 `json:"part_size"`
+	PartCount int   `json:"part_count"`
+	// ReceivedParts are the zero-based indexes of the parts stored so far, in ascending order
+	ReceivedParts []int `json:"received_parts"`
+	// URL is where the assembled file was stored, empty until the session completes
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewUploadSession creates a session that splits totalSize bytes into parts of partSize bytes
+func NewUploadSession(userID uuid.UUID, filename string, totalSize, partSize int64, ttl time.Duration) *UploadSession {
+	now := time.Now().UTC()
+	return &UploadSession{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Filename:      filename,
+		TotalSize:     totalSize,
+		PartSize:      partSize,
+		PartCount:     int((totalSize + partSize - 1) / partSize),
+		ReceivedParts: []int{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		ExpiresAt:     now.Add(ttl),
+	}
+}
+
+// PartLength returns the expected size of a part, or -1 for an index out of range
+func (s *UploadSession) PartLength(index int) int64 {
+	if index < 0 || index >= s.PartCount {
+		return -1
+	}
+	if index == s.PartCount-1 {
+		return s.TotalSize - int64(index)*s.PartSize
+	}
+	return s.PartSize
+}
+
+// MissingParts returns the indexes of the parts not received yet, in ascending order
+func (s *UploadSession) MissingParts() []int {
+	received := make(map[int]bool, len(s.ReceivedParts))
+	for _, index := range s.ReceivedParts {
+		received[index] = true
+	}
+
+	missing := make([]int, 0, s.PartCount-len(received))
+	for index := 0; index < s.PartCount; index++ {
+		if !received[index] {
+			missing = append(missing, index)
+		}
+	}
+	return missing
+}
+
+// IsReady reports whether every part has been received
+func (s *UploadSession) IsReady() bool {
+	return len(s.MissingParts()) == 0
+}
+
+// IsCompleted reports whether the parts have been assembled and stored
+func (s *UploadSession) IsCompleted() bool {
+	return s.URL != ""
+}
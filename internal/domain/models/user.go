@@ -6,24 +6,88 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserStatus represents whether an account is active
+type UserStatus string
+
+const (
+	// UserStatusActive is the status of a normal account
+	UserStatusActive UserStatus = "active"
+	// UserStatusDeactivated hides the account until the user logs in again within the grace period
+	UserStatusDeactivated UserStatus = "deactivated"
+	// UserStatusDeleting hides the account while its data is being deleted
+	UserStatusDeleting UserStatus = "deleting"
+	// UserStatusSuspended hides the account until the identity provider re-enables it through SCIM
+	UserStatusSuspended UserStatus = "suspended"
+	// UserStatusMerging hides the account while its data is being merged into another account
+	UserStatusMerging UserStatus = "merging"
+	// UserStatusBanned hides the account until an admin lifts the suspension; SCIM cannot change it
+	UserStatusBanned UserStatus = "banned"
+)
+
+// UserRole represents the permissions of an account
+type UserRole string
+
+const (
+	// UserRoleUser is the role of a normal account
+	UserRoleUser UserRole = "user"
+	// UserRoleModerator can access the moderation endpoints
+	UserRoleModerator UserRole = "moderator"
+	// UserRoleAdmin can access the admin and moderation endpoints
+	UserRoleAdmin UserRole = "admin"
+)
+
+// userRoleRanks orders the roles so that a higher role includes the permissions of the lower ones
+var userRoleRanks = map[UserRole]int{
+	UserRoleUser:      0,
+	UserRoleModerator: 1,
+	UserRoleAdmin:     2,
+}
+
+// IsValid returns whether the role is a known role
+func (r UserRole) IsValid() bool {
+	_, ok := userRoleRanks[r]
+	return ok
+}
+
+// Includes returns whether the role has at least the permissions of the given role
+func (r UserRole) Includes(required UserRole) bool {
+	rank, ok := userRoleRanks[r]
+	if !ok {
+		return false
+	}
+	return rank >= userRoleRanks[required]
+}
+
 // User represents a user in the system
 type User struct {
-	ID             uuid.UUID `json:"id"`
-	Username       string    `json:"username"`
-	Email          string    `json:"email"`
-	Password       string    `json:"-"` // パスワードはJSONにシリアライズしない
-	Name           string    `json:"name"`
-	Bio            string    `json:"bio"`
-	ProfileImage   string    `json:"profile_image"`
-	BannerImage    string    `json:"banner_image"`
-	Location       string    `json:"location"`
-	WebsiteURL     string    `json:"website_url"`
-	FollowerCount  int       `json:"follower_count"`
-	FollowingCount int       `json:"following_count"`
-	PostCount      int       `json:"post_count"`
-	IsVerified     bool      `json:"is_verified"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	Username       string     `json:"username"`
+	Email          string     `json:"email"`
+	Password       string     `json:"-"` // パスワードはJSONにシリアライズしない
+	Name           string     `json:"name"`
+	Bio            string     `json:"bio"`
+	ProfileImage   string     `json:"profile_image"`
+	BannerImage    string     `json:"banner_image"`
+	Location       string     `json:"location"`
+	WebsiteURL     string     `json:"website_url"`
+	FollowerCount  int        `json:"follower_count"`
+	FollowingCount int        `json:"following_count"`
+	PostCount      int        `json:"post_count"`
+	IsVerified     bool       `json:"is_verified"`
+	IsAgeVerified  bool       `json:"is_age_verified"`
+	BirthDate      *time.Time `json:"-"` // 年齢確認で登録された生年月日
+	CountryCode    string     `json:"country_code"`
+	SupporterTier  string     `json:"supporter_tier,omitempty"`
+	SupporterUntil *time.Time `json:"supporter_until,omitempty"` // サポーター特典の有効期限
+	IsSystem       bool       `json:"is_system"`                 // 運営が管理するシステムアカウントかどうか
+	Status         UserStatus `json:"-"`
+	Role           UserRole   `json:"-"`
+	DeactivatedAt  *time.Time `json:"-"` // アカウントを無効化した日時
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// PasswordResetRequired is set by an admin to require a new password on the next login
+	PasswordResetRequired bool `json:"-"`
 }
 
 // NewUser creates a new user with default values
@@ -44,11 +108,79 @@ func NewUser(username, email, password, name string) *User {
 		FollowingCount: 0,
 		PostCount:      0,
 		IsVerified:     false,
+		Status:         UserStatusActive,
+		Role:           UserRoleUser,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
 }
 
+// AgeAt returns the user's age at the given time, or -1 if the birth date is unknown
+func (u *User) AgeAt(t time.Time) int {
+	if u.BirthDate == nil {
+		return -1
+	}
+
+	birth := u.BirthDate.UTC()
+	t = t.UTC()
+	age := t.Year() - birth.Year()
+	if t.Month() < birth.Month() || (t.Month() == birth.Month() && t.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
+
+// IsDeactivated returns whether the account has been deactivated
+func (u *User) IsDeactivated() bool {
+	return u.Status == UserStatusDeactivated
+}
+
+// IsDeleting returns whether the account is scheduled for deletion
+func (u *User) IsDeleting() bool {
+	return u.Status == UserStatusDeleting
+}
+
+// IsSuspended returns whether the identity provider disabled the account through SCIM.
+// Accounts suspended by an admin are reported by IsBanned instead
+func (u *User) IsSuspended() bool {
+	return u.Status == UserStatusSuspended
+}
+
+// IsBanned returns whether an admin suspended the account
+func (u *User) IsBanned() bool {
+	return u.Status == UserStatusBanned
+}
+
+// IsMerging returns whether the account is being merged into another account
+func (u *User) IsMerging() bool {
+	return u.Status == UserStatusMerging
+}
+
+// IsAdmin returns whether the account has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// IsModerator returns whether the account has the moderator role or a higher one
+func (u *User) IsModerator() bool {
+	return u.Role.Includes(UserRoleModerator)
+}
+
+// CanReactivateAt returns whether a deactivated account can still be reactivated at the given time
+func (u *User) CanReactivateAt(t time.Time, gracePeriod time.Duration) bool {
+	return u.IsDeactivated() && u.DeactivatedAt != nil && t.Before(u.DeactivatedAt.Add(gracePeriod))
+}
+
+// IsSupporter returns whether the user currently has an active supporter badge
+func (u *User) IsSupporter() bool {
+	return u.IsSupporterAt(time.Now())
+}
+
+// IsSupporterAt returns whether the user's supporter badge is active at the given time
+func (u *User) IsSupporterAt(t time.Time) bool {
+	return u.SupporterTier != "" && u.SupporterUntil != nil && t.Before(*u.SupporterUntil)
+}
+
 // UserResponse represents the user data sent to clients
 type UserResponse struct {
 	ID             uuid.UUID `json:"id"`
@@ -64,6 +196,7 @@ type UserResponse struct {
 	FollowingCount int       `json:"following_count"`
 	PostCount      int       `json:"post_count"`
 	IsVerified     bool      `json:"is_verified"`
+	IsSupporter    bool      `json:"is_supporter"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
@@ -83,6 +216,7 @@ func (u *User) ToResponse() *UserResponse {
 		FollowingCount: u.FollowingCount,
 		PostCount:      u.PostCount,
 		IsVerified:     u.IsVerified,
+		IsSupporter:    u.IsSupporter(),
 		CreatedAt:      u.CreatedAt,
 	}
 }
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// CohortStats represents the signup cohort of a single day and how many of its users returned.
+// Retained counts are nil until the corresponding day has passed.
+type CohortStats struct {
+	CohortDate  time.Time `json:"cohort_date"`
+	CohortSize  int       `json:"cohort_size"`
+	D1Retained  *int      `json:"d1_retained"`
+	D7Retained  *int      `json:"d7_retained"`
+	D30Retained *int      `json:"d30_retained"`
+	ComputedAt  time.Time `json:"computed_at"`
+}
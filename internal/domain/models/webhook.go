@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent represents the kind of event sent to a personal webhook
+type WebhookEvent string
+
+const (
+	// WebhookEventFollow is sent when someone follows the webhook's owner
+	WebhookEventFollow WebhookEvent = "follow"
+	// WebhookEventMention is sent when someone mentions the webhook's owner in a post
+	WebhookEventMention WebhookEvent = "mention"
+	// WebhookEventTest is sent by the test delivery endpoint and cannot be subscribed to
+	WebhookEventTest WebhookEvent = "test"
+)
+
+// WebhookEvents lists the events a personal webhook can subscribe to
+var WebhookEvents = []WebhookEvent{WebhookEventFollow, WebhookEventMention}
+
+// IsValid reports whether the event can be subscribed to
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// UserWebhook represents a URL a user registered to receive their own events
+type UserWebhook struct {
+	ID     uuid.UUID      `json:"id"`
+	UserID uuid.UUID      `json:"user_id"`
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+	// Secret signs the deliveries and is only returned when it is created or rotated
+	Secret string `json:"-"`
+	Active bool   `json:"active"`
+	// FailureCount is the number of consecutive failed deliveries
+	FailureCount    int        `json:"failure_count"`
+	LastStatusCode  *int       `json:"last_status_code,omitempty"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NewUserWebhook creates a new active webhook
+func NewUserWebhook(userID uuid.UUID, url string, events []WebhookEvent, secret string) *UserWebhook {
+	now := time.Now().UTC()
+	return &UserWebhook{
+		ID:        uuid.New(),
+		UserID:    userID,
+		URL:       url,
+		Events:    events,
+		Secret:    secret,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Subscribes reports whether the webhook receives the event
+func (w *UserWebhook) Subscribes(event WebhookEvent) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the JSON body sent to a personal webhook
+type WebhookPayload struct {
+	// ID identifies the delivery so that receivers can ignore duplicates
+	ID        uuid.UUID    `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      interface{}  `json:"data"`
+}
+
+// WebhookDelivery is the result of sending a payload to a webhook
+type WebhookDelivery struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,148 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+)
+
+// 本文をbase64で符号化する際の1行の文字数（RFC 2045）
+const base64LineLength = 76
+
+// SMTPSender はSMTPサーバーを通じてメールを送信する送信先です
+// サーバーがSTARTTLSに対応している場合は暗号化してから認証・送信します
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     mail.Address
+	timeout  time.Duration
+}
+
+// NewSMTPSender は新しいSMTPSenderインスタンスを作成します
+// usernameが空の場合は認証せずに送信します
+func NewSMTPSender(host string, port int, username, password, fromAddress, fromName string, timeout time.Duration) (interfaces.EmailSender, error) {
+	from, err := mail.ParseAddress(fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("送信元のメールアドレスが不正です: %w", err)
+	}
+	if fromName != "" {
+		from.Name = fromName
+	}
+
+	return &SMTPSender{
+		addr:     net.JoinHostPort(host, fmt.Sprint(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     *from,
+		timeout:  timeout,
+	}, nil
+}
+
+// Send はテキスト形式のメールを1通送信します
+func (s *SMTPSender) Send(ctx context.Context, to, subject, body string) error {
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("宛先のメールアドレスが不正です: %w", err)
+	}
+
+	message, err := s.buildMessage(recipient, subject, body)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(recipient.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildMessage ヘッダーとbase64で符号化したUTF-8の本文からメールを組み立てる
+func (s *SMTPSender) buildMessage(to *mail.Address, subject, body string) ([]byte, error) {
+	messageID := make([]byte, 16)
+	if _, err := rand.Read(messageID); err != nil {
+		return nil, err
+	}
+
+	// 改行を含む件名でヘッダーを追加されないようにする
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(messageID), s.host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > base64LineLength {
+		buf.WriteString(encoded[:base64LineLength] + "\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+	buf.WriteString(encoded + "\r\n")
+
+	return buf.Bytes(), nil
+}
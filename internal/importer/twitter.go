@@ -0,0 +1,284 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// アーカイブのツイートの投稿日時の形式（例: Wed Oct 10 20:19:24 +0000 2018）
+const twitterTimeLayout = time.RubyDate
+
+// ツイートを含むファイル（古いアーカイブは tweet.js、分割されている場合は tweets-part1.js など）
+var twitterTweetsFilePattern = regexp.MustCompile(`^tweets?(-part\d+)?\.js$`)
+
+// 言語を判定できなかったツイートなど、Twitter独自の言語コード
+var twitterPseudoLanguages = map[string]bool{
+	"und": true, "zxx": true, "qam": true, "qct": true, "qht": true, "qme": true, "qst": true,
+}
+
+// TwitterArchive Twitter/Xのアカウントのアーカイブ（ZIP）から読み込んだ内容
+type TwitterArchive struct {
+	// アーカイブのアカウントのIDとユーザー名
+	AccountID string
+	Username  string
+	// 投稿日時の古い順のツイート
+	Tweets []*TwitterTweet
+}
+
+// TwitterTweet アーカイブのツイート1件
+type TwitterTweet struct {
+	ID string
+	// HTMLエスケープを戻し、短縮URLを元のURLに展開した本文（添付メディアのURLは除く）
+	Text              string
+	CreatedAt         time.Time
+	InReplyToStatusID string
+	InReplyToUserID   string
+	Language          string
+	// リツイート（本文が「RT @」で始まるもの）かどうか
+	IsRetweet bool
+}
+
+// TwitterImportOptions ツイートを投稿に変換する際の設定
+type TwitterImportOptions struct {
+	// 他のユーザーへの返信も（返信ではない）投稿として取り込むか
+	IncludeReplies bool
+}
+
+// TwitterImportSummary ツイートの変換結果の件数
+type TwitterImportSummary struct {
+	// 投稿に変換したツイート
+	Converted int
+	// 取り込み済みのツイート
+	AlreadyImported int
+	// 取り込まなかったリツイート・他のユーザーへの返信・本文が長すぎるツイート・本文のないツイート（メディアのみなど）
+	Retweets int
+	Replies  int
+	TooLong  int
+	Empty    int
+}
+
+// twitterArchiveEntry アーカイブのJSONの要素（新しいアーカイブは {"tweet": {...}} の形式）
+type twitterArchiveEntry struct {
+	Tweet   *twitterArchiveTweet `json:"tweet"`
+	Account *struct {
+		AccountID string `json:"accountId"`
+		Username  string `json:"username"`
+	} `json:"account"`
+}
+
+type twitterArchiveTweet struct {
+	ID                string `json:"id_str"`
+	FullText          string `json:"full_text"`
+	CreatedAt         string `json:"created_at"`
+	InReplyToStatusID string `json:"in_reply_to_status_id_str"`
+	InReplyToUserID   string `json:"in_reply_to_user_id_str"`
+	Lang              string `json:"lang"`
+	Entities          struct {
+		URLs []struct {
+			URL         string `json:"url"`
+			ExpandedURL string `json:"expanded_url"`
+		} `json:"urls"`
+		Media []struct {
+			URL string `json:"url"`
+		} `json:"media"`
+	} `json:"entities"`
+}
+
+// ReadTwitterArchive Twitter/Xのアーカイブ（ZIP）からアカウントとツイートを読み込む
+func ReadTwitterArchive(r io.ReaderAt, size int64) (*TwitterArchive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("アーカイブを開けません: %w", err)
+	}
+
+	archive := &TwitterArchive{}
+	found := false
+	for _, file := range zr.File {
+		if path.Base(path.Dir(file.Name)) != "data" {
+			continue
+		}
+
+		name := path.Base(file.Name)
+		switch {
+		case name == "account.js":
+			entries, err := readTwitterArchiveFile(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.Account != nil {
+					archive.AccountID = entry.Account.AccountID
+					archive.Username = entry.Account.Username
+				}
+			}
+		case twitterTweetsFilePattern.MatchString(name):
+			found = true
+			entries, err := readTwitterArchiveFile(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.Tweet == nil {
+					continue
+				}
+				tweet, err := convertTwitterTweet(entry.Tweet)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", file.Name, err)
+				}
+				archive.Tweets = append(archive.Tweets, tweet)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("アーカイブにツイート（data/tweets.js）が含まれていません")
+	}
+
+	sort.SliceStable(archive.Tweets, func(i, j int) bool {
+		return archive.Tweets[i].CreatedAt.Before(archive.Tweets[j].CreatedAt)
+	})
+
+	return archive, nil
+}
+
+// readTwitterArchiveFile アーカイブのJavaScriptファイル（window.YTD.tweets.part0 = [...]）の配列を読み込む
+func readTwitterArchiveFile(file *zip.File) ([]twitterArchiveEntry, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s を開けません: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%s を読み込めません: %w", file.Name, err)
+	}
+
+	// 代入文の右辺のみをJSONとして読み込む
+	if i := bytes.IndexByte(content, '='); i >= 0 && bytes.HasPrefix(bytes.TrimSpace(content), []byte("window.")) {
+		content = content[i+1:]
+	}
+	content = bytes.TrimSuffix(bytes.TrimSpace(content), []byte(";"))
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("%s の形式が正しくありません: %w", file.Name, err)
+	}
+
+	entries := make([]twitterArchiveEntry, 0, len(raw))
+	for _, item := range raw {
+		var entry twitterArchiveEntry
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return nil, fmt.Errorf("%s の形式が正しくありません: %w", file.Name, err)
+		}
+		// 古いアーカイブはツイートを直接並べている
+		if entry.Tweet == nil && entry.Account == nil {
+			var tweet twitterArchiveTweet
+			if err := json.Unmarshal(item, &tweet); err == nil && tweet.ID != "" {
+				entry.Tweet = &tweet
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// convertTwitterTweet アーカイブのツイートの本文と投稿日時を変換する
+func convertTwitterTweet(raw *twitterArchiveTweet) (*TwitterTweet, error) {
+	createdAt, err := time.Parse(twitterTimeLayout, raw.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("ツイート %s の投稿日時を読み込めません: %w", raw.ID, err)
+	}
+
+	text := html.UnescapeString(raw.FullText)
+	for _, u := range raw.Entities.URLs {
+		if u.URL != "" && u.ExpandedURL != "" {
+			text = strings.ReplaceAll(text, u.URL, u.ExpandedURL)
+		}
+	}
+	// 添付メディアは取り込まないため、メディアへの短縮URLを除く
+	for _, media := range raw.Entities.Media {
+		if media.URL != "" {
+			text = strings.ReplaceAll(text, media.URL, "")
+		}
+	}
+	text = strings.TrimSpace(text)
+
+	return &TwitterTweet{
+		ID:                raw.ID,
+		Text:              text,
+		CreatedAt:         createdAt.UTC(),
+		InReplyToStatusID: raw.InReplyToStatusID,
+		InReplyToUserID:   raw.InReplyToUserID,
+		Language:          raw.Lang,
+		IsRetweet:         strings.HasPrefix(raw.FullText, "RT @"),
+	}, nil
+}
+
+// BuildTwitterImports アーカイブのツイートをuserIDのユーザーの投稿に変換する
+// importedは取り込み済みのツイートのIDと投稿のIDの対応で、取り込み済みのツイートは除き、返信先の解決に使用する
+// 自分のツイートへの返信は返信先が取り込まれている場合に返信として、それ以外は返信ではない投稿として取り込む
+func BuildTwitterImports(archive *TwitterArchive, userID uuid.UUID, imported map[string]uuid.UUID, options TwitterImportOptions) ([]*models.PostImport, TwitterImportSummary) {
+	var summary TwitterImportSummary
+	postIDs := make(map[string]uuid.UUID, len(imported)+len(archive.Tweets))
+	for tweetID, postID := range imported {
+		postIDs[tweetID] = postID
+	}
+
+	imports := make([]*models.PostImport, 0, len(archive.Tweets))
+	for _, tweet := range archive.Tweets {
+		switch {
+		case imported[tweet.ID] != uuid.Nil:
+			summary.AlreadyImported++
+			continue
+		case tweet.IsRetweet:
+			summary.Retweets++
+			continue
+		case tweet.Text == "":
+			summary.Empty++
+			continue
+		case len(tweet.Text) > models.MaxPostContentLength:
+			summary.TooLong++
+			continue
+		}
+
+		selfReply := tweet.InReplyToStatusID != "" && archive.AccountID != "" && tweet.InReplyToUserID == archive.AccountID
+		if tweet.InReplyToStatusID != "" && !selfReply && !options.IncludeReplies {
+			summary.Replies++
+			continue
+		}
+
+		var post *models.Post
+		if parentID, ok := postIDs[tweet.InReplyToStatusID]; selfReply && ok {
+			post = models.NewReply(userID, parentID, tweet.Text, nil)
+		} else {
+			post = models.NewPost(userID, tweet.Text, nil)
+		}
+		post.CreatedAt = tweet.CreatedAt
+		post.UpdatedAt = tweet.CreatedAt
+		if language, ok := models.NormalizeLanguage(tweet.Language); ok && !twitterPseudoLanguages[language] {
+			post.Language = language
+		}
+
+		postIDs[tweet.ID] = post.ID
+		imports = append(imports, &models.PostImport{
+			Source:     models.PostImportSourceTwitter,
+			ExternalID: tweet.ID,
+			Post:       post,
+		})
+		summary.Converted++
+	}
+
+	return imports, summary
+}
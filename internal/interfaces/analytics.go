@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// AnalyticsSink は分析イベント（インプレッション・クリック・登録）の送信先を定義するインターフェース
+type AnalyticsSink interface {
+	// Name はログに表示する送信先の名前を返します
+	Name() string
+
+	// Write はイベントをまとめて送信します（失敗した場合はまとめて再送されます）
+	Write(ctx context.Context, events []*models.AnalyticsEvent) error
+
+	// Close は送信先のリソースを解放します
+	Close() error
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// EmailSender はメールの送信先（SMTPサーバーなど）を定義するインターフェース
+type EmailSender interface {
+	// Send はテキスト形式のメールを1通送信します
+	Send(ctx context.Context, to, subject, body string) error
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// ErrInvalidPushToken は端末トークンが無効（アプリの削除・トークンの期限切れなど）で、今後も送信できないことを表す
+var ErrInvalidPushToken = errors.New("invalid push token")
+
+// ErrPushRejected はプッシュサービスが通知の内容や設定を受け付けず、再送しても成功しないことを表す
+var ErrPushRejected = errors.New("push rejected")
+
+// PushProvider はモバイル端末へのプッシュ通知の送信先（FCM・APNs）を定義するインターフェース
+type PushProvider interface {
+	// Platform は送信できる端末のプラットフォームを返します
+	Platform() models.DevicePlatform
+
+	// Send は端末へ通知を送信します
+	// トークンが無効な場合はErrInvalidPushToken、再送しても成功しない場合はErrPushRejectedを含むエラーを返します（それ以外のエラーは再送されます）
+	Send(ctx context.Context, token string, message *models.PushMessage) error
+
+	// Close は送信先のリソースを解放します
+	Close() error
+}
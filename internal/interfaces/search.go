@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// SearchIndexer は投稿・ユーザーを索引に登録して全文検索する外部の検索エンジン（Elasticsearch・OpenSearch）を定義するインターフェース
+// 検索結果はIDのみを返し、呼び出し側がデータベースから取得し直す（削除・非表示になった投稿やユーザーはそこで除かれる）
+type SearchIndexer interface {
+	// Name はログに表示する検索エンジンの名前を返します
+	Name() string
+
+	// Setup は索引がない場合に作成します
+	Setup(ctx context.Context) error
+
+	// IndexPosts は投稿をまとめて索引に登録します（登録済みの投稿は上書きします）
+	IndexPosts(ctx context.Context, posts []*models.Post) error
+
+	// IndexUsers はユーザーをまとめて索引に登録します（登録済みのユーザーは上書きします）
+	IndexUsers(ctx context.Context, users []*models.User) error
+
+	// DeletePost は投稿を索引から削除します（登録されていない場合も成功します）
+	DeletePost(ctx context.Context, id uuid.UUID) error
+
+	// DeleteUser はユーザーを索引から削除します（登録されていない場合も成功します）
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+
+	// SearchPosts は検索語に一致する投稿のIDを関連度の高い順に返します
+	SearchPosts(ctx context.Context, query string, offset, limit int) ([]uuid.UUID, error)
+
+	// SearchUsers は検索語に一致するユーザーのIDを関連度の高い順に返します
+	SearchUsers(ctx context.Context, query string, offset, limit int) ([]uuid.UUID, error)
+
+	// Close は検索エンジンへの接続を解放します
+	Close() error
+}
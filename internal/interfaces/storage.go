@@ -2,18 +2,51 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"io"
 	"time"
 )
 
+// ErrUploadPartSize は分割アップロードのパートの大きさが指定された大きさと異なることを表す
+var ErrUploadPartSize = errors.New("upload part size mismatch")
+
 // StorageProvider はメディアファイルのストレージ操作を定義するインターフェース
 type StorageProvider interface {
 	// SaveFile はファイルを保存し、そのURLを返します
 	SaveFile(ctx context.Context, path string, filename string, fileContent io.Reader, fileSize int64) (string, error)
 
+	// WriteFile はストレージ内の指定したパスにファイルを保存し、そのURLを返します（既存のファイルは置き換えます）
+	// ファイル名を変えずに別のストレージへ複製する場合に使用します
+	WriteFile(ctx context.Context, path string, fileContent io.Reader) (string, error)
+
+	// OpenFile はSaveFileが返したURLのファイルを読み込みます
+	OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error)
+
 	// DeleteFile は指定されたパスのファイルを削除します
 	DeleteFile(ctx context.Context, path string) error
 
+	// PathFromURL はSaveFileが返したURLをストレージ内のパスに変換します（このストレージのURLでない場合はfalse）
+	PathFromURL(fileURL string) (string, bool)
+
 	// GetSignedURL は期限付きの署名付きURLを生成します（第三者ストレージ用）
 	GetSignedURL(ctx context.Context, path string, expires time.Duration) (string, error)
 }
+
+// ReadFailover はプライマリのストレージが利用できない間、読み込みを別のストレージへ振り替えるストレージが実装するインターフェース
+type ReadFailover interface {
+	// FailoverURL はプライマリが利用できない場合に、公開URLに対応する複製先のURLを返します（プライマリが利用できる場合はfalse）
+	FailoverURL(ctx context.Context, fileURL string) (string, bool)
+}
+
+// UploadPartStore は分割アップロードのパートを一時的に保存し、すべて揃った時点で結合するストレージ操作を定義するインターフェース
+type UploadPartStore interface {
+	// WritePart はセッションのパートを保存します（同じパートは置き換えます）
+	// 内容がsizeバイトでない場合は保存せずにErrUploadPartSizeを返します
+	WritePart(ctx context.Context, sessionID string, index int, content io.Reader, size int64) error
+
+	// Assemble はセッションのパートを番号順に結合したファイルを開きます（閉じると結合したファイルは削除されます）
+	Assemble(ctx context.Context, sessionID string, partCount int) (io.ReadCloser, error)
+
+	// DeleteParts はセッションのパートをすべて削除します
+	DeleteParts(ctx context.Context, sessionID string) error
+}
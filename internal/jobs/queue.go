@@ -0,0 +1,339 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// データベースの操作1回にかける最大時間
+	dbTimeout = 10 * time.Second
+	// 失敗したジョブを再試行するまでの最大間隔
+	maxBackoff = time.Hour
+	// 完了・失敗したジョブを削除する間隔
+	purgeInterval = time.Hour
+	// ジョブの実行時間に加えて、他のワーカーから取得されないようにする時間（進捗の保存にかかる時間）
+	leaseMargin = 30 * time.Second
+)
+
+// ErrDuplicateJob 同じキーのジョブが登録済み
+var ErrDuplicateJob = errors.New("duplicate job")
+
+// Handler ジョブを実行する処理
+// エラーを返した場合は最大回数まで再試行する（Permanentで包んだエラーの場合は再試行しない）
+type Handler func(ctx context.Context, job *models.Job) error
+
+// permanentError 再試行しても成功しないエラー
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 再試行せずにジョブを失敗にするエラーを作成する（不正なペイロードなど）
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Queue データベースに保存したジョブを種類ごとに登録した処理で実行するキュー
+// 複数のインスタンスで実行した場合も1つのジョブは1つのワーカーのみが実行する
+// ワーカーが途中で停止した場合は再実行されるため、処理は同じジョブを複数回実行しても問題ないようにする
+type Queue struct {
+	jobRepo      interfaces.JobRepository
+	txManager    interfaces.TxManager
+	workers      int
+	pollInterval time.Duration
+	timeout      time.Duration
+	maxAttempts  int
+	retryDelay   time.Duration
+	retention    time.Duration
+	log          logger.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	started  bool
+
+	triggerCh chan struct{}
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewQueue 新しいジョブキューを作成する
+// workersが0の場合はこのインスタンスではジョブを登録するのみで実行しない
+func NewQueue(
+	jobRepo interfaces.JobRepository,
+	txManager interfaces.TxManager,
+	workers int,
+	pollInterval time.Duration,
+	timeout time.Duration,
+	maxAttempts int,
+	retryDelay time.Duration,
+	retention time.Duration,
+	log logger.Logger,
+) *Queue {
+	if workers < 0 {
+		workers = 0
+	}
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	if retryDelay <= 0 {
+		retryDelay = 10 * time.Second
+	}
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+
+	return &Queue{
+		jobRepo:      jobRepo,
+		txManager:    txManager,
+		workers:      workers,
+		pollInterval: pollInterval,
+		timeout:      timeout,
+		maxAttempts:  maxAttempts,
+		retryDelay:   retryDelay,
+		retention:    retention,
+		log:          log,
+		handlers:     make(map[string]Handler),
+		triggerCh:    make(chan struct{}, 1),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Register ジョブの種類と、その種類のジョブを実行する処理を登録する（Startの前に呼び出す）
+func (q *Queue) Register(kind string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		panic(fmt.Sprintf("jobs: %s registered after the queue started", kind))
+	}
+	q.handlers[kind] = handler
+}
+
+// Start ワーカーと、完了したジョブの削除を開始する
+func (q *Queue) Start() {
+	q.mu.Lock()
+	q.started = true
+	q.mu.Unlock()
+
+	if q.workers == 0 {
+		return
+	}
+	if len(q.handlers) == 0 {
+		q.log.Info("ジョブキュー: 処理が登録されていないため、ワーカーを起動しません")
+		return
+	}
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	q.wg.Add(1)
+	go q.purger()
+}
+
+// Stop ワーカーを停止する（実行中のジョブは終了するまで待つ）
+func (q *Queue) Stop() {
+	close(q.stopCh)
+	q.wg.Wait()
+}
+
+// Enqueue ジョブをすぐに実行するよう登録する
+// トランザクション内で呼び出した場合は、コミットされた場合のみ実行する
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload interface{}) (*models.Job, error) {
+	return q.EnqueueAt(ctx, kind, payload, time.Now())
+}
+
+// EnqueueAt ジョブをrunAtに実行するよう登録する
+func (q *Queue) EnqueueAt(ctx context.Context, kind string, payload interface{}, runAt time.Time) (*models.Job, error) {
+	return q.enqueue(ctx, kind, payload, runAt, nil)
+}
+
+// EnqueueUnique 同じキーのジョブが登録されていない場合のみ、ジョブをrunAtに実行するよう登録する
+// 登録済みの場合はErrDuplicateJobを返す（完了したジョブも保持期間の間は登録済みとして扱う）
+func (q *Queue) EnqueueUnique(ctx context.Context, kind string, payload interface{}, runAt time.Time, key string) (*models.Job, error) {
+	return q.enqueue(ctx, kind, payload, runAt, &key)
+}
+
+func (q *Queue) enqueue(ctx context.Context, kind string, payload interface{}, runAt time.Time, uniqueKey *string) (*models.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.NewJob(kind, data, q.maxAttempts, runAt)
+	job.UniqueKey = uniqueKey
+	if err := q.jobRepo.Create(ctx, job); err != nil {
+		if err.Error() == "job already exists" {
+			return nil, ErrDuplicateJob
+		}
+		return nil, err
+	}
+
+	// 次の確認を待たずにコミット後すぐに実行する
+	if !runAt.After(time.Now()) {
+		q.txManager.AfterCommit(ctx, q.trigger)
+	}
+	return job, nil
+}
+
+// Get ジョブの状態を取得する
+func (q *Queue) Get(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	return q.jobRepo.GetByID(ctx, id)
+}
+
+// trigger 次の確認を待たずに待機中のワーカーにジョブを確認させる
+func (q *Queue) trigger() {
+	select {
+	case q.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+// worker 停止されるまでジョブを取得して実行する（ジョブがない場合は一定間隔で確認する）
+func (q *Queue) worker() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	kinds := q.kinds()
+	for {
+		for !q.stopping() && q.runNext(kinds) {
+		}
+
+		select {
+		case <-ticker.C:
+		case <-q.triggerCh:
+		case <-q.stopCh:
+			return
+		}
+	}
+}
+
+// kinds このインスタンスで実行するジョブの種類を返す
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// runNext ジョブを1件取得して実行する（実行するジョブがない場合はfalse）
+func (q *Queue) runNext(kinds []string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	job, err := q.jobRepo.ClaimNext(ctx, kinds, q.timeout+leaseMargin)
+	cancel()
+	if err != nil {
+		q.log.Error("ジョブキュー: ジョブの取得に失敗しました", "error", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	q.run(job)
+	return true
+}
+
+// run ジョブを実行し、結果を保存する
+func (q *Queue) run(job *models.Job) {
+	q.mu.RLock()
+	handler := q.handlers[job.Kind]
+	q.mu.RUnlock()
+
+	cause := q.execute(handler, job)
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var err error
+	var permanent *permanentError
+	switch {
+	case cause == nil:
+		err = q.jobRepo.Complete(ctx, job.ID)
+	case errors.As(cause, &permanent) || job.Attempts >= job.MaxAttempts:
+		q.log.Error("ジョブキュー: ジョブが失敗しました",
+			"id", job.ID, "kind", job.Kind, "attempts", job.Attempts, "error", cause)
+		err = q.jobRepo.Fail(ctx, job.ID, cause.Error())
+	default:
+		backoff := q.retryDelay << (job.Attempts - 1)
+		if backoff <= 0 || backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		q.log.Warn("ジョブキュー: ジョブが失敗したため再試行します",
+			"id", job.ID, "kind", job.Kind, "attempts", job.Attempts, "retry_in", backoff, "error", cause)
+		err = q.jobRepo.Retry(ctx, job.ID, time.Now().UTC().Add(backoff), cause.Error())
+	}
+	if err != nil {
+		// 保存できない場合も取得の期限が切れた後に再実行される
+		q.log.Error("ジョブキュー: ジョブの結果の保存に失敗しました", "id", job.ID, "kind", job.Kind, "error", err)
+	}
+}
+
+// execute 実行時間の上限を設けてジョブを実行する（処理のパニックはエラーとして扱う）
+func (q *Queue) execute(handler Handler, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+
+	return handler(ctx, job)
+}
+
+// purger 停止されるまで一定間隔で保持期間を過ぎた完了・失敗したジョブを削除する
+func (q *Queue) purger() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+			deleted, err := q.jobRepo.DeleteFinishedBefore(ctx, time.Now().Add(-q.retention))
+			cancel()
+			if err != nil {
+				q.log.Error("ジョブキュー: 終了したジョブの削除に失敗しました", "error", err)
+			} else if deleted > 0 {
+				q.log.Info("ジョブキュー: 終了したジョブを削除しました", "count", deleted)
+			}
+		case <-q.stopCh:
+			return
+		}
+	}
+}
+
+func (q *Queue) stopping() bool {
+	select {
+	case <-q.stopCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,191 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduleField(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    string
+		min, max int
+		expected []int
+	}{
+		{"Any", "*", 1, 12, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}},
+		{"Single", "5", 0, 59, []int{5}},
+		{"List", "1,15", 0, 59, []int{1, 15}},
+		{"Range", "9-17", 0, 23, []int{9, 10, 11, 12, 13, 14, 15, 16, 17}},
+		{"Step", "*/10", 0, 59, []int{0, 10, 20, 30, 40, 50}},
+		{"RangeWithStep", "0-30/5", 0, 59, []int{0, 5, 10, 15, 20, 25, 30}},
+		{"StartWithStep", "5/20", 0, 59, []int{5, 25, 45}},
+		{"StepFromMin", "*/5", 1, 12, []int{1, 6, 11}},
+		{"ListOfRanges", "1-3,10-12/2", 1, 12, []int{1, 2, 3, 10, 12}},
+		{"Bounds", "0,59", 0, 59, []int{0, 59}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := parseScheduleField(tt.field, tt.min, tt.max)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, trueIndexes(values))
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"Empty", ""},
+		{"TooFewFields", "* * * *"},
+		{"TooManyFields", "* * * * * *"},
+		{"MinuteOutOfRange", "60 * * * *"},
+		{"HourOutOfRange", "* 24 * * *"},
+		{"DayZero", "* * 0 * *"},
+		{"MonthOutOfRange", "* * * 13 *"},
+		{"WeekdayOutOfRange", "* * * * 8"},
+		{"ZeroStep", "*/0 * * * *"},
+		{"NegativeStep", "*/-1 * * * *"},
+		{"NonNumericStep", "*/a * * * *"},
+		{"NonNumericValue", "a * * * *"},
+		{"NonNumericRangeEnd", "1-a * * * *"},
+		{"MissingRangeStart", "-5 * * * *"},
+		{"ReversedRange", "30-10 * * * *"},
+		{"EmptyListItem", "1,,2 * * * *"},
+		{"NeverRuns", "0 0 30 2 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			assert.Error(t, err)
+			assert.Nil(t, schedule)
+		})
+	}
+}
+
+func TestParseScheduleSundayAsSeven(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 * * 7")
+	require.NoError(t, err)
+	assert.True(t, schedule.weekdays[0])
+	assert.Equal(t, []int{0, 7}, trueIndexes(schedule.weekdays))
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2026-01-01は木曜日
+	tests := []struct {
+		name     string
+		spec     string
+		after    time.Time
+		expected time.Time
+	}{
+		{
+			name:     "EveryMinute",
+			spec:     "* * * * *",
+			after:    time.Date(2026, 1, 1, 10, 7, 30, 0, time.UTC),
+			expected: time.Date(2026, 1, 1, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			name:     "Step",
+			spec:     "*/15 * * * *",
+			after:    time.Date(2026, 1, 1, 10, 7, 30, 0, time.UTC),
+			expected: time.Date(2026, 1, 1, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "StrictlyAfter",
+			spec:     "*/15 * * * *",
+			after:    time.Date(2026, 1, 1, 10, 15, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "NextHour",
+			spec:     "5 * * * *",
+			after:    time.Date(2026, 1, 1, 10, 59, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 1, 11, 5, 0, 0, time.UTC),
+		},
+		{
+			name:     "NextDay",
+			spec:     "30 3 * * *",
+			after:    time.Date(2026, 1, 1, 3, 30, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 2, 3, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "WeekdaysSkipWeekend",
+			spec:     "0 9 * * 1-5",
+			after:    time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "SundayAsSeven",
+			spec:     "0 12 * * 7",
+			after:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 1, 4, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "DayOfMonth",
+			spec:     "30 2 1 * *",
+			after:    time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 2, 1, 2, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "DayOrWeekday",
+			spec:     "0 0 13 * 5",
+			after:    time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "MonthStep",
+			spec:     "0 0 1 */3 *",
+			after:    time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "YearRollover",
+			spec:     "0 0 1 1 *",
+			after:    time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "SkipsMonthsWithoutTheDay",
+			spec:     "0 0 31 * *",
+			after:    time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "LeapDay",
+			spec:     "0 0 29 2 *",
+			after:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "ConvertsToUTC",
+			spec:     "0 1 * * *",
+			after:    time.Date(2026, 1, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+			expected: time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(tt.after))
+		})
+	}
+}
+
+// trueIndexes trueになっている添字を昇順に返す
+func trueIndexes(values []bool) []int {
+	var indexes []int
+	for i, value := range values {
+		if value {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
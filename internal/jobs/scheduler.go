@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+// scheduledKindPrefix 定期実行するタスクのジョブの種類の接頭辞
+const scheduledKindPrefix = "scheduled:"
+
+// scheduledTask 定期実行するタスク
+type scheduledTask struct {
+	name     string
+	schedule *Schedule
+	next     time.Time
+}
+
+// scheduledPayload 定期実行するタスクのジョブのペイロード
+type scheduledPayload struct {
+	// 実行予定だった時刻
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// Scheduler cron形式の実行時刻に、タスクのジョブをキューへ登録するスケジューラー
+// 実行時刻ごとに一意のキーで登録するため、複数のインスタンスで起動しても各時刻に1回だけ実行される
+// 停止中に過ぎた実行時刻の分は実行しない
+type Scheduler struct {
+	queue *Queue
+	tasks []*scheduledTask
+	log   logger.Logger
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewScheduler 新しいスケジューラーを作成する
+func NewScheduler(queue *Queue, log logger.Logger) *Scheduler {
+	return &Scheduler{
+		queue:  queue,
+		log:    log,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Add タスクを登録する（キューのStartの前に呼び出す）
+// specはcron形式の実行時刻（UTC）で、handlerはジョブとして実行されるため失敗した場合は再試行される
+func (s *Scheduler) Add(name, spec string, handler Handler) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", name, err)
+	}
+	for _, task := range s.tasks {
+		if task.name == name {
+			return fmt.Errorf("task %s is already scheduled", name)
+		}
+	}
+
+	s.queue.Register(scheduledKindPrefix+name, handler)
+	s.tasks = append(s.tasks, &scheduledTask{name: name, schedule: schedule})
+	return nil
+}
+
+// Start タスクの定期実行を開始する
+func (s *Scheduler) Start() {
+	go s.run()
+}
+
+// Stop タスクの定期実行を停止する（登録済みのジョブはキューが実行する）
+func (s *Scheduler) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// run 停止されるまで、次の実行時刻になったタスクのジョブを登録する
+func (s *Scheduler) run() {
+	defer close(s.doneCh)
+
+	if len(s.tasks) == 0 {
+		<-s.stopCh
+		return
+	}
+
+	now := time.Now()
+	for _, task := range s.tasks {
+		task.next = task.schedule.Next(now)
+		s.log.Info("定期実行するタスクを登録しました", "task", task.name, "next", task.next)
+	}
+
+	timer := time.NewTimer(time.Until(s.nextRun()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			s.enqueueDue(time.Now())
+			timer.Reset(time.Until(s.nextRun()))
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// nextRun すべてのタスクのうち最も早い次の実行時刻を返す
+func (s *Scheduler) nextRun() time.Time {
+	next := s.tasks[0].next
+	for _, task := range s.tasks[1:] {
+		if task.next.Before(next) {
+			next = task.next
+		}
+	}
+	return next
+}
+
+// enqueueDue 実行時刻を過ぎたタスクのジョブを登録し、次の実行時刻を求める
+func (s *Scheduler) enqueueDue(now time.Time) {
+	for _, task := range s.tasks {
+		if task.next.After(now) {
+			continue
+		}
+
+		scheduledAt := task.next
+		task.next = task.schedule.Next(now)
+
+		kind := scheduledKindPrefix + task.name
+		key := kind + "@" + scheduledAt.Format(time.RFC3339)
+
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		_, err := s.queue.EnqueueUnique(ctx, kind, scheduledPayload{ScheduledAt: scheduledAt}, scheduledAt, key)
+		cancel()
+		if err != nil {
+			// 他のインスタンスが先に登録した場合
+			if errors.Is(err, ErrDuplicateJob) {
+				continue
+			}
+			s.log.Error("定期実行するタスクのジョブの登録に失敗しました", "task", task.name, "scheduled_at", scheduledAt, "error", err)
+			continue
+		}
+		s.log.Debug("定期実行するタスクのジョブを登録しました", "task", task.name, "scheduled_at", scheduledAt)
+	}
+}
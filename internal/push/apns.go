@@ -0,0 +1,189 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// APNsの送信先（本番環境と開発環境）
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+	// 認証トークンを作り直す間隔（APNsは1時間より古いトークンを受け付けず、20分より短い間隔での更新も制限している）
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// apnsInvalidTokenReasons はAPNsが端末トークンを無効と判定した場合の理由です
+var apnsInvalidTokenReasons = map[string]bool{
+	"BadDeviceToken":         true,
+	"DeviceTokenNotForTopic": true,
+	"Unregistered":           true,
+	"ExpiredToken":           true,
+}
+
+// APNsProvider はApple Push Notification service（HTTP/2 API）でiOS端末へ通知を送信する送信先です
+// App Store Connectで発行した認証キー（.p8）で署名したトークンで認証します
+type APNsProvider struct {
+	baseURL  string
+	keyID    string
+	teamID   string
+	bundleID string
+	key      *ecdsa.PrivateKey
+	client   *http.Client
+
+	mu        sync.Mutex
+	authToken string
+	issuedAt  time.Time
+}
+
+// NewAPNsProvider は認証キーのファイルを読み込み、新しいAPNsProviderインスタンスを作成します
+// sandboxがtrueの場合は開発用の環境へ送信します
+func NewAPNsProvider(keyPath, keyID, teamID, bundleID string, sandbox bool, timeout time.Duration) (interfaces.PushProvider, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("APNsの認証キーを読み込めませんでした: %w", err)
+	}
+
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("APNsの認証キーの形式が不正です: %w", err)
+	}
+	if keyID == "" || teamID == "" || bundleID == "" {
+		return nil, fmt.Errorf("APNsのキーID・チームID・バンドルIDを設定してください")
+	}
+
+	baseURL := apnsProductionURL
+	if sandbox {
+		baseURL = apnsSandboxURL
+	}
+
+	// TLSの接続ではHTTP/2が使われる
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+
+	return &APNsProvider{
+		baseURL:  baseURL,
+		keyID:    keyID,
+		teamID:   teamID,
+		bundleID: bundleID,
+		key:      key,
+		client:   &http.Client{Timeout: timeout, Transport: transport},
+	}, nil
+}
+
+// Platform は送信できる端末のプラットフォームを返します
+func (p *APNsProvider) Platform() models.DevicePlatform {
+	return models.DevicePlatformAPNs
+}
+
+// Send は端末へ通知を送信します
+func (p *APNsProvider) Send(ctx context.Context, token string, message *models.PushMessage) error {
+	authToken, err := p.token()
+	if err != nil {
+		return err
+	}
+
+	aps := map[string]any{
+		"alert": map[string]string{
+			"title": message.Title,
+			"body":  message.Body,
+		},
+		"sound": "default",
+	}
+	payload := map[string]any{"aps": aps}
+	for key, value := range message.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/3/device/"+url.PathEscape(token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", p.bundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&result)
+
+	switch {
+	case apnsInvalidTokenReasons[result.Reason]:
+		return fmt.Errorf("%w: APNsが端末トークンを無効と判定しました（%s）", interfaces.ErrInvalidPushToken, result.Reason)
+	case result.Reason == "ExpiredProviderToken":
+		// 認証トークンを作り直して再送する
+		p.mu.Lock()
+		p.authToken = ""
+		p.mu.Unlock()
+		return fmt.Errorf("APNsがステータス%dを返しました（%s）", resp.StatusCode, result.Reason)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("APNsがステータス%dを返しました（%s）", resp.StatusCode, result.Reason)
+	default:
+		return fmt.Errorf("%w: APNsがステータス%dを返しました（%s）", interfaces.ErrPushRejected, resp.StatusCode, result.Reason)
+	}
+}
+
+// Close はアイドル状態の接続を閉じます
+func (p *APNsProvider) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// token は有効な認証トークンを返します（古くなった場合は作り直します）
+func (p *APNsProvider) token() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.authToken != "" && time.Since(p.issuedAt) < apnsTokenLifetime {
+		return p.authToken, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = p.keyID
+
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		return "", err
+	}
+
+	p.authToken = signed
+	p.issuedAt = now
+	return signed, nil
+}
@@ -0,0 +1,203 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// FCM HTTP v1 APIの送信先（%sはFirebaseのプロジェクトID）
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	// アクセストークンを取得する際に要求する権限
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+	// アクセストークンの期限が切れる前に取得し直すまでの余裕
+	fcmTokenRefreshMargin = time.Minute
+	// 送信に失敗した場合にエラーとして記録する応答の最大バイト数
+	maxErrorBody = 512
+)
+
+// fcmServiceAccount はFirebaseのサービスアカウントの鍵ファイル（JSON）のうち、認証に使う項目です
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMProvider はFirebase Cloud Messaging（HTTP v1 API）でAndroid端末へ通知を送信する送信先です
+// サービスアカウントの鍵で署名したJWTをOAuth 2.0のアクセストークンに交換し、期限が切れるまで使い回します
+type FCMProvider struct {
+	account fcmServiceAccount
+	sendURL string
+	signer  *rsa.PrivateKey
+	client  *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMProvider はサービスアカウントの鍵ファイルを読み込み、新しいFCMProviderインスタンスを作成します
+func NewFCMProvider(credentialsPath string, timeout time.Duration) (interfaces.PushProvider, error) {
+	data, err := os.ReadFile(credentialsPath)
+	if err != nil {
+		return nil, fmt.Errorf("サービスアカウントの鍵ファイルを読み込めませんでした: %w", err)
+	}
+
+	var account fcmServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("サービスアカウントの鍵ファイルの形式が不正です: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, fmt.Errorf("サービスアカウントの鍵ファイルにproject_id・client_email・token_uriがありません")
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("サービスアカウントの秘密鍵を読み込めませんでした: %w", err)
+	}
+
+	return &FCMProvider{
+		account: account,
+		sendURL: fmt.Sprintf(fcmSendURL, url.PathEscape(account.ProjectID)),
+		signer:  key,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Platform は送信できる端末のプラットフォームを返します
+func (p *FCMProvider) Platform() models.DevicePlatform {
+	return models.DevicePlatformFCM
+}
+
+// Send は端末へ通知を送信します
+func (p *FCMProvider) Send(ctx context.Context, token string, message *models.PushMessage) error {
+	accessToken, err := p.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	fcmMessage := map[string]any{
+		"token": token,
+		"notification": map[string]string{
+			"title": message.Title,
+			"body":  message.Body,
+		},
+	}
+	if len(message.Data) > 0 {
+		fcmMessage["data"] = message.Data
+	}
+	body, err := json.Marshal(map[string]any{"message": fcmMessage})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.sendURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	switch {
+	case resp.StatusCode == http.StatusNotFound || bytes.Contains(respBody, []byte("UNREGISTERED")):
+		return fmt.Errorf("%w: FCMが端末トークンを登録されていないと判定しました", interfaces.ErrInvalidPushToken)
+	case resp.StatusCode == http.StatusUnauthorized:
+		// アクセストークンが失効した可能性があるため、次の送信で取得し直す
+		p.mu.Lock()
+		p.accessToken = ""
+		p.mu.Unlock()
+		return fmt.Errorf("FCMがステータス%dを返しました: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("FCMがステータス%dを返しました: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	default:
+		return fmt.Errorf("%w: FCMがステータス%dを返しました: %s", interfaces.ErrPushRejected, resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+}
+
+// Close はアイドル状態の接続を閉じます
+func (p *FCMProvider) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// token は有効なアクセストークンを返します（期限が近い場合は取得し直します）
+func (p *FCMProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Now().Add(fcmTokenRefreshMargin).Before(p.expiresAt) {
+		return p.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(p.signer)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return "", fmt.Errorf("FCMのアクセストークンの取得に失敗しました（ステータス%d）: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	p.accessToken = result.AccessToken
+	p.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
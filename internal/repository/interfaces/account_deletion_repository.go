@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// AccountDeletionRepository アカウントの削除手続きに関するデータアクセスのインターフェースを定義
+type AccountDeletionRepository interface {
+	// 削除手続きを登録する（登録済みの場合はエラー）
+	Create(ctx context.Context, deletion *models.AccountDeletion) error
+
+	// ユーザーの削除手続きを取得
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.AccountDeletion, error)
+
+	// 実行予定時刻を過ぎた削除手続きを1件取得して実行中にする（staleAfterより長く更新のない実行中の手続きも再取得する、ない場合はnil）
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.AccountDeletion, error)
+
+	// 削除手続きの状態と進捗を保存する
+	UpdateProgress(ctx context.Context, deletion *models.AccountDeletion) error
+
+	// ユーザーがアップロードしたメディアのURLをURL順に取得（プロフィール画像・投稿と編集履歴の添付メディア）
+	ListMediaURLs(ctx context.Context, userID uuid.UUID) ([]string, error)
+
+	// ユーザーのいいねを削除し、いいねした投稿のいいね数を減らす
+	DeleteLikes(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// ユーザーのフォロー・フォロワーを削除し、相手のフォロー数・フォロワー数を減らす
+	DeleteFollows(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// ユーザーが受け取った通知とユーザーの操作による通知を削除する
+	DeleteNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// ユーザーの投稿を削除し、返信先・リポスト元の投稿の返信数・リポスト数を減らす
+	DeletePosts(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// ユーザーを削除する（残りの関連データは外部キーにより削除される）
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// AccountMergeRepository アカウントの統合手続きに関するデータアクセスのインターフェースを定義
+// 各手順は1つのトランザクションで行い、途中で失敗した後に再実行できる
+type AccountMergeRepository interface {
+	// 統合手続きを登録する（同じアカウントの統合が進行中の場合はエラー）
+	Create(ctx context.Context, merge *models.AccountMerge) error
+
+	// IDによる統合手続きの取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.AccountMerge, error)
+
+	// 実行予定時刻を過ぎた統合手続きを1件取得して実行中にする（staleAfterより長く更新のない実行中の手続きも再取得する、ない場合はnil）
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.AccountMerge, error)
+
+	// 統合手続きの状態と進捗を保存する
+	UpdateProgress(ctx context.Context, merge *models.AccountMerge) error
+
+	// 統合した場合に移す件数と、重複のため削除する件数を集計する（データは変更しない）
+	Preview(ctx context.Context, sourceID, targetID uuid.UUID) (*models.AccountMergeReport, error)
+
+	// 統合元の投稿を統合先に移し、統合先の投稿数を再計算する
+	MovePosts(ctx context.Context, sourceID, targetID uuid.UUID) (int64, error)
+
+	// 統合元のフォロー・フォロワーを統合先に移す（統合先と重複する関係と統合先との関係は削除する）
+	MoveFollows(ctx context.Context, sourceID, targetID uuid.UUID) (moved, dropped int64, err error)
+
+	// 統合元のいいねを統合先に移す（統合先がいいねしている投稿へのいいねは削除する）
+	MoveLikes(ctx context.Context, sourceID, targetID uuid.UUID) (moved, dropped int64, err error)
+
+	// 統合元が受け取った通知と統合元の操作による通知を統合先に移す（統合先から統合先への通知になるものは削除する）
+	MoveNotifications(ctx context.Context, sourceID, targetID uuid.UUID) (moved, dropped int64, err error)
+
+	// 統合元のユーザー名を統合先へ転送するよう登録し、統合元のユーザーを削除する
+	// 統合元へのこれまでの転送も統合先へ付け替える
+	Redirect(ctx context.Context, sourceID uuid.UUID, sourceUsername string, targetID uuid.UUID) error
+}
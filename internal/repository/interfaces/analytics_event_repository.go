@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// AnalyticsEventRepository 分析イベントの保存に関するデータアクセスのインターフェースを定義
+type AnalyticsEventRepository interface {
+	// イベントをまとめて保存する（同じIDのイベントは再送とみなして無視する）
+	InsertBatch(ctx context.Context, events []*models.AnalyticsEvent) error
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// APIUsageRepository API利用状況に関するデータアクセスのインターフェースを定義
+type APIUsageRepository interface {
+	// 1時間ごとの利用状況をまとめて加算する
+	AddUsage(ctx context.Context, usages []*models.APIUsage) error
+
+	// ユーザーのfromからtoまでの利用状況を取得（新しい順）
+	ListByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.APIUsage, error)
+
+	// キーのfromからtoまでの利用状況を取得（新しい順）
+	ListByKey(ctx context.Context, keyID string, from, to time.Time) ([]*models.APIUsage, error)
+
+	// fromからtoまでの利用状況をトークンごとに集計して取得（リクエスト数の多い順、appが空の場合はすべてのアプリ）
+	SummarizeByKey(ctx context.Context, app string, from, to time.Time, limit int) ([]*models.APIUsageSummary, error)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// AuditLogRepository 監査ログ（管理者の操作・ログインの試行・パスワードの変更・データのエクスポート）に関するデータアクセスのインターフェースを定義
+// 監査ログは追記のみで、記録した内容は更新・削除できない
+type AuditLogRepository interface {
+	// 監査ログを記録する
+	Create(ctx context.Context, entry *models.AuditLog) error
+
+	// 条件に一致する監査ログを新しい順に取得
+	List(ctx context.Context, filter models.AuditLogFilter, offset, limit int) ([]*models.AuditLog, error)
+
+	// 条件に一致する監査ログの件数を取得
+	Count(ctx context.Context, filter models.AuditLogFilter) (int64, error)
+}
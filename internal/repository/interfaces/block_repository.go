@@ -0,0 +1,31 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// BlockRepository ブロック関連のデータアクセスのインターフェースを定義
+type BlockRepository interface {
+	// ユーザーをブロックし、同じトランザクションで双方向のフォロー関係を解除する
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+
+	// ブロックを解除する
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+
+	// blockerIDがblockedIDをブロックしているかを確認
+	IsBlocking(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error)
+
+	// 2人のユーザー間にいずれかの方向のブロックが存在するかを確認
+	IsBlockedEither(ctx context.Context, userA, userB uuid.UUID) (bool, error)
+
+	// ブロック中のユーザー一覧を取得
+	GetBlockedUsers(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+
+	// ブロックしている、またはブロックされているユーザーのID一覧を取得
+	GetBlockRelatedUserIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	// ブロック中のユーザー数を取得
+	CountBlocked(ctx context.Context, userID uuid.UUID) (int64, error)
+}
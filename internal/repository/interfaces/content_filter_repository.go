@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// ContentFilterRepository 投稿・プロフィールの審査に使う禁止語ルールに関するデータアクセスのインターフェースを定義
+type ContentFilterRepository interface {
+	// ルールを作成する（同じパターンのルールが既にある場合はエラー）
+	Create(ctx context.Context, rule *models.ContentFilterRule) error
+
+	// IDによるルールの取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ContentFilterRule, error)
+
+	// すべてのルールをパターン順に取得
+	List(ctx context.Context) ([]*models.ContentFilterRule, error)
+
+	// ルールのパターン・アクション・レーティングを更新する
+	Update(ctx context.Context, rule *models.ContentFilterRule) error
+
+	// ルールを削除する
+	Delete(ctx context.Context, id uuid.UUID) error
+}
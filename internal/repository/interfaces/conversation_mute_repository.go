@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ConversationMuteRepository 会話（スレッド）のミュートに関するデータアクセスのインターフェースを定義
+type ConversationMuteRepository interface {
+	// 会話をミュートする
+	Mute(ctx context.Context, userID, rootPostID uuid.UUID) error
+
+	// 会話のミュートを解除する
+	Unmute(ctx context.Context, userID, rootPostID uuid.UUID) error
+
+	// 会話をミュートしているかを確認
+	IsMuted(ctx context.Context, userID, rootPostID uuid.UUID) (bool, error)
+
+	// 指定したユーザーのうち会話をミュートしているユーザーのIDを取得
+	GetMutedUserIDs(ctx context.Context, rootPostID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+}
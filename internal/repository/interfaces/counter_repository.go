@@ -0,0 +1,12 @@
+package interfaces
+
+import "context"
+
+// CounterRepository 投稿・ユーザーの集計値（いいね数・フォロワー数など）の再計算に関するデータアクセスのインターフェースを定義
+type CounterRepository interface {
+	// 投稿のいいね数・返信数・リポスト数を元のテーブルから再計算し、値が変わった投稿数を返す
+	ReconcilePostCounts(ctx context.Context) (int64, error)
+
+	// ユーザーのフォロワー数・フォロー数・投稿数を元のテーブルから再計算し、値が変わったユーザー数を返す
+	ReconcileUserCounts(ctx context.Context) (int64, error)
+}
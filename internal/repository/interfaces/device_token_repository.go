@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// DeviceTokenRepository プッシュ通知の送信先の端末に関するデータアクセスのインターフェースを定義
+type DeviceTokenRepository interface {
+	// 端末トークンを登録する（同じトークンが登録済みの場合は、ユーザーとプラットフォームを置き換える）
+	Upsert(ctx context.Context, device *models.DeviceToken) error
+
+	// ユーザーの端末を登録順に取得
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.DeviceToken, error)
+
+	// ユーザーの端末トークンを削除（登録されていない場合はエラー）
+	Delete(ctx context.Context, userID uuid.UUID, token string) error
+
+	// プッシュサービスが無効と判定した端末トークンを削除（ユーザーを問わない）
+	DeleteByToken(ctx context.Context, token string) error
+}
@@ -2,30 +2,60 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/google/uuid"
 )
 
 // FollowRepository フォロー関連のデータアクセスのインターフェースを定義
+// フォロー・フォロー解除は追記のみのイベント（follow_events）として記録し、followsテーブルはその射影とする
 type FollowRepository interface {
-	// フォローする
+	// フォローする（イベントを記録し、同じトランザクションでfollowsとフォロワー数・フォロー数に反映する）
 	Follow(ctx context.Context, followerID, followeeID uuid.UUID) error
 
-	// フォロー解除する
+	// フォロー解除する（イベントを記録し、同じトランザクションでfollowsとフォロワー数・フォロー数に反映する）
 	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error
 
+	// イベントを記録だけする（followsへの反映は射影ワーカーが行う。インポートなどでまとめて記録する場合に使用）
+	AppendEvent(ctx context.Context, event *models.FollowEvent) error
+
+	// 未反映のイベントを記録順にlimit件までfollowsに反映し、反映した件数を返す
+	// 同じフォロー関係でより新しいイベントが反映済みの場合は、followsを変更せずに反映済みとする
+	ProjectPending(ctx context.Context, limit int) (int, error)
+
+	// followsをイベントから作り直し、作り直したフォロー関係の数を返す
+	// フォロワー数・フォロー数は更新しないため、続けてカウンターを再計算すること
+	RebuildProjection(ctx context.Context) (int64, error)
+
+	// 現在のフォローを開始した日時を取得（フォローしていない場合はnil）
+	GetFollowedSince(ctx context.Context, followerID, followeeID uuid.UUID) (*time.Time, error)
+
+	// 期間内（fromからtoの前日まで）の日ごとのフォロー・フォロー解除の数を取得（followeeIDがnilの場合はサービス全体）
+	GetChurn(ctx context.Context, followeeID *uuid.UUID, from, to time.Time) ([]*models.FollowChurnDay, error)
+
 	// フォロー中かどうかを確認
 	IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error)
 
-	// フォロワー一覧を取得
-	GetFollowers(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+	// 複数のユーザーについてフォロー中かどうかをまとめて確認（フォロー中のユーザーIDのみtrueとなるマップを返す）
+	IsFollowingBatch(ctx context.Context, followerID uuid.UUID, followeeIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// フォロワー一覧を取得（無効化されたアカウントと、viewerIDとブロック関係にあるユーザーは一覧・件数に含めない。
+	// viewerIDがuuid.Nilの場合はブロックによる除外を行わない。以下も同様）
+	GetFollowers(ctx context.Context, userID, viewerID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
 
 	// フォロー中のユーザー一覧を取得
-	GetFollowing(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+	GetFollowing(ctx context.Context, userID, viewerID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+
+	// userIDのフォロワーのうち、viewerIDがフォローしているユーザーの一覧を取得
+	GetMutualFollowers(ctx context.Context, userID, viewerID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+
+	// userIDのフォロワーのうち、viewerIDがフォローしているユーザーの数を取得
+	CountMutualFollowers(ctx context.Context, userID, viewerID uuid.UUID) (int64, error)
 
 	// フォロワー数を取得
-	CountFollowers(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountFollowers(ctx context.Context, userID, viewerID uuid.UUID) (int64, error)
 
 	// フォロー中のユーザー数を取得
-	CountFollowing(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountFollowing(ctx context.Context, userID, viewerID uuid.UUID) (int64, error)
 }
@@ -0,0 +1,7 @@
+package interfaces
+
+// HealthChecker データベースなどの依存先がリクエストを処理できる状態かを返すインターフェースを定義
+type HealthChecker interface {
+	// リクエストを処理できる状態かどうか
+	Ready() bool
+}
@@ -0,0 +1,35 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// JobRepository バックグラウンドジョブのキューに関するデータアクセスのインターフェースを定義
+type JobRepository interface {
+	// ジョブを登録する（トランザクション内で呼び出した場合は、コミットされたときのみ実行される）
+	// 同じUniqueKeyのジョブが登録済みの場合はエラー
+	Create(ctx context.Context, job *models.Job) error
+
+	// ジョブを取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error)
+
+	// 指定した種類のうち実行予定時刻を過ぎたジョブを1件取得して実行中にし、試行回数を増やす（ない場合はnil）
+	// 取得したジョブはleaseの間は他のワーカーから取得されず、期限を過ぎても完了しない場合は再取得される
+	ClaimNext(ctx context.Context, kinds []string, lease time.Duration) (*models.Job, error)
+
+	// 実行に成功したジョブを完了にする
+	Complete(ctx context.Context, id uuid.UUID) error
+
+	// 実行に失敗したジョブをrunAtに再試行するよう待機させる
+	Retry(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error
+
+	// 再試行しないジョブを失敗にする
+	Fail(ctx context.Context, id uuid.UUID, lastError string) error
+
+	// before以前に完了・失敗したジョブを削除し、削除した件数を返す
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
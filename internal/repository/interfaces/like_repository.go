@@ -9,15 +9,18 @@ import (
 
 // LikeRepository いいね関連のデータアクセスのインターフェースを定義
 type LikeRepository interface {
-	// 投稿にいいねをする
+	// 投稿にいいねをする（いいね数も同じトランザクションで更新する）
 	Like(ctx context.Context, like *models.Like) error
 
-	// いいねを取り消す
+	// いいねを取り消す（いいね数も同じトランザクションで更新する）
 	Unlike(ctx context.Context, userID, postID uuid.UUID) error
 
 	// いいね済みかどうかを確認
 	HasLiked(ctx context.Context, userID, postID uuid.UUID) (bool, error)
 
+	// 複数の投稿についていいね済みかどうかをまとめて確認（いいね済みの投稿IDのみtrueとなるマップを返す）
+	HasLikedBatch(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+
 	// 投稿に対するいいね一覧を取得
 	GetLikesByPostID(ctx context.Context, postID uuid.UUID, offset, limit int) ([]*models.Like, error)
 
@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// ListRepository リスト関連のデータアクセスのインターフェースを定義
+type ListRepository interface {
+	// 新しいリストを作成
+	Create(ctx context.Context, list *models.List) error
+
+	// IDによるリスト取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.List, error)
+
+	// リストの更新
+	Update(ctx context.Context, list *models.List) error
+
+	// リストの削除
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// 所有者IDによるリスト一覧取得
+	GetByOwnerID(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*models.List, error)
+
+	// 所有者IDによるリスト数のカウント
+	CountByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error)
+
+	// メンバーを追加
+	AddMember(ctx context.Context, listID, userID uuid.UUID) error
+
+	// メンバーを削除
+	RemoveMember(ctx context.Context, listID, userID uuid.UUID) error
+
+	// メンバーかどうかを確認
+	IsMember(ctx context.Context, listID, userID uuid.UUID) (bool, error)
+
+	// メンバー一覧を取得
+	GetMembers(ctx context.Context, listID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+
+	// すべてのメンバーIDを取得
+	GetAllMemberIDs(ctx context.Context, listID uuid.UUID) ([]uuid.UUID, error)
+}
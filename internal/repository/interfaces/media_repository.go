@@ -0,0 +1,35 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// MediaRepository 内容のハッシュで重複を除いたメディアの実体と参照数に関するデータアクセスのインターフェースを定義
+type MediaRepository interface {
+	// 同じハッシュのメディアがあれば参照数とuserIDのユーザーのアップロード回数を1増やして返す（ない場合はエラー）
+	Acquire(ctx context.Context, hash string, userID uuid.UUID) (*models.MediaObject, error)
+
+	// メディアを参照数1で登録し、uploaderIDのユーザーのアップロードとして記録する（同じハッシュのメディアが既にある場合はエラー）
+	Create(ctx context.Context, object *models.MediaObject, uploaderID uuid.UUID) error
+
+	// URLの一覧に該当するメディアを取得する（登録されていないURLは含まれない）
+	ListByURLs(ctx context.Context, urls []string) ([]*models.MediaObject, error)
+
+	// URLの一覧のうちuserIDのユーザーがアップロードしたメディアのURLを取得する
+	ListUploadedURLs(ctx context.Context, userID uuid.UUID, urls []string) ([]string, error)
+
+	// userIDのユーザーがアップロードしていればそのアップロードと参照数を1減らし、参照数が0でpostID以外の投稿・編集履歴・
+	// プロフィール画像・分割アップロードからも参照されていない場合はdeleteObjectでファイルを削除してから行を削除する
+	// （登録されていないURLの場合はエラー）
+	Release(ctx context.Context, url string, userID, postID uuid.UUID, deleteObject func(ctx context.Context) error) (*models.MediaRelease, error)
+
+	// before以前から投稿・編集履歴・プロフィール画像・分割アップロードのいずれからも参照されていないメディアを最大limit件取得
+	ListOrphaned(ctx context.Context, before time.Time, limit int) ([]*models.MediaObject, error)
+
+	// 参照されていないメディアをdeleteObjectでファイルを削除してから削除し、使用量に数えていたアップロードを返す（参照された場合はエラー）
+	DeleteOrphan(ctx context.Context, id uuid.UUID, before time.Time, deleteObject func(ctx context.Context) error) ([]*models.MediaUpload, error)
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/google/uuid"
@@ -18,15 +19,25 @@ type NotificationRepository interface {
 	// ユーザーIDによる通知一覧取得
 	GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Notification, error)
 
+	// ユーザーの通知を同じ種類・同じ対象・同じ日（UTC）ごとにまとめ、最新の通知の順に取得（offset・limitはグループ単位）
+	// 各グループにはアクション実行者を新しい順に最大actorPreview人まで含める
+	GetGroupedByUserID(ctx context.Context, userID uuid.UUID, offset, limit, actorPreview int) ([]*models.NotificationGroup, error)
+
 	// 通知を既読にする
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 
+	// ユーザーの通知を既読にする（他のユーザーの通知の場合は見つからないものとして扱う）
+	MarkAsReadForUser(ctx context.Context, id, userID uuid.UUID) error
+
 	// ユーザーの全通知を既読にする
 	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
 
 	// 通知の削除
 	Delete(ctx context.Context, id uuid.UUID) error
 
+	// before以前に作成された通知を最大limit件削除し、削除した件数を返す
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+
 	// ユーザーの未読通知数を取得
 	CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 
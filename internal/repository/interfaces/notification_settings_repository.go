@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// NotificationSettingsRepository 通知の種類ごとの受け取り方の設定に関するデータアクセスのインターフェースを定義
+type NotificationSettingsRepository interface {
+	// ユーザーの設定を取得する（設定していない種類はデフォルト値を返す）
+	Get(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error)
+
+	// 指定した種類の通知の有効・無効とメール通知の有効・無効を保存し、保存後の設定を返す（指定していない項目は変更しない）
+	Update(ctx context.Context, userID uuid.UUID, enabled, email map[models.NotificationType]bool) (*models.NotificationSettings, error)
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// OutboxRepository 配信待ちのイベント（アウトボックス）に関するデータアクセスのインターフェースを定義
+type OutboxRepository interface {
+	// イベントを保存する（トランザクション内で呼び出した場合は、コミットされたときのみ配信される）
+	Create(ctx context.Context, events []*models.OutboxEvent) error
+
+	// 配信予定時刻を過ぎたイベントを古い順に最大limit件取得し、試行回数を増やす
+	// 取得したイベントはleaseの間は他の配信処理から取得されない（配信処理が停止した場合はその後に再取得される）
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error)
+
+	// 配信したイベントを削除する
+	Delete(ctx context.Context, id int64) error
+
+	// 配信に失敗したイベントをnextAttemptAtに再試行するよう保存する
+	Retry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+
+	// 最大回数まで配信に失敗したイベントを失敗として保存し、配信の対象から外す
+	MarkFailed(ctx context.Context, id int64, lastError string) error
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// PostImportRepository 外部サービスのアーカイブからの投稿の取り込みに関するデータアクセスのインターフェースを定義
+type PostImportRepository interface {
+	// ユーザーがsourceから取り込み済みの投稿を取得（元の投稿のIDをキー、投稿のIDを値とするマップを返す）
+	GetImportedPostIDs(ctx context.Context, userID uuid.UUID, source string) (map[string]uuid.UUID, error)
+
+	// 投稿をまとめて作成し、取り込み元の投稿のIDを記録して作成した件数を返す（1件でも取り込み済みの場合はすべて取り消す）
+	// 返信先の返信数とユーザーの投稿数は同じトランザクションで更新する。投稿日時は投稿に設定された値を保持する
+	Import(ctx context.Context, userID uuid.UUID, imports []*models.PostImport) (int64, error)
+}
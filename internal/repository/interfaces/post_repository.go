@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
@@ -12,35 +13,102 @@ type PostRepository interface {
 	// 新しい投稿を作成
 	Create(ctx context.Context, post *models.Post) error
 	
-	// IDによる投稿取得
+	// 返信でつながった複数の投稿（スレッド）を1つのトランザクションで作成し、返信先の返信数を更新する
+	CreateThread(ctx context.Context, posts []*models.Post) error
+	
+	// IDによる投稿取得（削除済みの投稿、モデレーターにより非表示・削除された投稿と無効化されたアカウントの投稿は見つからないものとして扱う。以下の取得・一覧・件数も同様）
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Post, error)
+
+	// 削除済み・モデレーターにより非表示にされた投稿も含めてIDで投稿を取得（会話の表示で削除済みの返信先を示すため、モデレーションで対象の投稿を確認するために使う）
+	GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Post, error)
+	
+	// 複数のIDによる投稿取得（IDをキーとするマップを返し、存在しないIDは含まれない）
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Post, error)
 	
 	// 投稿の更新
 	Update(ctx context.Context, post *models.Post) error
 	
-	// 投稿の削除
+	// 投稿の削除（返信や通知から参照できるよう、行は残して削除日時を記録する）
 	Delete(ctx context.Context, id uuid.UUID) error
 	
 	// ページネーション付き投稿一覧取得
 	List(ctx context.Context, offset, limit int) ([]*models.Post, error)
 	
+	// 指定したキーワードを本文に含む投稿を除いて、ページネーション付きで投稿一覧を取得
+	// viewerIDとブロック関係にあるユーザーの投稿も除く（uuid.Nilの場合は除かない。以下も同様）
+	ListExcluding(ctx context.Context, viewerID uuid.UUID, excludedKeywords []string, offset, limit int) ([]*models.Post, error)
+	
+	// 指定期間内の投稿をエンゲージメント（いいね・リポスト・返信）と経過時間から計算したスコアの高い順に取得
+	// 指定したキーワードを本文に含む投稿は除く
+	ListPopular(ctx context.Context, viewerID uuid.UUID, window time.Duration, excludedKeywords []string, offset, limit int) ([]*models.Post, error)
+	
+	// トピックに分類された投稿を新しい順に取得（指定したキーワードを本文に含む投稿は除く）
+	ListByTopic(ctx context.Context, viewerID, topicID uuid.UUID, excludedKeywords []string, offset, limit int) ([]*models.Post, error)
+	
+	// トピックに分類された指定期間内の投稿をエンゲージメントの高い順に取得（指定したキーワードを本文に含む投稿は除く）
+	ListPopularByTopic(ctx context.Context, viewerID, topicID uuid.UUID, window time.Duration, excludedKeywords []string, offset, limit int) ([]*models.Post, error)
+	
 	// ユーザーIDによる投稿取得
 	GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Post, error)
 	
+	// 複数ユーザーの投稿を時系列順に取得（viewerIDとブロック関係にあるユーザーの投稿は除く）
+	GetByUserIDs(ctx context.Context, userIDs []uuid.UUID, viewerID uuid.UUID, offset, limit int) ([]*models.Post, error)
+	
 	// 投稿への返信を取得
-	GetReplies(ctx context.Context, postID uuid.UUID, offset, limit int) ([]*models.Post, error)
+	GetReplies(ctx context.Context, postID uuid.UUID, sort models.ReplySort, offset, limit int) ([]*models.Post, error)
+	
+	// 2人のユーザーが互いに送った返信を新しい順に取得（AからBへの返信とBからAへの返信の両方）
+	GetRepliesBetween(ctx context.Context, userA, userB uuid.UUID, offset, limit int) ([]*models.Post, error)
+
+	// 返信先をたどって会話の祖先投稿を取得（ルート投稿から順に、最大maxDepth件）
+	// 会話がつながるよう削除済み・非表示の投稿も含める（IsUnavailableで判別する）
+	GetAncestors(ctx context.Context, postID uuid.UUID, maxDepth int) ([]*models.Post, error)
+	
+	// 投稿者が自分の投稿に返信を続けたスレッドを、先頭の投稿から順に取得する（最大maxPosts件）
+	// 指定した投稿から同じ投稿者の返信元をたどって先頭を求め、先頭から同じ投稿者の最初の返信をたどる
+	GetSelfThread(ctx context.Context, postID uuid.UUID, maxPosts int) ([]*models.Post, error)
 	
 	// 投稿のリポスト（再投稿）を取得
 	GetReposts(ctx context.Context, postID uuid.UUID, offset, limit int) ([]*models.Post, error)
+
+	// 投稿をリポストしたユーザーのIDを最新のリポストの新しい順に取得（同じユーザーの複数のリポストは1件にまとめ、最新のリポストの日時を返す）
+	GetReposterIDs(ctx context.Context, postID uuid.UUID, offset, limit int) ([]uuid.UUID, map[uuid.UUID]time.Time, error)
+	
+	// ユーザーがいいねした投稿をいいねした日時の新しい順に取得
+	GetLikedByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Post, error)
+	
+	// ユーザーがいいねした投稿数のカウント（表示できない投稿は数えない）
+	CountLikedByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	
+	// 本文に検索語を含む投稿を新しい順に取得
+	Search(ctx context.Context, query string, offset, limit int) ([]*models.Post, error)
+	
+	// 指定日時より後に作成された、本文に検索語を含む投稿を新しい順に取得
+	SearchSince(ctx context.Context, query string, since time.Time, limit int) ([]*models.Post, error)
 	
 	// ユーザーIDによる投稿数のカウント
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	
+	// 複数ユーザーの投稿数のカウント
+	CountByUserIDs(ctx context.Context, userIDs []uuid.UUID, viewerID uuid.UUID) (int64, error)
+	
+	// 指定した投稿より新しい、複数ユーザーの投稿数のカウント
+	CountNewerByUserIDs(ctx context.Context, userIDs []uuid.UUID, sinceID uuid.UUID) (int64, error)
+	
+	// sinceより後にユーザーが投稿者の投稿へいいね・返信・リポストした回数を投稿者ごとに取得（やり取りのない投稿者は含まない）
+	GetInteractionCounts(ctx context.Context, userID uuid.UUID, authorIDs []uuid.UUID, since time.Time) (map[uuid.UUID]int, error)
+	
 	// 投稿への返信数のカウント
 	CountReplies(ctx context.Context, postID uuid.UUID) (int64, error)
 	
+	// 2人のユーザーが互いに送った返信数のカウント
+	CountRepliesBetween(ctx context.Context, userA, userB uuid.UUID) (int64, error)
+
 	// 投稿のリポスト数のカウント
 	CountReposts(ctx context.Context, postID uuid.UUID) (int64, error)
+
+	// 投稿をリポストしたユーザー数のカウント
+	CountReposters(ctx context.Context, postID uuid.UUID) (int64, error)
 	
 	// いいね数を増加
 	IncrementLikeCount(ctx context.Context, postID uuid.UUID) error
@@ -59,4 +127,38 @@ type PostRepository interface {
 	
 	// 返信数を減少
 	DecrementReplyCount(ctx context.Context, postID uuid.UUID) error
+	
+	// 外部への共有数を増加し、更新後の共有数を返す（共有が無効な投稿はエラー）
+	IncrementShareCount(ctx context.Context, postID uuid.UUID) (int64, error)
+	
+	// 投稿の共有の有効・無効を設定
+	SetSharingEnabled(ctx context.Context, postID uuid.UUID, enabled bool) error
+	
+	// 投稿から添付メディアを取り除き、編集履歴を記録して更新後の投稿を返す
+	// deleteObjectsはトランザクション内で呼び出され、エラーを返した場合は投稿を変更しない
+	RemoveMedia(ctx context.Context, postID, editorID uuid.UUID, mediaURLs []string, deleteObjects func(ctx context.Context) error) (*models.Post, error)
+	
+	// 投稿の編集履歴を新しい順に取得
+	GetEdits(ctx context.Context, postID uuid.UUID) ([]*models.PostEdit, error)
+
+	// モデレーターによる投稿の表示状態（visible・hidden・removed）と理由を設定（削除済みの投稿は見つからないものとして扱う）
+	SetModerationStatus(ctx context.Context, postID, moderatorID uuid.UUID, status models.ModerationStatus, reason string) error
+
+	// 投稿の注意書き（コンテンツ警告）を設定（空文字で解除）
+	SetContentWarning(ctx context.Context, postID uuid.UUID, warning string) error
+
+	// 投稿への返信のロックを設定
+	SetRepliesLocked(ctx context.Context, postID uuid.UUID, locked bool) error
+
+	// モデレーターにより非表示・削除された投稿を対応した日時の新しい順に取得（statusが空の場合は両方）
+	ListModerated(ctx context.Context, status models.ModerationStatus, offset, limit int) ([]*models.Post, error)
+
+	// モデレーターにより非表示・削除された投稿数のカウント（statusが空の場合は両方）
+	CountModerated(ctx context.Context, status models.ModerationStatus) (int64, error)
+
+	// sinceより後に作成された投稿の言語の設定状況と、添付メディアの拡張子ごとの代替テキストの設定状況を集計
+	GetAccessibilityStats(ctx context.Context, since time.Time) (*models.AccessibilityStats, error)
+
+	// 有効期限を過ぎた投稿を期限の古い順にlimit件まで完全に削除し、削除した投稿を返す
+	PurgeExpired(ctx context.Context, limit int) ([]*models.Post, error)
 } 
\ No newline at end of file
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// PostViewRepository 投稿の閲覧数に関するデータアクセスのインターフェースを定義
+type PostViewRepository interface {
+	// 投稿ごとの閲覧数をまとめて加算する（dayは日別集計の日付）
+	AddViews(ctx context.Context, day time.Time, counts map[uuid.UUID]int64) error
+
+	// 投稿の日別閲覧数を取得（fromからtoまでの日付、古い順）
+	GetDailyViews(ctx context.Context, postID uuid.UUID, from, to time.Time) ([]*models.PostDailyViews, error)
+}
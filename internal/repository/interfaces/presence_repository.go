@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PresenceRepository ユーザーの最終ログイン日時に関するデータアクセスのインターフェースを定義
+type PresenceRepository interface {
+	// ユーザーの最終ログイン日時を記録する（記録済みの日時より古い場合は変更しない）
+	UpdateLastSeen(ctx context.Context, userIDs []uuid.UUID, seenAt time.Time) error
+
+	// ユーザーの最終ログイン日時を取得（記録がない場合はnil）
+	GetLastSeen(ctx context.Context, userID uuid.UUID) (*time.Time, error)
+}
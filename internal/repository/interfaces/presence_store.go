@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PresenceStore 複数のインスタンスで共有するユーザーのオンライン状態のインターフェースを定義
+// インスタンスごとに記録し、いずれかのインスタンスで有効期限内の記録があるユーザーをオンラインとする
+type PresenceStore interface {
+	// インスタンスに接続中のユーザーを記録し、ttlの間オンラインとする（接続中は定期的に呼び出して延長する）
+	Refresh(ctx context.Context, instanceID uuid.UUID, userIDs []uuid.UUID, ttl time.Duration) error
+
+	// インスタンスへの接続がなくなったユーザーの記録を削除する
+	Remove(ctx context.Context, instanceID uuid.UUID, userID uuid.UUID) error
+
+	// いずれかのインスタンスに接続中かを確認
+	IsOnline(ctx context.Context, userID uuid.UUID) (bool, error)
+}
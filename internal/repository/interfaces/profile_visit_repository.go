@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// ProfileVisitRepository プロフィールの訪問履歴に関するデータアクセスのインターフェースを定義
+type ProfileVisitRepository interface {
+	// 訪問を記録する（両方のユーザーがプロフィール訪問者の表示を有効にしている場合のみ記録し、記録したかどうかを返す）
+	RecordVisit(ctx context.Context, profileUserID, visitorID uuid.UUID, visitedAt time.Time) (bool, error)
+
+	// 指定日時より後の訪問者を新しい順に取得（訪問者の表示を無効にしたユーザーは含めない）
+	GetVisitors(ctx context.Context, profileUserID uuid.UUID, since time.Time, limit int) ([]*models.ProfileVisit, error)
+
+	// ユーザーが訪問した・訪問された履歴をすべて削除する
+	DeleteByUser(ctx context.Context, userID uuid.UUID) error
+
+	// 指定日時以前の訪問履歴を削除する
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// プロフィールごとに新しい順でkeep件を超える訪問履歴を削除する
+	Trim(ctx context.Context, keep int) (int64, error)
+}
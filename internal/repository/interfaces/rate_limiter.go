@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter クライアントごとのリクエスト数を数え、レート制限を判定するインターフェースを定義
+type RateLimiter interface {
+	// リクエストを1件記録し、許可するかどうか・残りのリクエスト数・制限が解除される時刻を返す
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, remaining int, resetAt time.Time, err error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// ReactionRepository 投稿への絵文字リアクション関連のデータアクセスのインターフェースを定義
+type ReactionRepository interface {
+	// 投稿にリアクションをする（同じ絵文字でリアクション済みの場合はエラー）
+	React(ctx context.Context, reaction *models.Reaction) error
+
+	// リアクションを取り消す
+	Unreact(ctx context.Context, userID, postID uuid.UUID, emoji string) error
+
+	// 複数の投稿について絵文字ごとのリアクション数をまとめて取得（リアクションのない投稿はマップに含まれない）
+	GetSummaries(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]*models.ReactionCount, error)
+
+	// 複数の投稿についてユーザーがリアクションした絵文字をまとめて取得（リアクションのない投稿はマップに含まれない）
+	GetUserReactions(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+}
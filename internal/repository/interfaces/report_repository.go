@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// ReportRepository 投稿・ユーザーの通報に関するデータアクセスのインターフェースを定義
+type ReportRepository interface {
+	// 通報を作成する（同じユーザーが同じ対象を未対応のまま重複して通報した場合はエラー）
+	Create(ctx context.Context, report *models.Report) error
+
+	// IDによる通報の取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Report, error)
+
+	// 状態ごとの通報を古い順に取得（対応を待っている時間が長いものから処理するため）
+	ListByStatus(ctx context.Context, status models.ReportStatus, offset, limit int) ([]*models.Report, error)
+
+	// 状態ごとの通報の件数を取得
+	CountByStatus(ctx context.Context, status models.ReportStatus) (int64, error)
+
+	// 未対応の通報を対応済み（resolved）または却下（dismissed）にする
+	Resolve(ctx context.Context, id, resolverID uuid.UUID, status models.ReportStatus, note string) error
+}
@@ -0,0 +1,45 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// SearchRepository 検索履歴と保存した検索に関するデータアクセスのインターフェースを定義
+type SearchRepository interface {
+	// 検索を履歴に記録する（同じ検索語は日時を更新し、keepを超えた古い履歴は削除する）
+	RecordSearch(ctx context.Context, userID uuid.UUID, query string, keep int) error
+
+	// 最近の検索を新しい順に取得
+	GetSearchHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*models.SearchHistoryEntry, error)
+
+	// 検索履歴を1件削除する
+	DeleteSearchHistoryEntry(ctx context.Context, userID, entryID uuid.UUID) error
+
+	// 検索履歴をすべて削除する
+	ClearSearchHistory(ctx context.Context, userID uuid.UUID) error
+
+	// 検索を保存する
+	CreateSavedSearch(ctx context.Context, search *models.SavedSearch) error
+
+	// 保存した検索を取得
+	GetSavedSearches(ctx context.Context, userID uuid.UUID) ([]*models.SavedSearch, error)
+
+	// 保存した検索の件数を取得
+	CountSavedSearches(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// 保存した検索の通知設定を変更する
+	UpdateSavedSearchNotify(ctx context.Context, userID, searchID uuid.UUID, notify bool) (*models.SavedSearch, error)
+
+	// 保存した検索を削除する
+	DeleteSavedSearch(ctx context.Context, userID, searchID uuid.UUID) error
+
+	// 通知が有効な保存した検索を最終確認日時の古い順に取得
+	GetNotifiableSavedSearches(ctx context.Context, limit int) ([]*models.SavedSearch, error)
+
+	// 保存した検索の最終確認日時を更新する
+	MarkSavedSearchChecked(ctx context.Context, searchID uuid.UUID, checkedAt time.Time) error
+}
@@ -0,0 +1,35 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// SettingsRepository ユーザー設定に関するデータアクセスのインターフェースを定義
+type SettingsRepository interface {
+	// ユーザーの設定を取得する（保存されていない場合はデフォルト値を返す）
+	Get(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error)
+
+	// 探索タイムラインから除外するキーワードを保存する
+	UpdateExploreExcludedKeywords(ctx context.Context, userID uuid.UUID, keywords []string) (*models.UserSettings, error)
+
+	// プロフィール訪問者の表示の有効・無効を保存する
+	UpdateProfileVisitorsEnabled(ctx context.Context, userID uuid.UUID, enabled bool) (*models.UserSettings, error)
+
+	// クライアントの種類ごとの通知の表示形式を保存する（他のクライアントの設定は変更しない）
+	UpdateNotificationGrouping(ctx context.Context, userID uuid.UUID, clientType string, grouping models.NotificationGrouping) (*models.UserSettings, error)
+
+	// プロフィールのカスタマイズ（アクセントカラー・表示するハッシュタグ）を保存する
+	UpdateProfileTheme(ctx context.Context, userID uuid.UUID, theme models.ProfileTheme) (*models.UserSettings, error)
+
+	// オンライン状態と最終ログイン日時を他のユーザーに表示するかを保存する
+	UpdatePresenceVisible(ctx context.Context, userID uuid.UUID, visible bool) (*models.UserSettings, error)
+
+	// いいねした投稿の一覧を他のユーザーに公開するかを保存する
+	UpdateLikesVisible(ctx context.Context, userID uuid.UUID, visible bool) (*models.UserSettings, error)
+
+	// ホームタイムラインの既定の並び順を保存する
+	UpdateHomeTimelineOrder(ctx context.Context, userID uuid.UUID, order models.TimelineOrder) (*models.UserSettings, error)
+}
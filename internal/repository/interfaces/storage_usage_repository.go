@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// StorageUsageRepository ユーザーごとのメディアの使用量に関するデータアクセスのインターフェースを定義
+type StorageUsageRepository interface {
+	// ユーザーの使用量を取得（まだアップロードしていない場合は0）
+	Get(ctx context.Context, userID uuid.UUID) (*models.StorageUsage, error)
+
+	// 使用量にsizeバイトのファイル1件を加える。quotaBytesを超える場合は加えずにfalseを返す（0以下の場合は上限なし）
+	Reserve(ctx context.Context, userID uuid.UUID, size, quotaBytes int64) (bool, error)
+
+	// 使用量からsizeバイトのファイル1件を差し引く
+	Refund(ctx context.Context, userID uuid.UUID, size int64) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// SupporterRepository サポーター（寄付者）の状態に関するデータアクセスのインターフェースを定義
+type SupporterRepository interface {
+	// Webhookイベントをユーザーのサポーター状態に反映する
+	// 処理済みのイベントの場合は何もせずfalseを返す
+	ApplyEvent(ctx context.Context, event *models.SupporterEvent) (bool, error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// SyncEventRepository クライアントの差分同期に使う変更のイベントに関するデータアクセスのインターフェースを定義
+// イベントは投稿・フォロー・通知のリポジトリが変更と同じクエリで記録する
+type SyncEventRepository interface {
+	// 保持しているイベントの最小のシーケンス番号と、before以前に発生したイベントの最大のシーケンス番号を取得（イベントがない場合は0）
+	SeqRange(ctx context.Context, before time.Time) (first, last int64, err error)
+
+	// afterより後、upTo以前のイベントのうち、ユーザーに関係するものを古い順に最大limit件取得
+	// （自分とフォロー中のユーザーの投稿、自分がした・されたフォロー、自分の通知）
+	ListForUser(ctx context.Context, userID uuid.UUID, after, upTo int64, limit int) ([]*models.SyncEvent, error)
+
+	// before以前に発生したイベントを最大limit件削除し、削除した件数を返す（最新のイベントは残す）
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ThreadCache 投稿者が自分の投稿に返信を続けたスレッド（投稿IDの一覧、古い順）のキャッシュのインターフェースを定義
+// スレッドのどの投稿のIDからでも取得できるよう、スレッドのすべての投稿のIDをキーとして保存する
+type ThreadCache interface {
+	// 投稿を含むスレッドの投稿IDを古い順に取得する（キャッシュされていない場合はnil）
+	Get(ctx context.Context, postID uuid.UUID) ([]uuid.UUID, error)
+
+	// スレッドの投稿ID（古い順）を保存する
+	Set(ctx context.Context, postIDs []uuid.UUID) error
+
+	// 投稿を含むスレッドのキャッシュを削除する（スレッドのすべての投稿のキーを削除する）
+	Invalidate(ctx context.Context, postID uuid.UUID) error
+}
@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TimelineCache ユーザーごとのホームタイムライン（投稿IDの一覧、新しい順）のキャッシュのインターフェースを定義
+type TimelineCache interface {
+	// タイムラインがキャッシュされているかを確認
+	Exists(ctx context.Context, userID uuid.UUID) (bool, error)
+
+	// タイムラインを投稿IDの一覧（新しい順）で置き換える
+	Replace(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) error
+
+	// キャッシュされているユーザーのタイムラインの先頭に投稿を追加する（キャッシュされていないユーザーは無視する）
+	Push(ctx context.Context, userIDs []uuid.UUID, postID uuid.UUID) error
+
+	// キャッシュされているすべてのタイムラインの先頭に投稿を追加する（システムアカウントのお知らせの配信に使う）
+	PushAll(ctx context.Context, postID uuid.UUID) error
+
+	// タイムラインの投稿IDを新しい順に取得し、キャッシュされている件数も返す
+	Range(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, int64, error)
+
+	// タイムラインのキャッシュを削除する
+	Invalidate(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// TopicRepository 探索タブのトピックと投稿のトピックへの分類に関するデータアクセスのインターフェースを定義
+type TopicRepository interface {
+	// トピックを作成する（同じスラッグのトピックが既にある場合はエラー）
+	Create(ctx context.Context, topic *models.Topic) error
+
+	// IDによるトピックの取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Topic, error)
+
+	// スラッグによるトピックの取得
+	GetBySlug(ctx context.Context, slug string) (*models.Topic, error)
+
+	// すべてのトピックを名前順に取得
+	List(ctx context.Context) ([]*models.Topic, error)
+
+	// トピックのスラッグ・名前・説明・ハッシュタグを更新する
+	Update(ctx context.Context, topic *models.Topic) error
+
+	// トピックを削除する（投稿の分類も削除される）
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// 投稿をトピックに分類する
+	// manualSlugsのトピックは投稿者の指定として、hashtagsのいずれかを持つトピックはハッシュタグからの分類として記録する
+	TagPost(ctx context.Context, postID uuid.UUID, manualSlugs []string, hashtags []string) error
+
+	// 投稿が分類されたトピックを名前順に取得
+	GetByPostID(ctx context.Context, postID uuid.UUID) ([]*models.Topic, error)
+}
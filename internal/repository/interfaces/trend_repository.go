@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// TrendRepository ハッシュタグのトレンドに関するデータアクセスのインターフェースを定義
+type TrendRepository interface {
+	// hourから1時間の間に作成された表示可能な投稿のハッシュタグを集計し直し、集計したハッシュタグの数を返す
+	AggregateHour(ctx context.Context, hour time.Time) (int64, error)
+
+	// since以降によく使われたハッシュタグを、投稿者の数・投稿数の多い順に最大limit件取得
+	ListTop(ctx context.Context, since time.Time, limit int) ([]*models.Trend, error)
+
+	// before以前の集計を削除し、削除した件数を返す
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
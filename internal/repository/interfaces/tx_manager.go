@@ -0,0 +1,21 @@
+package interfaces
+
+import "context"
+
+// TxManager 複数のリポジトリにまたがる処理を1つのトランザクションで実行するためのインターフェースを定義
+// トランザクションはコンテキストで受け渡され、対応するリポジトリはコンテキストにトランザクションがあればそれを使用する
+type TxManager interface {
+	// トランザクション内でfnを実行する。fnがエラーを返した場合はロールバックし、それ以外はコミットする
+	// ctxが既にトランザクション内の場合は、そのトランザクションに参加する
+	// 一時的なエラー（シリアライズ失敗など）で失敗した場合は、トランザクション全体を最初からやり直すことがある
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// トランザクションのコミット後にfnを実行するよう登録する（ロールバックされた場合は実行しない）
+	// ctxがトランザクション内でない場合はすぐに実行する
+	AfterCommit(ctx context.Context, fn func())
+
+	// トランザクション内でfnを実行し、fnの結果にかかわらずロールバックする（管理者向けの一括操作のドライラン用）
+	// 実際に実行する場合と同じクエリで対象の件数を数えられ、fnがAfterCommitで登録した処理（ファイルの削除など）は実行しない
+	// ctxが既にトランザクション内の場合は、セーブポイントまでロールバックする
+	DryRun(ctx context.Context, fn func(ctx context.Context) error) error
+}
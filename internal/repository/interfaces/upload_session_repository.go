@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// UploadSessionRepository 分割アップロードのセッションに関するデータアクセスのインターフェースを定義
+type UploadSessionRepository interface {
+	// セッションを作成する
+	Create(ctx context.Context, session *models.UploadSession) error
+
+	// IDによるセッションの取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.UploadSession, error)
+
+	// パートを受信済みとして記録する（同じパートを再送した場合は変更しない）
+	AddPart(ctx context.Context, id uuid.UUID, index int) (*models.UploadSession, error)
+
+	// 結合したファイルの保存先を記録してセッションを完了する
+	Complete(ctx context.Context, id uuid.UUID, url string) (*models.UploadSession, error)
+
+	// 期限を過ぎたセッションを古い順にlimit件まで取得
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*models.UploadSession, error)
+
+	// セッションを削除する
+	Delete(ctx context.Context, id uuid.UUID) error
+}
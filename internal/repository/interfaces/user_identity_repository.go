@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+)
+
+// UserIdentityRepository 外部のOpenIDプロバイダーのアカウントとの紐付けに関するデータアクセスのインターフェースを定義
+type UserIdentityRepository interface {
+	// 発行者とsubjectによる紐付けの取得
+	GetByIssuerAndSubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error)
+
+	// 紐付けを作成する（すでに紐付けられている場合はエラー）
+	Create(ctx context.Context, identity *models.UserIdentity) error
+
+	// ログイン日時とプロバイダーのメールアドレスを更新する
+	RecordLogin(ctx context.Context, issuer, subject, email string) error
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/google/uuid"
@@ -12,13 +13,19 @@ type UserRepository interface {
 	// 新しいユーザーを作成
 	Create(ctx context.Context, user *models.User) error
 
-	// IDによるユーザー取得
+	// IDによるユーザー取得（無効化されたアカウントは見つからないものとして扱う。以下の取得・一覧も同様）
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 
+	// IDによるユーザー取得（無効化・削除手続き中のアカウントも返す）
+	GetByIDIncludingInactive(ctx context.Context, id uuid.UUID) (*models.User, error)
+
+	// 複数のIDによるユーザー取得（IDをキーとするマップを返し、存在しないIDは含まれない）
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error)
+
 	// ユーザー名によるユーザー取得
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 
-	// メールアドレスによるユーザー取得
+	// メールアドレスによるユーザー取得（ログイン時に再開できるよう、無効化されたアカウントも返す）
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 
 	// ユーザー情報の更新
@@ -33,9 +40,15 @@ type UserRepository interface {
 	// 名前またはユーザー名による検索
 	Search(ctx context.Context, query string, offset, limit int) ([]*models.User, error)
 
-	// ユーザー名が利用可能か確認
+	// 統合により転送されているユーザー名の転送先のユーザーを取得
+	GetByRedirectedUsername(ctx context.Context, username string) (*models.User, error)
+
+	// ユーザー名が利用可能か確認（統合により転送されているユーザー名は利用できない）
 	IsUsernameAvailable(ctx context.Context, username string) (bool, error)
 
+	// システムアカウントのIDを作成順に取得
+	GetSystemUserIDs(ctx context.Context) ([]uuid.UUID, error)
+
 	// メールアドレスが利用可能か確認
 	IsEmailAvailable(ctx context.Context, email string) (bool, error)
 
@@ -47,4 +60,60 @@ type UserRepository interface {
 
 	// バナー画像URLの更新
 	UpdateBanner(ctx context.Context, userID uuid.UUID, bannerURL string) error
+
+	// アカウントを無効化し、無効化した日時を記録する
+	Deactivate(ctx context.Context, userID uuid.UUID) error
+
+	// 無効化されたアカウントを再開する
+	Reactivate(ctx context.Context, userID uuid.UUID) error
+
+	// ユーザーの権限を更新する
+	UpdateRole(ctx context.Context, userID uuid.UUID, role models.UserRole) error
+
+	// フォロワーの多いユーザーを取得（システムアカウントとexcludeIDsのユーザーを除く）
+	ListPopular(ctx context.Context, excludeIDs []uuid.UUID, limit int) ([]*models.User, error)
+
+	// IdPからの無効化によりアカウントを停止する（本人のログインでは再開できない）
+	Suspend(ctx context.Context, userID uuid.UUID) error
+
+	// IdPから停止されたアカウントを有効に戻す
+	Unsuspend(ctx context.Context, userID uuid.UUID) error
+
+	// 管理者がアカウントを停止する（IdPからの停止とは別に管理し、SCIMでは解除できない）
+	Ban(ctx context.Context, userID uuid.UUID) error
+
+	// 管理者が停止したアカウントを有効に戻す
+	Unban(ctx context.Context, userID uuid.UUID) error
+
+	// IdPが管理できるユーザー（システムアカウントと削除手続き中・統合中を除く、停止中を含む）を作成順に取得
+	// usernameを指定した場合は大文字・小文字を区別せずに一致するユーザーのみ返す
+	ListProvisioned(ctx context.Context, username string, offset, limit int) ([]*models.User, error)
+
+	// IdPが管理できるユーザーの数を取得
+	CountProvisioned(ctx context.Context, username string) (int64, error)
+
+	// アカウントを削除手続き中にする（削除が完了するまですべてのエンドポイントから隠す）
+	MarkForDeletion(ctx context.Context, userID uuid.UUID) error
+
+	// 有効なアカウントを統合中にする（統合が完了するまですべてのエンドポイントから隠す）
+	MarkForMerge(ctx context.Context, userID uuid.UUID) error
+
+	// 管理者向けにすべての状態のユーザーを新しい順に取得
+	// queryを指定した場合はユーザー名・名前・メールアドレスの部分一致、statusを指定した場合はその状態のユーザーのみ返す
+	ListForAdmin(ctx context.Context, query string, status models.UserStatus, offset, limit int) ([]*models.User, error)
+
+	// 管理者向けの一覧の件数を取得
+	CountForAdmin(ctx context.Context, query string, status models.UserStatus) (int64, error)
+
+	// 認証バッジの有無を更新する
+	SetVerified(ctx context.Context, userID uuid.UUID, verified bool) error
+
+	// 年齢確認の結果として生年月日と国コードを登録する（birthDateがnilの場合は年齢確認を取り消す。countryCodeが空の場合は国コードを変更しない）
+	SetAgeVerification(ctx context.Context, userID uuid.UUID, birthDate *time.Time, countryCode string) error
+
+	// 次のログインでパスワードの再設定を求めるかどうかを更新する
+	SetPasswordResetRequired(ctx context.Context, userID uuid.UUID, required bool) error
+
+	// パスワード（ハッシュ）を更新し、パスワードの再設定の要求を解除する
+	UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error
 }
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// UserStatsRepository ユーザーの活動記録とコホート統計に関するデータアクセスのインターフェースを定義
+type UserStatsRepository interface {
+	// ユーザーがdayに活動したことを記録する（同じ日の重複は無視）
+	RecordActivity(ctx context.Context, userID uuid.UUID, day time.Time) error
+
+	// fromからtoまでに登録したユーザーのコホート統計を集計して保存する（todayより前の日の活動のみ数える）
+	RollupCohorts(ctx context.Context, from, to, today time.Time) (int64, error)
+
+	// fromからtoまでのコホート統計を取得（古い順）
+	GetCohorts(ctx context.Context, from, to time.Time) ([]*models.CohortStats, error)
+
+	// 公開用のインスタンス全体の統計を集計（有効なユーザー数、公開中の投稿数、activeSince以降に活動したユーザー数）
+	CountInstanceStats(ctx context.Context, activeSince time.Time) (*models.InstanceStats, error)
+}
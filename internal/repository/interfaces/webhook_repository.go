@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// WebhookRepository ユーザーの個人用Webhookに関するデータアクセスのインターフェースを定義
+type WebhookRepository interface {
+	// Webhookを登録する
+	Create(ctx context.Context, webhook *models.UserWebhook) error
+
+	// IDによるWebhookの取得
+	GetByID(ctx context.Context, id uuid.UUID) (*models.UserWebhook, error)
+
+	// ユーザーのWebhookを登録順に取得
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserWebhook, error)
+
+	// ユーザーのWebhookの数を取得
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// ユーザーの有効なWebhookのうち、イベントを受け取るものを取得
+	ListActiveByEvent(ctx context.Context, userID uuid.UUID, event models.WebhookEvent) ([]*models.UserWebhook, error)
+
+	// WebhookのURL・イベント・有効状態・失敗回数を更新する
+	Update(ctx context.Context, webhook *models.UserWebhook) error
+
+	// Webhookの署名用シークレットを変更する
+	UpdateSecret(ctx context.Context, id uuid.UUID, secret string) error
+
+	// 送信結果を記録する（失敗した場合は失敗回数を1増やし、maxFailuresに達した場合は無効にする）
+	// 記録後にWebhookが有効かを返す
+	RecordDelivery(ctx context.Context, id uuid.UUID, statusCode *int, succeeded bool, maxFailures int) (bool, error)
+
+	// Webhookを削除する
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,305 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const accountDeletionColumns = `user_id, status, step, media_total, media_deleted, attempts, last_error,
+			next_attempt_at, requested_at, started_at, completed_at, updated_at`
+
+type accountDeletionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAccountDeletionRepository creates a new PostgreSQL implementation of AccountDeletionRepository
+func NewAccountDeletionRepository(db *pgxpool.Pool) interfaces.AccountDeletionRepository {
+	return &accountDeletionRepository{db: db}
+}
+
+func (r *accountDeletionRepository) Create(ctx context.Context, deletion *models.AccountDeletion) error {
+	// 失敗して止まった手続きのみ最初からやり直せる
+	query := `
+		INSERT INTO account_deletions (user_id, status, step, next_attempt_at, requested_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			step = EXCLUDED.step,
+			media_total = 0,
+			media_deleted = 0,
+			attempts = 0,
+			last_error = NULL,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			requested_at = EXCLUDED.requested_at,
+			started_at = NULL,
+			completed_at = NULL,
+			updated_at = EXCLUDED.updated_at
+		WHERE account_deletions.status = 'failed'
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query,
+		deletion.UserID,
+		deletion.Status,
+		deletion.Step,
+		deletion.NextAttemptAt,
+		deletion.RequestedAt,
+		deletion.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("account deletion already scheduled")
+	}
+
+	return nil
+}
+
+func (r *accountDeletionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.AccountDeletion, error) {
+	query := `
+		SELECT ` + accountDeletionColumns + `
+		FROM account_deletions
+		WHERE user_id = $1
+	`
+
+	deletion, err := scanAccountDeletion(conn(ctx, r.db).QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("account deletion not found")
+		}
+		return nil, err
+	}
+
+	return deletion, nil
+}
+
+func (r *accountDeletionRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.AccountDeletion, error) {
+	// 他のワーカーが取得中の手続きは飛ばす
+	query := `
+		UPDATE account_deletions
+		SET status = 'running',
+			attempts = attempts + 1,
+			started_at = COALESCE(started_at, NOW()),
+			updated_at = NOW()
+		WHERE user_id = (
+			SELECT user_id
+			FROM account_deletions
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+				OR (status = 'running' AND updated_at < $1)
+			ORDER BY next_attempt_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + accountDeletionColumns
+
+	deletion, err := scanAccountDeletion(conn(ctx, r.db).QueryRow(ctx, query, time.Now().Add(-staleAfter)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return deletion, nil
+}
+
+func (r *accountDeletionRepository) UpdateProgress(ctx context.Context, deletion *models.AccountDeletion) error {
+	query := `
+		UPDATE account_deletions
+		SET status = $2,
+			step = $3,
+			media_total = $4,
+			media_deleted = $5,
+			attempts = $6,
+			last_error = $7,
+			next_attempt_at = $8,
+			completed_at = $9,
+			updated_at = NOW()
+		WHERE user_id = $1
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		deletion.UserID,
+		deletion.Status,
+		deletion.Step,
+		deletion.MediaTotal,
+		deletion.MediaDeleted,
+		deletion.Attempts,
+		deletion.LastError,
+		deletion.NextAttemptAt,
+		deletion.CompletedAt,
+	).Scan(&deletion.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("account deletion not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *accountDeletionRepository) ListMediaURLs(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT profile_image FROM users
+		WHERE id = $1 AND COALESCE(profile_image, '') <> ''
+		UNION
+		SELECT jsonb_array_elements_text(media_urls) FROM posts
+		WHERE user_id = $1 AND jsonb_typeof(media_urls) = 'array'
+		UNION
+		SELECT jsonb_array_elements_text(e.previous_media_urls)
+		FROM post_edits e
+		JOIN posts p ON p.id = e.post_id
+		WHERE p.user_id = $1 AND jsonb_typeof(e.previous_media_urls) = 'array'
+		ORDER BY 1
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+func (r *accountDeletionRepository) DeleteLikes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.execDeletion(ctx, userID,
+		`UPDATE posts p
+		SET like_count = GREATEST(p.like_count - 1, 0)
+		FROM likes l
+		WHERE l.post_id = p.id AND l.user_id = $1`,
+		`DELETE FROM likes WHERE user_id = $1`,
+	)
+}
+
+func (r *accountDeletionRepository) DeleteFollows(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.execDeletion(ctx, userID,
+		`UPDATE users u
+		SET follower_count = GREATEST(u.follower_count - 1, 0)
+		FROM follows f
+		WHERE f.followee_id = u.id AND f.follower_id = $1`,
+		`UPDATE users u
+		SET following_count = GREATEST(u.following_count - 1, 0)
+		FROM follows f
+		WHERE f.follower_id = u.id AND f.followee_id = $1`,
+		// イベントも削除し、followsをイベントから作り直したときに復元されないようにする
+		`DELETE FROM follow_events WHERE follower_id = $1 OR followee_id = $1`,
+		`DELETE FROM follows WHERE follower_id = $1 OR followee_id = $1`,
+	)
+}
+
+func (r *accountDeletionRepository) DeleteNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.execDeletion(ctx, userID,
+		`DELETE FROM notifications WHERE user_id = $1 OR actor_id = $1`,
+	)
+}
+
+func (r *accountDeletionRepository) DeletePosts(ctx context.Context, userID uuid.UUID) (int64, error) {
+	// 削除済みの投稿はすでに返信数から除かれている
+	return r.execDeletion(ctx, userID,
+		`UPDATE posts p
+		SET reply_count = GREATEST(p.reply_count - c.count, 0)
+		FROM (
+			SELECT reply_to_id AS id, COUNT(*) AS count
+			FROM posts
+			WHERE user_id = $1 AND reply_to_id IS NOT NULL AND deleted_at IS NULL
+			GROUP BY reply_to_id
+		) c
+		WHERE p.id = c.id AND p.user_id <> $1`,
+		`UPDATE posts p
+		SET repost_count = GREATEST(p.repost_count - c.count, 0)
+		FROM (
+			SELECT repost_id AS id, COUNT(*) AS count
+			FROM posts
+			WHERE user_id = $1 AND repost_id IS NOT NULL AND deleted_at IS NULL
+			GROUP BY repost_id
+		) c
+		WHERE p.id = c.id AND p.user_id <> $1`,
+		`DELETE FROM posts WHERE user_id = $1`,
+	)
+}
+
+func (r *accountDeletionRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	// 削除手続き中でないユーザーは削除しない
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM users WHERE id = $1 AND status = 'deleting'`, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// execDeletion runs the counter updates and the final DELETE in one transaction and returns the deleted row count
+func (r *accountDeletionRepository) execDeletion(ctx context.Context, userID uuid.UUID, queries ...string) (int64, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var deleted int64
+	for _, query := range queries {
+		result, err := tx.Exec(ctx, query, userID)
+		if err != nil {
+			return 0, err
+		}
+		deleted = result.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// scanAccountDeletion scans a row selected with accountDeletionColumns
+func scanAccountDeletion(row pgx.Row) (*models.AccountDeletion, error) {
+	deletion := &models.AccountDeletion{}
+	err := row.Scan(
+		&deletion.UserID,
+		&deletion.Status,
+		&deletion.Step,
+		&deletion.MediaTotal,
+		&deletion.MediaDeleted,
+		&deletion.Attempts,
+		&deletion.LastError,
+		&deletion.NextAttemptAt,
+		&deletion.RequestedAt,
+		&deletion.StartedAt,
+		&deletion.CompletedAt,
+		&deletion.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return deletion, nil
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountDeletionRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	likeRepo := NewLikeRepository(db.Pool)
+	followRepo := NewFollowRepository(db.Pool)
+	notificationRepo := NewNotificationRepository(db.Pool)
+	counterRepo := NewCounterRepository(db.Pool)
+	deletionRepo := NewAccountDeletionRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	leaving := &models.User{
+		ID:           uuid.New(),
+		Username:     "leavinguser",
+		Email:        "leavinguser@example.com",
+		Password:     "hashedpassword",
+		Name:         "Leaving User",
+		ProfileImage: "http://localhost:8080/media/users/leaving/avatar/a.png",
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+	staying := &models.User{
+		ID:        uuid.New(),
+		Username:  "stayinguser",
+		Email:     "stayinguser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Staying User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, leaving))
+	require.NoError(t, userRepo.Create(ctx, staying))
+
+	// 削除されるユーザーの投稿・いいね・フォロー・通知
+	stayingPost := models.NewPost(staying.ID, "Staying post", nil)
+	require.NoError(t, postRepo.Create(ctx, stayingPost))
+	leavingPost := models.NewPost(leaving.ID, "Leaving post", []string{"http://localhost:8080/media/posts/b.png"})
+	require.NoError(t, postRepo.Create(ctx, leavingPost))
+	leavingReply := models.NewReply(leaving.ID, stayingPost.ID, "Leaving reply", nil)
+	require.NoError(t, postRepo.Create(ctx, leavingReply))
+
+	require.NoError(t, likeRepo.Like(ctx, models.NewLike(leaving.ID, stayingPost.ID)))
+	require.NoError(t, followRepo.Follow(ctx, leaving.ID, staying.ID))
+	require.NoError(t, followRepo.Follow(ctx, staying.ID, leaving.ID))
+	require.NoError(t, notificationRepo.Create(ctx, models.NewNotification(staying.ID, leaving.ID, models.NotificationTypeFollow, nil)))
+
+	_, err := counterRepo.ReconcilePostCounts(ctx)
+	require.NoError(t, err)
+	_, err = counterRepo.ReconcileUserCounts(ctx)
+	require.NoError(t, err)
+
+	// Create のテスト
+	t.Run("Create", func(t *testing.T) {
+		require.NoError(t, userRepo.MarkForDeletion(ctx, leaving.ID))
+		require.NoError(t, deletionRepo.Create(ctx, models.NewAccountDeletion(leaving.ID)))
+
+		// 削除手続き中のユーザーは隠される
+		_, err := userRepo.GetByID(ctx, leaving.ID)
+		assert.Error(t, err)
+
+		// 二重の登録はエラー
+		err = deletionRepo.Create(ctx, models.NewAccountDeletion(leaving.ID))
+		assert.EqualError(t, err, "account deletion already scheduled")
+
+		deletion, err := deletionRepo.GetByUserID(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.AccountDeletionPending, deletion.Status)
+		assert.Equal(t, models.AccountDeletionStepDisconnect, deletion.Step)
+	})
+
+	// ClaimNext と UpdateProgress のテスト
+	t.Run("ClaimNext", func(t *testing.T) {
+		deletion, err := deletionRepo.ClaimNext(ctx, time.Hour)
+		require.NoError(t, err)
+		require.NotNil(t, deletion)
+		assert.Equal(t, leaving.ID, deletion.UserID)
+		assert.Equal(t, models.AccountDeletionRunning, deletion.Status)
+		assert.Equal(t, 1, deletion.Attempts)
+		assert.NotNil(t, deletion.StartedAt)
+
+		// 実行中の手続きは再取得しない
+		next, err := deletionRepo.ClaimNext(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+
+		deletion.Step = models.AccountDeletionStepMedia
+		deletion.MediaTotal = 2
+		require.NoError(t, deletionRepo.UpdateProgress(ctx, deletion))
+
+		saved, err := deletionRepo.GetByUserID(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.AccountDeletionStepMedia, saved.Step)
+		assert.Equal(t, 2, saved.MediaTotal)
+	})
+
+	// ListMediaURLs のテスト
+	t.Run("ListMediaURLs", func(t *testing.T) {
+		urls, err := deletionRepo.ListMediaURLs(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"http://localhost:8080/media/users/leaving/avatar/a.png",
+			"http://localhost:8080/media/posts/b.png",
+		}, urls)
+	})
+
+	// Delete* のテスト
+	t.Run("DeleteData", func(t *testing.T) {
+		deleted, err := deletionRepo.DeleteLikes(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		deleted, err = deletionRepo.DeleteFollows(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		deleted, err = deletionRepo.DeleteNotifications(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		deleted, err = deletionRepo.DeletePosts(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		// 残るユーザーのカウンターが減っている
+		post, err := postRepo.GetByID(ctx, stayingPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, post.LikeCount)
+		assert.Equal(t, 0, post.ReplyCount)
+
+		user, err := userRepo.GetByID(ctx, staying.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, user.FollowerCount)
+		assert.Equal(t, 0, user.FollowingCount)
+
+		// 再実行しても失敗しない
+		deleted, err = deletionRepo.DeleteLikes(ctx, leaving.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), deleted)
+
+		require.NoError(t, deletionRepo.DeleteUser(ctx, leaving.ID))
+		assert.EqualError(t, deletionRepo.DeleteUser(ctx, leaving.ID), "user not found")
+
+		// 削除手続き中でないユーザーは削除しない
+		assert.EqualError(t, deletionRepo.DeleteUser(ctx, staying.ID), "user not found")
+
+		// ユーザーの削除後も進捗を確認できる
+		_, err = deletionRepo.GetByUserID(ctx, leaving.ID)
+		assert.NoError(t, err)
+	})
+}
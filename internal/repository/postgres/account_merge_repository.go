@@ -0,0 +1,434 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const accountMergeColumns = `id, source_user_id, source_username, target_user_id, requested_by, status, step,
+			moved, dropped, attempts, last_error, next_attempt_at, requested_at, started_at, completed_at, updated_at`
+
+// Conditions on a row of follows f (or likes l) of the source account ($1) that the
+// target account ($2) already has, so the row is dropped instead of moved
+const (
+	duplicateFollowingCondition = `(f.followee_id = $2 OR EXISTS (
+			SELECT 1 FROM follows t WHERE t.follower_id = $2 AND t.followee_id = f.followee_id))`
+	duplicateFollowerCondition = `(f.follower_id = $2 OR EXISTS (
+			SELECT 1 FROM follows t WHERE t.followee_id = $2 AND t.follower_id = f.follower_id))`
+	duplicateLikeCondition = `EXISTS (SELECT 1 FROM likes t WHERE t.user_id = $2 AND t.post_id = l.post_id)`
+	// selfNotificationCondition matches notifications that would be sent from the target to itself
+	selfNotificationCondition = `(user_id IN ($1, $2) AND actor_id IN ($1, $2))`
+)
+
+type accountMergeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAccountMergeRepository creates a new PostgreSQL implementation of AccountMergeRepository
+func NewAccountMergeRepository(db *pgxpool.Pool) interfaces.AccountMergeRepository {
+	return &accountMergeRepository{db: db}
+}
+
+// Create stores a pending merge. A second active merge of the same source
+// account violates the partial unique index and is rejected.
+func (r *accountMergeRepository) Create(ctx context.Context, merge *models.AccountMerge) error {
+	query := `
+		INSERT INTO account_merges (id, source_user_id, source_username, target_user_id, requested_by,
+			status, step, next_attempt_at, requested_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		merge.ID,
+		merge.SourceUserID,
+		merge.SourceUsername,
+		merge.TargetUserID,
+		merge.RequestedBy,
+		merge.Status,
+		merge.Step,
+		merge.NextAttemptAt,
+		merge.RequestedAt,
+		merge.UpdatedAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("account merge already in progress")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *accountMergeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AccountMerge, error) {
+	query := `
+		SELECT ` + accountMergeColumns + `
+		FROM account_merges
+		WHERE id = $1
+	`
+
+	merge, err := scanAccountMerge(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("account merge not found")
+		}
+		return nil, err
+	}
+
+	return merge, nil
+}
+
+func (r *accountMergeRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.AccountMerge, error) {
+	// 他のワーカーが取得中の手続きは飛ばす
+	query := `
+		UPDATE account_merges
+		SET status = 'running',
+			attempts = attempts + 1,
+			started_at = COALESCE(started_at, NOW()),
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM account_merges
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+				OR (status = 'running' AND updated_at < $1)
+			ORDER BY next_attempt_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + accountMergeColumns
+
+	merge, err := scanAccountMerge(conn(ctx, r.db).QueryRow(ctx, query, time.Now().Add(-staleAfter)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return merge, nil
+}
+
+func (r *accountMergeRepository) UpdateProgress(ctx context.Context, merge *models.AccountMerge) error {
+	moved, err := json.Marshal(merge.Moved)
+	if err != nil {
+		return err
+	}
+	dropped, err := json.Marshal(merge.Dropped)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE account_merges
+		SET status = $2,
+			step = $3,
+			moved = $4,
+			dropped = $5,
+			attempts = $6,
+			last_error = $7,
+			next_attempt_at = $8,
+			completed_at = $9,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = conn(ctx, r.db).QueryRow(ctx, query,
+		merge.ID,
+		merge.Status,
+		merge.Step,
+		string(moved),
+		string(dropped),
+		merge.Attempts,
+		merge.LastError,
+		merge.NextAttemptAt,
+		merge.CompletedAt,
+	).Scan(&merge.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("account merge not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Preview counts the rows each step would move or drop with the same conditions the steps use
+func (r *accountMergeRepository) Preview(ctx context.Context, sourceID, targetID uuid.UUID) (*models.AccountMergeReport, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM posts WHERE user_id = $1),
+			(SELECT COUNT(*) FROM follows f WHERE f.follower_id = $1 AND NOT ` + duplicateFollowingCondition + `),
+			(SELECT COUNT(*) FROM follows f WHERE f.follower_id = $1 AND ` + duplicateFollowingCondition + `),
+			(SELECT COUNT(*) FROM follows f WHERE f.followee_id = $1 AND NOT ` + duplicateFollowerCondition + `),
+			(SELECT COUNT(*) FROM follows f WHERE f.followee_id = $1 AND ` + duplicateFollowerCondition + `),
+			(SELECT COUNT(*) FROM likes l WHERE l.user_id = $1 AND NOT ` + duplicateLikeCondition + `),
+			(SELECT COUNT(*) FROM likes l WHERE l.user_id = $1 AND ` + duplicateLikeCondition + `),
+			(SELECT COUNT(*) FROM notifications WHERE (user_id = $1 OR actor_id = $1) AND NOT ` + selfNotificationCondition + `),
+			(SELECT COUNT(*) FROM notifications WHERE (user_id = $1 OR actor_id = $1) AND ` + selfNotificationCondition + `),
+			(SELECT COUNT(*) FROM username_redirects WHERE user_id = $1)
+	`
+
+	report := &models.AccountMergeReport{SourceUserID: sourceID, TargetUserID: targetID}
+	err := conn(ctx, r.db).QueryRow(ctx, query, sourceID, targetID).Scan(
+		&report.Posts,
+		&report.Following,
+		&report.FollowingDropped,
+		&report.Followers,
+		&report.FollowersDropped,
+		&report.Likes,
+		&report.LikesDropped,
+		&report.Notifications,
+		&report.NotificationsDropped,
+		&report.RedirectsMoved,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *accountMergeRepository) MovePosts(ctx context.Context, sourceID, targetID uuid.UUID) (int64, error) {
+	var moved int64
+	err := r.withinTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `UPDATE posts SET user_id = $2 WHERE user_id = $1`, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		moved = result.RowsAffected()
+
+		_, err = tx.Exec(ctx, `
+			UPDATE users
+			SET post_count = (SELECT COUNT(*) FROM posts WHERE user_id = $1 AND deleted_at IS NULL)
+			WHERE id = $1`, targetID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// MoveFollows moves the follows of the source account to the target and
+// drops the ones the target already has. Moved follows are recorded as
+// projected follow events of the target, and the events of the source are
+// deleted, so that rebuilding follows from the events keeps the merge.
+func (r *accountMergeRepository) MoveFollows(ctx context.Context, sourceID, targetID uuid.UUID) (int64, int64, error) {
+	var moved, dropped int64
+	err := r.withinTx(ctx, func(tx pgx.Tx) error {
+		// 重複して削除される関係の相手のフォロワー数・フォロー数を減らす（統合先の数は最後に再計算する）
+		counterQueries := []string{
+			`UPDATE users u
+			SET follower_count = GREATEST(u.follower_count - 1, 0)
+			FROM follows f
+			WHERE f.follower_id = $1 AND f.followee_id = u.id AND u.id <> $2 AND ` + duplicateFollowingCondition,
+			`UPDATE users u
+			SET following_count = GREATEST(u.following_count - 1, 0)
+			FROM follows f
+			WHERE f.followee_id = $1 AND f.follower_id = u.id AND u.id <> $2 AND ` + duplicateFollowerCondition,
+			`INSERT INTO follow_events (follower_id, followee_id, event_type, occurred_at, projected_at)
+			SELECT $2, f.followee_id, 'follow', f.created_at, NOW()
+			FROM follows f
+			WHERE f.follower_id = $1 AND NOT ` + duplicateFollowingCondition,
+			`INSERT INTO follow_events (follower_id, followee_id, event_type, occurred_at, projected_at)
+			SELECT f.follower_id, $2, 'follow', f.created_at, NOW()
+			FROM follows f
+			WHERE f.followee_id = $1 AND NOT ` + duplicateFollowerCondition,
+		}
+		batch := &pgx.Batch{}
+		for _, query := range counterQueries {
+			batch.Queue(query, sourceID, targetID)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+
+		following, err := tx.Exec(ctx, `
+			UPDATE follows f SET follower_id = $2
+			WHERE f.follower_id = $1 AND NOT `+duplicateFollowingCondition, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		followers, err := tx.Exec(ctx, `
+			UPDATE follows f SET followee_id = $2
+			WHERE f.followee_id = $1 AND NOT `+duplicateFollowerCondition, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		moved = following.RowsAffected() + followers.RowsAffected()
+
+		if _, err := tx.Exec(ctx, `DELETE FROM follow_events WHERE follower_id = $1 OR followee_id = $1`, sourceID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(ctx, `DELETE FROM follows WHERE follower_id = $1 OR followee_id = $1`, sourceID)
+		if err != nil {
+			return err
+		}
+		dropped = result.RowsAffected()
+
+		_, err = tx.Exec(ctx, `
+			UPDATE users
+			SET follower_count = (SELECT COUNT(*) FROM follows WHERE followee_id = $1),
+				following_count = (SELECT COUNT(*) FROM follows WHERE follower_id = $1)
+			WHERE id = $1`, targetID)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return moved, dropped, nil
+}
+
+func (r *accountMergeRepository) MoveLikes(ctx context.Context, sourceID, targetID uuid.UUID) (int64, int64, error) {
+	var moved, dropped int64
+	err := r.withinTx(ctx, func(tx pgx.Tx) error {
+		// 重複して削除されるいいねの分だけ投稿のいいね数を減らす
+		_, err := tx.Exec(ctx, `
+			UPDATE posts p
+			SET like_count = GREATEST(p.like_count - 1, 0)
+			FROM likes l
+			WHERE l.post_id = p.id AND l.user_id = $1 AND `+duplicateLikeCondition, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx, `
+			UPDATE likes l SET user_id = $2
+			WHERE l.user_id = $1 AND NOT `+duplicateLikeCondition, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		moved = result.RowsAffected()
+
+		result, err = tx.Exec(ctx, `DELETE FROM likes WHERE user_id = $1`, sourceID)
+		if err != nil {
+			return err
+		}
+		dropped = result.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return moved, dropped, nil
+}
+
+func (r *accountMergeRepository) MoveNotifications(ctx context.Context, sourceID, targetID uuid.UUID) (int64, int64, error) {
+	var moved, dropped int64
+	err := r.withinTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+			DELETE FROM notifications
+			WHERE (user_id = $1 OR actor_id = $1) AND `+selfNotificationCondition, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		dropped = result.RowsAffected()
+
+		result, err = tx.Exec(ctx, `
+			UPDATE notifications
+			SET user_id = CASE WHEN user_id = $1 THEN $2 ELSE user_id END,
+				actor_id = CASE WHEN actor_id = $1 THEN $2 ELSE actor_id END
+			WHERE user_id = $1 OR actor_id = $1`, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		moved = result.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return moved, dropped, nil
+}
+
+// Redirect deletes the source account and points its username, and every
+// username that already redirected to it, at the target account
+func (r *accountMergeRepository) Redirect(ctx context.Context, sourceID uuid.UUID, sourceUsername string, targetID uuid.UUID) error {
+	return r.withinTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE username_redirects SET user_id = $2 WHERE user_id = $1`, sourceID, targetID); err != nil {
+			return err
+		}
+
+		// 統合中でないユーザーは削除しない
+		result, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1 AND status = 'merging'`, sourceID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return errors.New("user not found")
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO username_redirects (username, user_id, created_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (username) DO UPDATE SET user_id = EXCLUDED.user_id, created_at = EXCLUDED.created_at`,
+			sourceUsername, targetID)
+		return err
+	})
+}
+
+// withinTx runs fn in a transaction (nested in the caller's transaction if there is one)
+func (r *accountMergeRepository) withinTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// scanAccountMerge scans a row selected with accountMergeColumns
+func scanAccountMerge(row pgx.Row) (*models.AccountMerge, error) {
+	merge := &models.AccountMerge{}
+	var moved, dropped []byte
+	err := row.Scan(
+		&merge.ID,
+		&merge.SourceUserID,
+		&merge.SourceUsername,
+		&merge.TargetUserID,
+		&merge.RequestedBy,
+		&merge.Status,
+		&merge.Step,
+		&moved,
+		&dropped,
+		&merge.Attempts,
+		&merge.LastError,
+		&merge.NextAttemptAt,
+		&merge.RequestedAt,
+		&merge.StartedAt,
+		&merge.CompletedAt,
+		&merge.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(moved, &merge.Moved); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(dropped, &merge.Dropped); err != nil {
+		return nil, err
+	}
+
+	return merge, nil
+}
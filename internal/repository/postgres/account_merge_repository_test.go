@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountMergeRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	likeRepo := NewLikeRepository(db.Pool)
+	followRepo := NewFollowRepository(db.Pool)
+	notificationRepo := NewNotificationRepository(db.Pool)
+	counterRepo := NewCounterRepository(db.Pool)
+	mergeRepo := NewAccountMergeRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成（duplicateをprimaryに統合する）
+	newUser := func(username string) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  "hashedpassword",
+			Name:      username,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	duplicate := newUser("duplicateuser")
+	primary := newUser("primaryuser")
+	friend := newUser("frienduser")
+	other := newUser("otheruser")
+
+	// 統合元の投稿
+	duplicatePost := models.NewPost(duplicate.ID, "Duplicate post", nil)
+	require.NoError(t, postRepo.Create(ctx, duplicatePost))
+	friendPost := models.NewPost(friend.ID, "Friend post", nil)
+	require.NoError(t, postRepo.Create(ctx, friendPost))
+	otherPost := models.NewPost(other.ID, "Other post", nil)
+	require.NoError(t, postRepo.Create(ctx, otherPost))
+
+	// friendへのフォローといいねは統合先と重複し、otherへのものは移される
+	require.NoError(t, followRepo.Follow(ctx, duplicate.ID, friend.ID))
+	require.NoError(t, followRepo.Follow(ctx, primary.ID, friend.ID))
+	require.NoError(t, followRepo.Follow(ctx, duplicate.ID, other.ID))
+	require.NoError(t, followRepo.Follow(ctx, friend.ID, duplicate.ID))
+	require.NoError(t, followRepo.Follow(ctx, duplicate.ID, primary.ID))
+	require.NoError(t, likeRepo.Like(ctx, models.NewLike(duplicate.ID, friendPost.ID)))
+	require.NoError(t, likeRepo.Like(ctx, models.NewLike(primary.ID, friendPost.ID)))
+	require.NoError(t, likeRepo.Like(ctx, models.NewLike(duplicate.ID, otherPost.ID)))
+	require.NoError(t, notificationRepo.Create(ctx, models.NewNotification(duplicate.ID, friend.ID, models.NotificationTypeFollow, nil)))
+	require.NoError(t, notificationRepo.Create(ctx, models.NewNotification(primary.ID, duplicate.ID, models.NotificationTypeFollow, nil)))
+
+	_, err := counterRepo.ReconcilePostCounts(ctx)
+	require.NoError(t, err)
+	_, err = counterRepo.ReconcileUserCounts(ctx)
+	require.NoError(t, err)
+
+	var merge *models.AccountMerge
+
+	// Preview のテスト
+	t.Run("Preview", func(t *testing.T) {
+		report, err := mergeRepo.Preview(ctx, duplicate.ID, primary.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), report.Posts)
+		assert.Equal(t, int64(1), report.Following)
+		assert.Equal(t, int64(2), report.FollowingDropped)
+		assert.Equal(t, int64(1), report.Followers)
+		assert.Equal(t, int64(0), report.FollowersDropped)
+		assert.Equal(t, int64(1), report.Likes)
+		assert.Equal(t, int64(1), report.LikesDropped)
+		assert.Equal(t, int64(1), report.Notifications)
+		assert.Equal(t, int64(1), report.NotificationsDropped)
+	})
+
+	// Create と ClaimNext のテスト
+	t.Run("CreateAndClaim", func(t *testing.T) {
+		require.NoError(t, userRepo.MarkForMerge(ctx, duplicate.ID))
+		merge = models.NewAccountMerge(duplicate, primary.ID, primary.ID)
+		require.NoError(t, mergeRepo.Create(ctx, merge))
+
+		// 同じアカウントの統合は同時に1件だけ
+		err := mergeRepo.Create(ctx, models.NewAccountMerge(duplicate, other.ID, primary.ID))
+		require.Error(t, err)
+		assert.Equal(t, "account merge already in progress", err.Error())
+
+		// 統合中のユーザーは隠される
+		_, err = userRepo.GetByID(ctx, duplicate.ID)
+		assert.Error(t, err)
+
+		claimed, err := mergeRepo.ClaimNext(ctx, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, merge.ID, claimed.ID)
+		assert.Equal(t, models.AccountMergeRunning, claimed.Status)
+		assert.Equal(t, 1, claimed.Attempts)
+
+		none, err := mergeRepo.ClaimNext(ctx, time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, none)
+	})
+
+	// 各手順のテスト
+	t.Run("MoveData", func(t *testing.T) {
+		moved, err := mergeRepo.MovePosts(ctx, duplicate.ID, primary.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), moved)
+
+		post, err := postRepo.GetByID(ctx, duplicatePost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, primary.ID, post.UserID)
+
+		moved, dropped, err := mergeRepo.MoveFollows(ctx, duplicate.ID, primary.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), moved)
+		assert.Equal(t, int64(2), dropped)
+
+		following, err := followRepo.IsFollowing(ctx, primary.ID, other.ID)
+		require.NoError(t, err)
+		assert.True(t, following)
+		following, err = followRepo.IsFollowing(ctx, friend.ID, primary.ID)
+		require.NoError(t, err)
+		assert.True(t, following)
+
+		moved, dropped, err = mergeRepo.MoveLikes(ctx, duplicate.ID, primary.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), moved)
+		assert.Equal(t, int64(1), dropped)
+
+		liked, err := likeRepo.HasLiked(ctx, primary.ID, otherPost.ID)
+		require.NoError(t, err)
+		assert.True(t, liked)
+		post, err = postRepo.GetByID(ctx, friendPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, post.LikeCount)
+
+		moved, dropped, err = mergeRepo.MoveNotifications(ctx, duplicate.ID, primary.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), moved)
+		assert.Equal(t, int64(1), dropped)
+
+		// 再実行しても移すデータは残っていない
+		moved, dropped, err = mergeRepo.MoveFollows(ctx, duplicate.ID, primary.ID)
+		require.NoError(t, err)
+		assert.Zero(t, moved)
+		assert.Zero(t, dropped)
+
+		// 統合先とフォローの相手の数が再計算されている
+		updated, err := userRepo.GetByID(ctx, primary.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated.PostCount)
+		assert.Equal(t, 2, updated.FollowingCount)
+		assert.Equal(t, 1, updated.FollowerCount)
+		updatedFriend, err := userRepo.GetByID(ctx, friend.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updatedFriend.FollowerCount)
+	})
+
+	// Redirect のテスト
+	t.Run("Redirect", func(t *testing.T) {
+		require.NoError(t, mergeRepo.Redirect(ctx, duplicate.ID, duplicate.Username, primary.ID))
+
+		_, err := userRepo.GetByIDIncludingInactive(ctx, duplicate.ID)
+		assert.Error(t, err)
+
+		redirected, err := userRepo.GetByRedirectedUsername(ctx, duplicate.Username)
+		require.NoError(t, err)
+		assert.Equal(t, primary.ID, redirected.ID)
+
+		// 転送中のユーザー名は登録できない
+		available, err := userRepo.IsUsernameAvailable(ctx, duplicate.Username)
+		require.NoError(t, err)
+		assert.False(t, available)
+
+		// 削除済みの場合は見つからない
+		err = mergeRepo.Redirect(ctx, duplicate.ID, duplicate.Username, primary.ID)
+		require.Error(t, err)
+		assert.Equal(t, "user not found", err.Error())
+	})
+
+	// UpdateProgress と GetByID のテスト
+	t.Run("UpdateProgress", func(t *testing.T) {
+		now := time.Now().UTC()
+		merge.Status = models.AccountMergeCompleted
+		merge.Step = models.AccountMergeStepDone
+		merge.Moved[models.AccountMergeStepPosts] = 1
+		merge.Dropped[models.AccountMergeStepLikes] = 1
+		merge.CompletedAt = &now
+		require.NoError(t, mergeRepo.UpdateProgress(ctx, merge))
+
+		saved, err := mergeRepo.GetByID(ctx, merge.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.AccountMergeCompleted, saved.Status)
+		assert.Equal(t, int64(1), saved.Moved[models.AccountMergeStepPosts])
+		assert.Equal(t, int64(1), saved.Dropped[models.AccountMergeStepLikes])
+		assert.Equal(t, "duplicateuser", saved.SourceUsername)
+
+		_, err = mergeRepo.GetByID(ctx, uuid.New())
+		require.Error(t, err)
+		assert.Equal(t, "account merge not found", err.Error())
+	})
+}
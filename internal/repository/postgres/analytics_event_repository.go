@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type analyticsEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAnalyticsEventRepository creates a new PostgreSQL implementation of AnalyticsEventRepository
+func NewAnalyticsEventRepository(db *pgxpool.Pool) interfaces.AnalyticsEventRepository {
+	return &analyticsEventRepository{db: db}
+}
+
+// InsertBatch inserts the events in a single statement. User and post IDs that
+// no longer exist (or were reported by a client for unknown rows) are stored as NULL
+// so one stale reference does not reject the whole batch.
+func (r *analyticsEventRepository) InsertBatch(ctx context.Context, events []*models.AnalyticsEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(events))
+	types := make([]string, len(events))
+	userIDs := make([]*uuid.UUID, len(events))
+	postIDs := make([]*uuid.UUID, len(events))
+	properties := make([]string, len(events))
+	occurredAt := make([]time.Time, len(events))
+	for i, event := range events {
+		props, err := json.Marshal(event.Properties)
+		if err != nil {
+			return err
+		}
+		ids[i] = event.ID
+		types[i] = string(event.Type)
+		userIDs[i] = event.UserID
+		postIDs[i] = event.PostID
+		properties[i] = string(props)
+		occurredAt[i] = event.OccurredAt
+	}
+
+	query := `
+		INSERT INTO analytics_events (id, type, user_id, post_id, properties, occurred_at)
+		SELECT e.id, e.type, u.id, p.id, e.properties::jsonb, e.occurred_at
+		FROM unnest($1::uuid[], $2::text[], $3::uuid[], $4::uuid[], $5::text[], $6::timestamptz[])
+			AS e(id, type, user_id, post_id, properties, occurred_at)
+		LEFT JOIN users u ON u.id = e.user_id
+		LEFT JOIN posts p ON p.id = e.post_id
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, ids, types, userIDs, postIDs, properties, occurredAt)
+	return err
+}
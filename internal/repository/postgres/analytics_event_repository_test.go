@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsEventRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	analyticsRepo := NewAnalyticsEventRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーと投稿の作成
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "analyticsuser",
+		Email:     "analytics@example.com",
+		Password:  "hashedpassword",
+		Name:      "Analytics User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+	post := models.NewPost(user.ID, "Analytics content", nil)
+	require.NoError(t, postRepo.Create(ctx, post))
+
+	countEvents := func(t *testing.T) int {
+		var count int
+		require.NoError(t, db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM analytics_events").Scan(&count))
+		return count
+	}
+
+	// InsertBatch のテスト
+	t.Run("InsertBatch", func(t *testing.T) {
+		impression := models.NewAnalyticsEvent(models.AnalyticsEventImpression, &user.ID, &post.ID, nil)
+		click := models.NewAnalyticsEvent(models.AnalyticsEventClick, &user.ID, &post.ID, map[string]string{"target": "link"})
+		signup := models.NewAnalyticsEvent(models.AnalyticsEventSignup, &user.ID, nil, map[string]string{"method": "password"})
+		require.NoError(t, analyticsRepo.InsertBatch(ctx, []*models.AnalyticsEvent{impression, click, signup}))
+		assert.Equal(t, 3, countEvents(t))
+
+		var target string
+		var postID *uuid.UUID
+		err := db.Pool.QueryRow(ctx, "SELECT properties->>'target', post_id FROM analytics_events WHERE id = $1", click.ID).Scan(&target, &postID)
+		require.NoError(t, err)
+		assert.Equal(t, "link", target)
+		require.NotNil(t, postID)
+		assert.Equal(t, post.ID, *postID)
+
+		// 再送されたイベントは重複して保存しない
+		require.NoError(t, analyticsRepo.InsertBatch(ctx, []*models.AnalyticsEvent{impression}))
+		assert.Equal(t, 3, countEvents(t))
+	})
+
+	// 存在しない投稿のイベントのテスト
+	t.Run("UnknownPost", func(t *testing.T) {
+		unknownPostID := uuid.New()
+		event := models.NewAnalyticsEvent(models.AnalyticsEventImpression, &user.ID, &unknownPostID, nil)
+		require.NoError(t, analyticsRepo.InsertBatch(ctx, []*models.AnalyticsEvent{event}))
+
+		// 投稿IDのみNULLとして保存する
+		var postID *uuid.UUID
+		var userID *uuid.UUID
+		err := db.Pool.QueryRow(ctx, "SELECT user_id, post_id FROM analytics_events WHERE id = $1", event.ID).Scan(&userID, &postID)
+		require.NoError(t, err)
+		assert.Nil(t, postID)
+		require.NotNil(t, userID)
+		assert.Equal(t, user.ID, *userID)
+	})
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const apiUsageColumns = `user_id, app, key_id, endpoint, hour, request_count, request_bytes, response_bytes`
+
+type apiUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAPIUsageRepository creates a new PostgreSQL implementation of APIUsageRepository
+func NewAPIUsageRepository(db *pgxpool.Pool) interfaces.APIUsageRepository {
+	return &apiUsageRepository{db: db}
+}
+
+func (r *apiUsageRepository) AddUsage(ctx context.Context, usages []*models.APIUsage) error {
+	if len(usages) == 0 {
+		return nil
+	}
+
+	// 複数のプロセスが同時に書き込む場合のデッドロックを避けるため、主キーの順に更新する
+	sorted := make([]*models.APIUsage, len(usages))
+	copy(sorted, usages)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if c := bytes.Compare(a.UserID[:], b.UserID[:]); c != 0 {
+			return c < 0
+		}
+		if !a.Hour.Equal(b.Hour) {
+			return a.Hour.Before(b.Hour)
+		}
+		if a.App != b.App {
+			return a.App < b.App
+		}
+		if a.KeyID != b.KeyID {
+			return a.KeyID < b.KeyID
+		}
+		return a.Endpoint < b.Endpoint
+	})
+
+	userIDs := make([]uuid.UUID, len(sorted))
+	apps := make([]string, len(sorted))
+	keyIDs := make([]string, len(sorted))
+	endpoints := make([]string, len(sorted))
+	hours := make([]time.Time, len(sorted))
+	requests := make([]int64, len(sorted))
+	requestBytes := make([]int64, len(sorted))
+	responseBytes := make([]int64, len(sorted))
+	for i, usage := range sorted {
+		userIDs[i] = usage.UserID
+		apps[i] = usage.App
+		keyIDs[i] = usage.KeyID
+		endpoints[i] = usage.Endpoint
+		hours[i] = usage.Hour.UTC().Truncate(time.Hour)
+		requests[i] = usage.RequestCount
+		requestBytes[i] = usage.RequestBytes
+		responseBytes[i] = usage.ResponseBytes
+	}
+
+	// 集計中に削除されたユーザーの利用状況は破棄する
+	query := `
+		INSERT INTO api_usage_hourly (` + apiUsageColumns + `)
+		SELECT u.user_id, u.app, u.key_id, u.endpoint, u.hour, u.request_count, u.request_bytes, u.response_bytes
+		FROM unnest($1::uuid[], $2::text[], $3::text[], $4::text[], $5::timestamptz[], $6::bigint[], $7::bigint[], $8::bigint[])
+			AS u(user_id, app, key_id, endpoint, hour, request_count, request_bytes, response_bytes)
+		JOIN users ON users.id = u.user_id
+		ON CONFLICT (user_id, hour, app, key_id, endpoint)
+		DO UPDATE SET
+			request_count = api_usage_hourly.request_count + EXCLUDED.request_count,
+			request_bytes = api_usage_hourly.request_bytes + EXCLUDED.request_bytes,
+			response_bytes = api_usage_hourly.response_bytes + EXCLUDED.response_bytes
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		userIDs, apps, keyIDs, endpoints, hours, requests, requestBytes, responseBytes)
+	return err
+}
+
+func (r *apiUsageRepository) ListByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.APIUsage, error) {
+	query := `
+		SELECT ` + apiUsageColumns + `
+		FROM api_usage_hourly
+		WHERE user_id = $1 AND hour >= $2 AND hour < $3
+		ORDER BY hour DESC, request_count DESC, endpoint ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanAPIUsages(rows)
+}
+
+func (r *apiUsageRepository) ListByKey(ctx context.Context, keyID string, from, to time.Time) ([]*models.APIUsage, error) {
+	query := `
+		SELECT ` + apiUsageColumns + `
+		FROM api_usage_hourly
+		WHERE key_id = $1 AND hour >= $2 AND hour < $3
+		ORDER BY hour DESC, request_count DESC, endpoint ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, keyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanAPIUsages(rows)
+}
+
+func (r *apiUsageRepository) SummarizeByKey(ctx context.Context, app string, from, to time.Time, limit int) ([]*models.APIUsageSummary, error) {
+	query := `
+		SELECT user_id, app, key_id,
+			SUM(request_count), SUM(request_bytes), SUM(response_bytes),
+			MIN(hour), MAX(hour)
+		FROM api_usage_hourly
+		WHERE hour >= $1 AND hour < $2 AND ($3 = '' OR app = $3)
+		GROUP BY user_id, app, key_id
+		ORDER BY SUM(request_count) DESC, key_id ASC
+		LIMIT $4
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, from, to, app, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*models.APIUsageSummary
+	for rows.Next() {
+		summary := &models.APIUsageSummary{}
+		if err := rows.Scan(
+			&summary.UserID,
+			&summary.App,
+			&summary.KeyID,
+			&summary.RequestCount,
+			&summary.RequestBytes,
+			&summary.ResponseBytes,
+			&summary.FirstSeen,
+			&summary.LastSeen,
+		); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// scanAPIUsages scans rows selected with apiUsageColumns and closes them
+func scanAPIUsages(rows pgx.Rows) ([]*models.APIUsage, error) {
+	defer rows.Close()
+
+	var usages []*models.APIUsage
+	for rows.Next() {
+		usage := &models.APIUsage{}
+		if err := rows.Scan(
+			&usage.UserID,
+			&usage.App,
+			&usage.KeyID,
+			&usage.Endpoint,
+			&usage.Hour,
+			&usage.RequestCount,
+			&usage.RequestBytes,
+			&usage.ResponseBytes,
+		); err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usages, nil
+}
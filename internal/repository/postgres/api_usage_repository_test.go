@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIUsageRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	usageRepo := NewAPIUsageRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "usageuser",
+		Email:     "usageuser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Usage User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+	from := hour.Add(-24 * time.Hour)
+	to := hour.Add(time.Hour)
+
+	// AddUsage のテスト
+	t.Run("AddUsage", func(t *testing.T) {
+		usages := []*models.APIUsage{
+			{UserID: user.ID, App: "web", KeyID: "key1", Endpoint: "GET /api/v1/timeline/home", Hour: hour, RequestCount: 2, RequestBytes: 0, ResponseBytes: 300},
+			{UserID: user.ID, App: "web", KeyID: "key1", Endpoint: "POST /api/v1/posts", Hour: hour, RequestCount: 1, RequestBytes: 50, ResponseBytes: 100},
+			{UserID: user.ID, App: "cli", KeyID: "key2", Endpoint: "GET /api/v1/timeline/home", Hour: hour.Add(-time.Hour), RequestCount: 1, RequestBytes: 0, ResponseBytes: 150},
+			// 存在しないユーザーの利用状況は破棄される
+			{UserID: uuid.New(), App: "web", KeyID: "key3", Endpoint: "GET /api/v1/timeline/home", Hour: hour, RequestCount: 1},
+		}
+		require.NoError(t, usageRepo.AddUsage(ctx, usages))
+
+		// 同じ時間・エンドポイントへの加算
+		require.NoError(t, usageRepo.AddUsage(ctx, []*models.APIUsage{
+			{UserID: user.ID, App: "web", KeyID: "key1", Endpoint: "GET /api/v1/timeline/home", Hour: hour.Add(10 * time.Minute), RequestCount: 3, ResponseBytes: 450},
+		}))
+
+		result, err := usageRepo.ListByUser(ctx, user.ID, from, to)
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+
+		// 新しい順、同じ時間ではリクエスト数の多い順
+		assert.Equal(t, "GET /api/v1/timeline/home", result[0].Endpoint)
+		assert.Equal(t, int64(5), result[0].RequestCount)
+		assert.Equal(t, int64(750), result[0].ResponseBytes)
+		assert.True(t, hour.Equal(result[0].Hour))
+		assert.Equal(t, "POST /api/v1/posts", result[1].Endpoint)
+		assert.Equal(t, int64(50), result[1].RequestBytes)
+		assert.Equal(t, "key2", result[2].KeyID)
+	})
+
+	// ListByKey のテスト
+	t.Run("ListByKey", func(t *testing.T) {
+		result, err := usageRepo.ListByKey(ctx, "key2", from, to)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, user.ID, result[0].UserID)
+		assert.Equal(t, "cli", result[0].App)
+
+		// 期間外
+		result, err = usageRepo.ListByKey(ctx, "key2", hour, to)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	// SummarizeByKey のテスト
+	t.Run("SummarizeByKey", func(t *testing.T) {
+		summaries, err := usageRepo.SummarizeByKey(ctx, "", from, to, 10)
+		require.NoError(t, err)
+		require.Len(t, summaries, 2)
+		assert.Equal(t, "key1", summaries[0].KeyID)
+		assert.Equal(t, int64(6), summaries[0].RequestCount)
+		assert.Equal(t, int64(850), summaries[0].ResponseBytes)
+		assert.Equal(t, "key2", summaries[1].KeyID)
+
+		// アプリで絞り込み
+		summaries, err = usageRepo.SummarizeByKey(ctx, "cli", from, to, 10)
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		assert.Equal(t, "key2", summaries[0].KeyID)
+		assert.True(t, hour.Add(-time.Hour).Equal(summaries[0].FirstSeen))
+	})
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditLogFilterCondition matches the entries selected by models.AuditLogFilter ($1 to $6)
+const auditLogFilterCondition = `
+	($1::uuid IS NULL OR actor_id = $1)
+	AND ($2::uuid IS NULL OR target_id = $2)
+	AND ($3::text = '' OR action = $3)
+	AND (NULLIF($4::text, '') IS NULL OR ip_address <<= NULLIF($4::text, '')::inet)
+	AND ($5::timestamptz IS NULL OR created_at >= $5)
+	AND ($6::timestamptz IS NULL OR created_at < $6)
+`
+
+type auditLogRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAuditLogRepository creates a new PostgreSQL implementation of AuditLogRepository
+func NewAuditLogRepository(db *pgxpool.Pool) interfaces.AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Create records an audit log entry. It joins the transaction in ctx so the
+// entry is only kept when the audited operation commits.
+func (r *auditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_logs (id, actor_id, action, target_type, target_id, ip_address, user_agent, details, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, '')::inet, $7, $8::jsonb, $9)
+	`
+
+	_, err = conn(ctx, r.db).Exec(ctx, query,
+		entry.ID, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID,
+		entry.IPAddress, entry.UserAgent, string(details), entry.CreatedAt,
+	)
+	return err
+}
+
+// List returns the newest entries matching the filter first
+func (r *auditLogRepository) List(ctx context.Context, filter models.AuditLogFilter, offset, limit int) ([]*models.AuditLog, error) {
+	query := `
+		SELECT id, actor_id, action, COALESCE(target_type, ''), target_id,
+			COALESCE(host(ip_address), ''), user_agent, details, created_at
+		FROM audit_logs
+		WHERE` + auditLogFilterCondition + `
+		ORDER BY created_at DESC, id
+		LIMIT $7 OFFSET $8
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query,
+		filter.ActorID, filter.TargetID, string(filter.Action), filter.IPAddress, filter.Since, filter.Until,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.AuditLog{}
+	for rows.Next() {
+		var entry models.AuditLog
+		var details []byte
+		if err := rows.Scan(
+			&entry.ID, &entry.ActorID, &entry.Action, &entry.TargetType, &entry.TargetID,
+			&entry.IPAddress, &entry.UserAgent, &details, &entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(details, &entry.Details); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Count returns the number of entries matching the filter
+func (r *auditLogRepository) Count(ctx context.Context, filter models.AuditLogFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM audit_logs WHERE" + auditLogFilterCondition
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		filter.ActorID, filter.TargetID, string(filter.Action), filter.IPAddress, filter.Since, filter.Until,
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
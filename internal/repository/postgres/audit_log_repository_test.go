@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	auditRepo := NewAuditLogRepository(db.Pool)
+	txManager := NewTxManager(db.Pool)
+
+	ctx := context.Background()
+
+	// 管理者と対象のユーザーの作成
+	admin := &models.User{
+		ID:        uuid.New(),
+		Username:  "auditadmin",
+		Email:     "auditadmin@example.com",
+		Password:  "hashedpassword",
+		Name:      "Audit Admin",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, admin))
+	target := &models.User{
+		ID:        uuid.New(),
+		Username:  "audittarget",
+		Email:     "audittarget@example.com",
+		Password:  "hashedpassword",
+		Name:      "Audit Target",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, target))
+
+	// Create と List のテスト
+	t.Run("CreateAndList", func(t *testing.T) {
+		first := models.NewAuditLog(admin.ID, models.AuditUserVerify, models.AuditTargetUser, target.ID, map[string]string{"verified": "true"})
+		first.CreatedAt = time.Now().UTC().Add(-time.Minute)
+		require.NoError(t, auditRepo.Create(ctx, first))
+
+		second := models.NewAuditLog(admin.ID, models.AuditUserSuspend, models.AuditTargetUser, target.ID, nil)
+		require.NoError(t, auditRepo.Create(ctx, second))
+
+		other := models.NewAuditLog(admin.ID, models.AuditUserRole, models.AuditTargetUser, admin.ID, map[string]string{"role": "admin"})
+		require.NoError(t, auditRepo.Create(ctx, other))
+
+		// 新しい順に返す
+		entries, err := auditRepo.List(ctx, models.AuditLogFilter{TargetID: &target.ID}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, second.ID, entries[0].ID)
+		assert.Equal(t, first.ID, entries[1].ID)
+		assert.Equal(t, "true", entries[1].Details["verified"])
+		require.NotNil(t, entries[1].ActorID)
+		assert.Equal(t, admin.ID, *entries[1].ActorID)
+
+		count, err := auditRepo.Count(ctx, models.AuditLogFilter{TargetID: &target.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// 対象を指定しない場合はすべて返す
+		entries, err = auditRepo.List(ctx, models.AuditLogFilter{}, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+
+		count, err = auditRepo.Count(ctx, models.AuditLogFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	// 認証の記録とクライアントの情報による絞り込みのテスト
+	t.Run("AuthEventsAndFilter", func(t *testing.T) {
+		succeeded := models.NewAuthAuditLog(&target.ID, models.AuditLoginSucceeded, map[string]string{"method": "password"}).
+			WithClient("192.0.2.10", "test-agent/1.0")
+		require.NoError(t, auditRepo.Create(ctx, succeeded))
+
+		// アカウントを特定できなかった試行は操作したユーザーも対象もない
+		failed := models.NewAuthAuditLog(nil, models.AuditLoginFailed, map[string]string{"email": "unknown@example.com"}).
+			WithClient("198.51.100.7", "")
+		require.NoError(t, auditRepo.Create(ctx, failed))
+
+		entries, err := auditRepo.List(ctx, models.AuditLogFilter{Action: models.AuditLoginFailed}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, failed.ID, entries[0].ID)
+		assert.Nil(t, entries[0].ActorID)
+		assert.Nil(t, entries[0].TargetID)
+		assert.Empty(t, entries[0].TargetType)
+		assert.Equal(t, "198.51.100.7", entries[0].IPAddress)
+		assert.Equal(t, "unknown@example.com", entries[0].Details["email"])
+
+		// 操作したユーザーとIPアドレスの範囲で絞り込む
+		entries, err = auditRepo.List(ctx, models.AuditLogFilter{ActorID: &target.ID, IPAddress: "192.0.2.0/24"}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, succeeded.ID, entries[0].ID)
+		assert.Equal(t, "192.0.2.10", entries[0].IPAddress)
+		assert.Equal(t, "test-agent/1.0", entries[0].UserAgent)
+
+		// 期間で絞り込む
+		since := time.Now().UTC().Add(time.Hour)
+		count, err := auditRepo.Count(ctx, models.AuditLogFilter{Since: &since})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		until := time.Now().UTC().Add(time.Hour)
+		count, err = auditRepo.Count(ctx, models.AuditLogFilter{IPAddress: "198.51.100.7", Until: &until})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	// 記録は更新・削除できない
+	t.Run("AppendOnly", func(t *testing.T) {
+		entry := models.NewAuthAuditLog(&target.ID, models.AuditPasswordChange, nil)
+		require.NoError(t, auditRepo.Create(ctx, entry))
+
+		_, err := db.Pool.Exec(ctx, "UPDATE audit_logs SET action = 'auth.login' WHERE id = $1", entry.ID)
+		assert.Error(t, err)
+
+		_, err = db.Pool.Exec(ctx, "DELETE FROM audit_logs WHERE id = $1", entry.ID)
+		assert.Error(t, err)
+
+		count, err := auditRepo.Count(ctx, models.AuditLogFilter{Action: models.AuditPasswordChange})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	// 操作がロールバックされた場合は記録も残らない
+	t.Run("RolledBackWithOperation", func(t *testing.T) {
+		before, err := auditRepo.Count(ctx, models.AuditLogFilter{})
+		require.NoError(t, err)
+
+		err = txManager.DryRun(ctx, func(ctx context.Context) error {
+			return auditRepo.Create(ctx, models.NewAuditLog(admin.ID, models.AuditUserPasswordReset, models.AuditTargetUser, target.ID, nil))
+		})
+		require.NoError(t, err)
+
+		after, err := auditRepo.Count(ctx, models.AuditLogFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	// 操作した管理者が削除されても記録は残る
+	t.Run("ActorDeleted", func(t *testing.T) {
+		require.NoError(t, userRepo.Delete(ctx, admin.ID))
+
+		entries, err := auditRepo.List(ctx, models.AuditLogFilter{TargetID: &target.ID, Action: models.AuditUserSuspend}, 0, 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+		assert.Nil(t, entries[0].ActorID)
+	})
+}
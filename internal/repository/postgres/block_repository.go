@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type blockRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBlockRepository creates a new PostgreSQL implementation of BlockRepository
+func NewBlockRepository(db *pgxpool.Pool) interfaces.BlockRepository {
+	return &blockRepository{db: db}
+}
+
+func (r *blockRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	// 自分自身をブロックできないようにする
+	if blockerID == blockedID {
+		return errors.New("cannot block yourself")
+	}
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO blocks (blocker_id, blocked_id, created_at)
+		VALUES ($1, $2, NOW())
+	`
+
+	_, err = tx.Exec(ctx, query, blockerID, blockedID)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("user already blocked")
+		}
+		return err
+	}
+
+	// ブロック後は双方向ともフォロー関係を維持しない
+	if err := unfollowIfFollowing(ctx, tx, blockerID, blockedID); err != nil {
+		return err
+	}
+	if err := unfollowIfFollowing(ctx, tx, blockedID, blockerID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *blockRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	query := `
+		DELETE FROM blocks
+		WHERE blocker_id = $1 AND blocked_id = $2
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("block relationship not found")
+	}
+
+	return nil
+}
+
+func (r *blockRepository) IsBlocking(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE blocker_id = $1 AND blocked_id = $2
+		)
+	`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, blockerID, blockedID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *blockRepository) IsBlockedEither(ctx context.Context, userA, userB uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2)
+				OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, userA, userB).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *blockRepository) GetBlockedUsers(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT blocked_id FROM blocks
+		WHERE blocker_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryUserIDs(ctx, query, userID, limit, offset)
+}
+
+func (r *blockRepository) GetBlockRelatedUserIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT blocked_id FROM blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id = $1
+	`
+
+	return r.queryUserIDs(ctx, query, userID)
+}
+
+func (r *blockRepository) CountBlocked(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := "SELECT COUNT(*) FROM blocks WHERE blocker_id = $1"
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// queryUserIDs is a helper function to execute queries that return user ID lists
+func (r *blockRepository) queryUserIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	blockRepo := NewBlockRepository(db.Pool)
+	followRepo := NewFollowRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user1 := &models.User{
+		ID:        uuid.New(),
+		Username:  "user1",
+		Email:     "user1@example.com",
+		Password:  "hashedpassword",
+		Name:      "User 1",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	user2 := &models.User{
+		ID:        uuid.New(),
+		Username:  "user2",
+		Email:     "user2@example.com",
+		Password:  "hashedpassword",
+		Name:      "User 2",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	user3 := &models.User{
+		ID:        uuid.New(),
+		Username:  "user3",
+		Email:     "user3@example.com",
+		Password:  "hashedpassword",
+		Name:      "User 3",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	err := userRepo.Create(ctx, user1)
+	require.NoError(t, err)
+	err = userRepo.Create(ctx, user2)
+	require.NoError(t, err)
+	err = userRepo.Create(ctx, user3)
+	require.NoError(t, err)
+
+	// Block のテスト
+	t.Run("Block", func(t *testing.T) {
+		// 双方向にフォローしている状態からブロックする
+		require.NoError(t, followRepo.Follow(ctx, user1.ID, user2.ID))
+		require.NoError(t, followRepo.Follow(ctx, user2.ID, user1.ID))
+
+		err := blockRepo.Block(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+
+		// フォロー関係は双方向とも同じトランザクションで解除される
+		following, err := followRepo.IsFollowing(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+		assert.False(t, following)
+		following, err = followRepo.IsFollowing(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.False(t, following)
+
+		blocker, err := userRepo.GetByID(ctx, user1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, blocker.FollowerCount)
+		assert.Equal(t, 0, blocker.FollowingCount)
+
+		isBlocking, err := blockRepo.IsBlocking(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+		assert.True(t, isBlocking)
+
+		// 逆方向はブロックしていない
+		isBlocking, err = blockRepo.IsBlocking(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.False(t, isBlocking)
+
+		// 重複ブロック
+		err = blockRepo.Block(ctx, user1.ID, user2.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "user already blocked")
+
+		// 自分自身をブロックできないことを確認
+		err = blockRepo.Block(ctx, user1.ID, user1.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot block yourself")
+	})
+
+	// ブロック関係にあるユーザーを一覧・件数から除くテスト
+	t.Run("ExcludedFromQueries", func(t *testing.T) {
+		require.NoError(t, followRepo.Follow(ctx, user3.ID, user1.ID))
+		require.NoError(t, followRepo.Follow(ctx, user3.ID, user2.ID))
+
+		// user2から見たuser3のフォロー中のユーザーには、user2をブロックしたuser1を含めない
+		following, err := followRepo.GetFollowing(ctx, user3.ID, user2.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{user2.ID}, following)
+
+		count, err := followRepo.CountFollowing(ctx, user3.ID, user2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// 閲覧者を指定しない場合は除かない
+		count, err = followRepo.CountFollowing(ctx, user3.ID, uuid.Nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		post := models.NewPost(user1.ID, "blocked author", nil)
+		require.NoError(t, postRepo.Create(ctx, post))
+
+		posts, err := postRepo.GetByUserIDs(ctx, []uuid.UUID{user1.ID}, user2.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+
+		postCount, err := postRepo.CountByUserIDs(ctx, []uuid.UUID{user1.ID}, user2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), postCount)
+
+		posts, err = postRepo.GetByUserIDs(ctx, []uuid.UUID{user1.ID}, user3.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, posts, 1)
+	})
+
+	// IsBlockedEither のテスト
+	t.Run("IsBlockedEither", func(t *testing.T) {
+		blocked, err := blockRepo.IsBlockedEither(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+		assert.True(t, blocked)
+
+		blocked, err = blockRepo.IsBlockedEither(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.True(t, blocked)
+
+		blocked, err = blockRepo.IsBlockedEither(ctx, user2.ID, uuid.New())
+		require.NoError(t, err)
+		assert.False(t, blocked)
+	})
+
+	// 一覧取得のテスト
+	t.Run("GetBlockedUsers", func(t *testing.T) {
+		blocked, err := blockRepo.GetBlockedUsers(ctx, user1.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{user2.ID}, blocked)
+
+		related, err := blockRepo.GetBlockRelatedUserIDs(ctx, user2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{user1.ID}, related)
+
+		count, err := blockRepo.CountBlocked(ctx, user1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	// Unblock のテスト
+	t.Run("Unblock", func(t *testing.T) {
+		err := blockRepo.Unblock(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+
+		blocked, err := blockRepo.IsBlockedEither(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+		assert.False(t, blocked)
+
+		// 存在しないブロックの解除を試みる
+		err = blockRepo.Unblock(ctx, user1.ID, user2.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "block relationship not found")
+	})
+}
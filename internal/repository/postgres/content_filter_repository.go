@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const contentFilterRuleColumns = `id, pattern, action, rating, created_by, created_at, updated_at`
+
+type contentFilterRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewContentFilterRepository creates a new PostgreSQL implementation of ContentFilterRepository
+func NewContentFilterRepository(db *pgxpool.Pool) interfaces.ContentFilterRepository {
+	return &contentFilterRepository{db: db}
+}
+
+// Create stores a rule. Patterns are unique, so a duplicate is rejected.
+func (r *contentFilterRepository) Create(ctx context.Context, rule *models.ContentFilterRule) error {
+	query := `
+		INSERT INTO content_filter_rules (id, pattern, action, rating, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		rule.ID, rule.Pattern, rule.Action, rule.Rating, rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("content filter rule already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns a rule by its ID
+func (r *contentFilterRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ContentFilterRule, error) {
+	query := "SELECT " + contentFilterRuleColumns + " FROM content_filter_rules WHERE id = $1"
+
+	var rule models.ContentFilterRule
+	err := scanContentFilterRule(conn(ctx, r.db).QueryRow(ctx, query, id), &rule)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("content filter rule not found")
+		}
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// List returns every rule ordered by pattern
+func (r *contentFilterRepository) List(ctx context.Context) ([]*models.ContentFilterRule, error) {
+	query := "SELECT " + contentFilterRuleColumns + " FROM content_filter_rules ORDER BY pattern"
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]*models.ContentFilterRule, 0)
+	for rows.Next() {
+		var rule models.ContentFilterRule
+		if err := scanContentFilterRule(rows, &rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Update changes the pattern, action and rating of a rule
+func (r *contentFilterRepository) Update(ctx context.Context, rule *models.ContentFilterRule) error {
+	query := `
+		UPDATE content_filter_rules
+		SET pattern = $2, action = $3, rating = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	rule.UpdatedAt = time.Now().UTC()
+	result, err := conn(ctx, r.db).Exec(ctx, query, rule.ID, rule.Pattern, rule.Action, rule.Rating, rule.UpdatedAt)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("content filter rule already exists")
+		}
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("content filter rule not found")
+	}
+
+	return nil
+}
+
+// Delete removes a rule
+func (r *contentFilterRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM content_filter_rules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("content filter rule not found")
+	}
+
+	return nil
+}
+
+func scanContentFilterRule(row pgx.Row, rule *models.ContentFilterRule) error {
+	return row.Scan(
+		&rule.ID, &rule.Pattern, &rule.Action, &rule.Rating, &rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+}
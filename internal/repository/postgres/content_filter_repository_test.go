@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentFilterRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	filterRepo := NewContentFilterRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// ルールを作成する管理者
+	admin := &models.User{
+		ID:        uuid.New(),
+		Username:  "filteradmin",
+		Email:     "filteradmin@example.com",
+		Password:  "hashedpassword",
+		Name:      "Filter Admin",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, admin))
+
+	var rejectRule *models.ContentFilterRule
+
+	// Create と GetByID のテスト
+	t.Run("CreateAndGet", func(t *testing.T) {
+		rejectRule = models.NewContentFilterRule("  BadWord ", models.ContentFilterActionReject, models.ContentRatingGeneral, admin.ID)
+		require.NoError(t, filterRepo.Create(ctx, rejectRule))
+
+		rule, err := filterRepo.GetByID(ctx, rejectRule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "badword", rule.Pattern)
+		assert.Equal(t, models.ContentFilterActionReject, rule.Action)
+		require.NotNil(t, rule.CreatedBy)
+		assert.Equal(t, admin.ID, *rule.CreatedBy)
+
+		// 大文字・小文字だけが異なるパターンは重複になる
+		duplicate := models.NewContentFilterRule("BADWORD", models.ContentFilterActionFlag, models.ContentRatingGeneral, admin.ID)
+		err = filterRepo.Create(ctx, duplicate)
+		require.Error(t, err)
+		assert.Equal(t, "content filter rule already exists", err.Error())
+
+		_, err = filterRepo.GetByID(ctx, uuid.New())
+		require.Error(t, err)
+		assert.Equal(t, "content filter rule not found", err.Error())
+	})
+
+	// List と Update のテスト
+	t.Run("ListAndUpdate", func(t *testing.T) {
+		labelRule := models.NewContentFilterRule("gore", models.ContentFilterActionLabel, models.ContentRatingAdult, admin.ID)
+		require.NoError(t, filterRepo.Create(ctx, labelRule))
+
+		rules, err := filterRepo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, "badword", rules[0].Pattern)
+		assert.Equal(t, "gore", rules[1].Pattern)
+		assert.Equal(t, models.ContentRatingAdult, rules[1].Rating)
+
+		rejectRule.Action = models.ContentFilterActionFlag
+		require.NoError(t, filterRepo.Update(ctx, rejectRule))
+
+		rule, err := filterRepo.GetByID(ctx, rejectRule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ContentFilterActionFlag, rule.Action)
+
+		// 他のルールと同じパターンには変更できない
+		labelRule.Pattern = "badword"
+		err = filterRepo.Update(ctx, labelRule)
+		require.Error(t, err)
+		assert.Equal(t, "content filter rule already exists", err.Error())
+	})
+
+	// Delete のテスト
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, filterRepo.Delete(ctx, rejectRule.ID))
+
+		err := filterRepo.Delete(ctx, rejectRule.ID)
+		require.Error(t, err)
+		assert.Equal(t, "content filter rule not found", err.Error())
+
+		rules, err := filterRepo.List(ctx)
+		require.NoError(t, err)
+		assert.Len(t, rules, 1)
+	})
+}
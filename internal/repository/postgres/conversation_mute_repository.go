@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type conversationMuteRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewConversationMuteRepository creates a new PostgreSQL implementation of ConversationMuteRepository
+func NewConversationMuteRepository(db *pgxpool.Pool) interfaces.ConversationMuteRepository {
+	return &conversationMuteRepository{db: db}
+}
+
+func (r *conversationMuteRepository) Mute(ctx context.Context, userID, rootPostID uuid.UUID) error {
+	query := `
+		INSERT INTO conversation_mutes (user_id, root_post_id, created_at)
+		VALUES ($1, $2, NOW())
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, userID, rootPostID)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("conversation already muted")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *conversationMuteRepository) Unmute(ctx context.Context, userID, rootPostID uuid.UUID) error {
+	query := `
+		DELETE FROM conversation_mutes
+		WHERE user_id = $1 AND root_post_id = $2
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID, rootPostID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("conversation mute not found")
+	}
+
+	return nil
+}
+
+func (r *conversationMuteRepository) IsMuted(ctx context.Context, userID, rootPostID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM conversation_mutes
+			WHERE user_id = $1 AND root_post_id = $2
+		)
+	`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID, rootPostID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *conversationMuteRepository) GetMutedUserIDs(ctx context.Context, rootPostID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(userIDs) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	query := `
+		SELECT user_id FROM conversation_mutes
+		WHERE root_post_id = $1 AND user_id = ANY($2)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, rootPostID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var muted []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		muted = append(muted, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return muted, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationMuteRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	muteRepo := NewConversationMuteRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	author := &models.User{
+		ID:        uuid.New(),
+		Username:  "threadauthor",
+		Email:     "threadauthor@example.com",
+		Password:  "hashedpassword",
+		Name:      "Thread Author",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	participant := &models.User{
+		ID:        uuid.New(),
+		Username:  "participant",
+		Email:     "participant@example.com",
+		Password:  "hashedpassword",
+		Name:      "Participant",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	err := userRepo.Create(ctx, author)
+	require.NoError(t, err)
+	err = userRepo.Create(ctx, participant)
+	require.NoError(t, err)
+
+	// 会話のルート投稿を作成
+	root := models.NewPost(author.ID, "Thread root", nil)
+	err = postRepo.Create(ctx, root)
+	require.NoError(t, err)
+
+	// Mute のテスト
+	t.Run("Mute", func(t *testing.T) {
+		err := muteRepo.Mute(ctx, author.ID, root.ID)
+		require.NoError(t, err)
+
+		muted, err := muteRepo.IsMuted(ctx, author.ID, root.ID)
+		require.NoError(t, err)
+		assert.True(t, muted)
+
+		muted, err = muteRepo.IsMuted(ctx, participant.ID, root.ID)
+		require.NoError(t, err)
+		assert.False(t, muted)
+
+		// 二重にミュートすることはできない
+		err = muteRepo.Mute(ctx, author.ID, root.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "conversation already muted")
+	})
+
+	// GetMutedUserIDs のテスト
+	t.Run("GetMutedUserIDs", func(t *testing.T) {
+		ids, err := muteRepo.GetMutedUserIDs(ctx, root.ID, []uuid.UUID{author.ID, participant.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{author.ID}, ids)
+
+		// 空のリストを渡した場合
+		ids, err = muteRepo.GetMutedUserIDs(ctx, root.ID, nil)
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	// Unmute のテスト
+	t.Run("Unmute", func(t *testing.T) {
+		err := muteRepo.Unmute(ctx, author.ID, root.ID)
+		require.NoError(t, err)
+
+		muted, err := muteRepo.IsMuted(ctx, author.ID, root.ID)
+		require.NoError(t, err)
+		assert.False(t, muted)
+
+		// ミュートしていない会話の解除
+		err = muteRepo.Unmute(ctx, author.ID, root.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "conversation mute not found")
+	})
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type counterRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCounterRepository creates a new PostgreSQL implementation of CounterRepository
+func NewCounterRepository(db *pgxpool.Pool) interfaces.CounterRepository {
+	return &counterRepository{db: db}
+}
+
+func (r *counterRepository) ReconcilePostCounts(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE posts p
+		SET like_count = a.like_count,
+			reply_count = a.reply_count,
+			repost_count = a.repost_count
+		FROM (
+			SELECT p.id,
+				COALESCE(l.count, 0) AS like_count,
+				COALESCE(r.count, 0) AS reply_count,
+				COALESCE(rp.count, 0) AS repost_count
+			FROM posts p
+			LEFT JOIN (
+				SELECT post_id, COUNT(*) AS count FROM likes GROUP BY post_id
+			) l ON l.post_id = p.id
+			LEFT JOIN (
+				SELECT reply_to_id, COUNT(*) AS count FROM posts
+				WHERE reply_to_id IS NOT NULL AND deleted_at IS NULL GROUP BY reply_to_id
+			) r ON r.reply_to_id = p.id
+			LEFT JOIN (
+				SELECT repost_id, COUNT(*) AS count FROM posts
+				WHERE repost_id IS NOT NULL AND deleted_at IS NULL GROUP BY repost_id
+			) rp ON rp.repost_id = p.id
+		) a
+		WHERE p.id = a.id
+			AND (p.like_count, p.reply_count, p.repost_count)
+				IS DISTINCT FROM (a.like_count, a.reply_count, a.repost_count)
+	`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *counterRepository) ReconcileUserCounts(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE users u
+		SET follower_count = a.follower_count,
+			following_count = a.following_count,
+			post_count = a.post_count
+		FROM (
+			SELECT u.id,
+				COALESCE(fr.count, 0) AS follower_count,
+				COALESCE(fg.count, 0) AS following_count,
+				COALESCE(p.count, 0) AS post_count
+			FROM users u
+			LEFT JOIN (
+				SELECT followee_id, COUNT(*) AS count FROM follows GROUP BY followee_id
+			) fr ON fr.followee_id = u.id
+			LEFT JOIN (
+				SELECT follower_id, COUNT(*) AS count FROM follows GROUP BY follower_id
+			) fg ON fg.follower_id = u.id
+			LEFT JOIN (
+				SELECT user_id, COUNT(*) AS count FROM posts
+				WHERE deleted_at IS NULL GROUP BY user_id
+			) p ON p.user_id = u.id
+		) a
+		WHERE u.id = a.id
+			AND (u.follower_count, u.following_count, u.post_count)
+				IS DISTINCT FROM (a.follower_count, a.following_count, a.post_count)
+	`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	likeRepo := NewLikeRepository(db.Pool)
+	followRepo := NewFollowRepository(db.Pool)
+	counterRepo := NewCounterRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	author := &models.User{
+		ID:        uuid.New(),
+		Username:  "counterauthor",
+		Email:     "counterauthor@example.com",
+		Password:  "hashedpassword",
+		Name:      "Counter Author",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	fan := &models.User{
+		ID:        uuid.New(),
+		Username:  "counterfan",
+		Email:     "counterfan@example.com",
+		Password:  "hashedpassword",
+		Name:      "Counter Fan",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, author))
+	require.NoError(t, userRepo.Create(ctx, fan))
+
+	post := models.NewPost(author.ID, "Counted post", nil)
+	require.NoError(t, postRepo.Create(ctx, post))
+
+	// Reconcile のテスト
+	t.Run("Reconcile", func(t *testing.T) {
+		require.NoError(t, likeRepo.Like(ctx, models.NewLike(fan.ID, post.ID)))
+		require.NoError(t, followRepo.Follow(ctx, fan.ID, author.ID))
+		reply := models.NewReply(fan.ID, post.ID, "Counted reply", nil)
+		require.NoError(t, postRepo.Create(ctx, reply))
+
+		// 直接書き換えてフォロワー数をずらす
+		drifted, err := userRepo.GetByID(ctx, author.ID)
+		require.NoError(t, err)
+		drifted.FollowerCount = 5
+		require.NoError(t, userRepo.Update(ctx, drifted))
+
+		// ずれた値を元のテーブルから再計算する
+		fixed, err := counterRepo.ReconcilePostCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), fixed)
+
+		updatedPost, err := postRepo.GetByID(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updatedPost.LikeCount)
+		assert.Equal(t, 1, updatedPost.ReplyCount)
+		assert.Equal(t, 0, updatedPost.RepostCount)
+
+		fixed, err = counterRepo.ReconcileUserCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), fixed)
+
+		updatedAuthor, err := userRepo.GetByID(ctx, author.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updatedAuthor.FollowerCount)
+		assert.Equal(t, 0, updatedAuthor.FollowingCount)
+		assert.Equal(t, 1, updatedAuthor.PostCount)
+
+		updatedFan, err := userRepo.GetByID(ctx, fan.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updatedFan.FollowingCount)
+		assert.Equal(t, 1, updatedFan.PostCount)
+
+		// 値が正しい場合は何も更新しない
+		fixed, err = counterRepo.ReconcilePostCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), fixed)
+		fixed, err = counterRepo.ReconcileUserCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), fixed)
+	})
+}
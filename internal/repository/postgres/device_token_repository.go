@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type deviceTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDeviceTokenRepository creates a new PostgreSQL implementation of DeviceTokenRepository
+func NewDeviceTokenRepository(db *pgxpool.Pool) interfaces.DeviceTokenRepository {
+	return &deviceTokenRepository{db: db}
+}
+
+// Upsert registers a device token, moving it to the new user when another user registered it before
+func (r *deviceTokenRepository) Upsert(ctx context.Context, device *models.DeviceToken) error {
+	query := `
+		INSERT INTO device_tokens (id, user_id, platform, token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (token) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	return conn(ctx, r.db).QueryRow(ctx, query,
+		device.ID, device.UserID, device.Platform, device.Token, device.CreatedAt, device.UpdatedAt,
+	).Scan(&device.ID, &device.CreatedAt)
+}
+
+// ListByUser returns a user's devices, oldest first
+func (r *deviceTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.DeviceToken, error) {
+	query := `
+		SELECT id, user_id, platform, token, created_at, updated_at
+		FROM device_tokens
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []*models.DeviceToken{}
+	for rows.Next() {
+		device := &models.DeviceToken{}
+		if err := rows.Scan(
+			&device.ID, &device.UserID, &device.Platform, &device.Token, &device.CreatedAt, &device.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, rows.Err()
+}
+
+// Delete removes one of a user's device tokens
+func (r *deviceTokenRepository) Delete(ctx context.Context, userID uuid.UUID, token string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM device_tokens WHERE user_id = $1 AND token = $2", userID, token)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("device token not found")
+	}
+
+	return nil
+}
+
+// DeleteByToken removes a device token the push service reported as invalid
+func (r *deviceTokenRepository) DeleteByToken(ctx context.Context, token string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM device_tokens WHERE token = $1", token)
+	return err
+}
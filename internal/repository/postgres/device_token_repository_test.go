@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceTokenRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	deviceRepo := NewDeviceTokenRepository(db.Pool)
+
+	ctx := context.Background()
+
+	newUser := func(username string) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  "hashedpassword",
+			Name:      username,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+
+	owner := newUser("deviceowner")
+	other := newUser("deviceother")
+
+	// Upsert と ListByUser のテスト
+	t.Run("UpsertAndList", func(t *testing.T) {
+		require.NoError(t, deviceRepo.Upsert(ctx, models.NewDeviceToken(owner.ID, models.DevicePlatformFCM, "fcm-token")))
+		require.NoError(t, deviceRepo.Upsert(ctx, models.NewDeviceToken(owner.ID, models.DevicePlatformAPNs, "apns-token")))
+
+		devices, err := deviceRepo.ListByUser(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Len(t, devices, 2)
+		assert.Equal(t, "fcm-token", devices[0].Token)
+		assert.Equal(t, models.DevicePlatformFCM, devices[0].Platform)
+		assert.Equal(t, "apns-token", devices[1].Token)
+		assert.Equal(t, models.DevicePlatformAPNs, devices[1].Platform)
+	})
+
+	// 同じトークンを別のユーザーが登録すると置き換わる
+	t.Run("UpsertMovesToken", func(t *testing.T) {
+		original, err := deviceRepo.ListByUser(ctx, owner.ID)
+		require.NoError(t, err)
+
+		device := models.NewDeviceToken(other.ID, models.DevicePlatformFCM, "fcm-token")
+		require.NoError(t, deviceRepo.Upsert(ctx, device))
+		assert.Equal(t, original[0].ID, device.ID)
+
+		devices, err := deviceRepo.ListByUser(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Len(t, devices, 1)
+		assert.Equal(t, "apns-token", devices[0].Token)
+
+		devices, err = deviceRepo.ListByUser(ctx, other.ID)
+		require.NoError(t, err)
+		require.Len(t, devices, 1)
+		assert.Equal(t, "fcm-token", devices[0].Token)
+	})
+
+	// Delete のテスト
+	t.Run("Delete", func(t *testing.T) {
+		// 他のユーザーのトークンは削除できない
+		err := deviceRepo.Delete(ctx, owner.ID, "fcm-token")
+		require.Error(t, err)
+		assert.Equal(t, "device token not found", err.Error())
+
+		require.NoError(t, deviceRepo.Delete(ctx, owner.ID, "apns-token"))
+
+		devices, err := deviceRepo.ListByUser(ctx, owner.ID)
+		require.NoError(t, err)
+		assert.Empty(t, devices)
+	})
+
+	// DeleteByToken のテスト
+	t.Run("DeleteByToken", func(t *testing.T) {
+		require.NoError(t, deviceRepo.DeleteByToken(ctx, "fcm-token"))
+		// 登録されていないトークンはエラーにしない
+		require.NoError(t, deviceRepo.DeleteByToken(ctx, "fcm-token"))
+
+		devices, err := deviceRepo.ListByUser(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Empty(t, devices)
+	})
+}
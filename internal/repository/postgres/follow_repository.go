@@ -3,9 +3,13 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -24,75 +28,321 @@ func (r *followRepository) Follow(ctx context.Context, followerID, followeeID uu
 		return errors.New("cannot follow yourself")
 	}
 
-	query := `
-		INSERT INTO follows (follower_id, followee_id, created_at)
-		VALUES ($1, $2, NOW())
-	`
+	return r.recordAndProject(ctx, models.NewFollowEvent(followerID, followeeID, models.FollowEventFollow), "already following")
+}
+
+func (r *followRepository) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	return r.recordAndProject(ctx, models.NewFollowEvent(followerID, followeeID, models.FollowEventUnfollow), "follow relationship not found")
+}
 
-	_, err := r.db.Exec(ctx, query, followerID, followeeID)
+// recordAndProject appends the event and applies it to follows in the same transaction
+// If the event does not change follows, nothing is recorded and notAppliedMessage is returned as an error
+func (r *followRepository) recordAndProject(ctx context.Context, event *models.FollowEvent, notAppliedMessage string) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	// フォロワー数とフォロー数を更新
-	updateFollowerCount := `
-		UPDATE users SET follower_count = follower_count + 1
-		WHERE id = $1
+	if err := insertFollowEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	applied, err := applyFollowEvent(ctx, tx, event)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New(notAppliedMessage)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *followRepository) AppendEvent(ctx context.Context, event *models.FollowEvent) error {
+	if event.FollowerID == event.FolloweeID {
+		return errors.New("cannot follow yourself")
+	}
+
+	return insertFollowEvent(ctx, conn(ctx, r.db), event)
+}
+
+func (r *followRepository) ProjectPending(ctx context.Context, limit int) (int, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 複数のワーカーが同じイベントを反映しないようにロックする
+	query := `
+		SELECT seq, follower_id, followee_id, event_type, occurred_at
+		FROM follow_events
+		WHERE projected_at IS NULL
+		ORDER BY seq
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
 	`
-	updateFollowingCount := `
-		UPDATE users SET following_count = following_count + 1
-		WHERE id = $1
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []*models.FollowEvent
+	for rows.Next() {
+		event := &models.FollowEvent{}
+		if err := rows.Scan(&event.Seq, &event.FollowerID, &event.FolloweeID, &event.Type, &event.OccurredAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	supersededQuery := `
+		SELECT EXISTS (
+			SELECT 1 FROM follow_events
+			WHERE follower_id = $1 AND followee_id = $2 AND seq > $3 AND projected_at IS NOT NULL
+		)
 	`
 
-	_, err = r.db.Exec(ctx, updateFollowerCount, followeeID)
+	for _, event := range events {
+		// より新しいイベントが反映済みの場合、このイベントでfollowsを変更すると状態が巻き戻ってしまう
+		var superseded bool
+		if err := tx.QueryRow(ctx, supersededQuery, event.FollowerID, event.FolloweeID, event.Seq).Scan(&superseded); err != nil {
+			return 0, err
+		}
+
+		if superseded {
+			if err := markFollowEventProjected(ctx, tx, event.Seq); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if _, err := applyFollowEvent(ctx, tx, event); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+func (r *followRepository) RebuildProjection(ctx context.Context) (int64, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 作り直している間にフォロー・フォロー解除が反映されないようにする（読み取りは続けられる）
+	if _, err := tx.Exec(ctx, `LOCK TABLE follows IN EXCLUSIVE MODE`); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM follows`); err != nil {
+		return 0, err
 	}
 
-	_, err = r.db.Exec(ctx, updateFollowingCount, followerID)
+	// フォロー関係ごとに最新のイベントがフォローであればフォロー中とし、その日時をフォローした日時とする
+	rebuildQuery := `
+		INSERT INTO follows (follower_id, followee_id, created_at)
+		SELECT follower_id, followee_id, occurred_at
+		FROM (
+			SELECT DISTINCT ON (follower_id, followee_id) follower_id, followee_id, event_type, occurred_at
+			FROM follow_events
+			ORDER BY follower_id, followee_id, seq DESC
+		) latest
+		WHERE event_type = 'follow' AND follower_id <> followee_id
+	`
+
+	result, err := tx.Exec(ctx, rebuildQuery)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE follow_events SET projected_at = NOW() WHERE projected_at IS NULL`); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
 	}
 
-	return nil
+	return result.RowsAffected(), nil
 }
 
-func (r *followRepository) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+func (r *followRepository) GetFollowedSince(ctx context.Context, followerID, followeeID uuid.UUID) (*time.Time, error) {
 	query := `
-		DELETE FROM follows
+		SELECT created_at FROM follows
 		WHERE follower_id = $1 AND followee_id = $2
 	`
 
-	result, err := r.db.Exec(ctx, query, followerID, followeeID)
+	var followedSince time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, query, followerID, followeeID).Scan(&followedSince)
 	if err != nil {
-		return err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	if result.RowsAffected() == 0 {
-		return errors.New("follow relationship not found")
-	}
+	return &followedSince, nil
+}
 
-	// フォロワー数とフォロー数を更新
-	updateFollowerCount := `
-		UPDATE users SET follower_count = GREATEST(follower_count - 1, 0)
-		WHERE id = $1
+func (r *followRepository) GetChurn(ctx context.Context, followeeID *uuid.UUID, from, to time.Time) ([]*models.FollowChurnDay, error) {
+	query := `
+		SELECT
+			(occurred_at AT TIME ZONE 'UTC')::date AS day,
+			COUNT(*) FILTER (WHERE event_type = 'follow'),
+			COUNT(*) FILTER (WHERE event_type = 'unfollow')
+		FROM follow_events
+		WHERE occurred_at >= $2 AND occurred_at < $3
+			AND ($1::uuid IS NULL OR followee_id = $1)
+		GROUP BY day
+		ORDER BY day
 	`
-	updateFollowingCount := `
-		UPDATE users SET following_count = GREATEST(following_count - 1, 0)
-		WHERE id = $1
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, followeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []*models.FollowChurnDay
+	for rows.Next() {
+		day := &models.FollowChurnDay{}
+		if err := rows.Scan(&day.Date, &day.Gained, &day.Lost); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+// insertFollowEvent appends the event and sets its sequence number
+func insertFollowEvent(ctx context.Context, db dbtx, event *models.FollowEvent) error {
+	query := `
+		INSERT INTO follow_events (follower_id, followee_id, event_type, occurred_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING seq
 	`
 
-	_, err = r.db.Exec(ctx, updateFollowerCount, followeeID)
+	return db.QueryRow(ctx, query, event.FollowerID, event.FolloweeID, event.Type, event.OccurredAt).Scan(&event.Seq)
+}
+
+// applyFollowEvent applies the event to follows and the follow counts, and marks it as projected
+// It returns false if follows was already in the state the event leads to
+func applyFollowEvent(ctx context.Context, db dbtx, event *models.FollowEvent) (bool, error) {
+	var result pgconn.CommandTag
+	var err error
+	delta := 1
+	switch event.Type {
+	case models.FollowEventFollow:
+		// フォローした日時はイベントの日時とする
+		query := `
+			INSERT INTO follows (follower_id, followee_id, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (follower_id, followee_id) DO NOTHING
+		`
+		result, err = db.Exec(ctx, query, event.FollowerID, event.FolloweeID, event.OccurredAt)
+	case models.FollowEventUnfollow:
+		query := `
+			DELETE FROM follows
+			WHERE follower_id = $1 AND followee_id = $2
+		`
+		result, err = db.Exec(ctx, query, event.FollowerID, event.FolloweeID)
+		delta = -1
+	default:
+		return false, errors.New("unknown follow event type")
+	}
 	if err != nil {
+		return false, err
+	}
+
+	// 後続の文は1回の往復でまとめて送信する
+	batch := &pgx.Batch{}
+	applied := result.RowsAffected() > 0
+	if applied {
+		// フォロワー数とフォロー数を同じトランザクションで更新
+		queueFollowCounts(batch, event.FollowerID, event.FolloweeID, delta)
+
+		// クライアントの差分同期のために記録
+		syncEventType := models.SyncEventFollowed
+		if event.Type == models.FollowEventUnfollow {
+			syncEventType = models.SyncEventUnfollowed
+		}
+		queueSyncEvent(batch, syncEventType, event.FollowerID, &event.FolloweeID, nil)
+	}
+	batch.Queue(markFollowEventProjectedQuery, event.Seq)
+
+	if err := db.SendBatch(ctx, batch).Close(); err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// unfollowIfFollowing records and applies an unfollow event if followerID follows followeeID
+// Nothing is recorded if there is no follow to remove
+func unfollowIfFollowing(ctx context.Context, db dbtx, followerID, followeeID uuid.UUID) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM follows
+			WHERE follower_id = $1 AND followee_id = $2
+		)
+	`
+
+	var following bool
+	if err := db.QueryRow(ctx, query, followerID, followeeID).Scan(&following); err != nil {
 		return err
 	}
+	if !following {
+		return nil
+	}
 
-	_, err = r.db.Exec(ctx, updateFollowingCount, followerID)
-	if err != nil {
+	event := models.NewFollowEvent(followerID, followeeID, models.FollowEventUnfollow)
+	if err := insertFollowEvent(ctx, db, event); err != nil {
 		return err
 	}
+	_, err := applyFollowEvent(ctx, db, event)
+	return err
+}
 
-	return nil
+const markFollowEventProjectedQuery = `UPDATE follow_events SET projected_at = NOW() WHERE seq = $1`
+
+// markFollowEventProjected records that the event has been applied to follows
+func markFollowEventProjected(ctx context.Context, db execer, seq int64) error {
+	_, err := db.Exec(ctx, markFollowEventProjectedQuery, seq)
+	return err
+}
+
+// queueFollowCounts queues adding delta to the followee's follower count and the follower's following count
+func queueFollowCounts(batch *pgx.Batch, followerID, followeeID uuid.UUID, delta int) {
+	updateFollowerCount := `
+		UPDATE users SET follower_count = GREATEST(follower_count + $2, 0)
+		WHERE id = $1
+	`
+	updateFollowingCount := `
+		UPDATE users SET following_count = GREATEST(following_count + $2, 0)
+		WHERE id = $1
+	`
+
+	batch.Queue(updateFollowerCount, followeeID, delta)
+	batch.Queue(updateFollowingCount, followerID, delta)
 }
 
 func (r *followRepository) IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
@@ -104,7 +354,7 @@ func (r *followRepository) IsFollowing(ctx context.Context, followerID, followee
 	`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, followerID, followeeID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, followerID, followeeID).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -112,15 +362,48 @@ func (r *followRepository) IsFollowing(ctx context.Context, followerID, followee
 	return exists, nil
 }
 
-func (r *followRepository) GetFollowers(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+func (r *followRepository) IsFollowingBatch(ctx context.Context, followerID uuid.UUID, followeeIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	following := make(map[uuid.UUID]bool)
+	if len(followeeIDs) == 0 {
+		return following, nil
+	}
+
+	query := `
+		SELECT followee_id FROM follows
+		WHERE follower_id = $1 AND followee_id = ANY($2)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, followerID, followeeIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var followeeID uuid.UUID
+		if err := rows.Scan(&followeeID); err != nil {
+			return nil, err
+		}
+		following[followeeID] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return following, nil
+}
+
+func (r *followRepository) GetFollowers(ctx context.Context, userID, viewerID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
 	query := `
 		SELECT follower_id FROM follows
-		WHERE followee_id = $1
+		WHERE followee_id = $1 AND follower_id NOT IN (` + inactiveUserIDs + `)
+			AND follower_id NOT IN (` + blockRelatedUserIDs("$2") + `)
 		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, viewerID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -142,15 +425,16 @@ func (r *followRepository) GetFollowers(ctx context.Context, userID uuid.UUID, o
 	return followers, nil
 }
 
-func (r *followRepository) GetFollowing(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+func (r *followRepository) GetFollowing(ctx context.Context, userID, viewerID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
 	query := `
 		SELECT followee_id FROM follows
-		WHERE follower_id = $1
+		WHERE follower_id = $1 AND followee_id NOT IN (` + inactiveUserIDs + `)
+			AND followee_id NOT IN (` + blockRelatedUserIDs("$2") + `)
 		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, viewerID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -172,11 +456,48 @@ func (r *followRepository) GetFollowing(ctx context.Context, userID uuid.UUID, o
 	return following, nil
 }
 
-func (r *followRepository) CountFollowers(ctx context.Context, userID uuid.UUID) (int64, error) {
-	query := "SELECT COUNT(*) FROM follows WHERE followee_id = $1"
+// mutualFollowersCondition selects follows of $1 whose follower $2 also follows
+var mutualFollowersCondition = `
+	FROM follows f
+	JOIN follows v ON v.followee_id = f.follower_id AND v.follower_id = $2
+	WHERE f.followee_id = $1 AND f.follower_id NOT IN (` + inactiveUserIDs + `)
+		AND f.follower_id NOT IN (` + blockRelatedUserIDs("$2") + `)
+`
+
+func (r *followRepository) GetMutualFollowers(ctx context.Context, userID, viewerID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT f.follower_id` + mutualFollowersCondition + `
+		ORDER BY f.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, viewerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var followers []uuid.UUID
+	for rows.Next() {
+		var followerID uuid.UUID
+		if err := rows.Scan(&followerID); err != nil {
+			return nil, err
+		}
+		followers = append(followers, followerID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return followers, nil
+}
+
+func (r *followRepository) CountMutualFollowers(ctx context.Context, userID, viewerID uuid.UUID) (int64, error) {
+	query := "SELECT COUNT(*)" + mutualFollowersCondition
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID, viewerID).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -184,11 +505,31 @@ func (r *followRepository) CountFollowers(ctx context.Context, userID uuid.UUID)
 	return count, nil
 }
 
-func (r *followRepository) CountFollowing(ctx context.Context, userID uuid.UUID) (int64, error) {
-	query := "SELECT COUNT(*) FROM follows WHERE follower_id = $1"
+func (r *followRepository) CountFollowers(ctx context.Context, userID, viewerID uuid.UUID) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM follows
+		WHERE followee_id = $1 AND follower_id NOT IN (` + inactiveUserIDs + `)
+			AND follower_id NOT IN (` + blockRelatedUserIDs("$2") + `)
+	`
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID, viewerID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *followRepository) CountFollowing(ctx context.Context, userID, viewerID uuid.UUID) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM follows
+		WHERE follower_id = $1 AND followee_id NOT IN (` + inactiveUserIDs + `)
+			AND followee_id NOT IN (` + blockRelatedUserIDs("$2") + `)
+	`
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID, viewerID).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
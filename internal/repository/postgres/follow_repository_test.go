@@ -82,6 +82,14 @@ func TestFollowRepository(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 1, updatedUser2.FollowerCount)
 
+		// 重複したフォローは失敗し、カウントも変わらない
+		err = followRepo.Follow(ctx, user1.ID, user2.ID)
+		assert.Error(t, err)
+
+		updatedUser2, err = userRepo.GetByID(ctx, user2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updatedUser2.FollowerCount)
+
 		// 自分自身をフォローできないことを確認
 		err = followRepo.Follow(ctx, user1.ID, user1.ID)
 		assert.Error(t, err)
@@ -120,14 +128,14 @@ func TestFollowRepository(t *testing.T) {
 		require.NoError(t, err)
 
 		// フォロワー一覧を取得
-		followers, err := followRepo.GetFollowers(ctx, user2.ID, 0, 10)
+		followers, err := followRepo.GetFollowers(ctx, user2.ID, uuid.Nil, 0, 10)
 		require.NoError(t, err)
 		assert.Len(t, followers, 1)
 		assert.Equal(t, user1.ID, followers[0])
 
 		// 存在しないユーザーのフォロワー一覧
 		nonexistentID := uuid.New()
-		followers, err = followRepo.GetFollowers(ctx, nonexistentID, 0, 10)
+		followers, err = followRepo.GetFollowers(ctx, nonexistentID, uuid.Nil, 0, 10)
 		require.NoError(t, err)
 		assert.Empty(t, followers)
 	})
@@ -135,14 +143,14 @@ func TestFollowRepository(t *testing.T) {
 	// GetFollowing のテスト
 	t.Run("GetFollowing", func(t *testing.T) {
 		// フォロー中一覧を取得
-		following, err := followRepo.GetFollowing(ctx, user1.ID, 0, 10)
+		following, err := followRepo.GetFollowing(ctx, user1.ID, uuid.Nil, 0, 10)
 		require.NoError(t, err)
 		assert.Len(t, following, 1)
 		assert.Equal(t, user2.ID, following[0])
 
 		// 存在しないユーザーのフォロー中一覧
 		nonexistentID := uuid.New()
-		following, err = followRepo.GetFollowing(ctx, nonexistentID, 0, 10)
+		following, err = followRepo.GetFollowing(ctx, nonexistentID, uuid.Nil, 0, 10)
 		require.NoError(t, err)
 		assert.Empty(t, following)
 	})
@@ -160,26 +168,183 @@ func TestFollowRepository(t *testing.T) {
 		assert.False(t, isFollowing)
 	})
 
+	// IsFollowingBatch のテスト
+	t.Run("IsFollowingBatch", func(t *testing.T) {
+		following, err := followRepo.IsFollowingBatch(ctx, user1.ID, []uuid.UUID{user2.ID, uuid.New()})
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]bool{user2.ID: true}, following)
+
+		// 空の場合
+		following, err = followRepo.IsFollowingBatch(ctx, user1.ID, nil)
+		require.NoError(t, err)
+		assert.Empty(t, following)
+	})
+
 	// Count のテスト
 	t.Run("Count", func(t *testing.T) {
 		// フォロワー数の確認
-		count, err := followRepo.CountFollowers(ctx, user2.ID)
+		count, err := followRepo.CountFollowers(ctx, user2.ID, uuid.Nil)
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), count)
 
 		// フォロー中数の確認
-		count, err = followRepo.CountFollowing(ctx, user1.ID)
+		count, err = followRepo.CountFollowing(ctx, user1.ID, uuid.Nil)
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), count)
 
 		// 存在しないユーザーのカウント
 		nonexistentID := uuid.New()
-		count, err = followRepo.CountFollowers(ctx, nonexistentID)
+		count, err = followRepo.CountFollowers(ctx, nonexistentID, uuid.Nil)
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), count)
 
-		count, err = followRepo.CountFollowing(ctx, nonexistentID)
+		count, err = followRepo.CountFollowing(ctx, nonexistentID, uuid.Nil)
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), count)
 	})
+
+	// GetFollowedSince のテスト
+	t.Run("GetFollowedSince", func(t *testing.T) {
+		followedSince, err := followRepo.GetFollowedSince(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+		require.NotNil(t, followedSince)
+		assert.WithinDuration(t, time.Now(), *followedSince, time.Minute)
+
+		// フォローしていない場合はnil
+		followedSince, err = followRepo.GetFollowedSince(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.Nil(t, followedSince)
+	})
+
+	// ProjectPending のテスト
+	t.Run("ProjectPending", func(t *testing.T) {
+		// 記録しただけのイベントはまだ反映されない
+		event := models.NewFollowEvent(user2.ID, user1.ID, models.FollowEventFollow)
+		require.NoError(t, followRepo.AppendEvent(ctx, event))
+		assert.NotZero(t, event.Seq)
+
+		isFollowing, err := followRepo.IsFollowing(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.False(t, isFollowing)
+
+		projected, err := followRepo.ProjectPending(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, projected)
+
+		isFollowing, err = followRepo.IsFollowing(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.True(t, isFollowing)
+
+		updatedUser1, err := userRepo.GetByID(ctx, user1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updatedUser1.FollowerCount)
+
+		// 反映済みのイベントは再度反映されない
+		projected, err = followRepo.ProjectPending(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 0, projected)
+
+		// より新しいイベントが反映済みの場合は古いイベントでfollowsを変更しない
+		stale := models.NewFollowEvent(user2.ID, user1.ID, models.FollowEventUnfollow)
+		require.NoError(t, followRepo.AppendEvent(ctx, stale))
+		require.NoError(t, followRepo.Unfollow(ctx, user2.ID, user1.ID))
+		require.NoError(t, followRepo.Follow(ctx, user2.ID, user1.ID))
+
+		projected, err = followRepo.ProjectPending(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, projected)
+
+		isFollowing, err = followRepo.IsFollowing(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.True(t, isFollowing)
+
+		// 自分自身へのイベントは記録できない
+		err = followRepo.AppendEvent(ctx, models.NewFollowEvent(user1.ID, user1.ID, models.FollowEventFollow))
+		assert.Error(t, err)
+	})
+
+	// RebuildProjection のテスト
+	t.Run("RebuildProjection", func(t *testing.T) {
+		followedSince, err := followRepo.GetFollowedSince(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+		require.NotNil(t, followedSince)
+
+		// followsを直接変更してイベントとずらす
+		_, err = db.Pool.Exec(ctx, `DELETE FROM follows WHERE follower_id = $1`, user1.ID)
+		require.NoError(t, err)
+
+		rebuilt, err := followRepo.RebuildProjection(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), rebuilt)
+
+		// フォローした日時はイベントの日時が引き継がれる
+		restored, err := followRepo.GetFollowedSince(ctx, user1.ID, user2.ID)
+		require.NoError(t, err)
+		require.NotNil(t, restored)
+		assert.WithinDuration(t, *followedSince, *restored, time.Millisecond)
+
+		isFollowing, err := followRepo.IsFollowing(ctx, user2.ID, user1.ID)
+		require.NoError(t, err)
+		assert.True(t, isFollowing)
+	})
+
+	// GetChurn のテスト
+	t.Run("GetChurn", func(t *testing.T) {
+		from := time.Now().UTC().Truncate(24 * time.Hour)
+		to := from.AddDate(0, 0, 1)
+
+		// user2 は Follow・Unfollow・Follow で2回フォローされ、1回フォロー解除された
+		days, err := followRepo.GetChurn(ctx, &user2.ID, from, to)
+		require.NoError(t, err)
+		require.Len(t, days, 1)
+		assert.Equal(t, int64(2), days[0].Gained)
+		assert.Equal(t, int64(1), days[0].Lost)
+
+		// サービス全体（記録しただけのイベントも含む）
+		days, err = followRepo.GetChurn(ctx, nil, from, to)
+		require.NoError(t, err)
+		require.Len(t, days, 1)
+		assert.Equal(t, int64(4), days[0].Gained)
+		assert.Equal(t, int64(3), days[0].Lost)
+
+		// 期間外
+		days, err = followRepo.GetChurn(ctx, &user2.ID, to, to.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		assert.Empty(t, days)
+	})
+
+	// GetMutualFollowers のテスト
+	t.Run("GetMutualFollowers", func(t *testing.T) {
+		user3 := &models.User{
+			ID:        uuid.New(),
+			Username:  "user3",
+			Email:     "user3@example.com",
+			Password:  "hashedpassword",
+			Name:      "User 3",
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user3))
+
+		// user2 のフォロワー（user1）を user3 はまだフォローしていない
+		mutual, err := followRepo.GetMutualFollowers(ctx, user2.ID, user3.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, mutual)
+
+		require.NoError(t, followRepo.Follow(ctx, user3.ID, user1.ID))
+
+		mutual, err = followRepo.GetMutualFollowers(ctx, user2.ID, user3.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{user1.ID}, mutual)
+
+		count, err := followRepo.CountMutualFollowers(ctx, user2.ID, user3.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// user3 自身はフォロワーに含まれても共通のフォロワーには含まれない
+		require.NoError(t, followRepo.Follow(ctx, user3.ID, user2.ID))
+		count, err = followRepo.CountMutualFollowers(ctx, user2.ID, user3.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
 }
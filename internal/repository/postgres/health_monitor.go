@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HealthMonitor は接続プールを定期的にPingし、フェイルオーバーなどでデータベースに接続できなくなった場合に
+// レディネスを落として接続を張り直します
+// 接続できない状態が続く間はロードバランサーがトラフィックを外せるよう、Readyはfalseを返します
+type HealthMonitor struct {
+	pool     *pgxpool.Pool
+	interval time.Duration
+	// レディネスを落として接続を張り直すまでに許容する連続失敗回数
+	failureThreshold int
+	log              logger.Logger
+
+	ready    atomic.Bool
+	failures int
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+var _ interfaces.HealthChecker = (*HealthMonitor)(nil)
+
+// NewHealthMonitor は新しいHealthMonitorを作成します（作成時点では接続できているものとして扱います）
+func NewHealthMonitor(pool *pgxpool.Pool, interval time.Duration, failureThreshold int, log logger.Logger) *HealthMonitor {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if failureThreshold <= 0 {
+		failureThreshold = 3
+	}
+
+	m := &HealthMonitor{
+		pool:             pool,
+		interval:         interval,
+		failureThreshold: failureThreshold,
+		log:              log,
+		stopCh:           make(chan struct{}),
+		doneCh:           make(chan struct{}),
+	}
+	m.ready.Store(true)
+	return m
+}
+
+// Start は定期的な確認を開始します
+func (m *HealthMonitor) Start() {
+	go m.run()
+}
+
+// Stop は定期的な確認を停止します
+func (m *HealthMonitor) Stop() {
+	close(m.stopCh)
+	<-m.doneCh
+}
+
+// Ready はデータベースに接続できる状態かを返します
+func (m *HealthMonitor) Ready() bool {
+	return m.ready.Load()
+}
+
+// run は停止されるまで一定間隔で接続を確認します
+func (m *HealthMonitor) run() {
+	defer close(m.doneCh)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.check()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// check はPingの結果に応じてレディネスを切り替え、失敗が続く場合は接続を張り直します
+func (m *HealthMonitor) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.interval)
+	defer cancel()
+
+	if err := m.pool.Ping(ctx); err != nil {
+		m.failures++
+		m.log.Warn("データベースの死活確認に失敗しました", "error", err, "failures", m.failures)
+
+		if m.failures%m.failureThreshold != 0 {
+			return
+		}
+		if m.ready.Swap(false) {
+			m.log.Error("データベースに接続できないため、レディネスを落としました", "failures", m.failures)
+		}
+		// 旧プライマリへの接続を破棄し、次の取得時に新しいプライマリへ接続し直す
+		m.pool.Reset()
+		m.log.Info("データベースの接続プールをリセットしました")
+		return
+	}
+
+	m.failures = 0
+	if !m.ready.Swap(true) {
+		m.log.Info("データベースへの接続が回復したため、レディネスを戻しました")
+	}
+}
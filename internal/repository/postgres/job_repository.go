@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const jobColumns = `id, kind, payload, status, unique_key, attempts, max_attempts, last_error,
+			run_at, locked_until, created_at, updated_at, finished_at`
+
+type jobRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewJobRepository creates a new PostgreSQL implementation of JobRepository
+func NewJobRepository(db *pgxpool.Pool) interfaces.JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Create inserts a job, joining the transaction in ctx so it only runs once the transaction commits.
+// A job with the unique key of an existing job is rejected.
+func (r *jobRepository) Create(ctx context.Context, job *models.Job) error {
+	query := `
+		INSERT INTO jobs (id, kind, payload, status, unique_key, max_attempts, run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (unique_key) DO NOTHING
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query,
+		job.ID,
+		job.Kind,
+		job.Payload,
+		job.Status,
+		job.UniqueKey,
+		job.MaxAttempts,
+		job.RunAt,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("job already exists")
+	}
+
+	return nil
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE id = $1
+	`
+
+	job, err := scanJob(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("job not found")
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ClaimNext leases the oldest due job of the given kinds, including running jobs whose lease has expired
+func (r *jobRepository) ClaimNext(ctx context.Context, kinds []string, lease time.Duration) (*models.Job, error) {
+	// 他のワーカーが取得中のジョブは飛ばす
+	query := `
+		UPDATE jobs
+		SET status = 'running',
+			attempts = attempts + 1,
+			locked_until = $2,
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM jobs
+			WHERE kind = ANY($1)
+				AND ((status = 'pending' AND run_at <= NOW())
+					OR (status = 'running' AND locked_until < NOW()))
+			ORDER BY run_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(conn(ctx, r.db).QueryRow(ctx, query, kinds, time.Now().Add(lease)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) Complete(ctx context.Context, id uuid.UUID) error {
+	return r.finish(ctx, `
+		UPDATE jobs
+		SET status = 'completed',
+			locked_until = NULL,
+			updated_at = NOW(),
+			finished_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`, id)
+}
+
+func (r *jobRepository) Retry(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	return r.finish(ctx, `
+		UPDATE jobs
+		SET status = 'pending',
+			run_at = $2,
+			last_error = $3,
+			locked_until = NULL,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`, id, runAt, lastError)
+}
+
+func (r *jobRepository) Fail(ctx context.Context, id uuid.UUID, lastError string) error {
+	return r.finish(ctx, `
+		UPDATE jobs
+		SET status = 'failed',
+			last_error = $2,
+			locked_until = NULL,
+			updated_at = NOW(),
+			finished_at = NOW()
+		WHERE id = $1 AND status = 'running'
+	`, id, lastError)
+}
+
+func (r *jobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM jobs WHERE finished_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// finish updates the state of a running job and reports a job that is no longer running as not found
+func (r *jobRepository) finish(ctx context.Context, query string, args ...interface{}) error {
+	result, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("job not found")
+	}
+
+	return nil
+}
+
+// scanJob scans a row selected with jobColumns
+func scanJob(row pgx.Row) (*models.Job, error) {
+	job := &models.Job{}
+	err := row.Scan(
+		&job.ID,
+		&job.Kind,
+		&job.Payload,
+		&job.Status,
+		&job.UniqueKey,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.LockedUntil,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	jobRepo := NewJobRepository(db.Pool)
+
+	ctx := context.Background()
+	payload := json.RawMessage(`{"user_id":"1"}`)
+	scheduled := models.NewJob("scheduled", payload, 3, time.Now().Add(time.Hour))
+
+	// 登録・取得・完了のテスト
+	t.Run("ClaimAndComplete", func(t *testing.T) {
+		job := models.NewJob("export", payload, 3, time.Now())
+		require.NoError(t, jobRepo.Create(ctx, job))
+
+		// 登録していない種類のジョブは取得しない
+		claimed, err := jobRepo.ClaimNext(ctx, []string{"other"}, time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, claimed)
+
+		claimed, err = jobRepo.ClaimNext(ctx, []string{"export"}, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, job.ID, claimed.ID)
+		assert.Equal(t, models.JobRunning, claimed.Status)
+		assert.Equal(t, 1, claimed.Attempts)
+		assert.JSONEq(t, string(payload), string(claimed.Payload))
+
+		// 取得中のジョブは再取得されない
+		again, err := jobRepo.ClaimNext(ctx, []string{"export"}, time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, again)
+
+		require.NoError(t, jobRepo.Complete(ctx, job.ID))
+		stored, err := jobRepo.GetByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobCompleted, stored.Status)
+		assert.NotNil(t, stored.FinishedAt)
+		assert.True(t, stored.IsFinished())
+
+		// 実行中でないジョブは完了にできない
+		err = jobRepo.Complete(ctx, job.ID)
+		require.Error(t, err)
+		assert.Equal(t, "job not found", err.Error())
+	})
+
+	// 実行予定時刻前のジョブは取得しない
+	t.Run("ScheduledJob", func(t *testing.T) {
+		require.NoError(t, jobRepo.Create(ctx, scheduled))
+
+		claimed, err := jobRepo.ClaimNext(ctx, []string{"scheduled"}, time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, claimed)
+	})
+
+	// 同じキーのジョブは1件のみ登録する
+	t.Run("UniqueKey", func(t *testing.T) {
+		key := "scheduled@2024-01-01T00:00:00Z"
+		job := models.NewJob("unique", payload, 3, time.Now().Add(time.Hour))
+		job.UniqueKey = &key
+		require.NoError(t, jobRepo.Create(ctx, job))
+
+		duplicate := models.NewJob("unique", payload, 3, time.Now().Add(time.Hour))
+		duplicate.UniqueKey = &key
+		err := jobRepo.Create(ctx, duplicate)
+		require.Error(t, err)
+		assert.Equal(t, "job already exists", err.Error())
+
+		// キーを指定しないジョブは何件でも登録できる
+		require.NoError(t, jobRepo.Create(ctx, models.NewJob("unique", payload, 3, time.Now().Add(time.Hour))))
+		require.NoError(t, jobRepo.Create(ctx, models.NewJob("unique", payload, 3, time.Now().Add(time.Hour))))
+	})
+
+	// 再試行・期限切れ・失敗のテスト
+	t.Run("RetryAndFail", func(t *testing.T) {
+		job := models.NewJob("retry", payload, 3, time.Now())
+		require.NoError(t, jobRepo.Create(ctx, job))
+
+		_, err := jobRepo.ClaimNext(ctx, []string{"retry"}, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, jobRepo.Retry(ctx, job.ID, time.Now().Add(-time.Second), "temporary"))
+
+		// 取得の期限が切れたジョブは再取得される
+		claimed, err := jobRepo.ClaimNext(ctx, []string{"retry"}, -time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, 2, claimed.Attempts)
+		require.NotNil(t, claimed.LastError)
+		assert.Equal(t, "temporary", *claimed.LastError)
+
+		claimed, err = jobRepo.ClaimNext(ctx, []string{"retry"}, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, 3, claimed.Attempts)
+
+		require.NoError(t, jobRepo.Fail(ctx, job.ID, "gave up"))
+		stored, err := jobRepo.GetByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobFailed, stored.Status)
+	})
+
+	// 終了したジョブの削除のテスト
+	t.Run("DeleteFinishedBefore", func(t *testing.T) {
+		deleted, err := jobRepo.DeleteFinishedBefore(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		// 実行待ちのジョブは残る
+		stored, err := jobRepo.GetByID(ctx, scheduled.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobPending, stored.Status)
+	})
+}
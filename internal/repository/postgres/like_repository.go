@@ -7,6 +7,8 @@ import (
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -20,57 +22,65 @@ func NewLikeRepository(db *pgxpool.Pool) interfaces.LikeRepository {
 }
 
 func (r *likeRepository) Like(ctx context.Context, like *models.Like) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		INSERT INTO likes (user_id, post_id, created_at)
 		VALUES ($1, $2, $3)
 	`
 
-	_, err := r.db.Exec(ctx, query, like.UserID, like.PostID, like.CreatedAt)
-	if err != nil {
-		return err
-	}
-
-	// いいね数を更新
+	// いいね数を同じトランザクションで更新（1回の往復でまとめて送信する）
 	updateLikeCount := `
 		UPDATE posts SET like_count = like_count + 1
 		WHERE id = $1
 	`
 
-	_, err = r.db.Exec(ctx, updateLikeCount, like.PostID)
-	if err != nil {
+	batch := &pgx.Batch{}
+	batch.Queue(query, like.UserID, like.PostID, like.CreatedAt)
+	batch.Queue(updateLikeCount, like.PostID)
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
 func (r *likeRepository) Unlike(ctx context.Context, userID, postID uuid.UUID) error {
-	query := `
-		DELETE FROM likes
-		WHERE user_id = $1 AND post_id = $2
-	`
-
-	result, err := r.db.Exec(ctx, query, userID, postID)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	if result.RowsAffected() == 0 {
-		return errors.New("like relationship not found")
-	}
+	query := `
+		DELETE FROM likes
+		WHERE user_id = $1 AND post_id = $2
+	`
 
-	// いいね数を更新
+	// いいね数を同じトランザクションで更新
+	// いいねがなかった場合はエラーを返し、ロールバックにより更新も取り消す
 	updateLikeCount := `
 		UPDATE posts SET like_count = GREATEST(like_count - 1, 0)
 		WHERE id = $1
 	`
 
-	_, err = r.db.Exec(ctx, updateLikeCount, postID)
-	if err != nil {
+	batch := &pgx.Batch{}
+	batch.Queue(query, userID, postID).Exec(func(result pgconn.CommandTag) error {
+		if result.RowsAffected() == 0 {
+			return errors.New("like relationship not found")
+		}
+		return nil
+	})
+	batch.Queue(updateLikeCount, postID)
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
 func (r *likeRepository) HasLiked(ctx context.Context, userID, postID uuid.UUID) (bool, error) {
@@ -82,7 +92,7 @@ func (r *likeRepository) HasLiked(ctx context.Context, userID, postID uuid.UUID)
 	`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, userID, postID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID, postID).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -90,6 +100,38 @@ func (r *likeRepository) HasLiked(ctx context.Context, userID, postID uuid.UUID)
 	return exists, nil
 }
 
+func (r *likeRepository) HasLikedBatch(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	liked := make(map[uuid.UUID]bool)
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+
+	query := `
+		SELECT post_id FROM likes
+		WHERE user_id = $1 AND post_id = ANY($2)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uuid.UUID
+		if err := rows.Scan(&postID); err != nil {
+			return nil, err
+		}
+		liked[postID] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return liked, nil
+}
+
 func (r *likeRepository) GetLikesByPostID(ctx context.Context, postID uuid.UUID, offset, limit int) ([]*models.Like, error) {
 	query := `
 		SELECT user_id, post_id, created_at
@@ -99,7 +141,7 @@ func (r *likeRepository) GetLikesByPostID(ctx context.Context, postID uuid.UUID,
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, query, postID, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, postID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +172,7 @@ func (r *likeRepository) GetLikesByUserID(ctx context.Context, userID uuid.UUID,
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +198,7 @@ func (r *likeRepository) CountLikesByPostID(ctx context.Context, postID uuid.UUI
 	query := "SELECT COUNT(*) FROM likes WHERE post_id = $1"
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, postID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, query, postID).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -168,7 +210,7 @@ func (r *likeRepository) CountLikesByUserID(ctx context.Context, userID uuid.UUI
 	query := "SELECT COUNT(*) FROM likes WHERE user_id = $1"
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -88,6 +88,38 @@ func TestLikeRepository(t *testing.T) {
 		updatedPost, err := postRepo.GetByID(ctx, post.ID)
 		require.NoError(t, err)
 		assert.Equal(t, 1, updatedPost.LikeCount)
+
+		// 重複したいいねは失敗し、いいね数も変わらない
+		err = likeRepo.Like(ctx, models.NewLike(user2.ID, post.ID))
+		assert.Error(t, err)
+
+		updatedPost, err = postRepo.GetByID(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updatedPost.LikeCount)
+	})
+
+	// HasLikedBatch のテスト
+	t.Run("HasLikedBatch", func(t *testing.T) {
+		otherPost := &models.Post{
+			ID:        uuid.New(),
+			UserID:    user1.ID,
+			Content:   "Other content",
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, postRepo.Create(ctx, otherPost))
+
+		liked, err := likeRepo.HasLikedBatch(ctx, user2.ID, []uuid.UUID{post.ID, otherPost.ID})
+		require.NoError(t, err)
+		assert.True(t, liked[post.ID])
+		assert.False(t, liked[otherPost.ID])
+
+		// いいねしていないユーザー
+		liked, err = likeRepo.HasLikedBatch(ctx, user1.ID, []uuid.UUID{post.ID, otherPost.ID})
+		require.NoError(t, err)
+		assert.Empty(t, liked)
+
+		require.NoError(t, postRepo.Delete(ctx, otherPost.ID))
 	})
 
 	// Unlike のテスト
@@ -176,4 +208,64 @@ func TestLikeRepository(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), count)
 	})
+
+	// GetLikedByUserID と CountLikedByUserID のテスト
+	t.Run("GetLikedByUserID", func(t *testing.T) {
+		older := models.NewPost(user1.ID, "Liked first", nil)
+		require.NoError(t, postRepo.Create(ctx, older))
+		newer := models.NewPost(user1.ID, "Liked later", nil)
+		require.NoError(t, postRepo.Create(ctx, newer))
+		deleted := models.NewPost(user1.ID, "Deleted after like", nil)
+		require.NoError(t, postRepo.Create(ctx, deleted))
+
+		for i, p := range []*models.Post{older, newer, deleted} {
+			like := models.NewLike(user2.ID, p.ID)
+			like.CreatedAt = time.Now().UTC().Add(time.Duration(i) * time.Minute)
+			require.NoError(t, likeRepo.Like(ctx, like))
+		}
+		require.NoError(t, postRepo.Delete(ctx, deleted.ID))
+
+		// いいねした日時の新しい順に返し、削除された投稿は含めない
+		posts, err := postRepo.GetLikedByUserID(ctx, user2.ID, 0, 10)
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, 0, len(posts))
+		for _, p := range posts {
+			ids = append(ids, p.ID)
+		}
+		assert.Equal(t, []uuid.UUID{newer.ID, older.ID, post.ID}, ids)
+
+		count, err := postRepo.CountLikedByUserID(ctx, user2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		// いいねしていないユーザーは空
+		posts, err = postRepo.GetLikedByUserID(ctx, uuid.New(), 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+
+	// GetInteractionCounts のテスト
+	t.Run("GetInteractionCounts", func(t *testing.T) {
+		since := time.Now().UTC().Add(-time.Hour)
+
+		// user2はこれまでにuser1の投稿4件にいいねしている（削除された投稿を含む）
+		counts, err := postRepo.GetInteractionCounts(ctx, user2.ID, []uuid.UUID{user1.ID}, since)
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]int{user1.ID: 4}, counts)
+
+		// 返信も投稿者とのやり取りとして数える
+		reply := models.NewReply(user2.ID, post.ID, "Reply to user1", nil)
+		require.NoError(t, postRepo.Create(ctx, reply))
+		counts, err = postRepo.GetInteractionCounts(ctx, user2.ID, []uuid.UUID{user1.ID, user2.ID}, since)
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]int{user1.ID: 5}, counts)
+
+		// 期間より前のやり取りと、指定していない投稿者は数えない
+		counts, err = postRepo.GetInteractionCounts(ctx, user2.ID, []uuid.UUID{user1.ID}, time.Now().UTC().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+		counts, err = postRepo.GetInteractionCounts(ctx, user2.ID, []uuid.UUID{uuid.New()}, since)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
 }
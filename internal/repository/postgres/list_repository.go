@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listColumns is the column list shared by the list SELECT queries
+const listColumns = `id, owner_id, name, description, is_private, member_count, created_at, updated_at`
+
+type listRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewListRepository creates a new PostgreSQL implementation of ListRepository
+func NewListRepository(db *pgxpool.Pool) interfaces.ListRepository {
+	return &listRepository{db: db}
+}
+
+func (r *listRepository) Create(ctx context.Context, list *models.List) error {
+	// バリデーションチェック
+	if list == nil {
+		return errors.New("list cannot be nil")
+	}
+	if list.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	query := `
+		INSERT INTO lists (` + listColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		list.ID, list.OwnerID, list.Name, list.Description,
+		list.IsPrivate, list.MemberCount, list.CreatedAt, list.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *listRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.List, error) {
+	query := `
+		SELECT ` + listColumns + `
+		FROM lists WHERE id = $1
+	`
+
+	var list models.List
+	err := scanList(conn(ctx, r.db).QueryRow(ctx, query, id), &list)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("list not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (r *listRepository) Update(ctx context.Context, list *models.List) error {
+	// バリデーションチェック
+	if list == nil {
+		return errors.New("list cannot be nil")
+	}
+	if list.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	query := `
+		UPDATE lists SET
+			name = $1, description = $2, is_private = $3, updated_at = $4
+		WHERE id = $5
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query,
+		list.Name, list.Description, list.IsPrivate, list.UpdatedAt, list.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("list not found")
+	}
+
+	return nil
+}
+
+func (r *listRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM lists WHERE id = $1"
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("list not found")
+	}
+
+	return nil
+}
+
+func (r *listRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*models.List, error) {
+	query := `
+		SELECT ` + listColumns + `
+		FROM lists
+		WHERE owner_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, ownerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []*models.List
+	for rows.Next() {
+		var list models.List
+		if err := scanList(rows, &list); err != nil {
+			return nil, err
+		}
+		lists = append(lists, &list)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (r *listRepository) CountByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	query := "SELECT COUNT(*) FROM lists WHERE owner_id = $1"
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, ownerID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *listRepository) AddMember(ctx context.Context, listID, userID uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO list_members (list_id, user_id, created_at)
+		VALUES ($1, $2, NOW())
+	`
+
+	// メンバー数も1回の往復でまとめて更新
+	batch := &pgx.Batch{}
+	batch.Queue(query, listID, userID)
+	batch.Queue("UPDATE lists SET member_count = member_count + 1 WHERE id = $1", listID)
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			switch pgErr.Code {
+			case "23505":
+				return errors.New("user already in list")
+			case "23503":
+				return errors.New("list or user not found")
+			}
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *listRepository) RemoveMember(ctx context.Context, listID, userID uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		DELETE FROM list_members
+		WHERE list_id = $1 AND user_id = $2
+	`
+
+	// メンバー数も1回の往復でまとめて更新
+	// メンバーでなかった場合はエラーを返し、ロールバックにより更新も取り消す
+	batch := &pgx.Batch{}
+	batch.Queue(query, listID, userID).Exec(func(result pgconn.CommandTag) error {
+		if result.RowsAffected() == 0 {
+			return errors.New("list member not found")
+		}
+		return nil
+	})
+	batch.Queue("UPDATE lists SET member_count = GREATEST(member_count - 1, 0) WHERE id = $1", listID)
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *listRepository) IsMember(ctx context.Context, listID, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM list_members
+			WHERE list_id = $1 AND user_id = $2
+		)
+	`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, listID, userID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *listRepository) GetMembers(ctx context.Context, listID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT user_id FROM list_members
+		WHERE list_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryUserIDs(ctx, query, listID, limit, offset)
+}
+
+func (r *listRepository) GetAllMemberIDs(ctx context.Context, listID uuid.UUID) ([]uuid.UUID, error) {
+	query := "SELECT user_id FROM list_members WHERE list_id = $1"
+
+	return r.queryUserIDs(ctx, query, listID)
+}
+
+// queryUserIDs is a helper function to execute queries that return user ID lists
+func (r *listRepository) queryUserIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// scanList scans a row selected with listColumns into list
+func scanList(row pgx.Row, list *models.List) error {
+	return row.Scan(
+		&list.ID, &list.OwnerID, &list.Name, &list.Description,
+		&list.IsPrivate, &list.MemberCount, &list.CreatedAt, &list.UpdatedAt,
+	)
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	listRepo := NewListRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	owner := &models.User{
+		ID:        uuid.New(),
+		Username:  "listowner",
+		Email:     "listowner@example.com",
+		Password:  "hashedpassword",
+		Name:      "List Owner",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	member := &models.User{
+		ID:        uuid.New(),
+		Username:  "listmember",
+		Email:     "listmember@example.com",
+		Password:  "hashedpassword",
+		Name:      "List Member",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	err := userRepo.Create(ctx, owner)
+	require.NoError(t, err)
+	err = userRepo.Create(ctx, member)
+	require.NoError(t, err)
+
+	testList := models.NewList(owner.ID, "Go developers", "Gophers I follow", false)
+
+	// Create のテスト
+	t.Run("Create", func(t *testing.T) {
+		err := listRepo.Create(ctx, testList)
+		require.NoError(t, err)
+
+		// 名前が空のリスト
+		err = listRepo.Create(ctx, models.NewList(owner.ID, "", "", false))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "name cannot be empty")
+	})
+
+	// GetByID のテスト
+	t.Run("GetByID", func(t *testing.T) {
+		list, err := listRepo.GetByID(ctx, testList.ID)
+		require.NoError(t, err)
+		assert.Equal(t, testList.Name, list.Name)
+		assert.Equal(t, testList.Description, list.Description)
+		assert.Equal(t, owner.ID, list.OwnerID)
+
+		// 存在しないIDでの取得を試みる
+		_, err = listRepo.GetByID(ctx, uuid.New())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "list not found")
+	})
+
+	// Update のテスト
+	t.Run("Update", func(t *testing.T) {
+		testList.Name = "Gophers"
+		testList.IsPrivate = true
+		err := listRepo.Update(ctx, testList)
+		require.NoError(t, err)
+
+		updated, err := listRepo.GetByID(ctx, testList.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Gophers", updated.Name)
+		assert.True(t, updated.IsPrivate)
+		assert.False(t, updated.CanView(member.ID))
+		assert.True(t, updated.CanView(owner.ID))
+	})
+
+	// メンバー管理のテスト
+	t.Run("Members", func(t *testing.T) {
+		err := listRepo.AddMember(ctx, testList.ID, member.ID)
+		require.NoError(t, err)
+
+		// 重複追加
+		err = listRepo.AddMember(ctx, testList.ID, member.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "user already in list")
+
+		isMember, err := listRepo.IsMember(ctx, testList.ID, member.ID)
+		require.NoError(t, err)
+		assert.True(t, isMember)
+
+		members, err := listRepo.GetMembers(ctx, testList.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{member.ID}, members)
+
+		list, err := listRepo.GetByID(ctx, testList.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, list.MemberCount)
+	})
+
+	// リストメンバーの投稿取得のテスト
+	t.Run("MemberPosts", func(t *testing.T) {
+		post := models.NewPost(member.ID, "Hello from a list member", nil)
+		err := postRepo.Create(ctx, post)
+		require.NoError(t, err)
+
+		memberIDs, err := listRepo.GetAllMemberIDs(ctx, testList.ID)
+		require.NoError(t, err)
+
+		posts, err := postRepo.GetByUserIDs(ctx, memberIDs, uuid.Nil, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, post.ID, posts[0].ID)
+
+		count, err := postRepo.CountByUserIDs(ctx, memberIDs, uuid.Nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// メンバーがいない場合
+		posts, err = postRepo.GetByUserIDs(ctx, nil, uuid.Nil, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+
+	// GetByOwnerID のテスト
+	t.Run("GetByOwnerID", func(t *testing.T) {
+		lists, err := listRepo.GetByOwnerID(ctx, owner.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, lists, 1)
+		assert.Equal(t, testList.ID, lists[0].ID)
+
+		count, err := listRepo.CountByOwnerID(ctx, owner.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	// RemoveMember のテスト
+	t.Run("RemoveMember", func(t *testing.T) {
+		err := listRepo.RemoveMember(ctx, testList.ID, member.ID)
+		require.NoError(t, err)
+
+		list, err := listRepo.GetByID(ctx, testList.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, list.MemberCount)
+
+		// 存在しないメンバーの削除を試みる
+		err = listRepo.RemoveMember(ctx, testList.ID, member.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "list member not found")
+	})
+
+	// Delete のテスト
+	t.Run("Delete", func(t *testing.T) {
+		err := listRepo.Delete(ctx, testList.ID)
+		require.NoError(t, err)
+
+		_, err = listRepo.GetByID(ctx, testList.ID)
+		assert.Error(t, err)
+
+		err = listRepo.Delete(ctx, testList.ID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,354 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const mediaObjectColumns = `id, hash, url, size, mime_type, blurhash, width, height, duration_ms, thumbnail_url, ref_count, created_at, updated_at`
+
+// unreferencedMediaCondition matches objects that no post, post edit, profile image or
+// upload session refers to. The post given by postParam and its edits are not counted,
+// so a post that is dropping the media does not keep it alive.
+func unreferencedMediaCondition(postParam string) string {
+	return `NOT EXISTS (SELECT 1 FROM posts p WHERE p.media_urls @> jsonb_build_array(m.url) AND p.id IS DISTINCT FROM ` + postParam + `)
+	AND NOT EXISTS (SELECT 1 FROM post_edits e WHERE e.previous_media_urls @> jsonb_build_array(m.url) AND e.post_id IS DISTINCT FROM ` + postParam + `)
+	AND NOT EXISTS (SELECT 1 FROM users u WHERE u.profile_image = m.url)
+	AND NOT EXISTS (SELECT 1 FROM upload_sessions s WHERE s.url = m.url)`
+}
+
+// orphanedMediaCondition matches objects that have not been acquired since $1 and that
+// nothing refers to. Uploads that were never attached to a post keep their reference
+// forever, and released objects that were still referenced keep their row, so these
+// are swept separately.
+var orphanedMediaCondition = `m.updated_at < $1
+	AND ` + unreferencedMediaCondition("NULL")
+
+type mediaRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewMediaRepository creates a new PostgreSQL implementation of MediaRepository
+func NewMediaRepository(db *pgxpool.Pool) interfaces.MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+// Acquire adds a reference to the object with the given content hash and counts
+// the upload toward the user who uploaded it again
+func (r *mediaRepository) Acquire(ctx context.Context, hash string, userID uuid.UUID) (*models.MediaObject, error) {
+	query := `
+		WITH object AS (
+			UPDATE media_objects SET ref_count = ref_count + 1, updated_at = $2
+			WHERE hash = $1
+			RETURNING ` + mediaObjectColumns + `
+		), upload AS (
+			INSERT INTO media_uploads (media_id, user_id, upload_count, created_at, updated_at)
+			SELECT id, $3, 1, $2, $2 FROM object
+			ON CONFLICT (media_id, user_id) DO UPDATE SET
+				upload_count = media_uploads.upload_count + 1,
+				updated_at = EXCLUDED.updated_at
+		)
+		SELECT ` + mediaObjectColumns + ` FROM object`
+
+	var object models.MediaObject
+	err := scanMediaObject(conn(ctx, r.db).QueryRow(ctx, query, hash, time.Now().UTC(), userID), &object)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("media object not found")
+		}
+		return nil, err
+	}
+
+	return &object, nil
+}
+
+// Create stores a new object uploaded by uploaderID. Hashes and URLs are unique, so a duplicate is rejected.
+func (r *mediaRepository) Create(ctx context.Context, object *models.MediaObject, uploaderID uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO media_objects (
+			id, hash, url, size, mime_type, blurhash, width, height, duration_ms, thumbnail_url, ref_count, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		object.ID, object.Hash, object.URL, object.Size, object.MimeType, object.Blurhash, object.Width, object.Height,
+		object.DurationMS, object.ThumbnailURL, object.RefCount, object.CreatedAt, object.UpdatedAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("media object already exists")
+		}
+		return err
+	}
+
+	uploadQuery := `
+		INSERT INTO media_uploads (media_id, user_id, upload_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+	`
+	if _, err := tx.Exec(ctx, uploadQuery, object.ID, uploaderID, object.RefCount, object.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListByURLs returns the objects stored at the given URLs. URLs of files
+// uploaded before deduplication or hosted elsewhere are skipped.
+func (r *mediaRepository) ListByURLs(ctx context.Context, urls []string) ([]*models.MediaObject, error) {
+	objects := make([]*models.MediaObject, 0, len(urls))
+	if len(urls) == 0 {
+		return objects, nil
+	}
+
+	query := "SELECT " + mediaObjectColumns + " FROM media_objects WHERE url = ANY($1)"
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, urls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var object models.MediaObject
+		if err := scanMediaObject(rows, &object); err != nil {
+			return nil, err
+		}
+		objects = append(objects, &object)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// ListUploadedURLs returns the URLs among urls whose objects were uploaded by the user.
+// A user who released every upload still counts as an uploader.
+func (r *mediaRepository) ListUploadedURLs(ctx context.Context, userID uuid.UUID, urls []string) ([]string, error) {
+	uploaded := make([]string, 0, len(urls))
+	if len(urls) == 0 {
+		return uploaded, nil
+	}
+
+	query := `
+		SELECT m.url FROM media_objects m
+		JOIN media_uploads u ON u.media_id = m.id
+		WHERE u.user_id = $1 AND m.url = ANY($2)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, urls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		uploaded = append(uploaded, url)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return uploaded, nil
+}
+
+// Release drops one of userID's uploads of the object with the given URL. Only an upload by
+// the user releases a reference, so removing media someone else uploaded leaves the count as
+// it is. When no reference is left and nothing other than postID refers to the URL, the file
+// is deleted through deleteObject before the row is removed, while the row lock keeps
+// concurrent uploads of the same content from reusing it.
+func (r *mediaRepository) Release(
+	ctx context.Context,
+	url string,
+	userID, postID uuid.UUID,
+	deleteObject func(ctx context.Context) error,
+) (*models.MediaRelease, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var object models.MediaObject
+	query := "SELECT " + mediaObjectColumns + " FROM media_objects WHERE url = $1 FOR UPDATE"
+	if err := scanMediaObject(tx.QueryRow(ctx, query, url), &object); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("media object not found")
+		}
+		return nil, err
+	}
+	release := &models.MediaRelease{Object: &object}
+
+	now := time.Now().UTC()
+	uploadQuery := `
+		UPDATE media_uploads SET upload_count = upload_count - 1, updated_at = $3
+		WHERE media_id = $1 AND user_id = $2 AND upload_count > 0
+	`
+	result, err := tx.Exec(ctx, uploadQuery, object.ID, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected() > 0 {
+		release.Refunded = true
+		query := `
+			UPDATE media_objects SET ref_count = GREATEST(ref_count - 1, 0), updated_at = $2
+			WHERE id = $1
+			RETURNING ` + mediaObjectColumns
+		if err := scanMediaObject(tx.QueryRow(ctx, query, object.ID, now), &object); err != nil {
+			return nil, err
+		}
+	}
+
+	if object.RefCount == 0 {
+		// 同じファイルを使う他の投稿やプロフィール画像が残っている場合は削除しない（参照されなくなった後に掃除される）
+		unreferencedQuery := `
+			SELECT EXISTS (
+				SELECT 1 FROM media_objects m
+				WHERE m.id = $1 AND ` + unreferencedMediaCondition("$2") + `
+			)
+		`
+		if err := tx.QueryRow(ctx, unreferencedQuery, object.ID, postID).Scan(&release.Deleted); err != nil {
+			return nil, err
+		}
+	}
+
+	if release.Deleted {
+		if err := deleteObject(ctx); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM media_objects WHERE id = $1", object.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// ListOrphaned returns up to limit objects matching orphanedMediaCondition, oldest first
+func (r *mediaRepository) ListOrphaned(ctx context.Context, before time.Time, limit int) ([]*models.MediaObject, error) {
+	query := `
+		SELECT ` + mediaObjectColumns + `
+		FROM media_objects m
+		WHERE ` + orphanedMediaCondition + `
+		ORDER BY m.updated_at ASC
+		LIMIT $2
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []*models.MediaObject
+	for rows.Next() {
+		var object models.MediaObject
+		if err := scanMediaObject(rows, &object); err != nil {
+			return nil, err
+		}
+		objects = append(objects, &object)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// DeleteOrphan deletes an object that is still orphaned and returns the uploads that still
+// counted toward their users' storage usage. The file is deleted through deleteObject while
+// the row is locked, so a concurrent upload of the same content waits and stores the file
+// again instead of reusing the deleted one.
+func (r *mediaRepository) DeleteOrphan(
+	ctx context.Context,
+	id uuid.UUID,
+	before time.Time,
+	deleteObject func(ctx context.Context) error,
+) ([]*models.MediaUpload, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT m.id FROM media_objects m
+		WHERE ` + orphanedMediaCondition + ` AND m.id = $2
+		FOR UPDATE
+	`
+
+	var lockedID uuid.UUID
+	if err := tx.QueryRow(ctx, query, before, id).Scan(&lockedID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("media object not found")
+		}
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, "DELETE FROM media_uploads WHERE media_id = $1 RETURNING media_id, user_id, upload_count", id)
+	if err != nil {
+		return nil, err
+	}
+	var uploads []*models.MediaUpload
+	for rows.Next() {
+		var upload models.MediaUpload
+		if err := rows.Scan(&upload.MediaID, &upload.UserID, &upload.UploadCount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if upload.UploadCount > 0 {
+			uploads = append(uploads, &upload)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := deleteObject(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM media_objects WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+func scanMediaObject(row pgx.Row, object *models.MediaObject) error {
+	return row.Scan(
+		&object.ID, &object.Hash, &object.URL, &object.Size, &object.MimeType, &object.Blurhash, &object.Width, &object.Height,
+		&object.DurationMS, &object.ThumbnailURL, &object.RefCount, &object.CreatedAt, &object.UpdatedAt,
+	)
+}
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	mediaRepo := NewMediaRepository(db.Pool)
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "mediauser",
+		Email:     "mediauser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Media User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	other := &models.User{
+		ID:        uuid.New(),
+		Username:  "othermediauser",
+		Email:     "othermediauser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Other Media User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+	require.NoError(t, userRepo.Create(ctx, other))
+
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	url := "http://localhost:8080/uploads/media/9f/" + hash + ".png"
+
+	// Create と Acquire のテスト
+	t.Run("CreateAndAcquire", func(t *testing.T) {
+		_, err := mediaRepo.Acquire(ctx, hash, user.ID)
+		require.Error(t, err)
+		assert.Equal(t, "media object not found", err.Error())
+
+		object := models.NewMediaObject(hash, url, 1024)
+		object.MimeType = "image/png"
+		object.Blurhash = "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+		object.Width = 640
+		object.Height = 480
+		object.DurationMS = 12500
+		object.ThumbnailURL = "http://localhost:8080/uploads/media/9f/" + hash + "_thumb.jpg"
+		require.NoError(t, mediaRepo.Create(ctx, object, user.ID))
+
+		// 同じ内容のメディアは重複になる
+		err = mediaRepo.Create(ctx, models.NewMediaObject(hash, url, 1024), other.ID)
+		require.Error(t, err)
+		assert.Equal(t, "media object already exists", err.Error())
+
+		// 別のユーザーが同じ内容をアップロードした場合も同じメディアを使う
+		object, err = mediaRepo.Acquire(ctx, hash, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, url, object.URL)
+		assert.Equal(t, int64(1024), object.Size)
+		assert.Equal(t, 2, object.RefCount)
+		assert.Equal(t, "image/png", object.MimeType)
+		assert.Equal(t, "LEHV6nWB2yk8pyo0adR*.7kCMdnj", object.Blurhash)
+		assert.Equal(t, 640, object.Width)
+		assert.Equal(t, 480, object.Height)
+		assert.Equal(t, 12500, object.DurationMS)
+		assert.Equal(t, "http://localhost:8080/uploads/media/9f/"+hash+"_thumb.jpg", object.ThumbnailURL)
+	})
+
+	// ListByURLs のテスト
+	t.Run("ListByURLs", func(t *testing.T) {
+		// 登録されていないURLは含まれない
+		objects, err := mediaRepo.ListByURLs(ctx, []string{url, "https://example.com/external.png"})
+		require.NoError(t, err)
+		require.Len(t, objects, 1)
+		assert.Equal(t, hash, objects[0].Hash)
+
+		objects, err = mediaRepo.ListByURLs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, objects)
+	})
+
+	// ListUploadedURLs のテスト
+	t.Run("ListUploadedURLs", func(t *testing.T) {
+		// 同じ内容をアップロードしたユーザーはどちらもアップロードしたユーザーになる
+		urls, err := mediaRepo.ListUploadedURLs(ctx, user.ID, []string{url, "https://example.com/external.png"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{url}, urls)
+
+		urls, err = mediaRepo.ListUploadedURLs(ctx, other.ID, []string{url})
+		require.NoError(t, err)
+		assert.Equal(t, []string{url}, urls)
+
+		// アップロードしていないユーザーのURLは含まれない
+		urls, err = mediaRepo.ListUploadedURLs(ctx, uuid.New(), []string{url})
+		require.NoError(t, err)
+		assert.Empty(t, urls)
+	})
+
+	// Release のテスト
+	t.Run("Release", func(t *testing.T) {
+		deleted := 0
+		deleteObject := func(ctx context.Context) error {
+			deleted++
+			return nil
+		}
+
+		// アップロードしていないユーザーが外しても参照数は変わらない
+		release, err := mediaRepo.Release(ctx, url, uuid.New(), uuid.Nil, deleteObject)
+		require.NoError(t, err)
+		assert.False(t, release.Refunded)
+		assert.False(t, release.Deleted)
+		assert.Equal(t, 2, release.Object.RefCount)
+
+		// 参照が残っている間はファイルを削除しない
+		release, err = mediaRepo.Release(ctx, url, other.ID, uuid.Nil, deleteObject)
+		require.NoError(t, err)
+		assert.True(t, release.Refunded)
+		assert.False(t, release.Deleted)
+		assert.Equal(t, 1, release.Object.RefCount)
+		assert.Equal(t, 0, deleted)
+
+		// アップロードした回数より多くは外せない
+		release, err = mediaRepo.Release(ctx, url, other.ID, uuid.Nil, deleteObject)
+		require.NoError(t, err)
+		assert.False(t, release.Refunded)
+		assert.Equal(t, 1, release.Object.RefCount)
+
+		// ファイルの削除に失敗した場合は参照数を戻す
+		_, err = mediaRepo.Release(ctx, url, user.ID, uuid.Nil, func(ctx context.Context) error {
+			return errors.New("storage unavailable")
+		})
+		require.Error(t, err)
+
+		release, err = mediaRepo.Release(ctx, url, user.ID, uuid.Nil, deleteObject)
+		require.NoError(t, err)
+		assert.True(t, release.Refunded)
+		assert.True(t, release.Deleted)
+		assert.Equal(t, 0, release.Object.RefCount)
+		assert.Equal(t, 1, deleted)
+
+		// 最後の参照を外すと行も削除される
+		_, err = mediaRepo.Release(ctx, url, user.ID, uuid.Nil, deleteObject)
+		require.Error(t, err)
+		assert.Equal(t, "media object not found", err.Error())
+
+		_, err = mediaRepo.Acquire(ctx, hash, user.ID)
+		require.Error(t, err)
+		assert.Equal(t, "media object not found", err.Error())
+	})
+
+	// 他の投稿から使われているメディアは参照数が0になっても削除しない
+	t.Run("ReleaseKeepsReferencedFile", func(t *testing.T) {
+		sharedHash := "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+		sharedURL := "http://localhost:8080/uploads/media/2c/" + sharedHash + ".png"
+		require.NoError(t, mediaRepo.Create(ctx, models.NewMediaObject(sharedHash, sharedURL, 512), user.ID))
+
+		first := models.NewPost(user.ID, "First", []string{sharedURL})
+		require.NoError(t, postRepo.Create(ctx, first))
+		second := models.NewPost(user.ID, "Second", []string{sharedURL})
+		require.NoError(t, postRepo.Create(ctx, second))
+
+		deleteObject := func(ctx context.Context) error {
+			t.Fatal("a file still used by another post was deleted")
+			return nil
+		}
+
+		// 1件目の投稿から外しても2件目の投稿が使っているため残る
+		release, err := mediaRepo.Release(ctx, sharedURL, user.ID, first.ID, deleteObject)
+		require.NoError(t, err)
+		assert.True(t, release.Refunded)
+		assert.Equal(t, 0, release.Object.RefCount)
+		assert.False(t, release.Deleted)
+
+		objects, err := mediaRepo.ListByURLs(ctx, []string{sharedURL})
+		require.NoError(t, err)
+		assert.Len(t, objects, 1)
+
+		// 1件目の投稿から取り除いた後も、編集履歴が使っているため2件目の投稿から外しても残る
+		_, err = postRepo.RemoveMedia(ctx, first.ID, user.ID, []string{sharedURL}, nil)
+		require.NoError(t, err)
+		release, err = mediaRepo.Release(ctx, sharedURL, user.ID, second.ID, deleteObject)
+		require.NoError(t, err)
+		assert.False(t, release.Refunded)
+		assert.False(t, release.Deleted)
+	})
+
+	// ListOrphaned と DeleteOrphan のテスト
+	t.Run("DeleteOrphan", func(t *testing.T) {
+		orphanHash := "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+		orphan := models.NewMediaObject(orphanHash, "http://localhost:8080/uploads/media/60/"+orphanHash+".png", 2048)
+		require.NoError(t, mediaRepo.Create(ctx, orphan, user.ID))
+		_, err := mediaRepo.Acquire(ctx, orphanHash, user.ID)
+		require.NoError(t, err)
+
+		// 猶予を過ぎていないメディアは含まれない
+		objects, err := mediaRepo.ListOrphaned(ctx, time.Now().Add(-time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, objects)
+
+		before := time.Now().Add(time.Minute)
+		objects, err = mediaRepo.ListOrphaned(ctx, before, 10)
+		require.NoError(t, err)
+		require.Len(t, objects, 1)
+		assert.Equal(t, orphan.ID, objects[0].ID)
+
+		// ファイルの削除に失敗した場合は行を残す
+		_, err = mediaRepo.DeleteOrphan(ctx, orphan.ID, before, func(ctx context.Context) error {
+			return errors.New("storage unavailable")
+		})
+		require.Error(t, err)
+
+		// 使用量に数えていたアップロードが返される
+		uploads, err := mediaRepo.DeleteOrphan(ctx, orphan.ID, before, func(ctx context.Context) error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, uploads, 1)
+		assert.Equal(t, user.ID, uploads[0].UserID)
+		assert.Equal(t, 2, uploads[0].UploadCount)
+
+		_, err = mediaRepo.DeleteOrphan(ctx, orphan.ID, before, func(ctx context.Context) error {
+			return nil
+		})
+		require.Error(t, err)
+		assert.Equal(t, "media object not found", err.Error())
+	})
+}
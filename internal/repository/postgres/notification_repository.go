@@ -23,12 +23,17 @@ func NewNotificationRepository(db *pgxpool.Pool) interfaces.NotificationReposito
 
 func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
 	query := `
-		INSERT INTO notifications (
-			id, user_id, actor_id, type, post_id, is_read, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		WITH inserted AS (
+			INSERT INTO notifications (
+				id, user_id, actor_id, type, post_id, is_read, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, user_id
+		)
+		INSERT INTO sync_events (event_type, user_id, subject_id)
+		SELECT 'notification_created', user_id, id FROM inserted
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := conn(ctx, r.db).Exec(ctx, query,
 		notification.ID, notification.UserID, notification.ActorID,
 		notification.Type, notification.PostID, notification.IsRead,
 		notification.CreatedAt,
@@ -44,7 +49,7 @@ func (r *notificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 	`
 
 	notification := &models.Notification{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&notification.ID, &notification.UserID, &notification.ActorID,
 		&notification.Type, &notification.PostID, &notification.IsRead,
 		&notification.CreatedAt,
@@ -61,12 +66,13 @@ func (r *notificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	query := `
 		SELECT id, user_id, actor_id, type, post_id, is_read, created_at
 		FROM notifications
-		WHERE user_id = $1
+		WHERE user_id = $1 AND actor_id NOT IN (` + inactiveUserIDs + `)
+			AND (post_id IS NULL OR post_id NOT IN (` + expiredPostIDs + `))
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -93,14 +99,115 @@ func (r *notificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	return notifications, nil
 }
 
+// GetGroupedByUserID merges likes and reposts per post and follows per day, and pages through the groups
+// The group key matches models.IsGroupedNotificationType and is "type:day[:post_id]", or the notification ID for other types
+func (r *notificationRepository) GetGroupedByUserID(ctx context.Context, userID uuid.UUID, offset, limit, actorPreview int) ([]*models.NotificationGroup, error) {
+	query := `
+		WITH keyed AS (
+			SELECT id, type, post_id, actor_id, is_read, created_at,
+				CASE WHEN type IN ('like', 'repost', 'follow')
+					THEN type || ':' || to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') || COALESCE(':' || post_id::text, '')
+					ELSE id::text
+				END AS group_key
+			FROM notifications
+			WHERE user_id = $1 AND actor_id NOT IN (` + inactiveUserIDs + `)
+				AND (post_id IS NULL OR post_id NOT IN (` + expiredPostIDs + `))
+		),
+		groups AS (
+			SELECT group_key,
+				(array_agg(id ORDER BY created_at DESC))[1] AS latest_id,
+				(array_agg(type))[1] AS type,
+				(array_agg(post_id))[1] AS post_id,
+				MAX(created_at) AS latest_at,
+				BOOL_AND(is_read) AS is_read,
+				COUNT(*) AS notification_count,
+				COUNT(DISTINCT actor_id) AS actor_count
+			FROM keyed
+			GROUP BY group_key
+			ORDER BY latest_at DESC, group_key
+			LIMIT $2 OFFSET $3
+		)
+		SELECT g.group_key, g.latest_id, g.type, g.post_id, g.latest_at, g.is_read, g.notification_count, g.actor_count,
+			ARRAY(
+				SELECT k.actor_id FROM keyed k
+				WHERE k.group_key = g.group_key
+				GROUP BY k.actor_id
+				ORDER BY MAX(k.created_at) DESC, k.actor_id
+				LIMIT $4
+			) AS actor_ids
+		FROM groups g
+		ORDER BY g.latest_at DESC, g.group_key
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, limit, offset, actorPreview)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []*models.NotificationGroup{}
+	for rows.Next() {
+		var key string
+		var latestID uuid.UUID
+		group := &models.NotificationGroup{}
+		if err := rows.Scan(
+			&key, &latestID, &group.Type, &group.PostID, &group.LatestAt, &group.Read,
+			&group.NotificationCount, &group.ActorCount, &group.ActorIDs,
+		); err != nil {
+			return nil, err
+		}
+
+		group.ID = latestID
+		if models.IsGroupedNotificationType(group.Type) {
+			group.ID = models.NotificationGroupID(userID, key)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
 func (r *notificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
 	query := `
-		UPDATE notifications
-		SET is_read = true
-		WHERE id = $1
+		WITH updated AS (
+			UPDATE notifications
+			SET is_read = true
+			WHERE id = $1
+			RETURNING id, user_id
+		)
+		INSERT INTO sync_events (event_type, user_id, subject_id)
+		SELECT 'notification_read', user_id, id FROM updated
 	`
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("notification not found")
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) MarkAsReadForUser(ctx context.Context, id, userID uuid.UUID) error {
+	query := `
+		WITH updated AS (
+			UPDATE notifications
+			SET is_read = true
+			WHERE id = $1 AND user_id = $2
+			RETURNING id, user_id
+		)
+		INSERT INTO sync_events (event_type, user_id, subject_id)
+		SELECT 'notification_read', user_id, id FROM updated
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id, userID)
 	if err != nil {
 		return err
 	}
@@ -113,20 +220,33 @@ func (r *notificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) e
 }
 
 func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	// 未読の通知があった場合のみ、クライアントの差分同期のために1件記録する
 	query := `
-		UPDATE notifications
-		SET is_read = true
-		WHERE user_id = $1 AND is_read = false
+		WITH updated AS (
+			UPDATE notifications
+			SET is_read = true
+			WHERE user_id = $1 AND is_read = false
+			RETURNING id
+		)
+		INSERT INTO sync_events (event_type, user_id)
+		SELECT 'notifications_read', $1 WHERE EXISTS (SELECT 1 FROM updated)
 	`
 
-	_, err := r.db.Exec(ctx, query, userID)
+	_, err := conn(ctx, r.db).Exec(ctx, query, userID)
 	return err
 }
 
 func (r *notificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := "DELETE FROM notifications WHERE id = $1"
+	query := `
+		WITH deleted AS (
+			DELETE FROM notifications WHERE id = $1
+			RETURNING id, user_id
+		)
+		INSERT INTO sync_events (event_type, user_id, subject_id)
+		SELECT 'notification_deleted', user_id, id FROM deleted
+	`
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -138,11 +258,41 @@ func (r *notificationRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// DeleteBefore deletes up to limit notifications created before before, recording
+// a sync event for each so that clients drop them as well
+func (r *notificationRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		WITH deleted AS (
+			DELETE FROM notifications
+			WHERE id IN (
+				SELECT id FROM notifications
+				WHERE created_at < $1
+				ORDER BY created_at
+				LIMIT $2
+			)
+			RETURNING id, user_id
+		)
+		INSERT INTO sync_events (event_type, user_id, subject_id)
+		SELECT 'notification_deleted', user_id, id FROM deleted
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (r *notificationRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
-	query := "SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = false"
+	query := `
+		SELECT COUNT(*) FROM notifications
+		WHERE user_id = $1 AND is_read = false AND actor_id NOT IN (` + inactiveUserIDs + `)
+			AND (post_id IS NULL OR post_id NOT IN (` + expiredPostIDs + `))
+	`
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -175,7 +325,8 @@ func (r *notificationRepository) GetWithRelations(ctx context.Context, id uuid.U
 				p.updated_at as post_updated_at
 			FROM notifications n
 			LEFT JOIN users u ON n.actor_id = u.id
-			LEFT JOIN posts p ON n.post_id = p.id
+			LEFT JOIN posts p ON n.post_id = p.id AND p.deleted_at IS NULL AND p.moderation_status = 'visible'
+				AND (p.expires_at IS NULL OR p.expires_at > NOW())
 			WHERE n.id = $1
 		)
 		SELECT * FROM notification_data
@@ -199,7 +350,7 @@ func (r *notificationRepository) GetWithRelations(ctx context.Context, id uuid.U
 		postIsRepost, postIsReply                      *bool
 	)
 
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&notification.ID, &notification.UserID, &notification.ActorID,
 		&notification.Type, &notification.PostID, &notification.IsRead,
 		&notification.CreatedAt,
@@ -281,15 +432,17 @@ func (r *notificationRepository) GetByUserIDWithRelations(ctx context.Context, u
 				p.updated_at as post_updated_at
 			FROM notifications n
 			LEFT JOIN users u ON n.actor_id = u.id
-			LEFT JOIN posts p ON n.post_id = p.id
-			WHERE n.user_id = $1
+			LEFT JOIN posts p ON n.post_id = p.id AND p.deleted_at IS NULL AND p.moderation_status = 'visible'
+				AND (p.expires_at IS NULL OR p.expires_at > NOW())
+			WHERE n.user_id = $1 AND n.actor_id NOT IN (` + inactiveUserIDs + `)
+				AND (n.post_id IS NULL OR n.post_id NOT IN (` + expiredPostIDs + `))
 			ORDER BY n.created_at DESC
 			LIMIT $2 OFFSET $3
 		)
 		SELECT * FROM notification_data
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +118,26 @@ func TestNotificationRepository(t *testing.T) {
 		assert.Contains(t, err.Error(), "notification not found")
 	})
 
+	// MarkAsReadForUser のテスト
+	t.Run("MarkAsReadForUser", func(t *testing.T) {
+		notifications, err := notificationRepo.GetByUserID(ctx, user1.ID, 0, 1)
+		require.NoError(t, err)
+		require.NotEmpty(t, notifications)
+		notification := notifications[0]
+
+		// 他のユーザーの通知は既読にできない
+		err = notificationRepo.MarkAsReadForUser(ctx, notification.ID, user2.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "notification not found")
+
+		err = notificationRepo.MarkAsReadForUser(ctx, notification.ID, user1.ID)
+		require.NoError(t, err)
+
+		updated, err := notificationRepo.GetByID(ctx, notification.ID)
+		require.NoError(t, err)
+		assert.True(t, updated.IsRead)
+	})
+
 	// MarkAllAsRead のテスト
 	t.Run("MarkAllAsRead", func(t *testing.T) {
 		// 追加の未読通知を作成
@@ -250,3 +270,103 @@ func TestNotificationRepository(t *testing.T) {
 		assert.Equal(t, int64(0), count)
 	})
 }
+
+func TestNotificationRepositoryGrouped(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	notificationRepo := NewNotificationRepository(db.Pool)
+
+	ctx := context.Background()
+
+	newUser := func(username string) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  "hashedpassword",
+			Name:      username,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+
+	owner := newUser("groupowner")
+	alice := newUser("groupalice")
+	bob := newUser("groupbob")
+	carol := newUser("groupcarol")
+
+	post := models.NewPost(owner.ID, "Popular post", nil)
+	require.NoError(t, postRepo.Create(ctx, post))
+
+	// 同じ日（UTC）の通知を古い順に作成
+	base := time.Now().UTC().Truncate(24 * time.Hour).Add(time.Hour)
+	create := func(actor *models.User, notificationType models.NotificationType, postID *uuid.UUID, minutes int) *models.Notification {
+		notification := models.NewNotification(owner.ID, actor.ID, notificationType, postID)
+		notification.CreatedAt = base.Add(time.Duration(minutes) * time.Minute)
+		require.NoError(t, notificationRepo.Create(ctx, notification))
+		return notification
+	}
+	create(alice, models.NotificationTypeLike, &post.ID, 1)
+	create(alice, models.NotificationTypeFollow, nil, 2)
+	create(bob, models.NotificationTypeLike, &post.ID, 3)
+	reply := create(bob, models.NotificationTypeReply, &post.ID, 4)
+	create(carol, models.NotificationTypeLike, &post.ID, 5)
+	create(alice, models.NotificationTypeLike, &post.ID, 6)
+
+	// GetGroupedByUserID のテスト
+	t.Run("GetGroupedByUserID", func(t *testing.T) {
+		groups, err := notificationRepo.GetGroupedByUserID(ctx, owner.ID, 0, 10, 2)
+		require.NoError(t, err)
+		require.Len(t, groups, 3)
+
+		// いいねは投稿ごとに1つのグループになる
+		likes := groups[0]
+		assert.Equal(t, models.NotificationTypeLike, likes.Type)
+		require.NotNil(t, likes.PostID)
+		assert.Equal(t, post.ID, *likes.PostID)
+		assert.Equal(t, 4, likes.NotificationCount)
+		assert.Equal(t, 3, likes.ActorCount)
+		assert.Equal(t, []uuid.UUID{alice.ID, carol.ID}, likes.ActorIDs)
+		assert.False(t, likes.Read)
+		assert.Equal(t, base.Add(6*time.Minute).Unix(), likes.LatestAt.Unix())
+
+		// 返信はまとめず、通知のIDをグループのIDにする
+		assert.Equal(t, models.NotificationTypeReply, groups[1].Type)
+		assert.Equal(t, reply.ID, groups[1].ID)
+		assert.Equal(t, 1, groups[1].NotificationCount)
+
+		assert.Equal(t, models.NotificationTypeFollow, groups[2].Type)
+		assert.Equal(t, []uuid.UUID{alice.ID}, groups[2].ActorIDs)
+
+		// グループのIDはページをまたいでも変わらない
+		page, err := notificationRepo.GetGroupedByUserID(ctx, owner.ID, 0, 1, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, likes.ID, page[0].ID)
+
+		// ページネーションはグループ単位
+		page, err = notificationRepo.GetGroupedByUserID(ctx, owner.ID, 2, 10, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, models.NotificationTypeFollow, page[0].Type)
+	})
+
+	// すべて既読にするとグループも既読になる
+	t.Run("Read", func(t *testing.T) {
+		require.NoError(t, notificationRepo.MarkAllAsRead(ctx, owner.ID))
+
+		groups, err := notificationRepo.GetGroupedByUserID(ctx, owner.ID, 0, 10, 2)
+		require.NoError(t, err)
+		for _, group := range groups {
+			assert.True(t, group.Read)
+		}
+	})
+}
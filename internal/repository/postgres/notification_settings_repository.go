@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type notificationSettingsRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewNotificationSettingsRepository creates a new PostgreSQL implementation of NotificationSettingsRepository
+func NewNotificationSettingsRepository(db *pgxpool.Pool) interfaces.NotificationSettingsRepository {
+	return &notificationSettingsRepository{db: db}
+}
+
+// Get returns a user's settings, using the defaults for the types the user has not configured
+func (r *notificationSettingsRepository) Get(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error) {
+	query := `
+		SELECT notification_type, enabled, email_enabled
+		FROM notification_settings
+		WHERE user_id = $1
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := models.NewNotificationSettings(userID)
+	for rows.Next() {
+		var notificationType models.NotificationType
+		var enabled, emailEnabled *bool
+		if err := rows.Scan(&notificationType, &enabled, &emailEnabled); err != nil {
+			return nil, err
+		}
+		if enabled != nil && models.IsMutableNotificationType(notificationType) {
+			settings.Enabled[notificationType] = *enabled
+		}
+		if emailEnabled != nil && models.IsEmailNotificationType(notificationType) {
+			settings.Email[notificationType] = *emailEnabled
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// Update stores the given preferences in a single statement, keeping the ones that are not given
+func (r *notificationSettingsRepository) Update(ctx context.Context, userID uuid.UUID, enabled, email map[models.NotificationType]bool) (*models.NotificationSettings, error) {
+	// 種類ごとに1行にまとめ、指定していない項目はNULLにする
+	rowsByType := make(map[models.NotificationType]int, len(enabled)+len(email))
+	var types []string
+	var enabledValues, emailValues []*bool
+	row := func(notificationType models.NotificationType) int {
+		i, ok := rowsByType[notificationType]
+		if !ok {
+			i = len(types)
+			rowsByType[notificationType] = i
+			types = append(types, string(notificationType))
+			enabledValues = append(enabledValues, nil)
+			emailValues = append(emailValues, nil)
+		}
+		return i
+	}
+	for notificationType, value := range enabled {
+		enabledValues[row(notificationType)] = &value
+	}
+	for notificationType, value := range email {
+		emailValues[row(notificationType)] = &value
+	}
+
+	query := `
+		INSERT INTO notification_settings (user_id, notification_type, enabled, email_enabled, updated_at)
+		SELECT $1, t.notification_type, t.enabled, t.email_enabled, NOW()
+		FROM unnest($2::text[], $3::boolean[], $4::boolean[]) AS t(notification_type, enabled, email_enabled)
+		ON CONFLICT (user_id, notification_type) DO UPDATE
+		SET enabled = COALESCE(EXCLUDED.enabled, notification_settings.enabled),
+			email_enabled = COALESCE(EXCLUDED.email_enabled, notification_settings.email_enabled),
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, userID, types, enabledValues, emailValues); err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, userID)
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationSettingsRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	settingsRepo := NewNotificationSettingsRepository(db.Pool)
+
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "notifsettings",
+		Email:     "notifsettings@example.com",
+		Password:  "hashedpassword",
+		Name:      "Notification Settings",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	// 設定していない場合はデフォルト値を返す
+	t.Run("GetDefault", func(t *testing.T) {
+		settings, err := settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, settings.EmailEnabled(models.NotificationTypeFollow))
+		assert.True(t, settings.EmailEnabled(models.NotificationTypeMention))
+		assert.False(t, settings.EmailEnabled(models.NotificationTypeLike))
+		assert.True(t, settings.IsEnabled(models.NotificationTypeLike))
+		assert.True(t, settings.IsEnabled(models.NotificationTypePostRemoved))
+	})
+
+	// メール通知の更新のテスト
+	t.Run("UpdateEmail", func(t *testing.T) {
+		settings, err := settingsRepo.Update(ctx, user.ID, nil, map[models.NotificationType]bool{
+			models.NotificationTypeFollow: false,
+		})
+		require.NoError(t, err)
+		assert.False(t, settings.EmailEnabled(models.NotificationTypeFollow))
+		assert.True(t, settings.EmailEnabled(models.NotificationTypeMention))
+
+		// 指定していない種類は変更しない
+		settings, err = settingsRepo.Update(ctx, user.ID, nil, map[models.NotificationType]bool{
+			models.NotificationTypeMention: false,
+		})
+		require.NoError(t, err)
+		assert.False(t, settings.EmailEnabled(models.NotificationTypeFollow))
+		assert.False(t, settings.EmailEnabled(models.NotificationTypeMention))
+
+		// 再び有効にする
+		settings, err = settingsRepo.Update(ctx, user.ID, nil, map[models.NotificationType]bool{
+			models.NotificationTypeFollow: true,
+		})
+		require.NoError(t, err)
+		assert.True(t, settings.EmailEnabled(models.NotificationTypeFollow))
+
+		settings, err = settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, settings.EmailEnabled(models.NotificationTypeFollow))
+		assert.False(t, settings.EmailEnabled(models.NotificationTypeMention))
+	})
+
+	// 通知の有効・無効の更新のテスト
+	t.Run("UpdateEnabled", func(t *testing.T) {
+		settings, err := settingsRepo.Update(ctx, user.ID, map[models.NotificationType]bool{
+			models.NotificationTypeLike:   false,
+			models.NotificationTypeFollow: false,
+		}, nil)
+		require.NoError(t, err)
+		assert.False(t, settings.IsEnabled(models.NotificationTypeLike))
+		assert.False(t, settings.IsEnabled(models.NotificationTypeFollow))
+		assert.True(t, settings.IsEnabled(models.NotificationTypeRepost))
+
+		// 無効にした種類はメールでも送信しないが、メール通知の設定は保持する
+		assert.False(t, settings.EmailEnabled(models.NotificationTypeFollow))
+		assert.True(t, settings.Email[models.NotificationTypeFollow])
+
+		// 同じ種類の両方の項目を一度に更新する
+		settings, err = settingsRepo.Update(ctx, user.ID,
+			map[models.NotificationType]bool{models.NotificationTypeFollow: true},
+			map[models.NotificationType]bool{models.NotificationTypeFollow: false},
+		)
+		require.NoError(t, err)
+		assert.True(t, settings.IsEnabled(models.NotificationTypeFollow))
+		assert.False(t, settings.EmailEnabled(models.NotificationTypeFollow))
+		assert.False(t, settings.IsEnabled(models.NotificationTypeLike))
+	})
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const outboxEventColumns = `id, channel, user_id, payload, attempts, last_error, next_attempt_at, failed_at, created_at`
+
+type outboxRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOutboxRepository creates a new PostgreSQL implementation of OutboxRepository
+func NewOutboxRepository(db *pgxpool.Pool) interfaces.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Create inserts the events, joining the transaction in ctx so they are only delivered once it commits
+func (r *outboxRepository) Create(ctx context.Context, events []*models.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_events (channel, user_id, payload, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	// 複数のイベントを1回の往復でまとめて作成する
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		batch.Queue(query,
+			event.Channel, event.UserID, event.Payload, event.NextAttemptAt, event.CreatedAt,
+		).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&event.ID)
+		})
+	}
+
+	return conn(ctx, r.db).SendBatch(ctx, batch).Close()
+}
+
+// ClaimDue leases up to limit due events, oldest first, skipping the ones another dispatcher holds
+func (r *outboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE outbox_events
+			SET attempts = attempts + 1,
+				next_attempt_at = $2
+			WHERE id IN (
+				SELECT id
+				FROM outbox_events
+				WHERE failed_at IS NULL AND next_attempt_at <= NOW()
+				ORDER BY id ASC
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + outboxEventColumns + `
+		)
+		SELECT ` + outboxEventColumns + `
+		FROM claimed
+		ORDER BY id ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, time.Now().Add(lease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.OutboxEvent
+	for rows.Next() {
+		event := &models.OutboxEvent{}
+		err := rows.Scan(
+			&event.ID,
+			&event.Channel,
+			&event.UserID,
+			&event.Payload,
+			&event.Attempts,
+			&event.LastError,
+			&event.NextAttemptAt,
+			&event.FailedAt,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Delete removes a delivered event
+func (r *outboxRepository) Delete(ctx context.Context, id int64) error {
+	return r.exec(ctx, `DELETE FROM outbox_events WHERE id = $1`, id)
+}
+
+// Retry schedules the next attempt of an event that failed to be delivered
+func (r *outboxRepository) Retry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	return r.exec(ctx, `
+		UPDATE outbox_events
+		SET next_attempt_at = $2, last_error = $3
+		WHERE id = $1
+	`, id, nextAttemptAt, lastError)
+}
+
+// MarkFailed keeps an event that has run out of attempts for inspection and stops delivering it
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	return r.exec(ctx, `
+		UPDATE outbox_events
+		SET failed_at = NOW(), last_error = $2
+		WHERE id = $1
+	`, id, lastError)
+}
+
+// exec runs a statement on a single event and reports a missing event as not found
+func (r *outboxRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	result, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("outbox event not found")
+	}
+
+	return nil
+}
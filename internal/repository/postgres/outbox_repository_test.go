@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	outboxRepo := NewOutboxRepository(db.Pool)
+	txManager := NewTxManager(db.Pool)
+
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "outboxuser",
+		Email:     "outboxuser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Outbox User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	payload := json.RawMessage(`{"type":"follow"}`)
+
+	// ロールバックされたトランザクションのイベントは保存されない
+	t.Run("CreateRolledBack", func(t *testing.T) {
+		rollback := errors.New("rollback")
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			require.NoError(t, outboxRepo.Create(ctx, []*models.OutboxEvent{
+				models.NewOutboxEvent(models.OutboxChannelWebSocket, user.ID, payload),
+			}))
+			return rollback
+		})
+		require.ErrorIs(t, err, rollback)
+
+		events, err := outboxRepo.ClaimDue(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	// 保存・取得・削除のテスト
+	t.Run("CreateClaimDelete", func(t *testing.T) {
+		created := []*models.OutboxEvent{
+			models.NewOutboxEvent(models.OutboxChannelWebSocket, user.ID, payload),
+			models.NewOutboxEvent(models.OutboxChannelPush, user.ID, payload),
+		}
+		require.NoError(t, outboxRepo.Create(ctx, created))
+		assert.NotZero(t, created[0].ID)
+
+		events, err := outboxRepo.ClaimDue(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, created[0].ID, events[0].ID)
+		assert.Equal(t, models.OutboxChannelWebSocket, events[0].Channel)
+		assert.Equal(t, models.OutboxChannelPush, events[1].Channel)
+		assert.Equal(t, 1, events[0].Attempts)
+		assert.JSONEq(t, string(payload), string(events[0].Payload))
+
+		// 取得中のイベントは再取得されない
+		again, err := outboxRepo.ClaimDue(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, again)
+
+		for _, event := range events {
+			require.NoError(t, outboxRepo.Delete(ctx, event.ID))
+		}
+		err = outboxRepo.Delete(ctx, events[0].ID)
+		require.Error(t, err)
+		assert.Equal(t, "outbox event not found", err.Error())
+	})
+
+	// 再試行と失敗のテスト
+	t.Run("RetryAndMarkFailed", func(t *testing.T) {
+		event := models.NewOutboxEvent(models.OutboxChannelEmail, user.ID, payload)
+		require.NoError(t, outboxRepo.Create(ctx, []*models.OutboxEvent{event}))
+
+		events, err := outboxRepo.ClaimDue(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		// 再試行の時刻を過ぎると再取得され、試行回数が増える
+		require.NoError(t, outboxRepo.Retry(ctx, event.ID, time.Now().Add(-time.Second), "queue full"))
+		events, err = outboxRepo.ClaimDue(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, 2, events[0].Attempts)
+		require.NotNil(t, events[0].LastError)
+		assert.Equal(t, "queue full", *events[0].LastError)
+
+		// 失敗したイベントは再取得されない
+		require.NoError(t, outboxRepo.Retry(ctx, event.ID, time.Now().Add(-time.Second), "queue full"))
+		require.NoError(t, outboxRepo.MarkFailed(ctx, event.ID, "gave up"))
+		events, err = outboxRepo.ClaimDue(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
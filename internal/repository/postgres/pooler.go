@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// 接続プーラーの検出で実行するクエリの回数
+const poolerProbeQueries = 5
+
+// pgbouncerの既定のポート
+const pgbouncerDefaultPort = 6432
+
+// EnableTransactionPooling は接続プーラー（pgbouncerのトランザクションプーリング）の背後でも動作するように接続プールを設定します
+// トランザクションごとにサーバー側の接続が入れ替わるため、名前付きのプリペアドステートメントとそのキャッシュを使用せず、シンプルプロトコルでクエリを送信します
+func EnableTransactionPooling(config *pgxpool.Config) {
+	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	config.ConnConfig.StatementCacheCapacity = 0
+	config.ConnConfig.DescriptionCacheCapacity = 0
+}
+
+// DetectTransactionPooler は起動時に、トランザクション（またはステートメント）プーリングの接続プーラーを経由しているかを推定します
+// 1つの接続で続けて実行したクエリが異なるバックエンドで処理された場合はプーラーを経由していると判断します
+// バックエンドが1つしかない場合は検出できないため、pgbouncerの既定のポートに接続している場合もプーラーの可能性があるものとして扱います
+// 戻り値は検出したかどうかと、その理由です
+func DetectTransactionPooler(ctx context.Context, connConfig *pgx.ConnConfig) (bool, string, error) {
+	// 検出自体がプーラーの背後でも失敗しないよう、シンプルプロトコルで接続する
+	probeConfig := connConfig.Copy()
+	probeConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	probeConfig.StatementCacheCapacity = 0
+	probeConfig.DescriptionCacheCapacity = 0
+	probeConfig.Tracer = nil
+
+	conn, err := pgx.ConnectConfig(ctx, probeConfig)
+	if err != nil {
+		return false, "", err
+	}
+	defer conn.Close(ctx)
+
+	pids := make(map[uint32]struct{}, poolerProbeQueries)
+	for i := 0; i < poolerProbeQueries; i++ {
+		var pid uint32
+		if err := conn.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&pid); err != nil {
+			return false, "", err
+		}
+		pids[pid] = struct{}{}
+	}
+	if len(pids) > 1 {
+		return true, fmt.Sprintf("1つの接続のクエリが%d個の異なるバックエンドで処理されました", len(pids)), nil
+	}
+
+	if probeConfig.Port == pgbouncerDefaultPort {
+		return true, fmt.Sprintf("pgbouncerの既定のポート（%d）に接続しています", pgbouncerDefaultPort), nil
+	}
+
+	return false, "", nil
+}
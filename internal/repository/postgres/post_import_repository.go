@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// importedPostColumns are the posts columns written by COPY; the rest keep their defaults
+var importedPostColumns = []string{
+	"id", "user_id", "content", "media_urls", "media_alt_texts", "reply_to_id",
+	"content_rating", "reply_policy", "sharing_enabled", "language", "created_at", "updated_at",
+}
+
+type postImportRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostImportRepository creates a new PostgreSQL implementation of PostImportRepository
+func NewPostImportRepository(db *pgxpool.Pool) interfaces.PostImportRepository {
+	return &postImportRepository{db: db}
+}
+
+func (r *postImportRepository) GetImportedPostIDs(ctx context.Context, userID uuid.UUID, source string) (map[string]uuid.UUID, error) {
+	query := `
+		SELECT external_id, post_id
+		FROM post_imports
+		WHERE user_id = $1 AND source = $2
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imported := make(map[string]uuid.UUID)
+	for rows.Next() {
+		var externalID string
+		var postID uuid.UUID
+		if err := rows.Scan(&externalID, &postID); err != nil {
+			return nil, err
+		}
+		imported[externalID] = postID
+	}
+
+	return imported, rows.Err()
+}
+
+// Import bulk inserts the posts with COPY. Unlike Create, it records no sync events,
+// as clients would otherwise receive the whole archive as new posts.
+func (r *postImportRepository) Import(ctx context.Context, userID uuid.UUID, imports []*models.PostImport) (int64, error) {
+	if len(imports) == 0 {
+		return 0, nil
+	}
+
+	postRows := make([][]any, 0, len(imports))
+	importRows := make([][]any, 0, len(imports))
+	postIDs := make([]uuid.UUID, 0, len(imports))
+	for _, imported := range imports {
+		post := imported.Post
+		if post.UserID != userID {
+			return 0, errors.New("imported post belongs to another user")
+		}
+		if err := validatePost(post); err != nil {
+			return 0, fmt.Errorf("imported post %s: %w", imported.ExternalID, err)
+		}
+
+		mediaURLsJSON, err := json.Marshal(post.MediaURLs)
+		if err != nil {
+			return 0, err
+		}
+		altTextsJSON, err := mediaAltTextsJSON(post)
+		if err != nil {
+			return 0, err
+		}
+
+		postRows = append(postRows, []any{
+			post.ID, post.UserID, post.Content, mediaURLsJSON, altTextsJSON, post.ReplyToID,
+			string(post.ContentRating), string(post.ReplyPolicy), post.SharingEnabled, post.Language, post.CreatedAt, post.UpdatedAt,
+		})
+		importRows = append(importRows, []any{userID, imported.Source, imported.ExternalID, post.ID})
+		postIDs = append(postIDs, post.ID)
+	}
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Replies may refer to posts copied in the same batch, as foreign keys are checked at the end of the statement
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"posts"}, importedPostColumns, pgx.CopyFromRows(postRows))
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"post_imports"}, []string{"user_id", "source", "external_id", "post_id"}, pgx.CopyFromRows(importRows))
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return 0, errors.New("post already imported")
+		}
+		return 0, err
+	}
+
+	replyCountQuery := `
+		UPDATE posts p
+		SET reply_count = p.reply_count + c.count
+		FROM (
+			SELECT reply_to_id, COUNT(*) AS count
+			FROM posts
+			WHERE id = ANY($1) AND reply_to_id IS NOT NULL
+			GROUP BY reply_to_id
+		) c
+		WHERE p.id = c.reply_to_id
+	`
+	if _, err := tx.Exec(ctx, replyCountQuery, postIDs); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(ctx, "UPDATE users SET post_count = post_count + $2 WHERE id = $1", userID, copied)
+	if err != nil {
+		return 0, err
+	}
+	if result.RowsAffected() == 0 {
+		return 0, errors.New("user not found")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return copied, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostImportRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	importRepo := NewPostImportRepository(db.Pool)
+
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "importer",
+		Email:     "importer@example.com",
+		Password:  "hashedpassword",
+		Name:      "Importer",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	postedAt := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := models.NewPost(user.ID, "first tweet", nil)
+	first.CreatedAt, first.UpdatedAt = postedAt, postedAt
+	// 同じバッチ内の投稿への返信
+	reply := models.NewReply(user.ID, first.ID, "self reply", nil)
+	reply.CreatedAt, reply.UpdatedAt = postedAt.Add(time.Minute), postedAt.Add(time.Minute)
+
+	// Import のテスト
+	t.Run("Import", func(t *testing.T) {
+		count, err := importRepo.Import(ctx, user.ID, []*models.PostImport{
+			{Source: models.PostImportSourceTwitter, ExternalID: "100", Post: first},
+			{Source: models.PostImportSourceTwitter, ExternalID: "101", Post: reply},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// 投稿日時を保持する
+		post, err := postRepo.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.True(t, postedAt.Equal(post.CreatedAt))
+		assert.Equal(t, 1, post.ReplyCount)
+
+		post, err = postRepo.GetByID(ctx, reply.ID)
+		require.NoError(t, err)
+		require.NotNil(t, post.ReplyToID)
+		assert.Equal(t, first.ID, *post.ReplyToID)
+
+		updated, err := userRepo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated.PostCount)
+	})
+
+	// GetImportedPostIDs のテスト
+	t.Run("GetImportedPostIDs", func(t *testing.T) {
+		imported, err := importRepo.GetImportedPostIDs(ctx, user.ID, models.PostImportSourceTwitter)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]uuid.UUID{"100": first.ID, "101": reply.ID}, imported)
+
+		imported, err = importRepo.GetImportedPostIDs(ctx, user.ID, "other")
+		require.NoError(t, err)
+		assert.Empty(t, imported)
+	})
+
+	// 取り込み済みの投稿を含む場合はすべて取り消す
+	t.Run("AlreadyImported", func(t *testing.T) {
+		again := models.NewPost(user.ID, "first tweet", nil)
+		other := models.NewPost(user.ID, "another tweet", nil)
+
+		_, err := importRepo.Import(ctx, user.ID, []*models.PostImport{
+			{Source: models.PostImportSourceTwitter, ExternalID: "102", Post: other},
+			{Source: models.PostImportSourceTwitter, ExternalID: "100", Post: again},
+		})
+		require.Error(t, err)
+		assert.Equal(t, "post already imported", err.Error())
+
+		_, err = postRepo.GetByID(ctx, other.ID)
+		assert.Error(t, err)
+	})
+
+	// 他のユーザーの投稿は取り込めない
+	t.Run("OtherUser", func(t *testing.T) {
+		post := models.NewPost(uuid.New(), "not mine", nil)
+		_, err := importRepo.Import(ctx, user.ID, []*models.PostImport{
+			{Source: models.PostImportSourceTwitter, ExternalID: "200", Post: post},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -2,16 +2,45 @@ package postgres
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// postColumns is the column list shared by the post SELECT queries
+const postColumns = `id, user_id, content, media_urls, reply_to_id, repost_id,
+			like_count, repost_count, reply_count, view_count, share_count,
+			content_rating, reply_policy, sharing_enabled, created_at, updated_at, deleted_at,
+			moderation_status, moderation_reason, moderated_by, moderated_at, content_warning, replies_locked,
+			media_alt_texts, language, expires_at`
+
+// livePostCondition excludes deleted and expired posts and posts hidden or removed by moderators
+const livePostCondition = `deleted_at IS NULL AND moderation_status = 'visible'
+	AND (expires_at IS NULL OR expires_at > NOW())`
+
+// expiredPostIDs selects the expired posts that the purge job has not deleted yet
+const expiredPostIDs = `SELECT id FROM posts WHERE expires_at <= NOW()`
+
+// visiblePostCondition excludes deleted or moderated posts and posts by deactivated or deleting accounts
+const visiblePostCondition = livePostCondition + `
+	AND user_id NOT IN (` + inactiveUserIDs + `)`
+
+// likeEscaper escapes the LIKE wildcard characters in a literal substring
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// execer is implemented by both *pgxpool.Pool and pgx.Tx
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
 type postRepository struct {
 	db *pgxpool.Pool
 }
@@ -22,74 +51,189 @@ func NewPostRepository(db *pgxpool.Pool) interfaces.PostRepository {
 }
 
 func (r *postRepository) Create(ctx context.Context, post *models.Post) error {
-	// バリデーションチェック
+	if err := validatePost(post); err != nil {
+		return err
+	}
+
+	return insertPost(ctx, conn(ctx, r.db), post)
+}
+
+func (r *postRepository) CreateThread(ctx context.Context, posts []*models.Post) error {
+	if len(posts) == 0 {
+		return errors.New("thread cannot be empty")
+	}
+	for _, post := range posts {
+		if err := validatePost(post); err != nil {
+			return err
+		}
+	}
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// スレッドの投稿と返信数の更新を1回の往復でまとめて送信する
+	batch := &pgx.Batch{}
+	for _, post := range posts {
+		args, err := insertPostArgs(post)
+		if err != nil {
+			return err
+		}
+		batch.Queue(insertPostQuery, args...)
+
+		// 返信先の返信数を更新
+		if post.ReplyToID != nil {
+			batch.Queue("UPDATE posts SET reply_count = reply_count + 1 WHERE id = $1 AND "+livePostCondition, *post.ReplyToID).Exec(func(result pgconn.CommandTag) error {
+				if result.RowsAffected() == 0 {
+					return errors.New("post not found")
+				}
+				return nil
+			})
+		}
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// validatePost checks the post fields and fills in default values before insertion
+func validatePost(post *models.Post) error {
 	if post == nil {
 		return errors.New("post cannot be nil")
 	}
 	if post.Content == "" {
 		return errors.New("content cannot be empty")
 	}
-	if len(post.Content) > 280 {
+	if len(post.Content) > models.MaxPostContentLength {
 		return errors.New("content cannot exceed 280 characters")
 	}
 	if len(post.MediaURLs) > 4 {
 		return errors.New("cannot have more than 4 media URLs")
 	}
+	if len(post.MediaAltTexts) > len(post.MediaURLs) {
+		return errors.New("cannot have more alt texts than media URLs")
+	}
+	if post.ContentRating == "" {
+		post.ContentRating = models.ContentRatingGeneral
+	}
+	if !post.ContentRating.IsValid() {
+		return errors.New("invalid content rating")
+	}
+	if post.ReplyPolicy == "" {
+		post.ReplyPolicy = models.ReplyPolicyEveryone
+	}
+	if !post.ReplyPolicy.IsValid() {
+		return errors.New("invalid reply policy")
+	}
 
-	query := `
+	return nil
+}
+
+// insertPostQuery inserts a post and records it for client delta sync in the same statement
+const insertPostQuery = `
+	WITH inserted AS (
 		INSERT INTO posts (
 			id, user_id, content, media_urls, reply_to_id, repost_id,
-			like_count, repost_count, reply_count, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-	`
+			like_count, repost_count, reply_count, content_rating,
+			reply_policy, sharing_enabled, created_at, updated_at,
+			media_alt_texts, language, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING id, user_id
+	)
+	INSERT INTO sync_events (event_type, user_id, subject_id)
+	SELECT 'post_created', user_id, id FROM inserted
+`
+
+// insertPost inserts post using db, which may be the pool or a transaction,
+// and records it for client delta sync in the same statement
+func insertPost(ctx context.Context, db execer, post *models.Post) error {
+	args, err := insertPostArgs(post)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, insertPostQuery, args...)
+	return err
+}
 
+// insertPostArgs returns the arguments of insertPostQuery for post
+func insertPostArgs(post *models.Post) ([]any, error) {
 	mediaURLsJSON, err := json.Marshal(post.MediaURLs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	altTextsJSON, err := mediaAltTextsJSON(post)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = r.db.Exec(ctx, query,
+	return []any{
 		post.ID, post.UserID, post.Content, mediaURLsJSON,
 		post.ReplyToID, post.RepostID, post.LikeCount,
-		post.RepostCount, post.ReplyCount, post.CreatedAt, post.UpdatedAt,
-	)
-
-	return err
+		post.RepostCount, post.ReplyCount, post.ContentRating,
+		post.ReplyPolicy, post.SharingEnabled, post.CreatedAt, post.UpdatedAt,
+		altTextsJSON, post.Language, post.ExpiresAt,
+	}, nil
 }
 
 func (r *postRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Post, error) {
 	query := `
-		SELECT id, user_id, content, media_urls, reply_to_id, repost_id,
-			like_count, repost_count, reply_count, created_at, updated_at
+		SELECT ` + postColumns + `
+		FROM posts WHERE id = $1 AND ` + visiblePostCondition + `
+	`
+
+	return r.getPost(ctx, query, id)
+}
+
+func (r *postRepository) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
 		FROM posts WHERE id = $1
 	`
 
+	return r.getPost(ctx, query, id)
+}
+
+// getPost is a helper function to execute queries that return a single post
+func (r *postRepository) getPost(ctx context.Context, query string, args ...interface{}) (*models.Post, error) {
 	var post models.Post
-	var mediaURLsJSON []byte
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&post.ID, &post.UserID, &post.Content, &mediaURLsJSON,
-		&post.ReplyToID, &post.RepostID, &post.LikeCount,
-		&post.RepostCount, &post.ReplyCount, &post.CreatedAt, &post.UpdatedAt,
-	)
+	err := scanPost(conn(ctx, r.db).QueryRow(ctx, query, args...), &post)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, errors.New("post not found")
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	if mediaURLsJSON != nil {
-		err = json.Unmarshal(mediaURLsJSON, &post.MediaURLs)
-		if err != nil {
-			return nil, err
-		}
+	return &post, nil
+}
+
+func (r *postRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Post, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]*models.Post{}, nil
 	}
 
-	post.IsReply = post.ReplyToID != nil
-	post.IsRepost = post.RepostID != nil
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts WHERE id = ANY($1) AND ` + visiblePostCondition + `
+	`
 
-	return &post, nil
+	posts, err := r.queryPosts(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	postsByID := make(map[uuid.UUID]*models.Post, len(posts))
+	for _, post := range posts {
+		postsByID[post.ID] = post
+	}
+
+	return postsByID, nil
 }
 
 func (r *postRepository) Update(ctx context.Context, post *models.Post) error {
@@ -100,28 +244,50 @@ func (r *postRepository) Update(ctx context.Context, post *models.Post) error {
 	if post.Content == "" {
 		return errors.New("content cannot be empty")
 	}
-	if len(post.Content) > 280 {
+	if len(post.Content) > models.MaxPostContentLength {
 		return errors.New("content cannot exceed 280 characters")
 	}
 	if len(post.MediaURLs) > 4 {
 		return errors.New("cannot have more than 4 media URLs")
 	}
+	if len(post.MediaAltTexts) > len(post.MediaURLs) {
+		return errors.New("cannot have more alt texts than media URLs")
+	}
+	if post.ContentRating == "" {
+		post.ContentRating = models.ContentRatingGeneral
+	}
+	if !post.ContentRating.IsValid() {
+		return errors.New("invalid content rating")
+	}
+	if post.ReplyPolicy == "" {
+		post.ReplyPolicy = models.ReplyPolicyEveryone
+	}
+	if !post.ReplyPolicy.IsValid() {
+		return errors.New("invalid reply policy")
+	}
 
 	query := `
 		UPDATE posts SET
 			content = $1, media_urls = $2, like_count = $3,
-			repost_count = $4, reply_count = $5, updated_at = $6
-		WHERE id = $7
+			repost_count = $4, reply_count = $5, content_rating = $6,
+			reply_policy = $7, sharing_enabled = $8, updated_at = $9,
+			media_alt_texts = $10, language = $11
+		WHERE id = $12 AND ` + livePostCondition + `
 	`
 
 	mediaURLsJSON, err := json.Marshal(post.MediaURLs)
 	if err != nil {
 		return err
 	}
+	altTextsJSON, err := mediaAltTextsJSON(post)
+	if err != nil {
+		return err
+	}
 
-	result, err := r.db.Exec(ctx, query,
+	result, err := conn(ctx, r.db).Exec(ctx, query,
 		post.Content, mediaURLsJSON, post.LikeCount,
-		post.RepostCount, post.ReplyCount, post.UpdatedAt, post.ID,
+		post.RepostCount, post.ReplyCount, post.ContentRating, post.ReplyPolicy,
+		post.SharingEnabled, post.UpdatedAt, altTextsJSON, post.Language, post.ID,
 	)
 
 	if err != nil {
@@ -135,10 +301,26 @@ func (r *postRepository) Update(ctx context.Context, post *models.Post) error {
 	return nil
 }
 
+// mediaAltTextsJSON encodes the alt texts padded to one entry per media URL
+func mediaAltTextsJSON(post *models.Post) ([]byte, error) {
+	altTexts := make([]string, len(post.MediaURLs))
+	copy(altTexts, post.MediaAltTexts)
+	return json.Marshal(altTexts)
+}
+
 func (r *postRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := "DELETE FROM posts WHERE id = $1"
+	query := `
+		WITH deleted AS (
+			UPDATE posts
+			SET deleted_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING id, user_id
+		)
+		INSERT INTO sync_events (event_type, user_id, subject_id)
+		SELECT 'post_deleted', user_id, id FROM deleted
+	`
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -152,48 +334,238 @@ func (r *postRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *postRepository) List(ctx context.Context, offset, limit int) ([]*models.Post, error) {
 	query := `
-		SELECT id, user_id, content, media_urls, reply_to_id, repost_id,
-			like_count, repost_count, reply_count, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
+		WHERE ` + visiblePostCondition + `
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	return r.queryPosts(ctx, query, limit, offset)
+	return r.readPosts(ctx, query, limit, offset)
+}
+
+func (r *postRepository) ListExcluding(ctx context.Context, viewerID uuid.UUID, excludedKeywords []string, offset, limit int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE ` + visiblePostCondition + ` AND NOT (content ILIKE ANY($1))
+			AND user_id NOT IN (` + blockRelatedUserIDs("$2") + `)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.readPosts(ctx, query, excludedKeywordPatterns(excludedKeywords), viewerID, limit, offset)
+}
+
+func (r *postRepository) ListPopular(ctx context.Context, viewerID uuid.UUID, window time.Duration, excludedKeywords []string, offset, limit int) ([]*models.Post, error) {
+	// エンゲージメント（リポストは返信・いいねより重み付けする）を経過時間で減衰させたスコアで並べる
+	// 投稿直後の数時間に点数が集中しないよう、経過時間に2時間を加えてから減衰させる
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE created_at > $1 AND ` + visiblePostCondition + ` AND NOT (content ILIKE ANY($2))
+			AND user_id NOT IN (` + blockRelatedUserIDs("$3") + `)
+		ORDER BY
+			(like_count + 2 * repost_count + reply_count)
+				/ POWER(EXTRACT(EPOCH FROM (NOW() - created_at)) / 3600 + 2, 1.5) DESC,
+			created_at DESC,
+			id
+		LIMIT $4 OFFSET $5
+	`
+
+	since := time.Now().UTC().Add(-window)
+	return r.readPosts(ctx, query, since, excludedKeywordPatterns(excludedKeywords), viewerID, limit, offset)
+}
+
+// topicPostCondition restricts posts to those classified into the topic given as $1
+const topicPostCondition = `id IN (SELECT post_id FROM post_topics WHERE topic_id = $1)`
+
+func (r *postRepository) ListByTopic(ctx context.Context, viewerID, topicID uuid.UUID, excludedKeywords []string, offset, limit int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE ` + topicPostCondition + ` AND ` + visiblePostCondition + ` AND NOT (content ILIKE ANY($2))
+			AND user_id NOT IN (` + blockRelatedUserIDs("$3") + `)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	return r.readPosts(ctx, query, topicID, excludedKeywordPatterns(excludedKeywords), viewerID, limit, offset)
+}
+
+func (r *postRepository) ListPopularByTopic(ctx context.Context, viewerID, topicID uuid.UUID, window time.Duration, excludedKeywords []string, offset, limit int) ([]*models.Post, error) {
+	// ListPopularと同じスコアで並べる
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE ` + topicPostCondition + ` AND created_at > $2 AND ` + visiblePostCondition + ` AND NOT (content ILIKE ANY($3))
+			AND user_id NOT IN (` + blockRelatedUserIDs("$4") + `)
+		ORDER BY
+			(like_count + 2 * repost_count + reply_count)
+				/ POWER(EXTRACT(EPOCH FROM (NOW() - created_at)) / 3600 + 2, 1.5) DESC,
+			created_at DESC,
+			id
+		LIMIT $5 OFFSET $6
+	`
+
+	since := time.Now().UTC().Add(-window)
+	return r.readPosts(ctx, query, topicID, since, excludedKeywordPatterns(excludedKeywords), viewerID, limit, offset)
+}
+
+// excludedKeywordPatterns converts keywords into substring ILIKE patterns,
+// escaping % and _ so they match literally
+func excludedKeywordPatterns(keywords []string) []string {
+	patterns := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		patterns[i] = "%" + likeEscaper.Replace(keyword) + "%"
+	}
+	return patterns
 }
 
 func (r *postRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Post, error) {
 	query := `
-		SELECT id, user_id, content, media_urls, reply_to_id, repost_id,
-			like_count, repost_count, reply_count, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
-		WHERE user_id = $1
+		WHERE user_id = $1 AND ` + visiblePostCondition + `
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	return r.queryPosts(ctx, query, userID, limit, offset)
+	return r.readPosts(ctx, query, userID, limit, offset)
 }
 
-func (r *postRepository) GetReplies(ctx context.Context, postID uuid.UUID, offset, limit int) ([]*models.Post, error) {
+func (r *postRepository) GetByUserIDs(ctx context.Context, userIDs []uuid.UUID, viewerID uuid.UUID, offset, limit int) ([]*models.Post, error) {
+	if len(userIDs) == 0 {
+		return []*models.Post{}, nil
+	}
+
 	query := `
-		SELECT id, user_id, content, media_urls, reply_to_id, repost_id,
-			like_count, repost_count, reply_count, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
-		WHERE reply_to_id = $1
+		WHERE user_id = ANY($1) AND ` + visiblePostCondition + `
+			AND user_id NOT IN (` + blockRelatedUserIDs("$2") + `)
 		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.readPosts(ctx, query, userIDs, viewerID, limit, offset)
+}
+
+// replyOrders maps each reply sort to its ORDER BY clause (id breaks ties so pages do not overlap)
+var replyOrders = map[models.ReplySort]string{
+	models.ReplySortNewest: "created_at DESC, id DESC",
+	models.ReplySortOldest: "created_at ASC, id ASC",
+	models.ReplySortTop:    "like_count + repost_count + reply_count DESC, created_at DESC, id DESC",
+}
+
+func (r *postRepository) GetReplies(ctx context.Context, postID uuid.UUID, sort models.ReplySort, offset, limit int) ([]*models.Post, error) {
+	order, ok := replyOrders[sort]
+	if !ok {
+		order = replyOrders[models.ReplySortNewest]
+	}
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE reply_to_id = $1 AND ` + visiblePostCondition + `
+		ORDER BY ` + order + `
 		LIMIT $2 OFFSET $3
 	`
 
 	return r.queryPosts(ctx, query, postID, limit, offset)
 }
 
+func (r *postRepository) GetRepliesBetween(ctx context.Context, userA, userB uuid.UUID, offset, limit int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE ` + repliesBetweenCondition + `
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.queryPosts(ctx, query, userA, userB, limit, offset)
+}
+
+// repliesBetweenCondition matches replies posted by $1 or $2 to a post by the other user,
+// including replies whose parent has since been deleted
+const repliesBetweenCondition = `
+	user_id IN ($1, $2)
+	AND ` + visiblePostCondition + `
+	AND EXISTS (
+		SELECT 1 FROM posts parent
+		WHERE parent.id = posts.reply_to_id
+			AND parent.user_id IN ($1, $2)
+			AND parent.user_id <> posts.user_id
+	)
+`
+
+func (r *postRepository) GetAncestors(ctx context.Context, postID uuid.UUID, maxDepth int) ([]*models.Post, error) {
+	query := `
+		WITH RECURSIVE chain (id, reply_to_id, depth) AS (
+			SELECT id, reply_to_id, 0 FROM posts WHERE id = $1
+			UNION ALL
+			SELECT p.id, p.reply_to_id, c.depth + 1
+			FROM posts p
+			JOIN chain c ON p.id = c.reply_to_id
+			WHERE c.depth < $2
+		)
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE id IN (SELECT id FROM chain WHERE depth > 0)
+		ORDER BY created_at ASC
+	`
+
+	return r.queryPosts(ctx, query, postID, maxDepth)
+}
+
+func (r *postRepository) GetSelfThread(ctx context.Context, postID uuid.UUID, maxPosts int) ([]*models.Post, error) {
+	// up: 同じ投稿者の返信元をたどる / down: 先頭から同じ投稿者の最初の返信をたどる
+	query := `
+		WITH RECURSIVE up (id, reply_to_id, user_id, depth) AS (
+			SELECT id, reply_to_id, user_id, 0
+			FROM posts
+			WHERE id = $1 AND ` + visiblePostCondition + `
+			UNION ALL
+			SELECT p.id, p.reply_to_id, p.user_id, u.depth + 1
+			FROM posts p
+			JOIN up u ON p.id = u.reply_to_id
+			WHERE p.user_id = u.user_id AND p.deleted_at IS NULL AND p.moderation_status = 'visible'
+				AND (p.expires_at IS NULL OR p.expires_at > NOW()) AND u.depth < $2
+		),
+		root AS (
+			SELECT id, user_id FROM up ORDER BY depth DESC LIMIT 1
+		),
+		down (id, user_id, depth) AS (
+			SELECT id, user_id, 1 FROM root
+			UNION ALL
+			SELECT next.id, next.user_id, d.depth + 1
+			FROM down d
+			CROSS JOIN LATERAL (
+				SELECT c.id, c.user_id
+				FROM posts c
+				WHERE c.reply_to_id = d.id AND c.user_id = d.user_id
+					AND c.deleted_at IS NULL AND c.moderation_status = 'visible'
+					AND (c.expires_at IS NULL OR c.expires_at > NOW())
+				ORDER BY c.created_at ASC, c.id ASC
+				LIMIT 1
+			) next
+			WHERE d.depth < $2
+		)
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE id IN (SELECT id FROM down)
+		ORDER BY created_at ASC, id ASC
+	`
+
+	return r.queryPosts(ctx, query, postID, maxPosts)
+}
+
 func (r *postRepository) GetReposts(ctx context.Context, postID uuid.UUID, offset, limit int) ([]*models.Post, error) {
 	query := `
-		SELECT id, user_id, content, media_urls, reply_to_id, repost_id,
-			like_count, repost_count, reply_count, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
-		WHERE repost_id = $1
+		WHERE repost_id = $1 AND ` + visiblePostCondition + `
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -201,11 +573,147 @@ func (r *postRepository) GetReposts(ctx context.Context, postID uuid.UUID, offse
 	return r.queryPosts(ctx, query, postID, limit, offset)
 }
 
+func (r *postRepository) GetReposterIDs(ctx context.Context, postID uuid.UUID, offset, limit int) ([]uuid.UUID, map[uuid.UUID]time.Time, error) {
+	query := `
+		SELECT user_id, MAX(created_at) AS reposted_at
+		FROM posts
+		WHERE repost_id = $1 AND ` + visiblePostCondition + `
+		GROUP BY user_id
+		ORDER BY reposted_at DESC, user_id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := readConn(ctx, r.db).Query(ctx, query, postID, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	repostedAt := make(map[uuid.UUID]time.Time)
+	for rows.Next() {
+		var userID uuid.UUID
+		var at time.Time
+		if err := rows.Scan(&userID, &at); err != nil {
+			return nil, nil, err
+		}
+		userIDs = append(userIDs, userID)
+		repostedAt[userID] = at
+	}
+
+	return userIDs, repostedAt, rows.Err()
+}
+
 func (r *postRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
-	query := "SELECT COUNT(*) FROM posts WHERE user_id = $1"
+	query := "SELECT COUNT(*) FROM posts WHERE user_id = $1 AND " + visiblePostCondition
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	err := readConn(ctx, r.db).QueryRow(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// likedPosts selects the posts liked by $1 with the time of the like as liked_at
+const likedPosts = `
+	SELECT posts.*, likes.created_at AS liked_at
+	FROM likes
+	JOIN posts ON posts.id = likes.post_id
+	WHERE likes.user_id = $1
+`
+
+func (r *postRepository) GetLikedByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM (` + likedPosts + `) posts
+		WHERE ` + visiblePostCondition + `
+		ORDER BY liked_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.readPosts(ctx, query, userID, limit, offset)
+}
+
+func (r *postRepository) CountLikedByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := "SELECT COUNT(*) FROM (" + likedPosts + ") posts WHERE " + visiblePostCondition
+
+	var count int64
+	err := readConn(ctx, r.db).QueryRow(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *postRepository) Search(ctx context.Context, query string, offset, limit int) ([]*models.Post, error) {
+	sqlQuery := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE content ILIKE $1 AND ` + visiblePostCondition + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.readPosts(ctx, sqlQuery, "%"+query+"%", limit, offset)
+}
+
+func (r *postRepository) SearchSince(ctx context.Context, query string, since time.Time, limit int) ([]*models.Post, error) {
+	sqlQuery := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE content ILIKE $1 AND created_at > $2 AND ` + visiblePostCondition + `
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	// 保存した検索の語は部分一致として扱うため、% と _ も文字どおりに一致させる
+	return r.queryPosts(ctx, sqlQuery, "%"+likeEscaper.Replace(query)+"%", since, limit)
+}
+
+func (r *postRepository) CountByUserIDs(ctx context.Context, userIDs []uuid.UUID, viewerID uuid.UUID) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		SELECT COUNT(*) FROM posts
+		WHERE user_id = ANY($1) AND ` + visiblePostCondition + `
+			AND user_id NOT IN (` + blockRelatedUserIDs("$2") + `)
+	`
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, userIDs, viewerID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *postRepository) CountNewerByUserIDs(ctx context.Context, userIDs []uuid.UUID, sinceID uuid.UUID) (int64, error) {
+	var since time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, "SELECT created_at FROM posts WHERE id = $1", sinceID).Scan(&since)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errors.New("post not found")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		SELECT COUNT(*) FROM posts
+		WHERE user_id = ANY($1) AND created_at > $2 AND ` + visiblePostCondition + `
+	`
+
+	var count int64
+	err = conn(ctx, r.db).QueryRow(ctx, query, userIDs, since).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -214,10 +722,22 @@ func (r *postRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (i
 }
 
 func (r *postRepository) CountReplies(ctx context.Context, postID uuid.UUID) (int64, error) {
-	query := "SELECT COUNT(*) FROM posts WHERE reply_to_id = $1"
+	query := "SELECT COUNT(*) FROM posts WHERE reply_to_id = $1 AND " + visiblePostCondition
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, postID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, query, postID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *postRepository) CountRepliesBetween(ctx context.Context, userA, userB uuid.UUID) (int64, error) {
+	query := "SELECT COUNT(*) FROM posts WHERE " + repliesBetweenCondition
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, userA, userB).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -226,10 +746,22 @@ func (r *postRepository) CountReplies(ctx context.Context, postID uuid.UUID) (in
 }
 
 func (r *postRepository) CountReposts(ctx context.Context, postID uuid.UUID) (int64, error) {
-	query := "SELECT COUNT(*) FROM posts WHERE repost_id = $1"
+	query := "SELECT COUNT(*) FROM posts WHERE repost_id = $1 AND " + visiblePostCondition
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, postID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *postRepository) CountReposters(ctx context.Context, postID uuid.UUID) (int64, error) {
+	query := "SELECT COUNT(DISTINCT user_id) FROM posts WHERE repost_id = $1 AND " + visiblePostCondition
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, postID).Scan(&count)
+	err := readConn(ctx, r.db).QueryRow(ctx, query, postID).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -237,14 +769,60 @@ func (r *postRepository) CountReposts(ctx context.Context, postID uuid.UUID) (in
 	return count, nil
 }
 
+func (r *postRepository) GetInteractionCounts(ctx context.Context, userID uuid.UUID, authorIDs []uuid.UUID, since time.Time) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(authorIDs) == 0 {
+		return counts, nil
+	}
+
+	// 返信とリポストは返信先・リポスト元の投稿者とのやり取りとして数える
+	query := `
+		SELECT author_id, COUNT(*)
+		FROM (
+			SELECT posts.user_id AS author_id
+			FROM likes
+			JOIN posts ON posts.id = likes.post_id
+			WHERE likes.user_id = $1 AND likes.created_at > $3
+			UNION ALL
+			SELECT parent.user_id
+			FROM posts
+			JOIN posts parent ON parent.id = COALESCE(posts.reply_to_id, posts.repost_id)
+			WHERE posts.user_id = $1 AND posts.created_at > $3 AND posts.deleted_at IS NULL
+		) interactions
+		WHERE author_id = ANY($2) AND author_id <> $1
+		GROUP BY author_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, authorIDs, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var authorID uuid.UUID
+		var count int
+		if err := rows.Scan(&authorID, &count); err != nil {
+			return nil, err
+		}
+		counts[authorID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
 func (r *postRepository) IncrementLikeCount(ctx context.Context, postID uuid.UUID) error {
 	query := `
 		UPDATE posts
 		SET like_count = like_count + 1
-		WHERE id = $1
+		WHERE id = $1 AND ` + livePostCondition + `
 	`
 
-	result, err := r.db.Exec(ctx, query, postID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, postID)
 	if err != nil {
 		return err
 	}
@@ -263,7 +841,7 @@ func (r *postRepository) DecrementLikeCount(ctx context.Context, postID uuid.UUI
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query, postID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, postID)
 	if err != nil {
 		return err
 	}
@@ -279,10 +857,10 @@ func (r *postRepository) IncrementRepostCount(ctx context.Context, postID uuid.U
 	query := `
 		UPDATE posts
 		SET repost_count = repost_count + 1
-		WHERE id = $1
+		WHERE id = $1 AND ` + livePostCondition + `
 	`
 
-	result, err := r.db.Exec(ctx, query, postID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, postID)
 	if err != nil {
 		return err
 	}
@@ -301,7 +879,7 @@ func (r *postRepository) DecrementRepostCount(ctx context.Context, postID uuid.U
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query, postID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, postID)
 	if err != nil {
 		return err
 	}
@@ -317,10 +895,10 @@ func (r *postRepository) IncrementReplyCount(ctx context.Context, postID uuid.UU
 	query := `
 		UPDATE posts
 		SET reply_count = reply_count + 1
-		WHERE id = $1
+		WHERE id = $1 AND ` + livePostCondition + `
 	`
 
-	result, err := r.db.Exec(ctx, query, postID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, postID)
 	if err != nil {
 		return err
 	}
@@ -339,7 +917,7 @@ func (r *postRepository) DecrementReplyCount(ctx context.Context, postID uuid.UU
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query, postID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, postID)
 	if err != nil {
 		return err
 	}
@@ -351,37 +929,416 @@ func (r *postRepository) DecrementReplyCount(ctx context.Context, postID uuid.UU
 	return nil
 }
 
-// queryPosts is a helper function to execute queries that return post lists
-func (r *postRepository) queryPosts(ctx context.Context, query string, args ...interface{}) ([]*models.Post, error) {
-	rows, err := r.db.Query(ctx, query, args...)
+func (r *postRepository) IncrementShareCount(ctx context.Context, postID uuid.UUID) (int64, error) {
+	// 共有を無効にしている投稿は数えない
+	query := `
+		UPDATE posts
+		SET share_count = share_count + 1
+		WHERE id = $1 AND sharing_enabled AND ` + livePostCondition + `
+		RETURNING share_count
+	`
+
+	var shareCount int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, postID).Scan(&shareCount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errors.New("post not found or sharing disabled")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return shareCount, nil
+}
+
+func (r *postRepository) SetSharingEnabled(ctx context.Context, postID uuid.UUID, enabled bool) error {
+	query := `
+		UPDATE posts
+		SET sharing_enabled = $1, updated_at = NOW()
+		WHERE id = $2 AND ` + livePostCondition + `
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, enabled, postID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("post not found")
+	}
+
+	return nil
+}
+
+func (r *postRepository) RemoveMedia(
+	ctx context.Context,
+	postID, editorID uuid.UUID,
+	mediaURLs []string,
+	deleteObjects func(ctx context.Context) error,
+) (*models.Post, error) {
+	if len(mediaURLs) == 0 {
+		return nil, errors.New("no media to remove")
+	}
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 同時に編集されないよう投稿の行をロックする
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts WHERE id = $1 AND ` + livePostCondition + `
+		FOR UPDATE
+	`
+
+	var post models.Post
+	if err := scanPost(tx.QueryRow(ctx, query, postID), &post); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("post not found")
+		}
+		return nil, err
+	}
+	previousMediaURLs := post.MediaURLs
+
+	removing := make(map[string]bool, len(mediaURLs))
+	for _, url := range mediaURLs {
+		removing[url] = true
+	}
+	// 代替テキストは残るメディアと同じ順序に詰める
+	remaining := make([]string, 0, len(post.MediaURLs))
+	remainingAltTexts := make([]string, 0, len(post.MediaURLs))
+	for i, url := range post.MediaURLs {
+		if removing[url] {
+			delete(removing, url)
+			continue
+		}
+		remaining = append(remaining, url)
+		remainingAltTexts = append(remainingAltTexts, post.AltText(i))
+	}
+	if len(removing) > 0 {
+		return nil, errors.New("media not found")
+	}
+
+	remainingJSON, err := json.Marshal(remaining)
+	if err != nil {
+		return nil, err
+	}
+	previousJSON, err := json.Marshal(previousMediaURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	post.MediaURLs = remaining
+	post.MediaAltTexts = remainingAltTexts
+	altTextsJSON, err := mediaAltTextsJSON(&post)
+	if err != nil {
+		return nil, err
+	}
+	post.UpdatedAt = time.Now().UTC()
+	if _, err := tx.Exec(ctx,
+		"UPDATE posts SET media_urls = $1, media_alt_texts = $2, updated_at = $3 WHERE id = $4",
+		remainingJSON, altTextsJSON, post.UpdatedAt, post.ID,
+	); err != nil {
+		return nil, err
+	}
+
+	// 編集履歴を記録
+	editQuery := `
+		INSERT INTO post_edits (post_id, editor_id, action, previous_content, previous_media_urls, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(ctx, editQuery,
+		post.ID, editorID, models.PostEditRemoveMedia, post.Content, previousJSON, post.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	// ストレージのファイルを削除できなかった場合は投稿を変更しない
+	if deleteObjects != nil {
+		if err := deleteObjects(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (r *postRepository) GetEdits(ctx context.Context, postID uuid.UUID) ([]*models.PostEdit, error) {
+	query := `
+		SELECT id, post_id, editor_id, action, previous_content, previous_media_urls, created_at
+		FROM post_edits
+		WHERE post_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, postID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var posts []*models.Post
+	var edits []*models.PostEdit
 	for rows.Next() {
-		var post models.Post
-		var mediaURLsJSON []byte
-		err := rows.Scan(
-			&post.ID, &post.UserID, &post.Content, &mediaURLsJSON,
-			&post.ReplyToID, &post.RepostID, &post.LikeCount,
-			&post.RepostCount, &post.ReplyCount, &post.CreatedAt, &post.UpdatedAt,
-		)
-		if err != nil {
+		var edit models.PostEdit
+		var previousMediaURLsJSON []byte
+		if err := rows.Scan(
+			&edit.ID, &edit.PostID, &edit.EditorID, &edit.Action,
+			&edit.PreviousContent, &previousMediaURLsJSON, &edit.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
-
-		if mediaURLsJSON != nil {
-			err = json.Unmarshal(mediaURLsJSON, &post.MediaURLs)
-			if err != nil {
+		if previousMediaURLsJSON != nil {
+			if err := json.Unmarshal(previousMediaURLsJSON, &edit.PreviousMediaURLs); err != nil {
 				return nil, err
 			}
 		}
+		edits = append(edits, &edit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return edits, nil
+}
+
+func (r *postRepository) SetModerationStatus(
+	ctx context.Context,
+	postID, moderatorID uuid.UUID,
+	status models.ModerationStatus,
+	reason string,
+) error {
+	switch status {
+	case models.ModerationStatusVisible, models.ModerationStatusHidden, models.ModerationStatusRemoved:
+	default:
+		return errors.New("invalid moderation status")
+	}
+
+	query := `
+		UPDATE posts
+		SET moderation_status = $1, moderation_reason = $2, moderated_by = $3, moderated_at = NOW()
+		WHERE id = $4 AND deleted_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, status, reason, moderatorID, postID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("post not found")
+	}
+
+	return nil
+}
+
+func (r *postRepository) SetContentWarning(ctx context.Context, postID uuid.UUID, warning string) error {
+	if len([]rune(warning)) > models.MaxContentWarningLength {
+		return errors.New("content warning too long")
+	}
+
+	query := `
+		UPDATE posts
+		SET content_warning = $1, updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, warning, postID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("post not found")
+	}
+
+	return nil
+}
+
+func (r *postRepository) SetRepliesLocked(ctx context.Context, postID uuid.UUID, locked bool) error {
+	query := `
+		UPDATE posts
+		SET replies_locked = $1, updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, locked, postID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("post not found")
+	}
+
+	return nil
+}
+
+// moderatedPostCondition matches posts hidden or removed by moderators, or only those with status $1 when it is not empty
+const moderatedPostCondition = `deleted_at IS NULL
+	AND moderation_status <> 'visible'
+	AND ($1::text = '' OR moderation_status = $1)`
+
+func (r *postRepository) ListModerated(ctx context.Context, status models.ModerationStatus, offset, limit int) ([]*models.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE ` + moderatedPostCondition + `
+		ORDER BY moderated_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryPosts(ctx, query, status, limit, offset)
+}
+
+func (r *postRepository) CountModerated(ctx context.Context, status models.ModerationStatus) (int64, error) {
+	query := "SELECT COUNT(*) FROM posts WHERE " + moderatedPostCondition
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, status).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetAccessibilityStats counts language tags and, per file extension, alt texts
+// of the media attached to non-deleted posts created after since. Reposts carry
+// no media of their own and are left out.
+func (r *postRepository) GetAccessibilityStats(ctx context.Context, since time.Time) (*models.AccessibilityStats, error) {
+	stats := &models.AccessibilityStats{Media: []models.AccessibilityMediaCount{}}
+
+	postsQuery := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE language <> ''),
+			COUNT(*) FILTER (WHERE jsonb_typeof(media_urls) = 'array' AND jsonb_array_length(media_urls) > 0)
+		FROM posts
+		WHERE created_at > $1 AND deleted_at IS NULL AND repost_id IS NULL
+	`
+	if err := conn(ctx, r.db).QueryRow(ctx, postsQuery, since).Scan(
+		&stats.Posts, &stats.PostsWithLanguage, &stats.PostsWithMedia,
+	); err != nil {
+		return nil, err
+	}
+
+	// media_alt_textsはmedia_urlsと同じ順序のため、位置で対応させる
+	mediaQuery := `
+		SELECT
+			LOWER(COALESCE(SUBSTRING(m.url FROM '\.([A-Za-z0-9]+)(?:[?#].*)?$'), '')) AS extension,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE COALESCE(p.media_alt_texts ->> (m.ord::int - 1), '') <> '')
+		FROM posts p
+		CROSS JOIN LATERAL jsonb_array_elements_text(
+			CASE WHEN jsonb_typeof(p.media_urls) = 'array' THEN p.media_urls ELSE '[]'::jsonb END
+		) WITH ORDINALITY AS m(url, ord)
+		WHERE p.created_at > $1 AND p.deleted_at IS NULL AND p.repost_id IS NULL
+		GROUP BY 1
+		ORDER BY 1
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, mediaQuery, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count models.AccessibilityMediaCount
+		if err := rows.Scan(&count.Extension, &count.Total, &count.WithAltText); err != nil {
+			return nil, err
+		}
+		stats.Media = append(stats.Media, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		post.IsReply = post.ReplyToID != nil
-		post.IsRepost = post.RepostID != nil
+	return stats, nil
+}
+
+// PurgeExpired permanently deletes up to limit expired posts, earliest expiration
+// first, and returns them so that their media can be released. The reply and
+// repost counts of the posts they referred to are decremented unless the expired
+// post had already been soft-deleted, which decremented them at that time.
+// Posts that were still live are recorded as deleted for client delta sync.
+func (r *postRepository) PurgeExpired(ctx context.Context, limit int) ([]*models.Post, error) {
+	// Rows deleted by this statement are excluded from the count updates, since a
+	// single statement must not both update and delete the same row.
+	query := `
+		WITH expired AS (
+			SELECT id FROM posts
+			WHERE expires_at <= NOW()
+			ORDER BY expires_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		),
+		replies AS (
+			UPDATE posts p
+			SET reply_count = GREATEST(p.reply_count - c.count, 0)
+			FROM (
+				SELECT reply_to_id AS id, COUNT(*) AS count
+				FROM posts
+				WHERE id IN (SELECT id FROM expired) AND reply_to_id IS NOT NULL AND deleted_at IS NULL
+				GROUP BY reply_to_id
+			) c
+			WHERE p.id = c.id AND p.id NOT IN (SELECT id FROM expired)
+		),
+		reposts AS (
+			UPDATE posts p
+			SET repost_count = GREATEST(p.repost_count - c.count, 0)
+			FROM (
+				SELECT repost_id AS id, COUNT(*) AS count
+				FROM posts
+				WHERE id IN (SELECT id FROM expired) AND repost_id IS NOT NULL AND deleted_at IS NULL
+				GROUP BY repost_id
+			) c
+			WHERE p.id = c.id AND p.id NOT IN (SELECT id FROM expired)
+		),
+		purged AS (
+			DELETE FROM posts
+			WHERE id IN (SELECT id FROM expired)
+			RETURNING ` + postColumns + `
+		),
+		events AS (
+			INSERT INTO sync_events (event_type, user_id, subject_id)
+			SELECT 'post_deleted', user_id, id FROM purged WHERE deleted_at IS NULL
+		)
+		SELECT ` + postColumns + ` FROM purged`
 
+	return r.queryPosts(ctx, query, limit)
+}
+
+// queryPosts is a helper function to execute queries that return post lists
+func (r *postRepository) queryPosts(ctx context.Context, query string, args ...interface{}) ([]*models.Post, error) {
+	return queryPostsOn(ctx, conn(ctx, r.db), query, args...)
+}
+
+// readPosts is queryPosts for reads that tolerate replication lag (timelines, profiles and searches),
+// which run on the read replica when one is configured
+func (r *postRepository) readPosts(ctx context.Context, query string, args ...interface{}) ([]*models.Post, error) {
+	return queryPostsOn(ctx, readConn(ctx, r.db), query, args...)
+}
+
+func queryPostsOn(ctx context.Context, db dbtx, query string, args ...interface{}) ([]*models.Post, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*models.Post
+	for rows.Next() {
+		var post models.Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, err
+		}
 		posts = append(posts, &post)
 	}
 
@@ -391,3 +1348,39 @@ func (r *postRepository) queryPosts(ctx context.Context, query string, args ...i
 
 	return posts, nil
 }
+
+// scanPost scans a row selected with postColumns into post
+func scanPost(row pgx.Row, post *models.Post) error {
+	var mediaURLsJSON, altTextsJSON []byte
+	err := row.Scan(
+		&post.ID, &post.UserID, &post.Content, &mediaURLsJSON,
+		&post.ReplyToID, &post.RepostID, &post.LikeCount,
+		&post.RepostCount, &post.ReplyCount, &post.ViewCount, &post.ShareCount,
+		&post.ContentRating, &post.ReplyPolicy, &post.SharingEnabled,
+		&post.CreatedAt, &post.UpdatedAt, &post.DeletedAt,
+		&post.ModerationStatus, &post.ModerationReason, &post.ModeratedBy, &post.ModeratedAt,
+		&post.ContentWarning, &post.RepliesLocked,
+		&altTextsJSON, &post.Language, &post.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if mediaURLsJSON != nil {
+		if err := json.Unmarshal(mediaURLsJSON, &post.MediaURLs); err != nil {
+			return err
+		}
+	}
+	var altTexts []string
+	if altTextsJSON != nil {
+		if err := json.Unmarshal(altTextsJSON, &altTexts); err != nil {
+			return err
+		}
+	}
+	post.SetMediaAltTexts(altTexts)
+
+	post.IsReply = post.ReplyToID != nil
+	post.IsRepost = post.RepostID != nil
+
+	return nil
+}
@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -76,6 +78,21 @@ func TestPostRepository(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	// GetByIDs のテスト
+	t.Run("GetByIDs", func(t *testing.T) {
+		missingID := uuid.New()
+		posts, err := postRepo.GetByIDs(ctx, []uuid.UUID{testPost.ID, missingID})
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, testPost.Content, posts[testPost.ID].Content)
+		assert.NotContains(t, posts, missingID)
+
+		// 空のIDリスト
+		posts, err = postRepo.GetByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+
 	// Update のテスト
 	t.Run("Update", func(t *testing.T) {
 		testPost.Content = "Updated content"
@@ -88,6 +105,32 @@ func TestPostRepository(t *testing.T) {
 		assert.Equal(t, "Updated content", updated.Content)
 	})
 
+	// ContentRating のテスト
+	t.Run("ContentRating", func(t *testing.T) {
+		// 未指定の場合はgeneralとして保存される
+		post, err := postRepo.GetByID(ctx, testPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ContentRatingGeneral, post.ContentRating)
+
+		testPost.ContentRating = models.ContentRatingAdult
+		err = postRepo.Update(ctx, testPost)
+		require.NoError(t, err)
+
+		post, err = postRepo.GetByID(ctx, testPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ContentRatingAdult, post.ContentRating)
+
+		// 不正なレーティング
+		testPost.ContentRating = models.ContentRating("unknown")
+		err = postRepo.Update(ctx, testPost)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid content rating")
+
+		testPost.ContentRating = models.ContentRatingGeneral
+		err = postRepo.Update(ctx, testPost)
+		require.NoError(t, err)
+	})
+
 	// GetByUserID のテスト
 	t.Run("GetByUserID", func(t *testing.T) {
 		posts, err := postRepo.GetByUserID(ctx, testUser.ID, 0, 10)
@@ -101,6 +144,325 @@ func TestPostRepository(t *testing.T) {
 		assert.Empty(t, posts)
 	})
 
+	// GetRepliesBetween と CountRepliesBetween のテスト
+	t.Run("GetRepliesBetween", func(t *testing.T) {
+		other := &models.User{
+			ID:        uuid.New(),
+			Username:  "replypartner",
+			Email:     "replypartner@example.com",
+			Password:  "hashedpassword",
+			Name:      "Reply Partner",
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, other))
+
+		root := models.NewPost(other.ID, "Root by partner", nil)
+		require.NoError(t, postRepo.Create(ctx, root))
+
+		// 相手への返信
+		toOther := models.NewReply(testUser.ID, root.ID, "Reply to partner", nil)
+		toOther.CreatedAt = time.Now().UTC().Add(-time.Minute)
+		require.NoError(t, postRepo.Create(ctx, toOther))
+
+		// 相手からの返信
+		fromOther := models.NewReply(other.ID, toOther.ID, "Reply back", nil)
+		require.NoError(t, postRepo.Create(ctx, fromOther))
+
+		// 自分の投稿への自分の返信は含めない
+		selfReply := models.NewReply(other.ID, root.ID, "Self reply", nil)
+		require.NoError(t, postRepo.Create(ctx, selfReply))
+
+		replies, err := postRepo.GetRepliesBetween(ctx, testUser.ID, other.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, replies, 2)
+		assert.Equal(t, fromOther.ID, replies[0].ID)
+		assert.Equal(t, toOther.ID, replies[1].ID)
+
+		// ユーザーの順序は問わない
+		replies, err = postRepo.GetRepliesBetween(ctx, other.ID, testUser.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, replies, 2)
+
+		count, err := postRepo.CountRepliesBetween(ctx, testUser.ID, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// やり取りのないユーザー
+		replies, err = postRepo.GetRepliesBetween(ctx, testUser.ID, uuid.New(), 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, replies)
+
+		for _, post := range []*models.Post{fromOther, selfReply, toOther, root} {
+			require.NoError(t, postRepo.Delete(ctx, post.ID))
+		}
+	})
+
+	// ReplyPolicy のテスト
+	t.Run("ReplyPolicy", func(t *testing.T) {
+		post, err := postRepo.GetByID(ctx, testPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ReplyPolicyEveryone, post.ReplyPolicy)
+
+		testPost.ReplyPolicy = models.ReplyPolicyFollowers
+		err = postRepo.Update(ctx, testPost)
+		require.NoError(t, err)
+
+		post, err = postRepo.GetByID(ctx, testPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ReplyPolicyFollowers, post.ReplyPolicy)
+
+		// 不正な返信設定
+		testPost.ReplyPolicy = models.ReplyPolicy("nobody")
+		err = postRepo.Update(ctx, testPost)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid reply policy")
+
+		testPost.ReplyPolicy = models.ReplyPolicyEveryone
+		err = postRepo.Update(ctx, testPost)
+		require.NoError(t, err)
+	})
+
+	// 共有数と共有設定のテスト
+	t.Run("Sharing", func(t *testing.T) {
+		shared := models.NewPost(testUser.ID, "Shareable post", nil)
+		err := postRepo.Create(ctx, shared)
+		require.NoError(t, err)
+
+		post, err := postRepo.GetByID(ctx, shared.ID)
+		require.NoError(t, err)
+		assert.True(t, post.SharingEnabled)
+		assert.Equal(t, int64(0), post.ShareCount)
+
+		count, err := postRepo.IncrementShareCount(ctx, shared.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// 共有を無効にすると共有数は増えない
+		err = postRepo.SetSharingEnabled(ctx, shared.ID, false)
+		require.NoError(t, err)
+		_, err = postRepo.IncrementShareCount(ctx, shared.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "sharing disabled")
+
+		post, err = postRepo.GetByID(ctx, shared.ID)
+		require.NoError(t, err)
+		assert.False(t, post.SharingEnabled)
+		assert.Equal(t, int64(1), post.ShareCount)
+
+		// 存在しない投稿
+		err = postRepo.SetSharingEnabled(ctx, uuid.New(), true)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "post not found")
+
+		err = postRepo.Delete(ctx, shared.ID)
+		require.NoError(t, err)
+	})
+
+	// モデレーション（非表示・削除・注意書き・返信のロック）のテスト
+	t.Run("Moderation", func(t *testing.T) {
+		moderated := models.NewPost(testUser.ID, "Moderated post", nil)
+		err := postRepo.Create(ctx, moderated)
+		require.NoError(t, err)
+
+		// 注意書きと返信のロック
+		err = postRepo.SetContentWarning(ctx, moderated.ID, "Spoilers")
+		require.NoError(t, err)
+		err = postRepo.SetRepliesLocked(ctx, moderated.ID, true)
+		require.NoError(t, err)
+
+		post, err := postRepo.GetByID(ctx, moderated.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Spoilers", post.ContentWarning)
+		assert.True(t, post.RepliesLocked)
+		assert.Equal(t, models.ModerationStatusVisible, post.ModerationStatus)
+
+		err = postRepo.SetContentWarning(ctx, moderated.ID, strings.Repeat("a", models.MaxContentWarningLength+1))
+		assert.Error(t, err)
+
+		// 削除した投稿は取得・一覧・件数に含まれない
+		err = postRepo.SetModerationStatus(ctx, moderated.ID, testUser.ID, models.ModerationStatusRemoved, "spam")
+		require.NoError(t, err)
+
+		_, err = postRepo.GetByID(ctx, moderated.ID)
+		assert.Error(t, err)
+
+		postsByID, err := postRepo.GetByIDs(ctx, []uuid.UUID{moderated.ID})
+		require.NoError(t, err)
+		assert.Empty(t, postsByID)
+
+		posts, err := postRepo.GetByUserID(ctx, testUser.ID, 0, 100)
+		require.NoError(t, err)
+		for _, p := range posts {
+			assert.NotEqual(t, moderated.ID, p.ID)
+		}
+
+		// 削除した投稿にはいいねできない
+		err = postRepo.IncrementLikeCount(ctx, moderated.ID)
+		assert.Error(t, err)
+
+		// モデレーション用の取得では対応の内容を確認できる
+		removed, err := postRepo.GetByIDIncludingDeleted(ctx, moderated.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ModerationStatusRemoved, removed.ModerationStatus)
+		assert.Equal(t, "spam", removed.ModerationReason)
+		require.NotNil(t, removed.ModeratedBy)
+		assert.Equal(t, testUser.ID, *removed.ModeratedBy)
+		assert.NotNil(t, removed.ModeratedAt)
+		assert.True(t, removed.IsUnavailable())
+
+		moderatedPosts, err := postRepo.ListModerated(ctx, models.ModerationStatusRemoved, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, moderatedPosts, 1)
+		assert.Equal(t, moderated.ID, moderatedPosts[0].ID)
+
+		count, err := postRepo.CountModerated(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		count, err = postRepo.CountModerated(ctx, models.ModerationStatusHidden)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		// 元に戻すと再び取得できる
+		err = postRepo.SetModerationStatus(ctx, moderated.ID, testUser.ID, models.ModerationStatusVisible, "")
+		require.NoError(t, err)
+
+		post, err = postRepo.GetByID(ctx, moderated.ID)
+		require.NoError(t, err)
+		assert.False(t, post.IsUnavailable())
+
+		// 不正な状態と存在しない投稿
+		err = postRepo.SetModerationStatus(ctx, moderated.ID, testUser.ID, models.ModerationStatus("unknown"), "")
+		assert.Error(t, err)
+		err = postRepo.SetModerationStatus(ctx, uuid.New(), testUser.ID, models.ModerationStatusHidden, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "post not found")
+
+		err = postRepo.Delete(ctx, moderated.ID)
+		require.NoError(t, err)
+	})
+
+	// RemoveMedia と GetEdits のテスト
+	t.Run("RemoveMedia", func(t *testing.T) {
+		withMedia := models.NewPost(testUser.ID, "Post with media", []string{"a.jpg", "b.jpg", "c.jpg"})
+		withMedia.SetMediaAltTexts([]string{"A", "B"})
+		err := postRepo.Create(ctx, withMedia)
+		require.NoError(t, err)
+
+		// 添付されていないメディアは取り除けない
+		_, err = postRepo.RemoveMedia(ctx, withMedia.ID, testUser.ID, []string{"a.jpg", "x.jpg"}, nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "media not found")
+
+		// ファイルの削除に失敗した場合は投稿を変更しない
+		_, err = postRepo.RemoveMedia(ctx, withMedia.ID, testUser.ID, []string{"b.jpg"}, func(ctx context.Context) error {
+			return errors.New("storage unavailable")
+		})
+		assert.Error(t, err)
+
+		post, err := postRepo.GetByID(ctx, withMedia.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.jpg", "b.jpg", "c.jpg"}, post.MediaURLs)
+
+		deleted := false
+		updated, err := postRepo.RemoveMedia(ctx, withMedia.ID, testUser.ID, []string{"b.jpg"}, func(ctx context.Context) error {
+			deleted = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, []string{"a.jpg", "c.jpg"}, updated.MediaURLs)
+
+		post, err = postRepo.GetByID(ctx, withMedia.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.jpg", "c.jpg"}, post.MediaURLs)
+		// 代替テキストも残ったメディアと同じ順序になる
+		assert.Equal(t, []string{"A", ""}, post.MediaAltTexts)
+
+		// 編集前の状態が履歴に残る
+		edits, err := postRepo.GetEdits(ctx, withMedia.ID)
+		require.NoError(t, err)
+		require.Len(t, edits, 1)
+		assert.Equal(t, models.PostEditRemoveMedia, edits[0].Action)
+		assert.Equal(t, testUser.ID, edits[0].EditorID)
+		assert.Equal(t, "Post with media", edits[0].PreviousContent)
+		assert.Equal(t, []string{"a.jpg", "b.jpg", "c.jpg"}, edits[0].PreviousMediaURLs)
+
+		// 存在しない投稿
+		_, err = postRepo.RemoveMedia(ctx, uuid.New(), testUser.ID, []string{"a.jpg"}, nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "post not found")
+
+		err = postRepo.Delete(ctx, withMedia.ID)
+		require.NoError(t, err)
+	})
+
+	// 代替テキスト・言語と GetAccessibilityStats のテスト
+	t.Run("Accessibility", func(t *testing.T) {
+		since := time.Now().Add(-time.Second)
+
+		described := models.NewPost(testUser.ID, "Described media", []string{"a.png", "b.gif"})
+		described.SetMediaAltTexts([]string{"  A cat  ", ""})
+		described.Language = "ja"
+		require.NoError(t, postRepo.Create(ctx, described))
+
+		undescribed := models.NewPost(testUser.ID, "Undescribed media", []string{"https://example.com/c.PNG?size=large"})
+		require.NoError(t, postRepo.Create(ctx, undescribed))
+
+		post, err := postRepo.GetByID(ctx, described.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A cat", ""}, post.MediaAltTexts)
+		assert.Equal(t, "ja", post.Language)
+
+		// メディアより多い代替テキストは保存できない
+		invalid := models.NewPost(testUser.ID, "Too many alt texts", []string{"d.png"})
+		invalid.MediaAltTexts = []string{"D", "E"}
+		err = postRepo.Create(ctx, invalid)
+		assert.Error(t, err)
+
+		stats, err := postRepo.GetAccessibilityStats(ctx, since)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), stats.Posts)
+		assert.Equal(t, int64(1), stats.PostsWithLanguage)
+		assert.Equal(t, int64(2), stats.PostsWithMedia)
+		assert.Equal(t, []models.AccessibilityMediaCount{
+			{Extension: "gif", Total: 1, WithAltText: 0},
+			{Extension: "png", Total: 2, WithAltText: 1},
+		}, stats.Media)
+
+		require.NoError(t, postRepo.Delete(ctx, described.ID))
+		require.NoError(t, postRepo.Delete(ctx, undescribed.ID))
+
+		// 削除された投稿は集計しない
+		stats, err = postRepo.GetAccessibilityStats(ctx, since)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats.Posts)
+		assert.Empty(t, stats.Media)
+	})
+
+	// CountNewerByUserIDs のテスト
+	t.Run("CountNewerByUserIDs", func(t *testing.T) {
+		newer := models.NewPost(testUser.ID, "Newer post", nil)
+		newer.CreatedAt = testPost.CreatedAt.Add(time.Minute)
+		err := postRepo.Create(ctx, newer)
+		require.NoError(t, err)
+		defer postRepo.Delete(ctx, newer.ID)
+
+		count, err := postRepo.CountNewerByUserIDs(ctx, []uuid.UUID{testUser.ID}, testPost.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		count, err = postRepo.CountNewerByUserIDs(ctx, []uuid.UUID{testUser.ID}, newer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		// 存在しない投稿IDを指定
+		_, err = postRepo.CountNewerByUserIDs(ctx, []uuid.UUID{testUser.ID}, uuid.New())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "post not found")
+	})
+
 	// Reply機能のテスト
 	t.Run("Reply", func(t *testing.T) {
 		// 返信の作成
@@ -117,7 +479,7 @@ func TestPostRepository(t *testing.T) {
 		require.NoError(t, err)
 
 		// 返信の取得
-		replies, err := postRepo.GetReplies(ctx, testPost.ID, 0, 10)
+		replies, err := postRepo.GetReplies(ctx, testPost.ID, models.ReplySortNewest, 0, 10)
 		require.NoError(t, err)
 		assert.NotEmpty(t, replies)
 		assert.Equal(t, replyID, replies[0].ID)
@@ -128,6 +490,176 @@ func TestPostRepository(t *testing.T) {
 		assert.Equal(t, int64(1), count)
 	})
 
+	// 返信の並び順のテスト
+	t.Run("GetRepliesSorted", func(t *testing.T) {
+		now := time.Now().UTC()
+		root := &models.Post{ID: uuid.New(), UserID: testUser.ID, Content: "Sorted root", CreatedAt: now.Add(-time.Hour), UpdatedAt: now}
+		require.NoError(t, postRepo.Create(ctx, root))
+
+		first := &models.Post{ID: uuid.New(), UserID: testUser.ID, Content: "First", IsReply: true, ReplyToID: &root.ID, CreatedAt: now.Add(-3 * time.Minute), UpdatedAt: now}
+		second := &models.Post{ID: uuid.New(), UserID: testUser.ID, Content: "Second", IsReply: true, ReplyToID: &root.ID, CreatedAt: now.Add(-2 * time.Minute), UpdatedAt: now}
+		third := &models.Post{ID: uuid.New(), UserID: testUser.ID, Content: "Third", IsReply: true, ReplyToID: &root.ID, CreatedAt: now.Add(-time.Minute), UpdatedAt: now}
+		for _, p := range []*models.Post{first, second, third} {
+			require.NoError(t, postRepo.Create(ctx, p))
+		}
+
+		// 2番目の返信が最も反応が多い
+		require.NoError(t, postRepo.IncrementLikeCount(ctx, second.ID))
+		require.NoError(t, postRepo.IncrementLikeCount(ctx, second.ID))
+		require.NoError(t, postRepo.IncrementReplyCount(ctx, first.ID))
+
+		ids := func(sort models.ReplySort) []uuid.UUID {
+			replies, err := postRepo.GetReplies(ctx, root.ID, sort, 0, 10)
+			require.NoError(t, err)
+			result := make([]uuid.UUID, 0, len(replies))
+			for _, reply := range replies {
+				result = append(result, reply.ID)
+			}
+			return result
+		}
+
+		assert.Equal(t, []uuid.UUID{third.ID, second.ID, first.ID}, ids(models.ReplySortNewest))
+		assert.Equal(t, []uuid.UUID{first.ID, second.ID, third.ID}, ids(models.ReplySortOldest))
+		assert.Equal(t, []uuid.UUID{second.ID, first.ID, third.ID}, ids(models.ReplySortTop))
+
+		// 未知の並び順は新しい順
+		assert.Equal(t, []uuid.UUID{third.ID, second.ID, first.ID}, ids(models.ReplySort("unknown")))
+	})
+
+	// GetAncestors のテスト
+	t.Run("GetAncestors", func(t *testing.T) {
+		now := time.Now().UTC()
+		root := &models.Post{ID: uuid.New(), UserID: testUser.ID, Content: "Root", CreatedAt: now.Add(-3 * time.Minute), UpdatedAt: now}
+		middle := &models.Post{ID: uuid.New(), UserID: testUser.ID, Content: "Middle", IsReply: true, ReplyToID: &root.ID, CreatedAt: now.Add(-2 * time.Minute), UpdatedAt: now}
+		leaf := &models.Post{ID: uuid.New(), UserID: testUser.ID, Content: "Leaf", IsReply: true, ReplyToID: &middle.ID, CreatedAt: now.Add(-time.Minute), UpdatedAt: now}
+		for _, p := range []*models.Post{root, middle, leaf} {
+			require.NoError(t, postRepo.Create(ctx, p))
+		}
+
+		// ルートから順に祖先投稿が返される
+		ancestors, err := postRepo.GetAncestors(ctx, leaf.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, ancestors, 2)
+		assert.Equal(t, root.ID, ancestors[0].ID)
+		assert.Equal(t, middle.ID, ancestors[1].ID)
+
+		// 深さの上限
+		ancestors, err = postRepo.GetAncestors(ctx, leaf.ID, 1)
+		require.NoError(t, err)
+		require.Len(t, ancestors, 1)
+		assert.Equal(t, middle.ID, ancestors[0].ID)
+
+		// ルート投稿には祖先がない
+		ancestors, err = postRepo.GetAncestors(ctx, root.ID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, ancestors)
+	})
+
+	// CreateThread のテスト
+	t.Run("CreateThread", func(t *testing.T) {
+		root := models.NewPost(testUser.ID, "Thread root", nil)
+		require.NoError(t, postRepo.Create(ctx, root))
+
+		first := models.NewReply(testUser.ID, root.ID, "Thread 1/3", nil)
+		second := models.NewReply(testUser.ID, first.ID, "Thread 2/3", nil)
+		third := models.NewReply(testUser.ID, second.ID, "Thread 3/3", nil)
+		second.CreatedAt = first.CreatedAt.Add(time.Millisecond)
+		third.CreatedAt = first.CreatedAt.Add(2 * time.Millisecond)
+
+		err := postRepo.CreateThread(ctx, []*models.Post{first, second, third})
+		require.NoError(t, err)
+
+		// スレッドが返信でつながっている
+		ancestors, err := postRepo.GetAncestors(ctx, third.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, ancestors, 3)
+		assert.Equal(t, root.ID, ancestors[0].ID)
+		assert.Equal(t, first.ID, ancestors[1].ID)
+		assert.Equal(t, second.ID, ancestors[2].ID)
+
+		// 返信先の返信数が更新されている
+		saved, err := postRepo.GetByID(ctx, root.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, saved.ReplyCount)
+
+		saved, err = postRepo.GetByID(ctx, second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, saved.ReplyCount)
+
+		// 途中の投稿が不正な場合はスレッド全体が作成されない
+		valid := models.NewPost(testUser.ID, "Valid", nil)
+		invalid := models.NewReply(testUser.ID, valid.ID, "", nil)
+		err = postRepo.CreateThread(ctx, []*models.Post{valid, invalid})
+		assert.Error(t, err)
+		_, err = postRepo.GetByID(ctx, valid.ID)
+		assert.Error(t, err)
+
+		// 返信先が存在しない場合はロールバックされる
+		orphan := models.NewReply(testUser.ID, uuid.New(), "Orphan", nil)
+		err = postRepo.CreateThread(ctx, []*models.Post{orphan})
+		assert.Error(t, err)
+		_, err = postRepo.GetByID(ctx, orphan.ID)
+		assert.Error(t, err)
+	})
+
+	// GetSelfThread のテスト
+	t.Run("GetSelfThread", func(t *testing.T) {
+		otherUser := &models.User{
+			ID:        uuid.New(),
+			Username:  "threadother",
+			Email:     "threadother@example.com",
+			Password:  "hashedpassword",
+			Name:      "Thread Other",
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, otherUser))
+
+		// 他のユーザーの投稿への返信から始まる自分のスレッド
+		question := models.NewPost(otherUser.ID, "Question", nil)
+		first := models.NewReply(testUser.ID, question.ID, "Answer 1/3", nil)
+		second := models.NewReply(testUser.ID, first.ID, "Answer 2/3", nil)
+		third := models.NewReply(testUser.ID, second.ID, "Answer 3/3", nil)
+		second.CreatedAt = first.CreatedAt.Add(time.Millisecond)
+		third.CreatedAt = first.CreatedAt.Add(2 * time.Millisecond)
+		require.NoError(t, postRepo.Create(ctx, question))
+		require.NoError(t, postRepo.CreateThread(ctx, []*models.Post{first, second, third}))
+
+		// 途中の投稿への他のユーザーの返信と、後から付けた自分の2つ目の返信はスレッドに含めない
+		interjection := models.NewReply(otherUser.ID, second.ID, "Interjection", nil)
+		require.NoError(t, postRepo.Create(ctx, interjection))
+		branch := models.NewReply(testUser.ID, first.ID, "Branch", nil)
+		branch.CreatedAt = first.CreatedAt.Add(time.Hour)
+		require.NoError(t, postRepo.Create(ctx, branch))
+
+		// スレッドのどの投稿からでも同じスレッドを返す
+		for _, post := range []*models.Post{first, second, third} {
+			thread, err := postRepo.GetSelfThread(ctx, post.ID, 100)
+			require.NoError(t, err)
+			require.Len(t, thread, 3)
+			assert.Equal(t, first.ID, thread[0].ID)
+			assert.Equal(t, second.ID, thread[1].ID)
+			assert.Equal(t, third.ID, thread[2].ID)
+		}
+
+		// 最大件数で打ち切る
+		thread, err := postRepo.GetSelfThread(ctx, third.ID, 2)
+		require.NoError(t, err)
+		require.Len(t, thread, 2)
+		assert.Equal(t, first.ID, thread[0].ID)
+
+		// 他のユーザーの投稿は単独の投稿として返す
+		thread, err = postRepo.GetSelfThread(ctx, interjection.ID, 100)
+		require.NoError(t, err)
+		require.Len(t, thread, 1)
+		assert.Equal(t, interjection.ID, thread[0].ID)
+
+		// 存在しない投稿の場合は空
+		thread, err = postRepo.GetSelfThread(ctx, uuid.New(), 100)
+		require.NoError(t, err)
+		assert.Empty(t, thread)
+	})
+
 	// Repost機能のテスト
 	t.Run("Repost", func(t *testing.T) {
 		// リポストの作成
@@ -318,6 +850,22 @@ func TestPostRepository(t *testing.T) {
 		// 削除されたことを確認
 		_, err = postRepo.GetByID(ctx, testPost.ID)
 		assert.Error(t, err)
+
+		// 行は残り、削除済みとして取得できる
+		deleted, err := postRepo.GetByIDIncludingDeleted(ctx, testPost.ID)
+		require.NoError(t, err)
+		assert.True(t, deleted.IsDeleted())
+
+		// 一覧や件数に含まれない
+		posts, err := postRepo.GetByUserID(ctx, testPost.UserID, 0, 100)
+		require.NoError(t, err)
+		for _, post := range posts {
+			assert.NotEqual(t, testPost.ID, post.ID)
+		}
+
+		// 削除済みの投稿は再度削除できない
+		err = postRepo.Delete(ctx, testPost.ID)
+		assert.Error(t, err)
 	})
 
 	// データ制約のテスト
@@ -452,4 +1000,143 @@ func TestPostRepository_Counts(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), count)
 	})
+	// 人気順の投稿一覧のテスト
+	t.Run("ListPopular", func(t *testing.T) {
+		now := time.Now().UTC()
+		newPost := func(content string, age time.Duration, likes, reposts int) *models.Post {
+			post := models.NewPost(testUser.ID, content, nil)
+			post.LikeCount = likes
+			post.RepostCount = reposts
+			post.CreatedAt = now.Add(-age)
+			post.UpdatedAt = post.CreatedAt
+			require.NoError(t, postRepo.Create(ctx, post))
+			return post
+		}
+
+		// 古くてもエンゲージメントが多い投稿は上位になる
+		hot := newPost("Hot post", 3*time.Hour, 50, 10)
+		fresh := newPost("Fresh post", 10*time.Minute, 5, 0)
+		// 同じエンゲージメントなら新しい投稿の方が上位になる
+		stale := newPost("Stale post", 48*time.Hour, 5, 0)
+		// 期間外の投稿は含まれない
+		newPost("Old viral post", 30*24*time.Hour, 1000, 100)
+		spoiler := newPost("Spoiler alert", time.Hour, 60, 0)
+
+		posts, err := postRepo.ListPopular(ctx, uuid.Nil, 7*24*time.Hour, []string{"spoiler"}, 0, 3)
+		require.NoError(t, err)
+		require.Len(t, posts, 3)
+		assert.Equal(t, hot.ID, posts[0].ID)
+		assert.Equal(t, fresh.ID, posts[1].ID)
+		assert.Equal(t, stale.ID, posts[2].ID)
+
+		// ページをまたいでも順位が一貫する
+		posts, err = postRepo.ListPopular(ctx, uuid.Nil, 7*24*time.Hour, nil, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, spoiler.ID, posts[0].ID)
+		posts, err = postRepo.ListPopular(ctx, uuid.Nil, 7*24*time.Hour, nil, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, hot.ID, posts[0].ID)
+	})
+
+	// 有効期限付きの投稿のテスト
+	t.Run("Expiration", func(t *testing.T) {
+		parent := models.NewPost(testUser.ID, "Parent post", nil)
+		require.NoError(t, postRepo.Create(ctx, parent))
+
+		expired := models.NewPost(testUser.ID, "Expired reply", nil)
+		expired.ReplyToID = &parent.ID
+		expiresAt := time.Now().UTC().Add(-time.Minute)
+		expired.ExpiresAt = &expiresAt
+		require.NoError(t, postRepo.Create(ctx, expired))
+		require.NoError(t, postRepo.IncrementReplyCount(ctx, parent.ID))
+
+		expiring := models.NewPost(testUser.ID, "Expiring post", nil)
+		later := time.Now().UTC().Add(time.Hour)
+		expiring.ExpiresAt = &later
+		require.NoError(t, postRepo.Create(ctx, expiring))
+
+		// 期限切れの投稿は取得できない
+		_, err := postRepo.GetByID(ctx, expired.ID)
+		assert.Error(t, err)
+		post, err := postRepo.GetByID(ctx, expiring.ID)
+		require.NoError(t, err)
+		assert.True(t, post.IsExpiring())
+
+		// 期限切れの投稿だけが削除され、返信先の返信数が減る
+		purged, err := postRepo.PurgeExpired(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, expired.ID, purged[0].ID)
+
+		_, err = postRepo.GetByIDIncludingDeleted(ctx, expired.ID)
+		assert.Error(t, err)
+		parent, err = postRepo.GetByID(ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, parent.ReplyCount)
+
+		purged, err = postRepo.PurgeExpired(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, purged)
+	})
+}
+
+func TestPostRepository_GetReposterIDs(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+
+	ctx := context.Background()
+	author := models.NewUser("author", "author@example.com", "hashedpassword", "Author")
+	alice := models.NewUser("alice", "alice@example.com", "hashedpassword", "Alice")
+	bob := models.NewUser("bob", "bob@example.com", "hashedpassword", "Bob")
+	for _, user := range []*models.User{author, alice, bob} {
+		require.NoError(t, userRepo.Create(ctx, user))
+	}
+
+	original := models.NewPost(author.ID, "Original post", nil)
+	require.NoError(t, postRepo.Create(ctx, original))
+
+	// aliceは2回リポストし、bobはその間に1回リポストする
+	base := time.Now().UTC().Add(-time.Hour)
+	reposts := []struct {
+		user *models.User
+		at   time.Time
+	}{
+		{alice, base},
+		{bob, base.Add(time.Minute)},
+		{alice, base.Add(2 * time.Minute)},
+	}
+	for _, r := range reposts {
+		repost := models.NewPost(r.user.ID, "Repost", nil)
+		repost.RepostID = &original.ID
+		repost.CreatedAt = r.at
+		repost.UpdatedAt = r.at
+		require.NoError(t, postRepo.Create(ctx, repost))
+	}
+
+	// 同じユーザーは最新のリポストの日時で1件にまとめる
+	userIDs, repostedAt, err := postRepo.GetReposterIDs(ctx, original.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{alice.ID, bob.ID}, userIDs)
+	assert.WithinDuration(t, base.Add(2*time.Minute), repostedAt[alice.ID], time.Millisecond)
+
+	// ページをまたいで同じユーザーを返さない
+	userIDs, _, err = postRepo.GetReposterIDs(ctx, original.ID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{bob.ID}, userIDs)
+
+	count, err := postRepo.CountReposters(ctx, original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = postRepo.CountReposts(ctx, original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
 }
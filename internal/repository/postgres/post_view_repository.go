@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postViewRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostViewRepository creates a new PostgreSQL implementation of PostViewRepository
+func NewPostViewRepository(db *pgxpool.Pool) interfaces.PostViewRepository {
+	return &postViewRepository{db: db}
+}
+
+func (r *postViewRepository) AddViews(ctx context.Context, day time.Time, counts map[uuid.UUID]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	// 複数のプロセスが同時に書き込む場合のデッドロックを避けるため、ID順に更新する
+	postIDs := make([]uuid.UUID, 0, len(counts))
+	for id := range counts {
+		postIDs = append(postIDs, id)
+	}
+	sort.Slice(postIDs, func(i, j int) bool {
+		return bytes.Compare(postIDs[i][:], postIDs[j][:]) < 0
+	})
+	views := make([]int64, len(postIDs))
+	for i, id := range postIDs {
+		views[i] = counts[id]
+	}
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 削除済みの投稿の閲覧数は破棄する
+	updateQuery := `
+		UPDATE posts p
+		SET view_count = p.view_count + v.views
+		FROM unnest($1::uuid[], $2::bigint[]) AS v(post_id, views)
+		WHERE p.id = v.post_id AND p.deleted_at IS NULL
+	`
+	dailyQuery := `
+		INSERT INTO post_daily_views (post_id, view_date, view_count)
+		SELECT v.post_id, $3::date, v.views
+		FROM unnest($1::uuid[], $2::bigint[]) AS v(post_id, views)
+		JOIN posts p ON p.id = v.post_id AND p.deleted_at IS NULL
+		ON CONFLICT (post_id, view_date)
+		DO UPDATE SET view_count = post_daily_views.view_count + EXCLUDED.view_count
+	`
+	// 2つの文を1回の往復でまとめて送信する
+	batch := &pgx.Batch{}
+	batch.Queue(updateQuery, postIDs, views)
+	batch.Queue(dailyQuery, postIDs, views, formatDate(day))
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postViewRepository) GetDailyViews(ctx context.Context, postID uuid.UUID, from, to time.Time) ([]*models.PostDailyViews, error) {
+	query := `
+		SELECT view_date, view_count
+		FROM post_daily_views
+		WHERE post_id = $1 AND view_date BETWEEN $2::date AND $3::date
+		ORDER BY view_date ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, postID, formatDate(from), formatDate(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dailyViews []*models.PostDailyViews
+	for rows.Next() {
+		views := &models.PostDailyViews{}
+		if err := rows.Scan(&views.Date, &views.ViewCount); err != nil {
+			return nil, err
+		}
+		dailyViews = append(dailyViews, views)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return dailyViews, nil
+}
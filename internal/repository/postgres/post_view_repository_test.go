@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostViewRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	viewRepo := NewPostViewRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーと投稿の作成
+	author := &models.User{
+		ID:        uuid.New(),
+		Username:  "viewauthor",
+		Email:     "viewauthor@example.com",
+		Password:  "hashedpassword",
+		Name:      "View Author",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	err := userRepo.Create(ctx, author)
+	require.NoError(t, err)
+
+	post1 := models.NewPost(author.ID, "First post", nil)
+	post2 := models.NewPost(author.ID, "Second post", nil)
+	require.NoError(t, postRepo.Create(ctx, post1))
+	require.NoError(t, postRepo.Create(ctx, post2))
+
+	today := time.Now().UTC()
+	yesterday := today.AddDate(0, 0, -1)
+
+	// AddViews のテスト
+	t.Run("AddViews", func(t *testing.T) {
+		err := viewRepo.AddViews(ctx, yesterday, map[uuid.UUID]int64{post1.ID: 3})
+		require.NoError(t, err)
+		err = viewRepo.AddViews(ctx, today, map[uuid.UUID]int64{post1.ID: 2, post2.ID: 5})
+		require.NoError(t, err)
+		err = viewRepo.AddViews(ctx, today, map[uuid.UUID]int64{post1.ID: 1})
+		require.NoError(t, err)
+
+		// 投稿の閲覧数に加算されている
+		post, err := postRepo.GetByID(ctx, post1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), post.ViewCount)
+
+		post, err = postRepo.GetByID(ctx, post2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), post.ViewCount)
+
+		// 存在しない投稿の閲覧数は無視される
+		err = viewRepo.AddViews(ctx, today, map[uuid.UUID]int64{uuid.New(): 1})
+		assert.NoError(t, err)
+
+		// 空の集計
+		err = viewRepo.AddViews(ctx, today, nil)
+		assert.NoError(t, err)
+	})
+
+	// GetDailyViews のテスト
+	t.Run("GetDailyViews", func(t *testing.T) {
+		views, err := viewRepo.GetDailyViews(ctx, post1.ID, yesterday, today)
+		require.NoError(t, err)
+		require.Len(t, views, 2)
+		assert.Equal(t, yesterday.Format("2006-01-02"), views[0].Date.Format("2006-01-02"))
+		assert.Equal(t, int64(3), views[0].ViewCount)
+		assert.Equal(t, today.Format("2006-01-02"), views[1].Date.Format("2006-01-02"))
+		assert.Equal(t, int64(3), views[1].ViewCount)
+
+		// 期間外の閲覧数は含まれない
+		views, err = viewRepo.GetDailyViews(ctx, post1.ID, today, today)
+		require.NoError(t, err)
+		require.Len(t, views, 1)
+		assert.Equal(t, int64(3), views[0].ViewCount)
+	})
+}
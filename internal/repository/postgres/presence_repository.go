@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type presenceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPresenceRepository creates a new PostgreSQL implementation of PresenceRepository
+func NewPresenceRepository(db *pgxpool.Pool) interfaces.PresenceRepository {
+	return &presenceRepository{db: db}
+}
+
+// UpdateLastSeen records the last seen time, skipping users deleted in the meantime
+func (r *presenceRepository) UpdateLastSeen(ctx context.Context, userIDs []uuid.UUID, seenAt time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO user_presence (user_id, last_seen_at)
+		SELECT id, $2 FROM users WHERE id = ANY($1)
+		ON CONFLICT (user_id) DO UPDATE
+		SET last_seen_at = GREATEST(user_presence.last_seen_at, EXCLUDED.last_seen_at)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, userIDs, seenAt)
+	return err
+}
+
+// GetLastSeen returns nil when the user has never been seen
+func (r *presenceRepository) GetLastSeen(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	query := "SELECT last_seen_at FROM user_presence WHERE user_id = $1"
+
+	var lastSeenAt time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(&lastSeenAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &lastSeenAt, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	presenceRepo := NewPresenceRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "presenceuser",
+		Email:     "presence@example.com",
+		Password:  "hashedpassword",
+		Name:      "Presence User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	// UpdateLastSeen と GetLastSeen のテスト
+	t.Run("UpdateAndGetLastSeen", func(t *testing.T) {
+		// 記録がない場合はnil
+		lastSeenAt, err := presenceRepo.GetLastSeen(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Nil(t, lastSeenAt)
+
+		seenAt := time.Now().UTC().Truncate(time.Second)
+		require.NoError(t, presenceRepo.UpdateLastSeen(ctx, []uuid.UUID{user.ID}, seenAt))
+
+		lastSeenAt, err = presenceRepo.GetLastSeen(ctx, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, lastSeenAt)
+		assert.True(t, seenAt.Equal(*lastSeenAt))
+
+		// 記録済みの日時より古い場合は変更しない
+		require.NoError(t, presenceRepo.UpdateLastSeen(ctx, []uuid.UUID{user.ID}, seenAt.Add(-time.Hour)))
+		lastSeenAt, err = presenceRepo.GetLastSeen(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, seenAt.Equal(*lastSeenAt))
+	})
+
+	// 存在しないユーザーは無視する
+	t.Run("UnknownUser", func(t *testing.T) {
+		unknownID := uuid.New()
+		require.NoError(t, presenceRepo.UpdateLastSeen(ctx, []uuid.UUID{unknownID}, time.Now().UTC()))
+
+		lastSeenAt, err := presenceRepo.GetLastSeen(ctx, unknownID)
+		require.NoError(t, err)
+		assert.Nil(t, lastSeenAt)
+	})
+}
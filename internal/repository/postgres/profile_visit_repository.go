@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type profileVisitRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewProfileVisitRepository creates a new PostgreSQL implementation of ProfileVisitRepository
+func NewProfileVisitRepository(db *pgxpool.Pool) interfaces.ProfileVisitRepository {
+	return &profileVisitRepository{db: db}
+}
+
+func (r *profileVisitRepository) RecordVisit(ctx context.Context, profileUserID, visitorID uuid.UUID, visitedAt time.Time) (bool, error) {
+	// 両方のユーザーが有効にしている場合のみ記録する
+	query := `
+		INSERT INTO profile_visits (profile_user_id, visitor_id, visited_at)
+		SELECT $1, $2, $3
+		WHERE (
+			SELECT COUNT(*) FROM user_settings
+			WHERE user_id IN ($1, $2) AND profile_visitors_enabled
+		) = 2
+		ON CONFLICT (profile_user_id, visitor_id) DO UPDATE
+		SET visited_at = GREATEST(profile_visits.visited_at, EXCLUDED.visited_at)
+	`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, profileUserID, visitorID, visitedAt)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *profileVisitRepository) GetVisitors(ctx context.Context, profileUserID uuid.UUID, since time.Time, limit int) ([]*models.ProfileVisit, error) {
+	query := `
+		SELECT v.profile_user_id, v.visitor_id, v.visited_at
+		FROM profile_visits v
+		JOIN user_settings s ON s.user_id = v.visitor_id
+		WHERE v.profile_user_id = $1 AND v.visited_at > $2 AND s.profile_visitors_enabled
+		ORDER BY v.visited_at DESC
+		LIMIT $3
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, profileUserID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var visits []*models.ProfileVisit
+	for rows.Next() {
+		var visit models.ProfileVisit
+		if err := rows.Scan(&visit.ProfileUserID, &visit.VisitorID, &visit.VisitedAt); err != nil {
+			return nil, err
+		}
+		visits = append(visits, &visit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return visits, nil
+}
+
+func (r *profileVisitRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM profile_visits WHERE profile_user_id = $1 OR visitor_id = $1", userID)
+	return err
+}
+
+func (r *profileVisitRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM profile_visits WHERE visited_at <= $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *profileVisitRepository) Trim(ctx context.Context, keep int) (int64, error) {
+	query := `
+		DELETE FROM profile_visits v
+		USING (
+			SELECT profile_user_id, visitor_id,
+				ROW_NUMBER() OVER (PARTITION BY profile_user_id ORDER BY visited_at DESC) AS rank
+			FROM profile_visits
+		) ranked
+		WHERE v.profile_user_id = ranked.profile_user_id
+			AND v.visitor_id = ranked.visitor_id
+			AND ranked.rank > $1
+	`
+
+	tag, err := conn(ctx, r.db).Exec(ctx, query, keep)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileVisitRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	settingsRepo := NewSettingsRepository(db.Pool)
+	visitRepo := NewProfileVisitRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	newUser := func(username string) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  "hashedpassword",
+			Name:      username,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+
+	owner := newUser("visitowner")
+	visitor := newUser("visitor")
+	other := newUser("othervisitor")
+
+	// RecordVisit のテスト
+	t.Run("RecordVisit", func(t *testing.T) {
+		// どちらも有効にしていない場合は記録しない
+		recorded, err := visitRepo.RecordVisit(ctx, owner.ID, visitor.ID, time.Now().UTC())
+		require.NoError(t, err)
+		assert.False(t, recorded)
+
+		// 片方だけが有効にしている場合も記録しない
+		_, err = settingsRepo.UpdateProfileVisitorsEnabled(ctx, owner.ID, true)
+		require.NoError(t, err)
+		recorded, err = visitRepo.RecordVisit(ctx, owner.ID, visitor.ID, time.Now().UTC())
+		require.NoError(t, err)
+		assert.False(t, recorded)
+
+		// 両方が有効にしている場合は記録する
+		_, err = settingsRepo.UpdateProfileVisitorsEnabled(ctx, visitor.ID, true)
+		require.NoError(t, err)
+		recorded, err = visitRepo.RecordVisit(ctx, owner.ID, visitor.ID, time.Now().UTC().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.True(t, recorded)
+
+		// 同じ訪問者は1件にまとめ、最終訪問日時を更新する
+		latest := time.Now().UTC()
+		_, err = visitRepo.RecordVisit(ctx, owner.ID, visitor.ID, latest)
+		require.NoError(t, err)
+
+		visits, err := visitRepo.GetVisitors(ctx, owner.ID, time.Now().UTC().Add(-24*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, visits, 1)
+		assert.Equal(t, visitor.ID, visits[0].VisitorID)
+		assert.WithinDuration(t, latest, visits[0].VisitedAt, time.Second)
+	})
+
+	// GetVisitors のテスト
+	t.Run("GetVisitors", func(t *testing.T) {
+		_, err := settingsRepo.UpdateProfileVisitorsEnabled(ctx, other.ID, true)
+		require.NoError(t, err)
+		_, err = visitRepo.RecordVisit(ctx, owner.ID, other.ID, time.Now().UTC().Add(-48*time.Hour))
+		require.NoError(t, err)
+
+		// 新しい順に返す
+		visits, err := visitRepo.GetVisitors(ctx, owner.ID, time.Now().UTC().Add(-72*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, visits, 2)
+		assert.Equal(t, visitor.ID, visits[0].VisitorID)
+		assert.Equal(t, other.ID, visits[1].VisitorID)
+
+		// 指定日時以前の訪問は含めない
+		visits, err = visitRepo.GetVisitors(ctx, owner.ID, time.Now().UTC().Add(-24*time.Hour), 10)
+		require.NoError(t, err)
+		assert.Len(t, visits, 1)
+
+		// 訪問者の表示を無効にしたユーザーは含めない
+		_, err = settingsRepo.UpdateProfileVisitorsEnabled(ctx, other.ID, false)
+		require.NoError(t, err)
+		visits, err = visitRepo.GetVisitors(ctx, owner.ID, time.Now().UTC().Add(-72*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, visits, 1)
+		assert.Equal(t, visitor.ID, visits[0].VisitorID)
+
+		_, err = settingsRepo.UpdateProfileVisitorsEnabled(ctx, other.ID, true)
+		require.NoError(t, err)
+	})
+
+	// Trim と DeleteBefore のテスト
+	t.Run("TrimAndDeleteBefore", func(t *testing.T) {
+		// プロフィールごとに新しい1件だけを残す
+		trimmed, err := visitRepo.Trim(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), trimmed)
+
+		visits, err := visitRepo.GetVisitors(ctx, owner.ID, time.Now().UTC().Add(-72*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, visits, 1)
+		assert.Equal(t, visitor.ID, visits[0].VisitorID)
+
+		deleted, err := visitRepo.DeleteBefore(ctx, time.Now().UTC().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+	})
+
+	// DeleteByUser のテスト
+	t.Run("DeleteByUser", func(t *testing.T) {
+		_, err := visitRepo.RecordVisit(ctx, owner.ID, visitor.ID, time.Now().UTC())
+		require.NoError(t, err)
+		_, err = visitRepo.RecordVisit(ctx, visitor.ID, owner.ID, time.Now().UTC())
+		require.NoError(t, err)
+
+		// 訪問した・訪問された履歴の両方を削除する
+		err = visitRepo.DeleteByUser(ctx, visitor.ID)
+		require.NoError(t, err)
+
+		visits, err := visitRepo.GetVisitors(ctx, owner.ID, time.Now().UTC().Add(-time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, visits)
+		visits, err = visitRepo.GetVisitors(ctx, visitor.ID, time.Now().UTC().Add(-time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, visits)
+	})
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// 実行計画の取得を同時に行う最大数（サンプリングによる負荷を抑えるため）
+const maxConcurrentExplains = 2
+
+// 実行計画の取得にかける最大時間
+const explainTimeout = 5 * time.Second
+
+type queryTracerKey struct{}
+
+type explainQueryKey struct{}
+
+// sampledQuery はサンプリング対象となったクエリの情報を保持します
+type sampledQuery struct {
+	sql     string
+	args    []any
+	startAt time.Time
+}
+
+// QueryTracer はリポジトリのクエリを一定の割合でサンプリングし、実行計画（EXPLAIN）をログに出力します
+// タイムラインや検索クエリの性能劣化を、利用者に影響が出る前に検知するために使用します
+// バインドパラメータの値はログに出力せず、型のみを記録します
+type QueryTracer struct {
+	sampleRate float64
+	analyze    bool
+	log        logger.Logger
+	pool       atomic.Pointer[pgxpool.Pool]
+	slots      chan struct{}
+}
+
+// NewQueryTracer 新しいクエリトレーサーを作成する
+// sampleRateは0〜1の割合、analyzeがtrueの場合はSELECT文をEXPLAIN ANALYZEで実際に実行して計測する（開発環境向け）
+func NewQueryTracer(sampleRate float64, analyze bool, log logger.Logger) *QueryTracer {
+	return &QueryTracer{
+		sampleRate: sampleRate,
+		analyze:    analyze,
+		log:        log,
+		slots:      make(chan struct{}, maxConcurrentExplains),
+	}
+}
+
+// SetPool は実行計画の取得に使用する接続プールを設定します
+// トレーサーはプールの作成前に設定する必要があるため、プール作成後に呼び出します
+func (t *QueryTracer) SetPool(pool *pgxpool.Pool) {
+	t.pool.Store(pool)
+}
+
+// TraceQueryStart はクエリの開始時に呼ばれ、サンプリング対象であればクエリ情報をコンテキストに保存します
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if t.sampleRate <= 0 || ctx.Value(explainQueryKey{}) != nil {
+		return ctx
+	}
+	if !isExplainable(data.SQL) || rand.Float64() >= t.sampleRate {
+		return ctx
+	}
+
+	return context.WithValue(ctx, queryTracerKey{}, &sampledQuery{
+		sql:     data.SQL,
+		args:    data.Args,
+		startAt: time.Now(),
+	})
+}
+
+// TraceQueryEnd はクエリの終了時に呼ばれ、サンプリング対象であれば非同期で実行計画を取得してログに出力します
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(queryTracerKey{}).(*sampledQuery)
+	if !ok || data.Err != nil {
+		return
+	}
+	duration := time.Since(query.startAt)
+
+	pool := t.pool.Load()
+	if pool == nil {
+		return
+	}
+
+	// 実行計画の取得が詰まっている場合はこのサンプルを破棄する
+	select {
+	case t.slots <- struct{}{}:
+	default:
+		return
+	}
+
+	go func() {
+		defer func() { <-t.slots }()
+		t.explain(pool, query, duration)
+	}()
+}
+
+// explain はクエリの実行計画を取得してログに出力します
+func (t *QueryTracer) explain(pool *pgxpool.Pool, query *sampledQuery, duration time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, explainQueryKey{}, true)
+
+	// ANALYZEはクエリを実際に実行するため、副作用のないSELECT文のみに使用する
+	analyze := t.analyze && statementKeyword(query.sql) == "SELECT"
+	prefix := "EXPLAIN (FORMAT TEXT) "
+	if analyze {
+		prefix = "EXPLAIN (ANALYZE, BUFFERS, FORMAT TEXT) "
+	}
+
+	rows, err := pool.Query(ctx, prefix+query.sql, query.args...)
+	if err != nil {
+		t.log.Warn("実行計画の取得に失敗しました", "sql", compactSQL(query.sql), "error", err)
+		return
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			t.log.Warn("実行計画の読み取りに失敗しました", "sql", compactSQL(query.sql), "error", err)
+			return
+		}
+		plan = append(plan, line)
+	}
+	if err := rows.Err(); err != nil {
+		t.log.Warn("実行計画の読み取りに失敗しました", "sql", compactSQL(query.sql), "error", err)
+		return
+	}
+
+	t.log.Info("クエリ実行計画",
+		"sql", compactSQL(query.sql),
+		"params", redactArgs(query.args),
+		"duration_ms", duration.Milliseconds(),
+		"analyze", analyze,
+		"plan", strings.Join(plan, "\n"),
+	)
+}
+
+// isExplainable はEXPLAINの対象となる文かを返します
+func isExplainable(sql string) bool {
+	switch statementKeyword(sql) {
+	case "SELECT", "WITH", "INSERT", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+// statementKeyword はSQL文の最初のキーワードを大文字で返します
+func statementKeyword(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// compactSQL はログ出力用にSQL文の空白をまとめます
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// redactArgs はバインドパラメータの値を伏せ、型のみを返します
+func redactArgs(args []any) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			redacted[i] = fmt.Sprintf("$%d=<nil>", i+1)
+			continue
+		}
+		redacted[i] = fmt.Sprintf("$%d=<%T>", i+1, arg)
+	}
+	return redacted
+}
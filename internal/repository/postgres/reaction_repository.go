@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type reactionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewReactionRepository creates a new PostgreSQL implementation of ReactionRepository
+func NewReactionRepository(db *pgxpool.Pool) interfaces.ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+func (r *reactionRepository) React(ctx context.Context, reaction *models.Reaction) error {
+	query := `
+		INSERT INTO post_reactions (user_id, post_id, emoji, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, reaction.UserID, reaction.PostID, reaction.Emoji, reaction.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("reaction already exists")
+	}
+
+	return nil
+}
+
+func (r *reactionRepository) Unreact(ctx context.Context, userID, postID uuid.UUID, emoji string) error {
+	query := `
+		DELETE FROM post_reactions
+		WHERE user_id = $1 AND post_id = $2 AND emoji = $3
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID, postID, emoji)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("reaction not found")
+	}
+
+	return nil
+}
+
+// GetSummaries counts reactions per emoji for a page of posts in a single query,
+// ordering each post's emoji by count and then by first use
+func (r *reactionRepository) GetSummaries(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]*models.ReactionCount, error) {
+	summaries := make(map[uuid.UUID][]*models.ReactionCount)
+	if len(postIDs) == 0 {
+		return summaries, nil
+	}
+
+	query := `
+		SELECT post_id, emoji, COUNT(*) AS count
+		FROM post_reactions
+		WHERE post_id = ANY($1)
+		GROUP BY post_id, emoji
+		ORDER BY post_id, count DESC, MIN(created_at), emoji
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uuid.UUID
+		count := &models.ReactionCount{}
+		if err := rows.Scan(&postID, &count.Emoji, &count.Count); err != nil {
+			return nil, err
+		}
+		summaries[postID] = append(summaries[postID], count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+func (r *reactionRepository) GetUserReactions(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	reactions := make(map[uuid.UUID][]string)
+	if len(postIDs) == 0 {
+		return reactions, nil
+	}
+
+	query := `
+		SELECT post_id, emoji
+		FROM post_reactions
+		WHERE user_id = $1 AND post_id = ANY($2)
+		ORDER BY post_id, created_at, emoji
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uuid.UUID
+		var emoji string
+		if err := rows.Scan(&postID, &emoji); err != nil {
+			return nil, err
+		}
+		reactions[postID] = append(reactions[postID], emoji)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactionRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	reactionRepo := NewReactionRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	newUser := func(username string) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  "hashedpassword",
+			Name:      username,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	user1 := newUser("reactor1")
+	user2 := newUser("reactor2")
+	user3 := newUser("reactor3")
+
+	// テスト投稿の作成
+	post := models.NewPost(user1.ID, "Test content", nil)
+	otherPost := models.NewPost(user1.ID, "Other content", nil)
+	quietPost := models.NewPost(user1.ID, "Quiet content", nil)
+	require.NoError(t, postRepo.Create(ctx, post))
+	require.NoError(t, postRepo.Create(ctx, otherPost))
+	require.NoError(t, postRepo.Create(ctx, quietPost))
+
+	// React のテスト
+	t.Run("React", func(t *testing.T) {
+		require.NoError(t, reactionRepo.React(ctx, models.NewReaction(user2.ID, post.ID, "👍")))
+		require.NoError(t, reactionRepo.React(ctx, models.NewReaction(user2.ID, post.ID, "🎉")))
+		require.NoError(t, reactionRepo.React(ctx, models.NewReaction(user3.ID, post.ID, "🎉")))
+		require.NoError(t, reactionRepo.React(ctx, models.NewReaction(user3.ID, otherPost.ID, "❤️")))
+
+		// 同じ絵文字で2回リアクションすることはできない
+		err := reactionRepo.React(ctx, models.NewReaction(user2.ID, post.ID, "👍"))
+		assert.EqualError(t, err, "reaction already exists")
+	})
+
+	// GetSummaries のテスト
+	t.Run("GetSummaries", func(t *testing.T) {
+		summaries, err := reactionRepo.GetSummaries(ctx, []uuid.UUID{post.ID, otherPost.ID, quietPost.ID})
+		require.NoError(t, err)
+
+		// リアクション数の多い絵文字から順に並ぶ
+		require.Len(t, summaries[post.ID], 2)
+		assert.Equal(t, "🎉", summaries[post.ID][0].Emoji)
+		assert.Equal(t, int64(2), summaries[post.ID][0].Count)
+		assert.Equal(t, "👍", summaries[post.ID][1].Emoji)
+		assert.Equal(t, int64(1), summaries[post.ID][1].Count)
+
+		require.Len(t, summaries[otherPost.ID], 1)
+		assert.Equal(t, "❤️", summaries[otherPost.ID][0].Emoji)
+
+		// リアクションのない投稿は含まれない
+		_, ok := summaries[quietPost.ID]
+		assert.False(t, ok)
+
+		summaries, err = reactionRepo.GetSummaries(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, summaries)
+	})
+
+	// GetUserReactions のテスト
+	t.Run("GetUserReactions", func(t *testing.T) {
+		reactions, err := reactionRepo.GetUserReactions(ctx, user2.ID, []uuid.UUID{post.ID, otherPost.ID})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"👍", "🎉"}, reactions[post.ID])
+		_, ok := reactions[otherPost.ID]
+		assert.False(t, ok)
+
+		reactions, err = reactionRepo.GetUserReactions(ctx, user1.ID, []uuid.UUID{post.ID, otherPost.ID})
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+	})
+
+	// Unreact のテスト
+	t.Run("Unreact", func(t *testing.T) {
+		require.NoError(t, reactionRepo.Unreact(ctx, user2.ID, post.ID, "🎉"))
+
+		summaries, err := reactionRepo.GetSummaries(ctx, []uuid.UUID{post.ID})
+		require.NoError(t, err)
+		require.Len(t, summaries[post.ID], 2)
+		for _, count := range summaries[post.ID] {
+			assert.Equal(t, int64(1), count.Count)
+		}
+
+		// リアクションしていない絵文字は取り消せない
+		err = reactionRepo.Unreact(ctx, user2.ID, post.ID, "🎉")
+		assert.EqualError(t, err, "reaction not found")
+	})
+}
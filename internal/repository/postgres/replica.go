@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// readReplica is a read-only pool that serves the read queries of a primary pool
+type readReplica struct {
+	pool   *pgxpool.Pool
+	health interfaces.HealthChecker
+}
+
+// readReplicas maps each primary pool to its read replica
+var readReplicas sync.Map
+
+// UseReadReplica routes the read queries of repositories created with primary
+// (timelines, profiles and searches) to replica while health reports it ready.
+// Writes and transactions always run on the primary.
+func UseReadReplica(primary, replica *pgxpool.Pool, health interfaces.HealthChecker) {
+	readReplicas.Store(primary, &readReplica{pool: replica, health: health})
+}
+
+// readConn returns the connection for a read query that tolerates replication lag:
+// the transaction in ctx, the healthy replica of db, or db itself.
+// Queries the replica cannot run because it is down are run on the primary instead
+func readConn(ctx context.Context, db *pgxpool.Pool) dbtx {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	if value, ok := readReplicas.Load(db); ok {
+		replica := value.(*readReplica)
+		if replica.health.Ready() {
+			return replicaPool{replica: replica.pool, primary: retryingPool{pool: db}}
+		}
+	}
+	return retryingPool{pool: db}
+}
+
+// replicaUnavailable reports whether err means the replica could not run the query,
+// so that it can be run on the primary instead
+func replicaUnavailable(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, pgx.ErrNoRows) || ctx.Err() != nil {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return transientErrorCodes[pgErr.Code]
+	}
+
+	var connectErr *pgconn.ConnectError
+	return errors.As(err, &connectErr) || pgconn.SafeToRetry(err)
+}
+
+// replicaPool runs read queries on the replica and falls back to the primary when the replica is unavailable.
+// Begin, Exec and SendBatch are not reads, so they run on the primary
+type replicaPool struct {
+	replica *pgxpool.Pool
+	primary retryingPool
+}
+
+func (p replicaPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.primary.Begin(ctx)
+}
+
+func (p replicaPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return p.primary.Exec(ctx, sql, arguments...)
+}
+
+func (p replicaPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return p.primary.SendBatch(ctx, b)
+}
+
+func (p replicaPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	queryCtx, cancel := queryContext(ctx)
+	rows, err := p.replica.Query(queryCtx, sql, args...)
+	if err != nil {
+		cancel()
+		if replicaUnavailable(ctx, err) {
+			return p.primary.Query(ctx, sql, args...)
+		}
+		return nil, err
+	}
+	return timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (p replicaPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return replicaRow{ctx: ctx, pool: p, sql: sql, args: args}
+}
+
+// replicaRow runs the query on the replica when scanned and falls back to the primary when the replica is unavailable
+type replicaRow struct {
+	ctx  context.Context
+	pool replicaPool
+	sql  string
+	args []any
+}
+
+func (r replicaRow) Scan(dest ...any) error {
+	queryCtx, cancel := queryContext(r.ctx)
+	err := r.pool.replica.QueryRow(queryCtx, r.sql, r.args...).Scan(dest...)
+	cancel()
+	if replicaUnavailable(r.ctx, err) {
+		return r.pool.primary.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	}
+	return err
+}
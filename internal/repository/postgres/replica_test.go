@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubHealth struct {
+	ready bool
+}
+
+func (h *stubHealth) Ready() bool {
+	return h.ready
+}
+
+func TestReplicaUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	assert.False(t, replicaUnavailable(ctx, nil))
+	assert.False(t, replicaUnavailable(ctx, pgx.ErrNoRows))
+	assert.False(t, replicaUnavailable(ctx, errors.New("post not found")))
+
+	// レプリカが停止中・復旧中の場合はプライマリで実行する
+	assert.True(t, replicaUnavailable(ctx, &pgconn.PgError{Code: "57P01"}))
+	assert.True(t, replicaUnavailable(ctx, &pgconn.PgError{Code: "57P03"}))
+	assert.True(t, replicaUnavailable(ctx, &pgconn.ConnectError{}))
+
+	// 構文エラーなどはプライマリでも失敗する
+	assert.False(t, replicaUnavailable(ctx, &pgconn.PgError{Code: "42601"}))
+
+	// キャンセルされたリクエストはプライマリで再実行しない
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, replicaUnavailable(canceled, &pgconn.ConnectError{}))
+}
+
+func TestReadConn(t *testing.T) {
+	ctx := context.Background()
+
+	// 接続は最初のクエリまで確立されない
+	primary, err := pgxpool.New(ctx, "postgres://gox@127.0.0.1:1/gox")
+	require.NoError(t, err)
+	defer primary.Close()
+	replica, err := pgxpool.New(ctx, "postgres://gox@127.0.0.1:1/gox_replica")
+	require.NoError(t, err)
+	defer replica.Close()
+
+	t.Run("WithoutReplica", func(t *testing.T) {
+		assert.Equal(t, retryingPool{pool: primary}, readConn(ctx, primary))
+	})
+
+	health := &stubHealth{ready: true}
+	UseReadReplica(primary, replica, health)
+	defer readReplicas.Delete(primary)
+
+	t.Run("HealthyReplica", func(t *testing.T) {
+		conn, ok := readConn(ctx, primary).(replicaPool)
+		require.True(t, ok)
+		assert.Same(t, replica, conn.replica)
+		assert.Equal(t, retryingPool{pool: primary}, conn.primary)
+	})
+
+	t.Run("UnhealthyReplica", func(t *testing.T) {
+		health.ready = false
+		defer func() { health.ready = true }()
+
+		assert.Equal(t, retryingPool{pool: primary}, readConn(ctx, primary))
+	})
+
+	t.Run("OtherPool", func(t *testing.T) {
+		assert.Equal(t, retryingPool{pool: replica}, readConn(ctx, replica))
+	})
+}
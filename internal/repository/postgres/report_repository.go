@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const reportColumns = `id, reporter_id, target_type, target_id, target_user_id, reason, comment,
+			status, resolved_by, resolution_note, resolved_at, created_at`
+
+type reportRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewReportRepository creates a new PostgreSQL implementation of ReportRepository
+func NewReportRepository(db *pgxpool.Pool) interfaces.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// Create stores an open report. A second open report by the same user for
+// the same target violates the partial unique index and is rejected.
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	query := `
+		INSERT INTO reports (id, reporter_id, target_type, target_id, target_user_id, reason, comment, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		report.ID, report.ReporterID, report.TargetType, report.TargetID, report.TargetUserID,
+		report.Reason, report.Comment, report.Status, report.CreatedAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("report already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns a report by its ID
+func (r *reportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	query := "SELECT " + reportColumns + " FROM reports WHERE id = $1"
+
+	var report models.Report
+	err := scanReport(conn(ctx, r.db).QueryRow(ctx, query, id), &report)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("report not found")
+		}
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// ListByStatus returns the reports with the given status, oldest first
+func (r *reportRepository) ListByStatus(ctx context.Context, status models.ReportStatus, offset, limit int) ([]*models.Report, error) {
+	query := `
+		SELECT ` + reportColumns + `
+		FROM reports
+		WHERE status = $1
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*models.Report{}
+	for rows.Next() {
+		var report models.Report
+		if err := scanReport(rows, &report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, &report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// CountByStatus returns the number of reports with the given status
+func (r *reportRepository) CountByStatus(ctx context.Context, status models.ReportStatus) (int64, error) {
+	query := "SELECT COUNT(*) FROM reports WHERE status = $1"
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, status).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Resolve closes an open report. Reports that are already closed are reported as not found.
+func (r *reportRepository) Resolve(ctx context.Context, id, resolverID uuid.UUID, status models.ReportStatus, note string) error {
+	if status != models.ReportStatusResolved && status != models.ReportStatusDismissed {
+		return errors.New("invalid report status")
+	}
+
+	query := `
+		UPDATE reports
+		SET status = $1, resolved_by = $2, resolution_note = $3, resolved_at = NOW()
+		WHERE id = $4 AND status = 'open'
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, status, resolverID, note, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("report not found")
+	}
+
+	return nil
+}
+
+// scanReport scans a row selected with reportColumns into report
+func scanReport(row pgx.Row, report *models.Report) error {
+	return row.Scan(
+		&report.ID, &report.ReporterID, &report.TargetType, &report.TargetID, &report.TargetUserID,
+		&report.Reason, &report.Comment, &report.Status, &report.ResolvedBy, &report.ResolutionNote,
+		&report.ResolvedAt, &report.CreatedAt,
+	)
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	reportRepo := NewReportRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// 通報者・対象のユーザーと投稿の作成
+	reporter := &models.User{
+		ID:        uuid.New(),
+		Username:  "reporter",
+		Email:     "reporter@example.com",
+		Password:  "hashedpassword",
+		Name:      "Reporter",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, reporter))
+	author := &models.User{
+		ID:        uuid.New(),
+		Username:  "reportedauthor",
+		Email:     "reportedauthor@example.com",
+		Password:  "hashedpassword",
+		Name:      "Reported Author",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, author))
+	post := models.NewPost(author.ID, "Reported content", nil)
+	require.NoError(t, postRepo.Create(ctx, post))
+
+	var postReport *models.Report
+
+	// Create と GetByID のテスト
+	t.Run("CreateAndGet", func(t *testing.T) {
+		postReport = models.NewReport(reporter.ID, models.ReportTargetPost, post.ID, author.ID, models.ReportReasonSpam, "Looks like spam")
+		require.NoError(t, reportRepo.Create(ctx, postReport))
+
+		report, err := reportRepo.GetByID(ctx, postReport.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ReportTargetPost, report.TargetType)
+		assert.Equal(t, post.ID, report.TargetID)
+		assert.Equal(t, author.ID, report.TargetUserID)
+		assert.Equal(t, models.ReportReasonSpam, report.Reason)
+		assert.Equal(t, "Looks like spam", report.Comment)
+		assert.Equal(t, models.ReportStatusOpen, report.Status)
+		assert.Nil(t, report.ResolvedAt)
+
+		// 未対応の通報は重複して作成できない
+		duplicate := models.NewReport(reporter.ID, models.ReportTargetPost, post.ID, author.ID, models.ReportReasonOther, "")
+		err = reportRepo.Create(ctx, duplicate)
+		require.Error(t, err)
+		assert.Equal(t, "report already exists", err.Error())
+
+		// 同じユーザーへの通報は別の対象として扱う
+		userReport := models.NewReport(reporter.ID, models.ReportTargetUser, author.ID, author.ID, models.ReportReasonHarassment, "")
+		require.NoError(t, reportRepo.Create(ctx, userReport))
+
+		_, err = reportRepo.GetByID(ctx, uuid.New())
+		assert.Error(t, err)
+	})
+
+	// ListByStatus と CountByStatus のテスト
+	t.Run("ListByStatus", func(t *testing.T) {
+		reports, err := reportRepo.ListByStatus(ctx, models.ReportStatusOpen, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, reports, 2)
+		assert.Equal(t, postReport.ID, reports[0].ID)
+
+		count, err := reportRepo.CountByStatus(ctx, models.ReportStatusOpen)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		reports, err = reportRepo.ListByStatus(ctx, models.ReportStatusResolved, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+	})
+
+	// Resolve のテスト
+	t.Run("Resolve", func(t *testing.T) {
+		// 未対応には戻せない
+		err := reportRepo.Resolve(ctx, postReport.ID, reporter.ID, models.ReportStatusOpen, "")
+		assert.Error(t, err)
+
+		err = reportRepo.Resolve(ctx, postReport.ID, reporter.ID, models.ReportStatusResolved, "Removed the post")
+		require.NoError(t, err)
+
+		report, err := reportRepo.GetByID(ctx, postReport.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ReportStatusResolved, report.Status)
+		assert.Equal(t, "Removed the post", report.ResolutionNote)
+		require.NotNil(t, report.ResolvedBy)
+		assert.Equal(t, reporter.ID, *report.ResolvedBy)
+		assert.NotNil(t, report.ResolvedAt)
+
+		// 対応済みの通報は再度対応できない
+		err = reportRepo.Resolve(ctx, postReport.ID, reporter.ID, models.ReportStatusDismissed, "")
+		require.Error(t, err)
+		assert.Equal(t, "report not found", err.Error())
+
+		count, err := reportRepo.CountByStatus(ctx, models.ReportStatusResolved)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// 対応済みになった対象は再び通報できる
+		again := models.NewReport(reporter.ID, models.ReportTargetPost, post.ID, author.ID, models.ReportReasonSpam, "")
+		require.NoError(t, reportRepo.Create(ctx, again))
+	})
+
+	// コンテンツフィルターによる自動の通報のテスト
+	t.Run("Automatic", func(t *testing.T) {
+		automatic := models.NewAutomaticReport(models.ReportTargetPost, post.ID, author.ID, models.ReportReasonOther, "Matched content filter")
+		require.NoError(t, reportRepo.Create(ctx, automatic))
+
+		report, err := reportRepo.GetByID(ctx, automatic.ID)
+		require.NoError(t, err)
+		assert.Nil(t, report.ReporterID)
+		assert.True(t, report.IsAutomatic())
+
+		// 同じ対象への未対応の自動通報は1件だけ
+		duplicate := models.NewAutomaticReport(models.ReportTargetPost, post.ID, author.ID, models.ReportReasonOther, "")
+		err = reportRepo.Create(ctx, duplicate)
+		require.Error(t, err)
+		assert.Equal(t, "report already exists", err.Error())
+	})
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RetryPolicy はトランザクション外のクエリが一時的なエラーで失敗した場合の再試行の設定です
+type RetryPolicy struct {
+	// 最初の実行を含む最大試行回数（1で再試行しない）
+	MaxAttempts int
+	// 1回目の再試行までの待ち時間（以降は2倍ずつ増やす）
+	BaseDelay time.Duration
+	// 再試行までの最大の待ち時間
+	MaxDelay time.Duration
+}
+
+var retryPolicy atomic.Pointer[RetryPolicy]
+
+func init() {
+	retryPolicy.Store(&RetryPolicy{MaxAttempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second})
+}
+
+// SetRetryPolicy はリポジトリ全体で使用する再試行の設定を変更します
+func SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	retryPolicy.Store(&policy)
+}
+
+// 再試行すると成功する可能性のあるPostgreSQLのエラーコード
+// いずれもサーバーが文を実行せずに失敗を返したもので、同じ文を再度実行しても二重に反映されない
+var transientErrorCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"57P01": true, // admin_shutdown（フェイルオーバーで旧プライマリが停止した場合など）
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now（昇格中のスタンバイなど）
+	"08000": true, // connection_exception
+	"08003": true, // connection_does_not_exist
+	"08006": true, // connection_failure
+	"08001": true, // sqlclient_unable_to_establish_sqlconnection
+	"08004": true, // sqlserver_rejected_establishment_of_sqlconnection
+}
+
+// isTransient はエラーが再試行すると成功する可能性のある一時的なものかを判定します
+// 接続のリセットなど、サーバーに文が届いたか分からないエラーは、書き込みが二重に反映されないよう
+// 送信前に失敗したことが分かる場合（pgconn.SafeToRetry）のみ一時的なエラーとして扱います
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return transientErrorCodes[pgErr.Code]
+	}
+
+	return pgconn.SafeToRetry(err)
+}
+
+// withRetry は一時的なエラーで失敗したfnを、待ち時間を指数的に増やしながら設定された回数まで再試行します
+func withRetry(ctx context.Context, fn func() error) error {
+	policy := retryPolicy.Load()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= policy.MaxAttempts || !isTransient(err) {
+			return err
+		}
+
+		timer := time.NewTimer(retryDelay(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay は再試行までの待ち時間を返します（同時に失敗したクエリが一斉に再試行しないよう揺らぎを加えます）
+func retryDelay(policy *RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryingPool はトランザクション外のクエリを一時的なエラーで再試行するdbtxです
+// トランザクション内の文はエラーでトランザクションが中断されるため再試行しません
+// 各試行にはSetQueryTimeoutで設定した期限を適用します
+type retryingPool struct {
+	pool *pgxpool.Pool
+}
+
+func (p retryingPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	var tx pgx.Tx
+	err := withRetry(ctx, func() error {
+		var err error
+		tx, err = p.pool.Begin(ctx)
+		return err
+	})
+	return tx, err
+}
+
+func (p retryingPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := withRetry(ctx, func() error {
+		queryCtx, cancel := queryContext(ctx)
+		defer cancel()
+
+		var err error
+		tag, err = p.pool.Exec(queryCtx, sql, arguments...)
+		return err
+	})
+	return tag, err
+}
+
+func (p retryingPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := withRetry(ctx, func() error {
+		queryCtx, cancel := queryContext(ctx)
+
+		var err error
+		rows, err = p.pool.Query(queryCtx, sql, args...)
+		if err != nil {
+			cancel()
+			return err
+		}
+		rows = timeoutRows{Rows: rows, cancel: cancel}
+		return nil
+	})
+	return rows, err
+}
+
+func (p retryingPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return retryingRow{ctx: ctx, pool: p.pool, sql: sql, args: args}
+}
+
+// SendBatch はキューに入れた文を1回の往復でまとめて送信します
+// 結果は読み出す時点で受信するため、一時的なエラーで失敗しても再試行しません
+func (p retryingPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	queryCtx, cancel := queryContext(ctx)
+	return timeoutBatchResults{BatchResults: p.pool.SendBatch(queryCtx, b), cancel: cancel}
+}
+
+// retryingRow はScanの時点でクエリを実行し、一時的なエラーの場合は再試行します
+type retryingRow struct {
+	ctx  context.Context
+	pool *pgxpool.Pool
+	sql  string
+	args []any
+}
+
+func (r retryingRow) Scan(dest ...any) error {
+	return withRetry(r.ctx, func() error {
+		queryCtx, cancel := queryContext(r.ctx)
+		defer cancel()
+
+		return r.pool.QueryRow(queryCtx, r.sql, r.args...).Scan(dest...)
+	})
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransient(t *testing.T) {
+	assert.False(t, isTransient(nil))
+	assert.False(t, isTransient(pgx.ErrNoRows))
+	assert.False(t, isTransient(errors.New("post not found")))
+
+	// 文を実行せずに失敗したサーバーのエラー
+	assert.True(t, isTransient(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, isTransient(&pgconn.PgError{Code: "40P01"}))
+	assert.True(t, isTransient(&pgconn.PgError{Code: "57P01"}))
+	assert.True(t, isTransient(&pgconn.PgError{Code: "57P03"}))
+
+	// 制約違反などは再試行しても成功しない
+	assert.False(t, isTransient(&pgconn.PgError{Code: "23505"}))
+}
+
+func TestWithRetry(t *testing.T) {
+	SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	defer SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second})
+
+	ctx := context.Background()
+	serializationFailure := &pgconn.PgError{Code: "40001"}
+
+	t.Run("RetriesTransientErrors", func(t *testing.T) {
+		attempts := 0
+		err := withRetry(ctx, func() error {
+			attempts++
+			if attempts < 3 {
+				return serializationFailure
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("GivesUpAfterMaxAttempts", func(t *testing.T) {
+		attempts := 0
+		err := withRetry(ctx, func() error {
+			attempts++
+			return serializationFailure
+		})
+		assert.ErrorIs(t, err, serializationFailure)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("DoesNotRetryOtherErrors", func(t *testing.T) {
+		attempts := 0
+		errFailed := errors.New("failed")
+		err := withRetry(ctx, func() error {
+			attempts++
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("StopsWhenContextIsDone", func(t *testing.T) {
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour})
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		attempts := 0
+		err := withRetry(canceled, func() error {
+			attempts++
+			return serializationFailure
+		})
+		assert.ErrorIs(t, err, serializationFailure)
+		assert.Equal(t, 1, attempts)
+	})
+}
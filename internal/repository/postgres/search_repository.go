@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type searchRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSearchRepository creates a new PostgreSQL implementation of SearchRepository
+func NewSearchRepository(db *pgxpool.Pool) interfaces.SearchRepository {
+	return &searchRepository{db: db}
+}
+
+const savedSearchColumns = `id, user_id, query, notify, last_checked_at, created_at`
+
+func (r *searchRepository) RecordSearch(ctx context.Context, userID uuid.UUID, query string, keep int) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO search_history (user_id, query, searched_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, query) DO UPDATE SET searched_at = EXCLUDED.searched_at
+	`, userID, query)
+	if err != nil {
+		return err
+	}
+
+	// 保持件数を超えた古い履歴を削除
+	_, err = tx.Exec(ctx, `
+		DELETE FROM search_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM search_history
+			WHERE user_id = $1
+			ORDER BY searched_at DESC
+			LIMIT $2
+		)
+	`, userID, keep)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *searchRepository) GetSearchHistory(ctx context.Context, userID uuid.UUID, limit int) ([]*models.SearchHistoryEntry, error) {
+	query := `
+		SELECT id, user_id, query, searched_at
+		FROM search_history
+		WHERE user_id = $1
+		ORDER BY searched_at DESC
+		LIMIT $2
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.SearchHistoryEntry
+	for rows.Next() {
+		var entry models.SearchHistoryEntry
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.Query, &entry.SearchedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *searchRepository) DeleteSearchHistoryEntry(ctx context.Context, userID, entryID uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `
+		DELETE FROM search_history
+		WHERE id = $1 AND user_id = $2
+	`, entryID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("search history entry not found")
+	}
+
+	return nil
+}
+
+func (r *searchRepository) ClearSearchHistory(ctx context.Context, userID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM search_history WHERE user_id = $1`, userID)
+	return err
+}
+
+func (r *searchRepository) CreateSavedSearch(ctx context.Context, search *models.SavedSearch) error {
+	query := `
+		INSERT INTO saved_searches (` + savedSearchColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		search.ID,
+		search.UserID,
+		search.Query,
+		search.Notify,
+		search.LastCheckedAt,
+		search.CreatedAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("saved search already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *searchRepository) GetSavedSearches(ctx context.Context, userID uuid.UUID) ([]*models.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.querySavedSearches(ctx, query, userID)
+}
+
+func (r *searchRepository) CountSavedSearches(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT COUNT(*) FROM saved_searches WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *searchRepository) UpdateSavedSearchNotify(ctx context.Context, userID, searchID uuid.UUID, notify bool) (*models.SavedSearch, error) {
+	// 通知を有効にした時点より前の投稿は通知しない
+	query := `
+		UPDATE saved_searches
+		SET notify = $3,
+			last_checked_at = CASE WHEN $3 AND NOT notify THEN NOW() ELSE last_checked_at END
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + savedSearchColumns
+
+	var search models.SavedSearch
+	err := conn(ctx, r.db).QueryRow(ctx, query, searchID, userID, notify).Scan(
+		&search.ID,
+		&search.UserID,
+		&search.Query,
+		&search.Notify,
+		&search.LastCheckedAt,
+		&search.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("saved search not found")
+		}
+		return nil, err
+	}
+
+	return &search, nil
+}
+
+func (r *searchRepository) DeleteSavedSearch(ctx context.Context, userID, searchID uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `
+		DELETE FROM saved_searches
+		WHERE id = $1 AND user_id = $2
+	`, searchID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("saved search not found")
+	}
+
+	return nil
+}
+
+func (r *searchRepository) GetNotifiableSavedSearches(ctx context.Context, limit int) ([]*models.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches
+		WHERE notify
+		ORDER BY last_checked_at ASC
+		LIMIT $1
+	`
+
+	return r.querySavedSearches(ctx, query, limit)
+}
+
+func (r *searchRepository) MarkSavedSearchChecked(ctx context.Context, searchID uuid.UUID, checkedAt time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE saved_searches
+		SET last_checked_at = $2
+		WHERE id = $1
+	`, searchID, checkedAt)
+	return err
+}
+
+func (r *searchRepository) querySavedSearches(ctx context.Context, query string, args ...interface{}) ([]*models.SavedSearch, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var searches []*models.SavedSearch
+	for rows.Next() {
+		var search models.SavedSearch
+		err := rows.Scan(
+			&search.ID,
+			&search.UserID,
+			&search.Query,
+			&search.Notify,
+			&search.LastCheckedAt,
+			&search.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, &search)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
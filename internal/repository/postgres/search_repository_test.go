@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	searchRepo := NewSearchRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "searcher",
+		Email:     "searcher@example.com",
+		Password:  "hashedpassword",
+		Name:      "Searcher",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	err := userRepo.Create(ctx, user)
+	require.NoError(t, err)
+
+	// RecordSearch のテスト
+	t.Run("RecordSearch", func(t *testing.T) {
+		require.NoError(t, searchRepo.RecordSearch(ctx, user.ID, "golang", 2))
+		require.NoError(t, searchRepo.RecordSearch(ctx, user.ID, "postgres", 2))
+
+		// 同じ検索語は1件にまとめて先頭に移動する
+		require.NoError(t, searchRepo.RecordSearch(ctx, user.ID, "golang", 2))
+		history, err := searchRepo.GetSearchHistory(ctx, user.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "golang", history[0].Query)
+		assert.Equal(t, "postgres", history[1].Query)
+
+		// 保持件数を超えた古い履歴は削除される
+		require.NoError(t, searchRepo.RecordSearch(ctx, user.ID, "gin", 2))
+		history, err = searchRepo.GetSearchHistory(ctx, user.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "gin", history[0].Query)
+		assert.Equal(t, "golang", history[1].Query)
+	})
+
+	// DeleteSearchHistoryEntry のテスト
+	t.Run("DeleteSearchHistoryEntry", func(t *testing.T) {
+		history, err := searchRepo.GetSearchHistory(ctx, user.ID, 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, history)
+
+		err = searchRepo.DeleteSearchHistoryEntry(ctx, user.ID, history[0].ID)
+		require.NoError(t, err)
+
+		// 削除済みの履歴の削除を試みる
+		err = searchRepo.DeleteSearchHistoryEntry(ctx, user.ID, history[0].ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "search history entry not found")
+
+		// 他のユーザーの履歴は削除できない
+		err = searchRepo.DeleteSearchHistoryEntry(ctx, uuid.New(), history[1].ID)
+		assert.Error(t, err)
+
+		require.NoError(t, searchRepo.ClearSearchHistory(ctx, user.ID))
+		history, err = searchRepo.GetSearchHistory(ctx, user.ID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	// SavedSearches のテスト
+	t.Run("SavedSearches", func(t *testing.T) {
+		search := models.NewSavedSearch(user.ID, "golang", false)
+		require.NoError(t, searchRepo.CreateSavedSearch(ctx, search))
+
+		// 同じ検索語の重複保存を試みる
+		err := searchRepo.CreateSavedSearch(ctx, models.NewSavedSearch(user.ID, "golang", true))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "saved search already exists")
+
+		count, err := searchRepo.CountSavedSearches(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// 通知が無効な検索は確認対象にならない
+		notifiable, err := searchRepo.GetNotifiableSavedSearches(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, notifiable)
+
+		updated, err := searchRepo.UpdateSavedSearchNotify(ctx, user.ID, search.ID, true)
+		require.NoError(t, err)
+		assert.True(t, updated.Notify)
+
+		notifiable, err = searchRepo.GetNotifiableSavedSearches(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, notifiable, 1)
+		assert.Equal(t, search.ID, notifiable[0].ID)
+
+		checkedAt := time.Now().UTC().Add(time.Minute).Truncate(time.Microsecond)
+		require.NoError(t, searchRepo.MarkSavedSearchChecked(ctx, search.ID, checkedAt))
+		searches, err := searchRepo.GetSavedSearches(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, searches, 1)
+		assert.True(t, checkedAt.Equal(searches[0].LastCheckedAt))
+
+		// 他のユーザーの検索は更新できない
+		_, err = searchRepo.UpdateSavedSearchNotify(ctx, uuid.New(), search.ID, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "saved search not found")
+
+		require.NoError(t, searchRepo.DeleteSavedSearch(ctx, user.ID, search.ID))
+		err = searchRepo.DeleteSavedSearch(ctx, user.ID, search.ID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "saved search not found")
+	})
+
+	// 投稿の検索のテスト
+	t.Run("PostSearch", func(t *testing.T) {
+		older := models.NewPost(user.ID, "Learning Golang today", nil)
+		older.CreatedAt = time.Now().UTC().Add(-time.Hour)
+		require.NoError(t, postRepo.Create(ctx, older))
+		newer := models.NewPost(user.ID, "golang generics are nice", nil)
+		require.NoError(t, postRepo.Create(ctx, newer))
+		other := models.NewPost(user.ID, "Unrelated post", nil)
+		require.NoError(t, postRepo.Create(ctx, other))
+
+		posts, err := postRepo.Search(ctx, "golang", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, posts, 2)
+		assert.Equal(t, newer.ID, posts[0].ID)
+		assert.Equal(t, older.ID, posts[1].ID)
+
+		posts, err = postRepo.SearchSince(ctx, "golang", time.Now().UTC().Add(-time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, newer.ID, posts[0].ID)
+
+		// ワイルドカードは文字どおりに一致する
+		percent := models.NewPost(user.ID, "100% done", nil)
+		require.NoError(t, postRepo.Create(ctx, percent))
+
+		posts, err = postRepo.SearchSince(ctx, "%", time.Now().UTC().Add(-time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, percent.ID, posts[0].ID)
+	})
+}
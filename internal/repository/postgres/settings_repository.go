@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type settingsRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSettingsRepository creates a new PostgreSQL implementation of SettingsRepository
+func NewSettingsRepository(db *pgxpool.Pool) interfaces.SettingsRepository {
+	return &settingsRepository{db: db}
+}
+
+// userSettingsColumns is the column list shared by the user_settings queries
+const userSettingsColumns = `user_id, explore_excluded_keywords, profile_visitors_enabled, notification_grouping,
+	profile_accent_color, pinned_hashtags, presence_visible, likes_visible, home_timeline_order, updated_at`
+
+func (r *settingsRepository) Get(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error) {
+	query := `
+		SELECT ` + userSettingsColumns + `
+		FROM user_settings
+		WHERE user_id = $1
+	`
+
+	settings, err := scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.NewUserSettings(userID), nil
+		}
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (r *settingsRepository) UpdateExploreExcludedKeywords(ctx context.Context, userID uuid.UUID, keywords []string) (*models.UserSettings, error) {
+	if keywords == nil {
+		keywords = []string{}
+	}
+
+	query := `
+		INSERT INTO user_settings (user_id, explore_excluded_keywords, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET explore_excluded_keywords = EXCLUDED.explore_excluded_keywords,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + userSettingsColumns + `
+	`
+
+	return scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID, keywords))
+}
+
+func (r *settingsRepository) UpdateProfileVisitorsEnabled(ctx context.Context, userID uuid.UUID, enabled bool) (*models.UserSettings, error) {
+	query := `
+		INSERT INTO user_settings (user_id, profile_visitors_enabled, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET profile_visitors_enabled = EXCLUDED.profile_visitors_enabled,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + userSettingsColumns + `
+	`
+
+	return scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID, enabled))
+}
+
+func (r *settingsRepository) UpdateNotificationGrouping(ctx context.Context, userID uuid.UUID, clientType string, grouping models.NotificationGrouping) (*models.UserSettings, error) {
+	query := `
+		INSERT INTO user_settings (user_id, notification_grouping, updated_at)
+		VALUES ($1, jsonb_build_object($2::text, $3::text), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET notification_grouping = user_settings.notification_grouping || EXCLUDED.notification_grouping,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + userSettingsColumns + `
+	`
+
+	return scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID, clientType, string(grouping)))
+}
+
+func (r *settingsRepository) UpdateProfileTheme(ctx context.Context, userID uuid.UUID, theme models.ProfileTheme) (*models.UserSettings, error) {
+	hashtags := theme.PinnedHashtags
+	if hashtags == nil {
+		hashtags = []string{}
+	}
+
+	query := `
+		INSERT INTO user_settings (user_id, profile_accent_color, pinned_hashtags, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET profile_accent_color = EXCLUDED.profile_accent_color,
+			pinned_hashtags = EXCLUDED.pinned_hashtags,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + userSettingsColumns + `
+	`
+
+	return scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID, theme.AccentColor, hashtags))
+}
+
+func (r *settingsRepository) UpdatePresenceVisible(ctx context.Context, userID uuid.UUID, visible bool) (*models.UserSettings, error) {
+	query := `
+		INSERT INTO user_settings (user_id, presence_visible, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET presence_visible = EXCLUDED.presence_visible,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + userSettingsColumns + `
+	`
+
+	return scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID, visible))
+}
+
+func (r *settingsRepository) UpdateLikesVisible(ctx context.Context, userID uuid.UUID, visible bool) (*models.UserSettings, error) {
+	query := `
+		INSERT INTO user_settings (user_id, likes_visible, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET likes_visible = EXCLUDED.likes_visible,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + userSettingsColumns + `
+	`
+
+	return scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID, visible))
+}
+
+func (r *settingsRepository) UpdateHomeTimelineOrder(ctx context.Context, userID uuid.UUID, order models.TimelineOrder) (*models.UserSettings, error) {
+	query := `
+		INSERT INTO user_settings (user_id, home_timeline_order, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET home_timeline_order = EXCLUDED.home_timeline_order,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + userSettingsColumns + `
+	`
+
+	return scanUserSettings(conn(ctx, r.db).QueryRow(ctx, query, userID, string(order)))
+}
+
+// scanUserSettings scans a row selected with userSettingsColumns
+func scanUserSettings(row pgx.Row) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	err := row.Scan(
+		&settings.UserID,
+		&settings.ExploreExcludedKeywords,
+		&settings.ProfileVisitorsEnabled,
+		&settings.NotificationGrouping,
+		&settings.ProfileAccentColor,
+		&settings.PinnedHashtags,
+		&settings.PresenceVisible,
+		&settings.LikesVisible,
+		&settings.HomeTimelineOrder,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.ExploreExcludedKeywords == nil {
+		settings.ExploreExcludedKeywords = []string{}
+	}
+	if settings.NotificationGrouping == nil {
+		settings.NotificationGrouping = map[string]models.NotificationGrouping{}
+	}
+	if settings.PinnedHashtags == nil {
+		settings.PinnedHashtags = []string{}
+	}
+
+	return &settings, nil
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	settingsRepo := NewSettingsRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "settingsuser",
+		Email:     "settings@example.com",
+		Password:  "hashedpassword",
+		Name:      "Settings User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	err := userRepo.Create(ctx, user)
+	require.NoError(t, err)
+
+	// Get のテスト
+	t.Run("GetDefaults", func(t *testing.T) {
+		// 保存されていない場合はデフォルト値を返す
+		settings, err := settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, settings.UserID)
+		assert.Empty(t, settings.ExploreExcludedKeywords)
+		assert.True(t, settings.PresenceVisible)
+		assert.True(t, settings.LikesVisible)
+		assert.Equal(t, models.TimelineOrderLatest, settings.HomeTimelineOrder)
+	})
+
+	// UpdateExploreExcludedKeywords のテスト
+	t.Run("UpdateExploreExcludedKeywords", func(t *testing.T) {
+		settings, err := settingsRepo.UpdateExploreExcludedKeywords(ctx, user.ID, []string{"spoiler", "#election"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"spoiler", "#election"}, settings.ExploreExcludedKeywords)
+
+		settings, err = settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"spoiler", "#election"}, settings.ExploreExcludedKeywords)
+
+		// 空にすると除外キーワードがなくなる
+		settings, err = settingsRepo.UpdateExploreExcludedKeywords(ctx, user.ID, nil)
+		require.NoError(t, err)
+		assert.Empty(t, settings.ExploreExcludedKeywords)
+	})
+
+	// UpdateNotificationGrouping のテスト
+	t.Run("UpdateNotificationGrouping", func(t *testing.T) {
+		settings, err := settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.NotificationGroupingFlat, settings.NotificationGroupingFor("ios"))
+
+		settings, err = settingsRepo.UpdateNotificationGrouping(ctx, user.ID, "ios", models.NotificationGroupingGrouped)
+		require.NoError(t, err)
+		assert.Equal(t, models.NotificationGroupingGrouped, settings.NotificationGroupingFor("ios"))
+
+		// 他のクライアントの設定は変更しない
+		settings, err = settingsRepo.UpdateNotificationGrouping(ctx, user.ID, "web", models.NotificationGroupingFlat)
+		require.NoError(t, err)
+		assert.Equal(t, models.NotificationGroupingGrouped, settings.NotificationGroupingFor("ios"))
+		assert.Equal(t, models.NotificationGroupingFlat, settings.NotificationGroupingFor("web"))
+
+		// 他の設定も保持される
+		settings, err = settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Len(t, settings.NotificationGrouping, 2)
+		assert.Empty(t, settings.ExploreExcludedKeywords)
+	})
+
+	// UpdateProfileTheme のテスト
+	t.Run("UpdateProfileTheme", func(t *testing.T) {
+		settings, err := settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "", settings.ProfileTheme().AccentColor)
+		assert.Empty(t, settings.ProfileTheme().PinnedHashtags)
+
+		theme := models.ProfileTheme{AccentColor: "#1da1f2", PinnedHashtags: []string{"golang", "猫"}}
+		settings, err = settingsRepo.UpdateProfileTheme(ctx, user.ID, theme)
+		require.NoError(t, err)
+		assert.Equal(t, theme, settings.ProfileTheme())
+
+		// 他の設定は保持される
+		assert.Equal(t, models.NotificationGroupingGrouped, settings.NotificationGroupingFor("ios"))
+
+		// 空にするとデフォルトに戻る
+		settings, err = settingsRepo.UpdateProfileTheme(ctx, user.ID, models.ProfileTheme{})
+		require.NoError(t, err)
+		assert.Equal(t, "", settings.ProfileAccentColor)
+		assert.Empty(t, settings.PinnedHashtags)
+	})
+
+	// UpdatePresenceVisible のテスト
+	t.Run("UpdatePresenceVisible", func(t *testing.T) {
+		settings, err := settingsRepo.UpdatePresenceVisible(ctx, user.ID, false)
+		require.NoError(t, err)
+		assert.False(t, settings.PresenceVisible)
+
+		// 本人には非表示にしても表示する
+		assert.True(t, settings.PresenceVisibleTo(user.ID))
+		assert.False(t, settings.PresenceVisibleTo(uuid.New()))
+
+		settings, err = settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, settings.PresenceVisible)
+
+		settings, err = settingsRepo.UpdatePresenceVisible(ctx, user.ID, true)
+		require.NoError(t, err)
+		assert.True(t, settings.PresenceVisible)
+	})
+
+	// UpdateLikesVisible のテスト
+	t.Run("UpdateLikesVisible", func(t *testing.T) {
+		settings, err := settingsRepo.UpdateLikesVisible(ctx, user.ID, false)
+		require.NoError(t, err)
+		assert.False(t, settings.LikesVisible)
+
+		// 本人には非公開にしても表示する
+		assert.True(t, settings.LikesVisibleTo(user.ID))
+		assert.False(t, settings.LikesVisibleTo(uuid.New()))
+
+		// 他の設定は保持される
+		assert.True(t, settings.PresenceVisible)
+
+		settings, err = settingsRepo.UpdateLikesVisible(ctx, user.ID, true)
+		require.NoError(t, err)
+		assert.True(t, settings.LikesVisible)
+	})
+
+	// UpdateHomeTimelineOrder のテスト
+	t.Run("UpdateHomeTimelineOrder", func(t *testing.T) {
+		settings, err := settingsRepo.UpdateHomeTimelineOrder(ctx, user.ID, models.TimelineOrderRanked)
+		require.NoError(t, err)
+		assert.Equal(t, models.TimelineOrderRanked, settings.HomeTimelineOrder)
+
+		settings, err = settingsRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.TimelineOrderRanked, settings.HomeTimelineOrder)
+
+		// 未知の並び順は保存できない
+		_, err = settingsRepo.UpdateHomeTimelineOrder(ctx, user.ID, models.TimelineOrder("random"))
+		assert.Error(t, err)
+
+		settings, err = settingsRepo.UpdateHomeTimelineOrder(ctx, user.ID, models.TimelineOrderLatest)
+		require.NoError(t, err)
+		assert.Equal(t, models.TimelineOrderLatest, settings.HomeTimelineOrder)
+	})
+
+	// 除外キーワードを指定した投稿一覧のテスト
+	t.Run("PostListExcluding", func(t *testing.T) {
+		spoiler := models.NewPost(user.ID, "Big SPOILER for the finale", nil)
+		require.NoError(t, postRepo.Create(ctx, spoiler))
+		hashtag := models.NewPost(user.ID, "Vote today #Election", nil)
+		require.NoError(t, postRepo.Create(ctx, hashtag))
+		wildcard := models.NewPost(user.ID, "100 percent sure", nil)
+		require.NoError(t, postRepo.Create(ctx, wildcard))
+		plain := models.NewPost(user.ID, "Nice weather", nil)
+		require.NoError(t, postRepo.Create(ctx, plain))
+
+		// 大文字小文字を区別せずに除外する
+		posts, err := postRepo.ListExcluding(ctx, uuid.Nil, []string{"spoiler", "#election"}, 0, 10)
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, 0, len(posts))
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+		assert.ElementsMatch(t, []uuid.UUID{wildcard.ID, plain.ID}, ids)
+
+		// % はワイルドカードとして扱わない
+		posts, err = postRepo.ListExcluding(ctx, uuid.Nil, []string{"%"}, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, posts, 4)
+
+		// 除外キーワードがない場合はすべて返す
+		posts, err = postRepo.ListExcluding(ctx, uuid.Nil, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, posts, 4)
+	})
+}
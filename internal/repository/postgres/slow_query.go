@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+type slowQueryKey struct{}
+
+type slowBatchKey struct{}
+
+// slowBatch はバッチの開始時刻と送信した文を保持します
+type slowBatch struct {
+	sqls    []string
+	startAt time.Time
+}
+
+// SlowQueryLogger は実行時間がしきい値を超えたクエリを、実行時間と正規化したSQL文とともにログに出力します
+// SQL文中のリテラルは伏せ、バインドパラメータの値は出力しません
+type SlowQueryLogger struct {
+	threshold time.Duration
+	log       logger.Logger
+}
+
+// NewSlowQueryLogger 新しい低速クエリのロガーを作成する
+func NewSlowQueryLogger(threshold time.Duration, log logger.Logger) *SlowQueryLogger {
+	return &SlowQueryLogger{
+		threshold: threshold,
+		log:       log,
+	}
+}
+
+// TraceQueryStart はクエリの開始時刻をコンテキストに保存します
+func (t *SlowQueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, slowQueryKey{}, &sampledQuery{
+		sql:     data.SQL,
+		startAt: time.Now(),
+	})
+}
+
+// TraceQueryEnd はクエリの実行時間がしきい値を超えた場合にログに出力します
+func (t *SlowQueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(slowQueryKey{}).(*sampledQuery)
+	if !ok {
+		return
+	}
+	duration := time.Since(query.startAt)
+	if duration < t.threshold {
+		return
+	}
+
+	keysAndValues := []any{
+		"sql", normalizeSQL(query.sql),
+		"duration_ms", duration.Milliseconds(),
+		"threshold_ms", t.threshold.Milliseconds(),
+		"rows", data.CommandTag.RowsAffected(),
+	}
+	if data.Err != nil {
+		keysAndValues = append(keysAndValues, "error", data.Err)
+	}
+	t.log.Warn("低速なクエリを検出しました", keysAndValues...)
+}
+
+// TraceBatchStart はバッチ（pgx.Batch）の開始時刻をコンテキストに保存します
+func (t *SlowQueryLogger) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return context.WithValue(ctx, slowBatchKey{}, &slowBatch{startAt: time.Now()})
+}
+
+// TraceBatchQuery はバッチの文を記録します
+func (t *SlowQueryLogger) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	if batch, ok := ctx.Value(slowBatchKey{}).(*slowBatch); ok {
+		batch.sqls = append(batch.sqls, data.SQL)
+	}
+}
+
+// TraceBatchEnd はバッチ全体の実行時間がしきい値を超えた場合に、バッチの文とともにログに出力します
+// バッチの文は1回の往復でまとめて実行されるため、文ごとの実行時間は計測しません
+func (t *SlowQueryLogger) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	batch, ok := ctx.Value(slowBatchKey{}).(*slowBatch)
+	if !ok {
+		return
+	}
+	duration := time.Since(batch.startAt)
+	if duration < t.threshold {
+		return
+	}
+
+	sqls := make([]string, len(batch.sqls))
+	for i, sql := range batch.sqls {
+		sqls[i] = normalizeSQL(sql)
+	}
+	keysAndValues := []any{
+		"sql", strings.Join(sqls, "; "),
+		"statements", len(sqls),
+		"duration_ms", duration.Milliseconds(),
+		"threshold_ms", t.threshold.Milliseconds(),
+	}
+	if data.Err != nil {
+		keysAndValues = append(keysAndValues, "error", data.Err)
+	}
+	t.log.Warn("低速なバッチを検出しました", keysAndValues...)
+}
+
+// normalizeSQL はログ出力用にSQL文の空白をまとめ、文字列と数値のリテラルを?に置き換えます
+// 同じ形のクエリが同じ文字列になるため、ログを集計しやすくなります
+func normalizeSQL(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	space := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = b.Len() > 0
+			continue
+		case space:
+			b.WriteByte(' ')
+			space = false
+		}
+
+		switch {
+		case c == '\'':
+			// 文字列リテラル（''は引用符のエスケープ）
+			for i++; i < len(sql); i++ {
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteByte('?')
+		case c == '"':
+			// 引用符で囲まれた識別子はそのまま出力する
+			start := i
+			for i++; i < len(sql) && sql[i] != '"'; i++ {
+			}
+			b.WriteString(sql[start:min(i+1, len(sql))])
+		case isDigit(c) && (i == 0 || !isIdentByte(sql[i-1])):
+			// 数値リテラル（$1などのプレースホルダーや識別子の一部は除く）
+			for i+1 < len(sql) && (isDigit(sql[i+1]) || sql[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
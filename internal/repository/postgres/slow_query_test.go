@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	assert.Equal(t,
+		"SELECT p.id FROM posts p WHERE p.user_id = $1 AND p.visibility = ? ORDER BY p.created_at DESC LIMIT ?",
+		normalizeSQL(`
+			SELECT p.id
+			FROM posts p
+			WHERE p.user_id = $1 AND p.visibility = 'public'
+			ORDER BY p.created_at DESC
+			LIMIT 20
+		`))
+
+	// エスケープされた引用符を含む文字列リテラル
+	assert.Equal(t, "SELECT ? || name FROM users", normalizeSQL("SELECT 'it''s ' || name FROM users"))
+
+	// 数値を含む識別子や引用符で囲まれた識別子は置き換えない
+	assert.Equal(t, `SELECT "column 1", md5(x), ? FROM t2 WHERE score > ?`, normalizeSQL(`SELECT "column 1", md5(x), 1.5 FROM t2 WHERE score > 10`))
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type storageUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStorageUsageRepository creates a new PostgreSQL implementation of StorageUsageRepository
+func NewStorageUsageRepository(db *pgxpool.Pool) interfaces.StorageUsageRepository {
+	return &storageUsageRepository{db: db}
+}
+
+// Get returns the user's usage, which is zero before the first upload
+func (r *storageUsageRepository) Get(ctx context.Context, userID uuid.UUID) (*models.StorageUsage, error) {
+	query := `
+		SELECT user_id, used_bytes, file_count, updated_at
+		FROM user_storage_usage
+		WHERE user_id = $1
+	`
+
+	usage := &models.StorageUsage{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(
+		&usage.UserID, &usage.UsedBytes, &usage.FileCount, &usage.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.StorageUsage{UserID: userID}, nil
+		}
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// Reserve adds a file to the usage in a single statement, so concurrent uploads cannot exceed the quota together
+func (r *storageUsageRepository) Reserve(ctx context.Context, userID uuid.UUID, size, quotaBytes int64) (bool, error) {
+	query := `
+		INSERT INTO user_storage_usage (user_id, used_bytes, file_count, updated_at)
+		SELECT $1, $2, 1, NOW()
+		WHERE $3 <= 0 OR $2 <= $3
+		ON CONFLICT (user_id) DO UPDATE SET
+			used_bytes = user_storage_usage.used_bytes + EXCLUDED.used_bytes,
+			file_count = user_storage_usage.file_count + 1,
+			updated_at = NOW()
+		WHERE $3 <= 0 OR user_storage_usage.used_bytes + EXCLUDED.used_bytes <= $3
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID, size, quotaBytes)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// Refund removes a file from the usage, never going below zero
+func (r *storageUsageRepository) Refund(ctx context.Context, userID uuid.UUID, size int64) error {
+	query := `
+		UPDATE user_storage_usage
+		SET used_bytes = GREATEST(used_bytes - $2, 0),
+			file_count = GREATEST(file_count - 1, 0),
+			updated_at = NOW()
+		WHERE user_id = $1
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, userID, size)
+	return err
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageUsageRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	usageRepo := NewStorageUsageRepository(db.Pool)
+
+	ctx := context.Background()
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "storageuser",
+		Email:     "storageuser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Storage User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	// アップロード前は0
+	t.Run("GetEmpty", func(t *testing.T) {
+		usage, err := usageRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, usage.UserID)
+		assert.Equal(t, int64(0), usage.UsedBytes)
+		assert.Equal(t, 0, usage.FileCount)
+	})
+
+	// Reserve のテスト
+	t.Run("Reserve", func(t *testing.T) {
+		reserved, err := usageRepo.Reserve(ctx, user.ID, 600, 1000)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		// 上限を超える場合は加えない
+		reserved, err = usageRepo.Reserve(ctx, user.ID, 500, 1000)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+
+		reserved, err = usageRepo.Reserve(ctx, user.ID, 400, 1000)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		// 上限がない場合は常に加える
+		reserved, err = usageRepo.Reserve(ctx, user.ID, 5000, 0)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		usage, err := usageRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6000), usage.UsedBytes)
+		assert.Equal(t, 3, usage.FileCount)
+	})
+
+	// Refund のテスト
+	t.Run("Refund", func(t *testing.T) {
+		require.NoError(t, usageRepo.Refund(ctx, user.ID, 5000))
+
+		usage, err := usageRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), usage.UsedBytes)
+		assert.Equal(t, 2, usage.FileCount)
+
+		// 0を下回らない
+		require.NoError(t, usageRepo.Refund(ctx, user.ID, 5000))
+		require.NoError(t, usageRepo.Refund(ctx, user.ID, 5000))
+		require.NoError(t, usageRepo.Refund(ctx, user.ID, 5000))
+
+		usage, err = usageRepo.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), usage.UsedBytes)
+		assert.Equal(t, 0, usage.FileCount)
+	})
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type supporterRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSupporterRepository creates a new PostgreSQL implementation of SupporterRepository
+func NewSupporterRepository(db *pgxpool.Pool) interfaces.SupporterRepository {
+	return &supporterRepository{db: db}
+}
+
+func (r *supporterRepository) ApplyEvent(ctx context.Context, event *models.SupporterEvent) (bool, error) {
+	if !event.Type.IsValid() {
+		return false, errors.New("invalid supporter event type")
+	}
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 処理済みのイベントは無視する
+	insertQuery := `
+		INSERT INTO supporter_events (event_id, event_type, user_id, received_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (event_id) DO NOTHING
+	`
+	result, err := tx.Exec(ctx, insertQuery, event.ID, event.Type, event.UserID)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return false, errors.New("user not found")
+		}
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	// イベントの到着順が前後しても期限が巻き戻らないようにする
+	var query string
+	args := []any{event.UserID}
+	switch event.Type {
+	case models.SupporterEventActivated, models.SupporterEventRenewed:
+		if event.ExpiresAt == nil {
+			return false, errors.New("expires_at is required")
+		}
+		query = `
+			UPDATE users
+			SET supporter_tier = $2,
+				supporter_until = GREATEST(COALESCE(supporter_until, $3), $3),
+				updated_at = NOW()
+			WHERE id = $1
+		`
+		args = append(args, event.Tier, *event.ExpiresAt)
+	case models.SupporterEventCancelled:
+		// 解約後も支払い済みの期間は特典を維持する
+		until := time.Now()
+		if event.ExpiresAt != nil {
+			until = *event.ExpiresAt
+		}
+		query = `
+			UPDATE users
+			SET supporter_until = LEAST(COALESCE(supporter_until, $2), $2), updated_at = NOW()
+			WHERE id = $1
+		`
+		args = append(args, until)
+	case models.SupporterEventRefunded:
+		query = `
+			UPDATE users
+			SET supporter_until = NOW(), updated_at = NOW()
+			WHERE id = $1
+		`
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupporterRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	supporterRepo := NewSupporterRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "supporter",
+		Email:     "supporter@example.com",
+		Password:  "hashedpassword",
+		Name:      "Supporter",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	err := userRepo.Create(ctx, user)
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	monthLater := now.AddDate(0, 1, 0)
+
+	// ApplyEvent（開始）のテスト
+	t.Run("Activate", func(t *testing.T) {
+		applied, err := supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:        "evt_activate",
+			Type:      models.SupporterEventActivated,
+			UserID:    user.ID,
+			Tier:      "gold",
+			ExpiresAt: &monthLater,
+		})
+		require.NoError(t, err)
+		assert.True(t, applied)
+
+		saved, err := userRepo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "gold", saved.SupporterTier)
+		require.NotNil(t, saved.SupporterUntil)
+		assert.True(t, saved.SupporterUntil.Equal(monthLater))
+		assert.True(t, saved.IsSupporter())
+
+		// 同じイベントは二重に処理しない
+		applied, err = supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:        "evt_activate",
+			Type:      models.SupporterEventActivated,
+			UserID:    user.ID,
+			Tier:      "gold",
+			ExpiresAt: &monthLater,
+		})
+		require.NoError(t, err)
+		assert.False(t, applied)
+	})
+
+	// 到着順が前後しても期限が巻き戻らない
+	t.Run("RenewOutOfOrder", func(t *testing.T) {
+		earlier := now.AddDate(0, 0, 7)
+		applied, err := supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:        "evt_renew_old",
+			Type:      models.SupporterEventRenewed,
+			UserID:    user.ID,
+			Tier:      "gold",
+			ExpiresAt: &earlier,
+		})
+		require.NoError(t, err)
+		assert.True(t, applied)
+
+		saved, err := userRepo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, saved.SupporterUntil.Equal(monthLater))
+	})
+
+	// 解約後は指定された期限まで特典を維持する
+	t.Run("Cancel", func(t *testing.T) {
+		weekLater := now.AddDate(0, 0, 7)
+		applied, err := supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:        "evt_cancel",
+			Type:      models.SupporterEventCancelled,
+			UserID:    user.ID,
+			ExpiresAt: &weekLater,
+		})
+		require.NoError(t, err)
+		assert.True(t, applied)
+
+		saved, err := userRepo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, saved.SupporterUntil.Equal(weekLater))
+		assert.True(t, saved.IsSupporter())
+	})
+
+	// 返金された場合はすぐに特典が終了する
+	t.Run("Refund", func(t *testing.T) {
+		applied, err := supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:     "evt_refund",
+			Type:   models.SupporterEventRefunded,
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+		assert.True(t, applied)
+
+		saved, err := userRepo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, saved.IsSupporter())
+	})
+
+	// エラーケース
+	t.Run("Errors", func(t *testing.T) {
+		// 存在しないユーザー
+		_, err := supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:        "evt_unknown_user",
+			Type:      models.SupporterEventActivated,
+			UserID:    uuid.New(),
+			ExpiresAt: &monthLater,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "user not found")
+
+		// 不正なイベント種別
+		_, err = supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:     "evt_invalid",
+			Type:   "supporter.unknown",
+			UserID: user.ID,
+		})
+		assert.Error(t, err)
+
+		// 期限のない開始イベント
+		_, err = supporterRepo.ApplyEvent(ctx, &models.SupporterEvent{
+			ID:     "evt_no_expiry",
+			Type:   models.SupporterEventActivated,
+			UserID: user.ID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expires_at is required")
+	})
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const syncEventColumns = `seq, event_type, user_id, target_user_id, subject_id, occurred_at`
+
+type syncEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSyncEventRepository creates a new PostgreSQL implementation of SyncEventRepository
+func NewSyncEventRepository(db *pgxpool.Pool) interfaces.SyncEventRepository {
+	return &syncEventRepository{db: db}
+}
+
+// SeqRange returns the oldest retained sequence number and the newest one that occurred before before
+func (r *syncEventRepository) SeqRange(ctx context.Context, before time.Time) (int64, int64, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT MIN(seq) FROM sync_events), 0),
+			COALESCE((SELECT MAX(seq) FROM sync_events WHERE occurred_at <= $1), 0)
+	`
+
+	var first, last int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, before).Scan(&first, &last); err != nil {
+		return 0, 0, err
+	}
+
+	return first, last, nil
+}
+
+// ListForUser returns the events in (after, upTo] relevant to the user, oldest first
+func (r *syncEventRepository) ListForUser(ctx context.Context, userID uuid.UUID, after, upTo int64, limit int) ([]*models.SyncEvent, error) {
+	query := `
+		SELECT ` + syncEventColumns + `
+		FROM sync_events
+		WHERE seq > $2 AND seq <= $3 AND (
+			(event_type IN ('post_created', 'post_deleted') AND (
+				user_id = $1 OR user_id IN (SELECT followee_id FROM follows WHERE follower_id = $1)
+			))
+			OR (event_type IN ('followed', 'unfollowed') AND (user_id = $1 OR target_user_id = $1))
+			OR (event_type LIKE 'notification%' AND user_id = $1)
+		)
+		ORDER BY seq
+		LIMIT $4
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, after, upTo, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*models.SyncEvent, 0)
+	for rows.Next() {
+		event := &models.SyncEvent{}
+		err := rows.Scan(
+			&event.Seq, &event.Type, &event.UserID, &event.TargetUserID, &event.SubjectID, &event.OccurredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// DeleteBefore deletes up to limit events that occurred before before. The newest
+// event is kept so that SeqRange can still tell that older cursors are stale.
+func (r *syncEventRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM sync_events
+		WHERE seq IN (
+			SELECT seq FROM sync_events
+			WHERE occurred_at < $1 AND seq < (SELECT MAX(seq) FROM sync_events)
+			ORDER BY seq
+			LIMIT $2
+		)
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// queueSyncEvent queues recording a change for client delta sync in batch, which is sent in a transaction
+func queueSyncEvent(batch *pgx.Batch, eventType models.SyncEventType, userID uuid.UUID, targetUserID, subjectID *uuid.UUID) {
+	query := `
+		INSERT INTO sync_events (event_type, user_id, target_user_id, subject_id)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	batch.Queue(query, eventType, userID, targetUserID, subjectID)
+}
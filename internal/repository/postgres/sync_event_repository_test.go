@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncEventRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	followRepo := NewFollowRepository(db.Pool)
+	notificationRepo := NewNotificationRepository(db.Pool)
+	syncRepo := NewSyncEventRepository(db.Pool)
+
+	ctx := context.Background()
+
+	newUser := func(username string) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  "hashedpassword",
+			Name:      username,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+
+	syncer := newUser("syncer")
+	followee := newUser("syncfollowee")
+	stranger := newUser("syncstranger")
+
+	// 空の場合は0を返す
+	t.Run("SeqRangeEmpty", func(t *testing.T) {
+		first, last, err := syncRepo.SeqRange(ctx, time.Now().UTC())
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), first)
+		assert.Equal(t, int64(0), last)
+	})
+
+	// 投稿・フォロー・通知の変更がリポジトリから記録される
+	require.NoError(t, followRepo.Follow(ctx, syncer.ID, followee.ID))
+	followeePost := models.NewPost(followee.ID, "Followee post", nil)
+	require.NoError(t, postRepo.Create(ctx, followeePost))
+	strangerPost := models.NewPost(stranger.ID, "Stranger post", nil)
+	require.NoError(t, postRepo.Create(ctx, strangerPost))
+	ownPost := models.NewPost(syncer.ID, "Own post", nil)
+	require.NoError(t, postRepo.Create(ctx, ownPost))
+	notification := models.NewNotification(syncer.ID, followee.ID, models.NotificationTypeLike, &ownPost.ID)
+	require.NoError(t, notificationRepo.Create(ctx, notification))
+	require.NoError(t, notificationRepo.MarkAsRead(ctx, notification.ID))
+	require.NoError(t, postRepo.Delete(ctx, followeePost.ID))
+
+	// ListForUser のテスト
+	t.Run("ListForUser", func(t *testing.T) {
+		first, last, err := syncRepo.SeqRange(ctx, time.Now().UTC())
+		require.NoError(t, err)
+		require.Greater(t, first, int64(0))
+		require.GreaterOrEqual(t, last, first)
+
+		events, err := syncRepo.ListForUser(ctx, syncer.ID, 0, last, 100)
+		require.NoError(t, err)
+
+		// フォローしていないユーザーの投稿は含まれない
+		types := make([]models.SyncEventType, 0, len(events))
+		for _, event := range events {
+			types = append(types, event.Type)
+			if event.SubjectID != nil {
+				assert.NotEqual(t, strangerPost.ID, *event.SubjectID)
+			}
+		}
+		assert.Equal(t, []models.SyncEventType{
+			models.SyncEventFollowed,
+			models.SyncEventPostCreated,
+			models.SyncEventPostCreated,
+			models.SyncEventNotificationCreated,
+			models.SyncEventNotificationRead,
+			models.SyncEventPostDeleted,
+		}, types)
+
+		require.NotNil(t, events[0].TargetUserID)
+		assert.Equal(t, followee.ID, *events[0].TargetUserID)
+		require.NotNil(t, events[5].SubjectID)
+		assert.Equal(t, followeePost.ID, *events[5].SubjectID)
+
+		// カーソルより後のイベントのみを返す
+		events, err = syncRepo.ListForUser(ctx, syncer.ID, events[4].Seq, last, 100)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.SyncEventPostDeleted, events[0].Type)
+
+		// フォローされたユーザーにはフォローが含まれる
+		events, err = syncRepo.ListForUser(ctx, followee.ID, 0, last, 100)
+		require.NoError(t, err)
+		require.NotEmpty(t, events)
+		assert.Equal(t, models.SyncEventFollowed, events[0].Type)
+	})
+
+	// DeleteBefore のテスト
+	t.Run("DeleteBefore", func(t *testing.T) {
+		_, last, err := syncRepo.SeqRange(ctx, time.Now().UTC())
+		require.NoError(t, err)
+
+		deleted, err := syncRepo.DeleteBefore(ctx, time.Now().UTC().Add(time.Hour), 100)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), deleted)
+
+		// 最新のイベントは残る
+		first, remaining, err := syncRepo.SeqRange(ctx, time.Now().UTC())
+		require.NoError(t, err)
+		assert.Equal(t, last, first)
+		assert.Equal(t, last, remaining)
+	})
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/TakuyaAizawa/gox/migrations"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -69,42 +69,17 @@ func NewTestDB(t *testing.T) *TestDB {
 	}
 }
 
-// runMigrations はマイグレーションを実行します
+// runMigrations はバイナリに埋め込まれたマイグレーションを実行します
 func runMigrations(t *testing.T, dbURL string) error {
 	t.Helper()
 
-	// プロジェクトのルートディレクトリを見つける（GoXディレクトリ）
-	wd, err := os.Getwd()
+	source, err := iofs.New(migrations.FS, ".")
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %v", err)
+		return fmt.Errorf("failed to load migrations: %v", err)
 	}
-	t.Logf("Current working directory: %s", wd)
-
-	// "internal/repository/postgres/testing" から "migrations" へのパスを構築
-	dir := wd
-	for {
-		if filepath.Base(dir) == "GoX" {
-			break
-		}
-		dir = filepath.Dir(dir)
-		if dir == "." || dir == "/" {
-			return fmt.Errorf("could not find project root (GoX directory)")
-		}
-	}
-	t.Logf("Project root directory: %s", dir)
-
-	migrationsPath := filepath.Join(dir, "migrations")
-	// ディレクトリの存在確認
-	if _, err := os.Stat(migrationsPath); os.IsNotExist(err) {
-		return fmt.Errorf("migrations directory not found at %s", migrationsPath)
-	}
-	t.Logf("Migrations directory: %s", migrationsPath)
-
-	migrationsURL := fmt.Sprintf("file://%s", migrationsPath)
-	t.Logf("Migrations URL: %s", migrationsURL)
 
 	// マイグレーションインスタンスの作成
-	m, err := migrate.New(migrationsURL, dbURL)
+	m, err := migrate.NewWithSourceInstance("iofs", source, dbURL)
 	if err != nil {
 		return fmt.Errorf("failed to create migrate instance: %v", err)
 	}
@@ -147,9 +122,48 @@ func (db *TestDB) CleanupAllTables(t *testing.T) {
 	// 外部キー制約を考慮して、正しい順序でクリーンアップ
 	tables := []string{
 		"notifications",
+		"conversation_mutes",
+		"post_daily_views",
+		"post_edits",
+		"analytics_events",
 		"likes",
+		"post_reactions",
+		"post_topics",
+		"topics",
+		"post_imports",
 		"posts",
+		"blocks",
+		"supporter_events",
+		"search_history",
+		"saved_searches",
+		"profile_visits",
+		"user_settings",
+		"user_presence",
+		"user_activity_days",
+		"user_cohort_stats",
+		"api_usage_hourly",
+		"list_members",
+		"lists",
 		"follows",
+		"follow_events",
+		"user_identities",
+		"account_deletions",
+		"audit_logs",
+		"reports",
+		"content_filter_rules",
+		"account_merges",
+		"username_redirects",
+		"media_uploads",
+		"media_objects",
+		"user_webhooks",
+		"upload_sessions",
+		"sync_events",
+		"user_storage_usage",
+		"device_tokens",
+		"notification_settings",
+		"outbox_events",
+		"jobs",
+		"hashtag_trends",
 		"users",
 	}
 
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var queryTimeout atomic.Int64
+
+// SetQueryTimeout はトランザクション外のクエリ1回あたりの最大実行時間を設定します（0で無効）
+// リクエストのコンテキストにより早い期限がある場合はそちらを優先します
+func SetQueryTimeout(timeout time.Duration) {
+	queryTimeout.Store(int64(timeout))
+}
+
+// SetStatementTimeout はPostgreSQLのstatement_timeoutを接続時のパラメータとして設定します（0で無効）
+// サーバー側で文を打ち切るため、クライアントが応答を待たなくなった後も文が実行され続けることを防ぎます
+// 接続プーラー（pgbouncer）は既定で未知の接続時パラメータを拒否するため、互換モードではロールやプーラー側で設定してください
+func SetStatementTimeout(config *pgxpool.Config, timeout time.Duration) {
+	if timeout <= 0 {
+		delete(config.ConnConfig.RuntimeParams, "statement_timeout")
+		return
+	}
+	config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(timeout.Milliseconds(), 10)
+}
+
+// queryContext はクエリ1回分の期限を設定したコンテキストを返します
+// ctxの期限の方が早い場合や期限が無効の場合はctxをそのまま返します
+func queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := time.Duration(queryTimeout.Load())
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutRows はCloseの時点でクエリ1回分の期限を解放するpgx.Rowsです
+type timeoutRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// timeoutBatchResults はCloseの時点でバッチ1回分の期限を解放するpgx.BatchResultsです
+type timeoutBatchResults struct {
+	pgx.BatchResults
+	cancel context.CancelFunc
+}
+
+func (r timeoutBatchResults) Close() error {
+	defer r.cancel()
+	return r.BatchResults.Close()
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryContext(t *testing.T) {
+	SetQueryTimeout(time.Second)
+	defer SetQueryTimeout(0)
+
+	t.Run("AddsDeadline", func(t *testing.T) {
+		ctx, cancel := queryContext(context.Background())
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+	})
+
+	t.Run("KeepsEarlierDeadline", func(t *testing.T) {
+		parent, parentCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer parentCancel()
+
+		ctx, cancel := queryContext(parent)
+		defer cancel()
+		assert.Equal(t, parent, ctx)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		SetQueryTimeout(0)
+		defer SetQueryTimeout(time.Second)
+
+		ctx, cancel := queryContext(context.Background())
+		defer cancel()
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+	})
+}
+
+func TestSetStatementTimeout(t *testing.T) {
+	config, err := pgxpool.ParseConfig("postgres://gox@127.0.0.1:1/gox")
+	require.NoError(t, err)
+
+	SetStatementTimeout(config, 5*time.Second)
+	assert.Equal(t, "5000", config.ConnConfig.RuntimeParams["statement_timeout"])
+
+	SetStatementTimeout(config, 0)
+	assert.NotContains(t, config.ConnConfig.RuntimeParams, "statement_timeout")
+}
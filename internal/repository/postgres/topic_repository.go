@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const topicColumns = `id, slug, name, description, hashtags, created_by, created_at, updated_at`
+
+type topicRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTopicRepository creates a new PostgreSQL implementation of TopicRepository
+func NewTopicRepository(db *pgxpool.Pool) interfaces.TopicRepository {
+	return &topicRepository{db: db}
+}
+
+// Create stores a topic. Slugs are unique, so a duplicate is rejected.
+func (r *topicRepository) Create(ctx context.Context, topic *models.Topic) error {
+	query := `
+		INSERT INTO topics (id, slug, name, description, hashtags, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		topic.ID, topic.Slug, topic.Name, topic.Description, topic.Hashtags, topic.CreatedBy, topic.CreatedAt, topic.UpdatedAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("topic already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns a topic by its ID
+func (r *topicRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Topic, error) {
+	return r.getOne(ctx, "SELECT "+topicColumns+" FROM topics WHERE id = $1", id)
+}
+
+// GetBySlug returns a topic by its slug
+func (r *topicRepository) GetBySlug(ctx context.Context, slug string) (*models.Topic, error) {
+	return r.getOne(ctx, "SELECT "+topicColumns+" FROM topics WHERE slug = $1", slug)
+}
+
+func (r *topicRepository) getOne(ctx context.Context, query string, arg any) (*models.Topic, error) {
+	var topic models.Topic
+	err := scanTopic(conn(ctx, r.db).QueryRow(ctx, query, arg), &topic)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("topic not found")
+		}
+		return nil, err
+	}
+
+	return &topic, nil
+}
+
+// List returns every topic ordered by name
+func (r *topicRepository) List(ctx context.Context) ([]*models.Topic, error) {
+	return r.queryTopics(ctx, "SELECT "+topicColumns+" FROM topics ORDER BY name, slug")
+}
+
+// Update changes the slug, name, description and hashtags of a topic.
+// Posts already classified keep their topics.
+func (r *topicRepository) Update(ctx context.Context, topic *models.Topic) error {
+	query := `
+		UPDATE topics
+		SET slug = $2, name = $3, description = $4, hashtags = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	topic.UpdatedAt = time.Now().UTC()
+	result, err := conn(ctx, r.db).Exec(ctx, query,
+		topic.ID, topic.Slug, topic.Name, topic.Description, topic.Hashtags, topic.UpdatedAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return errors.New("topic already exists")
+		}
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("topic not found")
+	}
+
+	return nil
+}
+
+// Delete removes a topic together with the classification of its posts
+func (r *topicRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM topics WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("topic not found")
+	}
+
+	return nil
+}
+
+// TagPost classifies a post into the topics chosen by its author and the topics
+// listing any of its hashtags. A topic matching both is recorded as manual.
+// Unknown slugs are ignored and tagging the same post again is a no-op.
+func (r *topicRepository) TagPost(ctx context.Context, postID uuid.UUID, manualSlugs []string, hashtags []string) error {
+	if len(manualSlugs) == 0 && len(hashtags) == 0 {
+		return nil
+	}
+	if manualSlugs == nil {
+		manualSlugs = []string{}
+	}
+	if hashtags == nil {
+		hashtags = []string{}
+	}
+
+	query := `
+		INSERT INTO post_topics (post_id, topic_id, source)
+		SELECT $1, id, CASE WHEN slug = ANY($2) THEN $4 ELSE $5 END
+		FROM topics
+		WHERE slug = ANY($2) OR hashtags && $3
+		ON CONFLICT (post_id, topic_id) DO NOTHING
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		postID, manualSlugs, hashtags, models.TopicSourceManual, models.TopicSourceHashtag,
+	)
+	return err
+}
+
+// GetByPostID returns the topics a post is classified into, ordered by name
+func (r *topicRepository) GetByPostID(ctx context.Context, postID uuid.UUID) ([]*models.Topic, error) {
+	query := `
+		SELECT ` + topicColumns + `
+		FROM topics
+		WHERE id IN (SELECT topic_id FROM post_topics WHERE post_id = $1)
+		ORDER BY name, slug
+	`
+
+	return r.queryTopics(ctx, query, postID)
+}
+
+func (r *topicRepository) queryTopics(ctx context.Context, query string, args ...any) ([]*models.Topic, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	topics := make([]*models.Topic, 0)
+	for rows.Next() {
+		var topic models.Topic
+		if err := scanTopic(rows, &topic); err != nil {
+			return nil, err
+		}
+		topics = append(topics, &topic)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return topics, nil
+}
+
+func scanTopic(row pgx.Row, topic *models.Topic) error {
+	return row.Scan(
+		&topic.ID, &topic.Slug, &topic.Name, &topic.Description, &topic.Hashtags,
+		&topic.CreatedBy, &topic.CreatedAt, &topic.UpdatedAt,
+	)
+}
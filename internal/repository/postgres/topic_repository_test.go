@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	topicRepo := NewTopicRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// トピックを作成する管理者と投稿者
+	admin := &models.User{
+		ID:        uuid.New(),
+		Username:  "topicadmin",
+		Email:     "topicadmin@example.com",
+		Password:  "hashedpassword",
+		Name:      "Topic Admin",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, admin))
+
+	author := &models.User{
+		ID:        uuid.New(),
+		Username:  "topicauthor",
+		Email:     "topicauthor@example.com",
+		Password:  "hashedpassword",
+		Name:      "Topic Author",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, author))
+
+	var tech, games *models.Topic
+
+	// Create・GetByID・GetBySlug のテスト
+	t.Run("CreateAndGet", func(t *testing.T) {
+		tech = models.NewTopic("tech", "Technology", "Gadgets and software", []string{"golang", "ai"}, admin.ID)
+		require.NoError(t, topicRepo.Create(ctx, tech))
+
+		topic, err := topicRepo.GetByID(ctx, tech.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "tech", topic.Slug)
+		assert.Equal(t, []string{"golang", "ai"}, topic.Hashtags)
+		require.NotNil(t, topic.CreatedBy)
+		assert.Equal(t, admin.ID, *topic.CreatedBy)
+
+		topic, err = topicRepo.GetBySlug(ctx, "tech")
+		require.NoError(t, err)
+		assert.Equal(t, tech.ID, topic.ID)
+
+		// 同じスラッグのトピックは重複になる
+		err = topicRepo.Create(ctx, models.NewTopic("tech", "Tech again", "", nil, admin.ID))
+		require.Error(t, err)
+		assert.Equal(t, "topic already exists", err.Error())
+
+		_, err = topicRepo.GetBySlug(ctx, "unknown")
+		require.Error(t, err)
+		assert.Equal(t, "topic not found", err.Error())
+	})
+
+	// List と Update のテスト
+	t.Run("ListAndUpdate", func(t *testing.T) {
+		games = models.NewTopic("games", "Games", "", []string{"gaming"}, admin.ID)
+		require.NoError(t, topicRepo.Create(ctx, games))
+
+		topics, err := topicRepo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, topics, 2)
+		assert.Equal(t, "games", topics[0].Slug)
+		assert.Equal(t, "tech", topics[1].Slug)
+
+		games.Hashtags = []string{"gaming", "esports"}
+		require.NoError(t, topicRepo.Update(ctx, games))
+
+		topic, err := topicRepo.GetByID(ctx, games.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gaming", "esports"}, topic.Hashtags)
+
+		// 他のトピックと同じスラッグには変更できない
+		games.Slug = "tech"
+		err = topicRepo.Update(ctx, games)
+		require.Error(t, err)
+		assert.Equal(t, "topic already exists", err.Error())
+		games.Slug = "games"
+	})
+
+	// TagPost・GetByPostID・投稿のトピック別一覧のテスト
+	t.Run("TagPostAndListByTopic", func(t *testing.T) {
+		now := time.Now().UTC()
+		newPost := func(content string, age time.Duration, likes int) *models.Post {
+			post := models.NewPost(author.ID, content, nil)
+			post.LikeCount = likes
+			post.CreatedAt = now.Add(-age)
+			post.UpdatedAt = post.CreatedAt
+			require.NoError(t, postRepo.Create(ctx, post))
+			return post
+		}
+
+		// ハッシュタグからの分類と投稿者の指定を組み合わせる
+		hashtagged := newPost("Learning #golang today", 2*time.Hour, 10)
+		require.NoError(t, topicRepo.TagPost(ctx, hashtagged.ID, nil, hashtagged.Hashtags()))
+		chosen := newPost("New console announced", time.Hour, 1)
+		require.NoError(t, topicRepo.TagPost(ctx, chosen.ID, []string{"tech", "games", "unknown"}, nil))
+		spoiler := newPost("Spoiler about the #esports final", 30*time.Minute, 50)
+		require.NoError(t, topicRepo.TagPost(ctx, spoiler.ID, nil, spoiler.Hashtags()))
+		newPost("Untagged post", 10*time.Minute, 100)
+
+		// 同じ投稿を再度分類しても重複しない
+		require.NoError(t, topicRepo.TagPost(ctx, hashtagged.ID, []string{"tech"}, hashtagged.Hashtags()))
+
+		topics, err := topicRepo.GetByPostID(ctx, chosen.ID)
+		require.NoError(t, err)
+		require.Len(t, topics, 2)
+		assert.Equal(t, "games", topics[0].Slug)
+		assert.Equal(t, "tech", topics[1].Slug)
+
+		posts, err := postRepo.ListByTopic(ctx, uuid.Nil, tech.ID, nil, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, posts, 2)
+		assert.Equal(t, chosen.ID, posts[0].ID)
+		assert.Equal(t, hashtagged.ID, posts[1].ID)
+
+		posts, err = postRepo.ListByTopic(ctx, uuid.Nil, games.ID, []string{"spoiler"}, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, chosen.ID, posts[0].ID)
+
+		posts, err = postRepo.ListPopularByTopic(ctx, uuid.Nil, tech.ID, 7*24*time.Hour, nil, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, posts, 2)
+		assert.Equal(t, hashtagged.ID, posts[0].ID)
+
+		// トピックを削除すると投稿の分類も削除される
+		require.NoError(t, topicRepo.Delete(ctx, games.ID))
+		topics, err = topicRepo.GetByPostID(ctx, chosen.ID)
+		require.NoError(t, err)
+		require.Len(t, topics, 1)
+		assert.Equal(t, "tech", topics[0].Slug)
+
+		err = topicRepo.Delete(ctx, games.ID)
+		require.Error(t, err)
+		assert.Equal(t, "topic not found", err.Error())
+	})
+}
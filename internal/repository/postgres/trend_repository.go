@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// hashtagPattern matches a hashtag that is not preceded by a word character (as in "C#"),
+// capturing the hashtag without "#" in the second group
+const hashtagPattern = `(^|[^[:alnum:]_&])#([[:alnum:]_]+)`
+
+// maxTrendHashtagLength is the length of the hashtag column
+const maxTrendHashtagLength = 100
+
+type trendRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTrendRepository creates a new PostgreSQL implementation of TrendRepository
+func NewTrendRepository(db *pgxpool.Pool) interfaces.TrendRepository {
+	return &trendRepository{db: db}
+}
+
+// AggregateHour replaces the counts of the hour starting at hour in one transaction,
+// so hashtags of posts deleted since the last run are dropped
+func (r *trendRepository) AggregateHour(ctx context.Context, hour time.Time) (int64, error) {
+	hour = hour.UTC().Truncate(time.Hour)
+
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM hashtag_trends WHERE hour = $1`, hour); err != nil {
+		return 0, err
+	}
+
+	query := `
+		INSERT INTO hashtag_trends (hashtag, hour, post_count, user_count)
+		SELECT lower(m[2]), $1, COUNT(DISTINCT p.id), COUNT(DISTINCT p.user_id)
+		FROM posts p
+		CROSS JOIN LATERAL regexp_matches(p.content, $3, 'g') AS m
+		WHERE p.created_at >= $1 AND p.created_at < $2
+			AND char_length(m[2]) <= $4
+			AND ` + visiblePostCondition + `
+		GROUP BY lower(m[2])
+	`
+
+	result, err := tx.Exec(ctx, query, hour, hour.Add(time.Hour), hashtagPattern, maxTrendHashtagLength)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *trendRepository) ListTop(ctx context.Context, since time.Time, limit int) ([]*models.Trend, error) {
+	query := `
+		SELECT hashtag, SUM(post_count), SUM(user_count)
+		FROM hashtag_trends
+		WHERE hour >= $1
+		GROUP BY hashtag
+		ORDER BY SUM(user_count) DESC, SUM(post_count) DESC, hashtag ASC
+		LIMIT $2
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, since.UTC().Truncate(time.Hour), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trends := make([]*models.Trend, 0, limit)
+	for rows.Next() {
+		trend := &models.Trend{}
+		if err := rows.Scan(&trend.Hashtag, &trend.PostCount, &trend.UserCount); err != nil {
+			return nil, err
+		}
+		trends = append(trends, trend)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return trends, nil
+}
+
+func (r *trendRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM hashtag_trends WHERE hour < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrendRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	trendRepo := NewTrendRepository(db.Pool)
+
+	ctx := context.Background()
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+
+	users := make([]*models.User, 0, 2)
+	for _, name := range []string{"trenduser1", "trenduser2"} {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  name,
+			Email:     name + "@example.com",
+			Password:  "hashedpassword",
+			Name:      name,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		users = append(users, user)
+	}
+
+	createPost := func(user *models.User, content string, createdAt time.Time) {
+		post := models.NewPost(user.ID, content, nil)
+		post.CreatedAt = createdAt
+		post.UpdatedAt = createdAt
+		require.NoError(t, postRepo.Create(ctx, post))
+	}
+
+	// 集計する1時間の投稿
+	createPost(users[0], "Hello #GoLang and #gox", hour.Add(10*time.Minute))
+	createPost(users[0], "More #golang", hour.Add(20*time.Minute))
+	createPost(users[1], "#golang is fun", hour.Add(30*time.Minute))
+	// 単語の途中の#はハッシュタグとして扱わない
+	createPost(users[1], "Learning C#sharp", hour.Add(40*time.Minute))
+	// 集計する1時間の範囲外の投稿
+	createPost(users[1], "Later #gox", hour.Add(90*time.Minute))
+
+	// 集計と取得のテスト
+	t.Run("AggregateAndListTop", func(t *testing.T) {
+		count, err := trendRepo.AggregateHour(ctx, hour)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// 集計し直しても重複しない
+		count, err = trendRepo.AggregateHour(ctx, hour)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		trends, err := trendRepo.ListTop(ctx, hour, 10)
+		require.NoError(t, err)
+		require.Len(t, trends, 2)
+		assert.Equal(t, "golang", trends[0].Hashtag)
+		assert.Equal(t, int64(3), trends[0].PostCount)
+		assert.Equal(t, int64(2), trends[0].UserCount)
+		assert.Equal(t, "gox", trends[1].Hashtag)
+		assert.Equal(t, int64(1), trends[1].PostCount)
+
+		// 期間外の集計は含めない
+		trends, err = trendRepo.ListTop(ctx, hour.Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, trends)
+	})
+
+	// 古い集計の削除のテスト
+	t.Run("DeleteBefore", func(t *testing.T) {
+		deleted, err := trendRepo.DeleteBefore(ctx, hour)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), deleted)
+
+		deleted, err = trendRepo.DeleteBefore(ctx, hour.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+	})
+}
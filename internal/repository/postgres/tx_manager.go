@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dbtx is implemented by both *pgxpool.Pool and pgx.Tx
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// txKey is the context key of the transaction started by TxManager
+type txKey struct{}
+
+// txState is the transaction in progress and the callbacks to run after it commits
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func()
+}
+
+// conn returns the transaction in ctx, or the pool when ctx is not in a transaction.
+// Statements run on the pool are retried on transient errors
+func conn(ctx context.Context, db *pgxpool.Pool) dbtx {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return retryingPool{pool: db}
+}
+
+type txManager struct {
+	db *pgxpool.Pool
+}
+
+// NewTxManager creates a new PostgreSQL implementation of TxManager
+func NewTxManager(db *pgxpool.Pool) interfaces.TxManager {
+	return &txManager{db: db}
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	// A transaction that fails with a transient error has been rolled back, so it is run again from the start
+	var state *txState
+	err := withRetry(ctx, func() error {
+		var err error
+		state, err = m.runTx(ctx, fn)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, callback := range state.afterCommit {
+		callback()
+	}
+	return nil
+}
+
+// runTx runs fn in a new transaction and returns its state once committed
+func (m *txManager) runTx(ctx context.Context, fn func(ctx context.Context) error) (*txState, error) {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (m *txManager) AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// DryRun runs fn in a transaction (or a savepoint when ctx is already in one) that is always
+// rolled back. Callbacks registered with AfterCommit during fn are discarded with it
+func (m *txManager) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	var tx pgx.Tx
+	var err error
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		tx, err = state.tx.Begin(ctx)
+	} else {
+		tx, err = m.db.Begin(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	return fn(context.WithValue(ctx, txKey{}, &txState{tx: tx}))
+}
@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	postRepo := NewPostRepository(db.Pool)
+	notificationRepo := NewNotificationRepository(db.Pool)
+	txManager := NewTxManager(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user1 := &models.User{
+		ID:        uuid.New(),
+		Username:  "user1",
+		Email:     "user1@example.com",
+		Password:  "hashedpassword",
+		Name:      "User 1",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	user2 := &models.User{
+		ID:        uuid.New(),
+		Username:  "user2",
+		Email:     "user2@example.com",
+		Password:  "hashedpassword",
+		Name:      "User 2",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user1))
+	require.NoError(t, userRepo.Create(ctx, user2))
+
+	parent := models.NewPost(user1.ID, "Parent post", nil)
+	require.NoError(t, postRepo.Create(ctx, parent))
+
+	// 返信の保存・返信数の更新・通知の作成を1つのトランザクションで行う
+	createReply := func(ctx context.Context, reply *models.Post) error {
+		if err := postRepo.Create(ctx, reply); err != nil {
+			return err
+		}
+		if err := postRepo.IncrementReplyCount(ctx, parent.ID); err != nil {
+			return err
+		}
+		notification := models.NewNotification(user1.ID, user2.ID, models.NotificationTypeReply, &reply.ID)
+		return notificationRepo.Create(ctx, notification)
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		reply := models.NewReply(user2.ID, parent.ID, "Reply", nil)
+		committed := false
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := createReply(ctx, reply); err != nil {
+				return err
+			}
+			txManager.AfterCommit(ctx, func() { committed = true })
+
+			// コミット前はトランザクションの外から見えない
+			_, err := postRepo.GetByID(context.Background(), reply.ID)
+			assert.Error(t, err)
+			assert.False(t, committed)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, committed)
+
+		_, err = postRepo.GetByID(ctx, reply.ID)
+		require.NoError(t, err)
+		updated, err := postRepo.GetByID(ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated.ReplyCount)
+		count, err := notificationRepo.CountUnreadByUserID(ctx, user1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		reply := models.NewReply(user2.ID, parent.ID, "Rolled back reply", nil)
+		committed := false
+		errFailed := errors.New("failed")
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := createReply(ctx, reply); err != nil {
+				return err
+			}
+			txManager.AfterCommit(ctx, func() { committed = true })
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+		assert.False(t, committed)
+
+		_, err = postRepo.GetByID(ctx, reply.ID)
+		assert.Error(t, err)
+		updated, err := postRepo.GetByID(ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated.ReplyCount)
+		count, err := notificationRepo.CountUnreadByUserID(ctx, user1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	// スレッドの作成は外側のトランザクションに参加し、後続の処理が失敗した場合はスレッド全体が取り消される
+	t.Run("ThreadRollback", func(t *testing.T) {
+		first := models.NewReply(user2.ID, parent.ID, "Thread 1/2", nil)
+		second := models.NewReply(user2.ID, first.ID, "Thread 2/2", nil)
+		errFailed := errors.New("failed")
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := postRepo.CreateThread(ctx, []*models.Post{first, second}); err != nil {
+				return err
+			}
+			notification := models.NewNotification(user1.ID, user2.ID, models.NotificationTypeReply, &first.ID)
+			if err := notificationRepo.Create(ctx, notification); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+
+		_, err = postRepo.GetByID(ctx, first.ID)
+		assert.Error(t, err)
+		_, err = postRepo.GetByID(ctx, second.ID)
+		assert.Error(t, err)
+		updated, err := postRepo.GetByID(ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated.ReplyCount)
+		count, err := notificationRepo.CountUnreadByUserID(ctx, user1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	// ドライランは同じ処理を実行してからロールバックし、コミット後の処理は実行しない
+	t.Run("DryRun", func(t *testing.T) {
+		reply := models.NewReply(user2.ID, parent.ID, "Dry run reply", nil)
+		committed := false
+
+		err := txManager.DryRun(ctx, func(ctx context.Context) error {
+			// 内側のWithinTxはドライランのトランザクションに参加する
+			err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+				return createReply(ctx, reply)
+			})
+			if err != nil {
+				return err
+			}
+			txManager.AfterCommit(ctx, func() { committed = true })
+
+			// ドライランの中では実行した結果が見える
+			updated, err := postRepo.GetByID(ctx, parent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 2, updated.ReplyCount)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, committed)
+
+		_, err = postRepo.GetByID(ctx, reply.ID)
+		assert.Error(t, err)
+		updated, err := postRepo.GetByID(ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated.ReplyCount)
+	})
+
+	// トランザクション内のドライランはセーブポイントまでロールバックする
+	t.Run("DryRunWithinTx", func(t *testing.T) {
+		kept := models.NewReply(user2.ID, parent.ID, "Kept reply", nil)
+		dropped := models.NewReply(user2.ID, parent.ID, "Dropped reply", nil)
+
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := postRepo.Create(ctx, kept); err != nil {
+				return err
+			}
+			return txManager.DryRun(ctx, func(ctx context.Context) error {
+				return postRepo.Create(ctx, dropped)
+			})
+		})
+		require.NoError(t, err)
+
+		_, err = postRepo.GetByID(ctx, kept.ID)
+		require.NoError(t, err)
+		_, err = postRepo.GetByID(ctx, dropped.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("AfterCommitOutsideTx", func(t *testing.T) {
+		called := false
+		txManager.AfterCommit(ctx, func() { called = true })
+		assert.True(t, called)
+	})
+}
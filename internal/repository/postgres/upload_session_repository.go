@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const uploadSessionColumns = `id, user_id, filename, total_size, part_size, part_count, received_parts, url, created_at, updated_at, expires_at`
+
+type uploadSessionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewUploadSessionRepository creates a new PostgreSQL implementation of UploadSessionRepository
+func NewUploadSessionRepository(db *pgxpool.Pool) interfaces.UploadSessionRepository {
+	return &uploadSessionRepository{db: db}
+}
+
+// Create stores a new upload session
+func (r *uploadSessionRepository) Create(ctx context.Context, session *models.UploadSession) error {
+	query := `
+		INSERT INTO upload_sessions (
+			id, user_id, filename, total_size, part_size, part_count, received_parts, url, created_at, updated_at, expires_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		session.ID, session.UserID, session.Filename, session.TotalSize, session.PartSize, session.PartCount,
+		uploadSessionParts(session), session.URL, session.CreatedAt, session.UpdatedAt, session.ExpiresAt,
+	)
+	return err
+}
+
+// GetByID returns an upload session by its ID
+func (r *uploadSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.UploadSession, error) {
+	query := "SELECT " + uploadSessionColumns + " FROM upload_sessions WHERE id = $1"
+	return r.getSession(ctx, query, id)
+}
+
+// AddPart records a received part, keeping the indexes sorted. Receiving the
+// same part again leaves the session unchanged.
+func (r *uploadSessionRepository) AddPart(ctx context.Context, id uuid.UUID, index int) (*models.UploadSession, error) {
+	query := `
+		UPDATE upload_sessions
+		SET received_parts = ARRAY(
+				SELECT DISTINCT part FROM unnest(array_append(received_parts, $2::integer)) AS part ORDER BY part
+			),
+			updated_at = $3
+		WHERE id = $1
+		RETURNING ` + uploadSessionColumns
+
+	return r.getSession(ctx, query, id, index, time.Now().UTC())
+}
+
+// Complete records the URL of the assembled file
+func (r *uploadSessionRepository) Complete(ctx context.Context, id uuid.UUID, url string) (*models.UploadSession, error) {
+	query := `
+		UPDATE upload_sessions SET url = $2, updated_at = $3
+		WHERE id = $1
+		RETURNING ` + uploadSessionColumns
+
+	return r.getSession(ctx, query, id, url, time.Now().UTC())
+}
+
+// ListExpired returns up to limit sessions that expired before now, oldest first
+func (r *uploadSessionRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*models.UploadSession, error) {
+	query := "SELECT " + uploadSessionColumns + " FROM upload_sessions WHERE expires_at <= $1 ORDER BY expires_at, id LIMIT $2"
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.UploadSession, 0)
+	for rows.Next() {
+		var session models.UploadSession
+		if err := scanUploadSession(rows, &session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Delete removes an upload session
+func (r *uploadSessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM upload_sessions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("upload session not found")
+	}
+
+	return nil
+}
+
+func (r *uploadSessionRepository) getSession(ctx context.Context, query string, args ...interface{}) (*models.UploadSession, error) {
+	var session models.UploadSession
+	if err := scanUploadSession(conn(ctx, r.db).QueryRow(ctx, query, args...), &session); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("upload session not found")
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// uploadSessionParts returns the received parts of a session, never nil, for storing as an array
+func uploadSessionParts(session *models.UploadSession) []int {
+	if session.ReceivedParts == nil {
+		return []int{}
+	}
+	return session.ReceivedParts
+}
+
+func scanUploadSession(row pgx.Row, session *models.UploadSession) error {
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.Filename, &session.TotalSize, &session.PartSize, &session.PartCount,
+		&session.ReceivedParts, &session.URL, &session.CreatedAt, &session.UpdatedAt, &session.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if session.ReceivedParts == nil {
+		session.ReceivedParts = []int{}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadSessionRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	sessionRepo := NewUploadSessionRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// アップロードするユーザー
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "uploaduser",
+		Email:     "uploaduser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Upload User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	// 10バイトを4バイトずつに分ける（最後のパートは2バイト）
+	session := models.NewUploadSession(user.ID, "video.mp4", 10, 4, time.Hour)
+
+	// Create と GetByID のテスト
+	t.Run("CreateAndGet", func(t *testing.T) {
+		require.NoError(t, sessionRepo.Create(ctx, session))
+
+		got, err := sessionRepo.GetByID(ctx, session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "video.mp4", got.Filename)
+		assert.Equal(t, 3, got.PartCount)
+		assert.Equal(t, int64(2), got.PartLength(2))
+		assert.Empty(t, got.ReceivedParts)
+		assert.False(t, got.IsCompleted())
+
+		_, err = sessionRepo.GetByID(ctx, uuid.New())
+		require.Error(t, err)
+		assert.Equal(t, "upload session not found", err.Error())
+	})
+
+	// AddPart のテスト
+	t.Run("AddPart", func(t *testing.T) {
+		got, err := sessionRepo.AddPart(ctx, session.ID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, got.ReceivedParts)
+		assert.Equal(t, []int{0, 1}, got.MissingParts())
+
+		// 同じパートを再送しても重複しない
+		got, err = sessionRepo.AddPart(ctx, session.ID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, got.ReceivedParts)
+
+		_, err = sessionRepo.AddPart(ctx, session.ID, 0)
+		require.NoError(t, err)
+		got, err = sessionRepo.AddPart(ctx, session.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2}, got.ReceivedParts)
+		assert.True(t, got.IsReady())
+	})
+
+	// Complete のテスト
+	t.Run("Complete", func(t *testing.T) {
+		got, err := sessionRepo.Complete(ctx, session.ID, "http://localhost:8080/media/media/ab/abc.mp4")
+		require.NoError(t, err)
+		assert.True(t, got.IsCompleted())
+		assert.Equal(t, "http://localhost:8080/media/media/ab/abc.mp4", got.URL)
+	})
+
+	// ListExpired と Delete のテスト
+	t.Run("ListExpiredAndDelete", func(t *testing.T) {
+		expired := models.NewUploadSession(user.ID, "old.mp4", 10, 4, -time.Minute)
+		require.NoError(t, sessionRepo.Create(ctx, expired))
+
+		sessions, err := sessionRepo.ListExpired(ctx, time.Now().UTC(), 10)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, expired.ID, sessions[0].ID)
+
+		require.NoError(t, sessionRepo.Delete(ctx, expired.ID))
+		err = sessionRepo.Delete(ctx, expired.ID)
+		require.Error(t, err)
+		assert.Equal(t, "upload session not found", err.Error())
+	})
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type userIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewUserIdentityRepository creates a new PostgreSQL implementation of UserIdentityRepository
+func NewUserIdentityRepository(db *pgxpool.Pool) interfaces.UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) GetByIssuerAndSubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	query := `
+		SELECT issuer, subject, user_id, COALESCE(email, ''), created_at, last_login_at
+		FROM user_identities
+		WHERE issuer = $1 AND subject = $2
+	`
+
+	identity := &models.UserIdentity{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, issuer, subject).Scan(
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("identity not found")
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (issuer, subject, user_id, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		identity.Issuer,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+		identity.CreatedAt,
+		identity.LastLoginAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return errors.New("identity already linked")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *userIdentityRepository) RecordLogin(ctx context.Context, issuer, subject, email string) error {
+	query := `
+		UPDATE user_identities
+		SET last_login_at = NOW(), email = NULLIF($3, '')
+		WHERE issuer = $1 AND subject = $2
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, issuer, subject, email)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("identity not found")
+	}
+
+	return nil
+}
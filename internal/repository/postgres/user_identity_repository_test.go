@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserIdentityRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	identityRepo := NewUserIdentityRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// テストユーザーの作成
+	user := models.NewUser("ssouser", "ssouser@example.com", "hashedpassword", "SSO User")
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	const issuer = "https://idp.example.com"
+
+	// Create のテスト
+	t.Run("Create", func(t *testing.T) {
+		now := time.Now().UTC()
+		identity := &models.UserIdentity{
+			Issuer:      issuer,
+			Subject:     "subject-1",
+			UserID:      user.ID,
+			Email:       user.Email,
+			CreatedAt:   now,
+			LastLoginAt: now,
+		}
+		require.NoError(t, identityRepo.Create(ctx, identity))
+
+		// 同じアカウントは二重に紐付けられない
+		err := identityRepo.Create(ctx, identity)
+		require.Error(t, err)
+		assert.Equal(t, "identity already linked", err.Error())
+	})
+
+	// GetByIssuerAndSubject のテスト
+	t.Run("GetByIssuerAndSubject", func(t *testing.T) {
+		identity, err := identityRepo.GetByIssuerAndSubject(ctx, issuer, "subject-1")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, identity.UserID)
+		assert.Equal(t, user.Email, identity.Email)
+
+		// 発行者が異なる場合は別のアカウントとして扱う
+		_, err = identityRepo.GetByIssuerAndSubject(ctx, "https://other.example.com", "subject-1")
+		require.Error(t, err)
+		assert.Equal(t, "identity not found", err.Error())
+	})
+
+	// RecordLogin のテスト
+	t.Run("RecordLogin", func(t *testing.T) {
+		before, err := identityRepo.GetByIssuerAndSubject(ctx, issuer, "subject-1")
+		require.NoError(t, err)
+
+		require.NoError(t, identityRepo.RecordLogin(ctx, issuer, "subject-1", "renamed@example.com"))
+
+		after, err := identityRepo.GetByIssuerAndSubject(ctx, issuer, "subject-1")
+		require.NoError(t, err)
+		assert.Equal(t, "renamed@example.com", after.Email)
+		assert.False(t, after.LastLoginAt.Before(before.LastLoginAt))
+
+		err = identityRepo.RecordLogin(ctx, issuer, "missing", "missing@example.com")
+		require.Error(t, err)
+		assert.Equal(t, "identity not found", err.Error())
+	})
+
+	// ユーザーを削除すると紐付けも削除される
+	t.Run("CascadeOnUserDelete", func(t *testing.T) {
+		other := models.NewUser("ssoother", "ssoother@example.com", "hashedpassword", "SSO Other")
+		require.NoError(t, userRepo.Create(ctx, other))
+		require.NoError(t, identityRepo.Create(ctx, &models.UserIdentity{
+			Issuer:      issuer,
+			Subject:     "subject-2",
+			UserID:      other.ID,
+			CreatedAt:   time.Now().UTC(),
+			LastLoginAt: time.Now().UTC(),
+		}))
+
+		require.NoError(t, userRepo.Delete(ctx, other.ID))
+
+		_, err := identityRepo.GetByIssuerAndSubject(ctx, issuer, "subject-2")
+		assert.Error(t, err)
+	})
+
+	// UpdateRole のテスト
+	t.Run("UpdateRole", func(t *testing.T) {
+		require.NoError(t, userRepo.UpdateRole(ctx, user.ID, models.UserRoleAdmin))
+
+		updated, err := userRepo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, updated.IsAdmin())
+
+		err = userRepo.UpdateRole(ctx, uuid.New(), models.UserRoleAdmin)
+		assert.Error(t, err)
+	})
+}
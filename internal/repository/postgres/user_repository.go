@@ -4,14 +4,36 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// userColumns is the column list shared by the user SELECT queries
+const userColumns = `id, username, email, password, name, bio, profile_image,
+			follower_count, following_count, post_count, is_verified,
+			is_age_verified, birth_date, country_code,
+			supporter_tier, supporter_until, is_system, status, deactivated_at, role,
+			password_reset_required, created_at, updated_at`
+
+// activeUserCondition excludes deactivated and deleting accounts from user lookups
+const activeUserCondition = `status = 'active'`
+
+// inactiveUserIDs selects the deactivated, deleting, suspended, merging and banned accounts, whose posts and follows are hidden
+const inactiveUserIDs = `SELECT id FROM users WHERE status <> 'active'`
+
+// blockRelatedUserIDs selects the users who blocked or were blocked by the viewer given as the placeholder viewerParam
+// If the viewer is uuid.Nil it selects nothing
+func blockRelatedUserIDs(viewerParam string) string {
+	return `SELECT blocked_id FROM blocks WHERE blocker_id = ` + viewerParam + `
+		UNION ALL SELECT blocker_id FROM blocks WHERE blocked_id = ` + viewerParam
+}
+
 type userRepository struct {
 	db *pgxpool.Pool
 }
@@ -26,14 +48,16 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 		INSERT INTO users (
 			id, username, email, password, name, bio, profile_image,
 			follower_count, following_count, post_count, is_verified,
+			is_age_verified, birth_date, country_code, is_system,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := conn(ctx, r.db).Exec(ctx, query,
 		user.ID, user.Username, user.Email, user.Password, user.Name,
 		user.Bio, user.ProfileImage, user.FollowerCount, user.FollowingCount,
-		user.PostCount, user.IsVerified, user.CreatedAt, user.UpdatedAt,
+		user.PostCount, user.IsVerified, user.IsAgeVerified, user.BirthDate,
+		user.CountryCode, user.IsSystem, user.CreatedAt, user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -49,20 +73,14 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, username, email, password, name, bio, profile_image,
-			follower_count, following_count, post_count, is_verified,
-			created_at, updated_at
-		FROM users WHERE id = $1
+		SELECT ` + userColumns + `
+		FROM users WHERE id = $1 AND ` + activeUserCondition + `
 	`
 
 	var user models.User
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password, &user.Name,
-		&user.Bio, &user.ProfileImage, &user.FollowerCount, &user.FollowingCount,
-		&user.PostCount, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-	)
+	err := scanUser(conn(ctx, r.db).QueryRow(ctx, query, id), &user)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, errors.New("user not found")
 	}
 	if err != nil {
@@ -72,22 +90,57 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	return &user, nil
 }
 
+func (r *userRepository) GetByIDIncludingInactive(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users WHERE id = $1
+	`
+
+	var user models.User
+	err := scanUser(conn(ctx, r.db).QueryRow(ctx, query, id), &user)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *userRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.User, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]*models.User{}, nil
+	}
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users WHERE id = ANY($1) AND ` + activeUserCondition + `
+	`
+
+	users, err := r.queryUsers(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	usersByID := make(map[uuid.UUID]*models.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	return usersByID, nil
+}
+
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
-		SELECT id, username, email, password, name, bio, profile_image,
-			follower_count, following_count, post_count, is_verified,
-			created_at, updated_at
-		FROM users WHERE username = $1
+		SELECT ` + userColumns + `
+		FROM users WHERE username = $1 AND ` + activeUserCondition + `
 	`
 
 	var user models.User
-	err := r.db.QueryRow(ctx, query, username).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password, &user.Name,
-		&user.Bio, &user.ProfileImage, &user.FollowerCount, &user.FollowingCount,
-		&user.PostCount, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-	)
+	err := scanUser(readConn(ctx, r.db).QueryRow(ctx, query, username), &user)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, errors.New("user not found")
 	}
 	if err != nil {
@@ -99,20 +152,14 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*m
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, username, email, password, name, bio, profile_image,
-			follower_count, following_count, post_count, is_verified,
-			created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users WHERE email = $1
 	`
 
 	var user models.User
-	err := r.db.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password, &user.Name,
-		&user.Bio, &user.ProfileImage, &user.FollowerCount, &user.FollowingCount,
-		&user.PostCount, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-	)
+	err := scanUser(conn(ctx, r.db).QueryRow(ctx, query, email), &user)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, errors.New("user not found")
 	}
 	if err != nil {
@@ -127,14 +174,16 @@ func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 		UPDATE users SET
 			username = $1, email = $2, name = $3, bio = $4,
 			profile_image = $5, follower_count = $6, following_count = $7,
-			post_count = $8, is_verified = $9, updated_at = $10
-		WHERE id = $11
+			post_count = $8, is_verified = $9, country_code = $10,
+			is_age_verified = $11, birth_date = $12, updated_at = $13
+		WHERE id = $14
 	`
 
-	result, err := r.db.Exec(ctx, query,
+	result, err := conn(ctx, r.db).Exec(ctx, query,
 		user.Username, user.Email, user.Name, user.Bio,
 		user.ProfileImage, user.FollowerCount, user.FollowingCount,
-		user.PostCount, user.IsVerified, user.UpdatedAt, user.ID,
+		user.PostCount, user.IsVerified, user.CountryCode,
+		user.IsAgeVerified, user.BirthDate, user.UpdatedAt, user.ID,
 	)
 
 	if err != nil {
@@ -154,7 +203,7 @@ func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := "DELETE FROM users WHERE id = $1"
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -168,84 +217,58 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*models.User, error) {
 	query := `
-		SELECT id, username, email, password, name, bio, profile_image,
-			follower_count, following_count, post_count, is_verified,
-			created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
+		WHERE ` + activeUserCondition + `
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var users []*models.User
-	for rows.Next() {
-		var user models.User
-		err := rows.Scan(
-			&user.ID, &user.Username, &user.Email, &user.Password, &user.Name,
-			&user.Bio, &user.ProfileImage, &user.FollowerCount, &user.FollowingCount,
-			&user.PostCount, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, &user)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return users, nil
+	return r.queryUsers(ctx, query, limit, offset)
 }
 
 func (r *userRepository) Search(ctx context.Context, query string, offset, limit int) ([]*models.User, error) {
 	sqlQuery := `
-		SELECT id, username, email, password, name, bio, profile_image,
-			follower_count, following_count, post_count, is_verified,
-			created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE username ILIKE $1 OR name ILIKE $1
+		WHERE (username ILIKE $1 OR name ILIKE $1) AND ` + activeUserCondition + `
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, sqlQuery, "%"+query+"%", limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	return r.readUsers(ctx, sqlQuery, "%"+query+"%", limit, offset)
+}
 
-	var users []*models.User
-	for rows.Next() {
-		var user models.User
-		err := rows.Scan(
-			&user.ID, &user.Username, &user.Email, &user.Password, &user.Name,
-			&user.Bio, &user.ProfileImage, &user.FollowerCount, &user.FollowingCount,
-			&user.PostCount, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, &user)
-	}
+// GetByRedirectedUsername returns the active account a merged username redirects to
+func (r *userRepository) GetByRedirectedUsername(ctx context.Context, username string) (*models.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = (SELECT user_id FROM username_redirects WHERE username = $1) AND ` + activeUserCondition + `
+	`
 
-	if err = rows.Err(); err != nil {
+	var user models.User
+	err := scanUser(conn(ctx, r.db).QueryRow(ctx, query, username), &user)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("user not found")
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	return users, nil
+	return &user, nil
 }
 
 func (r *userRepository) IsUsernameAvailable(ctx context.Context, username string) (bool, error) {
-	query := "SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)"
+	// 統合により転送されているユーザー名は、転送先を奪えないよう登録できない
+	query := `
+		SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)
+			OR EXISTS(SELECT 1 FROM username_redirects WHERE username = $1)
+	`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, username).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, username).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -253,11 +276,36 @@ func (r *userRepository) IsUsernameAvailable(ctx context.Context, username strin
 	return !exists, nil
 }
 
+func (r *userRepository) GetSystemUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	query := "SELECT id FROM users WHERE is_system ORDER BY created_at"
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (r *userRepository) IsEmailAvailable(ctx context.Context, email string) (bool, error) {
 	query := "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)"
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, email).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -269,7 +317,7 @@ func (r *userRepository) Count(ctx context.Context) (int64, error) {
 	query := "SELECT COUNT(*) FROM users"
 
 	var count int64
-	err := r.db.QueryRow(ctx, query).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -285,7 +333,7 @@ func (r *userRepository) UpdateAvatar(ctx context.Context, userID uuid.UUID, ava
 		WHERE id = $2
 	`
 
-	result, err := r.db.Exec(ctx, query, avatarURL, userID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, avatarURL, userID)
 	if err != nil {
 		return err
 	}
@@ -306,7 +354,7 @@ func (r *userRepository) UpdateBanner(ctx context.Context, userID uuid.UUID, ban
 		WHERE id = $2
 	`
 
-	result, err := r.db.Exec(ctx, query, bannerURL, userID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, bannerURL, userID)
 	if err != nil {
 		return err
 	}
@@ -318,3 +366,370 @@ func (r *userRepository) UpdateBanner(ctx context.Context, userID uuid.UUID, ban
 
 	return nil
 }
+
+func (r *userRepository) Deactivate(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'deactivated', deactivated_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND ` + activeUserCondition + `
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) Reactivate(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'active', deactivated_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'deactivated'
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) MarkForDeletion(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'deleting', updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// MarkForMerge hides an active account while it is being merged into another account
+func (r *userRepository) MarkForMerge(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'merging', updated_at = NOW()
+		WHERE id = $1 AND ` + activeUserCondition + `
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) Suspend(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'suspended', updated_at = NOW()
+		WHERE id = $1 AND status IN ('active', 'deactivated')
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) Unsuspend(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'active', deactivated_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'suspended'
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) Ban(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'banned', updated_at = NOW()
+		WHERE id = $1 AND status IN ('active', 'deactivated')
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) Unban(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET status = 'active', deactivated_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'banned'
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// adminUserCondition filters the admin user list by a partial match on username, name or email and by status
+const adminUserCondition = `($1 = '' OR username ILIKE '%' || $1 || '%' OR name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%')
+		AND ($2 = '' OR status = $2)`
+
+func (r *userRepository) ListForAdmin(ctx context.Context, query string, status models.UserStatus, offset, limit int) ([]*models.User, error) {
+	sqlQuery := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE ` + adminUserCondition + `
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.queryUsers(ctx, sqlQuery, query, string(status), limit, offset)
+}
+
+func (r *userRepository) CountForAdmin(ctx context.Context, query string, status models.UserStatus) (int64, error) {
+	sqlQuery := "SELECT COUNT(*) FROM users WHERE " + adminUserCondition
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, sqlQuery, query, string(status)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *userRepository) SetVerified(ctx context.Context, userID uuid.UUID, verified bool) error {
+	query := `
+		UPDATE users
+		SET is_verified = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, verified, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) SetAgeVerification(ctx context.Context, userID uuid.UUID, birthDate *time.Time, countryCode string) error {
+	query := `
+		UPDATE users
+		SET is_age_verified = $1, birth_date = $2,
+			country_code = COALESCE(NULLIF($3, ''), country_code), updated_at = NOW()
+		WHERE id = $4
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, birthDate != nil, birthDate, countryCode, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) SetPasswordResetRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	query := `
+		UPDATE users
+		SET password_reset_required = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, required, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error {
+	query := `
+		UPDATE users
+		SET password = $1, password_reset_required = FALSE, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, hashedPassword, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// provisionedUserCondition selects the accounts an identity provider can manage:
+// everything except system accounts and accounts being deleted, optionally filtered by username
+const provisionedUserCondition = `is_system = false AND status NOT IN ('deleting', 'merging') AND ($1 = '' OR LOWER(username) = LOWER($1))`
+
+func (r *userRepository) ListProvisioned(ctx context.Context, username string, offset, limit int) ([]*models.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE ` + provisionedUserCondition + `
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryUsers(ctx, query, username, limit, offset)
+}
+
+func (r *userRepository) CountProvisioned(ctx context.Context, username string) (int64, error) {
+	query := "SELECT COUNT(*) FROM users WHERE " + provisionedUserCondition
+
+	var count int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, username).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *userRepository) ListPopular(ctx context.Context, excludeIDs []uuid.UUID, limit int) ([]*models.User, error) {
+	if excludeIDs == nil {
+		excludeIDs = []uuid.UUID{}
+	}
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE ` + activeUserCondition + ` AND is_system = false AND id <> ALL($1)
+		ORDER BY follower_count DESC, post_count DESC, created_at
+		LIMIT $2
+	`
+
+	return r.readUsers(ctx, query, excludeIDs, limit)
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role models.UserRole) error {
+	query := `
+		UPDATE users
+		SET role = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, role, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// queryUsers is a helper function to execute queries that return user lists
+func (r *userRepository) queryUsers(ctx context.Context, query string, args ...interface{}) ([]*models.User, error) {
+	return queryUsersOn(ctx, conn(ctx, r.db), query, args...)
+}
+
+// readUsers is queryUsers for reads that tolerate replication lag (searches and suggestions),
+// which run on the read replica when one is configured
+func (r *userRepository) readUsers(ctx context.Context, query string, args ...interface{}) ([]*models.User, error) {
+	return queryUsersOn(ctx, readConn(ctx, r.db), query, args...)
+}
+
+func queryUsersOn(ctx context.Context, db dbtx, query string, args ...interface{}) ([]*models.User, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// scanUser scans a row selected with userColumns into user
+func scanUser(row pgx.Row, user *models.User) error {
+	return row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.Password, &user.Name,
+		&user.Bio, &user.ProfileImage, &user.FollowerCount, &user.FollowingCount,
+		&user.PostCount, &user.IsVerified,
+		&user.IsAgeVerified, &user.BirthDate, &user.CountryCode,
+		&user.SupporterTier, &user.SupporterUntil, &user.IsSystem, &user.Status, &user.DeactivatedAt, &user.Role,
+		&user.PasswordResetRequired, &user.CreatedAt, &user.UpdatedAt,
+	)
+}
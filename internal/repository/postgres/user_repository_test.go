@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,6 +75,26 @@ func TestUserRepository(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	// GetByIDs のテスト
+	t.Run("GetByIDs", func(t *testing.T) {
+		// テスト前にクリーンアップ
+		db.CleanupAllTables(t)
+		err := repo.Create(ctx, testUser)
+		require.NoError(t, err)
+
+		missingID := uuid.New()
+		users, err := repo.GetByIDs(ctx, []uuid.UUID{testUser.ID, missingID})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, testUser.Username, users[testUser.ID].Username)
+		assert.NotContains(t, users, missingID)
+
+		// 空のIDリスト
+		users, err = repo.GetByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
 	// GetByUsername のテスト
 	t.Run("GetByUsername", func(t *testing.T) {
 		// テスト前にクリーンアップ
@@ -117,6 +138,7 @@ func TestUserRepository(t *testing.T) {
 
 		testUser.Bio = "Updated bio"
 		testUser.Name = "Updated Name"
+		testUser.CountryCode = "JP"
 		err = repo.Update(ctx, testUser)
 		require.NoError(t, err)
 
@@ -125,6 +147,8 @@ func TestUserRepository(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "Updated bio", updated.Bio)
 		assert.Equal(t, "Updated Name", updated.Name)
+		assert.Equal(t, "JP", updated.CountryCode)
+		assert.False(t, updated.IsAgeVerified)
 
 		// 存在しないユーザーの更新を試みる
 		nonexistentUser := &models.User{
@@ -208,6 +232,324 @@ func TestUserRepository(t *testing.T) {
 		assert.True(t, available)
 	})
 
+	// GetSystemUserIDs のテスト
+	t.Run("GetSystemUserIDs", func(t *testing.T) {
+		// 一般ユーザーのみの場合は空
+		ids, err := repo.GetSystemUserIDs(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+
+		systemUser := &models.User{
+			ID:         uuid.New(),
+			Username:   "gox",
+			Email:      "gox@system.invalid",
+			Password:   "!",
+			Name:       "gox",
+			IsVerified: true,
+			IsSystem:   true,
+			CreatedAt:  time.Now().UTC(),
+			UpdatedAt:  time.Now().UTC(),
+		}
+		require.NoError(t, repo.Create(ctx, systemUser))
+
+		ids, err = repo.GetSystemUserIDs(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{systemUser.ID}, ids)
+
+		user, err := repo.GetByUsername(ctx, "gox")
+		require.NoError(t, err)
+		assert.True(t, user.IsSystem)
+
+		require.NoError(t, repo.Delete(ctx, systemUser.ID))
+	})
+
+	// Deactivate と Reactivate のテスト
+	t.Run("DeactivateAndReactivate", func(t *testing.T) {
+		err := repo.Deactivate(ctx, testUser.ID)
+		require.NoError(t, err)
+
+		// 無効化されたアカウントはIDやユーザー名では取得できない
+		_, err = repo.GetByID(ctx, testUser.ID)
+		assert.Error(t, err)
+		_, err = repo.GetByUsername(ctx, testUser.Username)
+		assert.Error(t, err)
+
+		// ログインのためメールアドレスでは取得できる
+		user, err := repo.GetByEmail(ctx, testUser.Email)
+		require.NoError(t, err)
+		assert.True(t, user.IsDeactivated())
+		require.NotNil(t, user.DeactivatedAt)
+		assert.True(t, user.CanReactivateAt(time.Now().UTC(), time.Hour))
+		assert.False(t, user.CanReactivateAt(time.Now().UTC().Add(2*time.Hour), time.Hour))
+
+		// 無効化済みのアカウントは再度無効化できない
+		err = repo.Deactivate(ctx, testUser.ID)
+		assert.Error(t, err)
+
+		err = repo.Reactivate(ctx, testUser.ID)
+		require.NoError(t, err)
+
+		user, err = repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStatusActive, user.Status)
+		assert.Nil(t, user.DeactivatedAt)
+	})
+
+	// ListPopular のテスト
+	t.Run("ListPopular", func(t *testing.T) {
+		popularUser := &models.User{
+			ID:            uuid.New(),
+			Username:      "popularuser",
+			Email:         "popular@example.com",
+			Password:      "hashedpassword",
+			Name:          "Popular User",
+			FollowerCount: 100,
+			CreatedAt:     time.Now().UTC(),
+			UpdatedAt:     time.Now().UTC(),
+		}
+		require.NoError(t, repo.Create(ctx, popularUser))
+		defer repo.Delete(ctx, popularUser.ID)
+
+		systemUser := &models.User{
+			ID:            uuid.New(),
+			Username:      "popularsystem",
+			Email:         "popularsystem@system.invalid",
+			Password:      "!",
+			Name:          "System",
+			FollowerCount: 1000,
+			IsSystem:      true,
+			CreatedAt:     time.Now().UTC(),
+			UpdatedAt:     time.Now().UTC(),
+		}
+		require.NoError(t, repo.Create(ctx, systemUser))
+		defer repo.Delete(ctx, systemUser.ID)
+
+		// フォロワーの多い順で、システムアカウントは含まない
+		users, err := repo.ListPopular(ctx, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+		assert.Equal(t, popularUser.ID, users[0].ID)
+		assert.Equal(t, testUser.ID, users[1].ID)
+
+		// 除外したユーザーは含まない
+		users, err = repo.ListPopular(ctx, []uuid.UUID{popularUser.ID}, 10)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, testUser.ID, users[0].ID)
+	})
+
+	// Suspend と Unsuspend のテスト
+	t.Run("SuspendAndUnsuspend", func(t *testing.T) {
+		err := repo.Suspend(ctx, testUser.ID)
+		require.NoError(t, err)
+
+		// 停止されたアカウントは隠される
+		_, err = repo.GetByID(ctx, testUser.ID)
+		assert.Error(t, err)
+
+		user, err := repo.GetByIDIncludingInactive(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.True(t, user.IsSuspended())
+
+		// 停止されたアカウントは本人の操作では再開できない
+		err = repo.Reactivate(ctx, testUser.ID)
+		assert.Error(t, err)
+
+		err = repo.Unsuspend(ctx, testUser.ID)
+		require.NoError(t, err)
+
+		user, err = repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStatusActive, user.Status)
+
+		// 停止されていないアカウントは再開できない
+		err = repo.Unsuspend(ctx, testUser.ID)
+		assert.Error(t, err)
+	})
+
+	// Ban と Unban のテスト
+	t.Run("BanAndUnban", func(t *testing.T) {
+		err := repo.Ban(ctx, testUser.ID)
+		require.NoError(t, err)
+
+		// 管理者が停止したアカウントは隠される
+		_, err = repo.GetByID(ctx, testUser.ID)
+		assert.Error(t, err)
+
+		user, err := repo.GetByIDIncludingInactive(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.True(t, user.IsBanned())
+		assert.False(t, user.IsSuspended())
+
+		// IdPからの再開では解除できない
+		err = repo.Unsuspend(ctx, testUser.ID)
+		assert.Error(t, err)
+
+		// IdPからの停止で上書きされない
+		err = repo.Suspend(ctx, testUser.ID)
+		assert.Error(t, err)
+
+		err = repo.Unban(ctx, testUser.ID)
+		require.NoError(t, err)
+
+		user, err = repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStatusActive, user.Status)
+
+		// 停止されていないアカウントは解除できない
+		err = repo.Unban(ctx, testUser.ID)
+		assert.Error(t, err)
+	})
+
+	// ListProvisioned と CountProvisioned のテスト
+	t.Run("ListProvisioned", func(t *testing.T) {
+		systemUser := &models.User{
+			ID:        uuid.New(),
+			Username:  "provisioningsystem",
+			Email:     "provisioningsystem@system.invalid",
+			Password:  "!",
+			Name:      "System",
+			IsSystem:  true,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, repo.Create(ctx, systemUser))
+		defer repo.Delete(ctx, systemUser.ID)
+
+		require.NoError(t, repo.Suspend(ctx, testUser.ID))
+		defer repo.Unsuspend(ctx, testUser.ID)
+
+		// 停止されたアカウントは含み、システムアカウントは含まない
+		users, err := repo.ListProvisioned(ctx, "", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, testUser.ID, users[0].ID)
+
+		count, err := repo.CountProvisioned(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// ユーザー名は大文字・小文字を区別しない
+		users, err = repo.ListProvisioned(ctx, strings.ToUpper(users[0].Username), 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+
+		users, err = repo.ListProvisioned(ctx, "provisioningsystem", 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+
+		count, err = repo.CountProvisioned(ctx, "nobody")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	// UpdateRole のテスト
+	t.Run("UpdateRole", func(t *testing.T) {
+		err := repo.UpdateRole(ctx, testUser.ID, models.UserRoleModerator)
+		require.NoError(t, err)
+
+		user, err := repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserRoleModerator, user.Role)
+		assert.True(t, user.IsModerator())
+		assert.False(t, user.IsAdmin())
+
+		// 未知の権限は保存できない
+		err = repo.UpdateRole(ctx, testUser.ID, models.UserRole("owner"))
+		assert.Error(t, err)
+
+		err = repo.UpdateRole(ctx, testUser.ID, models.UserRoleUser)
+		require.NoError(t, err)
+
+		// 存在しないユーザー
+		err = repo.UpdateRole(ctx, uuid.New(), models.UserRoleAdmin)
+		assert.Error(t, err)
+	})
+
+	// ListForAdmin と CountForAdmin のテスト
+	t.Run("ListForAdmin", func(t *testing.T) {
+		require.NoError(t, repo.Suspend(ctx, testUser.ID))
+		defer repo.Unsuspend(ctx, testUser.ID)
+
+		// 停止されたアカウントも含む
+		users, err := repo.ListForAdmin(ctx, "", "", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, testUser.ID, users[0].ID)
+
+		// メールアドレスの部分一致と状態で絞り込む
+		users, err = repo.ListForAdmin(ctx, strings.Split(testUser.Email, "@")[0], models.UserStatusSuspended, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+
+		count, err := repo.CountForAdmin(ctx, "", models.UserStatusActive)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		count, err = repo.CountForAdmin(ctx, "nobody", "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	// SetVerified のテスト
+	t.Run("SetVerified", func(t *testing.T) {
+		require.NoError(t, repo.SetVerified(ctx, testUser.ID, true))
+
+		user, err := repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.True(t, user.IsVerified)
+
+		require.NoError(t, repo.SetVerified(ctx, testUser.ID, false))
+
+		err = repo.SetVerified(ctx, uuid.New(), true)
+		assert.Error(t, err)
+	})
+
+	// SetAgeVerification のテスト
+	t.Run("SetAgeVerification", func(t *testing.T) {
+		birthDate := time.Date(2000, 4, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.SetAgeVerification(ctx, testUser.ID, &birthDate, "JP"))
+
+		user, err := repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.True(t, user.IsAgeVerified)
+		require.NotNil(t, user.BirthDate)
+		assert.True(t, birthDate.Equal(*user.BirthDate))
+		assert.Equal(t, "JP", user.CountryCode)
+
+		// 取り消しても国コードは変更しない
+		require.NoError(t, repo.SetAgeVerification(ctx, testUser.ID, nil, ""))
+
+		user, err = repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.False(t, user.IsAgeVerified)
+		assert.Nil(t, user.BirthDate)
+		assert.Equal(t, "JP", user.CountryCode)
+
+		err = repo.SetAgeVerification(ctx, uuid.New(), &birthDate, "JP")
+		assert.Error(t, err)
+	})
+
+	// SetPasswordResetRequired と UpdatePassword のテスト
+	t.Run("PasswordReset", func(t *testing.T) {
+		require.NoError(t, repo.SetPasswordResetRequired(ctx, testUser.ID, true))
+
+		user, err := repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.True(t, user.PasswordResetRequired)
+
+		// 新しいパスワードを設定すると要求は解除される
+		require.NoError(t, repo.UpdatePassword(ctx, testUser.ID, "newhashedpassword"))
+
+		user, err = repo.GetByID(ctx, testUser.ID)
+		require.NoError(t, err)
+		assert.False(t, user.PasswordResetRequired)
+		assert.Equal(t, "newhashedpassword", user.Password)
+
+		err = repo.UpdatePassword(ctx, uuid.New(), "newhashedpassword")
+		assert.Error(t, err)
+	})
+
 	// Delete のテスト
 	t.Run("Delete", func(t *testing.T) {
 		err := repo.Delete(ctx, testUser.ID)
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type userStatsRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewUserStatsRepository creates a new PostgreSQL implementation of UserStatsRepository
+func NewUserStatsRepository(db *pgxpool.Pool) interfaces.UserStatsRepository {
+	return &userStatsRepository{db: db}
+}
+
+func (r *userStatsRepository) RecordActivity(ctx context.Context, userID uuid.UUID, day time.Time) error {
+	query := `
+		INSERT INTO user_activity_days (user_id, activity_date)
+		VALUES ($1, $2::date)
+		ON CONFLICT (user_id, activity_date) DO NOTHING
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, userID, formatDate(day))
+	return err
+}
+
+func (r *userStatsRepository) RollupCohorts(ctx context.Context, from, to, today time.Time) (int64, error) {
+	// DnリテンションはN日後に活動したユーザー数。N日後が終わっていない場合はNULLとする
+	query := `
+		INSERT INTO user_cohort_stats (
+			cohort_date, cohort_size, d1_retained, d7_retained, d30_retained, computed_at
+		)
+		SELECT
+			c.cohort_date,
+			COUNT(*),
+			CASE WHEN c.cohort_date + 1 < $3::date THEN COUNT(a1.user_id) END,
+			CASE WHEN c.cohort_date + 7 < $3::date THEN COUNT(a7.user_id) END,
+			CASE WHEN c.cohort_date + 30 < $3::date THEN COUNT(a30.user_id) END,
+			NOW()
+		FROM (
+			SELECT id, (created_at AT TIME ZONE 'UTC')::date AS cohort_date
+			FROM users
+			WHERE (created_at AT TIME ZONE 'UTC')::date BETWEEN $1::date AND $2::date
+		) c
+		LEFT JOIN user_activity_days a1
+			ON a1.user_id = c.id AND a1.activity_date = c.cohort_date + 1
+		LEFT JOIN user_activity_days a7
+			ON a7.user_id = c.id AND a7.activity_date = c.cohort_date + 7
+		LEFT JOIN user_activity_days a30
+			ON a30.user_id = c.id AND a30.activity_date = c.cohort_date + 30
+		GROUP BY c.cohort_date
+		ON CONFLICT (cohort_date) DO UPDATE SET
+			cohort_size = EXCLUDED.cohort_size,
+			d1_retained = EXCLUDED.d1_retained,
+			d7_retained = EXCLUDED.d7_retained,
+			d30_retained = EXCLUDED.d30_retained,
+			computed_at = EXCLUDED.computed_at
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, formatDate(from), formatDate(to), formatDate(today))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *userStatsRepository) GetCohorts(ctx context.Context, from, to time.Time) ([]*models.CohortStats, error) {
+	query := `
+		SELECT cohort_date, cohort_size, d1_retained, d7_retained, d30_retained, computed_at
+		FROM user_cohort_stats
+		WHERE cohort_date BETWEEN $1::date AND $2::date
+		ORDER BY cohort_date ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, formatDate(from), formatDate(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cohorts []*models.CohortStats
+	for rows.Next() {
+		cohort := &models.CohortStats{}
+		err := rows.Scan(
+			&cohort.CohortDate, &cohort.CohortSize, &cohort.D1Retained,
+			&cohort.D7Retained, &cohort.D30Retained, &cohort.ComputedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cohorts = append(cohorts, cohort)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cohorts, nil
+}
+
+// CountInstanceStats counts the public totals, excluding system accounts and inactive users
+func (r *userStatsRepository) CountInstanceStats(ctx context.Context, activeSince time.Time) (*models.InstanceStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE status = 'active' AND NOT is_system),
+			(SELECT COUNT(*) FROM posts WHERE ` + visiblePostCondition + `),
+			(
+				SELECT COUNT(DISTINCT a.user_id)
+				FROM user_activity_days a
+				JOIN users u ON u.id = a.user_id
+				WHERE a.activity_date >= $1::date AND u.status = 'active' AND NOT u.is_system
+			)
+	`
+
+	stats := &models.InstanceStats{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, formatDate(activeSince)).Scan(
+		&stats.TotalUsers, &stats.TotalPosts, &stats.ActiveUsersLastMonth,
+	)
+	if err != nil {
+		return nil, err
+	}
+	stats.ComputedAt = time.Now().UTC()
+
+	return stats, nil
+}
+
+// formatDate formats t as a UTC date for DATE parameters
+func formatDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
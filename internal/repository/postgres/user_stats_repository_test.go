@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserStatsRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	statsRepo := NewUserStatsRepository(db.Pool)
+
+	ctx := context.Background()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	cohortDay := today.AddDate(0, 0, -40)
+	recentDay := today.AddDate(0, 0, -1)
+
+	newUser := func(username string, createdAt time.Time) *models.User {
+		user := &models.User{
+			ID:        uuid.New(),
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  "hashedpassword",
+			Name:      username,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+
+	// 40日前に登録したユーザー2人と、昨日登録したユーザー1人
+	early1 := newUser("cohortuser1", cohortDay.Add(9*time.Hour))
+	early2 := newUser("cohortuser2", cohortDay.Add(15*time.Hour))
+	newUser("cohortuser3", recentDay.Add(12*time.Hour))
+
+	// RecordActivity のテスト
+	t.Run("RecordActivity", func(t *testing.T) {
+		require.NoError(t, statsRepo.RecordActivity(ctx, early1.ID, cohortDay.AddDate(0, 0, 1)))
+		require.NoError(t, statsRepo.RecordActivity(ctx, early1.ID, cohortDay.AddDate(0, 0, 7)))
+		require.NoError(t, statsRepo.RecordActivity(ctx, early2.ID, cohortDay.AddDate(0, 0, 30)))
+
+		// 同じ日の重複は無視される
+		err := statsRepo.RecordActivity(ctx, early1.ID, cohortDay.AddDate(0, 0, 1))
+		assert.NoError(t, err)
+	})
+
+	// RollupCohorts のテスト
+	t.Run("RollupCohorts", func(t *testing.T) {
+		count, err := statsRepo.RollupCohorts(ctx, cohortDay, recentDay, today)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// 再集計しても重複しない
+		count, err = statsRepo.RollupCohorts(ctx, cohortDay, recentDay, today)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	// GetCohorts のテスト
+	t.Run("GetCohorts", func(t *testing.T) {
+		cohorts, err := statsRepo.GetCohorts(ctx, cohortDay, recentDay)
+		require.NoError(t, err)
+		require.Len(t, cohorts, 2)
+
+		early := cohorts[0]
+		assert.Equal(t, cohortDay.Format("2006-01-02"), early.CohortDate.Format("2006-01-02"))
+		assert.Equal(t, 2, early.CohortSize)
+		require.NotNil(t, early.D1Retained)
+		require.NotNil(t, early.D7Retained)
+		require.NotNil(t, early.D30Retained)
+		assert.Equal(t, 1, *early.D1Retained)
+		assert.Equal(t, 1, *early.D7Retained)
+		assert.Equal(t, 1, *early.D30Retained)
+
+		// 昨日のコホートのリテンションはまだ確定していない
+		recent := cohorts[1]
+		assert.Equal(t, 1, recent.CohortSize)
+		assert.Nil(t, recent.D1Retained)
+		assert.Nil(t, recent.D7Retained)
+		assert.Nil(t, recent.D30Retained)
+
+		// 期間外のコホートは含まれない
+		cohorts, err = statsRepo.GetCohorts(ctx, today, today)
+		require.NoError(t, err)
+		assert.Empty(t, cohorts)
+	})
+
+	// CountInstanceStats のテスト
+	t.Run("CountInstanceStats", func(t *testing.T) {
+		postRepo := NewPostRepository(db.Pool)
+		require.NoError(t, postRepo.Create(ctx, models.NewPost(early1.ID, "Public post", nil)))
+
+		// 30日以内に活動したのはearly2のみ（early1の活動は30日より前）
+		stats, err := statsRepo.CountInstanceStats(ctx, today.AddDate(0, 0, -30))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), stats.TotalUsers)
+		assert.Equal(t, int64(1), stats.TotalPosts)
+		assert.Equal(t, int64(1), stats.ActiveUsersLastMonth)
+		assert.False(t, stats.ComputedAt.IsZero())
+	})
+}
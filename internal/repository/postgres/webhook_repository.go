@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const webhookColumns = `id, user_id, url, events, secret, active, failure_count, last_status_code, last_delivered_at, created_at, updated_at`
+
+type webhookRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewWebhookRepository creates a new PostgreSQL implementation of WebhookRepository
+func NewWebhookRepository(db *pgxpool.Pool) interfaces.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// Create stores a new webhook
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.UserWebhook) error {
+	events, err := json.Marshal(webhookEvents(webhook))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO user_webhooks (id, user_id, url, events, secret, active, failure_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = conn(ctx, r.db).Exec(ctx, query,
+		webhook.ID, webhook.UserID, webhook.URL, events, webhook.Secret, webhook.Active, webhook.FailureCount,
+		webhook.CreatedAt, webhook.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID returns a webhook by its ID
+func (r *webhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.UserWebhook, error) {
+	query := "SELECT " + webhookColumns + " FROM user_webhooks WHERE id = $1"
+
+	var webhook models.UserWebhook
+	err := scanWebhook(conn(ctx, r.db).QueryRow(ctx, query, id), &webhook)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("webhook not found")
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// ListByUser returns a user's webhooks, oldest first
+func (r *webhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserWebhook, error) {
+	query := "SELECT " + webhookColumns + " FROM user_webhooks WHERE user_id = $1 ORDER BY created_at, id"
+	return r.queryWebhooks(ctx, query, userID)
+}
+
+// CountByUser returns the number of webhooks a user registered
+func (r *webhookRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := conn(ctx, r.db).QueryRow(ctx, "SELECT COUNT(*) FROM user_webhooks WHERE user_id = $1", userID).Scan(&count)
+	return count, err
+}
+
+// ListActiveByEvent returns a user's active webhooks subscribed to the event
+func (r *webhookRepository) ListActiveByEvent(ctx context.Context, userID uuid.UUID, event models.WebhookEvent) ([]*models.UserWebhook, error) {
+	query := "SELECT " + webhookColumns + ` FROM user_webhooks
+		WHERE user_id = $1 AND active AND events @> jsonb_build_array($2::text)
+		ORDER BY created_at, id`
+	return r.queryWebhooks(ctx, query, userID, string(event))
+}
+
+// Update changes the URL, events, active flag and failure count of a webhook
+func (r *webhookRepository) Update(ctx context.Context, webhook *models.UserWebhook) error {
+	events, err := json.Marshal(webhookEvents(webhook))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE user_webhooks
+		SET url = $2, events = $3, active = $4, failure_count = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	webhook.UpdatedAt = time.Now().UTC()
+	result, err := conn(ctx, r.db).Exec(ctx, query,
+		webhook.ID, webhook.URL, events, webhook.Active, webhook.FailureCount, webhook.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("webhook not found")
+	}
+
+	return nil
+}
+
+// UpdateSecret replaces the signing secret of a webhook
+func (r *webhookRepository) UpdateSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	query := "UPDATE user_webhooks SET secret = $2, updated_at = $3 WHERE id = $1"
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id, secret, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("webhook not found")
+	}
+
+	return nil
+}
+
+// RecordDelivery stores the outcome of a delivery. A success resets the
+// failure count; a failure increments it and deactivates the webhook once it
+// reaches maxFailures (zero or less never deactivates). It reports whether the
+// webhook is still active.
+func (r *webhookRepository) RecordDelivery(ctx context.Context, id uuid.UUID, statusCode *int, succeeded bool, maxFailures int) (bool, error) {
+	query := `
+		UPDATE user_webhooks
+		SET last_status_code = $2,
+			last_delivered_at = $3,
+			failure_count = CASE WHEN $4 THEN 0 ELSE failure_count + 1 END,
+			active = CASE WHEN $4 OR $5 <= 0 THEN active ELSE active AND failure_count + 1 < $5 END
+		WHERE id = $1
+		RETURNING active
+	`
+
+	var active bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, id, statusCode, time.Now().UTC(), succeeded, maxFailures).Scan(&active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, errors.New("webhook not found")
+		}
+		return false, err
+	}
+
+	return active, nil
+}
+
+// Delete removes a webhook
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM user_webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("webhook not found")
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]*models.UserWebhook, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]*models.UserWebhook, 0)
+	for rows.Next() {
+		var webhook models.UserWebhook
+		if err := scanWebhook(rows, &webhook); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// webhookEvents returns the events of a webhook, never nil, for storing as a JSON array
+func webhookEvents(webhook *models.UserWebhook) []models.WebhookEvent {
+	if webhook.Events == nil {
+		return []models.WebhookEvent{}
+	}
+	return webhook.Events
+}
+
+func scanWebhook(row pgx.Row, webhook *models.UserWebhook) error {
+	var events []byte
+	err := row.Scan(
+		&webhook.ID, &webhook.UserID, &webhook.URL, &events, &webhook.Secret, &webhook.Active, &webhook.FailureCount,
+		&webhook.LastStatusCode, &webhook.LastDeliveredAt, &webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	webhook.Events = []models.WebhookEvent{}
+	if len(events) > 0 {
+		if err := json.Unmarshal(events, &webhook.Events); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
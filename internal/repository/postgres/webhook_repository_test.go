@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	testing_helper "github.com/TakuyaAizawa/gox/internal/repository/postgres/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	db := testing_helper.NewTestDB(t)
+	defer db.Close()
+
+	// テスト開始時にすべてのテーブルをクリーンアップ
+	db.CleanupAllTables(t)
+
+	userRepo := NewUserRepository(db.Pool)
+	webhookRepo := NewWebhookRepository(db.Pool)
+
+	ctx := context.Background()
+
+	// Webhookを登録するユーザー
+	user := &models.User{
+		ID:        uuid.New(),
+		Username:  "webhookuser",
+		Email:     "webhookuser@example.com",
+		Password:  "hashedpassword",
+		Name:      "Webhook User",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	followHook := models.NewUserWebhook(user.ID, "https://example.com/follow", []models.WebhookEvent{models.WebhookEventFollow}, "secret1")
+	mentionHook := models.NewUserWebhook(user.ID, "https://example.com/mention", []models.WebhookEvent{models.WebhookEventMention}, "secret2")
+
+	// Create と GetByID のテスト
+	t.Run("CreateAndGet", func(t *testing.T) {
+		require.NoError(t, webhookRepo.Create(ctx, followHook))
+		require.NoError(t, webhookRepo.Create(ctx, mentionHook))
+
+		webhook, err := webhookRepo.GetByID(ctx, followHook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/follow", webhook.URL)
+		assert.Equal(t, []models.WebhookEvent{models.WebhookEventFollow}, webhook.Events)
+		assert.Equal(t, "secret1", webhook.Secret)
+		assert.True(t, webhook.Active)
+		assert.Nil(t, webhook.LastDeliveredAt)
+
+		_, err = webhookRepo.GetByID(ctx, uuid.New())
+		require.Error(t, err)
+		assert.Equal(t, "webhook not found", err.Error())
+
+		count, err := webhookRepo.CountByUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	// ListByUser と ListActiveByEvent のテスト
+	t.Run("List", func(t *testing.T) {
+		webhooks, err := webhookRepo.ListByUser(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+
+		webhooks, err = webhookRepo.ListActiveByEvent(ctx, user.ID, models.WebhookEventMention)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, mentionHook.ID, webhooks[0].ID)
+
+		// 無効なWebhookには送信しない
+		mentionHook.Active = false
+		require.NoError(t, webhookRepo.Update(ctx, mentionHook))
+
+		webhooks, err = webhookRepo.ListActiveByEvent(ctx, user.ID, models.WebhookEventMention)
+		require.NoError(t, err)
+		assert.Empty(t, webhooks)
+	})
+
+	// UpdateSecret のテスト
+	t.Run("UpdateSecret", func(t *testing.T) {
+		require.NoError(t, webhookRepo.UpdateSecret(ctx, followHook.ID, "rotated"))
+
+		webhook, err := webhookRepo.GetByID(ctx, followHook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", webhook.Secret)
+
+		err = webhookRepo.UpdateSecret(ctx, uuid.New(), "rotated")
+		require.Error(t, err)
+		assert.Equal(t, "webhook not found", err.Error())
+	})
+
+	// RecordDelivery のテスト
+	t.Run("RecordDelivery", func(t *testing.T) {
+		statusCode := 500
+		active, err := webhookRepo.RecordDelivery(ctx, followHook.ID, &statusCode, false, 2)
+		require.NoError(t, err)
+		assert.True(t, active)
+
+		// 成功すると失敗回数はリセットされる
+		statusCode = 204
+		active, err = webhookRepo.RecordDelivery(ctx, followHook.ID, &statusCode, true, 2)
+		require.NoError(t, err)
+		assert.True(t, active)
+
+		webhook, err := webhookRepo.GetByID(ctx, followHook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, webhook.FailureCount)
+		require.NotNil(t, webhook.LastStatusCode)
+		assert.Equal(t, 204, *webhook.LastStatusCode)
+		assert.NotNil(t, webhook.LastDeliveredAt)
+
+		// 連続して失敗すると無効になる
+		active, err = webhookRepo.RecordDelivery(ctx, followHook.ID, nil, false, 2)
+		require.NoError(t, err)
+		assert.True(t, active)
+		active, err = webhookRepo.RecordDelivery(ctx, followHook.ID, nil, false, 2)
+		require.NoError(t, err)
+		assert.False(t, active)
+
+		webhook, err = webhookRepo.GetByID(ctx, followHook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, webhook.FailureCount)
+		assert.Nil(t, webhook.LastStatusCode)
+	})
+
+	// Delete のテスト
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, webhookRepo.Delete(ctx, mentionHook.ID))
+
+		err := webhookRepo.Delete(ctx, mentionHook.ID)
+		require.Error(t, err)
+		assert.Equal(t, "webhook not found", err.Error())
+
+		webhooks, err := webhookRepo.ListByUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Len(t, webhooks, 1)
+	})
+}
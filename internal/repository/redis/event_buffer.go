@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type eventBuffer struct {
+	client    *goredis.Client
+	ttl       time.Duration
+	maxEvents int
+}
+
+// NewEventBuffer creates a Redis implementation of websocket.EventBuffer shared by all API servers
+func NewEventBuffer(client *goredis.Client, ttl time.Duration, maxEvents int) websocket.EventBuffer {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	if maxEvents <= 0 {
+		maxEvents = 100
+	}
+	return &eventBuffer{client: client, ttl: ttl, maxEvents: maxEvents}
+}
+
+const (
+	// 通知の連番のキーの接頭辞（後ろにユーザーIDが付く。再接続したクライアントの連番と食い違わないよう期限を設けない）
+	eventSeqKeyPrefix = "ws:seq:"
+	// 保存中の通知のキーの接頭辞（値は連番をスコアとするソート済みセットで、最後の通知からttl後に削除される）
+	eventBufferKeyPrefix = "ws:events:"
+	// 受信を確認した連番のキーの接頭辞
+	eventAckKeyPrefix = "ws:ack:"
+)
+
+// 連番を付けて通知を保存し、件数の上限を超えた古い通知を削除するスクリプト
+// （同じ内容の通知も区別できるよう、メンバーは「連番:メッセージ」とする）
+var appendEventScript = goredis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+redis.call('ZADD', KEYS[2], seq, seq .. ':' .. ARGV[1])
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -(tonumber(ARGV[3]) + 1))
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return seq
+`)
+
+// 記録済みの連番より大きい場合にのみ、受信を確認した連番を記録するスクリプト
+var ackEventScript = goredis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tonumber(ARGV[1]) > current then
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 0
+`)
+
+func (b *eventBuffer) Append(ctx context.Context, userID uuid.UUID, payload []byte) (int64, error) {
+	keys := []string{eventSeqKeyPrefix + userID.String(), eventBufferKeyPrefix + userID.String()}
+	return appendEventScript.Run(ctx, b.client, keys, payload, b.ttl.Milliseconds(), b.maxEvents).Int64()
+}
+
+func (b *eventBuffer) Since(ctx context.Context, userID uuid.UUID, afterSeq int64) ([]websocket.BufferedEvent, int64, error) {
+	var seqCmd *goredis.StringCmd
+	var eventsCmd *goredis.ZSliceCmd
+	_, err := b.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		seqCmd = pipe.Get(ctx, eventSeqKeyPrefix+userID.String())
+		eventsCmd = pipe.ZRangeByScoreWithScores(ctx, eventBufferKeyPrefix+userID.String(), &goredis.ZRangeBy{
+			Min: "(" + strconv.FormatInt(afterSeq, 10),
+			Max: "+inf",
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, 0, err
+	}
+
+	latestSeq, err := seqCmd.Int64()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, 0, err
+	}
+
+	var events []websocket.BufferedEvent
+	for _, z := range eventsCmd.Val() {
+		member, _ := z.Member.(string)
+		_, payload, ok := strings.Cut(member, ":")
+		if !ok {
+			continue
+		}
+		events = append(events, websocket.BufferedEvent{Seq: int64(z.Score), Payload: []byte(payload)})
+	}
+	return events, latestSeq, nil
+}
+
+func (b *eventBuffer) Ack(ctx context.Context, userID uuid.UUID, seq int64) error {
+	return ackEventScript.Run(ctx, b.client, []string{eventAckKeyPrefix + userID.String()}, seq, b.ttl.Milliseconds()).Err()
+}
+
+func (b *eventBuffer) Acked(ctx context.Context, userID uuid.UUID) (int64, error) {
+	seq, err := b.client.Get(ctx, eventAckKeyPrefix+userID.String()).Int64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	return seq, err
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type hubBroker struct {
+	client  *goredis.Client
+	channel string
+}
+
+// NewHubBroker creates a Redis pub/sub implementation of websocket.Broker
+func NewHubBroker(client *goredis.Client, channel string) websocket.Broker {
+	return &hubBroker{client: client, channel: channel}
+}
+
+func (b *hubBroker) Publish(ctx context.Context, message []byte) error {
+	return b.client.Publish(ctx, b.channel, message).Err()
+}
+
+func (b *hubBroker) Subscribe(ctx context.Context, handler func(message []byte)) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+
+	// 購読が完了するまで待つ（接続できない場合はエラーを返す）
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	for {
+		message, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		handler([]byte(message.Payload))
+	}
+}
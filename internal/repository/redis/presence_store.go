@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type presenceStore struct {
+	client *goredis.Client
+}
+
+// NewPresenceStore creates a new Redis implementation of PresenceStore
+func NewPresenceStore(client *goredis.Client) interfaces.PresenceStore {
+	return &presenceStore{client: client}
+}
+
+// オンライン状態のキーの接頭辞（後ろにユーザーIDが付き、値はインスタンスIDを有効期限をスコアとするソート済みセット）
+// 停止したインスタンスの記録は有効期限を過ぎると無視され、キー自体もttl後に削除される
+const presenceKeyPrefix = "presence:"
+
+func (s *presenceStore) Refresh(ctx context.Context, instanceID uuid.UUID, userIDs []uuid.UUID, ttl time.Duration) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	expiresAt := float64(time.Now().Add(ttl).UnixMilli())
+	pipe := s.client.Pipeline()
+	for _, userID := range userIDs {
+		key := presenceKeyPrefix + userID.String()
+		pipe.ZAdd(ctx, key, goredis.Z{Score: expiresAt, Member: instanceID.String()})
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *presenceStore) Remove(ctx context.Context, instanceID uuid.UUID, userID uuid.UUID) error {
+	return s.client.ZRem(ctx, presenceKeyPrefix+userID.String(), instanceID.String()).Err()
+}
+
+func (s *presenceStore) IsOnline(ctx context.Context, userID uuid.UUID) (bool, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	count, err := s.client.ZCount(ctx, presenceKeyPrefix+userID.String(), "("+now, "+inf").Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type rateLimiter struct {
+	client *goredis.Client
+}
+
+// NewRateLimiter creates a new Redis implementation of RateLimiter
+func NewRateLimiter(client *goredis.Client) interfaces.RateLimiter {
+	return &rateLimiter{client: client}
+}
+
+// レート制限のキーの接頭辞（後ろにクライアントのキーが付き、値はリクエスト時刻をスコアとするソート済みセット）
+const rateLimitKeyPrefix = "ratelimit:"
+
+// スライディングウィンドウでリクエストを数えるスクリプト
+// （時刻はRedisサーバーの時計を使い、APIサーバー間の時計のずれの影響を受けないようにする）
+var slidingWindowScript = goredis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = now + window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window
+end
+return {allowed, limit - count, reset}
+`)
+
+func (l *rateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, int, time.Time, error) {
+	// 同じミリ秒のリクエストを区別するため、メンバーには一意な値を使う
+	values, err := slidingWindowScript.Run(ctx, l.client, []string{rateLimitKeyPrefix + key},
+		window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	if len(values) != 3 {
+		return false, 0, time.Time{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return values[0] == 1, int(values[1]), time.UnixMilli(values[2]), nil
+}
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type threadCache struct {
+	client *goredis.Client
+	// スレッドのキャッシュの有効期間（期限切れ後はデータベースから作り直す）
+	ttl time.Duration
+}
+
+// NewThreadCache creates a new Redis implementation of ThreadCache
+func NewThreadCache(client *goredis.Client, ttl time.Duration) interfaces.ThreadCache {
+	return &threadCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// スレッドのキーの接頭辞（後ろに投稿IDが付き、値はスレッドの投稿IDのカンマ区切り）
+const threadKeyPrefix = "thread:unroll:"
+
+func threadKey(postID uuid.UUID) string {
+	return threadKeyPrefix + postID.String()
+}
+
+func (c *threadCache) Get(ctx context.Context, postID uuid.UUID) ([]uuid.UUID, error) {
+	value, err := c.client.Get(ctx, threadKey(postID)).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseThreadIDs(value), nil
+}
+
+func (c *threadCache) Set(ctx context.Context, postIDs []uuid.UUID) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		values[i] = id.String()
+	}
+	value := strings.Join(values, ",")
+
+	_, err := c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, id := range postIDs {
+			pipe.Set(ctx, threadKey(id), value, c.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (c *threadCache) Invalidate(ctx context.Context, postID uuid.UUID) error {
+	postIDs, err := c.Get(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{threadKey(postID)}
+	for _, id := range postIDs {
+		keys = append(keys, threadKey(id))
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// parseThreadIDs カンマ区切りの投稿IDを解析する（不正な値は無視する）
+func parseThreadIDs(value string) []uuid.UUID {
+	parts := strings.Split(value, ",")
+	postIDs := make([]uuid.UUID, 0, len(parts))
+	for _, part := range parts {
+		id, err := uuid.Parse(part)
+		if err != nil {
+			continue
+		}
+		postIDs = append(postIDs, id)
+	}
+	return postIDs
+}
@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// 1回のパイプラインで書き込むユーザー数
+const timelinePushBatchSize = 500
+
+type timelineCache struct {
+	client *goredis.Client
+	// タイムラインごとに保持する投稿IDの最大件数
+	maxLength int
+	// タイムラインのキャッシュの有効期間（期限切れ後はデータベースから作り直す）
+	ttl time.Duration
+}
+
+// NewTimelineCache creates a new Redis implementation of TimelineCache
+func NewTimelineCache(client *goredis.Client, maxLength int, ttl time.Duration) interfaces.TimelineCache {
+	return &timelineCache{
+		client:    client,
+		maxLength: maxLength,
+		ttl:       ttl,
+	}
+}
+
+// ホームタイムラインのキーの接頭辞（後ろにユーザーIDが付く）
+const timelineKeyPrefix = "timeline:home:"
+
+func timelineKey(userID uuid.UUID) string {
+	return timelineKeyPrefix + userID.String()
+}
+
+func (c *timelineCache) Exists(ctx context.Context, userID uuid.UUID) (bool, error) {
+	n, err := c.client.Exists(ctx, timelineKey(userID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (c *timelineCache) Replace(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) error {
+	key := timelineKey(userID)
+	if len(postIDs) > c.maxLength {
+		postIDs = postIDs[:c.maxLength]
+	}
+
+	// 作り直している間に読み取られても不完全な一覧が見えないようトランザクションで置き換える
+	_, err := c.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(postIDs) == 0 {
+			return nil
+		}
+		values := make([]interface{}, len(postIDs))
+		for i, id := range postIDs {
+			values[i] = id.String()
+		}
+		pipe.RPush(ctx, key, values...)
+		pipe.Expire(ctx, key, c.ttl)
+		return nil
+	})
+	return err
+}
+
+func (c *timelineCache) Push(ctx context.Context, userIDs []uuid.UUID, postID uuid.UUID) error {
+	for start := 0; start < len(userIDs); start += timelinePushBatchSize {
+		end := min(start+timelinePushBatchSize, len(userIDs))
+
+		_, err := c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for _, userID := range userIDs[start:end] {
+				key := timelineKey(userID)
+				// LPUSHXはキーが存在する場合のみ追加するため、キャッシュされていないタイムラインは作られない
+				pipe.LPushX(ctx, key, postID.String())
+				pipe.LTrim(ctx, key, 0, int64(c.maxLength-1))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *timelineCache) PushAll(ctx context.Context, postID uuid.UUID) error {
+	// KEYSはRedisを長時間ブロックするため、SCANで少しずつ取得して追加する
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, timelineKeyPrefix+"*", timelinePushBatchSize).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			_, err = c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+				for _, key := range keys {
+					pipe.LPushX(ctx, key, postID.String())
+					pipe.LTrim(ctx, key, 0, int64(c.maxLength-1))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (c *timelineCache) Range(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, int64, error) {
+	key := timelineKey(userID)
+
+	var rangeCmd *goredis.StringSliceCmd
+	var lenCmd *goredis.IntCmd
+	_, err := c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		rangeCmd = pipe.LRange(ctx, key, int64(offset), int64(offset+limit-1))
+		lenCmd = pipe.LLen(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	values := rangeCmd.Val()
+	postIDs := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			continue
+		}
+		postIDs = append(postIDs, id)
+	}
+
+	return postIDs, lenCmd.Val(), nil
+}
+
+func (c *timelineCache) Invalidate(ctx context.Context, userID uuid.UUID) error {
+	return c.client.Del(ctx, timelineKey(userID)).Err()
+}
@@ -0,0 +1,341 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/google/uuid"
+)
+
+// 検索エンジンへの1回のリクエストにかける最大時間
+const elasticsearchTimeout = 10 * time.Second
+
+// 投稿の索引の設定（本文は標準のアナライザーで、ハッシュタグは完全一致で検索する）
+var postIndexMapping = map[string]any{
+	"mappings": map[string]any{
+		"properties": map[string]any{
+			"content":    map[string]any{"type": "text"},
+			"hashtags":   map[string]any{"type": "keyword"},
+			"user_id":    map[string]any{"type": "keyword"},
+			"language":   map[string]any{"type": "keyword"},
+			"created_at": map[string]any{"type": "date"},
+		},
+	},
+}
+
+// ユーザーの索引の設定（ユーザー名と名前は入力途中でも一致するよう search_as_you_type で登録する）
+var userIndexMapping = map[string]any{
+	"mappings": map[string]any{
+		"properties": map[string]any{
+			"username":   map[string]any{"type": "search_as_you_type"},
+			"name":       map[string]any{"type": "search_as_you_type"},
+			"bio":        map[string]any{"type": "text"},
+			"created_at": map[string]any{"type": "date"},
+		},
+	},
+}
+
+// ElasticsearchIndexer はElasticsearch（またはREST APIに互換性のあるOpenSearch）のREST APIで投稿・ユーザーを索引に登録して検索する検索エンジンです
+// 投稿とユーザーはそれぞれ「接頭辞+posts」「接頭辞+users」の索引に登録します
+type ElasticsearchIndexer struct {
+	baseURL   string
+	username  string
+	password  string
+	postIndex string
+	userIndex string
+	client    *http.Client
+}
+
+// NewElasticsearchIndexer は新しいElasticsearchIndexerインスタンスを作成します
+// usernameが空の場合は認証せずに接続します
+func NewElasticsearchIndexer(baseURL, username, password, indexPrefix string) interfaces.SearchIndexer {
+	return &ElasticsearchIndexer{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		username:  username,
+		password:  password,
+		postIndex: indexPrefix + "posts",
+		userIndex: indexPrefix + "users",
+		client:    &http.Client{Timeout: elasticsearchTimeout},
+	}
+}
+
+// Name は検索エンジンの名前を返します
+func (s *ElasticsearchIndexer) Name() string {
+	return "elasticsearch"
+}
+
+// Setup は投稿・ユーザーの索引がない場合に作成します
+func (s *ElasticsearchIndexer) Setup(ctx context.Context) error {
+	indices := []struct {
+		name    string
+		mapping map[string]any
+	}{
+		{s.postIndex, postIndexMapping},
+		{s.userIndex, userIndexMapping},
+	}
+	for _, index := range indices {
+		status, err := s.do(ctx, http.MethodHead, "/"+index.name, nil, nil)
+		if err != nil {
+			return err
+		}
+		if status == http.StatusOK {
+			continue
+		}
+
+		body, err := json.Marshal(index.mapping)
+		if err != nil {
+			return err
+		}
+		status, err = s.do(ctx, http.MethodPut, "/"+index.name, body, nil)
+		if err != nil {
+			return err
+		}
+		if err := checkStatus(status, "索引"+index.name+"の作成"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexPosts は投稿をBulk APIでまとめて索引に登録します
+func (s *ElasticsearchIndexer) IndexPosts(ctx context.Context, posts []*models.Post) error {
+	docs := make(map[string]any, len(posts))
+	ids := make([]string, 0, len(posts))
+	for _, post := range posts {
+		hashtags := post.Hashtags()
+		if hashtags == nil {
+			hashtags = []string{}
+		}
+		id := post.ID.String()
+		ids = append(ids, id)
+		docs[id] = map[string]any{
+			"content":    post.Content,
+			"hashtags":   hashtags,
+			"user_id":    post.UserID,
+			"language":   post.Language,
+			"created_at": post.CreatedAt,
+		}
+	}
+	return s.bulkIndex(ctx, s.postIndex, ids, docs)
+}
+
+// IndexUsers はユーザーをBulk APIでまとめて索引に登録します
+func (s *ElasticsearchIndexer) IndexUsers(ctx context.Context, users []*models.User) error {
+	docs := make(map[string]any, len(users))
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		id := user.ID.String()
+		ids = append(ids, id)
+		docs[id] = map[string]any{
+			"username":   user.Username,
+			"name":       user.Name,
+			"bio":        user.Bio,
+			"created_at": user.CreatedAt,
+		}
+	}
+	return s.bulkIndex(ctx, s.userIndex, ids, docs)
+}
+
+// DeletePost は投稿を索引から削除します
+func (s *ElasticsearchIndexer) DeletePost(ctx context.Context, id uuid.UUID) error {
+	return s.deleteDoc(ctx, s.postIndex, id)
+}
+
+// DeleteUser はユーザーを索引から削除します
+func (s *ElasticsearchIndexer) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return s.deleteDoc(ctx, s.userIndex, id)
+}
+
+// SearchPosts は本文がすべての語を含む投稿を関連度の高い順（同じ場合は新しい順）に検索します
+// 「#」で始まる語はハッシュタグとして完全一致で検索します
+func (s *ElasticsearchIndexer) SearchPosts(ctx context.Context, query string, offset, limit int) ([]uuid.UUID, error) {
+	var must []any
+	var words []string
+	for _, word := range strings.Fields(query) {
+		if tag, ok := models.NormalizeHashtag(word); ok && strings.HasPrefix(word, "#") {
+			must = append(must, map[string]any{"term": map[string]any{"hashtags": tag}})
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(words) > 0 {
+		must = append(must, map[string]any{
+			"match": map[string]any{"content": map[string]any{"query": strings.Join(words, " "), "operator": "and"}},
+		})
+	}
+
+	return s.search(ctx, s.postIndex, map[string]any{
+		"from":    offset,
+		"size":    limit,
+		"_source": false,
+		"query":   map[string]any{"bool": map[string]any{"must": must}},
+		"sort":    []any{"_score", map[string]any{"created_at": "desc"}},
+	})
+}
+
+// SearchUsers はユーザー名・名前（入力途中の語を含む）・自己紹介が検索語に一致するユーザーを関連度の高い順に検索します
+func (s *ElasticsearchIndexer) SearchUsers(ctx context.Context, query string, offset, limit int) ([]uuid.UUID, error) {
+	return s.search(ctx, s.userIndex, map[string]any{
+		"from":    offset,
+		"size":    limit,
+		"_source": false,
+		"query": map[string]any{
+			"multi_match": map[string]any{
+				"query": strings.TrimPrefix(query, "@"),
+				"type":  "bool_prefix",
+				"fields": []string{
+					"username^3", "username._2gram", "username._3gram",
+					"name^2", "name._2gram", "name._3gram",
+					"bio",
+				},
+			},
+		},
+		"sort": []any{"_score", map[string]any{"created_at": "desc"}},
+	})
+}
+
+// Close はアイドル状態の接続を閉じます
+func (s *ElasticsearchIndexer) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// bulkIndex はidsの順にドキュメントをBulk APIで登録し、1件でも失敗した場合はエラーを返します
+func (s *ElasticsearchIndexer) bulkIndex(ctx context.Context, index string, ids []string, docs map[string]any) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, id := range ids {
+		action := map[string]any{"index": map[string]any{"_index": index, "_id": id}}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(docs[id]); err != nil {
+			return err
+		}
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID    string          `json:"_id"`
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	status, err := s.do(ctx, http.MethodPost, "/_bulk", body.Bytes(), &result)
+	if err != nil {
+		return err
+	}
+	if err := checkStatus(status, "索引への登録"); err != nil {
+		return err
+	}
+	if result.Errors {
+		for _, item := range result.Items {
+			for _, op := range item {
+				if len(op.Error) > 0 {
+					return fmt.Errorf("索引%sへの登録に失敗しました（ID: %s）: %s", index, op.ID, op.Error)
+				}
+			}
+		}
+		return fmt.Errorf("索引%sへの登録に失敗しました", index)
+	}
+	return nil
+}
+
+func (s *ElasticsearchIndexer) deleteDoc(ctx context.Context, index string, id uuid.UUID) error {
+	status, err := s.do(ctx, http.MethodDelete, "/"+index+"/_doc/"+id.String(), nil, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return checkStatus(status, "索引からの削除")
+}
+
+func (s *ElasticsearchIndexer) search(ctx context.Context, index string, query map[string]any) ([]uuid.UUID, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	status, err := s.do(ctx, http.MethodPost, "/"+index+"/_search", body, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(status, "検索"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		id, err := uuid.Parse(hit.ID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// do はリクエストを送信してステータスコードを返します
+// outがnilでない場合、2xxの応答の本文をJSONとしてoutに読み込みます
+func (s *ElasticsearchIndexer) do(ctx context.Context, method, path string, body []byte, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		if path == "/_bulk" {
+			req.Header.Set("Content-Type", "application/x-ndjson")
+		} else {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+func checkStatus(status int, operation string) error {
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("%sで検索エンジンがステータス%dを返しました", operation, status)
+	}
+	return nil
+}
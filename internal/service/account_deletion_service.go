@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// 削除の手順1つにかける最大時間
+	accountDeletionStepTimeout = 5 * time.Minute
+	// この時間より長く進捗の更新がない実行中の手続きは、停止したワーカーのものとして再取得する
+	accountDeletionStaleAfter = 15 * time.Minute
+	// 失敗した手続きを再試行するまでの最大間隔
+	accountDeletionMaxBackoff = time.Hour
+)
+
+// AccountDeletionService アカウントの削除を受け付け、バックグラウンドで関連データを順に削除するサービス
+// 手順ごとに進捗を保存するため、失敗や再起動の後は途中の手順から再開する
+type AccountDeletionService struct {
+	deletionRepo    interfaces.AccountDeletionRepository
+	userRepo        interfaces.UserRepository
+	txManager       interfaces.TxManager
+	storageProvider coreinterfaces.StorageProvider
+	media           *MediaService
+	hub             *websocket.Hub
+	pollInterval    time.Duration
+	maxAttempts     int
+	log             logger.Logger
+
+	triggerCh chan struct{}
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+// NewAccountDeletionService 新しいアカウント削除サービスを作成する
+func NewAccountDeletionService(
+	deletionRepo interfaces.AccountDeletionRepository,
+	userRepo interfaces.UserRepository,
+	txManager interfaces.TxManager,
+	storageProvider coreinterfaces.StorageProvider,
+	media *MediaService,
+	hub *websocket.Hub,
+	pollInterval time.Duration,
+	maxAttempts int,
+	log logger.Logger,
+) *AccountDeletionService {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	return &AccountDeletionService{
+		deletionRepo:    deletionRepo,
+		userRepo:        userRepo,
+		txManager:       txManager,
+		storageProvider: storageProvider,
+		media:           media,
+		hub:             hub,
+		pollInterval:    pollInterval,
+		maxAttempts:     maxAttempts,
+		log:             log,
+		triggerCh:       make(chan struct{}, 1),
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+	}
+}
+
+// Start 削除待ちのアカウントの処理を開始する
+func (s *AccountDeletionService) Start() {
+	go s.run()
+}
+
+// Stop 削除待ちのアカウントの処理を停止する（実行中の手続きは現在の手順を終えてから中断する）
+func (s *AccountDeletionService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// Schedule アカウントの削除を受け付ける
+// アカウントはすぐにすべてのエンドポイントから隠され、関連データはバックグラウンドで削除される
+func (s *AccountDeletionService) Schedule(ctx context.Context, userID uuid.UUID) (*models.AccountDeletion, error) {
+	deletion := models.NewAccountDeletion(userID)
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.MarkForDeletion(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.deletionRepo.Create(ctx, deletion); err != nil {
+			return err
+		}
+
+		s.txManager.AfterCommit(ctx, s.trigger)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("アカウントの削除を受け付けました", "user_id", userID)
+	return deletion, nil
+}
+
+// GetStatus アカウントの削除手続きの進捗を取得する
+func (s *AccountDeletionService) GetStatus(ctx context.Context, userID uuid.UUID) (*models.AccountDeletion, error) {
+	return s.deletionRepo.GetByUserID(ctx, userID)
+}
+
+// trigger 次の確認を待たずに削除待ちのアカウントを処理する
+func (s *AccountDeletionService) trigger() {
+	select {
+	case s.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+// run 停止されるまで一定間隔で削除待ちのアカウントを処理する
+func (s *AccountDeletionService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.processDue()
+	for {
+		select {
+		case <-ticker.C:
+			s.processDue()
+		case <-s.triggerCh:
+			s.processDue()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// processDue 実行予定時刻を過ぎた削除手続きがなくなるまで処理する
+func (s *AccountDeletionService) processDue() {
+	for !s.stopping() {
+		ctx, cancel := context.WithTimeout(context.Background(), accountDeletionStepTimeout)
+		deletion, err := s.deletionRepo.ClaimNext(ctx, accountDeletionStaleAfter)
+		cancel()
+		if err != nil {
+			s.log.Error("削除待ちのアカウントの取得に失敗しました", "error", err)
+			return
+		}
+		if deletion == nil {
+			return
+		}
+
+		s.process(deletion)
+	}
+}
+
+// process 削除手続きの残りの手順を順に実行する
+func (s *AccountDeletionService) process(deletion *models.AccountDeletion) {
+	for deletion.Step != models.AccountDeletionStepDone {
+		// 停止する場合は次回の起動時にこの手順から再開する
+		if s.stopping() {
+			deletion.Status = models.AccountDeletionPending
+			deletion.NextAttemptAt = time.Now().UTC()
+			s.saveProgress(deletion)
+			return
+		}
+
+		if err := s.runStep(deletion); err != nil {
+			s.fail(deletion, err)
+			return
+		}
+
+		deletion.Step = deletion.NextStep()
+		if !s.saveProgress(deletion) {
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	deletion.Status = models.AccountDeletionCompleted
+	deletion.LastError = nil
+	deletion.CompletedAt = &now
+	if s.saveProgress(deletion) {
+		s.log.Info("アカウントを削除しました", "user_id", deletion.UserID, "attempts", deletion.Attempts)
+	}
+}
+
+// runStep 削除の手順を1つ実行する（どの手順も途中で失敗した後に再実行できる）
+func (s *AccountDeletionService) runStep(deletion *models.AccountDeletion) error {
+	ctx, cancel := context.WithTimeout(context.Background(), accountDeletionStepTimeout)
+	defer cancel()
+
+	var deleted int64
+	var err error
+	switch deletion.Step {
+	case models.AccountDeletionStepDisconnect:
+		s.hub.DisconnectUser(deletion.UserID)
+	case models.AccountDeletionStepMedia:
+		err = s.deleteMedia(ctx, deletion)
+	case models.AccountDeletionStepLikes:
+		deleted, err = s.deletionRepo.DeleteLikes(ctx, deletion.UserID)
+	case models.AccountDeletionStepFollows:
+		deleted, err = s.deletionRepo.DeleteFollows(ctx, deletion.UserID)
+	case models.AccountDeletionStepNotifications:
+		deleted, err = s.deletionRepo.DeleteNotifications(ctx, deletion.UserID)
+	case models.AccountDeletionStepPosts:
+		deleted, err = s.deletionRepo.DeletePosts(ctx, deletion.UserID)
+	case models.AccountDeletionStepAccount:
+		err = s.deletionRepo.DeleteUser(ctx, deletion.UserID)
+		// 前回の実行で削除済みの場合
+		if err != nil && err.Error() == "user not found" {
+			err = nil
+		}
+	default:
+		return fmt.Errorf("unknown account deletion step: %s", deletion.Step)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.log.Debug("アカウント削除の手順を実行しました", "user_id", deletion.UserID, "step", deletion.Step, "deleted", deleted)
+	return nil
+}
+
+// deleteMedia ユーザーがアップロードしたメディアへの参照を外す（外部のURLはそのまま。他のユーザーと共有するファイルは残る）
+// 投稿から参照されているファイルは、後の手順で投稿を削除した後に参照されていないメディアの掃除で削除される
+// 同じ参照を二重に外さないよう1件ごとに進捗を保存し、再実行した場合は続きから処理する
+func (s *AccountDeletionService) deleteMedia(ctx context.Context, deletion *models.AccountDeletion) error {
+	urls, err := s.deletionRepo.ListMediaURLs(ctx, deletion.UserID)
+	if err != nil {
+		return err
+	}
+
+	stored := make([]string, 0, len(urls))
+	for _, mediaURL := range urls {
+		if _, ok := s.storageProvider.PathFromURL(mediaURL); ok {
+			stored = append(stored, mediaURL)
+		}
+	}
+
+	if deletion.MediaTotal != len(stored) || deletion.MediaDeleted > len(stored) {
+		deletion.MediaTotal = len(stored)
+		deletion.MediaDeleted = 0
+	}
+	for _, mediaURL := range stored[deletion.MediaDeleted:] {
+		if err := s.media.ReleaseFor(ctx, deletion.UserID, mediaURL, uuid.Nil); err != nil {
+			return err
+		}
+		deletion.MediaDeleted++
+
+		if !s.saveProgress(deletion) {
+			return fmt.Errorf("failed to save account deletion progress")
+		}
+	}
+
+	return nil
+}
+
+// fail 失敗した手続きを再試行まで待機させる（最大回数に達した場合は失敗として終了する）
+func (s *AccountDeletionService) fail(deletion *models.AccountDeletion, cause error) {
+	message := cause.Error()
+	deletion.LastError = &message
+
+	if deletion.Attempts >= s.maxAttempts {
+		deletion.Status = models.AccountDeletionFailed
+		s.log.Error("アカウントの削除に失敗しました",
+			"user_id", deletion.UserID, "step", deletion.Step, "attempts", deletion.Attempts, "error", cause)
+	} else {
+		backoff := time.Minute << (deletion.Attempts - 1)
+		if backoff <= 0 || backoff > accountDeletionMaxBackoff {
+			backoff = accountDeletionMaxBackoff
+		}
+		deletion.Status = models.AccountDeletionPending
+		deletion.NextAttemptAt = time.Now().UTC().Add(backoff)
+		s.log.Warn("アカウントの削除に失敗したため再試行します",
+			"user_id", deletion.UserID, "step", deletion.Step, "attempts", deletion.Attempts, "retry_in", backoff, "error", cause)
+	}
+
+	s.saveProgress(deletion)
+}
+
+// saveProgress 削除手続きの進捗を保存する（失敗した場合は実行中のまま残り、一定時間後に再取得される）
+func (s *AccountDeletionService) saveProgress(deletion *models.AccountDeletion) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), accountDeletionStepTimeout)
+	defer cancel()
+
+	if err := s.deletionRepo.UpdateProgress(ctx, deletion); err != nil {
+		s.log.Error("アカウント削除の進捗の保存に失敗しました", "user_id", deletion.UserID, "step", deletion.Step, "error", err)
+		return false
+	}
+	return true
+}
+
+func (s *AccountDeletionService) stopping() bool {
+	select {
+	case <-s.stopCh:
+		return true
+	default:
+		return false
+	}
+}
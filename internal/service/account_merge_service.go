@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ErrAccountMergeSelf はアカウントを自身に統合しようとしたことを表す
+var ErrAccountMergeSelf = errors.New("cannot merge an account into itself")
+
+// ErrAccountMergeSystem はシステムアカウントを統合しようとしたことを表す
+var ErrAccountMergeSystem = errors.New("cannot merge a system account")
+
+const (
+	// 統合の手順1つにかける最大時間
+	accountMergeStepTimeout = 5 * time.Minute
+	// この時間より長く進捗の更新がない実行中の手続きは、停止したワーカーのものとして再取得する
+	accountMergeStaleAfter = 15 * time.Minute
+	// 失敗した手続きを再試行するまでの最大間隔
+	accountMergeMaxBackoff = time.Hour
+)
+
+// AccountMergeService 重複して作成されたアカウントの統合を受け付け、バックグラウンドで統合先へデータを移すサービス
+// 手順ごとに進捗を保存するため、失敗や再起動の後は途中の手順から再開する
+type AccountMergeService struct {
+	mergeRepo    interfaces.AccountMergeRepository
+	userRepo     interfaces.UserRepository
+	txManager    interfaces.TxManager
+	hub          *websocket.Hub
+	pollInterval time.Duration
+	maxAttempts  int
+	log          logger.Logger
+
+	triggerCh chan struct{}
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+// NewAccountMergeService 新しいアカウント統合サービスを作成する
+func NewAccountMergeService(
+	mergeRepo interfaces.AccountMergeRepository,
+	userRepo interfaces.UserRepository,
+	txManager interfaces.TxManager,
+	hub *websocket.Hub,
+	pollInterval time.Duration,
+	maxAttempts int,
+	log logger.Logger,
+) *AccountMergeService {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	return &AccountMergeService{
+		mergeRepo:    mergeRepo,
+		userRepo:     userRepo,
+		txManager:    txManager,
+		hub:          hub,
+		pollInterval: pollInterval,
+		maxAttempts:  maxAttempts,
+		log:          log,
+		triggerCh:    make(chan struct{}, 1),
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// Start 統合待ちの手続きの処理を開始する
+func (s *AccountMergeService) Start() {
+	go s.run()
+}
+
+// Stop 統合待ちの手続きの処理を停止する（実行中の手続きは現在の手順を終えてから中断する）
+func (s *AccountMergeService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// Preview 統合した場合に移す件数と重複のため削除する件数を返す（ドライラン、データは変更しない）
+func (s *AccountMergeService) Preview(ctx context.Context, sourceID, targetID uuid.UUID) (*models.AccountMergeReport, error) {
+	source, _, err := s.loadAccounts(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.mergeRepo.Preview(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	report.SourceUsername = source.Username
+	return report, nil
+}
+
+// Schedule アカウントの統合を受け付ける
+// 統合元のアカウントはすぐにすべてのエンドポイントから隠され、データはバックグラウンドで統合先へ移される
+func (s *AccountMergeService) Schedule(ctx context.Context, sourceID, targetID, requestedBy uuid.UUID) (*models.AccountMerge, error) {
+	source, _, err := s.loadAccounts(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	merge := models.NewAccountMerge(source, targetID, requestedBy)
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.MarkForMerge(ctx, sourceID); err != nil {
+			return err
+		}
+		if err := s.mergeRepo.Create(ctx, merge); err != nil {
+			return err
+		}
+
+		s.txManager.AfterCommit(ctx, s.trigger)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("アカウントの統合を受け付けました", "source_user_id", sourceID, "target_user_id", targetID, "merge_id", merge.ID)
+	return merge, nil
+}
+
+// GetStatus アカウントの統合手続きの進捗を取得する
+func (s *AccountMergeService) GetStatus(ctx context.Context, id uuid.UUID) (*models.AccountMerge, error) {
+	return s.mergeRepo.GetByID(ctx, id)
+}
+
+// loadAccounts 統合元と統合先のアカウントを取得し、統合できるか確認する（どちらも有効なアカウントである必要がある）
+func (s *AccountMergeService) loadAccounts(ctx context.Context, sourceID, targetID uuid.UUID) (*models.User, *models.User, error) {
+	if sourceID == targetID {
+		return nil, nil, ErrAccountMergeSelf
+	}
+
+	source, err := s.userRepo.GetByID(ctx, sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := s.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.IsSystem || target.IsSystem {
+		return nil, nil, ErrAccountMergeSystem
+	}
+
+	return source, target, nil
+}
+
+// trigger 次の確認を待たずに統合待ちの手続きを処理する
+func (s *AccountMergeService) trigger() {
+	select {
+	case s.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+// run 停止されるまで一定間隔で統合待ちの手続きを処理する
+func (s *AccountMergeService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.processDue()
+	for {
+		select {
+		case <-ticker.C:
+			s.processDue()
+		case <-s.triggerCh:
+			s.processDue()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// processDue 実行予定時刻を過ぎた統合手続きがなくなるまで処理する
+func (s *AccountMergeService) processDue() {
+	for !s.stopping() {
+		ctx, cancel := context.WithTimeout(context.Background(), accountMergeStepTimeout)
+		merge, err := s.mergeRepo.ClaimNext(ctx, accountMergeStaleAfter)
+		cancel()
+		if err != nil {
+			s.log.Error("統合待ちの手続きの取得に失敗しました", "error", err)
+			return
+		}
+		if merge == nil {
+			return
+		}
+
+		s.process(merge)
+	}
+}
+
+// process 統合手続きの残りの手順を順に実行する
+func (s *AccountMergeService) process(merge *models.AccountMerge) {
+	for merge.Step != models.AccountMergeStepDone {
+		// 停止する場合は次回の起動時にこの手順から再開する
+		if s.stopping() {
+			merge.Status = models.AccountMergePending
+			merge.NextAttemptAt = time.Now().UTC()
+			s.saveProgress(merge)
+			return
+		}
+
+		if err := s.runStep(merge); err != nil {
+			s.fail(merge, err)
+			return
+		}
+
+		merge.Step = merge.NextStep()
+		if !s.saveProgress(merge) {
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	merge.Status = models.AccountMergeCompleted
+	merge.LastError = nil
+	merge.CompletedAt = &now
+	if s.saveProgress(merge) {
+		s.log.Info("アカウントを統合しました",
+			"source_user_id", merge.SourceUserID, "target_user_id", merge.TargetUserID, "attempts", merge.Attempts)
+	}
+}
+
+// runStep 統合の手順を1つ実行する（どの手順も途中で失敗した後に再実行できる）
+func (s *AccountMergeService) runStep(merge *models.AccountMerge) error {
+	ctx, cancel := context.WithTimeout(context.Background(), accountMergeStepTimeout)
+	defer cancel()
+
+	var moved, dropped int64
+	var err error
+	switch merge.Step {
+	case models.AccountMergeStepDisconnect:
+		s.hub.DisconnectUser(merge.SourceUserID)
+	case models.AccountMergeStepPosts:
+		moved, err = s.mergeRepo.MovePosts(ctx, merge.SourceUserID, merge.TargetUserID)
+	case models.AccountMergeStepFollows:
+		moved, dropped, err = s.mergeRepo.MoveFollows(ctx, merge.SourceUserID, merge.TargetUserID)
+	case models.AccountMergeStepLikes:
+		moved, dropped, err = s.mergeRepo.MoveLikes(ctx, merge.SourceUserID, merge.TargetUserID)
+	case models.AccountMergeStepNotifications:
+		moved, dropped, err = s.mergeRepo.MoveNotifications(ctx, merge.SourceUserID, merge.TargetUserID)
+	case models.AccountMergeStepRedirect:
+		err = s.mergeRepo.Redirect(ctx, merge.SourceUserID, merge.SourceUsername, merge.TargetUserID)
+		// 前回の実行で転送の登録と削除が済んでいる場合
+		if err != nil && err.Error() == "user not found" {
+			err = nil
+		}
+	default:
+		return fmt.Errorf("unknown account merge step: %s", merge.Step)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 再実行された手順は前回の件数に加算する（前回の実行がコミット済みの場合は今回の件数は0になる）
+	if moved > 0 {
+		merge.Moved[merge.Step] += moved
+	}
+	if dropped > 0 {
+		merge.Dropped[merge.Step] += dropped
+	}
+
+	s.log.Debug("アカウント統合の手順を実行しました",
+		"merge_id", merge.ID, "step", merge.Step, "moved", moved, "dropped", dropped)
+	return nil
+}
+
+// fail 失敗した手続きを再試行まで待機させる（最大回数に達した場合は失敗として終了する）
+// 失敗として終了した場合、統合元のアカウントは統合中のまま残り、移し終えたデータは統合先に残る
+func (s *AccountMergeService) fail(merge *models.AccountMerge, cause error) {
+	message := cause.Error()
+	merge.LastError = &message
+
+	if merge.Attempts >= s.maxAttempts {
+		merge.Status = models.AccountMergeFailed
+		s.log.Error("アカウントの統合に失敗しました",
+			"merge_id", merge.ID, "step", merge.Step, "attempts", merge.Attempts, "error", cause)
+	} else {
+		backoff := time.Minute << (merge.Attempts - 1)
+		if backoff <= 0 || backoff > accountMergeMaxBackoff {
+			backoff = accountMergeMaxBackoff
+		}
+		merge.Status = models.AccountMergePending
+		merge.NextAttemptAt = time.Now().UTC().Add(backoff)
+		s.log.Warn("アカウントの統合に失敗したため再試行します",
+			"merge_id", merge.ID, "step", merge.Step, "attempts", merge.Attempts, "retry_in", backoff, "error", cause)
+	}
+
+	s.saveProgress(merge)
+}
+
+// saveProgress 統合手続きの進捗を保存する（失敗した場合は実行中のまま残り、一定時間後に再取得される）
+func (s *AccountMergeService) saveProgress(merge *models.AccountMerge) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), accountMergeStepTimeout)
+	defer cancel()
+
+	if err := s.mergeRepo.UpdateProgress(ctx, merge); err != nil {
+		s.log.Error("アカウント統合の進捗の保存に失敗しました", "merge_id", merge.ID, "step", merge.Step, "error", err)
+		return false
+	}
+	return true
+}
+
+func (s *AccountMergeService) stopping() bool {
+	select {
+	case <-s.stopCh:
+		return true
+	default:
+		return false
+	}
+}
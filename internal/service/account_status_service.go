@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+// accountStatusTTL 停止状態をメモリに保持する時間
+// 停止・解除したインスタンスではForgetで即座に反映し、他のインスタンスではこの時間内に反映される
+const accountStatusTTL = 30 * time.Second
+
+// accountStatus メモリに保持するアカウントの停止状態
+type accountStatus struct {
+	suspended bool
+	checkedAt time.Time
+}
+
+// AccountStatusService 認証済みのリクエストのアカウントが停止されていないかを確認するサービス
+// アクセストークンは停止後も有効期限まで使えるため、リクエストごとにアカウントの状態を確認する
+type AccountStatusService struct {
+	userRepo interfaces.UserRepository
+
+	mu       sync.Mutex
+	statuses map[uuid.UUID]accountStatus
+	sweptAt  time.Time
+}
+
+// NewAccountStatusService 新しいアカウント状態サービスを作成する
+func NewAccountStatusService(userRepo interfaces.UserRepository) *AccountStatusService {
+	return &AccountStatusService{
+		userRepo: userRepo,
+		statuses: make(map[uuid.UUID]accountStatus),
+	}
+}
+
+// IsSuspended IdPまたは管理者によってアカウントが停止されているかを返す
+// 削除されたアカウントも停止されたものとして扱う
+func (s *AccountStatusService) IsSuspended(ctx context.Context, userID uuid.UUID) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	status, ok := s.statuses[userID]
+	s.mu.Unlock()
+	if ok && now.Sub(status.checkedAt) < accountStatusTTL {
+		return status.suspended, nil
+	}
+
+	suspended := true
+	user, err := s.userRepo.GetByIDIncludingInactive(ctx, userID)
+	if err != nil {
+		if err.Error() != "user not found" {
+			return false, err
+		}
+	} else {
+		suspended = user.IsSuspended() || user.IsBanned()
+	}
+
+	s.mu.Lock()
+	// 期限切れのエントリーはTTLごとにまとめて掃除する
+	if now.Sub(s.sweptAt) >= accountStatusTTL {
+		for id, cached := range s.statuses {
+			if now.Sub(cached.checkedAt) >= accountStatusTTL {
+				delete(s.statuses, id)
+			}
+		}
+		s.sweptAt = now
+	}
+	s.statuses[userID] = accountStatus{suspended: suspended, checkedAt: now}
+	s.mu.Unlock()
+
+	return suspended, nil
+}
+
+// Forget アカウントの停止状態を破棄し、次のリクエストでデータベースから読み直す
+func (s *AccountStatusService) Forget(userID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.statuses, userID)
+	s.mu.Unlock()
+}
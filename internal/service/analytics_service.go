@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// 停止時の最終送信にかける最大時間
+const analyticsFlushTimeout = 5 * time.Second
+
+// 登録の方法（signupイベントのmethodプロパティ）
+const (
+	SignupMethodPassword = "password"
+	SignupMethodSSO      = "sso"
+	SignupMethodSCIM     = "scim"
+)
+
+// AnalyticsService 分析イベントのイベントバス
+// ハンドラーやサービスから発行されたイベントをキューに溜め、一定件数または一定間隔ごとに設定された送信先へまとめて送信する
+// イベントの発行はリクエストを待たせないよう非同期で行い、キューがいっぱいの場合や送信先が設定されていない場合はイベントを破棄する
+type AnalyticsService struct {
+	// イベントの送信先（nilの場合は分析を無効にする）
+	sink          interfaces.AnalyticsSink
+	batchSize     int
+	bufferSize    int
+	flushInterval time.Duration
+	log           logger.Logger
+
+	events chan *models.AnalyticsEvent
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewAnalyticsService 新しい分析イベントのイベントバスを作成する
+func NewAnalyticsService(
+	sink interfaces.AnalyticsSink,
+	bufferSize int,
+	batchSize int,
+	flushInterval time.Duration,
+	log logger.Logger,
+) *AnalyticsService {
+	if bufferSize <= 0 {
+		bufferSize = 10000
+	}
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+
+	return &AnalyticsService{
+		sink:          sink,
+		batchSize:     batchSize,
+		bufferSize:    bufferSize,
+		flushInterval: flushInterval,
+		log:           log,
+		events:        make(chan *models.AnalyticsEvent, bufferSize),
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+}
+
+// Start イベントの送信を開始する
+func (s *AnalyticsService) Start() {
+	go s.run()
+}
+
+// Stop イベントの送信を停止し、キューに残ったイベントを送信してから送信先を閉じる
+func (s *AnalyticsService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// Enabled イベントの送信先が設定されているかどうかを返す
+func (s *AnalyticsService) Enabled() bool {
+	return s.sink != nil
+}
+
+// Track イベントを発行する（送信を待たずに戻る）
+func (s *AnalyticsService) Track(event *models.AnalyticsEvent) {
+	if s.sink == nil {
+		return
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		s.log.Warn("分析イベントのキューがいっぱいのためイベントを破棄しました", "type", event.Type)
+	}
+}
+
+// TrackSignup 新しいアカウントの登録を記録する
+func (s *AnalyticsService) TrackSignup(userID uuid.UUID, method string) {
+	s.Track(models.NewAnalyticsEvent(models.AnalyticsEventSignup, &userID, nil, map[string]string{"method": method}))
+}
+
+// run 停止されるまでイベントを受け取り、一定件数または一定間隔ごとに送信する
+func (s *AnalyticsService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.AnalyticsEvent, 0, s.batchSize)
+	for {
+		select {
+		case event := <-s.events:
+			// 送信に失敗したイベントが残っている場合も、一定件数ごとにしか再送しない
+			batch = append(batch, event)
+			if len(batch)%s.batchSize == 0 {
+				batch = s.flush(batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(batch)
+		case <-s.stopCh:
+			// キューに残ったイベントを取り出して最後に送信する
+		drain:
+			for {
+				select {
+				case event := <-s.events:
+					batch = append(batch, event)
+				default:
+					break drain
+				}
+			}
+			if len(s.flush(batch)) > 0 {
+				s.log.Error("停止時に送信できなかった分析イベントを破棄しました", "sink", s.sinkName())
+			}
+			if s.sink != nil {
+				if err := s.sink.Close(); err != nil {
+					s.log.Error("分析イベントの送信先を閉じられませんでした", "error", err, "sink", s.sinkName())
+				}
+			}
+			return
+		}
+	}
+}
+
+// flush イベントを送信先へ送信し、次に送信するイベントを返す
+// 送信に失敗した場合は次回に再送するため、キューの大きさまでイベントを残す（超えた分は古いものから破棄する）
+func (s *AnalyticsService) flush(batch []*models.AnalyticsEvent) []*models.AnalyticsEvent {
+	if len(batch) == 0 || s.sink == nil {
+		return batch[:0]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), analyticsFlushTimeout)
+	defer cancel()
+
+	if err := s.sink.Write(ctx, batch); err != nil {
+		s.log.Error("分析イベントの送信に失敗しました", "error", err, "sink", s.sinkName(), "count", len(batch))
+		if len(batch) > s.bufferSize {
+			s.log.Warn("送信できない分析イベントが多いため古いイベントを破棄しました", "count", len(batch)-s.bufferSize)
+			batch = append(batch[:0], batch[len(batch)-s.bufferSize:]...)
+		}
+		return batch
+	}
+
+	return batch[:0]
+}
+
+// sinkName ログに表示する送信先の名前を返す
+func (s *AnalyticsService) sinkName() string {
+	if s.sink == nil {
+		return "none"
+	}
+	return s.sink.Name()
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// 停止時の最終書き込みにかける最大時間
+const usageFlushTimeout = 5 * time.Second
+
+// apiUsageKey 利用状況を集計する単位
+type apiUsageKey struct {
+	userID   uuid.UUID
+	app      string
+	keyID    string
+	endpoint string
+	hour     time.Time
+}
+
+// apiUsageCount 集計中の利用状況
+type apiUsageCount struct {
+	requests      int64
+	requestBytes  int64
+	responseBytes int64
+}
+
+// APIUsageService トークンごとのAPI利用状況をメモリ上で1時間単位に集計し、一定間隔でまとめてデータベースへ書き込むサービス
+// 不正利用の調査や将来の利用量に応じた課金に使用する
+type APIUsageService struct {
+	usageRepo     interfaces.APIUsageRepository
+	flushInterval time.Duration
+	maxPending    int
+	log           logger.Logger
+
+	mu      sync.Mutex
+	pending map[apiUsageKey]*apiUsageCount
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewAPIUsageService 新しいAPI利用状況集計サービスを作成する
+func NewAPIUsageService(
+	usageRepo interfaces.APIUsageRepository,
+	flushInterval time.Duration,
+	maxPending int,
+	log logger.Logger,
+) *APIUsageService {
+	if flushInterval <= 0 {
+		flushInterval = time.Minute
+	}
+	if maxPending <= 0 {
+		maxPending = 5000
+	}
+
+	return &APIUsageService{
+		usageRepo:     usageRepo,
+		flushInterval: flushInterval,
+		maxPending:    maxPending,
+		log:           log,
+		pending:       make(map[apiUsageKey]*apiUsageCount),
+		flushCh:       make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+}
+
+// Start 定期的な書き込みを開始する
+func (s *APIUsageService) Start() {
+	go s.run()
+}
+
+// Stop 定期的な書き込みを停止し、未書き込みの利用状況を書き込む
+func (s *APIUsageService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// RecordRequest 1件のリクエストを記録する
+func (s *APIUsageService) RecordRequest(userID uuid.UUID, app, keyID, endpoint string, requestBytes, responseBytes int64) {
+	key := apiUsageKey{
+		userID:   userID,
+		app:      app,
+		keyID:    keyID,
+		endpoint: endpoint,
+		hour:     time.Now().UTC().Truncate(time.Hour),
+	}
+
+	s.mu.Lock()
+	count, ok := s.pending[key]
+	if !ok {
+		count = &apiUsageCount{}
+		s.pending[key] = count
+	}
+	count.requests++
+	count.requestBytes += requestBytes
+	count.responseBytes += responseBytes
+	full := len(s.pending) >= s.maxPending
+	s.mu.Unlock()
+
+	// 集計中の件数が多い場合は間隔を待たずに書き込む
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush 集計中の利用状況をデータベースへ書き込む
+// 書き込みに失敗した場合は次回の書き込みで再試行するため、集計中の状態に戻す
+func (s *APIUsageService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	counts := s.pending
+	s.pending = make(map[apiUsageKey]*apiUsageCount, len(counts))
+	s.mu.Unlock()
+
+	usages := make([]*models.APIUsage, 0, len(counts))
+	for key, count := range counts {
+		usages = append(usages, &models.APIUsage{
+			UserID:        key.userID,
+			App:           key.app,
+			KeyID:         key.keyID,
+			Endpoint:      key.endpoint,
+			Hour:          key.hour,
+			RequestCount:  count.requests,
+			RequestBytes:  count.requestBytes,
+			ResponseBytes: count.responseBytes,
+		})
+	}
+
+	if err := s.usageRepo.AddUsage(ctx, usages); err != nil {
+		s.mu.Lock()
+		for key, count := range counts {
+			if current, ok := s.pending[key]; ok {
+				current.requests += count.requests
+				current.requestBytes += count.requestBytes
+				current.responseBytes += count.responseBytes
+			} else {
+				s.pending[key] = count
+			}
+		}
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// GetUserUsage ユーザーのfromからtoまでの1時間ごとの利用状況を取得する
+func (s *APIUsageService) GetUserUsage(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.APIUsage, error) {
+	return s.usageRepo.ListByUser(ctx, userID, from, to)
+}
+
+// GetKeyUsage キーのfromからtoまでの1時間ごとの利用状況を取得する
+func (s *APIUsageService) GetKeyUsage(ctx context.Context, keyID string, from, to time.Time) ([]*models.APIUsage, error) {
+	return s.usageRepo.ListByKey(ctx, keyID, from, to)
+}
+
+// SummarizeByKey fromからtoまでの利用状況をトークンごとに集計して取得する
+func (s *APIUsageService) SummarizeByKey(ctx context.Context, app string, from, to time.Time, limit int) ([]*models.APIUsageSummary, error) {
+	return s.usageRepo.SummarizeByKey(ctx, app, from, to, limit)
+}
+
+// run 停止されるまで一定間隔で利用状況を書き込む
+func (s *APIUsageService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.flushCh:
+			s.flush()
+		case <-s.stopCh:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *APIUsageService) flush() {
+	ctx, cancel := context.WithTimeout(context.Background(), usageFlushTimeout)
+	defer cancel()
+
+	if err := s.Flush(ctx); err != nil {
+		s.log.Error("API利用状況の書き込みに失敗しました", "error", err)
+	}
+}
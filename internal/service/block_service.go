@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ErrBlocked はユーザー間にブロック関係があり操作が許可されないことを表す
+var ErrBlocked = errors.New("interaction blocked")
+
+// BlockService ブロック関係の管理と、ユーザー間のやり取りに対するブロックの適用を行うサービス
+type BlockService struct {
+	blockRepo interfaces.BlockRepository
+	log       logger.Logger
+}
+
+// NewBlockService 新しいブロックサービスを作成する
+func NewBlockService(
+	blockRepo interfaces.BlockRepository,
+	log logger.Logger,
+) *BlockService {
+	return &BlockService{
+		blockRepo: blockRepo,
+		log:       log,
+	}
+}
+
+// BlockUser ユーザーをブロックし、双方向のフォロー関係を解除する（ブロックと同じトランザクションで解除される）
+func (s *BlockService) BlockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return s.blockRepo.Block(ctx, blockerID, blockedID)
+}
+
+// UnblockUser ブロックを解除する
+func (s *BlockService) UnblockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return s.blockRepo.Unblock(ctx, blockerID, blockedID)
+}
+
+// IsBlocking blockerIDがblockedIDをブロックしているかを返す
+func (s *BlockService) IsBlocking(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	return s.blockRepo.IsBlocking(ctx, blockerID, blockedID)
+}
+
+// CheckInteraction actorIDからtargetIDへの操作（フォロー・返信・いいね・閲覧など）が
+// 許可されているかを確認し、いずれかの方向にブロックがあればErrBlockedを返す
+func (s *BlockService) CheckInteraction(ctx context.Context, actorID, targetID uuid.UUID) error {
+	if actorID == uuid.Nil || actorID == targetID {
+		return nil
+	}
+
+	blocked, err := s.blockRepo.IsBlockedEither(ctx, actorID, targetID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+
+	return nil
+}
+
+// FilterPosts 閲覧者とブロック関係にあるユーザーの投稿を取り除く
+func (s *BlockService) FilterPosts(ctx context.Context, viewerID uuid.UUID, posts []*models.Post) ([]*models.Post, error) {
+	if viewerID == uuid.Nil || len(posts) == 0 {
+		return posts, nil
+	}
+
+	excluded, err := s.blockedUserSet(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(excluded) == 0 {
+		return posts, nil
+	}
+
+	filtered := make([]*models.Post, 0, len(posts))
+	for _, post := range posts {
+		if excluded[post.UserID] {
+			continue
+		}
+		filtered = append(filtered, post)
+	}
+
+	return filtered, nil
+}
+
+// FilterUserIDs 閲覧者とブロック関係にあるユーザーのIDを取り除く
+func (s *BlockService) FilterUserIDs(ctx context.Context, viewerID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if viewerID == uuid.Nil || len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	excluded, err := s.blockedUserSet(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(excluded) == 0 {
+		return userIDs, nil
+	}
+
+	filtered := make([]uuid.UUID, 0, len(userIDs))
+	for _, id := range userIDs {
+		if excluded[id] {
+			continue
+		}
+		filtered = append(filtered, id)
+	}
+
+	return filtered, nil
+}
+
+// blockedUserSet 閲覧者とブロック関係にあるユーザーIDの集合を返す
+func (s *BlockService) blockedUserSet(ctx context.Context, viewerID uuid.UUID) (map[uuid.UUID]bool, error) {
+	related, err := s.blockRepo.GetBlockRelatedUserIDs(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[uuid.UUID]bool, len(related))
+	for _, id := range related {
+		set[id] = true
+	}
+
+	return set, nil
+}
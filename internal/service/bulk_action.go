@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+)
+
+// 一括操作の結果に含める対象の例の最大件数
+const bulkActionSampleSize = 20
+
+// BulkActionResult 管理者による一括操作（削除・停止・パージ）の結果
+type BulkActionResult struct {
+	// 実行せずに対象を数えただけかどうか
+	DryRun bool
+	// 対象となった行数とファイル数
+	Rows  int64
+	Files int
+	// 対象の例（IDやファイルのURL、最大20件）
+	Sample []string
+}
+
+// AddSample 対象の例を追加する（上限を超えた分は無視する）
+func (r *BulkActionResult) AddSample(items ...string) {
+	for _, item := range items {
+		if len(r.Sample) >= bulkActionSampleSize {
+			return
+		}
+		r.Sample = append(r.Sample, item)
+	}
+}
+
+// RunBulkAction 管理者による一括操作を実行する
+// dryRunがtrueの場合は、実際に実行する場合と同じ処理をトランザクション内で実行してからロールバックするため、件数と対象の例は実行した場合と一致する
+// fnはファイルの削除など取り消せない処理をtxManager.AfterCommitで登録し、ドライランではそれらは実行されない
+func RunBulkAction(
+	ctx context.Context,
+	txManager interfaces.TxManager,
+	dryRun bool,
+	fn func(ctx context.Context, result *BulkActionResult) error,
+) (*BulkActionResult, error) {
+	result := &BulkActionResult{DryRun: dryRun, Sample: []string{}}
+	run := func(ctx context.Context) error {
+		return fn(ctx, result)
+	}
+
+	var err error
+	if dryRun {
+		err = txManager.DryRun(ctx, run)
+	} else {
+		err = txManager.WithinTx(ctx, run)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ContentScreenResult 禁止語ルールによる審査の結果
+type ContentScreenResult struct {
+	// 拒否するルールに一致したか
+	Rejected bool
+	// 通報キューに載せるルールに一致したか
+	Flagged bool
+	// 一致したラベルのルールのうち最も厳しいレーティング（一致しない場合はgeneral）
+	Rating models.ContentRating
+	// 一致したルール
+	Matches []*models.ContentFilterRule
+}
+
+// ContentFilterService 投稿・プロフィールの内容を管理者が登録した禁止語ルールで審査するサービス
+// ルールはメモリに保持し、refreshIntervalごとにデータベースから読み直す
+type ContentFilterService struct {
+	filterRepo      interfaces.ContentFilterRepository
+	reportRepo      interfaces.ReportRepository
+	refreshInterval time.Duration
+	log             logger.Logger
+
+	mu       sync.RWMutex
+	rules    []*models.ContentFilterRule
+	loadedAt time.Time
+}
+
+// NewContentFilterService 新しいコンテンツフィルターサービスを作成する
+func NewContentFilterService(
+	filterRepo interfaces.ContentFilterRepository,
+	reportRepo interfaces.ReportRepository,
+	refreshInterval time.Duration,
+	log logger.Logger,
+) *ContentFilterService {
+	if refreshInterval <= 0 {
+		refreshInterval = time.Minute
+	}
+
+	return &ContentFilterService{
+		filterRepo:      filterRepo,
+		reportRepo:      reportRepo,
+		refreshInterval: refreshInterval,
+		log:             log,
+	}
+}
+
+// Screen テキストを禁止語ルールで審査する（空のテキストは無視する）
+func (s *ContentFilterService) Screen(ctx context.Context, texts ...string) (*ContentScreenResult, error) {
+	rules, err := s.loadRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ContentScreenResult{Rating: models.ContentRatingGeneral}
+	for _, rule := range rules {
+		if !matchesAny(rule, texts) {
+			continue
+		}
+		result.Matches = append(result.Matches, rule)
+		switch rule.Action {
+		case models.ContentFilterActionReject:
+			result.Rejected = true
+		case models.ContentFilterActionFlag:
+			result.Flagged = true
+		case models.ContentFilterActionLabel:
+			result.Rating = result.Rating.Max(rule.Rating)
+		}
+	}
+
+	return result, nil
+}
+
+// Flag 審査で通報キューに載せるルールに一致した対象を自動の通報として登録する
+// 同じ対象の未対応の自動通報が既にある場合は何もしない
+func (s *ContentFilterService) Flag(ctx context.Context, targetType models.ReportTargetType, targetID, targetUserID uuid.UUID, result *ContentScreenResult) error {
+	if result == nil || !result.Flagged {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(result.Matches))
+	for _, rule := range result.Matches {
+		if rule.Action == models.ContentFilterActionFlag {
+			patterns = append(patterns, rule.Pattern)
+		}
+	}
+
+	report := models.NewAutomaticReport(targetType, targetID, targetUserID, models.ReportReasonOther,
+		"content filter: "+strings.Join(patterns, ", "))
+	if err := s.reportRepo.Create(ctx, report); err != nil && err.Error() != "report already exists" {
+		return err
+	}
+
+	return nil
+}
+
+// Invalidate 保持しているルールを破棄し、次の審査でデータベースから読み直す
+// 管理者がルールを変更した直後に呼び出す
+func (s *ContentFilterService) Invalidate() {
+	s.mu.Lock()
+	s.rules = nil
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// loadRules 保持しているルールを返す（古くなっている場合はデータベースから読み直す）
+// 読み直しに失敗した場合は、保持している古いルールがあればそれを使い続ける
+func (s *ContentFilterService) loadRules(ctx context.Context) ([]*models.ContentFilterRule, error) {
+	s.mu.RLock()
+	rules, loadedAt := s.rules, s.loadedAt
+	s.mu.RUnlock()
+
+	if !loadedAt.IsZero() && time.Since(loadedAt) < s.refreshInterval {
+		return rules, nil
+	}
+
+	fresh, err := s.filterRepo.List(ctx)
+	if err != nil {
+		if !loadedAt.IsZero() {
+			s.log.Error("禁止語ルールの読み直しに失敗しました。保持しているルールを使用します", "error", err)
+			return rules, nil
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.rules = fresh
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return fresh, nil
+}
+
+// matchesAny ルールがいずれかのテキストに一致するかを判定する
+func matchesAny(rule *models.ContentFilterRule, texts []string) bool {
+	for _, text := range texts {
+		if text != "" && rule.Matches(text) {
+			return true
+		}
+	}
+	return false
+}
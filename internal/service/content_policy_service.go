@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ContentFilterReasonAgeRestricted 年齢制限により投稿が非表示になったことを表す理由コード
+const ContentFilterReasonAgeRestricted = "age_restricted"
+
+// ContentPolicyService 投稿のコンテンツレーティングに基づく閲覧可否を判定するサービス
+type ContentPolicyService struct {
+	userRepo           interfaces.UserRepository
+	minimumAge         int
+	countryMinimumAges map[string]int
+	log                logger.Logger
+}
+
+// NewContentPolicyService 新しいコンテンツポリシーサービスを作成する
+// countryMinimumAgesは国コードごとに既定の最低年齢を上書きする
+func NewContentPolicyService(
+	userRepo interfaces.UserRepository,
+	minimumAge int,
+	countryMinimumAges map[string]int,
+	log logger.Logger,
+) *ContentPolicyService {
+	normalized := make(map[string]int, len(countryMinimumAges))
+	for code, age := range countryMinimumAges {
+		normalized[strings.ToUpper(code)] = age
+	}
+
+	return &ContentPolicyService{
+		userRepo:           userRepo,
+		minimumAge:         minimumAge,
+		countryMinimumAges: normalized,
+		log:                log,
+	}
+}
+
+// LoadViewer 閲覧者のユーザー情報を取得する（未認証の場合はnilを返す）
+func (s *ContentPolicyService) LoadViewer(ctx context.Context, viewerID uuid.UUID) (*models.User, error) {
+	if viewerID == uuid.Nil {
+		return nil, nil
+	}
+	return s.userRepo.GetByID(ctx, viewerID)
+}
+
+// MinimumAgeFor 国コードに対応する制限付きコンテンツの最低年齢を返す
+func (s *ContentPolicyService) MinimumAgeFor(countryCode string) int {
+	if age, ok := s.countryMinimumAges[strings.ToUpper(countryCode)]; ok {
+		return age
+	}
+	return s.minimumAge
+}
+
+// CanViewRestricted 閲覧者が制限付きコンテンツを閲覧できるかを返す
+// 年齢確認済みで、居住国の最低年齢を満たしている必要がある
+func (s *ContentPolicyService) CanViewRestricted(viewer *models.User) bool {
+	if viewer == nil || !viewer.IsAgeVerified {
+		return false
+	}
+	return viewer.AgeAt(time.Now()) >= s.MinimumAgeFor(viewer.CountryCode)
+}
+
+// CanView 閲覧者が投稿を閲覧できるかを返す
+func (s *ContentPolicyService) CanView(viewer *models.User, post *models.Post) bool {
+	if !post.ContentRating.IsRestricted() {
+		return true
+	}
+	// 投稿者本人は常に閲覧できる
+	if viewer != nil && viewer.ID == post.UserID {
+		return true
+	}
+	return s.CanViewRestricted(viewer)
+}
+
+// FilterPosts 閲覧者が閲覧できない投稿を取り除き、非表示にした件数とともに返す
+func (s *ContentPolicyService) FilterPosts(viewer *models.User, posts []*models.Post) ([]*models.Post, int) {
+	visible := make([]*models.Post, 0, len(posts))
+	for _, post := range posts {
+		if s.CanView(viewer, post) {
+			visible = append(visible, post)
+		}
+	}
+	return visible, len(posts) - len(visible)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// 会話の祖先投稿をたどる最大の深さ
+const maxConversationDepth = 100
+
+// ConversationService 会話（返信スレッド）の参加者への通知とミュートを管理するサービス
+type ConversationService struct {
+	postRepo            interfaces.PostRepository
+	txManager           interfaces.TxManager
+	muteRepo            interfaces.ConversationMuteRepository
+	blockService        *BlockService
+	notificationService *NotificationService
+	log                 logger.Logger
+}
+
+// NewConversationService 新しい会話サービスを作成する
+func NewConversationService(
+	postRepo interfaces.PostRepository,
+	txManager interfaces.TxManager,
+	muteRepo interfaces.ConversationMuteRepository,
+	blockService *BlockService,
+	notificationService *NotificationService,
+	log logger.Logger,
+) *ConversationService {
+	return &ConversationService{
+		postRepo:            postRepo,
+		txManager:           txManager,
+		muteRepo:            muteRepo,
+		blockService:        blockService,
+		notificationService: notificationService,
+		log:                 log,
+	}
+}
+
+// CreateReply 返信を保存し、返信先の返信数の更新と会話の参加者への通知を1つのトランザクションで行う
+// いずれかが失敗した場合はすべて取り消され、WebSocketでの通知はコミット後に送信する
+func (s *ConversationService) CreateReply(ctx context.Context, reply *models.Post) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.postRepo.Create(ctx, reply); err != nil {
+			return err
+		}
+		if err := s.postRepo.IncrementReplyCount(ctx, *reply.ReplyToID); err != nil {
+			return err
+		}
+		return s.notifyReply(ctx, reply)
+	})
+}
+
+// CreateThread 返信でつながったスレッドの投稿を保存し、返信数の更新と会話の参加者への通知を1つのトランザクションで行う
+// 通知は他のユーザーの会話につながる先頭の投稿についてのみ行い（2件目以降は自分の投稿への返信のため）、
+// いずれかが失敗した場合はスレッド全体が取り消される
+func (s *ConversationService) CreateThread(ctx context.Context, posts []*models.Post) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.postRepo.CreateThread(ctx, posts); err != nil {
+			return err
+		}
+		return s.notifyReply(ctx, posts[0])
+	})
+}
+
+// NotifyReply 返信を会話の参加者に通知する
+// 返信先の投稿者に加えて、ルート投稿者と会話の途中で返信したユーザーにも通知する
+// 同じユーザーへの通知は1回にまとめ、返信者とブロック関係にあるユーザーや会話をミュートしているユーザーには通知しない
+func (s *ConversationService) NotifyReply(ctx context.Context, reply *models.Post) {
+	// エラーはnotifyReplyの中でログに記録する
+	_ = s.notifyReply(ctx, reply)
+}
+
+// notifyReply 返信を会話の参加者に通知し、通知の作成に失敗した場合はエラーを返す
+// トランザクション内ではエラーの後の文が実行できないため、最初のエラーで中断する
+func (s *ConversationService) notifyReply(ctx context.Context, reply *models.Post) error {
+	if reply.ReplyToID == nil {
+		return nil
+	}
+
+	ancestors, err := s.postRepo.GetAncestors(ctx, reply.ID, maxConversationDepth)
+	if err != nil {
+		s.log.Error("会話通知: 祖先投稿取得エラー", "error", err)
+		return err
+	}
+	if len(ancestors) == 0 {
+		return nil
+	}
+
+	root := ancestors[0]
+	parent := ancestors[len(ancestors)-1]
+
+	// 返信先の投稿者を先頭に、ルート投稿者、会話の参加者の順に通知先を並べる
+	// 削除された投稿・モデレーターにより非表示にされた投稿の投稿者は会話の参加者として扱わない
+	candidates := make([]uuid.UUID, 0, len(ancestors)+2)
+	for _, ancestor := range append([]*models.Post{parent, root}, ancestors...) {
+		if !ancestor.IsUnavailable() {
+			candidates = append(candidates, ancestor.UserID)
+		}
+	}
+
+	seen := map[uuid.UUID]bool{reply.UserID: true}
+	recipients := make([]uuid.UUID, 0, len(candidates))
+	for _, id := range candidates {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		recipients = append(recipients, id)
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	// 会話をミュートしているユーザーを除外
+	muted, err := s.muteRepo.GetMutedUserIDs(ctx, root.ID, recipients)
+	if err != nil {
+		s.log.Error("会話通知: ミュート状態取得エラー", "error", err)
+		return err
+	}
+	mutedSet := make(map[uuid.UUID]bool, len(muted))
+	for _, id := range muted {
+		mutedSet[id] = true
+	}
+
+	for _, recipientID := range recipients {
+		if mutedSet[recipientID] {
+			continue
+		}
+
+		// 返信者とブロック関係にあるユーザーには通知しない
+		if err := s.blockService.CheckInteraction(ctx, reply.UserID, recipientID); err != nil {
+			if err != ErrBlocked {
+				s.log.Error("会話通知: ブロック状態確認エラー", "error", err)
+			}
+			continue
+		}
+
+		if recipientID == parent.UserID {
+			err = s.notificationService.CreateReplyNotification(ctx, reply.UserID, recipientID, parent.ID, reply.ID)
+		} else {
+			err = s.notificationService.CreateConversationReplyNotification(ctx, reply.UserID, recipientID, reply.ID)
+		}
+		if err != nil {
+			s.log.Error("会話通知: 通知作成エラー", "error", err, "recipient_id", recipientID)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RootPostID 投稿が属する会話のルート投稿IDを返す
+func (s *ConversationService) RootPostID(ctx context.Context, post *models.Post) (uuid.UUID, error) {
+	if post.ReplyToID == nil {
+		return post.ID, nil
+	}
+
+	ancestors, err := s.postRepo.GetAncestors(ctx, post.ID, maxConversationDepth)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(ancestors) == 0 {
+		// 返信先が削除されている場合は投稿自身をルートとして扱う
+		return post.ID, nil
+	}
+
+	return ancestors[0].ID, nil
+}
+
+// Mute 投稿が属する会話をミュートし、ルート投稿IDを返す
+func (s *ConversationService) Mute(ctx context.Context, userID uuid.UUID, post *models.Post) (uuid.UUID, error) {
+	rootID, err := s.RootPostID(ctx, post)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return rootID, s.muteRepo.Mute(ctx, userID, rootID)
+}
+
+// Unmute 投稿が属する会話のミュートを解除し、ルート投稿IDを返す
+func (s *ConversationService) Unmute(ctx context.Context, userID uuid.UUID, post *models.Post) (uuid.UUID, error) {
+	rootID, err := s.RootPostID(ctx, post)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return rootID, s.muteRepo.Unmute(ctx, userID, rootID)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+)
+
+// CounterService 投稿・ユーザーの集計値を元のテーブル（likes・follows・posts）から再計算するサービス
+// いいね数・フォロワー数などの増減はリポジトリがいいね・フォローと同じトランザクションで行うため、
+// ここでは手動での修正などでずれた値を直すだけとする（定期的な実行は保守タスクのスケジューラーが行う）
+// 増減をRedisに加算してまとめて書き込む方式は、トランザクション内の更新と二重に数えることになるため使わない
+type CounterService struct {
+	counterRepo interfaces.CounterRepository
+}
+
+// NewCounterService 新しいカウンターサービスを作成する
+func NewCounterService(counterRepo interfaces.CounterRepository) *CounterService {
+	return &CounterService{counterRepo: counterRepo}
+}
+
+// Reconcile すべてのカウンターを元のテーブルから再計算し、修正した投稿数とユーザー数を返す
+func (s *CounterService) Reconcile(ctx context.Context) (posts, users int64, err error) {
+	posts, err = s.counterRepo.ReconcilePostCounts(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	users, err = s.counterRepo.ReconcileUserCounts(ctx)
+	if err != nil {
+		return posts, 0, err
+	}
+
+	return posts, users, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// emailJob 送信キューに積む通知
+type emailJob struct {
+	recipientID      uuid.UUID
+	notificationType models.NotificationType
+	event            websocket.NotificationEvent
+}
+
+// EmailNotificationService フォロー・メンションの通知を、WebSocketで接続していないユーザーへメールで送信するサービス
+// ユーザーが通知の種類ごとにメール通知を無効にしている場合は送信しない
+type EmailNotificationService struct {
+	settingsRepo interfaces.NotificationSettingsRepository
+	userRepo     interfaces.UserRepository
+	hub          *websocket.Hub
+	sender       coreinterfaces.EmailSender
+	appName      string
+	appURL       string
+	timeout      time.Duration
+	workers      int
+	log          logger.Logger
+
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan emailJob
+	wg      sync.WaitGroup
+}
+
+// NewEmailNotificationService 新しいメール通知サービスを作成する
+// appNameはメールの件名、appURLは本文の通知一覧へのリンクに使う
+func NewEmailNotificationService(
+	settingsRepo interfaces.NotificationSettingsRepository,
+	userRepo interfaces.UserRepository,
+	hub *websocket.Hub,
+	sender coreinterfaces.EmailSender,
+	appName string,
+	appURL string,
+	timeout time.Duration,
+	workers int,
+	queueSize int,
+	log logger.Logger,
+) *EmailNotificationService {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	if workers <= 0 {
+		workers = 2
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	return &EmailNotificationService{
+		settingsRepo: settingsRepo,
+		userRepo:     userRepo,
+		hub:          hub,
+		sender:       sender,
+		appName:      appName,
+		appURL:       strings.TrimSuffix(appURL, "/"),
+		timeout:      timeout,
+		workers:      workers,
+		log:          log,
+		queue:        make(chan emailJob, queueSize),
+	}
+}
+
+// Start 送信ワーカーを開始する
+func (s *EmailNotificationService) Start() {
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+}
+
+// Stop 新しい通知の受け付けを停止し、キューに残っている通知の送信を待つ
+func (s *EmailNotificationService) Stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Notify 通知をメールで送信するようキューに追加する（メールで送信できない種類の通知は無視する）
+// キューが満杯か停止中の場合はErrDeliveryUnavailableを返す
+func (s *EmailNotificationService) Notify(recipientID uuid.UUID, notificationType models.NotificationType, event websocket.NotificationEvent) error {
+	if !models.IsEmailNotificationType(notificationType) {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return ErrDeliveryUnavailable
+	}
+
+	select {
+	case s.queue <- emailJob{recipientID: recipientID, notificationType: notificationType, event: event}:
+		return nil
+	default:
+		s.log.Warn("メール通知: キューが満杯のため送信を受け付けられませんでした", "user_id", recipientID, "type", notificationType)
+		return ErrDeliveryUnavailable
+	}
+}
+
+func (s *EmailNotificationService) worker() {
+	defer s.wg.Done()
+	for job := range s.queue {
+		s.send(job)
+	}
+}
+
+// send 受信者がオフラインで、メール通知を有効にしている場合にメールを送信する
+func (s *EmailNotificationService) send(job emailJob) {
+	// 接続中のユーザーにはWebSocketで届いているため送信しない
+	if s.hub.IsOnline(job.recipientID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	settings, err := s.settingsRepo.Get(ctx, job.recipientID)
+	if err != nil {
+		s.log.Error("メール通知: 通知設定の取得に失敗しました", "error", err, "user_id", job.recipientID)
+		return
+	}
+	if !settings.EmailEnabled(job.notificationType) {
+		return
+	}
+
+	recipient, err := s.userRepo.GetByID(ctx, job.recipientID)
+	if err != nil {
+		s.log.Error("メール通知: 受信者の取得に失敗しました", "error", err, "user_id", job.recipientID)
+		return
+	}
+	if recipient.IsSystem || recipient.Status != models.UserStatusActive || recipient.Email == "" {
+		return
+	}
+
+	subject := fmt.Sprintf("[%s] %s", s.appName, job.event.Message)
+	if err := s.sender.Send(ctx, recipient.Email, subject, s.buildBody(recipient, job.event)); err != nil {
+		s.log.Warn("メール通知: 送信に失敗しました", "error", err, "user_id", job.recipientID, "type", job.notificationType)
+	}
+}
+
+// buildBody メールの本文を作成する
+func (s *EmailNotificationService) buildBody(recipient *models.User, event websocket.NotificationEvent) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%sさん\n\n%s\n", recipient.Name, event.Message)
+	if event.Post != nil && event.Post.Content != "" {
+		fmt.Fprintf(&body, "\n「%s」\n", event.Post.Content)
+	}
+	if s.appURL != "" {
+		fmt.Fprintf(&body, "\n通知を確認する: %s/notifications\n", s.appURL)
+	}
+	fmt.Fprintf(&body, "\n--\nこのメールは%sからの通知です。メール通知は通知設定から種類ごとに停止できます。\n", s.appName)
+	return body.String()
+}
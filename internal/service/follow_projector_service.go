@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// 1回の射影にかける最大時間
+const followProjectTimeout = 30 * time.Second
+
+// FollowProjectorService フォローイベントをfollowsテーブルに反映する射影ワーカー
+// API経由のフォロー・フォロー解除はリポジトリがイベントと同じトランザクションで反映するため、
+// ここではAppendEventで記録だけされたイベントを一定間隔で反映し、必要に応じてfollowsをイベントから作り直す
+type FollowProjectorService struct {
+	followRepo  interfaces.FollowRepository
+	counterRepo interfaces.CounterRepository
+	interval    time.Duration
+	batchSize   int
+	log         logger.Logger
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewFollowProjectorService 新しいフォローイベントの射影ワーカーを作成する
+func NewFollowProjectorService(
+	followRepo interfaces.FollowRepository,
+	counterRepo interfaces.CounterRepository,
+	interval time.Duration,
+	batchSize int,
+	log logger.Logger,
+) *FollowProjectorService {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &FollowProjectorService{
+		followRepo:  followRepo,
+		counterRepo: counterRepo,
+		interval:    interval,
+		batchSize:   batchSize,
+		log:         log,
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+}
+
+// Start 定期的な射影を開始する
+func (s *FollowProjectorService) Start() {
+	go s.run()
+}
+
+// Stop 定期的な射影を停止する
+func (s *FollowProjectorService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// ProjectPending 未反映のイベントがなくなるまで反映し、反映した件数を返す
+func (s *FollowProjectorService) ProjectPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		projected, err := s.followRepo.ProjectPending(ctx, s.batchSize)
+		total += projected
+		if err != nil {
+			return total, err
+		}
+		if projected < s.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// Rebuild followsをイベントから作り直し、フォロワー数・フォロー数を再計算する
+// 作り直したフォロー関係の数と、カウンターを修正したユーザー数を返す
+func (s *FollowProjectorService) Rebuild(ctx context.Context) (follows, users int64, err error) {
+	follows, err = s.followRepo.RebuildProjection(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	users, err = s.counterRepo.ReconcileUserCounts(ctx)
+	if err != nil {
+		return follows, 0, err
+	}
+
+	return follows, users, nil
+}
+
+// GetChurn 期間内の日ごとのフォロー・フォロー解除の数を取得する（userIDがnilの場合はサービス全体）
+func (s *FollowProjectorService) GetChurn(ctx context.Context, userID *uuid.UUID, from, to time.Time) ([]*models.FollowChurnDay, error) {
+	return s.followRepo.GetChurn(ctx, userID, from, to.AddDate(0, 0, 1))
+}
+
+// run 停止されるまで一定間隔で未反映のイベントを反映する
+func (s *FollowProjectorService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.project()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *FollowProjectorService) project() {
+	ctx, cancel := context.WithTimeout(context.Background(), followProjectTimeout)
+	defer cancel()
+
+	projected, err := s.ProjectPending(ctx)
+	if err != nil {
+		s.log.Error("フォローイベントの反映に失敗しました", "error", err)
+		return
+	}
+	if projected > 0 {
+		s.log.Info("フォローイベントを反映しました", "events", projected)
+	}
+}
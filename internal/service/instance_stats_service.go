@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+const (
+	// アクティブユーザーとして数える期間
+	instanceStatsActiveWindow = 30 * 24 * time.Hour
+	// 1回の集計にかける最大時間
+	instanceStatsTimeout = time.Minute
+)
+
+// InstanceStatsService インスタンスディレクトリ向けの公開統計を定期的に集計して保持するサービス
+// 公開エンドポイントへのリクエストはこのサービスが保持する集計結果のみを返し、データベースに問い合わせない
+type InstanceStatsService struct {
+	statsRepo interfaces.UserStatsRepository
+	interval  time.Duration
+	log       logger.Logger
+
+	mu      sync.RWMutex
+	current *models.InstanceStats
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewInstanceStatsService 新しい公開統計サービスを作成する
+// intervalは集計の間隔
+func NewInstanceStatsService(
+	statsRepo interfaces.UserStatsRepository,
+	interval time.Duration,
+	log logger.Logger,
+) *InstanceStatsService {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &InstanceStatsService{
+		statsRepo: statsRepo,
+		interval:  interval,
+		log:       log,
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+// Start 公開統計の定期的な集計を開始する（起動直後に1回集計する）
+func (s *InstanceStatsService) Start() {
+	go s.run()
+}
+
+// Stop 公開統計の定期的な集計を停止する
+func (s *InstanceStatsService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// Current 最新の集計結果を返す（まだ一度も集計できていない場合はnil）
+func (s *InstanceStatsService) Current() *models.InstanceStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// run 停止されるまで一定間隔で公開統計を集計する
+func (s *InstanceStatsService) run() {
+	defer close(s.doneCh)
+
+	s.refresh()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.refresh()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// refresh 公開統計を集計して保持する（失敗した場合は前回の集計結果を返し続ける）
+func (s *InstanceStatsService) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), instanceStatsTimeout)
+	defer cancel()
+
+	stats, err := s.statsRepo.CountInstanceStats(ctx, time.Now().UTC().Add(-instanceStatsActiveWindow))
+	if err != nil {
+		s.log.Error("公開統計の集計に失敗しました", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.current = stats
+	s.mu.Unlock()
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+const (
+	// 1回に削除する通知の最大数
+	notificationPurgeBatchSize = 1000
+	// 1回に削除する参照されていないメディアの最大数
+	orphanedMediaSweepBatchSize = 100
+)
+
+// MaintenanceService スケジューラーから定期実行する保守タスクをまとめたサービス
+// 各タスクはジョブとして実行されるため、失敗した場合は再試行され、同じタスクを複数回実行しても問題ないようにする
+type MaintenanceService struct {
+	counters              *CounterService
+	notificationRepo      interfaces.NotificationRepository
+	trendRepo             interfaces.TrendRepository
+	media                 *MediaService
+	notificationRetention time.Duration
+	trendRetention        time.Duration
+	mediaGracePeriod      time.Duration
+	log                   logger.Logger
+}
+
+// NewMaintenanceService 新しい保守タスクのサービスを作成する
+func NewMaintenanceService(
+	counters *CounterService,
+	notificationRepo interfaces.NotificationRepository,
+	trendRepo interfaces.TrendRepository,
+	media *MediaService,
+	notificationRetention time.Duration,
+	trendRetention time.Duration,
+	mediaGracePeriod time.Duration,
+	log logger.Logger,
+) *MaintenanceService {
+	if notificationRetention <= 0 {
+		notificationRetention = 90 * 24 * time.Hour
+	}
+	if trendRetention <= 0 {
+		trendRetention = 7 * 24 * time.Hour
+	}
+	if mediaGracePeriod <= 0 {
+		mediaGracePeriod = 24 * time.Hour
+	}
+
+	return &MaintenanceService{
+		counters:              counters,
+		notificationRepo:      notificationRepo,
+		trendRepo:             trendRepo,
+		media:                 media,
+		notificationRetention: notificationRetention,
+		trendRetention:        trendRetention,
+		mediaGracePeriod:      mediaGracePeriod,
+		log:                   log,
+	}
+}
+
+// ReconcileCounters いいね数・フォロワー数などのカウンターを元のテーブルから再計算する
+func (s *MaintenanceService) ReconcileCounters(ctx context.Context, _ *models.Job) error {
+	posts, users, err := s.counters.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	s.log.Info("カウンターを再計算しました", "posts", posts, "users", users)
+	return nil
+}
+
+// PurgeNotifications 保持期間を過ぎた通知を削除する
+func (s *MaintenanceService) PurgeNotifications(ctx context.Context, _ *models.Job) error {
+	before := time.Now().Add(-s.notificationRetention)
+
+	var total int64
+	for {
+		deleted, err := s.notificationRepo.DeleteBefore(ctx, before, notificationPurgeBatchSize)
+		if err != nil {
+			return err
+		}
+		total += deleted
+		if deleted < notificationPurgeBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.log.Info("保持期間を過ぎた通知を削除しました", "notifications", total)
+	}
+	return nil
+}
+
+// AggregateTrends 直前の1時間と現在の1時間のハッシュタグを集計し直し、保持期間を過ぎた集計を削除する
+// 直前の1時間も集計し直すのは、前回の実行後にその時間に作成された投稿を反映するため
+func (s *MaintenanceService) AggregateTrends(ctx context.Context, _ *models.Job) error {
+	now := time.Now().UTC()
+	current := now.Truncate(time.Hour)
+
+	for _, hour := range []time.Time{current.Add(-time.Hour), current} {
+		if _, err := s.trendRepo.AggregateHour(ctx, hour); err != nil {
+			return err
+		}
+	}
+
+	deleted, err := s.trendRepo.DeleteBefore(ctx, now.Add(-s.trendRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		s.log.Info("保持期間を過ぎたトレンドの集計を削除しました", "count", deleted)
+	}
+	return nil
+}
+
+// SweepOrphanedMedia アップロード後、猶予を過ぎてもどこからも参照されていないメディアを削除する
+func (s *MaintenanceService) SweepOrphanedMedia(ctx context.Context, _ *models.Job) error {
+	before := time.Now().Add(-s.mediaGracePeriod)
+
+	total := 0
+	for {
+		deleted, err := s.media.SweepOrphans(ctx, before, orphanedMediaSweepBatchSize)
+		total += deleted
+		if err != nil {
+			return err
+		}
+		if deleted < orphanedMediaSweepBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.log.Info("参照されていないメディアを削除しました", "count", total)
+	}
+	return nil
+}
@@ -0,0 +1,529 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	repointerfaces "github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/blurhash"
+	"github.com/TakuyaAizawa/gox/internal/util/mp4"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// 重複を除いたメディアを保存するストレージ内のディレクトリ
+	mediaPathPrefix = "media/"
+	// プレースホルダー（BlurHash）の横・縦方向の成分数
+	mediaBlurhashXComponents = 4
+	mediaBlurhashYComponents = 3
+	// プレースホルダーを生成する画像の最大の幅・高さ（これより大きい画像はデコードしない）
+	maxMediaImageDimension = 8192
+	// 動画のサムネイルの抽出にかける最大時間
+	videoThumbnailTimeout = 30 * time.Second
+)
+
+var (
+	// ErrInvalidVideo 動画のファイルとして読み込めない（MP4・MOV形式でない、または再生時間が記録されていない）
+	ErrInvalidVideo = errors.New("invalid video")
+	// ErrVideoTooLong 動画の再生時間が上限を超えている
+	ErrVideoTooLong = errors.New("video too long")
+	// ErrStorageQuotaExceeded アップロードするとユーザーのメディアの使用量が上限を超える
+	ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+)
+
+// MediaService アップロードされたメディアを内容のハッシュで重複排除して保存するサービス
+// 同じ内容のファイルはストレージに1つだけ保存し、参照数が0になりどこからも使われなくなった時点で削除する
+// ユーザーごとのメディアの使用量も管理し、アップロード時に上限を検証する（参照を外すとアップロードしたユーザーの使用量から差し引く）
+type MediaService struct {
+	mediaRepo repointerfaces.MediaRepository
+	usageRepo repointerfaces.StorageUsageRepository
+	storage   interfaces.StorageProvider
+	// 動画のサムネイルの抽出に使うffmpegのパス（空の場合はサムネイルを抽出しない）
+	ffmpegPath string
+	// ユーザーごとのメディアの使用量の上限（0以下の場合は上限なし）
+	storageQuota int64
+	log          logger.Logger
+}
+
+// NewMediaService 新しいメディアサービスを作成する
+func NewMediaService(
+	mediaRepo repointerfaces.MediaRepository,
+	usageRepo repointerfaces.StorageUsageRepository,
+	storage interfaces.StorageProvider,
+	ffmpegPath string,
+	storageQuota int64,
+	log logger.Logger,
+) *MediaService {
+	return &MediaService{
+		mediaRepo:    mediaRepo,
+		usageRepo:    usageRepo,
+		storage:      storage,
+		ffmpegPath:   ffmpegPath,
+		storageQuota: storageQuota,
+		log:          log,
+	}
+}
+
+// StorageUsage ユーザーのメディアの使用量と上限を返す
+func (s *MediaService) StorageUsage(ctx context.Context, userID uuid.UUID) (*models.StorageUsage, error) {
+	usage, err := s.usageRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	usage.QuotaBytes = s.storageQuota
+	return usage, nil
+}
+
+// CheckStorage sizeバイトのファイルをアップロードしても上限を超えないか検証する（使用量には加えない）
+func (s *MediaService) CheckStorage(ctx context.Context, userID uuid.UUID, size int64) error {
+	usage, err := s.StorageUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !usage.Allows(size) {
+		return ErrStorageQuotaExceeded
+	}
+	return nil
+}
+
+// ReserveStorage 保存する前にsizeバイトのファイルをユーザーの使用量に加える（上限を超える場合はErrStorageQuotaExceeded）
+// 保存に失敗した場合はRefundStorageで差し引く
+func (s *MediaService) ReserveStorage(ctx context.Context, userID uuid.UUID, size int64) error {
+	reserved, err := s.usageRepo.Reserve(ctx, userID, size, s.storageQuota)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return ErrStorageQuotaExceeded
+	}
+	return nil
+}
+
+// RefundStorage ReserveStorageで加えたファイルをユーザーの使用量から差し引く（失敗した場合はログに残す）
+func (s *MediaService) RefundStorage(ctx context.Context, userID uuid.UUID, size int64) {
+	if err := s.usageRepo.Refund(ctx, userID, size); err != nil {
+		s.log.Warn("メディアの使用量を差し引けませんでした", "error", err, "user_id", userID, "size", size)
+	}
+}
+
+// Store userIDのユーザーがアップロードしたファイルを保存してURLを返す（同じ内容のファイルが既にある場合は保存せずにそのURLを返す）
+func (s *MediaService) Store(ctx context.Context, userID uuid.UUID, filename string, content io.Reader) (string, error) {
+	// ハッシュを計算してから保存するため、内容をメモリに読み込む（サイズはアップロード時に検証済み）
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+
+	return s.store(ctx, userID, filename, data, func(object *models.MediaObject) {
+		s.describeImage(object, data)
+	})
+}
+
+// StoreVideo MP4・MOV形式の動画を再生時間を検証して保存し、URLを返す
+// 再生時間と映像の大きさを記録し、ffmpegが設定されている場合は最初のフレームをサムネイルとして保存する
+func (s *MediaService) StoreVideo(ctx context.Context, userID uuid.UUID, filename string, content io.Reader, maxDuration time.Duration) (string, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := mp4.Probe(data)
+	if err != nil {
+		s.log.Debug("動画として読み込めません", "filename", filename, "error", err)
+		return "", ErrInvalidVideo
+	}
+	if info.Duration > maxDuration {
+		return "", ErrVideoTooLong
+	}
+
+	return s.store(ctx, userID, filename, data, func(object *models.MediaObject) {
+		s.describeVideo(ctx, object, data, info)
+	})
+}
+
+// store 内容のハッシュで重複排除してファイルを保存し、userIDのユーザーのアップロードとして記録する（describeは新しく保存する場合のみ呼ばれる）
+func (s *MediaService) store(ctx context.Context, userID uuid.UUID, filename string, data []byte, describe func(object *models.MediaObject)) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	object, err := s.mediaRepo.Acquire(ctx, hash, userID)
+	if err == nil {
+		s.log.Debug("同じ内容のメディアを再利用しました", "hash", hash, "url", object.URL, "ref_count", object.RefCount)
+		return object.URL, nil
+	}
+	if err.Error() != "media object not found" {
+		return "", err
+	}
+
+	// パスは内容から決まるため、同じ内容が同時にアップロードされても同じファイルを書き込むだけになる
+	path := fmt.Sprintf("%s%s/%s%s", mediaPathPrefix, hash[:2], hash, strings.ToLower(filepath.Ext(filename)))
+	fileURL, err := s.storage.WriteFile(ctx, path, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	object = models.NewMediaObject(hash, fileURL, int64(len(data)))
+	object.MimeType = detectMimeType(filename, data)
+	describe(object)
+	if err := s.mediaRepo.Create(ctx, object, userID); err != nil {
+		if err.Error() != "media object already exists" {
+			return "", err
+		}
+
+		// 先に登録されたメディアを使い、拡張子の違いで別のパスに書き込んだファイルは削除する
+		object, err := s.mediaRepo.Acquire(ctx, hash, userID)
+		if err != nil {
+			return "", err
+		}
+		if object.URL != fileURL {
+			if err := s.storage.DeleteFile(ctx, path); err != nil {
+				s.log.Warn("重複したメディアファイルの削除に失敗しました", "error", err, "path", path)
+			}
+		}
+		return object.URL, nil
+	}
+
+	return fileURL, nil
+}
+
+// detectMimeType ファイルの内容からMIMEタイプを判定する（内容から判定できない場合は拡張子から判定する）
+func detectMimeType(filename string, data []byte) string {
+	mimeType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	if mimeType == "application/octet-stream" || strings.HasPrefix(mimeType, "text/") {
+		if byExtension := models.MimeTypeFromExtension(filepath.Ext(filename)); byExtension != "" {
+			return byExtension
+		}
+	}
+	return mimeType
+}
+
+// describeImage 画像の大きさとプレースホルダーを設定する（画像として読み込めないファイルは空のままにする）
+func (s *MediaService) describeImage(object *models.MediaObject, data []byte) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		s.log.Debug("画像として読み込めないため、プレースホルダーを生成しません", "hash", object.Hash, "error", err)
+		return
+	}
+	if config.Width > maxMediaImageDimension || config.Height > maxMediaImageDimension {
+		s.log.Debug("画像が大きすぎるため、プレースホルダーを生成しません", "hash", object.Hash, "width", config.Width, "height", config.Height)
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		s.log.Debug("画像として読み込めないため、プレースホルダーを生成しません", "hash", object.Hash, "error", err)
+		return
+	}
+
+	hash, err := blurhash.Encode(img, mediaBlurhashXComponents, mediaBlurhashYComponents)
+	if err != nil {
+		s.log.Warn("プレースホルダーの生成に失敗しました", "hash", object.Hash, "error", err)
+		return
+	}
+
+	object.Blurhash = hash
+	object.Width = img.Bounds().Dx()
+	object.Height = img.Bounds().Dy()
+}
+
+// describeVideo 動画の再生時間と映像の大きさを設定し、サムネイルを保存する
+// サムネイルを抽出できない場合はサムネイルとプレースホルダーを空のままにする
+func (s *MediaService) describeVideo(ctx context.Context, object *models.MediaObject, data []byte, info *mp4.Info) {
+	object.DurationMS = int(info.Duration.Milliseconds())
+
+	if s.ffmpegPath != "" {
+		thumbnail, err := s.extractThumbnail(ctx, data)
+		if err != nil {
+			s.log.Warn("動画のサムネイルの抽出に失敗しました", "hash", object.Hash, "error", err)
+		} else {
+			// サムネイルのパスは内容から決まるため、同じ動画のアップロードが重なっても同じファイルになる
+			path := fmt.Sprintf("%s%s/%s_thumb.jpg", mediaPathPrefix, object.Hash[:2], object.Hash)
+			thumbnailURL, err := s.storage.WriteFile(ctx, path, bytes.NewReader(thumbnail))
+			if err != nil {
+				s.log.Warn("動画のサムネイルの保存に失敗しました", "hash", object.Hash, "error", err)
+			} else {
+				object.ThumbnailURL = thumbnailURL
+				s.describeImage(object, thumbnail)
+			}
+		}
+	}
+
+	// 映像の大きさはサムネイルより動画のヘッダーを優先する（回転などでサムネイルと異なる場合がある）
+	if info.Width > 0 && info.Height > 0 {
+		object.Width = info.Width
+		object.Height = info.Height
+	}
+}
+
+// extractThumbnail ffmpegで動画の最初のフレームをJPEG画像として取り出す
+func (s *MediaService) extractThumbnail(ctx context.Context, data []byte) ([]byte, error) {
+	// MP4はファイルの末尾にヘッダーがある場合があり、パイプからは読み込めないため一時ファイルに書き出す
+	file, err := os.CreateTemp("", "gox-video-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, videoThumbnailTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.ffmpegPath,
+		"-hide_banner", "-loglevel", "error",
+		"-i", file.Name(),
+		"-frames:v", "1", "-f", "image2pipe", "-c:v", "mjpeg",
+		"pipe:1",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() == 0 {
+		return nil, errors.New("ffmpeg: no frame extracted")
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Attachment アップロードしたメディアの情報を返す（取得に失敗した場合はURLと種類のみを返す）
+func (s *MediaService) Attachment(ctx context.Context, fileURL string) models.MediaAttachment {
+	objects, err := s.mediaRepo.ListByURLs(ctx, []string{fileURL})
+	if err != nil {
+		s.log.Warn("メディアの情報の取得に失敗しました", "error", err, "url", fileURL)
+	}
+	if len(objects) > 0 {
+		return objects[0].Attachment()
+	}
+	return models.MediaAttachment{URL: fileURL, Type: models.MediaTypeFromURL(fileURL), MimeType: models.MimeTypeFromURL(fileURL)}
+}
+
+// Attachments 投稿の添付メディア（プレースホルダーと画像の大きさを含む）を投稿IDごとに返す
+// 重複排除の導入前のメディアや外部のURL、取得に失敗した場合はURLのみを返す
+func (s *MediaService) Attachments(ctx context.Context, posts []*models.Post) map[uuid.UUID][]models.MediaAttachment {
+	urls := make([]string, 0)
+	for _, post := range posts {
+		urls = append(urls, post.MediaURLs...)
+	}
+
+	objects := make(map[string]*models.MediaObject)
+	if len(urls) > 0 {
+		found, err := s.mediaRepo.ListByURLs(ctx, urls)
+		if err != nil {
+			s.log.Warn("添付メディアの情報の取得に失敗しました", "error", err)
+		}
+		for _, object := range found {
+			objects[object.URL] = object
+		}
+	}
+
+	attachments := make(map[uuid.UUID][]models.MediaAttachment, len(posts))
+	for _, post := range posts {
+		media := make([]models.MediaAttachment, 0, len(post.MediaURLs))
+		for i, mediaURL := range post.MediaURLs {
+			attachment := models.MediaAttachment{
+				URL:      mediaURL,
+				Type:     models.MediaTypeFromURL(mediaURL),
+				MimeType: models.MimeTypeFromURL(mediaURL),
+			}
+			if object, ok := objects[mediaURL]; ok {
+				attachment = object.Attachment()
+			}
+			attachment.AltText = post.AltText(i)
+			media = append(media, attachment)
+		}
+		attachments[post.ID] = media
+	}
+
+	return attachments
+}
+
+// PostAttachments 1件の投稿の添付メディアを返す
+func (s *MediaService) PostAttachments(ctx context.Context, post *models.Post) []models.MediaAttachment {
+	return s.Attachments(ctx, []*models.Post{post})[post.ID]
+}
+
+// UploadedBy このストレージのURLがすべてuserIDのユーザーがアップロードしたメディアか確認する（外部のURLは確認しない）
+// 他のユーザーがアップロードしたファイルと、アップロードしたユーザーが記録されていない（記録の導入前に保存された）ファイルは拒否する
+func (s *MediaService) UploadedBy(ctx context.Context, userID uuid.UUID, urls []string) (bool, error) {
+	stored := make([]string, 0, len(urls))
+	for _, fileURL := range urls {
+		if _, ok := s.storage.PathFromURL(fileURL); ok {
+			stored = append(stored, fileURL)
+		}
+	}
+	if len(stored) == 0 {
+		return true, nil
+	}
+
+	uploaded, err := s.mediaRepo.ListUploadedURLs(ctx, userID, stored)
+	if err != nil {
+		return false, err
+	}
+
+	uploadedSet := make(map[string]bool, len(uploaded))
+	for _, fileURL := range uploaded {
+		uploadedSet[fileURL] = true
+	}
+	for _, fileURL := range stored {
+		if !uploadedSet[fileURL] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ReleaseFor userIDのユーザーがメディアを使わなくなったときに呼び、そのユーザーのアップロードへの参照を1つ外して使用量から差し引く
+// 使用量はアップロードしたユーザーにのみ戻す（他のユーザーがアップロードしたメディアを外しても、参照数と使用量は変わらない）
+// postIDはメディアを外す投稿（投稿以外から外す場合はuuid.Nil）で、他の投稿・編集履歴・プロフィール画像から使われているファイルは残し、
+// 使われなくなった後の掃除で削除する。このサービスが登録していないファイル（外部のURLや重複排除の導入前に保存されたファイル）は削除しない
+func (s *MediaService) ReleaseFor(ctx context.Context, userID uuid.UUID, fileURL string, postID uuid.UUID) error {
+	path, ok := s.storage.PathFromURL(fileURL)
+	if !ok {
+		return nil
+	}
+
+	deleteObject := func(ctx context.Context) error {
+		return s.storage.DeleteFile(ctx, path)
+	}
+
+	release, err := s.mediaRepo.Release(ctx, fileURL, userID, postID, deleteObject)
+	if err != nil {
+		if err.Error() == "media object not found" {
+			return nil
+		}
+		return err
+	}
+
+	if release.Refunded {
+		s.RefundStorage(ctx, userID, release.Object.Size)
+	}
+	// 動画のサムネイルは動画と一緒に削除する
+	if release.Deleted {
+		s.deleteThumbnail(ctx, release.Object)
+	}
+
+	s.log.Debug("メディアへの参照を外しました", "url", fileURL, "user_id", userID, "ref_count", release.Object.RefCount, "deleted", release.Deleted)
+	return nil
+}
+
+// SweepOrphans アップロードされた後どこからも参照されずにbefore以前から残っているメディアを最大limit件削除し、削除した件数を返す
+// 使用量に数えていたアップロードは、アップロードしたユーザーの使用量から差し引く
+func (s *MediaService) SweepOrphans(ctx context.Context, before time.Time, limit int) (int, error) {
+	objects, err := s.mediaRepo.ListOrphaned(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, object := range objects {
+		ok, err := s.deleteOrphan(ctx, object, before, false)
+		if err != nil {
+			return deleted, err
+		}
+		if ok {
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// PurgeOrphans SweepOrphansと同じ処理で参照されていないメディアを最大limit件削除し、件数と対象のURLをresultに記録する（管理者向けの一括操作用）
+// RunBulkActionのfnから呼び出す。ファイルはtxManager.AfterCommitで削除するため、ドライランでは削除されない
+func (s *MediaService) PurgeOrphans(ctx context.Context, txManager repointerfaces.TxManager, before time.Time, limit int, result *BulkActionResult) error {
+	objects, err := s.mediaRepo.ListOrphaned(ctx, before, limit)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		ok, err := s.deleteOrphan(ctx, object, before, true)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		result.Rows++
+		result.AddSample(object.URL)
+
+		for _, fileURL := range []string{object.URL, object.ThumbnailURL} {
+			path, managed := s.storage.PathFromURL(fileURL)
+			if fileURL == "" || !managed {
+				continue
+			}
+			result.Files++
+			txManager.AfterCommit(ctx, func() {
+				if err := s.storage.DeleteFile(context.Background(), path); err != nil {
+					s.log.Warn("参照されていないメディアのファイルの削除に失敗しました", "error", err, "url", fileURL)
+				}
+			})
+		}
+	}
+
+	return nil
+}
+
+// deleteOrphan 参照されていないメディアを削除し、アップロードしたユーザーの使用量から差し引く
+// 一覧の取得後に参照された場合は削除せずにfalseを返す。keepFilesがtrueの場合はファイルを削除しない
+func (s *MediaService) deleteOrphan(ctx context.Context, object *models.MediaObject, before time.Time, keepFiles bool) (bool, error) {
+	deleteObject := func(ctx context.Context) error {
+		if path, ok := s.storage.PathFromURL(object.URL); ok && !keepFiles {
+			return s.storage.DeleteFile(ctx, path)
+		}
+		return nil
+	}
+
+	uploads, err := s.mediaRepo.DeleteOrphan(ctx, object.ID, before, deleteObject)
+	if err != nil {
+		if err.Error() == "media object not found" {
+			return false, nil
+		}
+		return false, err
+	}
+	if !keepFiles {
+		s.deleteThumbnail(ctx, object)
+	}
+	for _, upload := range uploads {
+		for i := 0; i < upload.UploadCount; i++ {
+			s.RefundStorage(ctx, upload.UserID, object.Size)
+		}
+	}
+	return true, nil
+}
+
+// deleteThumbnail 削除した動画のサムネイルを削除する（失敗した場合はログに残す）
+func (s *MediaService) deleteThumbnail(ctx context.Context, object *models.MediaObject) {
+	if object.ThumbnailURL == "" {
+		return
+	}
+	if thumbnailPath, ok := s.storage.PathFromURL(object.ThumbnailURL); ok {
+		if err := s.storage.DeleteFile(ctx, thumbnailPath); err != nil {
+			s.log.Warn("動画のサムネイルの削除に失敗しました", "error", err, "url", object.ThumbnailURL)
+		}
+	}
+}
@@ -6,9 +6,12 @@ import (
 
 	"github.com/TakuyaAizawa/gox/internal/domain/models"
 	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/telemetry"
 	"github.com/TakuyaAizawa/gox/internal/websocket"
 	"github.com/TakuyaAizawa/gox/pkg/logger"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // NotificationService 通知関連のビジネスロジックを管理するサービス
@@ -16,7 +19,10 @@ type NotificationService struct {
 	notificationRepo interfaces.NotificationRepository
 	userRepo         interfaces.UserRepository
 	postRepo         interfaces.PostRepository
-	hub              *websocket.Hub
+	blockRepo        interfaces.BlockRepository
+	settingsRepo     interfaces.NotificationSettingsRepository
+	txManager        interfaces.TxManager
+	outbox           *OutboxService
 	log              logger.Logger
 }
 
@@ -25,22 +31,28 @@ func NewNotificationService(
 	notificationRepo interfaces.NotificationRepository,
 	userRepo interfaces.UserRepository,
 	postRepo interfaces.PostRepository,
-	hub *websocket.Hub,
+	blockRepo interfaces.BlockRepository,
+	settingsRepo interfaces.NotificationSettingsRepository,
+	txManager interfaces.TxManager,
+	outbox *OutboxService,
 	log logger.Logger,
 ) *NotificationService {
 	return &NotificationService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
 		postRepo:         postRepo,
-		hub:              hub,
+		blockRepo:        blockRepo,
+		settingsRepo:     settingsRepo,
+		txManager:        txManager,
+		outbox:           outbox,
 		log:              log,
 	}
 }
 
 // CreateLikeNotification いいね通知を作成する
 func (s *NotificationService) CreateLikeNotification(ctx context.Context, actorID, recipientID uuid.UUID, postID uuid.UUID) error {
-	// 自分自身へのいいねは通知しない
-	if actorID == recipientID {
+	// 自分自身へのいいね、受信者が無効にしている場合は通知しない
+	if actorID == recipientID || !s.notificationEnabled(ctx, recipientID, models.NotificationTypeLike) {
 		return nil
 	}
 
@@ -66,12 +78,6 @@ func (s *NotificationService) CreateLikeNotification(ctx context.Context, actorI
 		&postID,
 	)
 
-	err = s.notificationRepo.Create(ctx, notification)
-	if err != nil {
-		s.log.Error("いいね通知: 保存エラー", "error", err)
-		return err
-	}
-
 	// WebSocket通知の作成
 	notificationEvent := websocket.NotificationEvent{
 		ID:        notification.ID,
@@ -90,12 +96,10 @@ func (s *NotificationService) CreateLikeNotification(ctx context.Context, actorI
 		},
 	}
 
-	// WebSocketを通じて通知を送信
-	message := websocket.NewNotificationMessage(notificationEvent)
-	err = s.hub.NotifyUser(recipientID, message)
-	if err != nil {
-		s.log.Warn("WebSocket通知の送信に失敗しました", "error", err)
-		// WebSocket送信の失敗は処理を続行
+	// 通知を保存し、WebSocketとプッシュ通知を通じて送信する
+	if err := s.saveNotification(ctx, notification, "", notificationEvent); err != nil {
+		s.log.Error("いいね通知: 保存エラー", "error", err)
+		return err
 	}
 
 	return nil
@@ -103,8 +107,8 @@ func (s *NotificationService) CreateLikeNotification(ctx context.Context, actorI
 
 // CreateFollowNotification フォロー通知を作成する
 func (s *NotificationService) CreateFollowNotification(ctx context.Context, actorID, recipientID uuid.UUID) error {
-	// 自分自身へのフォローは通知しない
-	if actorID == recipientID {
+	// 自分自身へのフォロー、受信者が無効にしている場合は通知しない
+	if actorID == recipientID || !s.notificationEnabled(ctx, recipientID, models.NotificationTypeFollow) {
 		return nil
 	}
 
@@ -123,12 +127,6 @@ func (s *NotificationService) CreateFollowNotification(ctx context.Context, acto
 		nil,
 	)
 
-	err = s.notificationRepo.Create(ctx, notification)
-	if err != nil {
-		s.log.Error("フォロー通知: 保存エラー", "error", err)
-		return err
-	}
-
 	// WebSocket通知の作成
 	notificationEvent := websocket.NotificationEvent{
 		ID:        notification.ID,
@@ -143,12 +141,132 @@ func (s *NotificationService) CreateFollowNotification(ctx context.Context, acto
 		},
 	}
 
-	// WebSocketを通じて通知を送信
-	message := websocket.NewNotificationMessage(notificationEvent)
-	err = s.hub.NotifyUser(recipientID, message)
+	// 通知を保存し、WebSocket・プッシュ通知・個人用Webhookを通じて送信する（オフラインの場合はメールでも送信）
+	if err := s.saveNotification(ctx, notification, models.WebhookEventFollow, notificationEvent); err != nil {
+		s.log.Error("フォロー通知: 保存エラー", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateMentionNotifications 投稿の本文で「@ユーザー名」によりメンションされたユーザーにメンション通知を作成する
+// 存在しないユーザー・投稿者本人・投稿者とブロック関係にあるユーザーには通知しない
+func (s *NotificationService) CreateMentionNotifications(ctx context.Context, post *models.Post) error {
+	usernames := models.ExtractMentions(post.Content)
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	// アクターユーザー情報の取得
+	actor, err := s.userRepo.GetByID(ctx, post.UserID)
 	if err != nil {
-		s.log.Warn("WebSocket通知の送信に失敗しました", "error", err)
-		// WebSocket送信の失敗は処理を続行
+		s.log.Error("メンション通知: アクターユーザー取得エラー", "error", err)
+		return err
+	}
+
+	for _, username := range usernames {
+		recipient, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			if err.Error() == "user not found" {
+				continue
+			}
+			s.log.Error("メンション通知: メンション先ユーザー取得エラー", "error", err)
+			return err
+		}
+		if recipient.ID == actor.ID || !s.notificationEnabled(ctx, recipient.ID, models.NotificationTypeMention) {
+			continue
+		}
+
+		blocked, err := s.blockRepo.IsBlockedEither(ctx, actor.ID, recipient.ID)
+		if err != nil {
+			s.log.Error("メンション通知: ブロック状態の確認エラー", "error", err)
+			return err
+		}
+		if blocked {
+			continue
+		}
+
+		// 通知レコードの作成
+		notification := models.NewNotification(
+			recipient.ID,
+			actor.ID,
+			models.NotificationTypeMention,
+			&post.ID,
+		)
+
+		// WebSocket通知の作成
+		notificationEvent := websocket.NotificationEvent{
+			ID:        notification.ID,
+			Type:      websocket.EventTypeMention,
+			CreatedAt: notification.CreatedAt,
+			Message:   fmt.Sprintf("%sさんがあなたをメンションしました", actor.Name),
+			Actor: websocket.ActorInfo{
+				ID:          actor.ID,
+				Username:    actor.Username,
+				DisplayName: actor.Name,
+				AvatarURL:   actor.ProfileImage,
+			},
+			Post: &websocket.PostInfo{
+				ID:      post.ID,
+				Content: truncateString(post.Content, 50),
+			},
+		}
+
+		// 通知を保存し、WebSocket・プッシュ通知・個人用Webhookを通じて送信する（オフラインの場合はメールでも送信）
+		if err := s.saveNotification(ctx, notification, models.WebhookEventMention, notificationEvent); err != nil {
+			s.log.Error("メンション通知: 保存エラー", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreatePostRemovedNotification モデレーターにより投稿が削除されたことを投稿者に通知する
+// actorIDは通知の送信者として表示するアカウント（通常はシステムアカウント）
+func (s *NotificationService) CreatePostRemovedNotification(ctx context.Context, actorID, recipientID uuid.UUID, postID uuid.UUID, reason string) error {
+	// アクターユーザー情報の取得
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		s.log.Error("投稿削除通知: アクターユーザー取得エラー", "error", err)
+		return err
+	}
+
+	// 通知レコードの作成
+	notification := models.NewNotification(
+		recipientID,
+		actorID,
+		models.NotificationTypePostRemoved,
+		&postID,
+	)
+
+	message := "あなたの投稿はガイドラインに違反しているため削除されました"
+	if reason != "" {
+		message = fmt.Sprintf("%s（理由: %s）", message, reason)
+	}
+
+	// WebSocket通知の作成（削除された投稿の本文は含めない）
+	notificationEvent := websocket.NotificationEvent{
+		ID:        notification.ID,
+		Type:      websocket.EventTypeSystem,
+		CreatedAt: notification.CreatedAt,
+		Message:   message,
+		Actor: websocket.ActorInfo{
+			ID:          actor.ID,
+			Username:    actor.Username,
+			DisplayName: actor.Name,
+			AvatarURL:   actor.ProfileImage,
+		},
+		Post: &websocket.PostInfo{
+			ID: postID,
+		},
+	}
+
+	// 通知を保存し、WebSocketとプッシュ通知を通じて送信する
+	if err := s.saveNotification(ctx, notification, "", notificationEvent); err != nil {
+		s.log.Error("投稿削除通知: 保存エラー", "error", err)
+		return err
 	}
 
 	return nil
@@ -156,8 +274,19 @@ func (s *NotificationService) CreateFollowNotification(ctx context.Context, acto
 
 // CreateReplyNotification 返信通知を作成する
 func (s *NotificationService) CreateReplyNotification(ctx context.Context, actorID, recipientID uuid.UUID, postID, replyID uuid.UUID) error {
-	// 自分自身への返信は通知しない
-	if actorID == recipientID {
+	return s.createReplyNotification(ctx, actorID, recipientID, replyID, "%sさんがあなたの投稿に返信しました")
+}
+
+// CreateConversationReplyNotification 参加している会話への返信通知を作成する
+func (s *NotificationService) CreateConversationReplyNotification(ctx context.Context, actorID, recipientID uuid.UUID, replyID uuid.UUID) error {
+	return s.createReplyNotification(ctx, actorID, recipientID, replyID, "%sさんがあなたの参加している会話に返信しました")
+}
+
+// createReplyNotification 返信通知を作成し、WebSocketで送信する
+// messageFormatにはアクターの表示名が埋め込まれる
+func (s *NotificationService) createReplyNotification(ctx context.Context, actorID, recipientID uuid.UUID, replyID uuid.UUID, messageFormat string) error {
+	// 自分自身への返信、受信者が無効にしている場合は通知しない
+	if actorID == recipientID || !s.notificationEnabled(ctx, recipientID, models.NotificationTypeReply) {
 		return nil
 	}
 
@@ -183,18 +312,12 @@ func (s *NotificationService) CreateReplyNotification(ctx context.Context, actor
 		&replyID,
 	)
 
-	err = s.notificationRepo.Create(ctx, notification)
-	if err != nil {
-		s.log.Error("返信通知: 保存エラー", "error", err)
-		return err
-	}
-
 	// WebSocket通知の作成
 	notificationEvent := websocket.NotificationEvent{
 		ID:        notification.ID,
 		Type:      websocket.EventTypeReply,
 		CreatedAt: notification.CreatedAt,
-		Message:   fmt.Sprintf("%sさんがあなたの投稿に返信しました", actor.Name),
+		Message:   fmt.Sprintf(messageFormat, actor.Name),
 		Actor: websocket.ActorInfo{
 			ID:          actor.ID,
 			Username:    actor.Username,
@@ -207,17 +330,126 @@ func (s *NotificationService) CreateReplyNotification(ctx context.Context, actor
 		},
 	}
 
-	// WebSocketを通じて通知を送信
-	message := websocket.NewNotificationMessage(notificationEvent)
-	err = s.hub.NotifyUser(recipientID, message)
+	// 通知を保存し、WebSocketとプッシュ通知を通じて送信する
+	if err := s.saveNotification(ctx, notification, "", notificationEvent); err != nil {
+		s.log.Error("返信通知: 保存エラー", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateSavedSearchNotification 保存した検索に一致する新しい投稿があることを、検索を保存したユーザーに通知する
+func (s *NotificationService) CreateSavedSearchNotification(ctx context.Context, search *models.SavedSearch, post *models.Post) error {
+	// 受信者が無効にしている場合は通知しない
+	if !s.notificationEnabled(ctx, search.UserID, models.NotificationTypeSavedSearch) {
+		return nil
+	}
+
+	// 投稿者をアクターとして表示する
+	actor, err := s.userRepo.GetByID(ctx, post.UserID)
 	if err != nil {
-		s.log.Warn("WebSocket通知の送信に失敗しました", "error", err)
-		// WebSocket送信の失敗は処理を続行
+		s.log.Error("保存した検索の通知: アクターユーザー取得エラー", "error", err)
+		return err
+	}
+
+	// 通知レコードの作成
+	notification := models.NewNotification(
+		search.UserID,
+		post.UserID,
+		models.NotificationTypeSavedSearch,
+		&post.ID,
+	)
+
+	// WebSocket通知の作成
+	notificationEvent := websocket.NotificationEvent{
+		ID:        notification.ID,
+		Type:      websocket.EventTypeSavedSearch,
+		CreatedAt: notification.CreatedAt,
+		Message:   fmt.Sprintf("保存した検索「%s」に一致する新しい投稿があります", search.Query),
+		Actor: websocket.ActorInfo{
+			ID:          actor.ID,
+			Username:    actor.Username,
+			DisplayName: actor.Name,
+			AvatarURL:   actor.ProfileImage,
+		},
+		Post: &websocket.PostInfo{
+			ID:      post.ID,
+			Content: truncateString(post.Content, 50),
+		},
+	}
+
+	// 通知を保存し、WebSocketとプッシュ通知を通じて送信する
+	if err := s.saveNotification(ctx, notification, "", notificationEvent); err != nil {
+		s.log.Error("保存した検索の通知: 保存エラー", "error", err)
+		return err
 	}
 
 	return nil
 }
 
+// MarkAsRead ユーザーの通知を既読にし、残りの未読通知数を返す（notificationIDがnilの場合はすべての通知を既読にする）
+// 他のユーザーの通知を指定した場合は、存在しない場合と同じく "notification not found" のエラーを返す
+func (s *NotificationService) MarkAsRead(ctx context.Context, userID uuid.UUID, notificationID *uuid.UUID) (int64, error) {
+	var err error
+	if notificationID != nil {
+		err = s.notificationRepo.MarkAsReadForUser(ctx, *notificationID, userID)
+	} else {
+		err = s.notificationRepo.MarkAllAsRead(ctx, userID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return s.notificationRepo.CountUnreadByUserID(ctx, userID)
+}
+
+// notificationEnabled 受信者がその種類の通知を受け取る設定かを返す
+// 設定を取得できない場合は通知を失わないよう受け取るものとして扱う
+func (s *NotificationService) notificationEnabled(ctx context.Context, recipientID uuid.UUID, notificationType models.NotificationType) bool {
+	settings, err := s.settingsRepo.Get(ctx, recipientID)
+	if err != nil {
+		s.log.Warn("通知設定の取得に失敗しました", "error", err, "user_id", recipientID, "type", notificationType)
+		return true
+	}
+	return settings.IsEnabled(notificationType)
+}
+
+// saveNotification 通知を保存し、同じトランザクションで配信イベントをアウトボックスに保存する
+// 配信はコミット後にバックグラウンドで行い、失敗した場合は再試行する（webhookEventが空の場合はWebhookへ送信しない）
+func (s *NotificationService) saveNotification(ctx context.Context, notification *models.Notification, webhookEvent models.WebhookEvent, event websocket.NotificationEvent) (err error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "notification.save", trace.WithAttributes(
+		attribute.String("notification.type", string(notification.Type)),
+		attribute.String("notification.recipient_id", notification.UserID.String()),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			return err
+		}
+		return s.outbox.EnqueueNotification(ctx, notification.UserID, notification.Type, webhookEvent, event)
+	})
+}
+
+// newPushMessage 通知イベントから端末に表示するプッシュ通知を作成する
+// アプリが通知の対象を開けるよう、通知・アクター・投稿のIDをデータとして含める
+func newPushMessage(event websocket.NotificationEvent) *models.PushMessage {
+	message := &models.PushMessage{
+		Title: event.Message,
+		Data: map[string]string{
+			"notification_id": event.ID.String(),
+			"type":            string(event.Type),
+			"actor_id":        event.Actor.ID.String(),
+		},
+	}
+	if event.Post != nil {
+		message.Body = event.Post.Content
+		message.Data["post_id"] = event.Post.ID.String()
+	}
+	return message
+}
+
 // 文字列を指定の長さで切り詰める補助関数
 func truncateString(s string, maxLength int) string {
 	if len(s) <= maxLength {
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// おすすめのユーザーの理由
+const (
+	// 運営が選んだアカウント
+	SuggestionReasonStaffPick = "staff_pick"
+	// フォロワーの多いアカウント
+	SuggestionReasonPopular = "popular"
+)
+
+// オンボーディングの手順
+const (
+	OnboardingStepProfile = "profile"
+	OnboardingStepFollow  = "follow"
+	OnboardingStepPost    = "post"
+)
+
+// おすすめから除外するフォロー中のユーザーを取得する上限
+const onboardingFollowingLimit = 1000
+
+// FollowSuggestion おすすめのユーザー
+type FollowSuggestion struct {
+	User   *models.User
+	Reason string
+}
+
+// OnboardingStep オンボーディングの手順と完了したかどうか
+type OnboardingStep struct {
+	Name      string
+	Completed bool
+}
+
+// OnboardingProgress オンボーディングの進捗
+type OnboardingProgress struct {
+	Steps     []OnboardingStep
+	Completed bool
+	// フォロー中のユーザー数と、フォローの手順の完了に必要な数
+	FollowingCount  int
+	RequiredFollows int
+}
+
+// OnboardingService 新しいユーザーのオンボーディング（おすすめのユーザー・自動フォロー・進捗）を管理するサービス
+// 登録直後のホームタイムラインが空にならないよう、設定されたアカウントを自動でフォローし、フォローするユーザーを提案する
+// システムアカウント（お知らせなど）の投稿はフォローしなくても全ユーザーのホームタイムラインに表示されるため、提案・自動フォローの対象にしない
+type OnboardingService struct {
+	userRepo   interfaces.UserRepository
+	followRepo interfaces.FollowRepository
+	blockRepo  interfaces.BlockRepository
+	// 運営が選んだおすすめのアカウントと、登録時に自動でフォローするアカウント（ユーザー名）
+	suggestedAccounts  []string
+	autoFollowAccounts []string
+	suggestionLimit    int
+	minFollows         int
+	log                logger.Logger
+}
+
+// NewOnboardingService 新しいオンボーディングサービスを作成する
+func NewOnboardingService(
+	userRepo interfaces.UserRepository,
+	followRepo interfaces.FollowRepository,
+	blockRepo interfaces.BlockRepository,
+	suggestedAccounts []string,
+	autoFollowAccounts []string,
+	suggestionLimit int,
+	minFollows int,
+	log logger.Logger,
+) *OnboardingService {
+	if suggestionLimit <= 0 {
+		suggestionLimit = 10
+	}
+	if minFollows < 0 {
+		minFollows = 0
+	}
+
+	return &OnboardingService{
+		userRepo:           userRepo,
+		followRepo:         followRepo,
+		blockRepo:          blockRepo,
+		suggestedAccounts:  suggestedAccounts,
+		autoFollowAccounts: autoFollowAccounts,
+		suggestionLimit:    suggestionLimit,
+		minFollows:         minFollows,
+		log:                log,
+	}
+}
+
+// SuggestionLimit 一度に提案するユーザー数の上限を返す
+func (s *OnboardingService) SuggestionLimit() int {
+	return s.suggestionLimit
+}
+
+// Welcome 新しいユーザーに自動フォローするアカウントをフォローさせる
+// 見つからない・無効化されたアカウントは飛ばし、失敗しても登録は続行できるようエラーはログに記録するのみ
+// フォローされたアカウントへの通知は作成しない
+func (s *OnboardingService) Welcome(ctx context.Context, userID uuid.UUID) {
+	for _, username := range s.autoFollowAccounts {
+		account, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			s.log.Warn("自動フォローするアカウントが見つかりません", "username", username, "error", err)
+			continue
+		}
+		if account.ID == userID || account.IsSystem {
+			continue
+		}
+
+		if err := s.followRepo.Follow(ctx, userID, account.ID); err != nil {
+			s.log.Error("アカウントの自動フォロー中にエラーが発生しました", "error", err, "user_id", userID, "username", username)
+			continue
+		}
+	}
+}
+
+// Suggestions フォローするユーザーを提案する
+// 運営が選んだアカウントを先に、残りをフォロワーの多いアカウントで埋める
+// 自分・フォロー中・ブロック関係にあるユーザーとシステムアカウントは含めない
+func (s *OnboardingService) Suggestions(ctx context.Context, userID uuid.UUID, limit int) ([]*FollowSuggestion, error) {
+	if limit <= 0 || limit > s.suggestionLimit {
+		limit = s.suggestionLimit
+	}
+
+	following, err := s.followRepo.GetFollowing(ctx, userID, uuid.Nil, 0, onboardingFollowingLimit)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := s.blockRepo.GetBlockRelatedUserIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[uuid.UUID]struct{}, len(following)+len(blocked)+1)
+	excluded[userID] = struct{}{}
+	for _, id := range append(following, blocked...) {
+		excluded[id] = struct{}{}
+	}
+
+	suggestions := make([]*FollowSuggestion, 0, limit)
+	seen := make(map[string]struct{}, len(s.suggestedAccounts))
+	for _, username := range s.suggestedAccounts {
+		if len(suggestions) >= limit {
+			break
+		}
+		if _, ok := seen[strings.ToLower(username)]; ok {
+			continue
+		}
+		seen[strings.ToLower(username)] = struct{}{}
+
+		account, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			if err.Error() != "user not found" {
+				return nil, err
+			}
+			continue
+		}
+		if _, ok := excluded[account.ID]; ok || account.IsSystem {
+			continue
+		}
+
+		excluded[account.ID] = struct{}{}
+		suggestions = append(suggestions, &FollowSuggestion{User: account, Reason: SuggestionReasonStaffPick})
+	}
+
+	if len(suggestions) < limit {
+		excludeIDs := make([]uuid.UUID, 0, len(excluded))
+		for id := range excluded {
+			excludeIDs = append(excludeIDs, id)
+		}
+
+		popular, err := s.userRepo.ListPopular(ctx, excludeIDs, limit-len(suggestions))
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range popular {
+			suggestions = append(suggestions, &FollowSuggestion{User: account, Reason: SuggestionReasonPopular})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// Progress オンボーディングの進捗を返す
+// プロフィールの設定（アイコンまたは自己紹介）、一定数のユーザーのフォロー、最初の投稿が完了したかどうかを確認する
+func (s *OnboardingService) Progress(ctx context.Context, userID uuid.UUID) (*OnboardingProgress, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// フォロー数は無効化されたアカウントを除いて数える
+	followingCount, err := s.followRepo.CountFollowing(ctx, userID, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &OnboardingProgress{
+		Steps: []OnboardingStep{
+			{Name: OnboardingStepProfile, Completed: user.ProfileImage != "" || strings.TrimSpace(user.Bio) != ""},
+			{Name: OnboardingStepFollow, Completed: int(followingCount) >= s.minFollows},
+			{Name: OnboardingStepPost, Completed: user.PostCount > 0},
+		},
+		FollowingCount:  int(followingCount),
+		RequiredFollows: s.minFollows,
+	}
+
+	progress.Completed = true
+	for _, step := range progress.Steps {
+		if !step.Completed {
+			progress.Completed = false
+			break
+		}
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/telemetry"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrDeliveryUnavailable 送信キューが満杯か停止中のため、配信を受け付けられなかった
+var ErrDeliveryUnavailable = errors.New("delivery queue unavailable")
+
+const (
+	// 取得したイベントを他の配信処理から隠す時間（配信処理が停止した場合はその後に再取得される）
+	outboxLease = time.Minute
+	// データベースの操作1回にかける最大時間
+	outboxTimeout = 10 * time.Second
+	// 失敗したイベントを再試行するまでの最大間隔
+	outboxMaxBackoff = time.Hour
+)
+
+// outboxNotification アウトボックスに保存する通知の配信内容
+type outboxNotification struct {
+	Type         models.NotificationType     `json:"type"`
+	WebhookEvent models.WebhookEvent         `json:"webhook_event,omitempty"`
+	Event        websocket.NotificationEvent `json:"event"`
+	// 通知を作成したリクエストのトレース（配信のスパンをその続きとして記録する）
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+}
+
+// OutboxService 通知の配信イベントを通知と同じトランザクションでアウトボックスに保存し、バックグラウンドで配信するサービス
+// 配信先ごとにイベントを保存するため、失敗した配信先のみを再試行する（同じ通知が重複して届く場合がある）
+type OutboxService struct {
+	outboxRepo   interfaces.OutboxRepository
+	txManager    interfaces.TxManager
+	hub          *websocket.Hub
+	webhooks     *WebhookService
+	push         *PushService
+	emails       *EmailNotificationService
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	retryDelay   time.Duration
+	log          logger.Logger
+
+	triggerCh chan struct{}
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+// NewOutboxService 新しいアウトボックスの配信サービスを作成する
+// webhooks・push・emailsがnilの場合は、その配信先へのイベントを保存しない
+func NewOutboxService(
+	outboxRepo interfaces.OutboxRepository,
+	txManager interfaces.TxManager,
+	hub *websocket.Hub,
+	webhooks *WebhookService,
+	push *PushService,
+	emails *EmailNotificationService,
+	pollInterval time.Duration,
+	batchSize int,
+	maxAttempts int,
+	retryDelay time.Duration,
+	log logger.Logger,
+) *OutboxService {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	if retryDelay <= 0 {
+		retryDelay = 5 * time.Second
+	}
+
+	return &OutboxService{
+		outboxRepo:   outboxRepo,
+		txManager:    txManager,
+		hub:          hub,
+		webhooks:     webhooks,
+		push:         push,
+		emails:       emails,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		maxAttempts:  maxAttempts,
+		retryDelay:   retryDelay,
+		log:          log,
+		triggerCh:    make(chan struct{}, 1),
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// Start 配信待ちのイベントの配信を開始する
+func (s *OutboxService) Start() {
+	go s.run()
+}
+
+// Stop 配信待ちのイベントの配信を停止する（残りのイベントは次回の起動時に配信する）
+func (s *OutboxService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// EnqueueNotification 通知の配信イベントを配信先ごとにアウトボックスに保存する
+// 通知を作成するトランザクション内で呼び出し、コミットされた場合のみ配信する
+// webhookEventが空の場合はWebhookへ送信せず、メールはメールで通知できる種類の場合のみ送信する
+func (s *OutboxService) EnqueueNotification(
+	ctx context.Context,
+	recipientID uuid.UUID,
+	notificationType models.NotificationType,
+	webhookEvent models.WebhookEvent,
+	event websocket.NotificationEvent,
+) error {
+	payload, err := json.Marshal(outboxNotification{
+		Type:         notificationType,
+		WebhookEvent: webhookEvent,
+		Event:        event,
+		TraceContext: telemetry.Inject(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	channels := []models.OutboxChannel{models.OutboxChannelWebSocket}
+	if s.push != nil {
+		channels = append(channels, models.OutboxChannelPush)
+	}
+	if s.emails != nil && models.IsEmailNotificationType(notificationType) {
+		channels = append(channels, models.OutboxChannelEmail)
+	}
+	if s.webhooks != nil && webhookEvent != "" {
+		channels = append(channels, models.OutboxChannelWebhook)
+	}
+
+	events := make([]*models.OutboxEvent, 0, len(channels))
+	for _, channel := range channels {
+		events = append(events, models.NewOutboxEvent(channel, recipientID, payload))
+	}
+	if err := s.outboxRepo.Create(ctx, events); err != nil {
+		return err
+	}
+
+	// 次の確認を待たずにコミット後すぐに配信する
+	s.txManager.AfterCommit(ctx, s.trigger)
+	return nil
+}
+
+// trigger 次の確認を待たずに配信待ちのイベントを配信する
+func (s *OutboxService) trigger() {
+	select {
+	case s.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+// run 停止されるまで一定間隔で配信待ちのイベントを配信する
+func (s *OutboxService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.processDue()
+	for {
+		select {
+		case <-ticker.C:
+			s.processDue()
+		case <-s.triggerCh:
+			s.processDue()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// processDue 配信予定時刻を過ぎたイベントがなくなるまで配信する
+func (s *OutboxService) processDue() {
+	for !s.stopping() {
+		ctx, cancel := context.WithTimeout(context.Background(), outboxTimeout)
+		events, err := s.outboxRepo.ClaimDue(ctx, s.batchSize, outboxLease)
+		cancel()
+		if err != nil {
+			s.log.Error("配信待ちのイベントの取得に失敗しました", "error", err)
+			return
+		}
+
+		for _, event := range events {
+			s.process(event)
+		}
+
+		if len(events) < s.batchSize {
+			return
+		}
+	}
+}
+
+// process イベントを配信し、成功した場合は削除、失敗した場合は再試行まで待機させる
+func (s *OutboxService) process(event *models.OutboxEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), outboxTimeout)
+	defer cancel()
+
+	cause := s.deliver(ctx, event)
+	if cause == nil {
+		if err := s.outboxRepo.Delete(ctx, event.ID); err != nil {
+			s.log.Error("配信したイベントの削除に失敗しました", "id", event.ID, "error", err)
+		}
+		return
+	}
+
+	if event.Attempts >= s.maxAttempts {
+		s.log.Error("イベントの配信に失敗しました",
+			"id", event.ID, "channel", event.Channel, "user_id", event.UserID, "attempts", event.Attempts, "error", cause)
+		if err := s.outboxRepo.MarkFailed(ctx, event.ID, cause.Error()); err != nil {
+			s.log.Error("配信に失敗したイベントの保存に失敗しました", "id", event.ID, "error", err)
+		}
+		return
+	}
+
+	backoff := s.retryDelay << (event.Attempts - 1)
+	if backoff <= 0 || backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	s.log.Warn("イベントの配信に失敗したため再試行します",
+		"id", event.ID, "channel", event.Channel, "user_id", event.UserID, "attempts", event.Attempts, "retry_in", backoff, "error", cause)
+	if err := s.outboxRepo.Retry(ctx, event.ID, time.Now().UTC().Add(backoff), cause.Error()); err != nil {
+		// 保存できない場合も取得の期限が切れた後に再取得される
+		s.log.Error("配信に失敗したイベントの保存に失敗しました", "id", event.ID, "error", err)
+	}
+}
+
+// deliver イベントを配信先へ渡す
+// 保存後に配信先が無効になった場合は配信済みとして扱う
+func (s *OutboxService) deliver(ctx context.Context, event *models.OutboxEvent) (err error) {
+	var notification outboxNotification
+	if err := json.Unmarshal(event.Payload, &notification); err != nil {
+		return fmt.Errorf("invalid outbox payload: %w", err)
+	}
+
+	_, span := telemetry.Tracer().Start(telemetry.Extract(ctx, notification.TraceContext), "outbox.deliver", trace.WithAttributes(
+		attribute.String("outbox.channel", string(event.Channel)),
+		attribute.Int("outbox.attempts", event.Attempts),
+		attribute.String("notification.type", string(notification.Type)),
+		attribute.String("notification.recipient_id", event.UserID.String()),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	switch event.Channel {
+	case models.OutboxChannelWebSocket:
+		// 切断中のユーザーにも再接続後に再送できるよう、連番を付けて送信する
+		return s.hub.DeliverNotification(event.UserID, websocket.NewNotificationMessage(notification.Event))
+	case models.OutboxChannelPush:
+		if s.push == nil {
+			return nil
+		}
+		return s.push.Notify(event.UserID, newPushMessage(notification.Event))
+	case models.OutboxChannelEmail:
+		if s.emails == nil {
+			return nil
+		}
+		return s.emails.Notify(event.UserID, notification.Type, notification.Event)
+	case models.OutboxChannelWebhook:
+		if s.webhooks == nil {
+			return nil
+		}
+		return s.webhooks.Dispatch(event.UserID, notification.WebhookEvent, notification.Event)
+	default:
+		return fmt.Errorf("unknown outbox channel: %s", event.Channel)
+	}
+}
+
+func (s *OutboxService) stopping() bool {
+	select {
+	case <-s.stopCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+// 1回の削除にかける最大時間
+const postExpirationTimeout = time.Minute
+
+// PostExpirationService 有効期限を過ぎた投稿を定期的に削除するワーカー
+// 期限切れの投稿は削除されるまでの間もリポジトリがすべての読み取りから除外するため、ここでは行と添付メディアの後片付けだけを行う
+type PostExpirationService struct {
+	postRepo     interfaces.PostRepository
+	media        *MediaService
+	threadUnroll *ThreadUnrollService
+	interval     time.Duration
+	batchSize    int
+	log          logger.Logger
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewPostExpirationService 新しい期限切れ投稿の削除ワーカーを作成する
+func NewPostExpirationService(
+	postRepo interfaces.PostRepository,
+	media *MediaService,
+	threadUnroll *ThreadUnrollService,
+	interval time.Duration,
+	batchSize int,
+	log logger.Logger,
+) *PostExpirationService {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &PostExpirationService{
+		postRepo:     postRepo,
+		media:        media,
+		threadUnroll: threadUnroll,
+		interval:     interval,
+		batchSize:    batchSize,
+		log:          log,
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// Start 定期的な削除を開始する
+func (s *PostExpirationService) Start() {
+	go s.run()
+}
+
+// Stop 定期的な削除を停止する
+func (s *PostExpirationService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// PurgeExpired 期限切れの投稿がなくなるまで削除し、削除した件数を返す
+// 削除した投稿の添付メディアへの参照を外し、投稿を含むスレッドのキャッシュを削除する
+func (s *PostExpirationService) PurgeExpired(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		posts, err := s.postRepo.PurgeExpired(ctx, s.batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += len(posts)
+
+		for _, post := range posts {
+			// 投稿は削除済みのため、メディアの解放に失敗してもログに残して続行する
+			for _, mediaURL := range post.MediaURLs {
+				if err := s.media.ReleaseFor(ctx, post.UserID, mediaURL, post.ID); err != nil {
+					s.log.Error("期限切れの投稿のメディアの削除に失敗しました", "error", err, "post_id", post.ID, "url", mediaURL)
+				}
+			}
+
+			if post.ReplyToID != nil {
+				s.threadUnroll.Invalidate(ctx, *post.ReplyToID)
+			}
+			s.threadUnroll.Invalidate(ctx, post.ID)
+		}
+
+		if len(posts) < s.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// run 停止されるまで一定間隔で期限切れの投稿を削除する
+func (s *PostExpirationService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.purge()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *PostExpirationService) purge() {
+	ctx, cancel := context.WithTimeout(context.Background(), postExpirationTimeout)
+	defer cancel()
+
+	purged, err := s.PurgeExpired(ctx)
+	if err != nil {
+		s.log.Error("期限切れの投稿の削除に失敗しました", "error", err)
+		return
+	}
+	if purged > 0 {
+		s.log.Info("期限切れの投稿を削除しました", "posts", purged)
+	}
+}
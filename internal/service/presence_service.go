@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// 処理待ちにできる接続・切断のイベントの数
+const presenceEventBufferSize = 1024
+
+// オンライン状態の記録・最終ログイン日時の書き込み1回にかける最大時間
+const presenceWriteTimeout = 5 * time.Second
+
+// presenceEvent ユーザーのこのインスタンスへの接続の有無の変化
+type presenceEvent struct {
+	userID uuid.UUID
+	online bool
+	at     time.Time
+}
+
+// Presence 他のユーザーに表示するオンライン状態と最終ログイン日時
+type Presence struct {
+	IsOnline   bool
+	LastSeenAt *time.Time
+}
+
+// PresenceService WebSocketの接続からユーザーのオンライン状態と最終ログイン日時を管理するサービス
+// 複数のインスタンスで動かす場合はオンライン状態を共有のストアに有効期限付きで記録し、
+// ストアがない場合はこのインスタンスへの接続のみでオンライン状態を判定する
+type PresenceService struct {
+	hub          *websocket.Hub
+	store        interfaces.PresenceStore
+	presenceRepo interfaces.PresenceRepository
+	// オンライン状態の有効期間と、接続中のユーザーの記録を延長する間隔
+	ttl             time.Duration
+	refreshInterval time.Duration
+	log             logger.Logger
+
+	events chan presenceEvent
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewPresenceService 新しいオンライン状態のサービスを作成し、ハブの接続・切断を受け取るよう設定する（ハブのRunの前に呼ぶ）
+// storeがnilの場合はこのインスタンスへの接続のみでオンライン状態を判定する
+func NewPresenceService(
+	hub *websocket.Hub,
+	store interfaces.PresenceStore,
+	presenceRepo interfaces.PresenceRepository,
+	ttl time.Duration,
+	refreshInterval time.Duration,
+	log logger.Logger,
+) *PresenceService {
+	if ttl <= 0 {
+		ttl = 90 * time.Second
+	}
+	if refreshInterval <= 0 || refreshInterval >= ttl {
+		refreshInterval = ttl / 3
+	}
+
+	s := &PresenceService{
+		hub:             hub,
+		store:           store,
+		presenceRepo:    presenceRepo,
+		ttl:             ttl,
+		refreshInterval: refreshInterval,
+		log:             log,
+		events:          make(chan presenceEvent, presenceEventBufferSize),
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+	}
+	hub.SetPresenceHandler(s.enqueue)
+	return s
+}
+
+// Start 接続・切断のイベントの処理と、接続中のユーザーの記録の定期的な延長を開始する
+func (s *PresenceService) Start() {
+	go s.run()
+}
+
+// Stop 処理待ちのイベントを処理してから停止する（ハブのShutdownの後に呼ぶ）
+func (s *PresenceService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// Get ユーザーのオンライン状態と最終ログイン日時を取得する
+// 表示する設定かどうかは呼び出し側で確認する
+func (s *PresenceService) Get(ctx context.Context, userID uuid.UUID) (Presence, error) {
+	if s.isOnline(ctx, userID) {
+		// 接続中の場合は現在を最終ログイン日時とする
+		now := time.Now().UTC()
+		return Presence{IsOnline: true, LastSeenAt: &now}, nil
+	}
+
+	lastSeenAt, err := s.presenceRepo.GetLastSeen(ctx, userID)
+	if err != nil {
+		return Presence{}, err
+	}
+	return Presence{LastSeenAt: lastSeenAt}, nil
+}
+
+// isOnline いずれかのインスタンスに接続中かを返す（ストアを確認できない場合はこのインスタンスへの接続のみで判定する）
+func (s *PresenceService) isOnline(ctx context.Context, userID uuid.UUID) bool {
+	if s.hub.IsOnline(userID) {
+		return true
+	}
+	if s.store == nil {
+		return false
+	}
+
+	online, err := s.store.IsOnline(ctx, userID)
+	if err != nil {
+		s.log.Warn("オンライン状態の確認に失敗しました", "error", err, "user_id", userID)
+		return false
+	}
+	return online
+}
+
+// enqueue ハブから接続・切断のイベントを受け取る（ハブのループを止めないよう、処理は別のgoroutineで行う）
+func (s *PresenceService) enqueue(userID uuid.UUID, online bool) {
+	select {
+	case s.events <- presenceEvent{userID: userID, online: online, at: time.Now().UTC()}:
+	default:
+		// 定期的な延長で補われるため、オンライン状態のずれは有効期間内に解消される
+		s.log.Warn("オンライン状態のイベントが多すぎるため破棄しました", "user_id", userID, "online", online)
+	}
+}
+
+// run 停止されるまでイベントを処理し、一定間隔で接続中のユーザーの記録を延長する
+func (s *PresenceService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-s.events:
+			s.handle(event)
+		case <-ticker.C:
+			s.refresh()
+		case <-s.stopCh:
+			for {
+				select {
+				case event := <-s.events:
+					s.handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle 接続したユーザーをオンラインとして記録し、切断したユーザーの最終ログイン日時を記録する
+func (s *PresenceService) handle(event presenceEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceWriteTimeout)
+	defer cancel()
+
+	if event.online {
+		if s.store != nil {
+			if err := s.store.Refresh(ctx, s.hub.InstanceID(), []uuid.UUID{event.userID}, s.ttl); err != nil {
+				s.log.Warn("オンライン状態の記録に失敗しました", "error", err, "user_id", event.userID)
+			}
+		}
+		return
+	}
+
+	if s.store != nil {
+		if err := s.store.Remove(ctx, s.hub.InstanceID(), event.userID); err != nil {
+			s.log.Warn("オンライン状態の削除に失敗しました", "error", err, "user_id", event.userID)
+		}
+	}
+	if err := s.presenceRepo.UpdateLastSeen(ctx, []uuid.UUID{event.userID}, event.at); err != nil {
+		s.log.Error("最終ログイン日時の記録に失敗しました", "error", err, "user_id", event.userID)
+	}
+}
+
+// refresh 接続中のユーザーのオンライン状態を延長し、最終ログイン日時を更新する
+// インスタンスが停止しても、最終ログイン日時は最後に延長した時刻が残る
+func (s *PresenceService) refresh() {
+	userIDs := s.hub.OnlineUsers()
+	if len(userIDs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceWriteTimeout)
+	defer cancel()
+
+	if s.store != nil {
+		if err := s.store.Refresh(ctx, s.hub.InstanceID(), userIDs, s.ttl); err != nil {
+			s.log.Warn("オンライン状態の延長に失敗しました", "error", err, "count", len(userIDs))
+		}
+	}
+	if err := s.presenceRepo.UpdateLastSeen(ctx, userIDs, time.Now().UTC()); err != nil {
+		s.log.Error("最終ログイン日時の更新に失敗しました", "error", err, "count", len(userIDs))
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // アバターとしてアップロードできる形式のデコーダーを登録
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/profilecard"
+	"github.com/TakuyaAizawa/gox/internal/util/qrcode"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// 生成したカードをキャッシュする期間（サポーターの期限切れなどを反映するため）
+	profileCardTTL = time.Hour
+	// キャッシュするカードの最大数
+	maxCachedProfileCards = 1000
+	// デコードを許可するアバター画像の最大の幅・高さ
+	maxAvatarDimension = 4096
+)
+
+// ProfileCard 生成済みのプロフィールカード画像
+type ProfileCard struct {
+	PNG  []byte
+	ETag string
+	// カードの内容から計算したバージョン（プロフィールが変わるとキャッシュを作り直す）
+	version    string
+	renderedAt time.Time
+}
+
+// ProfileCardService プロフィール共有用のカード画像（アバター・ユーザー名・QRコード）を生成するサービス
+type ProfileCardService struct {
+	storage interfaces.StorageProvider
+	// プロフィールページのURLの基点（アプリケーションのURL）
+	appURL string
+	log    logger.Logger
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]*ProfileCard
+}
+
+// NewProfileCardService 新しいプロフィールカードサービスを作成する
+func NewProfileCardService(storage interfaces.StorageProvider, appURL string, log logger.Logger) *ProfileCardService {
+	return &ProfileCardService{
+		storage: storage,
+		appURL:  strings.TrimRight(appURL, "/"),
+		log:     log,
+		cache:   make(map[uuid.UUID]*ProfileCard),
+	}
+}
+
+// ProfileURL ユーザーのプロフィールページのURLを返す
+func (s *ProfileCardService) ProfileURL(user *models.User) string {
+	return s.appURL + "/users/" + user.Username
+}
+
+// Card ユーザーのプロフィールカードを返す（キャッシュがあればそれを使用する）
+func (s *ProfileCardService) Card(ctx context.Context, user *models.User) (*ProfileCard, error) {
+	version := s.cardVersion(user)
+
+	s.mu.Lock()
+	cached, ok := s.cache[user.ID]
+	s.mu.Unlock()
+	if ok && cached.version == version && time.Since(cached.renderedAt) < profileCardTTL {
+		return cached, nil
+	}
+
+	card, err := s.render(ctx, user, version)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if _, exists := s.cache[user.ID]; !exists && len(s.cache) >= maxCachedProfileCards {
+		s.evictOldestLocked()
+	}
+	s.cache[user.ID] = card
+	s.mu.Unlock()
+
+	return card, nil
+}
+
+// render カード画像を生成してPNGに変換する
+func (s *ProfileCardService) render(ctx context.Context, user *models.User, version string) (*ProfileCard, error) {
+	qr, err := qrcode.Encode([]byte(s.ProfileURL(user)))
+	if err != nil {
+		return nil, fmt.Errorf("QRコードの生成に失敗しました: %w", err)
+	}
+
+	img := profilecard.Render(profilecard.Card{
+		Username:  user.Username,
+		Avatar:    s.loadAvatar(ctx, user),
+		QR:        qr,
+		Supporter: user.IsSupporter(),
+	})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("PNGへの変換に失敗しました: %w", err)
+	}
+
+	return &ProfileCard{
+		PNG:        buf.Bytes(),
+		ETag:       strconv.Quote(version),
+		version:    version,
+		renderedAt: time.Now(),
+	}, nil
+}
+
+// loadAvatar ストレージからアバター画像を読み込む
+// 読み込めない場合はカードの生成を続けられるようnilを返す
+func (s *ProfileCardService) loadAvatar(ctx context.Context, user *models.User) image.Image {
+	if user.ProfileImage == "" {
+		return nil
+	}
+
+	file, err := s.storage.OpenFile(ctx, user.ProfileImage)
+	if err != nil {
+		s.log.Warn("アバター画像を読み込めません", "error", err, "user_id", user.ID)
+		return nil
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, supporterMediaQuota.AvatarMaxBytes+1))
+	if err != nil || int64(len(data)) > supporterMediaQuota.AvatarMaxBytes {
+		s.log.Warn("アバター画像を読み込めません", "error", err, "user_id", user.ID)
+		return nil
+	}
+
+	// 極端に大きい画像をデコードしないよう先に寸法を確認する
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width > maxAvatarDimension || config.Height > maxAvatarDimension {
+		s.log.Warn("アバター画像をデコードできません", "error", err, "user_id", user.ID)
+		return nil
+	}
+
+	avatar, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		s.log.Warn("アバター画像をデコードできません", "error", err, "user_id", user.ID)
+		return nil
+	}
+	return avatar
+}
+
+// cardVersion カードに描画する内容からバージョンを計算する
+func (s *ProfileCardService) cardVersion(user *models.User) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		user.Username,
+		user.ProfileImage,
+		strconv.FormatBool(user.IsSupporter()),
+		s.ProfileURL(user),
+	}, "\n")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// evictOldestLocked 最も古いカードをキャッシュから削除する（呼び出し側でロックを取得すること）
+func (s *ProfileCardService) evictOldestLocked() {
+	var oldestID uuid.UUID
+	var oldest time.Time
+	for id, card := range s.cache {
+		if oldest.IsZero() || card.renderedAt.Before(oldest) {
+			oldestID, oldest = id, card.renderedAt
+		}
+	}
+	delete(s.cache, oldestID)
+}
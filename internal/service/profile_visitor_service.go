@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ErrProfileVisitorsDisabled プロフィール訪問者の表示を有効にしていない場合のエラー
+var ErrProfileVisitorsDisabled = errors.New("profile visitors disabled")
+
+// 古い訪問履歴を削除する間隔
+const profileVisitCleanupInterval = time.Hour
+
+// 訪問履歴の削除1回にかける最大時間
+const profileVisitCleanupTimeout = time.Minute
+
+// ProfileVisitorService プロフィールの訪問者を記録・表示するサービス
+// 閲覧する側とされる側の両方が有効にしている場合のみ記録し、有効にしているユーザーだけが訪問者を見られる
+type ProfileVisitorService struct {
+	visitRepo    interfaces.ProfileVisitRepository
+	settingsRepo interfaces.SettingsRepository
+	// プロフィールの閲覧を訪問として記録する割合
+	sampleRate float64
+	retention  time.Duration
+	maxPerUser int
+	log        logger.Logger
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewProfileVisitorService 新しいプロフィール訪問者サービスを作成する
+func NewProfileVisitorService(
+	visitRepo interfaces.ProfileVisitRepository,
+	settingsRepo interfaces.SettingsRepository,
+	sampleRate float64,
+	retention time.Duration,
+	maxPerUser int,
+	log logger.Logger,
+) *ProfileVisitorService {
+	if sampleRate < 0 {
+		sampleRate = 0
+	}
+	if sampleRate > 1 {
+		sampleRate = 1
+	}
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	if maxPerUser <= 0 {
+		maxPerUser = 100
+	}
+
+	return &ProfileVisitorService{
+		visitRepo:    visitRepo,
+		settingsRepo: settingsRepo,
+		sampleRate:   sampleRate,
+		retention:    retention,
+		maxPerUser:   maxPerUser,
+		log:          log,
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// Start 古い訪問履歴の定期削除を開始する
+func (s *ProfileVisitorService) Start() {
+	go s.run()
+}
+
+// Stop 古い訪問履歴の定期削除を停止する
+func (s *ProfileVisitorService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// RecordVisit プロフィールの閲覧を訪問として記録する（設定した割合で間引き、非同期で書き込む）
+func (s *ProfileVisitorService) RecordVisit(profileUserID, visitorID uuid.UUID) {
+	if profileUserID == visitorID {
+		return
+	}
+	if s.sampleRate < 1 && rand.Float64() >= s.sampleRate {
+		return
+	}
+
+	visitedAt := time.Now().UTC()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := s.visitRepo.RecordVisit(ctx, profileUserID, visitorID, visitedAt); err != nil {
+			s.log.Error("プロフィール訪問の記録に失敗しました", "error", err)
+		}
+	}()
+}
+
+// SetEnabled プロフィール訪問者の表示の有効・無効を切り替える
+// 無効にした場合は、そのユーザーが訪問した・訪問された履歴を削除する
+func (s *ProfileVisitorService) SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) (*models.UserSettings, error) {
+	settings, err := s.settingsRepo.UpdateProfileVisitorsEnabled(ctx, userID, enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		if err := s.visitRepo.DeleteByUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return settings, nil
+}
+
+// Visitors 保持期間内の最近の訪問者を新しい順に返す
+func (s *ProfileVisitorService) Visitors(ctx context.Context, userID uuid.UUID, limit int) ([]*models.ProfileVisit, error) {
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.ProfileVisitorsEnabled {
+		return nil, ErrProfileVisitorsDisabled
+	}
+
+	if limit <= 0 || limit > s.maxPerUser {
+		limit = s.maxPerUser
+	}
+
+	since := time.Now().UTC().Add(-s.retention)
+	return s.visitRepo.GetVisitors(ctx, userID, since, limit)
+}
+
+// run 停止されるまで一定間隔で古い訪問履歴を削除する
+func (s *ProfileVisitorService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(profileVisitCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.cleanup()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// cleanup 保持期間を過ぎた訪問履歴と、プロフィールごとの上限を超えた訪問履歴を削除する
+func (s *ProfileVisitorService) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), profileVisitCleanupTimeout)
+	defer cancel()
+
+	expired, err := s.visitRepo.DeleteBefore(ctx, time.Now().UTC().Add(-s.retention))
+	if err != nil {
+		s.log.Error("古いプロフィール訪問履歴の削除に失敗しました", "error", err)
+		return
+	}
+
+	trimmed, err := s.visitRepo.Trim(ctx, s.maxPerUser)
+	if err != nil {
+		s.log.Error("プロフィール訪問履歴の削除に失敗しました", "error", err)
+		return
+	}
+
+	if expired > 0 || trimmed > 0 {
+		s.log.Info("プロフィール訪問履歴を削除しました", "expired", expired, "trimmed", trimmed)
+	}
+}
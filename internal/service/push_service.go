@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ErrPushPlatformUnavailable は端末のプラットフォームの送信先が設定されていないことを表す
+var ErrPushPlatformUnavailable = errors.New("push platform unavailable")
+
+// ErrInvalidDeviceToken は端末トークンの形式が不正であることを表す
+var ErrInvalidDeviceToken = errors.New("invalid device token")
+
+// 端末トークンの最大長（FCMのトークンは約160文字、APNsは64文字の16進数）
+const maxDeviceTokenLength = 4096
+
+// pushJob 送信キューに積む通知
+type pushJob struct {
+	userID  uuid.UUID
+	message *models.PushMessage
+}
+
+// PushService ユーザーの端末を管理し、通知をモバイル端末へプッシュ送信するサービス
+// 送信はキューを通じてワーカーが行い、一時的な失敗は間隔を空けて再送し、無効と判定された端末トークンは削除する
+type PushService struct {
+	deviceRepo  interfaces.DeviceTokenRepository
+	providers   map[models.DevicePlatform]coreinterfaces.PushProvider
+	maxAttempts int
+	retryDelay  time.Duration
+	timeout     time.Duration
+	workers     int
+	log         logger.Logger
+
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan pushJob
+	wg      sync.WaitGroup
+}
+
+// NewPushService 新しいプッシュ通知サービスを作成する
+// providersにない（設定されていない）プラットフォームの端末は登録できない
+func NewPushService(
+	deviceRepo interfaces.DeviceTokenRepository,
+	providers []coreinterfaces.PushProvider,
+	maxAttempts int,
+	retryDelay time.Duration,
+	timeout time.Duration,
+	workers int,
+	queueSize int,
+	log logger.Logger,
+) *PushService {
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	if retryDelay <= 0 {
+		retryDelay = time.Second
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if workers <= 0 {
+		workers = 4
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	byPlatform := make(map[models.DevicePlatform]coreinterfaces.PushProvider, len(providers))
+	for _, provider := range providers {
+		byPlatform[provider.Platform()] = provider
+	}
+
+	return &PushService{
+		deviceRepo:  deviceRepo,
+		providers:   byPlatform,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		timeout:     timeout,
+		workers:     workers,
+		log:         log,
+		queue:       make(chan pushJob, queueSize),
+	}
+}
+
+// Start 送信ワーカーを開始する
+func (s *PushService) Start() {
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+}
+
+// Stop 新しい通知の受け付けを停止し、キューに残っている通知の送信を待つ
+func (s *PushService) Stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	for _, provider := range s.providers {
+		if err := provider.Close(); err != nil {
+			s.log.Warn("プッシュ通知: 送信先の終了に失敗しました", "platform", provider.Platform(), "error", err)
+		}
+	}
+}
+
+// RegisterDevice ユーザーの端末を登録する（同じ端末トークンが別のユーザーで登録済みの場合は置き換える）
+func (s *PushService) RegisterDevice(ctx context.Context, userID uuid.UUID, platform models.DevicePlatform, token string) (*models.DeviceToken, error) {
+	if token == "" || len(token) > maxDeviceTokenLength {
+		return nil, ErrInvalidDeviceToken
+	}
+	if _, ok := s.providers[platform]; !ok {
+		return nil, ErrPushPlatformUnavailable
+	}
+
+	device := models.NewDeviceToken(userID, platform, token)
+	if err := s.deviceRepo.Upsert(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// ListDevices ユーザーが登録した端末を取得する
+func (s *PushService) ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.DeviceToken, error) {
+	return s.deviceRepo.ListByUser(ctx, userID)
+}
+
+// UnregisterDevice ユーザーの端末の登録を解除する（ログアウト時など）
+func (s *PushService) UnregisterDevice(ctx context.Context, userID uuid.UUID, token string) error {
+	return s.deviceRepo.Delete(ctx, userID, token)
+}
+
+// Notify ユーザーの端末へ通知を送信するようキューに追加する
+// キューが満杯か停止中の場合はErrDeliveryUnavailableを返す
+func (s *PushService) Notify(userID uuid.UUID, message *models.PushMessage) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return ErrDeliveryUnavailable
+	}
+
+	select {
+	case s.queue <- pushJob{userID: userID, message: message}:
+		return nil
+	default:
+		s.log.Warn("プッシュ通知: キューが満杯のため送信を受け付けられませんでした", "user_id", userID)
+		return ErrDeliveryUnavailable
+	}
+}
+
+func (s *PushService) worker() {
+	defer s.wg.Done()
+	for job := range s.queue {
+		s.send(job)
+	}
+}
+
+// send ユーザーのすべての端末へ通知を送信する
+func (s *PushService) send(job pushJob) {
+	ctx := context.Background()
+
+	devices, err := s.deviceRepo.ListByUser(ctx, job.userID)
+	if err != nil {
+		s.log.Error("プッシュ通知: 端末の取得に失敗しました", "error", err, "user_id", job.userID)
+		return
+	}
+
+	for _, device := range devices {
+		provider, ok := s.providers[device.Platform]
+		if !ok {
+			continue
+		}
+		s.deliver(ctx, provider, device, job.message)
+	}
+}
+
+// deliver 端末へ通知を送信する
+// 一時的な失敗は間隔を倍にしながら再送し、端末トークンが無効と判定された場合は端末の登録を削除する
+func (s *PushService) deliver(ctx context.Context, provider coreinterfaces.PushProvider, device *models.DeviceToken, message *models.PushMessage) {
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		err := provider.Send(sendCtx, device.Token, message)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		switch {
+		case errors.Is(err, coreinterfaces.ErrInvalidPushToken):
+			if err := s.deviceRepo.DeleteByToken(ctx, device.Token); err != nil {
+				s.log.Error("プッシュ通知: 無効な端末の削除に失敗しました", "error", err, "device_id", device.ID)
+				return
+			}
+			s.log.Info("プッシュ通知: 無効な端末トークンを削除しました", "device_id", device.ID, "user_id", device.UserID, "platform", device.Platform)
+			return
+		case errors.Is(err, coreinterfaces.ErrPushRejected):
+			s.log.Warn("プッシュ通知: 送信先が通知を受け付けませんでした", "error", err, "device_id", device.ID, "platform", device.Platform)
+			return
+		case attempt >= s.maxAttempts:
+			s.log.Warn("プッシュ通知: 再送しても送信できませんでした", "error", err, "device_id", device.ID, "platform", device.Platform, "attempts", attempt)
+			return
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ErrReplyRestricted は投稿の返信設定により返信が許可されないことを表す
+var ErrReplyRestricted = errors.New("reply restricted")
+
+// ErrRepliesLocked はモデレーターにより投稿への返信がロックされていることを表す
+var ErrRepliesLocked = errors.New("replies locked")
+
+// ReplyPolicyService 投稿ごとの返信設定を適用するサービス
+type ReplyPolicyService struct {
+	followRepo interfaces.FollowRepository
+	log        logger.Logger
+}
+
+// NewReplyPolicyService 新しい返信設定サービスを作成する
+func NewReplyPolicyService(
+	followRepo interfaces.FollowRepository,
+	log logger.Logger,
+) *ReplyPolicyService {
+	return &ReplyPolicyService{
+		followRepo: followRepo,
+		log:        log,
+	}
+}
+
+// CheckReply replierIDのユーザーがparentに返信できるかを確認し、できない場合はErrReplyRestrictedを返す
+// 返信がロックされている場合は投稿者本人も含めてErrRepliesLockedを返す
+// 投稿者本人は返信設定に関わらず返信できる
+func (s *ReplyPolicyService) CheckReply(ctx context.Context, replierID uuid.UUID, parent *models.Post) error {
+	if parent.RepliesLocked {
+		return ErrRepliesLocked
+	}
+	if replierID == parent.UserID {
+		return nil
+	}
+
+	var allowed bool
+	var err error
+	switch parent.ReplyPolicy {
+	case models.ReplyPolicyFollowers:
+		// 返信者が投稿者をフォローしている
+		allowed, err = s.followRepo.IsFollowing(ctx, replierID, parent.UserID)
+	case models.ReplyPolicyFollowing:
+		// 投稿者が返信者をフォローしている
+		allowed, err = s.followRepo.IsFollowing(ctx, parent.UserID, replierID)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrReplyRestricted
+	}
+
+	return nil
+}
+
+// CanReply 返信できるかをboolで返す（投稿の表示用、確認に失敗した場合はfalse）
+func (s *ReplyPolicyService) CanReply(ctx context.Context, replierID uuid.UUID, parent *models.Post) bool {
+	if replierID == uuid.Nil {
+		return false
+	}
+	if err := s.CheckReply(ctx, replierID, parent); err != nil {
+		if !errors.Is(err, ErrReplyRestricted) && !errors.Is(err, ErrRepliesLocked) {
+			s.log.Error("返信設定の確認エラー", "error", err)
+		}
+		return false
+	}
+	return true
+}
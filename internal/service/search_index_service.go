@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	coreinterfaces "github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// 投稿・ユーザー1件の索引の更新にかける最大時間
+	searchIndexTimeout = 10 * time.Second
+	// 索引を作り直す際に一度に登録する件数
+	searchReindexBatchSize = 500
+)
+
+// searchIndexJob 索引の更新キューに積む処理（post・userのいずれか、またはdeleteIDを指定する）
+type searchIndexJob struct {
+	post     *models.Post
+	user     *models.User
+	deleteID uuid.UUID
+	// deleteIDがユーザーのIDの場合はtrue
+	deleteUser bool
+}
+
+// SearchIndexService 投稿・ユーザーの作成・更新・削除を外部の検索エンジンの索引へ非同期に反映し、検索エンジンで検索するサービス
+// 検索エンジンを使用しない場合（indexerがnil）はデータベースで検索する
+// 索引の更新はキューが満杯の場合や失敗した場合に反映されないことがあるため、必要に応じてReindexで作り直す
+type SearchIndexService struct {
+	indexer  coreinterfaces.SearchIndexer
+	postRepo interfaces.PostRepository
+	userRepo interfaces.UserRepository
+	workers  int
+	log      logger.Logger
+
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan searchIndexJob
+	wg      sync.WaitGroup
+}
+
+// NewSearchIndexService 新しい検索の索引サービスを作成する
+// indexerがnilの場合は索引を更新せず、データベースで検索する
+func NewSearchIndexService(
+	indexer coreinterfaces.SearchIndexer,
+	postRepo interfaces.PostRepository,
+	userRepo interfaces.UserRepository,
+	workers int,
+	queueSize int,
+	log logger.Logger,
+) *SearchIndexService {
+	if workers <= 0 {
+		workers = 2
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	return &SearchIndexService{
+		indexer:  indexer,
+		postRepo: postRepo,
+		userRepo: userRepo,
+		workers:  workers,
+		log:      log,
+		queue:    make(chan searchIndexJob, queueSize),
+	}
+}
+
+// Start 索引を更新するワーカーを開始する
+func (s *SearchIndexService) Start() {
+	if s.indexer == nil {
+		return
+	}
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+}
+
+// Stop 新しい更新の受け付けを停止し、キューに残っている更新の完了を待つ
+func (s *SearchIndexService) Stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// IndexPost 投稿を索引に登録するようキューに追加する
+func (s *SearchIndexService) IndexPost(post *models.Post) {
+	s.enqueue(searchIndexJob{post: post})
+}
+
+// IndexUser ユーザーを索引に登録するようキューに追加する
+func (s *SearchIndexService) IndexUser(user *models.User) {
+	s.enqueue(searchIndexJob{user: user})
+}
+
+// RemovePost 投稿を索引から削除するようキューに追加する
+func (s *SearchIndexService) RemovePost(postID uuid.UUID) {
+	s.enqueue(searchIndexJob{deleteID: postID})
+}
+
+// RemoveUser ユーザーを索引から削除するようキューに追加する
+func (s *SearchIndexService) RemoveUser(userID uuid.UUID) {
+	s.enqueue(searchIndexJob{deleteID: userID, deleteUser: true})
+}
+
+func (s *SearchIndexService) enqueue(job searchIndexJob) {
+	if s.indexer == nil {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return
+	}
+
+	select {
+	case s.queue <- job:
+	default:
+		s.log.Warn("検索の索引: キューが満杯のため索引の更新をスキップしました")
+	}
+}
+
+// SearchPosts 検索語に一致する投稿を返す
+// 検索エンジンを使用する場合は関連度の高い順、データベースで検索する場合は新しい順に並ぶ
+func (s *SearchIndexService) SearchPosts(ctx context.Context, query string, offset, limit int) ([]*models.Post, error) {
+	if s.indexer == nil {
+		return s.postRepo.Search(ctx, query, offset, limit)
+	}
+
+	ids, err := s.indexer.SearchPosts(ctx, query, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	postsByID, err := s.postRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 索引に残っている削除・非表示の投稿は除き、検索エンジンの順序を保つ
+	posts := make([]*models.Post, 0, len(ids))
+	for _, id := range ids {
+		if post, ok := postsByID[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+// SearchUsers 検索語に一致するユーザーを返す
+// 検索エンジンを使用する場合は関連度の高い順、データベースで検索する場合は新しい順に並ぶ
+func (s *SearchIndexService) SearchUsers(ctx context.Context, query string, offset, limit int) ([]*models.User, error) {
+	if s.indexer == nil {
+		return s.userRepo.Search(ctx, query, offset, limit)
+	}
+
+	ids, err := s.indexer.SearchUsers(ctx, query, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	usersByID, err := s.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 索引に残っている削除・停止中のユーザーは除き、検索エンジンの順序を保つ
+	users := make([]*models.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := usersByID[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// Reindex データベースのすべての投稿・ユーザーを索引に登録し直す
+// 登録した投稿とユーザーの件数を返す（削除済みの投稿・ユーザーは索引に残るが、検索結果からは除かれる）
+func (s *SearchIndexService) Reindex(ctx context.Context) (int, int, error) {
+	if s.indexer == nil {
+		return 0, 0, nil
+	}
+
+	postCount := 0
+	for offset := 0; ; offset += searchReindexBatchSize {
+		posts, err := s.postRepo.List(ctx, offset, searchReindexBatchSize)
+		if err != nil {
+			return postCount, 0, err
+		}
+		if err := s.indexer.IndexPosts(ctx, posts); err != nil {
+			return postCount, 0, err
+		}
+		postCount += len(posts)
+		if len(posts) < searchReindexBatchSize {
+			break
+		}
+	}
+
+	userCount := 0
+	for offset := 0; ; offset += searchReindexBatchSize {
+		users, err := s.userRepo.List(ctx, offset, searchReindexBatchSize)
+		if err != nil {
+			return postCount, userCount, err
+		}
+		if err := s.indexer.IndexUsers(ctx, users); err != nil {
+			return postCount, userCount, err
+		}
+		userCount += len(users)
+		if len(users) < searchReindexBatchSize {
+			break
+		}
+	}
+
+	return postCount, userCount, nil
+}
+
+func (s *SearchIndexService) worker() {
+	defer s.wg.Done()
+	for job := range s.queue {
+		s.apply(job)
+	}
+}
+
+// apply キューから取り出した更新を検索エンジンへ反映する（失敗した場合は記録のみ行う）
+func (s *SearchIndexService) apply(job searchIndexJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), searchIndexTimeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case job.post != nil:
+		err = s.indexer.IndexPosts(ctx, []*models.Post{job.post})
+	case job.user != nil:
+		err = s.indexer.IndexUsers(ctx, []*models.User{job.user})
+	case job.deleteUser:
+		err = s.indexer.DeleteUser(ctx, job.deleteID)
+	default:
+		err = s.indexer.DeletePost(ctx, job.deleteID)
+	}
+	if err != nil {
+		s.log.Warn("検索の索引: 索引の更新に失敗しました", "error", err, "indexer", s.indexer.Name())
+	}
+}
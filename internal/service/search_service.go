@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// 保持する検索履歴の件数
+	maxSearchHistory = 20
+	// ユーザーごとに保存できる検索の件数
+	maxSavedSearches = 25
+	// 検索語の最大文字数
+	maxSearchQueryLength = 100
+	// 保存した検索の確認で一度に取得する件数
+	savedSearchBatchSize = 100
+	// 保存した検索ごとに確認する新着投稿の最大件数
+	savedSearchMatchLimit = 20
+	// 保存した検索の確認1回にかける最大時間
+	savedSearchCheckTimeout = time.Minute
+)
+
+// ErrInvalidSearchQuery は検索語が空または長すぎることを表す
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// ErrSavedSearchLimit は保存できる検索の上限に達していることを表す
+var ErrSavedSearchLimit = errors.New("saved search limit reached")
+
+// SearchService 検索履歴と保存した検索を管理し、保存した検索の新着投稿を定期的に通知するサービス
+type SearchService struct {
+	searchRepo    interfaces.SearchRepository
+	postRepo      interfaces.PostRepository
+	notifications *NotificationService
+	blockService  *BlockService
+	checkInterval time.Duration
+	log           logger.Logger
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewSearchService 新しい検索サービスを作成する
+func NewSearchService(
+	searchRepo interfaces.SearchRepository,
+	postRepo interfaces.PostRepository,
+	notifications *NotificationService,
+	blockService *BlockService,
+	checkInterval time.Duration,
+	log logger.Logger,
+) *SearchService {
+	if checkInterval <= 0 {
+		checkInterval = 5 * time.Minute
+	}
+
+	return &SearchService{
+		searchRepo:    searchRepo,
+		postRepo:      postRepo,
+		notifications: notifications,
+		blockService:  blockService,
+		checkInterval: checkInterval,
+		log:           log,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+}
+
+// NormalizeQuery 検索語の前後の空白を取り除き、長さを検証する
+func NormalizeQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return "", ErrInvalidSearchQuery
+	}
+	return query, nil
+}
+
+// RecordSearch 検索を履歴に記録する
+func (s *SearchService) RecordSearch(ctx context.Context, userID uuid.UUID, query string) error {
+	return s.searchRepo.RecordSearch(ctx, userID, query, maxSearchHistory)
+}
+
+// History 最近の検索を新しい順に返す
+func (s *SearchService) History(ctx context.Context, userID uuid.UUID) ([]*models.SearchHistoryEntry, error) {
+	return s.searchRepo.GetSearchHistory(ctx, userID, maxSearchHistory)
+}
+
+// DeleteHistoryEntry 検索履歴を1件削除する
+func (s *SearchService) DeleteHistoryEntry(ctx context.Context, userID, entryID uuid.UUID) error {
+	return s.searchRepo.DeleteSearchHistoryEntry(ctx, userID, entryID)
+}
+
+// ClearHistory 検索履歴をすべて削除する
+func (s *SearchService) ClearHistory(ctx context.Context, userID uuid.UUID) error {
+	return s.searchRepo.ClearSearchHistory(ctx, userID)
+}
+
+// SaveSearch 検索を保存する
+func (s *SearchService) SaveSearch(ctx context.Context, userID uuid.UUID, query string, notify bool) (*models.SavedSearch, error) {
+	count, err := s.searchRepo.CountSavedSearches(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxSavedSearches {
+		return nil, ErrSavedSearchLimit
+	}
+
+	search := models.NewSavedSearch(userID, query, notify)
+	if err := s.searchRepo.CreateSavedSearch(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// SavedSearches 保存した検索を返す
+func (s *SearchService) SavedSearches(ctx context.Context, userID uuid.UUID) ([]*models.SavedSearch, error) {
+	return s.searchRepo.GetSavedSearches(ctx, userID)
+}
+
+// SetNotify 保存した検索の新着通知を有効・無効にする
+func (s *SearchService) SetNotify(ctx context.Context, userID, searchID uuid.UUID, notify bool) (*models.SavedSearch, error) {
+	return s.searchRepo.UpdateSavedSearchNotify(ctx, userID, searchID, notify)
+}
+
+// DeleteSavedSearch 保存した検索を削除する
+func (s *SearchService) DeleteSavedSearch(ctx context.Context, userID, searchID uuid.UUID) error {
+	return s.searchRepo.DeleteSavedSearch(ctx, userID, searchID)
+}
+
+// Start 保存した検索の定期的な確認を開始する
+func (s *SearchService) Start() {
+	go s.run()
+}
+
+// Stop 保存した検索の定期的な確認を停止する
+func (s *SearchService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// CheckSavedSearches 通知が有効な保存した検索に新着投稿があれば通知する
+// 通知した保存した検索の件数を返す
+func (s *SearchService) CheckSavedSearches(ctx context.Context) (int, error) {
+	startedAt := time.Now()
+	notified := 0
+
+	for {
+		searches, err := s.searchRepo.GetNotifiableSavedSearches(ctx, savedSearchBatchSize)
+		if err != nil {
+			return notified, err
+		}
+		// 最終確認日時の古い順に取得するため、今回確認済みのものが先頭に来たら全件確認済み
+		if len(searches) == 0 || !searches[0].LastCheckedAt.Before(startedAt) {
+			return notified, nil
+		}
+
+		for _, search := range searches {
+			if !search.LastCheckedAt.Before(startedAt) {
+				continue
+			}
+			ok, err := s.checkSavedSearch(ctx, search)
+			if err != nil {
+				return notified, err
+			}
+			if ok {
+				notified++
+			}
+		}
+
+		if len(searches) < savedSearchBatchSize {
+			return notified, nil
+		}
+	}
+}
+
+// checkSavedSearch 前回の確認以降に作成された一致する投稿があれば、最新の投稿について通知する
+func (s *SearchService) checkSavedSearch(ctx context.Context, search *models.SavedSearch) (bool, error) {
+	checkedAt := time.Now()
+
+	posts, err := s.postRepo.SearchSince(ctx, search.Query, search.LastCheckedAt, savedSearchMatchLimit)
+	if err != nil {
+		return false, err
+	}
+
+	// 自分の投稿とブロック関係にあるユーザーの投稿は通知しない
+	matches := make([]*models.Post, 0, len(posts))
+	for _, post := range posts {
+		if post.UserID != search.UserID {
+			matches = append(matches, post)
+		}
+	}
+	matches, err = s.blockService.FilterPosts(ctx, search.UserID, matches)
+	if err != nil {
+		return false, err
+	}
+
+	if len(matches) > 0 {
+		if err := s.notifications.CreateSavedSearchNotification(ctx, search, matches[0]); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.searchRepo.MarkSavedSearchChecked(ctx, search.ID, checkedAt); err != nil {
+		return false, err
+	}
+	return len(matches) > 0, nil
+}
+
+// run 停止されるまで一定間隔で保存した検索を確認する
+func (s *SearchService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.check()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *SearchService) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), savedSearchCheckTimeout)
+	defer cancel()
+
+	notified, err := s.CheckSavedSearches(ctx)
+	if err != nil {
+		s.log.Error("保存した検索の確認に失敗しました", "error", err)
+		return
+	}
+	if notified > 0 {
+		s.log.Info("保存した検索の新着投稿を通知しました", "count", notified)
+	}
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/util/oidc"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// ユーザー名の長さの制限（登録時の検証と同じ）
+	ssoUsernameMinLength = 3
+	ssoUsernameMaxLength = 30
+	// 使用中のユーザー名に番号を付けて試す回数
+	ssoUsernameAttempts = 20
+)
+
+// SSOService 外部のOpenIDプロバイダーによるシングルサインオンを管理するサービス
+// 初回のログイン時にユーザーを作成し（JITプロビジョニング）、ログインのたびにグループから権限を設定する
+type SSOService struct {
+	provider        *oidc.Provider
+	userRepo        interfaces.UserRepository
+	identityRepo    interfaces.UserIdentityRepository
+	txManager       interfaces.TxManager
+	systemAccounts  *SystemAccountService
+	onboarding      *OnboardingService
+	analytics       *AnalyticsService
+	groupsClaim     string
+	allowedGroups   map[string]struct{}
+	adminGroups     map[string]struct{}
+	moderatorGroups map[string]struct{}
+	// ログイン後にトークンを付けてリダイレクトするURL（空の場合はJSONで返す）
+	postLoginRedirectURL string
+	log                  logger.Logger
+}
+
+// NewSSOService 新しいシングルサインオンサービスを作成する（providerがnilの場合は無効）
+func NewSSOService(
+	provider *oidc.Provider,
+	userRepo interfaces.UserRepository,
+	identityRepo interfaces.UserIdentityRepository,
+	txManager interfaces.TxManager,
+	systemAccounts *SystemAccountService,
+	onboarding *OnboardingService,
+	analytics *AnalyticsService,
+	groupsClaim string,
+	allowedGroups []string,
+	adminGroups []string,
+	moderatorGroups []string,
+	postLoginRedirectURL string,
+	log logger.Logger,
+) *SSOService {
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	return &SSOService{
+		provider:             provider,
+		userRepo:             userRepo,
+		identityRepo:         identityRepo,
+		txManager:            txManager,
+		systemAccounts:       systemAccounts,
+		onboarding:           onboarding,
+		analytics:            analytics,
+		groupsClaim:          groupsClaim,
+		allowedGroups:        groupSet(allowedGroups),
+		adminGroups:          groupSet(adminGroups),
+		moderatorGroups:      groupSet(moderatorGroups),
+		postLoginRedirectURL: postLoginRedirectURL,
+		log:                  log,
+	}
+}
+
+// Enabled シングルサインオンが有効かどうかを返す（有効な場合はパスワードでの登録・ログインはできない）
+func (s *SSOService) Enabled() bool {
+	return s.provider != nil
+}
+
+// PostLoginRedirectURL ログイン後にリダイレクトするURLを返す（空の場合はJSONで返す）
+func (s *SSOService) PostLoginRedirectURL() string {
+	return s.postLoginRedirectURL
+}
+
+// AuthCodeURL プロバイダーのログイン画面へのリダイレクト先URLを返す
+func (s *SSOService) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	return s.provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+}
+
+// Authenticate 認可コードを検証し、対応するユーザーを返す（初回のログインではユーザーを作成する）
+// 返すユーザーは無効化・削除手続き中の場合があるため、呼び出し側で状態を確認する
+func (s *SSOService) Authenticate(ctx context.Context, code, codeVerifier, nonce string) (*models.User, error) {
+	claims, err := s.provider.Exchange(ctx, code, codeVerifier, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := claims.StringList(s.groupsClaim)
+	if !s.groupAllowed(groups) {
+		s.log.Warn("ログインが許可されていないグループのユーザーです", "subject", claims.Subject, "groups", groups)
+		return nil, errors.New("sso group not allowed")
+	}
+	role := s.roleForGroups(groups)
+
+	var user *models.User
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.provisionUser(ctx, claims)
+		if err != nil {
+			return err
+		}
+
+		// グループから外れた場合は権限も外す
+		if user.Role != role {
+			if err := s.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+				return err
+			}
+			s.log.Info("シングルサインオンのグループからユーザーの権限を更新しました", "user_id", user.ID, "role", role)
+			user.Role = role
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// provisionUser プロバイダーのアカウントに紐付いたユーザーを返す
+// 紐付いていない場合は確認済みのメールアドレスが一致するユーザーに紐付け、いなければ新しく作成する
+func (s *SSOService) provisionUser(ctx context.Context, claims *oidc.Claims) (*models.User, error) {
+	issuer := s.provider.Issuer()
+
+	identity, err := s.identityRepo.GetByIssuerAndSubject(ctx, issuer, claims.Subject)
+	if err == nil {
+		if err := s.identityRepo.RecordLogin(ctx, issuer, claims.Subject, claims.Email); err != nil {
+			return nil, err
+		}
+		return s.userRepo.GetByIDIncludingInactive(ctx, identity.UserID)
+	}
+	if err.Error() != "identity not found" {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, errors.New("sso email missing")
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		// 未確認のメールアドレスやシステムアカウントには紐付けない（他人のアカウントを乗っ取れないようにする）
+		if !claims.EmailVerified || user.IsSystem {
+			return nil, errors.New("sso account conflict")
+		}
+		s.log.Info("既存のユーザーをシングルサインオンのアカウントに紐付けました", "user_id", user.ID, "subject", claims.Subject)
+	case err.Error() == "user not found":
+		user, err = s.createUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		s.log.Info("シングルサインオンのユーザーを作成しました", "user_id", user.ID, "username", user.Username, "subject", claims.Subject)
+
+		// 作成が確定した後に、設定されたアカウントを自動でフォローし、登録を記録する
+		userID := user.ID
+		s.txManager.AfterCommit(ctx, func() {
+			s.onboarding.Welcome(context.Background(), userID)
+			s.analytics.TrackSignup(userID, SignupMethodSSO)
+		})
+	default:
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.identityRepo.Create(ctx, &models.UserIdentity{
+		Issuer:      issuer,
+		Subject:     claims.Subject,
+		UserID:      user.ID,
+		Email:       claims.Email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser プロバイダーのクレームから新しいユーザーを作成する（パスワードではログインできない）
+func (s *SSOService) createUser(ctx context.Context, claims *oidc.Claims) (*models.User, error) {
+	username, err := s.availableUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// 推測できないパスワードを設定する
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(base64.RawURLEncoding.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = username
+	}
+	if runes := []rune(name); len(runes) > 50 {
+		name = string(runes[:50])
+	}
+
+	user := models.NewUser(username, claims.Email, string(hashedPassword), name)
+	user.IsVerified = claims.EmailVerified
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// availableUsername クレームから使用できるユーザー名を決める（使用中の場合は番号を付ける）
+func (s *SSOService) availableUsername(ctx context.Context, claims *oidc.Claims) (string, error) {
+	base := sanitizeUsername(claims.PreferredUsername)
+	if len(base) < ssoUsernameMinLength {
+		base = sanitizeUsername(strings.SplitN(claims.Email, "@", 2)[0])
+	}
+	if len(base) < ssoUsernameMinLength {
+		base = "user"
+	}
+
+	for i := 1; i <= ssoUsernameAttempts+1; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = numberedUsername(base, fmt.Sprint(i))
+		}
+		// 最後はランダムな番号を付ける
+		if i == ssoUsernameAttempts+1 {
+			n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+			if err != nil {
+				return "", err
+			}
+			candidate = numberedUsername(base, fmt.Sprintf("%06d", n.Int64()))
+		}
+
+		if s.systemAccounts.IsReserved(candidate) {
+			continue
+		}
+		available, err := s.userRepo.IsUsernameAvailable(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if available {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("使用できるユーザー名が見つかりません: %s", base)
+}
+
+// groupAllowed ログインを許可するグループに含まれるかを返す（許可するグループが設定されていない場合はすべて許可する）
+func (s *SSOService) groupAllowed(groups []string) bool {
+	if len(s.allowedGroups) == 0 {
+		return true
+	}
+	for _, group := range groups {
+		if _, ok := s.allowedGroups[group]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// roleForGroups グループに対応する権限を返す（複数のグループに属する場合は上位の権限を返す）
+func (s *SSOService) roleForGroups(groups []string) models.UserRole {
+	role := models.UserRoleUser
+	for _, group := range groups {
+		if _, ok := s.adminGroups[group]; ok {
+			return models.UserRoleAdmin
+		}
+		if _, ok := s.moderatorGroups[group]; ok {
+			role = models.UserRoleModerator
+		}
+	}
+	return role
+}
+
+func groupSet(groups []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		set[group] = struct{}{}
+	}
+	return set
+}
+
+// sanitizeUsername ユーザー名に使用できない文字を取り除く（英数字のみ）
+func sanitizeUsername(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	username := b.String()
+	if len(username) > ssoUsernameMaxLength {
+		username = username[:ssoUsernameMaxLength]
+	}
+	return username
+}
+
+// numberedUsername 長さの制限に収まるようにユーザー名の末尾に番号を付ける
+func numberedUsername(base, suffix string) string {
+	if len(base)+len(suffix) > ssoUsernameMaxLength {
+		base = base[:ssoUsernameMaxLength-len(suffix)]
+	}
+	return base + suffix
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSignature はWebhookの署名が正しくないことを表す
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrInvalidSupporterEvent はWebhookのイベント内容が不正であることを表す
+var ErrInvalidSupporterEvent = errors.New("invalid supporter event")
+
+// Webhookのタイムスタンプとして許容する時刻のずれ（リプレイ攻撃を防ぐため）
+const supporterWebhookTolerance = 5 * time.Minute
+
+// 階級が指定されていない場合のサポーターの階級
+const defaultSupporterTier = "supporter"
+
+// MediaQuota ユーザーがアップロードできるメディアの上限
+type MediaQuota struct {
+	AvatarMaxBytes int64
+	BannerMaxBytes int64
+	// 投稿に添付する画像・動画の最大サイズと、動画の最大の再生時間
+	PostImageMaxBytes int64
+	VideoMaxBytes     int64
+	VideoMaxDuration  time.Duration
+}
+
+var (
+	// 通常のユーザーのメディア上限
+	standardMediaQuota = MediaQuota{
+		AvatarMaxBytes:    2 * 1024 * 1024,
+		BannerMaxBytes:    5 * 1024 * 1024,
+		PostImageMaxBytes: 5 * 1024 * 1024,
+		VideoMaxBytes:     50 * 1024 * 1024,
+		VideoMaxDuration:  140 * time.Second,
+	}
+	// サポーターのメディア上限
+	supporterMediaQuota = MediaQuota{
+		AvatarMaxBytes:    8 * 1024 * 1024,
+		BannerMaxBytes:    20 * 1024 * 1024,
+		PostImageMaxBytes: 20 * 1024 * 1024,
+		VideoMaxBytes:     200 * 1024 * 1024,
+		VideoMaxDuration:  10 * time.Minute,
+	}
+)
+
+// SupporterService 決済サービスからのWebhookを処理し、サポーターの特典を適用するサービス
+type SupporterService struct {
+	supporterRepo interfaces.SupporterRepository
+	webhookSecret string
+	log           logger.Logger
+}
+
+// NewSupporterService 新しいサポーターサービスを作成する
+func NewSupporterService(
+	supporterRepo interfaces.SupporterRepository,
+	webhookSecret string,
+	log logger.Logger,
+) *SupporterService {
+	return &SupporterService{
+		supporterRepo: supporterRepo,
+		webhookSecret: webhookSecret,
+		log:           log,
+	}
+}
+
+// HandleWebhook Webhookの署名を検証し、イベントをユーザーのサポーター状態に反映する
+// 処理済みのイベントの場合はfalseを返す
+func (s *SupporterService) HandleWebhook(ctx context.Context, timestamp, signature string, body []byte) (bool, error) {
+	if err := s.verifySignature(timestamp, signature, body, time.Now()); err != nil {
+		return false, err
+	}
+
+	var event models.SupporterEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return false, ErrInvalidSupporterEvent
+	}
+	if event.ID == "" || event.UserID == uuid.Nil || !event.Type.IsValid() {
+		return false, ErrInvalidSupporterEvent
+	}
+	if event.Tier == "" {
+		event.Tier = defaultSupporterTier
+	}
+
+	applied, err := s.supporterRepo.ApplyEvent(ctx, &event)
+	if err != nil {
+		return false, err
+	}
+
+	if applied {
+		s.log.Info("サポーターイベントを反映しました", "event_id", event.ID, "type", event.Type, "user_id", event.UserID)
+	}
+	return applied, nil
+}
+
+// MediaQuota ユーザーのメディアアップロードの上限を返す（サポーターは上限が高くなる）
+func (s *SupporterService) MediaQuota(user *models.User) MediaQuota {
+	if user != nil && user.IsSupporter() {
+		return supporterMediaQuota
+	}
+	return standardMediaQuota
+}
+
+// verifySignature 「タイムスタンプ.本文」のHMAC-SHA256による署名を検証する
+// 署名ヘッダーは "sha256=<16進数>" の形式
+func (s *SupporterService) verifySignature(timestamp, signature string, body []byte, now time.Time) error {
+	// シークレットが設定されていない場合はすべて拒否する
+	if s.webhookSecret == "" {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	sentAt := time.Unix(unix, 0)
+	if now.Sub(sentAt) > supporterWebhookTolerance || sentAt.Sub(now) > supporterWebhookTolerance {
+		return ErrInvalidSignature
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// 保持期間を過ぎたイベントを削除する間隔と、1回の削除にかける最大時間
+	syncEventPurgeInterval = time.Hour
+	syncEventPurgeTimeout  = 5 * time.Minute
+	// 1回のクエリで削除するイベントの最大数
+	syncEventPurgeBatchSize = 1000
+)
+
+// SyncService オフラインファーストのクライアント向けに、前回の同期以降の変更を返すサービス
+// 変更は投稿・フォロー・通知のリポジトリがsync_eventsに記録し、このサービスが保持期間を過ぎたものを削除する
+type SyncService struct {
+	syncRepo  interfaces.SyncEventRepository
+	retention time.Duration
+	// 記録されてからこの時間が経つまでイベントを返さない（コミットの順序がシーケンス番号と前後しても取りこぼさないようにする）
+	settleDelay time.Duration
+	log         logger.Logger
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewSyncService 新しい差分同期サービスを作成する
+func NewSyncService(
+	syncRepo interfaces.SyncEventRepository,
+	retention time.Duration,
+	settleDelay time.Duration,
+	log logger.Logger,
+) *SyncService {
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	if settleDelay < 0 {
+		settleDelay = 0
+	}
+
+	return &SyncService{
+		syncRepo:    syncRepo,
+		retention:   retention,
+		settleDelay: settleDelay,
+		log:         log,
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+}
+
+// Start 保持期間を過ぎたイベントの定期的な削除を開始する
+func (s *SyncService) Start() {
+	go s.run()
+}
+
+// Stop 保持期間を過ぎたイベントの定期的な削除を停止する
+func (s *SyncService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// Changes cursorより後のユーザーに関係する変更を古い順に最大limit件返す
+// cursorがnilの場合や、不正・保持期間より古い場合は、すべて取得し直すよう求める（ResetRequired）
+// 取得し直した後は、返したNextCursorから同期を続ける
+func (s *SyncService) Changes(ctx context.Context, userID uuid.UUID, cursor *int64, limit int) (*models.SyncPage, error) {
+	first, last, err := s.syncRepo.SeqRange(ctx, time.Now().UTC().Add(-s.settleDelay))
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.SyncPage{Events: []*models.SyncEvent{}, NextCursor: last}
+
+	// 保持しているイベントが途切れている場合、その間の変更は失われている
+	if cursor == nil || *cursor < 0 || *cursor > last || (first > 0 && *cursor < first-1) {
+		page.ResetRequired = true
+		return page, nil
+	}
+
+	// 1件多く取得して続きがあるか判定する
+	events, err := s.syncRepo.ListForUser(ctx, userID, *cursor, last, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.HasMore = true
+		page.NextCursor = events[limit-1].Seq
+	} else {
+		// 関係する変更がなかった範囲も読み終えたものとして進める
+		page.Events = events
+	}
+
+	return page, nil
+}
+
+// run 停止されるまで一定間隔で保持期間を過ぎたイベントを削除する
+func (s *SyncService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(syncEventPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.purge()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *SyncService) purge() {
+	ctx, cancel := context.WithTimeout(context.Background(), syncEventPurgeTimeout)
+	defer cancel()
+
+	before := time.Now().UTC().Add(-s.retention)
+	var total int64
+	for {
+		deleted, err := s.syncRepo.DeleteBefore(ctx, before, syncEventPurgeBatchSize)
+		if err != nil {
+			s.log.Error("保持期間を過ぎた同期イベントの削除に失敗しました", "error", err)
+			break
+		}
+		total += deleted
+		if deleted < syncEventPurgeBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.log.Info("保持期間を過ぎた同期イベントを削除しました", "events", total)
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// システムアカウントのメールアドレスのドメイン（実在しないドメインのため、メールは届かない）
+const systemAccountEmailDomain = "system.invalid"
+
+// システムアカウントのパスワード（bcryptのハッシュとして不正な値のため、ログインはできない）
+const systemAccountPassword = "!"
+
+// ErrNoAnnouncementsAccount お知らせを投稿するシステムアカウントが設定されていない場合のエラー
+var ErrNoAnnouncementsAccount = errors.New("announcements account not configured")
+
+// SystemAccountService 運営が管理するシステムアカウント（@goxなど）を管理するサービス
+// 起動時にアカウントを作成し、ユーザー名の予約とお知らせの投稿を扱う
+// システムアカウントはログインやフォローができず、投稿はフォロー関係によらず全ユーザーのホームタイムラインに表示される
+type SystemAccountService struct {
+	userRepo       interfaces.UserRepository
+	postRepo       interfaces.PostRepository
+	timelineFanout *TimelineFanoutService
+	// 起動時に作成するシステムアカウントのユーザー名
+	accounts             []string
+	announcementsAccount string
+	// 登録できないユーザー名（小文字）
+	reserved map[string]struct{}
+	log      logger.Logger
+
+	mu              sync.RWMutex
+	systemIDs       map[uuid.UUID]struct{}
+	announcementsID uuid.UUID
+}
+
+// NewSystemAccountService 新しいシステムアカウントサービスを作成する
+// システムアカウントのユーザー名とreservedUsernamesは登録できないユーザー名として扱う
+func NewSystemAccountService(
+	userRepo interfaces.UserRepository,
+	postRepo interfaces.PostRepository,
+	timelineFanout *TimelineFanoutService,
+	accounts []string,
+	announcementsAccount string,
+	reservedUsernames []string,
+	log logger.Logger,
+) *SystemAccountService {
+	// お知らせを投稿するアカウントも起動時に作成する
+	accounts = append([]string{}, accounts...)
+	if announcementsAccount != "" && !containsFold(accounts, announcementsAccount) {
+		accounts = append(accounts, announcementsAccount)
+	}
+
+	reserved := make(map[string]struct{}, len(accounts)+len(reservedUsernames))
+	for _, username := range append(append([]string{}, accounts...), reservedUsernames...) {
+		reserved[strings.ToLower(username)] = struct{}{}
+	}
+
+	return &SystemAccountService{
+		userRepo:             userRepo,
+		postRepo:             postRepo,
+		timelineFanout:       timelineFanout,
+		accounts:             accounts,
+		announcementsAccount: announcementsAccount,
+		reserved:             reserved,
+		log:                  log,
+		systemIDs:            make(map[uuid.UUID]struct{}),
+	}
+}
+
+// Bootstrap 存在しないシステムアカウントを作成し、システムアカウントのIDを読み込む
+// 同じユーザー名の一般ユーザーが既に存在する場合は、そのユーザーをシステムアカウントとして扱わない
+func (s *SystemAccountService) Bootstrap(ctx context.Context) error {
+	for _, username := range s.accounts {
+		user, err := s.userRepo.GetByUsername(ctx, username)
+		if err == nil {
+			if !user.IsSystem {
+				s.log.Error("システムアカウントのユーザー名が一般ユーザーに使用されています", "username", username)
+			}
+			continue
+		}
+
+		now := time.Now().UTC()
+		user = &models.User{
+			ID:         uuid.New(),
+			Username:   username,
+			Email:      username + "@" + systemAccountEmailDomain,
+			Password:   systemAccountPassword,
+			Name:       username,
+			IsVerified: true,
+			IsSystem:   true,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		s.log.Info("システムアカウントを作成しました", "username", username)
+	}
+
+	ids, err := s.userRepo.GetSystemUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	systemIDs := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		systemIDs[id] = struct{}{}
+	}
+
+	var announcementsID uuid.UUID
+	if s.announcementsAccount != "" {
+		user, err := s.userRepo.GetByUsername(ctx, s.announcementsAccount)
+		if err != nil {
+			return err
+		}
+		if user.IsSystem {
+			announcementsID = user.ID
+		}
+	}
+
+	s.mu.Lock()
+	s.systemIDs = systemIDs
+	s.announcementsID = announcementsID
+	s.mu.Unlock()
+
+	return nil
+}
+
+// IsReserved ユーザー名がシステムアカウント用に予約されているかを確認する（大文字・小文字は区別しない）
+func (s *SystemAccountService) IsReserved(username string) bool {
+	_, ok := s.reserved[strings.ToLower(username)]
+	return ok
+}
+
+// IsSystemUser ユーザーがシステムアカウントかどうかを確認する
+func (s *SystemAccountService) IsSystemUser(userID uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.systemIDs[userID]
+	return ok
+}
+
+// UserIDs すべてのシステムアカウントのIDを返す（ホームタイムラインに投稿を含めるために使う）
+func (s *SystemAccountService) UserIDs() []uuid.UUID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]uuid.UUID, 0, len(s.systemIDs))
+	for id := range s.systemIDs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// AnnouncementsUserID お知らせ用のシステムアカウントのIDを返す（設定されていない場合はuuid.Nil）
+func (s *SystemAccountService) AnnouncementsUserID() uuid.UUID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.announcementsID
+}
+
+// PostAnnouncement お知らせ用のシステムアカウントで投稿し、全ユーザーのホームタイムラインへ配信する
+func (s *SystemAccountService) PostAnnouncement(ctx context.Context, content string) (*models.Post, error) {
+	s.mu.RLock()
+	announcementsID := s.announcementsID
+	s.mu.RUnlock()
+
+	if announcementsID == uuid.Nil {
+		return nil, ErrNoAnnouncementsAccount
+	}
+
+	post := models.NewPost(announcementsID, content, nil)
+	if err := s.postRepo.Create(ctx, post); err != nil {
+		return nil, err
+	}
+
+	s.timelineFanout.EnqueueAnnouncement(post)
+
+	return post, nil
+}
+
+// containsFold 大文字・小文字を区別せずに一覧に値が含まれるかを確認する
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
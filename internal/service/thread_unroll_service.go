@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ThreadUnrollService 投稿者が自分の投稿に返信を続けたスレッドを1つの文書として読むための「リーダーモード」を提供するサービス
+// スレッドの構造（投稿IDの一覧）はキャッシュし、投稿の内容は編集・削除を反映するため毎回データベースから取得する
+type ThreadUnrollService struct {
+	postRepo interfaces.PostRepository
+	// スレッドのキャッシュ（nilの場合はキャッシュしない）
+	cache interfaces.ThreadCache
+	// 1つのスレッドとして読み込む投稿の最大件数
+	maxPosts int
+	log      logger.Logger
+}
+
+// NewThreadUnrollService 新しいスレッド展開サービスを作成する
+func NewThreadUnrollService(
+	postRepo interfaces.PostRepository,
+	cache interfaces.ThreadCache,
+	maxPosts int,
+	log logger.Logger,
+) *ThreadUnrollService {
+	if maxPosts <= 0 {
+		maxPosts = 100
+	}
+
+	return &ThreadUnrollService{
+		postRepo: postRepo,
+		cache:    cache,
+		maxPosts: maxPosts,
+		log:      log,
+	}
+}
+
+// Unroll 投稿を含むスレッドの投稿を先頭から順に返す
+// スレッドの先頭は投稿者が他のユーザーに返信した投稿の場合もあり、単独の投稿の場合はその投稿のみを返す
+func (s *ThreadUnrollService) Unroll(ctx context.Context, postID uuid.UUID) ([]*models.Post, error) {
+	if posts, ok := s.cachedThread(ctx, postID); ok {
+		return posts, nil
+	}
+
+	posts, err := s.postRepo.GetSelfThread(ctx, postID, s.maxPosts)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, errors.New("post not found")
+	}
+
+	if s.cache != nil {
+		postIDs := make([]uuid.UUID, len(posts))
+		for i, post := range posts {
+			postIDs[i] = post.ID
+		}
+		if err := s.cache.Set(ctx, postIDs); err != nil {
+			s.log.Warn("スレッドのキャッシュの保存に失敗しました", "error", err, "post_id", postID)
+		}
+	}
+
+	return posts, nil
+}
+
+// Invalidate 投稿を含むスレッドのキャッシュを削除する
+// 返信の作成時には返信先の投稿、削除時には削除した投稿を指定する
+func (s *ThreadUnrollService) Invalidate(ctx context.Context, postID uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+
+	if err := s.cache.Invalidate(ctx, postID); err != nil {
+		s.log.Warn("スレッドのキャッシュの削除に失敗しました", "error", err, "post_id", postID)
+	}
+}
+
+// cachedThread キャッシュされたスレッドの投稿を返す
+// キャッシュにない場合や、指定した投稿が削除されている場合はfalseを返してデータベースから作り直す
+func (s *ThreadUnrollService) cachedThread(ctx context.Context, postID uuid.UUID) ([]*models.Post, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+
+	postIDs, err := s.cache.Get(ctx, postID)
+	if err != nil {
+		s.log.Warn("スレッドのキャッシュの取得に失敗しました", "error", err, "post_id", postID)
+		return nil, false
+	}
+	if len(postIDs) == 0 {
+		return nil, false
+	}
+
+	postMap, err := s.postRepo.GetByIDs(ctx, postIDs)
+	if err != nil {
+		s.log.Warn("キャッシュされたスレッドの投稿の取得に失敗しました", "error", err, "post_id", postID)
+		return nil, false
+	}
+	if _, ok := postMap[postID]; !ok {
+		return nil, false
+	}
+
+	posts := make([]*models.Post, 0, len(postIDs))
+	for _, id := range postIDs {
+		if post, ok := postMap[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts, true
+}
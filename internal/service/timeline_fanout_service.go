@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// フォロワー一覧を取得する際のページサイズ
+	timelineFanoutFollowerPageSize = 1000
+	// 投稿1件の配信にかける最大時間
+	timelineFanoutTimeout = 30 * time.Second
+)
+
+// TimelineFanoutService 投稿時にフォロワーのホームタイムラインのキャッシュへ投稿IDを配信し（fan-out-on-write）、
+// 多くのアカウントをフォローしているユーザーのホームタイムラインをキャッシュから読み出すサービス
+// キャッシュが無効な場合や読み出せない場合、呼び出し側はデータベースから取得する
+type TimelineFanoutService struct {
+	cache      interfaces.TimelineCache
+	followRepo interfaces.FollowRepository
+	postRepo   interfaces.PostRepository
+	// キャッシュから読み出すフォロー数の下限
+	minFollowing int
+	// キャッシュするタイムラインの最大件数
+	maxLength int
+	workers   int
+	log       logger.Logger
+
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan timelineFanoutJob
+	wg      sync.WaitGroup
+}
+
+// timelineFanoutJob 配信キューに積む投稿
+type timelineFanoutJob struct {
+	post *models.Post
+	// trueの場合はフォロワーではなく、キャッシュされているすべてのタイムラインへ配信する
+	broadcast bool
+}
+
+// NewTimelineFanoutService 新しいタイムライン配信サービスを作成する
+// cacheがnilの場合はキャッシュを使用しない
+func NewTimelineFanoutService(
+	cache interfaces.TimelineCache,
+	followRepo interfaces.FollowRepository,
+	postRepo interfaces.PostRepository,
+	minFollowing int,
+	maxLength int,
+	workers int,
+	queueSize int,
+	log logger.Logger,
+) *TimelineFanoutService {
+	if maxLength <= 0 {
+		maxLength = 800
+	}
+	if workers <= 0 {
+		workers = 4
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	return &TimelineFanoutService{
+		cache:        cache,
+		followRepo:   followRepo,
+		postRepo:     postRepo,
+		minFollowing: minFollowing,
+		maxLength:    maxLength,
+		workers:      workers,
+		log:          log,
+		queue:        make(chan timelineFanoutJob, queueSize),
+	}
+}
+
+// Start 配信ワーカーを開始する
+func (s *TimelineFanoutService) Start() {
+	if s.cache == nil {
+		return
+	}
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+}
+
+// Stop 新しい配信の受け付けを停止し、キューに残っている配信の完了を待つ
+func (s *TimelineFanoutService) Stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Enqueue 投稿をフォロワーのタイムラインへ配信するようキューに追加する
+// キューが満杯の場合は配信を諦める（キャッシュは有効期限切れ後にデータベースから作り直される）
+func (s *TimelineFanoutService) Enqueue(post *models.Post) {
+	s.enqueue(timelineFanoutJob{post: post})
+}
+
+// EnqueueAnnouncement システムアカウントのお知らせを、フォロー関係によらずキャッシュされているすべてのタイムラインへ配信するようキューに追加する
+// キャッシュされていないタイムラインはデータベースから取得する際にシステムアカウントの投稿を含める
+func (s *TimelineFanoutService) EnqueueAnnouncement(post *models.Post) {
+	s.enqueue(timelineFanoutJob{post: post, broadcast: true})
+}
+
+func (s *TimelineFanoutService) enqueue(job timelineFanoutJob) {
+	if s.cache == nil {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return
+	}
+
+	select {
+	case s.queue <- job:
+	default:
+		s.log.Warn("タイムライン配信: キューが満杯のため配信をスキップしました", "post_id", job.post.ID)
+	}
+}
+
+// Invalidate ユーザーのタイムラインのキャッシュを削除する（フォロー関係が変わった場合など）
+func (s *TimelineFanoutService) Invalidate(ctx context.Context, userID uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Invalidate(ctx, userID); err != nil {
+		s.log.Warn("タイムライン配信: キャッシュの削除に失敗しました", "error", err, "user_id", userID)
+	}
+}
+
+// HomeTimeline キャッシュからホームタイムラインの投稿を取得する
+// userIDsはフォロー中のユーザー・自分・システムアカウントのID。キャッシュを使用できない場合はokにfalseを返す
+func (s *TimelineFanoutService) HomeTimeline(ctx context.Context, userID uuid.UUID, userIDs []uuid.UUID, offset, limit int) (posts []*models.Post, total int64, ok bool) {
+	// フォロー数（自分を除く）が少ない場合や、キャッシュしている範囲を超える場合はデータベースから取得する
+	if s.cache == nil || len(userIDs)-1 < s.minFollowing || offset+limit > s.maxLength {
+		return nil, 0, false
+	}
+
+	exists, err := s.cache.Exists(ctx, userID)
+	if err != nil {
+		s.log.Warn("タイムライン配信: キャッシュの確認に失敗しました", "error", err, "user_id", userID)
+		return nil, 0, false
+	}
+	if !exists {
+		if err := s.rebuild(ctx, userID, userIDs); err != nil {
+			s.log.Warn("タイムライン配信: キャッシュの作成に失敗しました", "error", err, "user_id", userID)
+			return nil, 0, false
+		}
+	}
+
+	postIDs, length, err := s.cache.Range(ctx, userID, offset, limit)
+	if err != nil {
+		s.log.Warn("タイムライン配信: キャッシュの読み出しに失敗しました", "error", err, "user_id", userID)
+		return nil, 0, false
+	}
+
+	postsByID, err := s.postRepo.GetByIDs(ctx, postIDs)
+	if err != nil {
+		s.log.Error("タイムライン配信: 投稿の取得に失敗しました", "error", err)
+		return nil, 0, false
+	}
+
+	// 削除済みの投稿と、作り直しと配信が重なった場合の重複を除く
+	posts = make([]*models.Post, 0, len(postIDs))
+	seen := make(map[uuid.UUID]bool, len(postIDs))
+	for _, id := range postIDs {
+		post, found := postsByID[id]
+		if !found || seen[id] {
+			continue
+		}
+		seen[id] = true
+		posts = append(posts, post)
+	}
+	// 並行して配信されるため、追加順が作成順と前後する場合がある
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].CreatedAt.After(posts[j].CreatedAt)
+	})
+
+	// キャッシュが上限まで埋まっている場合はそれより古い投稿もあるため、総数はデータベースで数える
+	total = length
+	if length >= int64(s.maxLength) {
+		total, err = s.postRepo.CountByUserIDs(ctx, userIDs, userID)
+		if err != nil {
+			s.log.Error("タイムライン配信: 投稿数の取得に失敗しました", "error", err)
+			total = length
+		}
+	}
+
+	return posts, total, true
+}
+
+// rebuild データベースからタイムラインを取得してキャッシュを作り直す
+func (s *TimelineFanoutService) rebuild(ctx context.Context, userID uuid.UUID, userIDs []uuid.UUID) error {
+	posts, err := s.postRepo.GetByUserIDs(ctx, userIDs, userID, 0, s.maxLength)
+	if err != nil {
+		return err
+	}
+
+	postIDs := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+	return s.cache.Replace(ctx, userID, postIDs)
+}
+
+// worker キューから投稿を取り出して配信する
+func (s *TimelineFanoutService) worker() {
+	defer s.wg.Done()
+
+	for job := range s.queue {
+		if job.broadcast {
+			s.broadcast(job.post)
+		} else {
+			s.fanout(job.post)
+		}
+	}
+}
+
+// broadcast キャッシュされているすべてのタイムラインに投稿IDを追加する
+func (s *TimelineFanoutService) broadcast(post *models.Post) {
+	ctx, cancel := context.WithTimeout(context.Background(), timelineFanoutTimeout)
+	defer cancel()
+
+	if err := s.cache.PushAll(ctx, post.ID); err != nil {
+		s.log.Error("タイムライン配信: お知らせの配信に失敗しました", "error", err, "post_id", post.ID)
+	}
+}
+
+// fanout 投稿者自身とフォロワーのタイムラインのキャッシュに投稿IDを追加する
+func (s *TimelineFanoutService) fanout(post *models.Post) {
+	ctx, cancel := context.WithTimeout(context.Background(), timelineFanoutTimeout)
+	defer cancel()
+
+	if err := s.cache.Push(ctx, []uuid.UUID{post.UserID}, post.ID); err != nil {
+		s.log.Error("タイムライン配信: キャッシュへの追加に失敗しました", "error", err, "post_id", post.ID)
+		return
+	}
+
+	for offset := 0; ; offset += timelineFanoutFollowerPageSize {
+		followers, err := s.followRepo.GetFollowers(ctx, post.UserID, uuid.Nil, offset, timelineFanoutFollowerPageSize)
+		if err != nil {
+			s.log.Error("タイムライン配信: フォロワー取得エラー", "error", err, "post_id", post.ID)
+			return
+		}
+
+		if err := s.cache.Push(ctx, followers, post.ID); err != nil {
+			s.log.Error("タイムライン配信: キャッシュへの追加に失敗しました", "error", err, "post_id", post.ID)
+			return
+		}
+
+		if len(followers) < timelineFanoutFollowerPageSize {
+			return
+		}
+	}
+}
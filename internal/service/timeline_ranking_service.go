@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// RankedTimelineWindow おすすめ順で並べ替える最新の投稿の件数（それより古い投稿は新しい順で続ける）
+const RankedTimelineWindow = 200
+
+const (
+	// 投稿者とのやり取りを数える期間
+	timelineInteractionWindow = 30 * 24 * time.Hour
+	// やり取りの多い投稿者の投稿を押し上げる強さ
+	timelineInteractionBoost = 1.0
+	// 投稿が古くなるにつれて順位を下げる強さ
+	timelineAgeGravity = 1.5
+)
+
+// TimelineRankingService ホームタイムラインをおすすめ順に並べ替えるサービス
+// 新しい投稿ほど、また閲覧者がよくいいね・返信・リポストする投稿者の投稿ほど上位になる
+type TimelineRankingService struct {
+	postRepo interfaces.PostRepository
+	log      logger.Logger
+}
+
+// NewTimelineRankingService 新しいタイムライン並べ替えサービスを作成する
+func NewTimelineRankingService(
+	postRepo interfaces.PostRepository,
+	log logger.Logger,
+) *TimelineRankingService {
+	return &TimelineRankingService{
+		postRepo: postRepo,
+		log:      log,
+	}
+}
+
+// Rank postsをおすすめ順に並べ替えたスライスを返す（postsは変更しない）
+// やり取りの回数を取得できない場合は、投稿の新しさのみで並べ替える
+func (s *TimelineRankingService) Rank(ctx context.Context, userID uuid.UUID, posts []*models.Post) []*models.Post {
+	ranked := make([]*models.Post, len(posts))
+	copy(ranked, posts)
+	if len(ranked) == 0 {
+		return ranked
+	}
+
+	authorIDs := make([]uuid.UUID, 0, len(ranked))
+	seen := make(map[uuid.UUID]bool, len(ranked))
+	for _, post := range ranked {
+		if !seen[post.UserID] {
+			seen[post.UserID] = true
+			authorIDs = append(authorIDs, post.UserID)
+		}
+	}
+
+	now := time.Now()
+	interactions, err := s.postRepo.GetInteractionCounts(ctx, userID, authorIDs, now.Add(-timelineInteractionWindow))
+	if err != nil {
+		s.log.Warn("タイムラインの並べ替え: やり取りの回数の取得に失敗しました", "error", err, "user_id", userID)
+		interactions = map[uuid.UUID]int{}
+	}
+
+	scores := make(map[uuid.UUID]float64, len(ranked))
+	for _, post := range ranked {
+		scores[post.ID] = timelineScore(post, interactions[post.UserID], now)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i].ID] > scores[ranked[j].ID]
+	})
+
+	return ranked
+}
+
+// timelineScore 投稿の経過時間と投稿者とのやり取りの回数からおすすめ順のスコアを計算する
+func timelineScore(post *models.Post, interactions int, now time.Time) float64 {
+	ageHours := math.Max(now.Sub(post.CreatedAt).Hours(), 0)
+	boost := 1 + timelineInteractionBoost*math.Log1p(float64(interactions))
+	return boost / math.Pow(ageHours+2, timelineAgeGravity)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/internal/websocket"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// フォロワー一覧を取得する際のページサイズ
+const timelineUpdateFollowerPageSize = 1000
+
+// TimelineUpdateService 新着投稿をフォロワーのタイムラインへ知らせるサービス
+type TimelineUpdateService struct {
+	followRepo    interfaces.FollowRepository
+	postRepo      interfaces.PostRepository
+	blockService  *BlockService
+	contentPolicy *ContentPolicyService
+	hub           *websocket.Hub
+	log           logger.Logger
+}
+
+// NewTimelineUpdateService 新しいタイムライン更新サービスを作成し、ストリームの購読の確認と配信先の絞り込みをハブに登録する
+func NewTimelineUpdateService(
+	followRepo interfaces.FollowRepository,
+	postRepo interfaces.PostRepository,
+	blockService *BlockService,
+	contentPolicy *ContentPolicyService,
+	hub *websocket.Hub,
+	log logger.Logger,
+) *TimelineUpdateService {
+	s := &TimelineUpdateService{
+		followRepo:    followRepo,
+		postRepo:      postRepo,
+		blockService:  blockService,
+		contentPolicy: contentPolicy,
+		hub:           hub,
+		log:           log,
+	}
+	hub.SetStreamAuthorizer(s.AuthorizeSubscription)
+	hub.SetStreamFilter(s.FilterStreamRecipients)
+	return s
+}
+
+// AuthorizeSubscription ストリームの購読を許可するかを確認する
+// 返信のストリームは、返信先の投稿を閲覧できる（投稿者とブロック関係になく、年齢制限を満たす）場合のみ購読できる
+// ブロック関係の有無を知られないよう、閲覧できない投稿は存在しないものとして扱う
+func (s *TimelineUpdateService) AuthorizeSubscription(ctx context.Context, userID uuid.UUID, subscription websocket.Subscription) error {
+	if subscription.Stream != websocket.StreamReplies || subscription.PostID == nil {
+		return nil
+	}
+	notFound := websocket.NewCommandError(websocket.ErrorCodeNotFound, "post not found")
+
+	post, err := s.postRepo.GetByID(ctx, *subscription.PostID)
+	if err != nil {
+		if err.Error() == "post not found" {
+			return notFound
+		}
+		return err
+	}
+
+	if err := s.blockService.CheckInteraction(ctx, userID, post.UserID); err != nil {
+		if errors.Is(err, ErrBlocked) {
+			return notFound
+		}
+		return err
+	}
+
+	viewer, err := s.contentPolicy.LoadViewer(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !s.contentPolicy.CanView(viewer, post) {
+		return notFound
+	}
+
+	return nil
+}
+
+// FilterStreamRecipients ストリームの購読者のうち、投稿者とブロック関係にないユーザーを返す
+func (s *TimelineUpdateService) FilterStreamRecipients(ctx context.Context, authorID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	return s.blockService.FilterUserIDs(ctx, authorID, userIDs)
+}
+
+// PublishNewPost 接続中のフォロワーに新着投稿のヒントを送信し、ホームタイムライン・返信・ハッシュタグのストリームに配信する
+// ヒントには投稿内容を含めないため、年齢制限の判定はクライアントが件数や投稿を取得する際に行われる
+// ストリームへの配信は投稿者とブロック関係にある購読者を除く
+func (s *TimelineUpdateService) PublishNewPost(ctx context.Context, post *models.Post) {
+	message := websocket.NewTimelineUpdateMessage(websocket.TimelineUpdateEvent{
+		Timeline:  "home",
+		PostID:    post.ID,
+		AuthorID:  post.UserID,
+		CreatedAt: post.CreatedAt,
+	})
+	homeMessage := newStreamPostMessage(post, websocket.StreamHome, "")
+
+	s.PublishToStreams(post)
+
+	// 投稿者自身のホームタイムラインにも並ぶ
+	if err := s.hub.PublishStreams([]string{websocket.HomeStreamKey(post.UserID)}, post.UserID, homeMessage); err != nil {
+		s.log.Warn("タイムライン更新: ストリーム送信エラー", "error", err)
+	}
+
+	for offset := 0; ; offset += timelineUpdateFollowerPageSize {
+		followers, err := s.followRepo.GetFollowers(ctx, post.UserID, uuid.Nil, offset, timelineUpdateFollowerPageSize)
+		if err != nil {
+			s.log.Error("タイムライン更新: フォロワー取得エラー", "error", err)
+			return
+		}
+
+		// 他のインスタンスに接続しているフォロワーにも届くよう、ページごとにまとめて送信する
+		if err := s.hub.NotifyUsers(followers, message); err != nil {
+			s.log.Warn("タイムライン更新: WebSocket送信エラー", "error", err)
+		}
+		keys := make([]string, len(followers))
+		for i, followerID := range followers {
+			keys[i] = websocket.HomeStreamKey(followerID)
+		}
+		if err := s.hub.PublishStreams(keys, post.UserID, homeMessage); err != nil {
+			s.log.Warn("タイムライン更新: ストリーム送信エラー", "error", err)
+		}
+
+		if len(followers) < timelineUpdateFollowerPageSize {
+			return
+		}
+	}
+}
+
+// PublishToStreams 返信先の投稿の返信ストリームと、投稿に含まれるハッシュタグのストリームに新着投稿を配信する
+// スレッドの2件目以降の投稿のように、フォロワーへのヒントを送らない投稿にも使う
+func (s *TimelineUpdateService) PublishToStreams(post *models.Post) {
+	if post.ReplyToID != nil {
+		message := newStreamPostMessage(post, websocket.StreamReplies, "")
+		if err := s.hub.PublishStreams([]string{websocket.RepliesStreamKey(*post.ReplyToID)}, post.UserID, message); err != nil {
+			s.log.Warn("タイムライン更新: ストリーム送信エラー", "error", err)
+		}
+	}
+
+	for _, tag := range post.Hashtags() {
+		message := newStreamPostMessage(post, websocket.StreamHashtag, tag)
+		if err := s.hub.PublishStreams([]string{websocket.HashtagStreamKey(tag)}, post.UserID, message); err != nil {
+			s.log.Warn("タイムライン更新: ストリーム送信エラー", "error", err)
+		}
+	}
+}
+
+// newStreamPostMessage ストリームの新着投稿メッセージを作成する
+func newStreamPostMessage(post *models.Post, stream websocket.Stream, tag string) *websocket.WebSocketMessage {
+	return websocket.NewStreamPostMessage(websocket.StreamPostEvent{
+		Stream:    stream,
+		Tag:       tag,
+		PostID:    post.ID,
+		AuthorID:  post.UserID,
+		ReplyToID: post.ReplyToID,
+		CreatedAt: post.CreatedAt,
+	})
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+// ErrUnknownTopic 指定されたトピックが存在しない場合のエラー
+var ErrUnknownTopic = errors.New("unknown topic")
+
+// TopicService 投稿を探索タブのトピックに分類するサービス
+// 投稿者が指定したトピックと、投稿のハッシュタグを登録しているトピックに分類する
+type TopicService struct {
+	topicRepo interfaces.TopicRepository
+	log       logger.Logger
+}
+
+// NewTopicService 新しいトピックサービスを作成する
+func NewTopicService(
+	topicRepo interfaces.TopicRepository,
+	log logger.Logger,
+) *TopicService {
+	return &TopicService{
+		topicRepo: topicRepo,
+		log:       log,
+	}
+}
+
+// ResolveSlugs 投稿者が指定したトピックのスラッグを正規化し、重複を除いて返す
+// 存在しないトピックが含まれる場合は ErrUnknownTopic を返す
+func (s *TopicService) ResolveSlugs(ctx context.Context, slugs []string) ([]string, error) {
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+
+	topics, err := s.topicRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		known[topic.Slug] = true
+	}
+
+	resolved := make([]string, 0, len(slugs))
+	seen := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		normalized, ok := models.NormalizeTopicSlug(slug)
+		if !ok || !known[normalized] {
+			return nil, ErrUnknownTopic
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			resolved = append(resolved, normalized)
+		}
+	}
+
+	return resolved, nil
+}
+
+// TagPost 投稿をトピックに分類し、分類されたトピックを返す
+// 分類に失敗しても投稿自体は作成済みのため、エラーは記録するだけで空のスライスを返す
+func (s *TopicService) TagPost(ctx context.Context, post *models.Post, manualSlugs []string) []*models.Topic {
+	if err := s.topicRepo.TagPost(ctx, post.ID, manualSlugs, post.Hashtags()); err != nil {
+		s.log.Warn("投稿のトピックへの分類に失敗しました", "error", err, "post_id", post.ID)
+		return []*models.Topic{}
+	}
+
+	topics, err := s.topicRepo.GetByPostID(ctx, post.ID)
+	if err != nil {
+		s.log.Warn("投稿のトピックの取得に失敗しました", "error", err, "post_id", post.ID)
+		return []*models.Topic{}
+	}
+	return topics
+}
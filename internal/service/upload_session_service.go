@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	repointerfaces "github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	// 期限切れのセッションを削除する間隔と、1回の削除にかける最大時間
+	uploadSessionCleanupInterval = 10 * time.Minute
+	uploadSessionCleanupTimeout  = time.Minute
+	// 1回に削除する期限切れのセッションの最大数
+	uploadSessionCleanupBatchSize = 100
+)
+
+var (
+	// ErrInvalidUploadPart パートの番号がセッションの範囲外
+	ErrInvalidUploadPart = errors.New("invalid upload part")
+	// ErrUploadIncomplete 受信していないパートがあるため完了できない
+	ErrUploadIncomplete = errors.New("upload incomplete")
+	// ErrUploadCompleted 完了したセッションにはパートを送信できない
+	ErrUploadCompleted = errors.New("upload already completed")
+)
+
+// UploadSessionService 大きなファイルをパートに分けてアップロードするセッションを管理するサービス
+// パートは一時的なストアに保存し、すべて揃った時点でサーバー側で結合してメディアとして保存する
+// 接続が切れた場合は、受信済みのパートを確認して不足しているパートのみを送信すれば再開できる
+type UploadSessionService struct {
+	sessionRepo repointerfaces.UploadSessionRepository
+	parts       interfaces.UploadPartStore
+	media       *MediaService
+	partSize    int64
+	ttl         time.Duration
+	log         logger.Logger
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewUploadSessionService 新しい分割アップロードのサービスを作成する
+func NewUploadSessionService(
+	sessionRepo repointerfaces.UploadSessionRepository,
+	parts interfaces.UploadPartStore,
+	media *MediaService,
+	partSize int64,
+	ttl time.Duration,
+	log logger.Logger,
+) *UploadSessionService {
+	if partSize <= 0 {
+		partSize = 5 * 1024 * 1024
+	}
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	return &UploadSessionService{
+		sessionRepo: sessionRepo,
+		parts:       parts,
+		media:       media,
+		partSize:    partSize,
+		ttl:         ttl,
+		log:         log,
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+}
+
+// Start 期限切れのセッションの定期的な削除を開始する
+func (s *UploadSessionService) Start() {
+	go s.run()
+}
+
+// Stop 期限切れのセッションの定期的な削除を停止する
+func (s *UploadSessionService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// Initiate totalSizeバイトのファイルのアップロードを開始する（大きさとファイル形式は呼び出し元で検証済み）
+func (s *UploadSessionService) Initiate(ctx context.Context, userID uuid.UUID, filename string, totalSize int64) (*models.UploadSession, error) {
+	session := models.NewUploadSession(userID, filepath.Base(filename), totalSize, s.partSize, s.ttl)
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// UploadPart パートを保存して受信済みとして記録する
+// 同じパートは何度送信してもよく、大きさが異なる場合は受信済みのパートを残したままinterfaces.ErrUploadPartSizeを返す
+func (s *UploadSessionService) UploadPart(ctx context.Context, session *models.UploadSession, index int, content io.Reader) (*models.UploadSession, error) {
+	if session.IsCompleted() {
+		return nil, ErrUploadCompleted
+	}
+	size := session.PartLength(index)
+	if size < 0 {
+		return nil, ErrInvalidUploadPart
+	}
+
+	if err := s.parts.WritePart(ctx, session.ID.String(), index, content, size); err != nil {
+		return nil, err
+	}
+
+	return s.sessionRepo.AddPart(ctx, session.ID, index)
+}
+
+// Complete パートを結合してメディアとして保存し、保存先を記録する（完了済みの場合はそのまま返す）
+// 動画の場合はmaxDurationを超える再生時間を拒否し、ユーザーのメディアの使用量が上限を超える場合はErrStorageQuotaExceededを返す
+func (s *UploadSessionService) Complete(ctx context.Context, session *models.UploadSession, maxDuration time.Duration) (*models.UploadSession, error) {
+	if session.IsCompleted() {
+		return session, nil
+	}
+	if !session.IsReady() {
+		return nil, ErrUploadIncomplete
+	}
+
+	if err := s.media.ReserveStorage(ctx, session.UserID, session.TotalSize); err != nil {
+		return nil, err
+	}
+
+	fileURL, err := s.store(ctx, session, maxDuration)
+	if err != nil {
+		s.media.RefundStorage(ctx, session.UserID, session.TotalSize)
+		return nil, err
+	}
+
+	completed, err := s.sessionRepo.Complete(ctx, session.ID, fileURL)
+	if err != nil {
+		// 記録できなかった場合は保存したメディアへの参照と使用量を戻す
+		if releaseErr := s.media.ReleaseFor(ctx, session.UserID, fileURL, uuid.Nil); releaseErr != nil {
+			s.log.Warn("保存したファイルへの参照を外せませんでした", "error", releaseErr, "url", fileURL)
+		}
+		return nil, err
+	}
+
+	// 結合後のパートは不要（削除に失敗した場合もセッションの期限切れで削除される）
+	if err := s.parts.DeleteParts(ctx, session.ID.String()); err != nil {
+		s.log.Warn("分割アップロードのパートの削除に失敗しました", "error", err, "session_id", session.ID)
+	}
+
+	return completed, nil
+}
+
+// store パートを結合してメディアとして保存し、URLを返す
+func (s *UploadSessionService) store(ctx context.Context, session *models.UploadSession, maxDuration time.Duration) (string, error) {
+	file, err := s.parts.Assemble(ctx, session.ID.String(), session.PartCount)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if models.MediaTypeFromExtension(filepath.Ext(session.Filename)) == models.MediaTypeVideo {
+		return s.media.StoreVideo(ctx, session.UserID, session.Filename, file, maxDuration)
+	}
+	return s.media.Store(ctx, session.UserID, session.Filename, file)
+}
+
+// Abort アップロードを中止し、受信済みのパートとセッションを削除する
+func (s *UploadSessionService) Abort(ctx context.Context, session *models.UploadSession) error {
+	if err := s.parts.DeleteParts(ctx, session.ID.String()); err != nil {
+		return err
+	}
+	return s.sessionRepo.Delete(ctx, session.ID)
+}
+
+// run 停止されるまで一定間隔で期限切れのセッションを削除する
+func (s *UploadSessionService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(uploadSessionCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.cleanup()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *UploadSessionService) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), uploadSessionCleanupTimeout)
+	defer cancel()
+
+	sessions, err := s.sessionRepo.ListExpired(ctx, time.Now().UTC(), uploadSessionCleanupBatchSize)
+	if err != nil {
+		s.log.Error("期限切れの分割アップロードの取得に失敗しました", "error", err)
+		return
+	}
+
+	deleted := 0
+	for _, session := range sessions {
+		if err := s.Abort(ctx, session); err != nil {
+			s.log.Error("期限切れの分割アップロードの削除に失敗しました", "error", err, "session_id", session.ID)
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		s.log.Info("期限切れの分割アップロードを削除しました", "sessions", deleted)
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// 毎日の集計で再計算する日数（D30リテンションが確定するまでの期間）
+const cohortRollupDays = 31
+
+// 集計ジョブ1回にかける最大時間
+const cohortRollupTimeout = 5 * time.Minute
+
+// UserStatsService ユーザーの活動を記録し、登録日ごとのコホート統計を毎日集計するサービス
+type UserStatsService struct {
+	statsRepo  interfaces.UserStatsRepository
+	rollupHour int
+	log        logger.Logger
+
+	// 同じ日に同じユーザーの活動を何度も書き込まないよう、その日に記録したユーザーを保持する
+	mu           sync.Mutex
+	activityDate string
+	recorded     map[uuid.UUID]struct{}
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewUserStatsService 新しいユーザー統計サービスを作成する
+// rollupHourはコホート統計を毎日集計する時刻（UTCの時）
+func NewUserStatsService(
+	statsRepo interfaces.UserStatsRepository,
+	rollupHour int,
+	log logger.Logger,
+) *UserStatsService {
+	if rollupHour < 0 || rollupHour > 23 {
+		rollupHour = 3
+	}
+
+	return &UserStatsService{
+		statsRepo:  statsRepo,
+		rollupHour: rollupHour,
+		log:        log,
+		recorded:   make(map[uuid.UUID]struct{}),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// Start 毎日のコホート統計の集計を開始する
+func (s *UserStatsService) Start() {
+	go s.run()
+}
+
+// Stop 毎日のコホート統計の集計を停止する
+func (s *UserStatsService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// RecordActivity ユーザーの活動を記録する（1日1回のみ書き込む）
+func (s *UserStatsService) RecordActivity(userID uuid.UUID) {
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+
+	s.mu.Lock()
+	if s.activityDate != today {
+		s.activityDate = today
+		s.recorded = make(map[uuid.UUID]struct{})
+	}
+	if _, ok := s.recorded[userID]; ok {
+		s.mu.Unlock()
+		return
+	}
+	s.recorded[userID] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := s.statsRepo.RecordActivity(ctx, userID, now); err != nil {
+			s.log.Error("ユーザー活動の記録に失敗しました", "error", err)
+
+			// 次のリクエストで再試行する
+			s.mu.Lock()
+			delete(s.recorded, userID)
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// Rollup fromからtoまでに登録したユーザーのコホート統計を集計する
+func (s *UserStatsService) Rollup(ctx context.Context, from, to time.Time) (int64, error) {
+	return s.statsRepo.RollupCohorts(ctx, from, to, time.Now().UTC())
+}
+
+// GetCohorts fromからtoまでのコホート統計を取得する
+func (s *UserStatsService) GetCohorts(ctx context.Context, from, to time.Time) ([]*models.CohortStats, error) {
+	return s.statsRepo.GetCohorts(ctx, from, to)
+}
+
+// run 停止されるまで毎日決まった時刻にコホート統計を集計する
+func (s *UserStatsService) run() {
+	defer close(s.doneCh)
+
+	for {
+		timer := time.NewTimer(time.Until(s.nextRollup(time.Now().UTC())))
+		select {
+		case <-timer.C:
+			s.rollupRecent()
+		case <-s.stopCh:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// nextRollup now以降で次に集計する時刻を返す
+func (s *UserStatsService) nextRollup(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.rollupHour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// rollupRecent リテンションが確定していない直近のコホートを再集計する
+func (s *UserStatsService) rollupRecent() {
+	ctx, cancel := context.WithTimeout(context.Background(), cohortRollupTimeout)
+	defer cancel()
+
+	to := time.Now().UTC().AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -cohortRollupDays)
+
+	count, err := s.Rollup(ctx, from, to)
+	if err != nil {
+		s.log.Error("コホート統計の集計に失敗しました", "error", err)
+		return
+	}
+	s.log.Info("コホート統計を集計しました", "cohorts", count)
+}
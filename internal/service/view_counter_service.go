@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// 停止時の最終書き込みにかける最大時間
+const viewFlushTimeout = 5 * time.Second
+
+// ViewCounterService 投稿の閲覧数をメモリ上で集計し、一定間隔でまとめてデータベースへ書き込むサービス
+// 閲覧のたびにUPDATEを発行すると人気の投稿の行に書き込みが集中するため、投稿ごとに件数をまとめてから書き込む
+type ViewCounterService struct {
+	viewRepo      interfaces.PostViewRepository
+	flushInterval time.Duration
+	maxPending    int
+	log           logger.Logger
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]int64
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewViewCounterService 新しい閲覧数集計サービスを作成する
+func NewViewCounterService(
+	viewRepo interfaces.PostViewRepository,
+	flushInterval time.Duration,
+	maxPending int,
+	log logger.Logger,
+) *ViewCounterService {
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+	if maxPending <= 0 {
+		maxPending = 1000
+	}
+
+	return &ViewCounterService{
+		viewRepo:      viewRepo,
+		flushInterval: flushInterval,
+		maxPending:    maxPending,
+		log:           log,
+		pending:       make(map[uuid.UUID]int64),
+		flushCh:       make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+}
+
+// Start 定期的な書き込みを開始する
+func (s *ViewCounterService) Start() {
+	go s.run()
+}
+
+// Stop 定期的な書き込みを停止し、未書き込みの閲覧数を書き込む
+func (s *ViewCounterService) Stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+// RecordView 投稿の閲覧を1件記録する
+func (s *ViewCounterService) RecordView(postID uuid.UUID) {
+	s.mu.Lock()
+	s.pending[postID]++
+	full := len(s.pending) >= s.maxPending
+	s.mu.Unlock()
+
+	// 集計中の投稿が多い場合は間隔を待たずに書き込む
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// PendingViews まだデータベースに書き込まれていない閲覧数を返す
+func (s *ViewCounterService) PendingViews(postID uuid.UUID) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending[postID]
+}
+
+// Flush 集計中の閲覧数をデータベースへ書き込む
+// 書き込みに失敗した場合は次回の書き込みで再試行するため、件数を集計中の状態に戻す
+func (s *ViewCounterService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	counts := s.pending
+	s.pending = make(map[uuid.UUID]int64, len(counts))
+	s.mu.Unlock()
+
+	if err := s.viewRepo.AddViews(ctx, time.Now(), counts); err != nil {
+		s.mu.Lock()
+		for id, count := range counts {
+			s.pending[id] += count
+		}
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// run 停止されるまで一定間隔で閲覧数を書き込む
+func (s *ViewCounterService) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.flushCh:
+			s.flush()
+		case <-s.stopCh:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *ViewCounterService) flush() {
+	ctx, cancel := context.WithTimeout(context.Background(), viewFlushTimeout)
+	defer cancel()
+
+	if err := s.Flush(ctx); err != nil {
+		s.log.Error("閲覧数の書き込みに失敗しました", "error", err)
+	}
+}
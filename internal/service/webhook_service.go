@@ -0,0 +1,342 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/TakuyaAizawa/gox/internal/repository/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ErrWebhookLimitReached はユーザーが登録できるWebhookの上限に達していることを表す
+var ErrWebhookLimitReached = errors.New("webhook limit reached")
+
+// ErrInvalidWebhookURL はWebhookのURLが送信先として使えないことを表す
+var ErrInvalidWebhookURL = errors.New("invalid webhook url")
+
+// errPrivateNetwork はプライベートネットワークのアドレスへの接続を拒否したことを表す
+var errPrivateNetwork = errors.New("webhook destination is a private network address")
+
+const (
+	// 送信するHTTPヘッダー（署名は「タイムスタンプ.本文」のHMAC-SHA256で、"sha256=<16進数>" の形式）
+	webhookEventHeader     = "X-Gox-Event"
+	webhookDeliveryHeader  = "X-Gox-Delivery"
+	webhookTimestampHeader = "X-Gox-Timestamp"
+	webhookSignatureHeader = "X-Gox-Signature"
+	// 送信失敗時にエラーとして記録する応答の最大バイト数
+	webhookMaxErrorBody = 256
+)
+
+// webhookJob 送信キューに積むイベント
+type webhookJob struct {
+	userID  uuid.UUID
+	payload *models.WebhookPayload
+}
+
+// WebhookService ユーザーが登録した個人用Webhookを管理し、ユーザー自身へのイベント（フォロー・メンション）を送信するサービス
+// 送信はキューを通じてワーカーが行い、連続して失敗したWebhookは無効にする
+type WebhookService struct {
+	webhookRepo interfaces.WebhookRepository
+	client      *http.Client
+	maxPerUser  int
+	maxFailures int
+	workers     int
+	log         logger.Logger
+
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan webhookJob
+	wg      sync.WaitGroup
+}
+
+// NewWebhookService 新しいWebhookサービスを作成する
+// allowPrivateNetworksがfalseの場合、名前解決の結果がプライベートネットワークのアドレスになる送信先には接続しない
+func NewWebhookService(
+	webhookRepo interfaces.WebhookRepository,
+	maxPerUser int,
+	timeout time.Duration,
+	maxFailures int,
+	workers int,
+	queueSize int,
+	allowPrivateNetworks bool,
+	log logger.Logger,
+) *WebhookService {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if workers <= 0 {
+		workers = 4
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = rejectPrivateNetwork
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// リダイレクト先は登録時に確認していないため追わない
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxPerUser:  maxPerUser,
+		maxFailures: maxFailures,
+		workers:     workers,
+		log:         log,
+		queue:       make(chan webhookJob, queueSize),
+	}
+}
+
+// Start 送信ワーカーを開始する
+func (s *WebhookService) Start() {
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+}
+
+// Stop 新しいイベントの受け付けを停止し、キューに残っているイベントの送信を待つ
+func (s *WebhookService) Stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	s.client.CloseIdleConnections()
+}
+
+// Register Webhookを登録し、署名用のシークレットを生成する
+func (s *WebhookService) Register(ctx context.Context, userID uuid.UUID, rawURL string, events []models.WebhookEvent) (*models.UserWebhook, error) {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	count, err := s.webhookRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.maxPerUser > 0 && count >= s.maxPerUser {
+		return nil, ErrWebhookLimitReached
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := models.NewUserWebhook(userID, rawURL, events, secret)
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Update WebhookのURL・イベント・有効状態を更新する
+// 無効になっていたWebhookを有効にする場合は、連続した失敗の回数をリセットする
+func (s *WebhookService) Update(ctx context.Context, webhook *models.UserWebhook) error {
+	if err := validateWebhookURL(webhook.URL); err != nil {
+		return err
+	}
+	if webhook.Active {
+		webhook.FailureCount = 0
+	}
+	return s.webhookRepo.Update(ctx, webhook)
+}
+
+// RotateSecret 署名用のシークレットを新しく生成して置き換え、新しいシークレットを返す
+func (s *WebhookService) RotateSecret(ctx context.Context, webhook *models.UserWebhook) (string, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	if err := s.webhookRepo.UpdateSecret(ctx, webhook.ID, secret); err != nil {
+		return "", err
+	}
+	webhook.Secret = secret
+	return secret, nil
+}
+
+// SendTest テスト用のイベントをすぐに送信し、結果を返す（結果は失敗回数に含めない）
+func (s *WebhookService) SendTest(ctx context.Context, webhook *models.UserWebhook) *models.WebhookDelivery {
+	payload := newWebhookPayload(models.WebhookEventTest, map[string]interface{}{
+		"webhook_id": webhook.ID,
+		"message":    "Webhookのテスト送信です",
+	})
+	return s.deliver(ctx, webhook, payload)
+}
+
+// Dispatch ユーザーのイベントを、イベントを受け取る有効なWebhookへ送信するようキューに追加する
+// キューが満杯か停止中の場合はErrDeliveryUnavailableを返す
+func (s *WebhookService) Dispatch(userID uuid.UUID, event models.WebhookEvent, data interface{}) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return ErrDeliveryUnavailable
+	}
+
+	select {
+	case s.queue <- webhookJob{userID: userID, payload: newWebhookPayload(event, data)}:
+		return nil
+	default:
+		s.log.Warn("Webhook: キューが満杯のため送信を受け付けられませんでした", "user_id", userID, "event", event)
+		return ErrDeliveryUnavailable
+	}
+}
+
+func (s *WebhookService) worker() {
+	defer s.wg.Done()
+	for job := range s.queue {
+		s.send(job)
+	}
+}
+
+// send イベントを受け取るユーザーのWebhookへ送信し、結果を記録する
+func (s *WebhookService) send(job webhookJob) {
+	ctx := context.Background()
+
+	webhooks, err := s.webhookRepo.ListActiveByEvent(ctx, job.userID, job.payload.Event)
+	if err != nil {
+		s.log.Error("Webhook: 送信先の取得に失敗しました", "error", err, "user_id", job.userID)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		delivery := s.deliver(ctx, webhook, job.payload)
+
+		var statusCode *int
+		if delivery.StatusCode != 0 {
+			statusCode = &delivery.StatusCode
+		}
+		active, err := s.webhookRepo.RecordDelivery(ctx, webhook.ID, statusCode, delivery.Delivered, s.maxFailures)
+		if err != nil {
+			s.log.Error("Webhook: 送信結果の記録に失敗しました", "error", err, "webhook_id", webhook.ID)
+			continue
+		}
+
+		if !delivery.Delivered {
+			s.log.Warn("Webhook: 送信に失敗しました", "webhook_id", webhook.ID, "event", job.payload.Event, "error", delivery.Error)
+		}
+		if !active {
+			s.log.Warn("Webhook: 連続して送信に失敗したため無効にしました", "webhook_id", webhook.ID, "user_id", webhook.UserID)
+		}
+	}
+}
+
+// deliver 署名を付けてペイロードを送信する（2xx以外の応答は失敗とする）
+func (s *WebhookService) deliver(ctx context.Context, webhook *models.UserWebhook, payload *models.WebhookPayload) *models.WebhookDelivery {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &models.WebhookDelivery{Error: err.Error()}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return &models.WebhookDelivery{Error: err.Error()}
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoX-Webhook/1.0")
+	req.Header.Set(webhookEventHeader, string(payload.Event))
+	req.Header.Set(webhookDeliveryHeader, payload.ID.String())
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, signWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return &models.WebhookDelivery{Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	delivery := &models.WebhookDelivery{StatusCode: resp.StatusCode}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		delivery.Delivered = true
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		return delivery
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxErrorBody))
+	delivery.Error = fmt.Sprintf("送信先がステータス%dを返しました: %s", resp.StatusCode, bytes.TrimSpace(message))
+	return delivery
+}
+
+// newWebhookPayload 新しい配信IDでペイロードを作成する
+func newWebhookPayload(event models.WebhookEvent, data interface{}) *models.WebhookPayload {
+	return &models.WebhookPayload{
+		ID:        uuid.New(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+// signWebhookPayload 「タイムスタンプ.本文」のHMAC-SHA256による署名を "sha256=<16進数>" の形式で返す
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookSecret 推測できない署名用のシークレットを返す
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// validateWebhookURL WebhookのURLがhttp・httpsの絶対URLで、認証情報を含まないかを確認する
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Hostname() == "" || parsed.User != nil {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// rejectPrivateNetwork 接続先がループバック・プライベート・リンクローカルなどのアドレスの場合に接続を拒否する
+// 名前解決の後に確認するため、DNSの応答を変えて内部のアドレスへ送信させることはできない
+func rejectPrivateNetwork(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errPrivateNetwork
+	}
+	return nil
+}
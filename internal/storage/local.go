@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/TakuyaAizawa/gox/internal/interfaces"
@@ -71,6 +72,51 @@ func (s *LocalStorage) SaveFile(ctx context.Context, path string, filename strin
 	return publicURL, nil
 }
 
+// WriteFile はベースディレクトリからの相対パスにファイルを保存します
+// 書き込み途中のファイルが読まれないよう、一時ファイルに書き込んでから置き換えます
+func (s *LocalStorage) WriteFile(ctx context.Context, path string, fileContent io.Reader) (string, error) {
+	// ベースディレクトリの外に書き込めないようにする
+	relPath := filepath.Clean("/" + path)
+	fullPath := filepath.Join(s.baseDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("ファイルの作成に失敗しました: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, fileContent); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), fullPath); err != nil {
+		return "", fmt.Errorf("ファイルの置き換えに失敗しました: %w", err)
+	}
+
+	return s.baseURL + filepath.ToSlash(relPath), nil
+}
+
+// OpenFile は公開URLに対応するローカルファイルを開きます
+func (s *LocalStorage) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	relPath, ok := s.PathFromURL(fileURL)
+	if !ok {
+		return nil, fmt.Errorf("このストレージのURLではありません: %s", fileURL)
+	}
+
+	file, err := os.Open(filepath.Join(s.baseDir, relPath))
+	if err != nil {
+		return nil, fmt.Errorf("ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	return file, nil
+}
+
 // DeleteFile はローカルファイルシステムからファイルを削除します
 func (s *LocalStorage) DeleteFile(ctx context.Context, path string) error {
 	fullPath := filepath.Join(s.baseDir, path)
@@ -88,6 +134,17 @@ func (s *LocalStorage) DeleteFile(ctx context.Context, path string) error {
 	return nil
 }
 
+// PathFromURL は公開URLをベースディレクトリからの相対パスに変換します
+func (s *LocalStorage) PathFromURL(fileURL string) (string, bool) {
+	prefix := s.baseURL + "/"
+	if !strings.HasPrefix(fileURL, prefix) {
+		return "", false
+	}
+
+	// ベースディレクトリの外を参照できないようにする
+	return filepath.Clean("/" + strings.TrimPrefix(fileURL, prefix)), true
+}
+
 // GetSignedURL はローカルストレージでは実際に署名URLは使用しないため、単純にURLを返します
 func (s *LocalStorage) GetSignedURL(ctx context.Context, path string, expires time.Duration) (string, error) {
 	// ローカルストレージでは署名URLは不要のため、通常のURLを返す
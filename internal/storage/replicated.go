@@ -0,0 +1,353 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+const (
+	// 1件の複製ジョブにかける最大時間
+	replicationJobTimeout = 2 * time.Minute
+	// 複製元・複製先のファイルを読み込むためのURLの有効期間
+	replicationURLExpiry = time.Hour
+	// プライマリの死活確認に使用するファイル
+	replicationProbePath = ".replication/probe"
+)
+
+// replicationOp は複製ジョブの種類です
+type replicationOp string
+
+const (
+	replicationCopy   replicationOp = "copy"
+	replicationDelete replicationOp = "delete"
+)
+
+// replicationJob はセカンダリへの複製・削除のジョブです
+type replicationJob struct {
+	op       replicationOp
+	path     string
+	attempts int
+}
+
+// ReplicatedStorage はプライマリのストレージに保存したファイルを、非同期でセカンダリ（別のバケット・リージョン）へ複製するストレージプロバイダーです
+// 保存・削除はプライマリで完了した時点で返し、セカンダリへの複製はキューに入れたジョブとしてワーカーが行います
+// 複製したファイルはハッシュを比較して検証し、失敗したジョブは間隔を空けて再試行します
+// プライマリを定期的に確認し、利用できない間は読み込みをセカンダリへ振り替えます
+// キューはメモリ上にあるため、再起動時に処理されていないジョブは失われます
+type ReplicatedStorage struct {
+	primary        interfaces.StorageProvider
+	secondary      interfaces.StorageProvider
+	workers        int
+	maxAttempts    int
+	healthInterval time.Duration
+	log            logger.Logger
+
+	jobs      chan *replicationJob
+	primaryUp atomic.Bool
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewReplicatedStorage は新しいReplicatedStorageインスタンスを作成します
+func NewReplicatedStorage(
+	primary interfaces.StorageProvider,
+	secondary interfaces.StorageProvider,
+	workers int,
+	queueSize int,
+	maxAttempts int,
+	healthInterval time.Duration,
+	log logger.Logger,
+) *ReplicatedStorage {
+	if workers <= 0 {
+		workers = 2
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	if healthInterval <= 0 {
+		healthInterval = 30 * time.Second
+	}
+
+	s := &ReplicatedStorage{
+		primary:        primary,
+		secondary:      secondary,
+		workers:        workers,
+		maxAttempts:    maxAttempts,
+		healthInterval: healthInterval,
+		log:            log,
+		jobs:           make(chan *replicationJob, queueSize),
+		stopCh:         make(chan struct{}),
+	}
+	s.primaryUp.Store(true)
+	return s
+}
+
+// Start は複製のワーカーとプライマリの死活確認を開始します
+func (s *ReplicatedStorage) Start() {
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.runWorker()
+	}
+	s.wg.Add(1)
+	go s.runHealthCheck()
+}
+
+// Stop は複製のワーカーとプライマリの死活確認を停止します（処理中のジョブは完了を待ちます）
+func (s *ReplicatedStorage) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// SaveFile はファイルをプライマリに保存し、セカンダリへの複製をキューに入れます
+func (s *ReplicatedStorage) SaveFile(ctx context.Context, path string, filename string, fileContent io.Reader, fileSize int64) (string, error) {
+	fileURL, err := s.primary.SaveFile(ctx, path, filename, fileContent, fileSize)
+	if err != nil {
+		return "", err
+	}
+
+	if filePath, ok := s.primary.PathFromURL(fileURL); ok {
+		s.enqueue(&replicationJob{op: replicationCopy, path: filePath})
+	}
+	return fileURL, nil
+}
+
+// WriteFile はファイルをプライマリの指定したパスに保存し、セカンダリへの複製をキューに入れます
+func (s *ReplicatedStorage) WriteFile(ctx context.Context, path string, fileContent io.Reader) (string, error) {
+	fileURL, err := s.primary.WriteFile(ctx, path, fileContent)
+	if err != nil {
+		return "", err
+	}
+
+	s.enqueue(&replicationJob{op: replicationCopy, path: path})
+	return fileURL, nil
+}
+
+// OpenFile はファイルを開きます
+// プライマリが利用できない場合や、プライマリで開けなかった場合はセカンダリから開きます
+func (s *ReplicatedStorage) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	if s.primaryUp.Load() {
+		file, err := s.primary.OpenFile(ctx, fileURL)
+		if err == nil {
+			return file, nil
+		}
+		s.log.Warn("プライマリのストレージでファイルを開けませんでした。セカンダリから開きます", "error", err, "url", fileURL)
+	}
+
+	filePath, ok := s.PathFromURL(fileURL)
+	if !ok {
+		return nil, fmt.Errorf("このストレージのURLではありません: %s", fileURL)
+	}
+	secondaryURL, err := s.urlFor(ctx, s.secondary, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return s.secondary.OpenFile(ctx, secondaryURL)
+}
+
+// DeleteFile はファイルをプライマリから削除し、セカンダリからの削除をキューに入れます
+func (s *ReplicatedStorage) DeleteFile(ctx context.Context, path string) error {
+	if err := s.primary.DeleteFile(ctx, path); err != nil {
+		return err
+	}
+
+	s.enqueue(&replicationJob{op: replicationDelete, path: path})
+	return nil
+}
+
+// PathFromURL はプライマリまたはセカンダリの公開URLをストレージ内のパスに変換します
+func (s *ReplicatedStorage) PathFromURL(fileURL string) (string, bool) {
+	if filePath, ok := s.primary.PathFromURL(fileURL); ok {
+		return filePath, true
+	}
+	return s.secondary.PathFromURL(fileURL)
+}
+
+// GetSignedURL は署名付きURLを生成します（プライマリが利用できない場合はセカンダリのURLを返します）
+func (s *ReplicatedStorage) GetSignedURL(ctx context.Context, path string, expires time.Duration) (string, error) {
+	if s.primaryUp.Load() {
+		return s.primary.GetSignedURL(ctx, path, expires)
+	}
+	return s.secondary.GetSignedURL(ctx, path, expires)
+}
+
+// FailoverURL はプライマリが利用できない場合に、公開URLに対応するセカンダリのURLを返します
+func (s *ReplicatedStorage) FailoverURL(ctx context.Context, fileURL string) (string, bool) {
+	if s.primaryUp.Load() {
+		return "", false
+	}
+
+	filePath, ok := s.primary.PathFromURL(fileURL)
+	if !ok {
+		return "", false
+	}
+	secondaryURL, err := s.urlFor(ctx, s.secondary, filePath)
+	if err != nil {
+		s.log.Error("セカンダリのURLの生成に失敗しました", "error", err, "path", filePath)
+		return "", false
+	}
+	return secondaryURL, true
+}
+
+// PrimaryAvailable はプライマリのストレージが利用できるかどうかを返します
+func (s *ReplicatedStorage) PrimaryAvailable() bool {
+	return s.primaryUp.Load()
+}
+
+// enqueue は複製ジョブをキューに入れます（キューがいっぱいの場合は破棄してログに記録します）
+func (s *ReplicatedStorage) enqueue(job *replicationJob) {
+	select {
+	case s.jobs <- job:
+	default:
+		s.log.Error("複製のキューがいっぱいのためジョブを破棄しました", "op", job.op, "path", job.path)
+	}
+}
+
+// retry は失敗したジョブを、試行回数に応じて間隔を空けてからキューに戻します
+func (s *ReplicatedStorage) retry(job *replicationJob, err error) {
+	job.attempts++
+	if job.attempts >= s.maxAttempts {
+		s.log.Error("ファイルの複製を諦めました", "error", err, "op", job.op, "path", job.path, "attempts", job.attempts)
+		return
+	}
+
+	delay := time.Duration(job.attempts*job.attempts) * time.Second
+	s.log.Warn("ファイルの複製に失敗しました。再試行します", "error", err, "op", job.op, "path", job.path, "attempts", job.attempts, "delay", delay)
+	time.AfterFunc(delay, func() {
+		select {
+		case <-s.stopCh:
+		default:
+			s.enqueue(job)
+		}
+	})
+}
+
+// runWorker は停止されるまでキューのジョブを処理します
+func (s *ReplicatedStorage) runWorker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case job := <-s.jobs:
+			ctx, cancel := context.WithTimeout(context.Background(), replicationJobTimeout)
+			var err error
+			switch job.op {
+			case replicationCopy:
+				err = s.copyFile(ctx, job.path)
+			case replicationDelete:
+				err = s.secondary.DeleteFile(ctx, job.path)
+			}
+			cancel()
+
+			if err != nil {
+				s.retry(job, err)
+			}
+		}
+	}
+}
+
+// copyFile はプライマリのファイルをセカンダリの同じパスへ複製し、内容のハッシュが一致することを確認します
+func (s *ReplicatedStorage) copyFile(ctx context.Context, path string) error {
+	primaryURL, err := s.urlFor(ctx, s.primary, path)
+	if err != nil {
+		return err
+	}
+	src, err := s.primary.OpenFile(ctx, primaryURL)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	sourceHash := sha256.New()
+	secondaryURL, err := s.secondary.WriteFile(ctx, path, io.TeeReader(src, sourceHash))
+	if err != nil {
+		return err
+	}
+
+	// 複製先から読み直して内容を検証する
+	dst, err := s.secondary.OpenFile(ctx, secondaryURL)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	replicaHash := sha256.New()
+	if _, err := io.Copy(replicaHash, dst); err != nil {
+		return err
+	}
+	if !bytes.Equal(sourceHash.Sum(nil), replicaHash.Sum(nil)) {
+		return fmt.Errorf("複製したファイルの内容が一致しません: %s", path)
+	}
+	return nil
+}
+
+// runHealthCheck は停止されるまで一定間隔でプライマリの死活を確認します
+func (s *ReplicatedStorage) runHealthCheck() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.healthInterval)
+			err := s.probePrimary(ctx)
+			cancel()
+
+			up := err == nil
+			if s.primaryUp.Swap(up) != up {
+				if up {
+					s.log.Info("プライマリのストレージが復旧しました。読み込みをプライマリに戻します")
+				} else {
+					s.log.Error("プライマリのストレージが利用できません。読み込みをセカンダリへ振り替えます", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// probePrimary はプライマリに確認用のファイルを書き込んで読み直します
+func (s *ReplicatedStorage) probePrimary(ctx context.Context) error {
+	content := time.Now().UTC().Format(time.RFC3339Nano)
+	fileURL, err := s.primary.WriteFile(ctx, replicationProbePath, strings.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	file, err := s.primary.OpenFile(ctx, fileURL)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	read, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	if string(read) != content {
+		return fmt.Errorf("確認用のファイルの内容が一致しません")
+	}
+	return nil
+}
+
+// urlFor はストレージ内のパスに対応するストレージのURLを返します
+func (s *ReplicatedStorage) urlFor(ctx context.Context, provider interfaces.StorageProvider, path string) (string, error) {
+	return provider.GetSignedURL(ctx, strings.TrimPrefix(path, "/"), replicationURLExpiry)
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/TakuyaAizawa/gox/internal/interfaces"
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// LocalUploadPartStore は分割アップロードのパートをローカルファイルシステムに保存するストアです
+// セッションごとのディレクトリにパートを保存し、結合したファイルはストレージへ渡した後に削除されます
+type LocalUploadPartStore struct {
+	baseDir string
+	log     logger.Logger
+}
+
+// NewLocalUploadPartStore は新しいLocalUploadPartStoreインスタンスを作成します
+func NewLocalUploadPartStore(baseDir string, log logger.Logger) interfaces.UploadPartStore {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		log.Error("分割アップロードのディレクトリの作成に失敗しました", "error", err)
+	}
+
+	return &LocalUploadPartStore{
+		baseDir: baseDir,
+		log:     log,
+	}
+}
+
+// WritePart はパートを一時ファイルに書き込んでから置き換えます
+// 書き込み途中のパートや大きさの異なるパートで、受信済みのパートを上書きしないようにする
+func (s *LocalUploadPartStore) WritePart(ctx context.Context, sessionID string, index int, content io.Reader, size int64) error {
+	dir, err := s.sessionDir(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("ファイルの作成に失敗しました: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	// 大きすぎるパートを最後まで書き込まないよう、1バイト多く読んだ時点で止める
+	written, err := io.Copy(tmp, io.LimitReader(content, size+1))
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
+	}
+	if written != size {
+		return interfaces.ErrUploadPartSize
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, partFilename(index))); err != nil {
+		return fmt.Errorf("ファイルの置き換えに失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// Assemble はパートを番号順に1つのファイルへ結合して開きます
+func (s *LocalUploadPartStore) Assemble(ctx context.Context, sessionID string, partCount int) (io.ReadCloser, error) {
+	dir, err := s.sessionDir(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	assembled, err := os.CreateTemp(dir, ".assembled-*")
+	if err != nil {
+		return nil, fmt.Errorf("ファイルの作成に失敗しました: %w", err)
+	}
+	file := &assembledFile{File: assembled}
+
+	for index := 0; index < partCount; index++ {
+		if err := appendPart(assembled, filepath.Join(dir, partFilename(index))); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	if _, err := assembled.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	return file, nil
+}
+
+// DeleteParts はセッションのディレクトリを削除します
+func (s *LocalUploadPartStore) DeleteParts(ctx context.Context, sessionID string) error {
+	dir, err := s.sessionDir(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("パートの削除に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// sessionDir はセッションのパートを保存するディレクトリを返します（UUID以外はベースディレクトリの外を指さないよう拒否する）
+func (s *LocalUploadPartStore) sessionDir(sessionID string) (string, error) {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return "", fmt.Errorf("無効なセッションIDです: %s", sessionID)
+	}
+	return filepath.Join(s.baseDir, sessionID), nil
+}
+
+// partFilename はパートのファイル名を返します
+func partFilename(index int) string {
+	return "part-" + strconv.Itoa(index)
+}
+
+// appendPart はパートの内容をファイルの末尾に追加します
+func appendPart(dst *os.File, partPath string) error {
+	part, err := os.Open(partPath)
+	if err != nil {
+		return fmt.Errorf("パートの読み込みに失敗しました: %w", err)
+	}
+	defer part.Close()
+
+	if _, err := io.Copy(dst, part); err != nil {
+		return fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// assembledFile は閉じると削除される結合済みのファイルです
+type assembledFile struct {
+	*os.File
+}
+
+// Close はファイルを閉じて削除します
+func (f *assembledFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); removeErr != nil && !os.IsNotExist(removeErr) && err == nil {
+		err = removeErr
+	}
+	return err
+}
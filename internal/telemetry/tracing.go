@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName アプリケーションが作成するスパンの計装名
+const instrumentationName = "github.com/TakuyaAizawa/gox"
+
+// Setup OTLP（HTTP）でトレースを送信するトレーサープロバイダーを作成し、グローバルに登録する
+// 無効の場合もW3C Trace Contextの伝播は行う（上流から受け取ったトレースIDをアウトボックスなどへ引き継ぐ）
+// endpointが空の場合はOTEL_EXPORTER_OTLP_ENDPOINTなどの標準の環境変数に従う
+// 返した関数は未送信のスパンを送信してから終了する
+func Setup(
+	ctx context.Context,
+	enabled bool,
+	endpoint string,
+	serviceName string,
+	environment string,
+	sampleRate float64,
+) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var options []otlptracehttp.Option
+	if endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.DeploymentEnvironment(environment),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if sampleRate < 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// 上流でサンプリングされたリクエストは割合に関わらず記録する
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer アプリケーションのスパンを作成するトレーサーを返す（無効の場合は何も記録しない）
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Inject コンテキストのトレース情報を保存できる形式で取り出す（非同期の処理へ引き継ぐ場合に使用する）
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract Injectで取り出したトレース情報をコンテキストに戻す
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// End スパンを終了する（errがnilでない場合はスパンを失敗として記録する）
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,156 @@
+package blurhash
+
+import (
+	"errors"
+	"image"
+	"math"
+	"strings"
+)
+
+// ErrInvalidComponents は成分数が1〜9の範囲にないことを表す
+var ErrInvalidComponents = errors.New("blurhash: components must be between 1 and 9")
+
+// ErrEmptyImage は幅または高さが0の画像であることを表す
+var ErrEmptyImage = errors.New("blurhash: empty image")
+
+// 計算に使う最大の幅・高さ（プレースホルダーは低解像度で十分なため、大きな画像は間引いて計算する）
+const maxSampleSize = 64
+
+// Base83で使う文字
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Encode 画像をBlurHash（画像の読み込み前に表示するぼかしたプレースホルダーの文字列）に変換する
+// xComponents・yComponentsは横・縦方向の成分数（多いほど細部を表現できるが文字列が長くなる）
+func Encode(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", ErrInvalidComponents
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return "", ErrEmptyImage
+	}
+	pixels, width, height := sample(img)
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			factors = append(factors, basisFactor(pixels, width, height, i, j))
+		}
+	}
+
+	var hash strings.Builder
+	hash.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	// 交流成分の最大値（量子化して1文字で表す）
+	maximumValue := 1.0
+	if len(factors) > 1 {
+		actualMaximum := 0.0
+		for _, factor := range factors[1:] {
+			for _, v := range factor {
+				actualMaximum = math.Max(actualMaximum, math.Abs(v))
+			}
+		}
+		quantisedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantisedMaximum+1) / 166
+		hash.WriteString(encode83(quantisedMaximum, 1))
+	} else {
+		hash.WriteString(encode83(0, 1))
+	}
+
+	hash.WriteString(encode83(encodeDC(factors[0]), 4))
+	for _, factor := range factors[1:] {
+		hash.WriteString(encode83(encodeAC(factor, maximumValue), 2))
+	}
+
+	return hash.String(), nil
+}
+
+// sample 画像を最大maxSampleSize四方に間引き、線形RGBの画素の一覧と幅・高さを返す
+func sample(img image.Image) ([][3]float64, int, int) {
+	bounds := img.Bounds()
+	width := min(bounds.Dx(), maxSampleSize)
+	height := min(bounds.Dy(), maxSampleSize)
+
+	pixels := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			r, g, b, _ := img.At(sx, sy).RGBA()
+			pixels[y*width+x] = [3]float64{
+				sRGBToLinear(int(r >> 8)),
+				sRGBToLinear(int(g >> 8)),
+				sRGBToLinear(int(b >> 8)),
+			}
+		}
+	}
+
+	return pixels, width, height
+}
+
+// basisFactor 横i・縦j番目のコサイン基底に対する成分を計算する
+func basisFactor(pixels [][3]float64, width, height, i, j int) [3]float64 {
+	var factor [3]float64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) *
+				math.Cos(math.Pi*float64(j)*float64(y)/float64(height))
+			pixel := pixels[y*width+x]
+			factor[0] += basis * pixel[0]
+			factor[1] += basis * pixel[1]
+			factor[2] += basis * pixel[2]
+		}
+	}
+
+	normalisation := 2.0
+	if i == 0 && j == 0 {
+		normalisation = 1
+	}
+	scale := normalisation / float64(width*height)
+
+	return [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale}
+}
+
+// encodeDC 直流成分（平均色）をsRGBの24ビットの値に変換する
+func encodeDC(value [3]float64) int {
+	return linearToSRGB(value[0])<<16 | linearToSRGB(value[1])<<8 | linearToSRGB(value[2])
+}
+
+// encodeAC 交流成分を各色19段階に量子化した値に変換する
+func encodeAC(value [3]float64, maximumValue float64) int {
+	quant := func(v float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximumValue, 0.5)*9+9.5))))
+	}
+	return quant(value[0])*19*19 + quant(value[1])*19 + quant(value[2])
+}
+
+// encode83 値をlength文字のBase83に変換する
+func encode83(value, length int) string {
+	var b strings.Builder
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		b.WriteByte(base83Chars[digit])
+	}
+	return b.String()
+}
+
+func sRGBToLinear(value int) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,165 @@
+package blurhash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newImage 各画素の色をfで決めた画像を作成する
+func newImage(width, height int, f func(x, y int) color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, f(x, y))
+		}
+	}
+	return img
+}
+
+func solid(c color.RGBA) func(x, y int) color.RGBA {
+	return func(x, y int) color.RGBA { return c }
+}
+
+func TestEncode(t *testing.T) {
+	// 期待値は参照実装（woltapp/blurhash のTypeScript版）の計算手順で求めたもの
+	// 間引きの影響を受けないよう、画像はmaxSampleSize四方以下とする
+	tests := []struct {
+		name                     string
+		img                      image.Image
+		xComponents, yComponents int
+		expected                 string
+	}{
+		{
+			name:        "SolidColorDCOnly",
+			img:         newImage(16, 16, solid(color.RGBA{255, 0, 0, 255})),
+			xComponents: 1, yComponents: 1,
+			expected: "00TI:j",
+		},
+		{
+			name:        "SolidWhite",
+			img:         newImage(32, 32, solid(color.RGBA{255, 255, 255, 255})),
+			xComponents: 4, yComponents: 3,
+			expected: "L9TSUA~qfQ~q~qoffQoffQfQfQfQ",
+		},
+		{
+			name: "HorizontalGradient",
+			img: newImage(32, 16, func(x, y int) color.RGBA {
+				r := uint8(x * 255 / 31)
+				return color.RGBA{r, 128, 255 - r, 255}
+			}),
+			xComponents: 4, yComponents: 3,
+			expected: "L.Hd%V2zw%XAs;Wrjua}fQfQfQfQ",
+		},
+		{
+			name: "BlackAndWhiteHalves",
+			img: newImage(20, 10, func(x, y int) color.RGBA {
+				if x < 10 {
+					return color.RGBA{0, 0, 0, 255}
+				}
+				return color.RGBA{255, 255, 255, 255}
+			}),
+			xComponents: 2, yComponents: 1,
+			expected: "1~Lqe900",
+		},
+		{
+			name: "Checkerboard",
+			img: newImage(64, 64, func(x, y int) color.RGBA {
+				if (x/8+y/8)%2 == 0 {
+					return color.RGBA{200, 40, 40, 255}
+				}
+				return color.RGBA{30, 90, 220, 255}
+			}),
+			xComponents: 4, yComponents: 4,
+			expected: "U1G~lK^BfQ^B^B[QfQ[QfQfQfQfQ^B[QfQ{t",
+		},
+		{
+			name: "MaximumComponents",
+			img: newImage(24, 48, func(x, y int) color.RGBA {
+				return color.RGBA{uint8(x * 7 % 256), uint8(y * 5 % 256), uint8(x * y % 256), 255}
+			}),
+			xComponents: 9, yComponents: 9,
+			expected: "|rB5e6FqsQbWa^k8WnoeWqhkadfRe?fSe?fSe=fOgYfkfTflfRfhfOfhfRi_a$fSf6fNf5fSfAfSfgfSfRfNfPfTfSfNfNj=a~fOfOfTfRfNfRfTf5fSfOfSfSfNfSfSfOkBa}fOfTfNfQfTfNfSe?fOfRfRfOfTfOfSfO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := Encode(tt.img, tt.xComponents, tt.yComponents)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hash)
+			// 1文字目の成分数・1文字目の最大値・4文字の平均色・各交流成分2文字
+			assert.Len(t, hash, 6+2*(tt.xComponents*tt.yComponents-1))
+		})
+	}
+}
+
+func TestEncodeSubImage(t *testing.T) {
+	// 原点が(0, 0)でない画像も同じハッシュになる
+	gradient := func(x, y int) color.RGBA {
+		return color.RGBA{uint8(x * 8), uint8(y * 8), 100, 255}
+	}
+	img := newImage(32, 32, gradient)
+	padded := newImage(48, 40, func(x, y int) color.RGBA {
+		if x < 10 || y < 5 || x >= 42 || y >= 37 {
+			return color.RGBA{0, 255, 0, 255}
+		}
+		return gradient(x-10, y-5)
+	})
+
+	expected, err := Encode(img, 4, 3)
+	require.NoError(t, err)
+	hash, err := Encode(padded.SubImage(image.Rect(10, 5, 42, 37)), 4, 3)
+	require.NoError(t, err)
+	assert.Equal(t, expected, hash)
+}
+
+func TestEncodeSamplesLargeImages(t *testing.T) {
+	// 大きな画像はmaxSampleSize四方に間引いて計算するため、拡大した画像は元の画像と同じハッシュになる
+	small := newImage(64, 64, func(x, y int) color.RGBA {
+		return color.RGBA{uint8(x * 4), uint8(y * 8), uint8((x + y) * 2), 255}
+	})
+	large := newImage(256, 256, func(x, y int) color.RGBA {
+		return small.RGBAAt(x/4, y/4)
+	})
+
+	expected, err := Encode(small, 4, 3)
+	require.NoError(t, err)
+	hash, err := Encode(large, 4, 3)
+	require.NoError(t, err)
+	assert.Equal(t, expected, hash)
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	img := newImage(4, 4, solid(color.RGBA{0, 0, 0, 255}))
+
+	tests := []struct {
+		name                     string
+		img                      image.Image
+		xComponents, yComponents int
+		expected                 error
+	}{
+		{"ZeroXComponents", img, 0, 3, ErrInvalidComponents},
+		{"TooManyYComponents", img, 4, 10, ErrInvalidComponents},
+		{"EmptyImage", image.NewRGBA(image.Rect(0, 0, 0, 8)), 4, 3, ErrEmptyImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := Encode(tt.img, tt.xComponents, tt.yComponents)
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Empty(t, hash)
+		})
+	}
+}
+
+func TestEncode83(t *testing.T) {
+	assert.Equal(t, "0", encode83(0, 1))
+	assert.Equal(t, "~", encode83(82, 1))
+	assert.Equal(t, "10", encode83(83, 2))
+	// 0xFF0000（赤）の平均色
+	assert.Equal(t, "TI:j", encode83(0xFF0000, 4))
+}
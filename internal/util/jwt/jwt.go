@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -24,12 +26,13 @@ type Claims struct {
 	UserID   string    `json:"sub"`
 	Username string    `json:"username,omitempty"`
 	Email    string    `json:"email,omitempty"`
+	Role     string    `json:"role,omitempty"` // 権限（ミドルウェアでデータベースを参照せずに判定するため）
 	Type     TokenType `json:"type"`
 	jwt.RegisteredClaims
 }
 
 // 新しいJWTトークンを生成する
-func GenerateToken(userID uuid.UUID, username, email string, tokenType TokenType, secret string, expirationHours int) (string, error) {
+func GenerateToken(userID uuid.UUID, username, email, role string, tokenType TokenType, secret string, expirationHours int) (string, error) {
 	// 有効期限の設定
 	expirationTime := time.Now().Add(time.Duration(expirationHours) * time.Hour)
 	
@@ -38,6 +41,7 @@ func GenerateToken(userID uuid.UUID, username, email string, tokenType TokenType
 		UserID:   userID.String(),
 		Username: username,
 		Email:    email,
+		Role:     role,
 		Type:     tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
@@ -89,4 +93,10 @@ func GetUserIDFromToken(claims *Claims) (uuid.UUID, error) {
 		return uuid.Nil, fmt.Errorf("トークン内のユーザーIDが無効です: %w", err)
 	}
 	return userID, nil
-} 
\ No newline at end of file
+} 
+
+// トークンを識別するキーを返す（トークン自体を保存せずに利用状況を集計するため、ハッシュの先頭を使用する）
+func TokenKey(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:8])
+}
@@ -28,7 +28,7 @@ func (j *JWTUtil) GenerateToken(userID string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return GenerateToken(id, "", "", AccessToken, j.secretKey, j.accessExpiry)
+	return GenerateToken(id, "", "", "", AccessToken, j.secretKey, j.accessExpiry)
 }
 
 // GenerateTokenWithDetails ユーザー詳細を含むアクセストークンを生成する
@@ -37,7 +37,16 @@ func (j *JWTUtil) GenerateTokenWithDetails(userID, username, email string) (stri
 	if err != nil {
 		return "", err
 	}
-	return GenerateToken(id, username, email, AccessToken, j.secretKey, j.accessExpiry)
+	return GenerateToken(id, username, email, "", AccessToken, j.secretKey, j.accessExpiry)
+}
+
+// GenerateTokenWithRole 権限を含むアクセストークンを生成する
+func (j *JWTUtil) GenerateTokenWithRole(userID, role string) (string, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return "", err
+	}
+	return GenerateToken(id, "", "", role, AccessToken, j.secretKey, j.accessExpiry)
 }
 
 // GenerateRefreshToken リフレッシュトークンを生成する
@@ -46,7 +55,7 @@ func (j *JWTUtil) GenerateRefreshToken(userID string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return GenerateToken(id, "", "", RefreshToken, j.secretKey, j.refreshExpiry)
+	return GenerateToken(id, "", "", "", RefreshToken, j.secretKey, j.refreshExpiry)
 }
 
 // ValidateAccessToken アクセストークンを検証する
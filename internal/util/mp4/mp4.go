@@ -0,0 +1,191 @@
+package mp4
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ErrInvalidFormat はMP4（ISO BMFF）形式のファイルとして読み込めないことを表す
+var ErrInvalidFormat = errors.New("mp4: invalid format")
+
+// ErrNoDuration は再生時間が記録されていないことを表す
+var ErrNoDuration = errors.New("mp4: missing duration")
+
+// Info 動画ファイルのヘッダーから読み取った情報
+type Info struct {
+	// 再生時間
+	Duration time.Duration
+	// 映像の幅・高さ（映像のトラックがない場合は0）
+	Width  int
+	Height int
+}
+
+// ファイルの先頭に置かれるボックスの種類
+var leadingBoxes = map[string]bool{
+	"ftyp": true,
+	"moov": true,
+	"mdat": true,
+	"free": true,
+	"skip": true,
+	"wide": true,
+}
+
+// box ボックス（MP4の構成単位）の種類と中身
+type box struct {
+	kind string
+	body []byte
+}
+
+// Probe MP4・MOV形式の動画ファイルの再生時間と映像の大きさを読み取る
+// 映像はデコードせず、ヘッダー（moovボックス）のみを読む
+func Probe(data []byte) (*Info, error) {
+	boxes, err := readBoxes(data)
+	if err != nil {
+		return nil, err
+	}
+	// MP4はftypから始まる（古いQuickTimeの形式はftypがない場合がある）
+	if len(boxes) == 0 || !leadingBoxes[boxes[0].kind] {
+		return nil, ErrInvalidFormat
+	}
+
+	moov, ok := findBox(boxes, "moov")
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+	children, err := readBoxes(moov.body)
+	if err != nil {
+		return nil, err
+	}
+
+	mvhd, ok := findBox(children, "mvhd")
+	if !ok {
+		return nil, ErrNoDuration
+	}
+	duration, err := movieDuration(mvhd.body)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{Duration: duration}
+	for _, trak := range children {
+		if trak.kind != "trak" {
+			continue
+		}
+		trakChildren, err := readBoxes(trak.body)
+		if err != nil {
+			return nil, err
+		}
+		tkhd, ok := findBox(trakChildren, "tkhd")
+		if !ok {
+			continue
+		}
+		// 音声のトラックは幅・高さが0のため、最初の映像のトラックの大きさを使う
+		if width, height := trackDimensions(tkhd.body); width > 0 && height > 0 {
+			info.Width = width
+			info.Height = height
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// readBoxes 連続したボックスを読み込む
+func readBoxes(data []byte) ([]box, error) {
+	boxes := make([]box, 0)
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, ErrInvalidFormat
+		}
+
+		size := uint64(binary.BigEndian.Uint32(data[0:4]))
+		kind := string(data[4:8])
+		header := uint64(8)
+		switch size {
+		case 0:
+			// 0はファイルの終わりまでを表す
+			size = uint64(len(data))
+		case 1:
+			// 1は64ビットのサイズが続くことを表す
+			if len(data) < 16 {
+				return nil, ErrInvalidFormat
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return nil, ErrInvalidFormat
+		}
+
+		boxes = append(boxes, box{kind: kind, body: data[header:size]})
+		data = data[size:]
+	}
+	return boxes, nil
+}
+
+// findBox 指定した種類の最初のボックスを返す
+func findBox(boxes []box, kind string) (box, bool) {
+	for _, b := range boxes {
+		if b.kind == kind {
+			return b, true
+		}
+	}
+	return box{}, false
+}
+
+// movieDuration mvhdボックスから再生時間を読み取る
+func movieDuration(body []byte) (time.Duration, error) {
+	if len(body) < 1 {
+		return 0, ErrInvalidFormat
+	}
+
+	var timescale, duration uint64
+	switch body[0] {
+	case 0:
+		if len(body) < 20 {
+			return 0, ErrInvalidFormat
+		}
+		timescale = uint64(binary.BigEndian.Uint32(body[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(body[16:20]))
+	case 1:
+		if len(body) < 32 {
+			return 0, ErrInvalidFormat
+		}
+		timescale = uint64(binary.BigEndian.Uint32(body[20:24]))
+		duration = binary.BigEndian.Uint64(body[24:32])
+	default:
+		return 0, ErrInvalidFormat
+	}
+
+	// 不明な再生時間はすべてのビットが1になる
+	if timescale == 0 || duration == 0 || duration == 0xFFFFFFFF || duration == 0xFFFFFFFFFFFFFFFF {
+		return 0, ErrNoDuration
+	}
+
+	seconds := duration / timescale
+	if seconds > uint64(time.Duration(1<<63-1)/time.Second) {
+		return 0, ErrInvalidFormat
+	}
+	remainder := duration % timescale
+	return time.Duration(seconds)*time.Second + time.Duration(remainder*uint64(time.Second)/timescale), nil
+}
+
+// trackDimensions tkhdボックスから映像の幅・高さを読み取る（16.16の固定小数点数の整数部）
+func trackDimensions(body []byte) (int, int) {
+	if len(body) < 1 {
+		return 0, 0
+	}
+
+	offset := 76
+	if body[0] == 1 {
+		offset = 88
+	}
+	if len(body) < offset+8 {
+		return 0, 0
+	}
+
+	width := binary.BigEndian.Uint32(body[offset:offset+4]) >> 16
+	height := binary.BigEndian.Uint32(body[offset+4:offset+8]) >> 16
+	return int(width), int(height)
+}
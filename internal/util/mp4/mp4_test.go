@@ -0,0 +1,199 @@
+package mp4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeBox 32ビットのサイズを持つボックスを組み立てる
+func makeBox(kind string, children ...[]byte) []byte {
+	body := bytes.Join(children, nil)
+	data := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	data = append(data, kind...)
+	return append(data, body...)
+}
+
+// makeLargeBox 64ビットのサイズを持つボックスを組み立てる
+func makeLargeBox(kind string, children ...[]byte) []byte {
+	body := bytes.Join(children, nil)
+	data := binary.BigEndian.AppendUint32(nil, 1)
+	data = append(data, kind...)
+	data = binary.BigEndian.AppendUint64(data, uint64(16+len(body)))
+	return append(data, body...)
+}
+
+// mvhdV0 32ビットの時刻・再生時間を持つmvhdボックス
+func mvhdV0(timescale, duration uint32) []byte {
+	body := make([]byte, 100)
+	binary.BigEndian.PutUint32(body[12:16], timescale)
+	binary.BigEndian.PutUint32(body[16:20], duration)
+	return makeBox("mvhd", body)
+}
+
+// mvhdV1 64ビットの時刻・再生時間を持つmvhdボックス
+func mvhdV1(timescale uint32, duration uint64) []byte {
+	body := make([]byte, 112)
+	body[0] = 1
+	binary.BigEndian.PutUint32(body[20:24], timescale)
+	binary.BigEndian.PutUint64(body[24:32], duration)
+	return makeBox("mvhd", body)
+}
+
+// trak 指定した大きさのtkhdボックスを持つtrakボックス（音声のトラックは0x0）
+func trak(version byte, width, height uint32) []byte {
+	offset := 76
+	if version == 1 {
+		offset = 88
+	}
+	body := make([]byte, offset+8)
+	body[0] = version
+	binary.BigEndian.PutUint32(body[offset:offset+4], width<<16)
+	binary.BigEndian.PutUint32(body[offset+4:offset+8], height<<16)
+	return makeBox("trak", makeBox("tkhd", body))
+}
+
+var ftyp = makeBox("ftyp", []byte("isom\x00\x00\x02\x00isomiso2mp41"))
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected Info
+	}{
+		{
+			name:     "VideoAfterAudioTrack",
+			data:     bytes.Join([][]byte{ftyp, makeBox("moov", mvhdV0(1000, 12345), trak(0, 0, 0), trak(0, 1920, 1080)), makeBox("mdat", []byte{1, 2, 3})}, nil),
+			expected: Info{Duration: 12345 * time.Millisecond, Width: 1920, Height: 1080},
+		},
+		{
+			name:     "Version1Headers",
+			data:     bytes.Join([][]byte{ftyp, makeBox("moov", mvhdV1(90000, 90000*3+45000), trak(1, 1280, 720))}, nil),
+			expected: Info{Duration: 3500 * time.Millisecond, Width: 1280, Height: 720},
+		},
+		{
+			name:     "AudioOnly",
+			data:     bytes.Join([][]byte{ftyp, makeBox("moov", mvhdV0(44100, 44100*2), trak(0, 0, 0))}, nil),
+			expected: Info{Duration: 2 * time.Second},
+		},
+		{
+			name:     "QuickTimeWithoutFtyp",
+			data:     bytes.Join([][]byte{makeBox("wide"), makeBox("moov", mvhdV0(600, 300), trak(0, 640, 480))}, nil),
+			expected: Info{Duration: 500 * time.Millisecond, Width: 640, Height: 480},
+		},
+		{
+			name:     "LargeSizeBox",
+			data:     bytes.Join([][]byte{ftyp, makeLargeBox("mdat", make([]byte, 32)), makeBox("moov", mvhdV0(1000, 1000))}, nil),
+			expected: Info{Duration: time.Second},
+		},
+		{
+			name: "SizeZeroExtendsToEnd",
+			data: bytes.Join([][]byte{ftyp, makeBox("moov", mvhdV0(1000, 1000), trak(0, 320, 240)),
+				{0, 0, 0, 0, 'm', 'd', 'a', 't', 1, 2, 3, 4}}, nil),
+			expected: Info{Duration: time.Second, Width: 320, Height: 240},
+		},
+		{
+			name:     "TrackWithoutTkhd",
+			data:     bytes.Join([][]byte{ftyp, makeBox("moov", mvhdV0(1000, 1500), makeBox("trak", makeBox("mdia")))}, nil),
+			expected: Info{Duration: 1500 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Probe(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *info)
+		})
+	}
+}
+
+func TestProbeInvalid(t *testing.T) {
+	moov := func(children ...[]byte) []byte {
+		return bytes.Join([][]byte{ftyp, makeBox("moov", children...)}, nil)
+	}
+	mvhdWithVersion := func(version byte) []byte {
+		body := make([]byte, 100)
+		body[0] = version
+		return makeBox("mvhd", body)
+	}
+	sizeBelowHeader := binary.BigEndian.AppendUint32(nil, 4)
+	sizeBelowHeader = append(sizeBelowHeader, "ftyp"...)
+	largeSizeBelowHeader := binary.BigEndian.AppendUint32(nil, 1)
+	largeSizeBelowHeader = append(largeSizeBelowHeader, "ftyp"...)
+	largeSizeBelowHeader = binary.BigEndian.AppendUint64(largeSizeBelowHeader, 8)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"Empty", nil, ErrInvalidFormat},
+		{"TruncatedHeader", []byte{0, 0, 0, 8, 'f', 't'}, ErrInvalidFormat},
+		{"SizeBeyondData", ftyp[:len(ftyp)-1], ErrInvalidFormat},
+		{"SizeBelowHeader", sizeBelowHeader, ErrInvalidFormat},
+		{"TruncatedLargeSize", []byte{0, 0, 0, 1, 'm', 'd', 'a', 't', 0, 0}, ErrInvalidFormat},
+		{"LargeSizeBelowHeader", largeSizeBelowHeader, ErrInvalidFormat},
+		{"NotMP4", makeBox("RIFF", []byte("WAVEfmt ")), ErrInvalidFormat},
+		{"NoMoov", bytes.Join([][]byte{ftyp, makeBox("mdat", []byte{1})}, nil), ErrInvalidFormat},
+		{"TruncatedChildBox", moov([]byte{0, 0, 0, 100, 'm', 'v', 'h', 'd'}), ErrInvalidFormat},
+		{"NoMvhd", moov(trak(0, 640, 480)), ErrNoDuration},
+		{"EmptyMvhd", moov(makeBox("mvhd")), ErrInvalidFormat},
+		{"TruncatedMvhdV0", moov(makeBox("mvhd", make([]byte, 19))), ErrInvalidFormat},
+		{"TruncatedMvhdV1", moov(makeBox("mvhd", append([]byte{1}, make([]byte, 30)...))), ErrInvalidFormat},
+		{"UnknownMvhdVersion", moov(mvhdWithVersion(2)), ErrInvalidFormat},
+		{"ZeroTimescale", moov(mvhdV0(0, 1000)), ErrNoDuration},
+		{"ZeroDuration", moov(mvhdV0(1000, 0)), ErrNoDuration},
+		{"UnknownDurationV0", moov(mvhdV0(1000, 0xFFFFFFFF)), ErrNoDuration},
+		{"UnknownDurationV1", moov(mvhdV1(1000, 0xFFFFFFFFFFFFFFFF)), ErrNoDuration},
+		{"DurationOverflow", moov(mvhdV1(1, 1<<62)), ErrInvalidFormat},
+		{"TruncatedTrackBox", moov(mvhdV0(1000, 1000), makeBox("trak", []byte{0, 0, 0, 50, 't', 'k', 'h', 'd'})), ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Probe(tt.data)
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Nil(t, info)
+		})
+	}
+}
+
+func TestProbeTruncatedTkhd(t *testing.T) {
+	// 幅・高さまで届かないtkhdは大きさなしとして扱う
+	data := bytes.Join([][]byte{ftyp, makeBox("moov", mvhdV0(1000, 1000), makeBox("trak", makeBox("tkhd", make([]byte, 80))))}, nil)
+
+	info, err := Probe(data)
+	require.NoError(t, err)
+	assert.Equal(t, Info{Duration: time.Second}, *info)
+}
+
+func TestProbeTruncatedFileDoesNotPanic(t *testing.T) {
+	header := bytes.Join([][]byte{ftyp, makeBox("moov", mvhdV1(1000, 5000), trak(0, 0, 0), trak(1, 1920, 1080))}, nil)
+	data := append(bytes.Clone(header), makeLargeBox("mdat", make([]byte, 16))...)
+
+	// 途中で切れたファイルはボックスの境界以外のどこで切れてもエラーになり、パニックしない
+	for n := 0; n < len(data); n++ {
+		if n == len(header) {
+			continue
+		}
+		assert.NotPanics(t, func() {
+			info, err := Probe(data[:n])
+			assert.Error(t, err, "truncated to %d bytes", n)
+			assert.Nil(t, info)
+		})
+	}
+
+	// 途中のバイトが壊れていてもパニックしない
+	for i := range data {
+		corrupted := bytes.Clone(data)
+		corrupted[i] ^= 0xFF
+		assert.NotPanics(t, func() {
+			_, _ = Probe(corrupted)
+		}, "corrupted byte %d", i)
+	}
+}
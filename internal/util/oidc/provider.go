@@ -0,0 +1,358 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ディスカバリーの結果を再取得するまでの時間
+const discoveryTTL = time.Hour
+
+// 署名鍵が見つからない場合にJWKSを再取得する最短の間隔
+const jwksRefreshInterval = time.Minute
+
+// レスポンスの最大サイズ
+const maxResponseBytes = 1 << 20
+
+// IDトークンの署名に使用できるアルゴリズム
+var validMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512"}
+
+// metadata OpenIDプロバイダーのディスカバリードキュメント
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider 外部のOpenIDプロバイダーとの認可コードフロー（PKCE）とIDトークンの検証を行う
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	client       *http.Client
+
+	mu           sync.Mutex
+	meta         *metadata
+	discoveredAt time.Time
+	keys         map[string]interface{}
+	keysFetched  time.Time
+}
+
+// Claims 検証済みのIDトークンのクレーム
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	// すべてのクレーム（グループなどプロバイダー固有のクレームの取得に使用する）
+	Raw jwt.MapClaims
+}
+
+// NewProvider 新しいOpenIDプロバイダーのクライアントを作成する（ディスカバリーは最初の使用時に行う）
+func NewProvider(issuer, clientID, clientSecret, redirectURL string, scopes []string) *Provider {
+	return &Provider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       scopes,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Issuer プロバイダーの発行者URLを返す
+func (p *Provider) Issuer() string {
+	return p.issuer
+}
+
+// AuthCodeURL 認可エンドポイントへのリダイレクト先URLを返す
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.clientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {strings.Join(p.scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return meta.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange 認可コードをトークンに交換し、検証済みのIDトークンのクレームを返す
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := p.doJSON(req, &token); err != nil {
+		if token.Error != "" {
+			return nil, fmt.Errorf("トークンの取得に失敗しました: %s %s", token.Error, token.ErrorDescription)
+		}
+		return nil, fmt.Errorf("トークンの取得に失敗しました: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("トークンレスポンスにIDトークンが含まれていません")
+	}
+
+	return p.VerifyIDToken(ctx, token.IDToken, nonce)
+}
+
+// VerifyIDToken IDトークンの署名・発行者・対象者・有効期限・nonceを検証してクレームを返す
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mapClaims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, mapClaims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, meta, kid)
+	},
+		jwt.WithValidMethods(validMethods),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("IDトークンの検証に失敗しました: %w", err)
+	}
+
+	// 複数の対象者を含む場合は認可された当事者がこのクライアントであること
+	if aud, _ := mapClaims.GetAudience(); len(aud) > 1 {
+		if azp, _ := mapClaims["azp"].(string); azp != p.clientID {
+			return nil, errors.New("IDトークンの認可された当事者が一致しません")
+		}
+	}
+	if tokenNonce, _ := mapClaims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("IDトークンのnonceが一致しません")
+	}
+
+	claims := &Claims{Raw: mapClaims}
+	claims.Subject, _ = mapClaims.GetSubject()
+	if claims.Subject == "" {
+		return nil, errors.New("IDトークンにsubjectが含まれていません")
+	}
+	claims.Email, _ = mapClaims["email"].(string)
+	claims.Name, _ = mapClaims["name"].(string)
+	claims.PreferredUsername, _ = mapClaims["preferred_username"].(string)
+	switch verified := mapClaims["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = verified
+	case string:
+		// 一部のプロバイダーは文字列で返す
+		claims.EmailVerified = verified == "true"
+	}
+
+	return claims, nil
+}
+
+// StringList クレームの値を文字列のリストとして返す（単一の文字列やスペース区切りの値にも対応する）
+func (c *Claims) StringList(name string) []string {
+	switch value := c.Raw[name].(type) {
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	case string:
+		return strings.Fields(value)
+	default:
+		return nil
+	}
+}
+
+// discover ディスカバリードキュメントを取得する（一定時間キャッシュする）
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	if p.meta != nil && time.Since(p.discoveredAt) < discoveryTTL {
+		meta := p.meta
+		p.mu.Unlock()
+		return meta, nil
+	}
+	p.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &metadata{}
+	if err := p.doJSON(req, meta); err != nil {
+		// 取得に失敗した場合は期限切れのキャッシュを使用する
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.meta != nil {
+			return p.meta, nil
+		}
+		return nil, fmt.Errorf("OpenIDプロバイダーのディスカバリーに失敗しました: %w", err)
+	}
+	if strings.TrimSuffix(meta.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("ディスカバリーの発行者が一致しません: %s", meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("ディスカバリードキュメントに必要なエンドポイントが含まれていません")
+	}
+
+	p.mu.Lock()
+	p.meta = meta
+	p.discoveredAt = time.Now()
+	p.mu.Unlock()
+
+	return meta, nil
+}
+
+// key IDトークンの署名を検証する公開鍵を返す（見つからない場合は鍵のローテーションに備えてJWKSを再取得する）
+func (p *Provider) key(ctx context.Context, meta *metadata, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysFetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("署名鍵が見つかりません: %s", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx, meta.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("署名鍵が見つかりません: %s", kid)
+}
+
+// lookupKey キャッシュから鍵を探す（kidが指定されていない場合は鍵が1つのときのみ使用する）
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid != "" {
+		key, ok := p.keys[kid]
+		return key, ok
+	}
+	if len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// fetchKeys JWKSから署名用の公開鍵を取得する
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwkSet
+	if err := p.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("JWKSの取得に失敗しました: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// 対応していない鍵は無視する
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// doJSON リクエストを送信してJSONのレスポンスをデコードする（2xx以外の場合もデコードしてからエラーを返す）
+func (p *Provider) doJSON(req *http.Request, v interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	decodeErr := json.Unmarshal(body, v)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("予期しないステータスコードです: %d", resp.StatusCode)
+	}
+	return decodeErr
+}
+
+// RandomString 推測できないランダムな文字列を返す（state・nonce・PKCEのコード検証子に使用する）
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge PKCEのコード検証子からS256のコードチャレンジを返す
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,79 @@
+package profilecard
+
+// 5x7ドットのビットマップフォント
+// ユーザー名は英数字のみのため、英数字と「@」「_」のみ収録する（未収録の文字は「?」で描画する）
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+var glyphs = map[rune][glyphHeight]string{
+	'0': {" ### ", "#   #", "#  ##", "# # #", "##  #", "#   #", " ### "},
+	'1': {"  #  ", " ##  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'2': {" ### ", "#   #", "    #", "   # ", "  #  ", " #   ", "#####"},
+	'3': {"#####", "   # ", "  #  ", "   # ", "    #", "#   #", " ### "},
+	'4': {"   # ", "  ## ", " # # ", "#  # ", "#####", "   # ", "   # "},
+	'5': {"#####", "#    ", "#### ", "    #", "    #", "#   #", " ### "},
+	'6': {"  ## ", " #   ", "#    ", "#### ", "#   #", "#   #", " ### "},
+	'7': {"#####", "    #", "   # ", "  #  ", " #   ", " #   ", " #   "},
+	'8': {" ### ", "#   #", "#   #", " ### ", "#   #", "#   #", " ### "},
+	'9': {" ### ", "#   #", "#   #", " ####", "    #", "   # ", " ##  "},
+
+	'A': {" ### ", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'B': {"#### ", "#   #", "#   #", "#### ", "#   #", "#   #", "#### "},
+	'C': {" ### ", "#   #", "#    ", "#    ", "#    ", "#   #", " ### "},
+	'D': {"###  ", "#  # ", "#   #", "#   #", "#   #", "#  # ", "###  "},
+	'E': {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#####"},
+	'F': {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#    "},
+	'G': {" ### ", "#   #", "#    ", "# ###", "#   #", "#   #", " ####"},
+	'H': {"#   #", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'I': {" ### ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'J': {"  ###", "   # ", "   # ", "   # ", "   # ", "#  # ", " ##  "},
+	'K': {"#   #", "#  # ", "# #  ", "##   ", "# #  ", "#  # ", "#   #"},
+	'L': {"#    ", "#    ", "#    ", "#    ", "#    ", "#    ", "#####"},
+	'M': {"#   #", "## ##", "# # #", "# # #", "#   #", "#   #", "#   #"},
+	'N': {"#   #", "#   #", "##  #", "# # #", "#  ##", "#   #", "#   #"},
+	'O': {" ### ", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'P': {"#### ", "#   #", "#   #", "#### ", "#    ", "#    ", "#    "},
+	'Q': {" ### ", "#   #", "#   #", "#   #", "# # #", "#  # ", " ## #"},
+	'R': {"#### ", "#   #", "#   #", "#### ", "# #  ", "#  # ", "#   #"},
+	'S': {" ####", "#    ", "#    ", " ### ", "    #", "    #", "#### "},
+	'T': {"#####", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U': {"#   #", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'V': {"#   #", "#   #", "#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W': {"#   #", "#   #", "#   #", "# # #", "# # #", "# # #", " # # "},
+	'X': {"#   #", "#   #", " # # ", "  #  ", " # # ", "#   #", "#   #"},
+	'Y': {"#   #", "#   #", " # # ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'Z': {"#####", "    #", "   # ", "  #  ", " #   ", "#    ", "#####"},
+
+	'a': {"     ", "     ", " ### ", "    #", " ####", "#   #", " ####"},
+	'b': {"#    ", "#    ", "# ## ", "##  #", "#   #", "#   #", "#### "},
+	'c': {"     ", "     ", " ### ", "#    ", "#    ", "#   #", " ### "},
+	'd': {"    #", "    #", " ## #", "#  ##", "#   #", "#   #", " ####"},
+	'e': {"     ", "     ", " ### ", "#   #", "#####", "#    ", " ### "},
+	'f': {"  ## ", " #  #", " #   ", "###  ", " #   ", " #   ", " #   "},
+	'g': {"     ", " ####", "#   #", "#   #", " ####", "    #", " ### "},
+	'h': {"#    ", "#    ", "# ## ", "##  #", "#   #", "#   #", "#   #"},
+	'i': {"  #  ", "     ", " ##  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'j': {"   # ", "     ", "  ## ", "   # ", "   # ", "#  # ", " ##  "},
+	'k': {"#    ", "#    ", "#  # ", "# #  ", "##   ", "# #  ", "#  # "},
+	'l': {" ##  ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'm': {"     ", "     ", "## # ", "# # #", "# # #", "#   #", "#   #"},
+	'n': {"     ", "     ", "# ## ", "##  #", "#   #", "#   #", "#   #"},
+	'o': {"     ", "     ", " ### ", "#   #", "#   #", "#   #", " ### "},
+	'p': {"     ", "     ", "#### ", "#   #", "#### ", "#    ", "#    "},
+	'q': {"     ", "     ", " ## #", "#  ##", " ####", "    #", "    #"},
+	'r': {"     ", "     ", "# ## ", "##  #", "#    ", "#    ", "#    "},
+	's': {"     ", "     ", " ### ", "#    ", " ### ", "    #", "#### "},
+	't': {" #   ", " #   ", "###  ", " #   ", " #   ", " #  #", "  ## "},
+	'u': {"     ", "     ", "#   #", "#   #", "#   #", "#  ##", " ## #"},
+	'v': {"     ", "     ", "#   #", "#   #", "#   #", " # # ", "  #  "},
+	'w': {"     ", "     ", "#   #", "#   #", "# # #", "# # #", " # # "},
+	'x': {"     ", "     ", "#   #", " # # ", "  #  ", " # # ", "#   #"},
+	'y': {"     ", "     ", "#   #", "#   #", " ####", "    #", " ### "},
+	'z': {"     ", "     ", "#####", "   # ", "  #  ", " #   ", "#####"},
+
+	'@': {" ### ", "#   #", "# ###", "# # #", "# ###", "#    ", " ####"},
+	'_': {"     ", "     ", "     ", "     ", "     ", "     ", "#####"},
+	'?': {" ### ", "#   #", "    #", "   # ", "  #  ", "     ", "  #  "},
+}
@@ -0,0 +1,158 @@
+package profilecard
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/TakuyaAizawa/gox/internal/util/qrcode"
+)
+
+// カードの寸法（SNSのリンクプレビューで一般的な1200x630）
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// レイアウト
+const (
+	// アバター（円形）の中心と直径
+	avatarCenterX = 330
+	avatarCenterY = 240
+	avatarSize    = 280
+	// ユーザー名の描画領域
+	handleTop      = 430
+	handleMaxWidth = 540
+	handleMaxScale = 8
+	// QRコードの描画領域（クワイエットゾーンを含む）
+	qrLeft = 700
+	qrTop  = 105
+	qrSize = 420
+	// QRコードの周囲の余白（モジュール数）
+	qrQuietZone = 4
+	// 上部のアクセントの帯の高さ
+	accentHeight = 16
+)
+
+var (
+	backgroundColor = color.RGBA{0xF5, 0xF8, 0xFA, 0xFF}
+	textColor       = color.RGBA{0x0F, 0x14, 0x19, 0xFF}
+	accentColor     = color.RGBA{0x1D, 0x9B, 0xF0, 0xFF}
+	// サポーターのアクセントカラー
+	supporterColor = color.RGBA{0xE0, 0xA8, 0x00, 0xFF}
+	white          = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+)
+
+// Card カードに描画する内容
+type Card struct {
+	// 「@」を除いたユーザー名
+	Username string
+	// アバター画像（nilの場合は単色の円を描画する）
+	Avatar image.Image
+	// プロフィールへのリンクのQRコード
+	QR *qrcode.Code
+	// サポーターの場合はアクセントカラーを変える
+	Supporter bool
+}
+
+// Render カードを画像として描画する
+func Render(card Card) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{backgroundColor}, image.Point{}, draw.Src)
+
+	accent := accentColor
+	if card.Supporter {
+		accent = supporterColor
+	}
+	draw.Draw(img, image.Rect(0, 0, Width, accentHeight), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	drawAvatar(img, card.Avatar, accent)
+	drawHandle(img, "@"+card.Username)
+	if card.QR != nil {
+		drawQR(img, card.QR)
+	}
+
+	return img
+}
+
+// drawAvatar アバターを中央の正方形で切り抜き、円形に縮小して描画する
+func drawAvatar(img *image.RGBA, avatar image.Image, fallback color.RGBA) {
+	radius := avatarSize / 2
+	left, top := avatarCenterX-radius, avatarCenterY-radius
+
+	var src image.Rectangle
+	if avatar != nil {
+		b := avatar.Bounds()
+		side := min(b.Dx(), b.Dy())
+		src = image.Rect(0, 0, side, side).Add(b.Min).Add(image.Pt((b.Dx()-side)/2, (b.Dy()-side)/2))
+		if side == 0 {
+			avatar = nil
+		}
+	}
+
+	for y := 0; y < avatarSize; y++ {
+		for x := 0; x < avatarSize; x++ {
+			dx, dy := x-radius, y-radius
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			var c color.Color = fallback
+			if avatar != nil {
+				// 最近傍法で縮小する
+				c = avatar.At(src.Min.X+x*src.Dx()/avatarSize, src.Min.Y+y*src.Dy()/avatarSize)
+			}
+			img.Set(left+x, top+y, c)
+		}
+	}
+}
+
+// drawHandle ユーザー名を左側の領域の中央に、収まる最大の倍率で描画する
+func drawHandle(img *image.RGBA, text string) {
+	runes := []rune(text)
+	// 1文字あたりの幅（文字間の1ドットを含む）
+	advance := glyphWidth + 1
+	scale := handleMaxWidth / (len(runes)*advance - 1)
+	scale = max(1, min(handleMaxScale, scale))
+
+	width := (len(runes)*advance - 1) * scale
+	x := avatarCenterX - width/2
+	for _, r := range runes {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		for gy, row := range glyph {
+			for gx, dot := range row {
+				if dot != '#' {
+					continue
+				}
+				rect := image.Rect(x+gx*scale, handleTop+gy*scale, x+(gx+1)*scale, handleTop+(gy+1)*scale)
+				draw.Draw(img, rect, &image.Uniform{textColor}, image.Point{}, draw.Src)
+			}
+		}
+		x += advance * scale
+	}
+}
+
+// drawQR QRコードを白背景の正方形に描画する
+func drawQR(img *image.RGBA, qr *qrcode.Code) {
+	modules := qr.Size + qrQuietZone*2
+	moduleSize := qrSize / modules
+	// 整数倍で描画し、余りは周囲の余白に回す
+	offset := (qrSize - moduleSize*modules) / 2
+
+	area := image.Rect(qrLeft, qrTop, qrLeft+qrSize, qrTop+qrSize)
+	draw.Draw(img, area, &image.Uniform{white}, image.Point{}, draw.Src)
+
+	originX := qrLeft + offset + qrQuietZone*moduleSize
+	originY := qrTop + offset + qrQuietZone*moduleSize
+	for y := 0; y < qr.Size; y++ {
+		for x := 0; x < qr.Size; x++ {
+			if !qr.Dark(x, y) {
+				continue
+			}
+			rect := image.Rect(originX+x*moduleSize, originY+y*moduleSize, originX+(x+1)*moduleSize, originY+(y+1)*moduleSize)
+			draw.Draw(img, rect, &image.Uniform{textColor}, image.Point{}, draw.Src)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package qrcode
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 誤り訂正レベルMの形式情報（マスク番号0〜7、上位ビットから）
+var levelMFormatBits = []string{
+	"101010000010010",
+	"101000100100101",
+	"101111001111100",
+	"101101101001011",
+	"100010111111001",
+	"100000011001110",
+	"100111110010111",
+	"100101010100000",
+}
+
+// バージョン7〜10の型番情報（上位ビットから）
+var versionInfoBits = map[int]string{
+	7:  "000111110010010100",
+	8:  "001000010110111100",
+	9:  "001001101010011001",
+	10: "001010010011010011",
+}
+
+func TestEncodeVersionBoundaries(t *testing.T) {
+	// バイトモード・誤り訂正レベルMで各バージョンに格納できる最大バイト数
+	tests := []struct {
+		version  int
+		capacity int
+	}{
+		{1, 14},
+		{2, 26},
+		{3, 42},
+		{4, 62},
+		{5, 84},
+		{6, 106},
+		{7, 122},
+		{8, 152},
+		{9, 180},
+		{10, 213},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.capacity, dataCapacity(tt.version), "version %d", tt.version)
+
+		// 最大バイト数ちょうどはそのバージョンに収まる
+		code, err := Encode(bytes.Repeat([]byte("a"), tt.capacity))
+		require.NoError(t, err)
+		assert.Equal(t, tt.version*4+17, code.Size, "version %d", tt.version)
+
+		// 1バイト多い場合は次のバージョンになる
+		if tt.version < maxVersion {
+			code, err = Encode(bytes.Repeat([]byte("a"), tt.capacity+1))
+			require.NoError(t, err)
+			assert.Equal(t, (tt.version+1)*4+17, code.Size, "version %d", tt.version+1)
+		}
+	}
+
+	// 最大のバージョンに収まらない場合はエラー
+	_, err := Encode(bytes.Repeat([]byte("a"), 214))
+	assert.ErrorIs(t, err, ErrDataTooLong)
+
+	// 空のデータはバージョン1になる
+	code, err := Encode(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 21, code.Size)
+}
+
+func TestEncodeData(t *testing.T) {
+	// モード指示子0100・文字数5・"hello"・終端パターン0000の後に埋め草を続ける
+	expected := []byte{
+		0x40, 0x56, 0x86, 0x56, 0xC6, 0xC6, 0xF0,
+		0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC,
+	}
+	assert.Equal(t, expected, encodeData(1, []byte("hello")))
+
+	// バージョン10以上は文字数指示子が16ビットになる
+	codewords := encodeData(10, []byte("a"))
+	assert.Equal(t, []byte{0x40, 0x00, 0x16, 0x10, 0xEC}, codewords[:5])
+	assert.Len(t, codewords, totalDataCodewords(10))
+}
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// 1-Mの "HELLO WORLD"（英数字モード）のデータコード語と誤り訂正コード語
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	assert.Equal(t, expected, reedSolomonRemainder(data, reedSolomonDivisor(10)))
+}
+
+func TestDrawFormatBits(t *testing.T) {
+	for mask, expected := range levelMFormatBits {
+		c := newCode(1)
+		c.drawFormatBits(mask)
+		first, second := readFormatBits(c)
+		assert.Equal(t, expected, first, "mask %d", mask)
+		assert.Equal(t, expected, second, "mask %d", mask)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		version int
+	}{
+		{"Short", "hello", 1},
+		{"ProfileURL", "https://gox.example.com/@alice", 3},
+		{"VersionInfo", string(bytes.Repeat([]byte("x"), 120)), 7},
+		{"TwoGroups", string(bytes.Repeat([]byte("y"), 150)), 8},
+		{"LongCountIndicator", string(bytes.Repeat([]byte("z"), 200)), 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Encode([]byte(tt.data))
+			require.NoError(t, err)
+			require.Equal(t, tt.version*4+17, code.Size)
+
+			// 3か所のファインダーパターンと常に暗のモジュール
+			for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+				assertFinder(t, code, corner[0], corner[1])
+			}
+			assert.True(t, code.Dark(8, code.Size-8))
+
+			// タイミングパターン
+			for i := 8; i < code.Size-8; i++ {
+				assert.Equal(t, i%2 == 0, code.Dark(6, i))
+				assert.Equal(t, i%2 == 0, code.Dark(i, 6))
+			}
+
+			// 形式情報は2か所とも同じで、誤り訂正レベルMのいずれかのマスクを表す
+			first, second := readFormatBits(code)
+			require.Equal(t, first, second)
+			mask := -1
+			for m, bits := range levelMFormatBits {
+				if bits == first {
+					mask = m
+				}
+			}
+			require.GreaterOrEqual(t, mask, 0, "format bits %s", first)
+
+			// 型番情報（バージョン7以上）
+			if tt.version >= 7 {
+				assert.Equal(t, versionInfoBits[tt.version], readVersionBits(code))
+			}
+
+			// マスクを外して読み出したコード語が、データと誤り訂正コード語に一致する
+			code.applyMask(mask)
+			expected := addErrorCorrection(tt.version, encodeData(tt.version, []byte(tt.data)))
+			assert.Equal(t, expected, readCodewords(code, len(expected)))
+		})
+	}
+}
+
+func TestDarkOutOfRange(t *testing.T) {
+	code, err := Encode([]byte("hello"))
+	require.NoError(t, err)
+
+	assert.False(t, code.Dark(-1, 0))
+	assert.False(t, code.Dark(0, -1))
+	assert.False(t, code.Dark(code.Size, 0))
+	assert.False(t, code.Dark(0, code.Size))
+}
+
+// assertFinder 左上が(x, y)の7x7のファインダーパターンを確認する
+func assertFinder(t *testing.T, code *Code, x, y int) {
+	t.Helper()
+	for dy := 0; dy < 7; dy++ {
+		for dx := 0; dx < 7; dx++ {
+			dist := max(abs(dx-3), abs(dy-3))
+			assert.Equal(t, dist != 2, code.Dark(x+dx, y+dy), "finder at (%d, %d)", x+dx, y+dy)
+		}
+	}
+}
+
+// readFormatBits 左上と、右上・左下に配置された形式情報を上位ビットから読み出す
+func readFormatBits(c *Code) (string, string) {
+	first := make([]byte, 15)
+	second := make([]byte, 15)
+	set := func(bits []byte, i int, dark bool) {
+		bits[14-i] = '0'
+		if dark {
+			bits[14-i] = '1'
+		}
+	}
+
+	for i := 0; i <= 5; i++ {
+		set(first, i, c.modules[i][8])
+	}
+	set(first, 6, c.modules[7][8])
+	set(first, 7, c.modules[8][8])
+	set(first, 8, c.modules[8][7])
+	for i := 9; i < 15; i++ {
+		set(first, i, c.modules[8][14-i])
+	}
+
+	for i := 0; i < 8; i++ {
+		set(second, i, c.modules[8][c.Size-1-i])
+	}
+	for i := 8; i < 15; i++ {
+		set(second, i, c.modules[c.Size-15+i][8])
+	}
+	return string(first), string(second)
+}
+
+// readVersionBits 右上に配置された型番情報を上位ビットから読み出し、左下と一致しない場合は空文字列を返す
+func readVersionBits(c *Code) string {
+	bits := make([]byte, 18)
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		if c.modules[b][a] != c.modules[a][b] {
+			return ""
+		}
+		bits[17-i] = '0'
+		if c.modules[b][a] {
+			bits[17-i] = '1'
+		}
+	}
+	return string(bits)
+}
+
+// readCodewords 右下から2列ずつジグザグに、機能パターン以外のモジュールからコード語を読み出す
+func readCodewords(c *Code, n int) []byte {
+	result := make([]byte, n)
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.function[y][x] || i >= n*8 {
+					continue
+				}
+				if c.modules[y][x] {
+					result[i/8] |= 1 << (7 - i%8)
+				}
+				i++
+			}
+		}
+	}
+	return result
+}
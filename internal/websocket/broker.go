@@ -0,0 +1,156 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// brokerPublishTimeout はブローカーへのメッセージの送信を待つ時間の上限
+const brokerPublishTimeout = 2 * time.Second
+
+// Broker は複数のインスタンスのハブの間でメッセージを中継する
+// 配信は最大1回で、停止中のインスタンスへのメッセージは破棄される
+type Broker interface {
+	// Publish はすべてのインスタンス（自分を含む）にメッセージを送信する
+	Publish(ctx context.Context, message []byte) error
+
+	// Subscribe は受信したメッセージをhandlerに渡す（ctxが終了するまで戻らない）
+	Subscribe(ctx context.Context, handler func(message []byte)) error
+}
+
+// brokerMessageKind はインスタンス間で中継する操作の種類
+type brokerMessageKind string
+
+const (
+	brokerNotify     brokerMessageKind = "notify"
+	brokerBroadcast  brokerMessageKind = "broadcast"
+	brokerDisconnect brokerMessageKind = "disconnect"
+	brokerStream     brokerMessageKind = "stream"
+)
+
+// brokerMessage はインスタンス間で中継するメッセージ
+type brokerMessage struct {
+	// 送信したインスタンス（自分が送信したメッセージは既に処理済みのため無視する）
+	Origin uuid.UUID         `json:"origin"`
+	Kind   brokerMessageKind `json:"kind"`
+	// 対象のユーザー（ブロードキャストの場合は空）
+	UserIDs []uuid.UUID `json:"user_ids,omitempty"`
+	// 対象のストリームのキーと、配信する投稿の投稿者（ストリームへの配信の場合）
+	Streams  []string   `json:"streams,omitempty"`
+	AuthorID *uuid.UUID `json:"author_id,omitempty"`
+	// クライアントに送信するメッセージ（切断の場合は空）
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// SetBroker は他のインスタンスのハブとメッセージを中継するブローカーを設定する（Runの前に呼ぶ）
+// 設定しない場合は、このインスタンスに接続しているクライアントにのみ配信する
+func (h *Hub) SetBroker(broker Broker) {
+	h.broker = broker
+}
+
+// publish は他のインスタンスのハブにメッセージを中継する
+// 失敗してもこのインスタンスのクライアントへの配信は済んでいるため、ログに記録するのみとする
+func (h *Hub) publish(kind brokerMessageKind, userIDs []uuid.UUID, payload []byte) {
+	h.relay(brokerMessage{
+		Origin:  h.instanceID,
+		Kind:    kind,
+		UserIDs: userIDs,
+		Payload: payload,
+	})
+}
+
+// publishStreams は他のインスタンスのハブにストリームへの配信を中継する
+// 配信先の絞り込みは受信したインスタンスで行う
+func (h *Hub) publishStreams(keys []string, authorID uuid.UUID, payload []byte) {
+	message := brokerMessage{
+		Origin:  h.instanceID,
+		Kind:    brokerStream,
+		Streams: keys,
+		Payload: payload,
+	}
+	if authorID != uuid.Nil {
+		message.AuthorID = &authorID
+	}
+	h.relay(message)
+}
+
+// relay はブローカーにメッセージを送信する
+func (h *Hub) relay(msg brokerMessage) {
+	if h.broker == nil {
+		return
+	}
+
+	kind := msg.Kind
+	message, err := json.Marshal(msg)
+	if err != nil {
+		h.log.Error("WebSocketメッセージの中継に失敗しました", "kind", kind, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), brokerPublishTimeout)
+	defer cancel()
+	if err := h.broker.Publish(ctx, message); err != nil {
+		h.log.Warn("WebSocketメッセージの中継に失敗しました", "kind", kind, "error", err)
+	}
+}
+
+// subscribe はハブが停止するまで、他のインスタンスから中継されたメッセージをこのインスタンスのクライアントに配信する
+// ブローカーとの接続が切れた場合は待ってから再接続する
+func (h *Hub) subscribe() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-h.done
+		cancel()
+	}()
+
+	for {
+		err := h.broker.Subscribe(ctx, h.deliverRelayed)
+		if ctx.Err() != nil {
+			return
+		}
+		h.log.Warn("WebSocketメッセージの受信が中断されました。再接続します", "error", err)
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverRelayed は他のインスタンスから中継されたメッセージをこのインスタンスのクライアントに配信する
+func (h *Hub) deliverRelayed(data []byte) {
+	var message brokerMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		h.log.Warn("中継されたWebSocketメッセージを読み取れませんでした", "error", err)
+		return
+	}
+	if message.Origin == h.instanceID {
+		return
+	}
+
+	switch message.Kind {
+	case brokerNotify:
+		for _, userID := range message.UserIDs {
+			h.notifyLocal(userID, message.Payload)
+		}
+	case brokerBroadcast:
+		select {
+		case h.broadcast <- message.Payload:
+		case <-h.done:
+		}
+	case brokerDisconnect:
+		for _, userID := range message.UserIDs {
+			h.disconnectLocal(userID)
+		}
+	case brokerStream:
+		authorID := uuid.Nil
+		if message.AuthorID != nil {
+			authorID = *message.AuthorID
+		}
+		h.publishStreamsLocal(message.Streams, authorID, message.Payload)
+	}
+}
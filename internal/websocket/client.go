@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/TakuyaAizawa/gox/pkg/logger"
@@ -36,10 +37,47 @@ type Client struct {
 	// 送信メッセージチャネル
 	send chan []byte
 
+	// 送信チャネルが閉じられたときに送るクローズフレームの内容（ハブが送信チャネルを閉じる前に設定する）
+	closeMessage []byte
+
+	// 購読中のストリームのキー（ハブのループのみが使用する）
+	streams map[string]bool
+
 	// ロガー
 	log logger.Logger
 }
 
+// clientMessage はクライアントから送信されるコマンド
+// idを指定した場合は、結果（result）やエラーフレーム（error）に同じidが付く
+//
+//	{"type":"subscribe","data":{"stream":"hashtag","tag":"golang"}}  ストリームの購読（購読解除は "unsubscribe"）
+//	{"type":"ack","data":{"seq":42}}                                 受信した通知の連番の確認
+//	{"type":"resume","data":{"last_seq":42}}                         再接続後、受信できなかった通知の再送の要求
+//	{"type":"ping","id":"1","data":{...}}                            dataをそのまま返すpong
+//	{"type":"mark_read","id":"2","data":{"notification_id":"..."}}   HandleCommandで登録したコマンド
+type clientMessage struct {
+	// コマンドの種類
+	Type string `json:"type"`
+
+	// クライアントが付けるリクエストの識別子
+	ID string `json:"id,omitempty"`
+
+	// コマンドの引数
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// ackCommand はackコマンドの引数
+type ackCommand struct {
+	// 受信した通知の連番
+	Seq int64 `json:"seq" binding:"required,min=1"`
+}
+
+// resumeCommand はresumeコマンドの引数
+type resumeCommand struct {
+	// 最後に受信した通知の連番（省略時は最後にackした連番）
+	LastSeq *int64 `json:"last_seq" binding:"omitempty,min=0"`
+}
+
 // NewClient は新しいWebSocketクライアントを作成する
 func NewClient(hub *Hub, conn *websocket.Conn, userID uuid.UUID, log logger.Logger) *Client {
 	return &Client{
@@ -55,7 +93,10 @@ func NewClient(hub *Hub, conn *websocket.Conn, userID uuid.UUID, log logger.Logg
 // 各クライアント接続ごとに1つのgoroutineで実行される必要がある
 func (c *Client) ReadPump() {
 	defer func() {
-		c.hub.unregister <- c
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
 		c.conn.Close()
 	}()
 
@@ -67,20 +108,63 @@ func (c *Client) ReadPump() {
 	})
 
 	// クライアントからのメッセージ読み取りループ
-	// 現在の実装では、クライアントからのメッセージは単に破棄される
-	// 必要に応じて、ここでクライアントからのメッセージを処理することができる
+	// クライアントはコマンド（ストリームの購読、通知の受信確認・再送要求、既読化など）を送信できる
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, message, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				c.log.Warn("WebSocket読み取りエラー", "error", err)
 			}
 			break
 		}
-		// 現在はクライアントからのメッセージは処理しない
+		c.handleClientMessage(message)
 	}
 }
 
+// handleClientMessage はクライアントから受信したコマンドを検証し、処理する
+// 処理できない場合はエラーフレームを返し、接続は維持する
+func (c *Client) handleClientMessage(data []byte) {
+	var message clientMessage
+	if err := json.Unmarshal(data, &message); err != nil || message.Type == "" {
+		c.hub.sendToClient(c, errorReply(message.ID, ErrorCodeInvalidMessage, "message must be a JSON object with a type"))
+		return
+	}
+
+	switch message.Type {
+	case "subscribe", "unsubscribe":
+		var subscription Subscription
+		if err := DecodeCommand(message.Data, &subscription); err != nil {
+			c.hub.sendToClient(c, c.commandErrorReply(message.ID, err))
+			return
+		}
+		c.handleSubscription(message.ID, subscription, message.Type == "subscribe")
+	case "ack":
+		var command ackCommand
+		if err := DecodeCommand(message.Data, &command); err != nil {
+			c.hub.sendToClient(c, c.commandErrorReply(message.ID, err))
+			return
+		}
+		c.handleAck(command.Seq)
+	case "resume":
+		var command resumeCommand
+		if err := DecodeCommand(message.Data, &command); err != nil {
+			c.hub.sendToClient(c, c.commandErrorReply(message.ID, err))
+			return
+		}
+		c.handleResume(message.ID, command.LastSeq)
+	case "ping":
+		c.handlePing(message)
+	default:
+		c.runCommand(message)
+	}
+}
+
+// errorReply はクライアントに送信するエラーフレームを作成する
+func errorReply(id, code, message string) []byte {
+	reply, _ := json.Marshal(NewErrorMessage(id, code, message))
+	return reply
+}
+
 // WritePump はクライアントへのメッセージ送信を処理する
 // 各クライアント接続ごとに1つのgoroutineで実行される必要がある
 func (c *Client) WritePump() {
@@ -96,7 +180,7 @@ func (c *Client) WritePump() {
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				// Hubがチャネルを閉じた
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				c.conn.WriteMessage(websocket.CloseMessage, c.closeMessage)
 				return
 			}
 
@@ -0,0 +1,143 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// commandTimeout はクライアントからのコマンドの処理を待つ時間の上限
+const commandTimeout = 5 * time.Second
+
+// エラーフレームのコード
+const (
+	// ErrorCodeInvalidMessage はメッセージがJSONとして読み取れない、またはtypeがない
+	ErrorCodeInvalidMessage = "invalid_message"
+
+	// ErrorCodeUnknownCommand は未対応のコマンド
+	ErrorCodeUnknownCommand = "unknown_command"
+
+	// ErrorCodeInvalidParams はdataがコマンドのスキーマに合わない
+	ErrorCodeInvalidParams = "invalid_params"
+
+	// ErrorCodeNotFound は対象が存在しない（他のユーザーのものを含む）
+	ErrorCodeNotFound = "not_found"
+
+	// ErrorCodeLimitExceeded は上限を超えた
+	ErrorCodeLimitExceeded = "limit_exceeded"
+
+	// ErrorCodeUnavailable はこのサーバーでは利用できない
+	ErrorCodeUnavailable = "unavailable"
+
+	// ErrorCodeInternal はサーバーの内部エラー
+	ErrorCodeInternal = "internal_error"
+)
+
+// CommandHandler はクライアントから送信されたコマンドを処理し、resultメッセージで返す結果を返す
+// CommandErrorを返した場合はそのコードで、それ以外のエラーはinternal_errorでエラーフレームを返す
+type CommandHandler func(ctx context.Context, userID uuid.UUID, data json.RawMessage) (interface{}, error)
+
+// CommandError はクライアントにエラーフレームで返すエラー
+type CommandError struct {
+	Code    string
+	Message string
+}
+
+// Error はエラーメッセージを返す
+func (e *CommandError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// NewCommandError はクライアントに返すエラーを作成する
+func NewCommandError(code, message string) *CommandError {
+	return &CommandError{Code: code, Message: message}
+}
+
+// HandleCommand はクライアントから送信されるコマンドの処理を登録する（接続を受け付ける前に呼ぶ）
+// subscribe・unsubscribe・ack・resume・pingはハブが処理するため登録できない
+func (h *Hub) HandleCommand(name string, handler CommandHandler) {
+	h.commandMutex.Lock()
+	defer h.commandMutex.Unlock()
+
+	if h.commands == nil {
+		h.commands = make(map[string]CommandHandler)
+	}
+	h.commands[name] = handler
+}
+
+// commandHandler は登録されたコマンドの処理を返す
+func (h *Hub) commandHandler(name string) (CommandHandler, bool) {
+	h.commandMutex.RLock()
+	defer h.commandMutex.RUnlock()
+
+	handler, ok := h.commands[name]
+	return handler, ok
+}
+
+// DecodeCommand はコマンドのdataをvに読み取り、bindingタグで検証する
+// 未知のフィールドを含む場合や検証に失敗した場合はinvalid_paramsのCommandErrorを返す
+func DecodeCommand(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return NewCommandError(ErrorCodeInvalidParams, err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(v); err != nil {
+		return NewCommandError(ErrorCodeInvalidParams, err.Error())
+	}
+	return nil
+}
+
+// runCommand は登録されたコマンドを処理し、結果またはエラーフレームを返す（ReadPumpから呼ぶ）
+func (c *Client) runCommand(message clientMessage) {
+	handler, ok := c.hub.commandHandler(message.Type)
+	if !ok {
+		c.hub.sendToClient(c, errorReply(message.ID, ErrorCodeUnknownCommand, "unknown command: "+message.Type))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	result, err := handler(ctx, c.ID, message.Data)
+	if err != nil {
+		c.hub.sendToClient(c, c.commandErrorReply(message.ID, err))
+		return
+	}
+
+	reply, err := json.Marshal(NewResultMessage(message.ID, message.Type, result))
+	if err != nil {
+		c.hub.sendToClient(c, errorReply(message.ID, ErrorCodeInternal, "failed to encode result"))
+		return
+	}
+	c.hub.sendToClient(c, reply)
+}
+
+// commandErrorReply はコマンドのエラーをエラーフレームにする（内部エラーの詳細はクライアントに返さずログに記録する）
+func (c *Client) commandErrorReply(id string, err error) []byte {
+	var commandErr *CommandError
+	if errors.As(err, &commandErr) {
+		return errorReply(id, commandErr.Code, commandErr.Message)
+	}
+	c.log.Error("WebSocketコマンドの処理中にエラーが発生しました", "user_id", c.ID, "error", err)
+	return errorReply(id, ErrorCodeInternal, "internal error")
+}
+
+// handlePing はpingのdataをそのままpongで返す（ReadPumpから呼ぶ）
+// アプリケーションレベルで往復時間を測るために使う（接続の維持はWebSocketのping/pongフレームで行う）
+func (c *Client) handlePing(message clientMessage) {
+	reply, err := json.Marshal(NewPongMessage(message.ID, message.Data))
+	if err != nil {
+		return
+	}
+	c.hub.sendToClient(c, reply)
+}
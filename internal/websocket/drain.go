@@ -0,0 +1,171 @@
+package websocket
+
+import (
+	"encoding/json"
+	"math/rand"
+	"time"
+)
+
+// 再接続を指示したクライアントが再接続するまでに待つ時間の上限（再接続先への負荷を分散するため）
+const reconnectJitter = 5 * time.Second
+
+// DrainStatus はインスタンスのWebSocket接続のドレイン（ローリングデプロイのための接続の移動）の状態
+type DrainStatus struct {
+	// ドレイン中かどうか（ドレイン中は新しい接続を受け付けない）
+	Draining bool
+	// ドレインを開始した時刻
+	StartedAt time.Time
+	// 接続中のクライアントに再接続を指示し終えるまでの時間
+	Window time.Duration
+	// 残っている接続の数
+	RemainingConnections int
+}
+
+// SafeToTerminate はドレイン中で、残っている接続がなくインスタンスを停止できるかを返す
+func (s DrainStatus) SafeToTerminate() bool {
+	return s.Draining && s.RemainingConnections == 0
+}
+
+// drainRequest はドレインの開始をハブのループに伝える
+type drainRequest struct {
+	generation uint64
+	window     time.Duration
+}
+
+// reconnectRequest は1つのクライアントへの再接続の指示をハブのループに伝える
+type reconnectRequest struct {
+	client     *Client
+	generation uint64
+}
+
+// StartDrain はドレインを開始する（既にドレイン中の場合はfalseを返す）
+// 新しい接続の受け付けを止め、接続中のクライアントにはwindowの間でランダムな時刻に再接続を指示してから切断する
+func (h *Hub) StartDrain(window time.Duration) bool {
+	h.drainMutex.Lock()
+	if h.drainStatus.Draining {
+		h.drainMutex.Unlock()
+		return false
+	}
+	h.drainGeneration++
+	generation := h.drainGeneration
+	h.drainStatus = DrainStatus{
+		Draining:  true,
+		StartedAt: time.Now().UTC(),
+		Window:    window,
+	}
+	h.drainMutex.Unlock()
+
+	select {
+	case h.drain <- drainRequest{generation: generation, window: window}:
+	case <-h.done:
+		return false
+	}
+	h.log.Info("WebSocket接続のドレインを開始しました", "window", window, "connections", h.ConnectionCount())
+	return true
+}
+
+// CancelDrain はドレインを取り消し、新しい接続の受け付けを再開する（まだ再接続を指示していないクライアントはそのまま残る）
+func (h *Hub) CancelDrain() {
+	h.drainMutex.Lock()
+	defer h.drainMutex.Unlock()
+
+	if h.drainStatus.Draining {
+		h.drainGeneration++
+		h.drainStatus = DrainStatus{}
+		h.log.Info("WebSocket接続のドレインを取り消しました")
+	}
+}
+
+// Draining はドレイン中かどうかを返す
+func (h *Hub) Draining() bool {
+	h.drainMutex.Lock()
+	defer h.drainMutex.Unlock()
+
+	return h.drainStatus.Draining
+}
+
+// GetDrainStatus はドレインの状態と残っている接続の数を返す
+func (h *Hub) GetDrainStatus() DrainStatus {
+	h.drainMutex.Lock()
+	status := h.drainStatus
+	h.drainMutex.Unlock()
+
+	status.RemainingConnections = h.ConnectionCount()
+	return status
+}
+
+// ConnectionCount は接続中のクライアントの数を返す
+func (h *Hub) ConnectionCount() int {
+	h.userMutex.RLock()
+	defer h.userMutex.RUnlock()
+
+	count := 0
+	for _, clients := range h.userClients {
+		count += len(clients)
+	}
+	return count
+}
+
+// isCurrentDrain は指定した世代のドレインが取り消されずに続いているかを返す
+func (h *Hub) isCurrentDrain(generation uint64) bool {
+	h.drainMutex.Lock()
+	defer h.drainMutex.Unlock()
+
+	return h.drainStatus.Draining && h.drainGeneration == generation
+}
+
+// scheduleReconnects は接続中のすべてのクライアントに、windowの間でランダムな時刻に再接続を指示する（ハブのループから呼ぶ）
+func (h *Hub) scheduleReconnects(req drainRequest) {
+	for client := range h.clients {
+		var delay time.Duration
+		if req.window > 0 {
+			delay = time.Duration(rand.Int63n(int64(req.window)))
+		}
+		request := reconnectRequest{client: client, generation: req.generation}
+		time.AfterFunc(delay, func() {
+			select {
+			case h.reconnect <- request:
+			case <-h.done:
+			}
+		})
+	}
+}
+
+// sendReconnect はクライアントに再接続を指示してから切断する（ハブのループから呼ぶ）
+func (h *Hub) sendReconnect(req reconnectRequest) {
+	client := req.client
+	if _, ok := h.clients[client]; !ok || !h.isCurrentDrain(req.generation) {
+		return
+	}
+
+	retryAfter := time.Duration(rand.Int63n(int64(reconnectJitter)))
+	if payload, err := json.Marshal(NewReconnectMessage("draining", retryAfter)); err == nil {
+		select {
+		case client.send <- payload:
+		default:
+		}
+	}
+
+	// 送信チャネルを閉じると、キューにあるメッセージを送信してから接続が閉じられる
+	delete(h.clients, client)
+	close(client.send)
+	h.removeAllSubscriptions(client)
+
+	h.userMutex.Lock()
+	userClients := h.userClients[client.ID]
+	for i, c := range userClients {
+		if c == client {
+			h.userClients[client.ID] = append(userClients[:i], userClients[i+1:]...)
+			break
+		}
+	}
+	offline := len(h.userClients[client.ID]) == 0
+	if offline {
+		delete(h.userClients, client.ID)
+	}
+	h.userMutex.Unlock()
+
+	if offline {
+		h.presenceChanged(client.ID, false)
+	}
+}
@@ -31,10 +31,66 @@ type Hub struct {
 	// クライアント登録解除リクエスト
 	unregister chan *Client
 
+	// ユーザーの全クライアントの切断リクエスト
+	disconnect chan uuid.UUID
+
+	// ストリームの購読者（キーごと、ハブのループのみが使用する）と、購読の変更・配信のリクエスト
+	streams       map[string]map[*Client]bool
+	subscriptions chan subscriptionRequest
+	streamPublish chan *streamMessage
+
+	// SetStreamAuthorizer・SetStreamFilterで設定されたストリームの購読の確認と配信先の絞り込み（nilの場合は行わない）
+	streamAuthorizer StreamAuthorizer
+	streamFilter     StreamFilter
+	streamHookMutex  sync.RWMutex
+
+	// クライアントから受信したメッセージへの応答の送信リクエスト
+	replies chan clientReply
+
+	// HandleCommandで登録されたコマンドの処理
+	commands     map[string]CommandHandler
+	commandMutex sync.RWMutex
+
+	// 再接続したクライアントに通知を再送するためのバッファ（nilの場合は通知に連番を付けない）
+	buffer EventBuffer
+
+	// ドレインの開始リクエストと、クライアントへの再接続の指示
+	drain     chan drainRequest
+	reconnect chan reconnectRequest
+
+	// ドレインの状態の排他制御
+	drainMutex      sync.Mutex
+	drainStatus     DrainStatus
+	drainGeneration uint64
+
+	// シャットダウンの開始リクエストと、ハブのループの終了の通知
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	done         chan struct{}
+
+	// シャットダウン中に切断を指示し、まだ接続が閉じられていないクライアント（ハブのループのみが使用する）
+	closing map[*Client]bool
+
+	// 他のインスタンスのハブとメッセージを中継するブローカー（nilの場合は中継しない）と、このインスタンスの識別子
+	broker     Broker
+	instanceID uuid.UUID
+
+	// ユーザーごとの同時接続数の上限（0の場合は制限しない）
+	maxUserClients int
+
+	// ユーザーのこのインスタンスへの接続の有無が変わったときに呼び出す関数（nilの場合は呼び出さない）
+	presenceHandler PresenceHandler
+
 	// ロガー
 	log logger.Logger
 }
 
+// clientReply は特定のクライアントへの応答メッセージを表す
+type clientReply struct {
+	client   *Client
+	payloads [][]byte
+}
+
 // NotificationMessage はユーザーへの通知メッセージを表す
 type NotificationMessage struct {
 	// 通知の受信者ID
@@ -47,36 +103,89 @@ type NotificationMessage struct {
 // NewHub は新しいHubを作成する
 func NewHub(log logger.Logger) *Hub {
 	return &Hub{
-		clients:     make(map[*Client]bool),
-		userClients: make(map[uuid.UUID][]*Client),
-		broadcast:   make(chan []byte),
-		notify:      make(chan *NotificationMessage),
-		register:    make(chan *Client),
-		unregister:  make(chan *Client),
-		log:         log,
+		clients:       make(map[*Client]bool),
+		userClients:   make(map[uuid.UUID][]*Client),
+		broadcast:     make(chan []byte),
+		notify:        make(chan *NotificationMessage),
+		register:      make(chan *Client),
+		unregister:    make(chan *Client),
+		disconnect:    make(chan uuid.UUID),
+		streams:       make(map[string]map[*Client]bool),
+		subscriptions: make(chan subscriptionRequest),
+		streamPublish: make(chan *streamMessage),
+		replies:       make(chan clientReply),
+		drain:         make(chan drainRequest),
+		reconnect:     make(chan reconnectRequest),
+		shutdown:      make(chan struct{}),
+		done:          make(chan struct{}),
+		instanceID:    uuid.New(),
+		log:           log,
 	}
 }
 
 // Run はハブの主要ループを開始する
+// Shutdownが呼ばれた場合は、すべての接続が閉じられてから終了する
 func (h *Hub) Run() {
+	if h.broker != nil {
+		go h.subscribe()
+	}
+
+	shutdown := h.shutdown
 	for {
 		select {
+		case <-shutdown:
+			// 以降はシャットダウンを繰り返し受け取らないようにする
+			shutdown = nil
+			h.closeAll()
+			if len(h.closing) == 0 {
+				close(h.done)
+				return
+			}
+
 		case client := <-h.register:
+			// シャットダウン中は登録せずに切断する
+			if h.closing != nil {
+				h.closeForShutdown(client)
+				continue
+			}
+
 			// クライアントを登録
 			h.clients[client] = true
 
 			// ユーザーIDでインデックス化
 			h.userMutex.Lock()
 			h.userClients[client.ID] = append(h.userClients[client.ID], client)
+			online := len(h.userClients[client.ID]) == 1
+			evicted := h.evictExcessClients(client.ID)
 			h.userMutex.Unlock()
 
+			if evicted > 0 {
+				h.log.Info("同時接続数の上限を超えたため古いWebSocket接続を切断", "user_id", client.ID, "client_count", evicted)
+			}
+
+			// ユーザーの最初の接続の場合はオンラインになったことを知らせる
+			if online {
+				h.presenceChanged(client.ID, true)
+			}
+
 			h.log.Info("WebSocketクライアント接続", "user_id", client.ID)
 
 		case client := <-h.unregister:
+			// シャットダウン中はすべての接続が閉じられたら終了する
+			if h.closing != nil {
+				delete(h.closing, client)
+				if len(h.closing) == 0 {
+					close(h.done)
+					return
+				}
+				continue
+			}
+
 			// クライアントの登録解除
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
 				close(client.send)
+				h.removeAllSubscriptions(client)
 
 				// ユーザーのクライアントリストからも削除
 				h.userMutex.Lock()
@@ -89,14 +198,56 @@ func (h *Hub) Run() {
 					}
 				}
 				// クライアントがなくなったらマップからも削除
-				if len(h.userClients[client.ID]) == 0 {
+				offline := len(h.userClients[client.ID]) == 0
+				if offline {
 					delete(h.userClients, client.ID)
 				}
 				h.userMutex.Unlock()
 
+				// ユーザーの最後の接続の場合はオフラインになったことを知らせる
+				if offline {
+					h.presenceChanged(client.ID, false)
+				}
+
 				h.log.Info("WebSocketクライアント切断", "user_id", client.ID)
 			}
 
+		case userID := <-h.disconnect:
+			// ユーザーの全クライアントを切断（送信チャネルを閉じると接続も閉じられる）
+			h.userMutex.Lock()
+			userClients := h.userClients[userID]
+			delete(h.userClients, userID)
+			h.userMutex.Unlock()
+
+			for _, client := range userClients {
+				if _, ok := h.clients[client]; ok {
+					delete(h.clients, client)
+					close(client.send)
+					h.removeAllSubscriptions(client)
+				}
+			}
+
+			if len(userClients) > 0 {
+				h.presenceChanged(userID, false)
+				h.log.Info("WebSocketクライアントを強制切断", "user_id", userID, "client_count", len(userClients))
+			}
+
+		case req := <-h.drain:
+			// 接続中のクライアントに順に再接続を指示する
+			h.scheduleReconnects(req)
+
+		case req := <-h.reconnect:
+			h.sendReconnect(req)
+
+		case req := <-h.subscriptions:
+			h.updateSubscription(req)
+
+		case message := <-h.streamPublish:
+			h.sendStream(message)
+
+		case reply := <-h.replies:
+			h.sendReplies(reply.client, reply.payloads)
+
 		case message := <-h.broadcast:
 			// すべてのクライアントにブロードキャスト
 			for client := range h.clients {
@@ -106,6 +257,7 @@ func (h *Hub) Run() {
 					// 送信バッファがいっぱいの場合はクライアントを切断
 					close(client.send)
 					delete(h.clients, client)
+					h.removeAllSubscriptions(client)
 
 					h.userMutex.Lock()
 					userClients := h.userClients[client.ID]
@@ -115,10 +267,15 @@ func (h *Hub) Run() {
 							break
 						}
 					}
-					if len(h.userClients[client.ID]) == 0 {
+					offline := len(h.userClients[client.ID]) == 0
+					if offline {
 						delete(h.userClients, client.ID)
 					}
 					h.userMutex.Unlock()
+
+					if offline {
+						h.presenceChanged(client.ID, false)
+					}
 				}
 			}
 
@@ -147,33 +304,118 @@ func (h *Hub) Run() {
 	}
 }
 
-// NotifyUser は特定のユーザーに通知を送信する
+// NotifyUser は特定のユーザーに通知を送信する（ブローカーを設定した場合は他のインスタンスに接続しているクライアントにも送信する）
 func (h *Hub) NotifyUser(userID uuid.UUID, notification interface{}) error {
 	payload, err := json.Marshal(notification)
 	if err != nil {
 		return err
 	}
 
-	h.notify <- &NotificationMessage{
-		UserID:  userID,
-		Payload: payload,
+	h.notifyLocal(userID, payload)
+	h.publish(brokerNotify, []uuid.UUID{userID}, payload)
+	return nil
+}
+
+// NotifyUsers は複数のユーザーに同じ通知を送信する
+// 他のインスタンスへは1つのメッセージにまとめて中継するため、接続中かわからない多数のユーザーへの送信に使う
+func (h *Hub) NotifyUsers(userIDs []uuid.UUID, notification interface{}) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
 	}
 
+	for _, userID := range userIDs {
+		if h.IsOnline(userID) {
+			h.notifyLocal(userID, payload)
+		}
+	}
+	h.publish(brokerNotify, userIDs, payload)
 	return nil
 }
 
+// notifyLocal はこのインスタンスに接続しているユーザーのクライアントに通知を送信する
+func (h *Hub) notifyLocal(userID uuid.UUID, payload []byte) {
+	// シャットダウン後は送信しない
+	select {
+	case h.notify <- &NotificationMessage{UserID: userID, Payload: payload}:
+	case <-h.done:
+	}
+}
+
+// IsOnline は指定したユーザーがこのインスタンスのWebSocketで接続中かを返す（他のインスタンスへの接続は含まない）
+func (h *Hub) IsOnline(userID uuid.UUID) bool {
+	h.userMutex.RLock()
+	defer h.userMutex.RUnlock()
+
+	return len(h.userClients[userID]) > 0
+}
+
+// DisconnectUser は指定したユーザーのすべての接続を切断する（ブローカーを設定した場合は他のインスタンスへの接続も切断する）
+func (h *Hub) DisconnectUser(userID uuid.UUID) {
+	h.disconnectLocal(userID)
+	h.publish(brokerDisconnect, []uuid.UUID{userID}, nil)
+}
+
+// disconnectLocal は指定したユーザーのこのインスタンスへの接続を切断する
+func (h *Hub) disconnectLocal(userID uuid.UUID) {
+	select {
+	case h.disconnect <- userID:
+	case <-h.done:
+	}
+}
+
 // Register はクライアントをハブに登録する
+// シャットダウン後に登録したクライアントはすぐに切断する
 func (h *Hub) Register(client *Client) {
-	h.register <- client
+	select {
+	case h.register <- client:
+	case <-h.done:
+		client.closeMessage = goingAwayMessage
+		close(client.send)
+	}
+}
+
+// sendToClient はクライアントから受信したメッセージへの応答を、そのクライアントにのみ送信する
+func (h *Hub) sendToClient(client *Client, payloads ...[]byte) {
+	select {
+	case h.replies <- clientReply{client: client, payloads: payloads}:
+	case <-h.done:
+	}
+}
+
+// sendReplies はクライアントに応答を送信する（ハブのループから呼ぶ）
+func (h *Hub) sendReplies(client *Client, payloads [][]byte) {
+	// 切断済みのクライアントの送信チャネルは閉じられている
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+
+	for _, payload := range payloads {
+		select {
+		case client.send <- payload:
+		default:
+			// バッファがいっぱいの場合は残りの応答を破棄する
+			h.log.Warn("応答の送信失敗: バッファがいっぱい", "user_id", client.ID)
+			return
+		}
+	}
 }
 
-// Broadcast はすべての接続クライアントにメッセージを送信する
+// Broadcast はすべての接続クライアントにメッセージを送信する（ブローカーを設定した場合は他のインスタンスのクライアントにも送信する）
 func (h *Hub) Broadcast(message interface{}) error {
 	payload, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
-	h.broadcast <- payload
+	select {
+	case h.broadcast <- payload:
+	case <-h.done:
+	}
+	h.publish(brokerBroadcast, nil, payload)
 	return nil
 }
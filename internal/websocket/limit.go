@@ -0,0 +1,35 @@
+package websocket
+
+import (
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// policyViolationMessage は同時接続数の上限を超えて切断するときに送るクローズフレームの内容
+var policyViolationMessage = websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many connections")
+
+// SetMaxConnectionsPerUser はユーザーごとの同時接続数の上限を設定する（Runの前に呼ぶ、0の場合は制限しない）
+// 上限はこのインスタンスへの接続数に対して適用される
+func (h *Hub) SetMaxConnectionsPerUser(max int) {
+	h.maxUserClients = max
+}
+
+// evictExcessClients は同時接続数の上限を超えた分の、ユーザーの古い接続から順に切断し、切断した数を返す
+// （ハブのループから、userMutexをロックした状態で呼ぶ）
+func (h *Hub) evictExcessClients(userID uuid.UUID) int {
+	userClients := h.userClients[userID]
+	if h.maxUserClients <= 0 || len(userClients) <= h.maxUserClients {
+		return 0
+	}
+
+	// userClientsは接続した順に並んでいる
+	excess := len(userClients) - h.maxUserClients
+	for _, client := range userClients[:excess] {
+		delete(h.clients, client)
+		client.closeMessage = policyViolationMessage
+		close(client.send)
+		h.removeAllSubscriptions(client)
+	}
+	h.userClients[userID] = append([]*Client(nil), userClients[excess:]...)
+	return excess
+}
@@ -0,0 +1,88 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxConnectionsPerUser(t *testing.T) {
+	log, err := logger.NewLogger("error", "json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		max  int
+	}{
+		{"One", 1},
+		{"Three", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(log)
+			hub.SetMaxConnectionsPerUser(tt.max)
+			go hub.Run()
+
+			userID := uuid.New()
+			clients := make([]*Client, tt.max+1)
+			for i := range clients {
+				clients[i] = NewClient(hub, nil, userID, log)
+				hub.Register(clients[i])
+			}
+
+			// 最も古い接続の送信チャネルが上限超過のクローズフレームを設定して閉じられる
+			oldest := clients[0]
+			select {
+			case _, ok := <-oldest.send:
+				require.False(t, ok, "the oldest client received a message instead of being closed")
+			case <-time.After(time.Second):
+				t.Fatal("the oldest client was not disconnected")
+			}
+			assert.Equal(t, policyViolationMessage, oldest.closeMessage)
+
+			// 新しい順に上限までの接続が残る
+			hub.userMutex.RLock()
+			remaining := append([]*Client(nil), hub.userClients[userID]...)
+			hub.userMutex.RUnlock()
+			assert.Equal(t, clients[1:], remaining)
+
+			for _, client := range clients[1:] {
+				select {
+				case <-client.send:
+					t.Fatal("a client within the limit was disconnected")
+				default:
+				}
+				assert.Nil(t, client.closeMessage)
+			}
+		})
+	}
+}
+
+func TestMaxConnectionsPerUserOtherUsers(t *testing.T) {
+	log, err := logger.NewLogger("error", "json")
+	require.NoError(t, err)
+
+	hub := NewHub(log)
+	hub.SetMaxConnectionsPerUser(1)
+	go hub.Run()
+
+	// 上限はユーザーごとに数える
+	first := NewClient(hub, nil, uuid.New(), log)
+	second := NewClient(hub, nil, uuid.New(), log)
+	hub.Register(first)
+	hub.Register(second)
+
+	// ハブのループが2件目の登録を処理し終えるまで待つ
+	hub.sendToClient(second)
+
+	hub.userMutex.RLock()
+	defer hub.userMutex.RUnlock()
+	assert.Equal(t, []*Client{first}, hub.userClients[first.ID])
+	assert.Equal(t, []*Client{second}, hub.userClients[second.ID])
+	assert.Nil(t, first.closeMessage)
+}
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,8 +29,36 @@ const (
 	// EventTypeMention はメンション通知イベント
 	EventTypeMention EventType = "mention"
 
+	// EventTypeSavedSearch は保存した検索に一致する新しい投稿の通知イベント
+	EventTypeSavedSearch EventType = "saved_search"
+
 	// EventTypeSystem はシステム通知イベント
 	EventTypeSystem EventType = "system"
+
+	// EventTypeTimelineUpdate はタイムラインに新着投稿があることを知らせるイベント
+	EventTypeTimelineUpdate EventType = "timeline_update"
+
+	// EventTypeReconnect はサーバーの停止に備えて別のインスタンスへの再接続を求めるイベント
+	EventTypeReconnect EventType = "reconnect"
+
+	// EventTypeStreamPost は購読中のストリームに新着投稿があることを知らせるイベント
+	EventTypeStreamPost EventType = "stream_post"
+
+	// EventTypeSubscribed と EventTypeUnsubscribed はストリームの購読・購読解除の応答
+	EventTypeSubscribed   EventType = "subscribed"
+	EventTypeUnsubscribed EventType = "unsubscribed"
+
+	// EventTypeReplayed は再送の要求に対して、バッファに残っていた通知をすべて再送したことを知らせるイベント
+	EventTypeReplayed EventType = "replayed"
+
+	// EventTypeResult はクライアントから受信したコマンドの処理結果
+	EventTypeResult EventType = "result"
+
+	// EventTypePong はクライアントから受信したpingへの応答
+	EventTypePong EventType = "pong"
+
+	// EventTypeError はクライアントから受信したコマンドを処理できなかったことを知らせるエラーフレーム
+	EventTypeError EventType = "error"
 )
 
 // WebSocketMessage はWebSocketを通じて送信されるメッセージの基本構造
@@ -86,6 +115,57 @@ type PostInfo struct {
 	Content string `json:"content"`
 }
 
+// TimelineUpdateEvent はタイムラインの新着投稿のヒントを表す
+// 投稿内容は含めず、クライアントは新着件数APIで件数を取得する
+type TimelineUpdateEvent struct {
+	// 対象のタイムライン（"home"）
+	Timeline string `json:"timeline"`
+
+	// 新着投稿ID
+	PostID uuid.UUID `json:"post_id"`
+
+	// 投稿者ID
+	AuthorID uuid.UUID `json:"author_id"`
+
+	// 投稿時刻
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StreamPostEvent はストリームの新着投稿を表す
+// タイムラインの新着ヒントと同じく投稿内容は含めず、クライアントは投稿取得APIで内容を取得する
+// （ブロックや年齢制限の判定は取得時に行われる）
+type StreamPostEvent struct {
+	// 配信されたストリーム（repliesの場合の投稿IDはReplyToID）
+	Stream Stream `json:"stream"`
+
+	// ハッシュタグ（hashtagの場合）
+	Tag string `json:"tag,omitempty"`
+
+	// 新着投稿ID
+	PostID uuid.UUID `json:"post_id"`
+
+	// 投稿者ID
+	AuthorID uuid.UUID `json:"author_id"`
+
+	// 返信先の投稿ID（返信の場合）
+	ReplyToID *uuid.UUID `json:"reply_to_id,omitempty"`
+
+	// 投稿時刻
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ErrorEvent はエラーフレームの内容を表す
+type ErrorEvent struct {
+	// エラーになったコマンドのid（指定された場合）
+	ID string `json:"id,omitempty"`
+
+	// エラーの種類（invalid_params など）
+	Code string `json:"code"`
+
+	// エラーの詳細
+	Message string `json:"message"`
+}
+
 // NewNotificationMessage は通知メッセージを作成する
 func NewNotificationMessage(event NotificationEvent) *WebSocketMessage {
 	return &WebSocketMessage{
@@ -103,3 +183,91 @@ func NewSystemMessage(message string) *WebSocketMessage {
 		},
 	}
 }
+
+// NewTimelineUpdateMessage はタイムラインの新着ヒントメッセージを作成する
+func NewTimelineUpdateMessage(event TimelineUpdateEvent) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: string(EventTypeTimelineUpdate),
+		Data: event,
+	}
+}
+
+// NewReconnectMessage は再接続の指示メッセージを作成する
+// クライアントはretry_after_msだけ待ってから再接続する（接続が閉じられた後、ロードバランサーが別のインスタンスへ振り分ける）
+func NewReconnectMessage(reason string, retryAfter time.Duration) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: string(EventTypeReconnect),
+		Data: map[string]interface{}{
+			"reason":         reason,
+			"retry_after_ms": retryAfter.Milliseconds(),
+		},
+	}
+}
+
+// NewStreamPostMessage はストリームの新着投稿メッセージを作成する
+func NewStreamPostMessage(event StreamPostEvent) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: string(EventTypeStreamPost),
+		Data: event,
+	}
+}
+
+// NewSubscriptionMessage はストリームの購読・購読解除の応答メッセージを作成する
+func NewSubscriptionMessage(subscribed bool, subscription Subscription) *WebSocketMessage {
+	eventType := EventTypeUnsubscribed
+	if subscribed {
+		eventType = EventTypeSubscribed
+	}
+	return &WebSocketMessage{
+		Type: string(eventType),
+		Data: subscription,
+	}
+}
+
+// NewErrorMessage はクライアントのコマンドを処理できなかったことを知らせるエラーフレームを作成する
+func NewErrorMessage(id, code, message string) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: string(EventTypeError),
+		Data: ErrorEvent{
+			ID:      id,
+			Code:    code,
+			Message: message,
+		},
+	}
+}
+
+// NewResultMessage はコマンドの処理結果のメッセージを作成する
+func NewResultMessage(id, command string, result interface{}) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: string(EventTypeResult),
+		Data: map[string]interface{}{
+			"id":      id,
+			"command": command,
+			"result":  result,
+		},
+	}
+}
+
+// NewPongMessage はpingへの応答メッセージを作成する
+func NewPongMessage(id string, payload json.RawMessage) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: string(EventTypePong),
+		Data: map[string]interface{}{
+			"id":      id,
+			"payload": payload,
+		},
+	}
+}
+
+// NewReplayedMessage は通知の再送の完了メッセージを作成する
+// gapがtrueの場合はバッファから削除済みの通知があったため、クライアントは通知一覧APIで取得し直す
+func NewReplayedMessage(lastSeq int64, count int, gap bool) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: string(EventTypeReplayed),
+		Data: map[string]interface{}{
+			"last_seq": lastSeq,
+			"count":    count,
+			"gap":      gap,
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package websocket
+
+import "github.com/google/uuid"
+
+// PresenceHandler はユーザーのこのインスタンスへの接続の有無が変わったときに呼び出される関数
+// ハブのループから呼び出されるため、時間のかかる処理は別のgoroutineで行うこと
+type PresenceHandler func(userID uuid.UUID, online bool)
+
+// SetPresenceHandler はユーザーの最初の接続と最後の切断を知らせる関数を設定する（Runの前に呼ぶ）
+func (h *Hub) SetPresenceHandler(handler PresenceHandler) {
+	h.presenceHandler = handler
+}
+
+// InstanceID はこのインスタンスのハブの識別子を返す
+func (h *Hub) InstanceID() uuid.UUID {
+	return h.instanceID
+}
+
+// OnlineUsers はこのインスタンスに接続中のユーザーのIDを返す
+func (h *Hub) OnlineUsers() []uuid.UUID {
+	h.userMutex.RLock()
+	defer h.userMutex.RUnlock()
+
+	userIDs := make([]uuid.UUID, 0, len(h.userClients))
+	for userID := range h.userClients {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
+
+// presenceChanged はユーザーの接続の有無の変化を知らせる（ハブのループから呼ぶ）
+func (h *Hub) presenceChanged(userID uuid.UUID, online bool) {
+	if h.presenceHandler != nil {
+		h.presenceHandler(userID, online)
+	}
+}
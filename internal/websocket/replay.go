@@ -0,0 +1,274 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// bufferTimeout は通知のバッファへの保存・読み出しを待つ時間の上限
+const bufferTimeout = 2 * time.Second
+
+// BufferedEvent はバッファに保存された通知
+type BufferedEvent struct {
+	// ユーザーごとの連番
+	Seq int64
+
+	// 連番を付ける前のJSON形式のメッセージ
+	Payload []byte
+}
+
+// EventBuffer は再接続したクライアントに再送するため、ユーザーへの通知を連番を付けて短期間保存する
+// 古い通知は保存期間と件数の上限を超えると削除される
+type EventBuffer interface {
+	// Append は通知を保存し、ユーザーごとの次の連番を返す
+	Append(ctx context.Context, userID uuid.UUID, payload []byte) (int64, error)
+
+	// Since は連番がafterSeqより後の、保存されている通知を古い順に返す（最後に付けた連番も返す）
+	Since(ctx context.Context, userID uuid.UUID, afterSeq int64) ([]BufferedEvent, int64, error)
+
+	// Ack はユーザーが受信を確認した連番を記録する（記録済みの連番より小さい場合は無視する）
+	Ack(ctx context.Context, userID uuid.UUID, seq int64) error
+
+	// Acked はユーザーが最後に受信を確認した連番を返す（記録がない場合は0）
+	Acked(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// sequencedMessage は連番を付けたメッセージ
+type sequencedMessage struct {
+	Seq  int64           `json:"seq"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// SetEventBuffer は通知を再送するためのバッファを設定する（Runの前に呼ぶ）
+// 設定しない場合、通知には連番を付けず、切断中の通知は再送されない
+func (h *Hub) SetEventBuffer(buffer EventBuffer) {
+	h.buffer = buffer
+}
+
+// DeliverNotification はユーザーへの通知に連番を付けてバッファに保存してから送信する
+// 切断中のユーザーも、再接続後にresumeを送信するとバッファに残っている通知を受け取れる
+// バッファへの保存に失敗した場合は送信せずにエラーを返す（呼び出し元が再試行する）
+func (h *Hub) DeliverNotification(userID uuid.UUID, message *WebSocketMessage) error {
+	if h.buffer == nil {
+		return h.NotifyUser(userID, message)
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bufferTimeout)
+	defer cancel()
+	seq, err := h.buffer.Append(ctx, userID, payload)
+	if err != nil {
+		return err
+	}
+
+	payload, err = withSeq(payload, seq)
+	if err != nil {
+		return err
+	}
+	h.notifyLocal(userID, payload)
+	h.publish(brokerNotify, []uuid.UUID{userID}, payload)
+	return nil
+}
+
+// withSeq はJSON形式のメッセージに連番を付ける
+func withSeq(payload []byte, seq int64) ([]byte, error) {
+	var message sequencedMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return nil, err
+	}
+	message.Seq = seq
+	return json.Marshal(message)
+}
+
+// handleAck はクライアントが受信を確認した連番を記録する（ReadPumpから呼ぶ）
+func (c *Client) handleAck(seq int64) {
+	if c.hub.buffer == nil || seq <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bufferTimeout)
+	defer cancel()
+	if err := c.hub.buffer.Ack(ctx, c.ID, seq); err != nil {
+		c.log.Warn("通知の受信確認の記録に失敗しました", "user_id", c.ID, "seq", seq, "error", err)
+	}
+}
+
+// handleResume はlastSeqより後の、バッファに残っている通知をクライアントに再送する（ReadPumpから呼ぶ）
+// 再送の後にreplayedメッセージを送り、バッファから削除済みの通知があった場合はgapをtrueにする
+// 再送と並行して届いた通知と重複する場合があるため、クライアントは連番で重複を取り除く
+func (c *Client) handleResume(id string, lastSeq *int64) {
+	if c.hub.buffer == nil {
+		c.hub.sendToClient(c, errorReply(id, ErrorCodeUnavailable, "replay is not available"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bufferTimeout)
+	defer cancel()
+
+	var afterSeq int64
+	if lastSeq != nil {
+		afterSeq = *lastSeq
+	} else {
+		acked, err := c.hub.buffer.Acked(ctx, c.ID)
+		if err != nil {
+			c.log.Warn("通知の再送に失敗しました", "user_id", c.ID, "error", err)
+			c.hub.sendToClient(c, errorReply(id, ErrorCodeInternal, "failed to replay notifications"))
+			return
+		}
+		afterSeq = acked
+	}
+
+	events, latestSeq, err := c.hub.buffer.Since(ctx, c.ID, afterSeq)
+	if err != nil {
+		c.log.Warn("通知の再送に失敗しました", "user_id", c.ID, "error", err)
+		c.hub.sendToClient(c, errorReply(id, ErrorCodeInternal, "failed to replay notifications"))
+		return
+	}
+
+	payloads := make([][]byte, 0, len(events)+1)
+	for _, event := range events {
+		payload, err := withSeq(event.Payload, event.Seq)
+		if err != nil {
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+
+	// 削除済みの通知がある場合や、連番がサーバー側で初期化された場合（afterSeqが最後の連番より大きい）は、
+	// クライアントは通知一覧APIで取得し直し、last_seqを最後の連番に置き換える
+	gap := afterSeq > latestSeq
+	if len(events) > 0 {
+		gap = gap || events[0].Seq > afterSeq+1
+	} else {
+		gap = gap || latestSeq > afterSeq
+	}
+
+	replayed, err := json.Marshal(NewReplayedMessage(latestSeq, len(payloads), gap))
+	if err != nil {
+		return
+	}
+	c.hub.sendToClient(c, append(payloads, replayed)...)
+}
+
+// memoryEventBuffer はプロセス内のメモリに通知を保存するEventBuffer（単一のインスタンスで動かす場合に使う）
+type memoryEventBuffer struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	maxEvents int
+	users     map[uuid.UUID]*memoryUserEvents
+}
+
+// memoryUserEvents はユーザーごとの連番と保存中の通知
+type memoryUserEvents struct {
+	seq    int64
+	acked  int64
+	events []memoryEvent
+}
+
+// memoryEvent は保存期限付きの通知
+type memoryEvent struct {
+	BufferedEvent
+	expiresAt time.Time
+}
+
+// NewMemoryEventBuffer は通知をttlの間、ユーザーごとに最大maxEvents件までメモリに保存するEventBufferを作成する
+// 連番はプロセスの再起動で初期化される
+func NewMemoryEventBuffer(ttl time.Duration, maxEvents int) EventBuffer {
+	ttl, maxEvents = bufferLimits(ttl, maxEvents)
+	return &memoryEventBuffer{
+		ttl:       ttl,
+		maxEvents: maxEvents,
+		users:     make(map[uuid.UUID]*memoryUserEvents),
+	}
+}
+
+func (b *memoryEventBuffer) Append(_ context.Context, userID uuid.UUID, payload []byte) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	user := b.users[userID]
+	if user == nil {
+		user = &memoryUserEvents{}
+		b.users[userID] = user
+	}
+
+	now := time.Now()
+	user.seq++
+	user.events = append(b.unexpired(user.events, now), memoryEvent{
+		BufferedEvent: BufferedEvent{Seq: user.seq, Payload: payload},
+		expiresAt:     now.Add(b.ttl),
+	})
+	if len(user.events) > b.maxEvents {
+		user.events = user.events[len(user.events)-b.maxEvents:]
+	}
+	return user.seq, nil
+}
+
+func (b *memoryEventBuffer) Since(_ context.Context, userID uuid.UUID, afterSeq int64) ([]BufferedEvent, int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	user := b.users[userID]
+	if user == nil {
+		return nil, 0, nil
+	}
+
+	user.events = b.unexpired(user.events, time.Now())
+	var events []BufferedEvent
+	for _, event := range user.events {
+		if event.Seq > afterSeq {
+			events = append(events, event.BufferedEvent)
+		}
+	}
+	return events, user.seq, nil
+}
+
+func (b *memoryEventBuffer) Ack(_ context.Context, userID uuid.UUID, seq int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if user := b.users[userID]; user != nil && seq > user.acked {
+		user.acked = seq
+	}
+	return nil
+}
+
+func (b *memoryEventBuffer) Acked(_ context.Context, userID uuid.UUID) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if user := b.users[userID]; user != nil {
+		return user.acked, nil
+	}
+	return 0, nil
+}
+
+// bufferLimits は通知の保存期間と件数の上限が設定されていない場合の値を返す
+func bufferLimits(ttl time.Duration, maxEvents int) (time.Duration, int) {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	if maxEvents <= 0 {
+		maxEvents = 100
+	}
+	return ttl, maxEvents
+}
+
+// unexpired は保存期限を過ぎていない通知を返す
+func (b *memoryEventBuffer) unexpired(events []memoryEvent, now time.Time) []memoryEvent {
+	for i, event := range events {
+		if event.expiresAt.After(now) {
+			return events[i:]
+		}
+	}
+	return events[:0]
+}
@@ -0,0 +1,234 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appendEvents はユーザーにcount件の通知を保存する
+func appendEvents(t *testing.T, buffer EventBuffer, userID uuid.UUID, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		_, err := buffer.Append(context.Background(), userID, []byte(`{"type":"notification","data":{}}`))
+		require.NoError(t, err)
+	}
+}
+
+// eventSeqs は通知の連番を返す
+func eventSeqs(events []BufferedEvent) []int64 {
+	seqs := []int64{}
+	for _, event := range events {
+		seqs = append(seqs, event.Seq)
+	}
+	return seqs
+}
+
+func TestMemoryEventBufferSince(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxEvents int
+		appended  int
+		afterSeq  int64
+		expected  []int64
+		latestSeq int64
+	}{
+		{"Empty", 10, 0, 0, []int64{}, 0},
+		{"All", 10, 3, 0, []int64{1, 2, 3}, 3},
+		{"AfterSeq", 10, 5, 2, []int64{3, 4, 5}, 5},
+		{"CaughtUp", 10, 3, 3, []int64{}, 3},
+		// 最後の連番より大きいafterSeqでは何も返さない
+		{"AheadOfLatest", 10, 3, 7, []int64{}, 3},
+		// 件数の上限を超えた古い通知は削除される
+		{"TrimmedToMaxEvents", 3, 5, 0, []int64{3, 4, 5}, 5},
+		{"TrimmedAfterSeq", 3, 5, 3, []int64{4, 5}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewMemoryEventBuffer(time.Minute, tt.maxEvents)
+			userID := uuid.New()
+			appendEvents(t, buffer, userID, tt.appended)
+
+			events, latestSeq, err := buffer.Since(context.Background(), userID, tt.afterSeq)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, eventSeqs(events))
+			assert.Equal(t, tt.latestSeq, latestSeq)
+		})
+	}
+}
+
+func TestMemoryEventBufferAppend(t *testing.T) {
+	buffer := NewMemoryEventBuffer(time.Minute, 2)
+	first, second := uuid.New(), uuid.New()
+
+	// 連番はユーザーごとに1から付けられ、削除された通知があっても続けて付けられる
+	for i, userID := range []uuid.UUID{first, first, second, first} {
+		seq, err := buffer.Append(context.Background(), userID, []byte(`{}`))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 1, 3}[i], seq)
+	}
+
+	events, _, err := buffer.Since(context.Background(), first, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, eventSeqs(events))
+	assert.Equal(t, []byte(`{}`), events[0].Payload)
+}
+
+func TestMemoryEventBufferAck(t *testing.T) {
+	tests := []struct {
+		name     string
+		appended int
+		acks     []int64
+		expected int64
+	}{
+		{"NoAck", 3, nil, 0},
+		{"Ack", 3, []int64{2}, 2},
+		{"Latest", 3, []int64{1, 3}, 3},
+		// 記録済みより小さい連番は無視する
+		{"IgnoresOlder", 3, []int64{3, 1}, 3},
+		// 通知を受け取っていないユーザーの確認は記録しない
+		{"UnknownUser", 0, []int64{2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewMemoryEventBuffer(time.Minute, 10)
+			userID := uuid.New()
+			appendEvents(t, buffer, userID, tt.appended)
+
+			for _, seq := range tt.acks {
+				require.NoError(t, buffer.Ack(context.Background(), userID, seq))
+			}
+
+			acked, err := buffer.Acked(context.Background(), userID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, acked)
+		})
+	}
+}
+
+func TestMemoryEventBufferUnexpired(t *testing.T) {
+	now := time.Now()
+	event := func(seq int64, expiresIn time.Duration) memoryEvent {
+		return memoryEvent{BufferedEvent: BufferedEvent{Seq: seq}, expiresAt: now.Add(expiresIn)}
+	}
+
+	tests := []struct {
+		name     string
+		events   []memoryEvent
+		expected []int64
+	}{
+		{"Empty", nil, []int64{}},
+		{"AllValid", []memoryEvent{event(1, time.Second), event(2, time.Minute)}, []int64{1, 2}},
+		{"SomeExpired", []memoryEvent{event(1, -time.Minute), event(2, -time.Second), event(3, time.Second)}, []int64{3}},
+		// 保存期限ちょうどの通知は期限切れとして扱う
+		{"ExpiresNow", []memoryEvent{event(1, 0), event(2, time.Second)}, []int64{2}},
+		{"AllExpired", []memoryEvent{event(1, -time.Minute), event(2, -time.Second)}, []int64{}},
+	}
+
+	buffer := &memoryEventBuffer{ttl: time.Minute, maxEvents: 10}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seqs := []int64{}
+			for _, event := range buffer.unexpired(tt.events, now) {
+				seqs = append(seqs, event.Seq)
+			}
+			assert.Equal(t, tt.expected, seqs)
+		})
+	}
+}
+
+func TestMemoryEventBufferSinceSkipsExpired(t *testing.T) {
+	buffer := NewMemoryEventBuffer(time.Minute, 10).(*memoryEventBuffer)
+	userID := uuid.New()
+	appendEvents(t, buffer, userID, 3)
+
+	// 最初の2件の保存期限を過ぎたことにする
+	for i := range buffer.users[userID].events[:2] {
+		buffer.users[userID].events[i].expiresAt = time.Now().Add(-time.Second)
+	}
+
+	events, latestSeq, err := buffer.Since(context.Background(), userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, eventSeqs(events))
+	assert.Equal(t, int64(3), latestSeq)
+}
+
+// replayedData はreplayedメッセージの内容
+type replayedData struct {
+	LastSeq int64 `json:"last_seq"`
+	Count   int   `json:"count"`
+	Gap     bool  `json:"gap"`
+}
+
+func TestHandleResume(t *testing.T) {
+	log, err := logger.NewLogger("error", "json")
+	require.NoError(t, err)
+
+	seq := func(value int64) *int64 { return &value }
+
+	tests := []struct {
+		name      string
+		maxEvents int
+		appended  int
+		acked     int64
+		lastSeq   *int64
+		replayed  []int64
+		latestSeq int64
+		gap       bool
+	}{
+		{"FromStart", 10, 3, 0, seq(0), []int64{1, 2, 3}, 3, false},
+		{"FromLastSeq", 10, 5, 0, seq(2), []int64{3, 4, 5}, 5, false},
+		{"CaughtUp", 10, 3, 0, seq(3), []int64{}, 3, false},
+		// last_seqを省略した場合は受信を確認した連番の後から再送する
+		{"FromAcked", 10, 5, 3, nil, []int64{4, 5}, 5, false},
+		// 削除済みの通知がある場合は再送できる通知を送ってgapを知らせる
+		{"Trimmed", 2, 5, 0, seq(1), []int64{4, 5}, 5, true},
+		// 連番がサーバー側で初期化された場合（last_seqが最後の連番より大きい）
+		{"AheadOfLatest", 10, 2, 0, seq(7), []int64{}, 2, true},
+		{"AheadOfEmptyBuffer", 10, 0, 0, seq(4), []int64{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewMemoryEventBuffer(time.Minute, tt.maxEvents)
+			hub := NewHub(log)
+			hub.SetEventBuffer(buffer)
+			go hub.Run()
+
+			userID := uuid.New()
+			appendEvents(t, buffer, userID, tt.appended)
+			if tt.acked > 0 {
+				require.NoError(t, buffer.Ack(context.Background(), userID, tt.acked))
+			}
+
+			client := NewClient(hub, nil, userID, log)
+			hub.Register(client)
+			client.handleResume("1", tt.lastSeq)
+
+			// 再送した通知の後にreplayedメッセージが届く
+			replayed := []int64{}
+			for range tt.replayed {
+				var message sequencedMessage
+				require.NoError(t, json.Unmarshal(<-client.send, &message))
+				replayed = append(replayed, message.Seq)
+			}
+			assert.Equal(t, tt.replayed, replayed)
+
+			var message struct {
+				Type string       `json:"type"`
+				Data replayedData `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(<-client.send, &message))
+			assert.Equal(t, string(EventTypeReplayed), message.Type)
+			assert.Equal(t, replayedData{LastSeq: tt.latestSeq, Count: len(tt.replayed), Gap: tt.gap}, message.Data)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// goingAwayMessage はサーバーの停止時に送るクローズフレームの内容
+var goingAwayMessage = websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+
+// Shutdown はすべてのクライアントにクローズフレームを送って切断し、接続が閉じられてハブのループが終了するまで待つ
+// 送信待ちのメッセージはクローズフレームの前に送信する
+// 終了後の通知・ブロードキャストは破棄される
+// ctxの期限までに終了しなかった場合はctxのエラーを返す
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.shutdownOnce.Do(func() {
+		close(h.shutdown)
+	})
+
+	select {
+	case <-h.done:
+		h.log.Info("WebSocketのハブを停止しました")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeAll はすべてのクライアントの切断を開始する（ハブのループから呼ぶ）
+func (h *Hub) closeAll() {
+	h.closing = make(map[*Client]bool, len(h.clients))
+	for client := range h.clients {
+		h.closeForShutdown(client)
+	}
+	h.clients = make(map[*Client]bool)
+	h.streams = make(map[string]map[*Client]bool)
+
+	h.userMutex.Lock()
+	userClients := h.userClients
+	h.userClients = make(map[uuid.UUID][]*Client)
+	h.userMutex.Unlock()
+
+	for userID := range userClients {
+		h.presenceChanged(userID, false)
+	}
+
+	h.log.Info("WebSocketクライアントを切断しています", "client_count", len(h.closing))
+}
+
+// closeForShutdown はクライアントにクローズフレームを送って切断し、接続が閉じられるのを待つ対象にする（ハブのループから呼ぶ）
+func (h *Hub) closeForShutdown(client *Client) {
+	client.closeMessage = goingAwayMessage
+	close(client.send)
+	h.closing[client] = true
+}
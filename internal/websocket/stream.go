@@ -0,0 +1,314 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/TakuyaAizawa/gox/internal/domain/models"
+	"github.com/google/uuid"
+)
+
+// Stream はクライアントが購読できる投稿のストリームの種類を表す
+type Stream string
+
+const (
+	// StreamHome は自分のホームタイムライン（フォローしているユーザーと自分の新着投稿）
+	StreamHome Stream = "home"
+
+	// StreamReplies は指定した投稿への返信
+	StreamReplies Stream = "replies"
+
+	// StreamHashtag は指定したハッシュタグを含む投稿
+	StreamHashtag Stream = "hashtag"
+)
+
+// 1つのクライアントが同時に購読できるストリームの最大数
+const maxClientStreams = 50
+
+// Subscription はストリームの購読の指定を表す（クライアントからの要求と、サーバーからの応答の両方で使う）
+type Subscription struct {
+	// ストリームの種類
+	Stream Stream `json:"stream"`
+
+	// 返信を購読する投稿のID（repliesの場合）
+	PostID *uuid.UUID `json:"post_id,omitempty"`
+
+	// 購読するハッシュタグ（hashtagの場合、先頭の#は付けない）
+	Tag string `json:"tag,omitempty"`
+}
+
+// subscriptionRequest はクライアントのストリームの購読・購読解除のリクエスト
+type subscriptionRequest struct {
+	client    *Client
+	id        string
+	key       string
+	subscribe bool
+
+	// クライアントに送信する応答
+	reply []byte
+}
+
+// streamMessage はストリームの購読者への配信リクエスト
+type streamMessage struct {
+	// 配信先のストリームのキー
+	keys []string
+
+	// 配信する投稿の投稿者（uuid.Nilの場合は配信先を絞り込まない）
+	authorID uuid.UUID
+
+	// JSON形式のメッセージ
+	payload []byte
+}
+
+// key はハブがストリームを識別するキーを返す（ホームタイムラインはユーザーごとのストリームとなる）
+func (s Subscription) key(userID uuid.UUID) (string, error) {
+	switch s.Stream {
+	case StreamHome:
+		return HomeStreamKey(userID), nil
+	case StreamReplies:
+		if s.PostID == nil {
+			return "", errors.New("post_id is required for the replies stream")
+		}
+		return RepliesStreamKey(*s.PostID), nil
+	case StreamHashtag:
+		tag, ok := models.NormalizeHashtag(s.Tag)
+		if !ok {
+			return "", errors.New("invalid tag for the hashtag stream")
+		}
+		return HashtagStreamKey(tag), nil
+	}
+	return "", errors.New("unknown stream")
+}
+
+// HomeStreamKey はユーザーのホームタイムラインのストリームのキーを返す
+func HomeStreamKey(userID uuid.UUID) string {
+	return "home:" + userID.String()
+}
+
+// RepliesStreamKey は投稿への返信のストリームのキーを返す
+func RepliesStreamKey(postID uuid.UUID) string {
+	return "replies:" + postID.String()
+}
+
+// HashtagStreamKey はハッシュタグのストリームのキーを返す（tagは小文字にそろえたもの）
+func HashtagStreamKey(tag string) string {
+	return "hashtag:" + tag
+}
+
+// StreamAuthorizer はユーザーがストリームを購読できるかを確認する関数（ReadPumpから呼び出される）
+// 購読できない場合はCommandErrorを返す。それ以外のエラーはinternal_errorとして返す
+type StreamAuthorizer func(ctx context.Context, userID uuid.UUID, subscription Subscription) error
+
+// StreamFilter はストリームの購読者のうち、authorIDの投稿を配信してよいユーザーを返す関数
+// ハブのループの外で呼び出される。エラーを返した場合はこのインスタンスの購読者に配信しない
+type StreamFilter func(ctx context.Context, authorID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+
+// SetStreamAuthorizer はストリームの購読を許可するかを確認する関数を設定する（接続を受け付ける前に呼ぶ）
+func (h *Hub) SetStreamAuthorizer(authorizer StreamAuthorizer) {
+	h.streamHookMutex.Lock()
+	defer h.streamHookMutex.Unlock()
+	h.streamAuthorizer = authorizer
+}
+
+// SetStreamFilter はストリームの配信先を投稿者ごとに絞り込む関数を設定する（接続を受け付ける前に呼ぶ）
+func (h *Hub) SetStreamFilter(filter StreamFilter) {
+	h.streamHookMutex.Lock()
+	defer h.streamHookMutex.Unlock()
+	h.streamFilter = filter
+}
+
+// streamHooks は設定されたストリームの購読の確認と配信先の絞り込みの関数を返す
+func (h *Hub) streamHooks() (StreamAuthorizer, StreamFilter) {
+	h.streamHookMutex.RLock()
+	defer h.streamHookMutex.RUnlock()
+	return h.streamAuthorizer, h.streamFilter
+}
+
+// handleSubscription はクライアントのストリームの購読・購読解除の要求を処理する（ReadPumpから呼ぶ）
+func (c *Client) handleSubscription(id string, subscription Subscription, subscribe bool) {
+	key, err := subscription.key(c.ID)
+	if err != nil {
+		c.hub.sendToClient(c, errorReply(id, ErrorCodeInvalidParams, err.Error()))
+		return
+	}
+
+	// 購読の解除は確認せずに受け付ける
+	if authorize, _ := c.hub.streamHooks(); subscribe && authorize != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		err := authorize(ctx, c.ID, subscription)
+		cancel()
+		if err != nil {
+			c.hub.sendToClient(c, c.commandErrorReply(id, err))
+			return
+		}
+	}
+
+	if subscription.Stream == StreamHashtag {
+		subscription.Tag, _ = models.NormalizeHashtag(subscription.Tag)
+	}
+	reply, err := json.Marshal(NewSubscriptionMessage(subscribe, subscription))
+	if err != nil {
+		return
+	}
+
+	c.hub.requestSubscription(subscriptionRequest{
+		client:    c,
+		id:        id,
+		key:       key,
+		subscribe: subscribe,
+		reply:     reply,
+	})
+}
+
+// requestSubscription はハブのループに購読の変更を依頼する
+func (h *Hub) requestSubscription(req subscriptionRequest) {
+	select {
+	case h.subscriptions <- req:
+	case <-h.done:
+	}
+}
+
+// PublishStreams はストリームを購読しているクライアントにメッセージを送信する（ブローカーを設定した場合は他のインスタンスのクライアントにも送信する）
+// authorIDはメッセージの投稿の投稿者で、SetStreamFilterで設定した関数で配信先を絞り込む（uuid.Nilの場合は絞り込まない）
+func (h *Hub) PublishStreams(keys []string, authorID uuid.UUID, message interface{}) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	h.publishStreamsLocal(keys, authorID, payload)
+	h.publishStreams(keys, authorID, payload)
+	return nil
+}
+
+// publishStreamsLocal はこのインスタンスのストリームの購読者にメッセージを送信する
+func (h *Hub) publishStreamsLocal(keys []string, authorID uuid.UUID, payload []byte) {
+	select {
+	case h.streamPublish <- &streamMessage{keys: keys, authorID: authorID, payload: payload}:
+	case <-h.done:
+	}
+}
+
+// updateSubscription はクライアントのストリームの購読を変更し、応答を送信する（ハブのループから呼ぶ）
+func (h *Hub) updateSubscription(req subscriptionRequest) {
+	client := req.client
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+
+	reply := req.reply
+	if req.subscribe {
+		if !client.streams[req.key] && len(client.streams) >= maxClientStreams {
+			reply = errorReply(req.id, ErrorCodeLimitExceeded, "too many subscriptions")
+		} else {
+			h.addSubscription(client, req.key)
+		}
+	} else {
+		h.removeSubscription(client, req.key)
+	}
+
+	h.sendReplies(client, [][]byte{reply})
+}
+
+// addSubscription はクライアントをストリームの購読者に加える（ハブのループから呼ぶ）
+func (h *Hub) addSubscription(client *Client, key string) {
+	if client.streams == nil {
+		client.streams = make(map[string]bool)
+	}
+	client.streams[key] = true
+
+	subscribers := h.streams[key]
+	if subscribers == nil {
+		subscribers = make(map[*Client]bool)
+		h.streams[key] = subscribers
+	}
+	subscribers[client] = true
+}
+
+// removeSubscription はクライアントをストリームの購読者から外す（ハブのループから呼ぶ）
+func (h *Hub) removeSubscription(client *Client, key string) {
+	delete(client.streams, key)
+
+	subscribers := h.streams[key]
+	delete(subscribers, client)
+	if len(subscribers) == 0 {
+		delete(h.streams, key)
+	}
+}
+
+// removeAllSubscriptions は切断するクライアントのすべての購読を解除する（ハブのループから呼ぶ）
+func (h *Hub) removeAllSubscriptions(client *Client) {
+	for key := range client.streams {
+		h.removeSubscription(client, key)
+	}
+}
+
+// sendStream はストリームの購読者にメッセージを送信する（ハブのループから呼ぶ）
+// 複数のストリームを購読しているクライアントにも1回だけ送信する
+// 配信先を絞り込む関数を設定した場合は、ハブのループを止めないよう別のgoroutineで絞り込んでから送信する
+func (h *Hub) sendStream(message *streamMessage) {
+	sent := make(map[*Client]bool)
+	var clients []*Client
+	for _, key := range message.keys {
+		for client := range h.streams[key] {
+			if sent[client] {
+				continue
+			}
+			sent[client] = true
+			clients = append(clients, client)
+		}
+	}
+	if len(clients) == 0 {
+		return
+	}
+
+	if _, filter := h.streamHooks(); filter != nil && message.authorID != uuid.Nil {
+		go h.sendFilteredStream(filter, message, clients)
+		return
+	}
+
+	for _, client := range clients {
+		select {
+		case client.send <- message.payload:
+		default:
+			// バッファがいっぱいの場合はこのクライアントをスキップ
+			h.log.Warn("ストリーム送信失敗: バッファがいっぱい", "user_id", client.ID)
+		}
+	}
+}
+
+// sendFilteredStream は配信してよいユーザーのクライアントにのみストリームのメッセージを送信する
+// 送信はハブのループを通して行うため、その間に切断したクライアントには送信しない
+func (h *Hub) sendFilteredStream(filter StreamFilter, message *streamMessage, clients []*Client) {
+	seen := make(map[uuid.UUID]bool, len(clients))
+	userIDs := make([]uuid.UUID, 0, len(clients))
+	for _, client := range clients {
+		if !seen[client.ID] {
+			seen[client.ID] = true
+			userIDs = append(userIDs, client.ID)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	allowedIDs, err := filter(ctx, message.authorID, userIDs)
+	cancel()
+	if err != nil {
+		h.log.Warn("ストリームの配信先の絞り込みに失敗しました", "author_id", message.authorID, "error", err)
+		return
+	}
+
+	allowed := make(map[uuid.UUID]bool, len(allowedIDs))
+	for _, userID := range allowedIDs {
+		allowed[userID] = true
+	}
+	for _, client := range clients {
+		if allowed[client.ID] {
+			h.sendToClient(client, message.payload)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package websocket
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// UpgradeFailureReason WebSocketへのアップグレードが失敗した理由
+type UpgradeFailureReason string
+
+const (
+	// UpgradeFailureAuthMissing Authorizationヘッダーがない（クライアントの実装漏れであることが多い）
+	UpgradeFailureAuthMissing UpgradeFailureReason = "auth_missing"
+	// UpgradeFailureAuthInvalid トークンの形式が不正、または検証に失敗した（期限切れや改ざんを含む）
+	UpgradeFailureAuthInvalid UpgradeFailureReason = "auth_invalid"
+	// UpgradeFailureOriginRejected 許可されていないオリジンからの接続
+	UpgradeFailureOriginRejected UpgradeFailureReason = "origin_rejected"
+	// UpgradeFailureHandshake WebSocketのハンドシェイクとして不正なリクエスト（Upgradeヘッダーの欠落など）
+	UpgradeFailureHandshake UpgradeFailureReason = "handshake"
+	// UpgradeFailureUpgradeError 接続の乗っ取りに失敗したなど、サーバー側でアップグレードできなかった
+	UpgradeFailureUpgradeError UpgradeFailureReason = "upgrade_error"
+	// UpgradeFailureDraining インスタンスがドレイン中のため接続を受け付けなかった（クライアントは再試行する）
+	UpgradeFailureDraining UpgradeFailureReason = "draining"
+)
+
+// UpgradeMetrics ルートごとのWebSocketアップグレードの成功数と理由別の失敗数を集計する
+type UpgradeMetrics struct {
+	mu     sync.Mutex
+	since  time.Time
+	routes map[string]*upgradeRouteMetrics
+}
+
+// upgradeRouteMetrics 1つのルートの集計
+type upgradeRouteMetrics struct {
+	succeeded     int64
+	failed        map[UpgradeFailureReason]int64
+	lastFailureAt time.Time
+}
+
+// UpgradeRouteSnapshot ルートごとの集計結果
+type UpgradeRouteSnapshot struct {
+	Route         string                         `json:"route"`
+	Succeeded     int64                          `json:"succeeded"`
+	Failed        map[UpgradeFailureReason]int64 `json:"failed"`
+	LastFailureAt *time.Time                     `json:"last_failure_at"`
+}
+
+// NewUpgradeMetrics 新しいアップグレードの集計を作成する
+func NewUpgradeMetrics() *UpgradeMetrics {
+	return &UpgradeMetrics{
+		since:  time.Now().UTC(),
+		routes: make(map[string]*upgradeRouteMetrics),
+	}
+}
+
+// RecordSuccess アップグレードの成功を記録する
+func (m *UpgradeMetrics) RecordSuccess(route string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.route(route).succeeded++
+}
+
+// RecordFailure アップグレードの失敗を理由とともに記録する
+func (m *UpgradeMetrics) RecordFailure(route string, reason UpgradeFailureReason) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics := m.route(route)
+	metrics.failed[reason]++
+	metrics.lastFailureAt = time.Now().UTC()
+}
+
+// Snapshot 集計開始日時とルートごとの集計結果（ルート順）を返す
+func (m *UpgradeMetrics) Snapshot() (time.Time, []UpgradeRouteSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshots := make([]UpgradeRouteSnapshot, 0, len(m.routes))
+	for route, metrics := range m.routes {
+		failed := make(map[UpgradeFailureReason]int64, len(metrics.failed))
+		for reason, count := range metrics.failed {
+			failed[reason] = count
+		}
+		snapshot := UpgradeRouteSnapshot{
+			Route:     route,
+			Succeeded: metrics.succeeded,
+			Failed:    failed,
+		}
+		if !metrics.lastFailureAt.IsZero() {
+			lastFailureAt := metrics.lastFailureAt
+			snapshot.LastFailureAt = &lastFailureAt
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Route < snapshots[j].Route
+	})
+
+	return m.since, snapshots
+}
+
+// route ルートの集計を取得する（なければ作成する）。呼び出し側でロックを取得すること
+func (m *UpgradeMetrics) route(route string) *upgradeRouteMetrics {
+	metrics, ok := m.routes[route]
+	if !ok {
+		metrics = &upgradeRouteMetrics{failed: make(map[UpgradeFailureReason]int64)}
+		m.routes[route] = metrics
+	}
+	return metrics
+}
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_posts_content_rating;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS content_rating;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS is_age_verified,
+    DROP COLUMN IF EXISTS birth_date,
+    DROP COLUMN IF EXISTS country_code;
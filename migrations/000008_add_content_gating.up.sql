@@ -0,0 +1,10 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS is_age_verified BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS birth_date DATE,
+    ADD COLUMN IF NOT EXISTS country_code VARCHAR(2) NOT NULL DEFAULT '';
+
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS content_rating VARCHAR(20) NOT NULL DEFAULT 'general'
+        CHECK (content_rating IN ('general', 'sensitive', 'adult'));
+
+CREATE INDEX idx_posts_content_rating ON posts(content_rating) WHERE content_rating <> 'general';
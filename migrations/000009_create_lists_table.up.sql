@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS lists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(25) NOT NULL,
+    description VARCHAR(100) NOT NULL DEFAULT '',
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    member_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_lists_owner_id ON lists(owner_id);
+
+CREATE TABLE IF NOT EXISTS list_members (
+    list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX idx_list_members_user_id ON list_members(user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS online_migration_progress;
+DROP TABLE IF EXISTS feature_flags;
@@ -0,0 +1,2 @@
+-- タイムラインの新着件数カウント（user_id ごとに created_at で範囲検索）用
+CREATE INDEX IF NOT EXISTS idx_posts_user_id_created_at ON posts(user_id, created_at DESC);
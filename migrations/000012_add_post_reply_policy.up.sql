@@ -0,0 +1,3 @@
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS reply_policy VARCHAR(20) NOT NULL DEFAULT 'everyone'
+        CHECK (reply_policy IN ('everyone', 'followers', 'following'));
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS conversation_mutes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    root_post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, root_post_id)
+);
+
+CREATE INDEX idx_conversation_mutes_root_post_id ON conversation_mutes(root_post_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS post_daily_views;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS view_count;
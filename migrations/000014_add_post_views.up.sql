@@ -0,0 +1,10 @@
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+
+-- 投稿ごとの日別閲覧数（投稿者向けの分析で使用）
+CREATE TABLE IF NOT EXISTS post_daily_views (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    view_date DATE NOT NULL,
+    view_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, view_date)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_cohort_stats;
+DROP TABLE IF EXISTS user_activity_days;
@@ -0,0 +1,18 @@
+-- ユーザーが活動した日（リテンションの計算に使用）
+CREATE TABLE IF NOT EXISTS user_activity_days (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_date DATE NOT NULL,
+    PRIMARY KEY (user_id, activity_date)
+);
+
+CREATE INDEX idx_user_activity_days_activity_date ON user_activity_days(activity_date);
+
+-- 登録日ごとのコホート統計（毎日の集計ジョブで更新）
+CREATE TABLE IF NOT EXISTS user_cohort_stats (
+    cohort_date DATE PRIMARY KEY,
+    cohort_size INT NOT NULL DEFAULT 0,
+    d1_retained INT,
+    d7_retained INT,
+    d30_retained INT,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
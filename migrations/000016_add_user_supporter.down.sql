@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS supporter_events;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS supporter_until,
+    DROP COLUMN IF EXISTS supporter_tier;
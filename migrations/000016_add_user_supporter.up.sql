@@ -0,0 +1,11 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS supporter_tier VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS supporter_until TIMESTAMP WITH TIME ZONE;
+
+-- 決済サービスから受信したWebhookイベント（同じイベントを二重に処理しないために使用）
+CREATE TABLE IF NOT EXISTS supporter_events (
+    event_id VARCHAR(255) PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,23 @@
+-- ユーザーの最近の検索（ユーザーごとに同じ検索語は1件にまとめる）
+CREATE TABLE IF NOT EXISTS search_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query VARCHAR(100) NOT NULL,
+    searched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, query)
+);
+
+CREATE INDEX idx_search_history_user_id_searched_at ON search_history(user_id, searched_at DESC);
+
+-- 保存した検索（notifyが有効な場合は定期ジョブで新着投稿を確認する）
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query VARCHAR(100) NOT NULL,
+    notify BOOLEAN NOT NULL DEFAULT FALSE,
+    last_checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, query)
+);
+
+CREATE INDEX idx_saved_searches_notify ON saved_searches(last_checked_at) WHERE notify;
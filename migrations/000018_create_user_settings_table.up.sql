@@ -0,0 +1,7 @@
+-- ユーザーごとの設定（行がない場合はデフォルト値を使用する）
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    -- 探索タイムラインから除外するキーワード・ハッシュタグ（ホームタイムラインには影響しない）
+    explore_excluded_keywords TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
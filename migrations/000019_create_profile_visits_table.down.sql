@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS profile_visits;
+
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS profile_visitors_enabled;
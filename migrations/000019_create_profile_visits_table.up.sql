@@ -0,0 +1,15 @@
+-- プロフィール訪問者の表示（閲覧する側・される側の両方が有効にしている場合のみ記録する）
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS profile_visitors_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- プロフィールの最近の訪問者（同じ訪問者は1件にまとめ、最終訪問日時を更新する）
+CREATE TABLE IF NOT EXISTS profile_visits (
+    profile_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    visitor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    visited_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (profile_user_id, visitor_id)
+);
+
+CREATE INDEX idx_profile_visits_profile_user_id_visited_at ON profile_visits(profile_user_id, visited_at DESC);
+CREATE INDEX idx_profile_visits_visitor_id ON profile_visits(visitor_id);
+CREATE INDEX idx_profile_visits_visited_at ON profile_visits(visited_at);
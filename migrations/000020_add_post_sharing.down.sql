@@ -0,0 +1,3 @@
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS sharing_enabled,
+    DROP COLUMN IF EXISTS share_count;
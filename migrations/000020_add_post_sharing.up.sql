@@ -0,0 +1,4 @@
+-- 外部への共有数と、投稿者が共有を許可しているか（無効の場合は共有用のメタデータを返さない）
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS share_count BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS sharing_enabled BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,12 @@
+-- 投稿の編集履歴（編集前の本文と添付メディアを保持する）
+CREATE TABLE IF NOT EXISTS post_edits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    editor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    previous_content TEXT NOT NULL,
+    previous_media_urls JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_post_edits_post_id_created_at ON post_edits(post_id, created_at DESC);
@@ -0,0 +1,5 @@
+-- 運営が管理するシステムアカウント（@goxなど）。登録・ログイン・フォローはできず、投稿は全ユーザーのホームタイムラインに表示される
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS is_system BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_is_system ON users(id) WHERE is_system;
@@ -0,0 +1,5 @@
+-- 投稿の論理削除。削除された投稿は一覧や取得の対象から外れるが、返信のスレッドや通知から参照できるよう行は残す
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_deactivated;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deactivated_at,
+    DROP COLUMN IF EXISTS status;
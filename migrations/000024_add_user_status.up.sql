@@ -0,0 +1,8 @@
+-- アカウントの状態。無効化されたアカウントのプロフィール・投稿・フォロー関係はすべてのエンドポイントから隠し、
+-- 猶予期間内に再度ログインすると再開する
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'deactivated')),
+    ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_users_deactivated ON users(id) WHERE status = 'deactivated';
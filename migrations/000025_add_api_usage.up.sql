@@ -0,0 +1,16 @@
+-- トークン・クライアントアプリ・エンドポイントごとのAPI利用状況（1時間単位で集計）
+-- key_idはアクセストークンのハッシュの先頭で、トークン自体は保存しない
+CREATE TABLE IF NOT EXISTS api_usage_hourly (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app VARCHAR(50) NOT NULL,
+    key_id VARCHAR(16) NOT NULL,
+    endpoint VARCHAR(255) NOT NULL,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    request_bytes BIGINT NOT NULL DEFAULT 0,
+    response_bytes BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, hour, app, key_id, endpoint)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_usage_hourly_hour ON api_usage_hourly(hour);
+CREATE INDEX IF NOT EXISTS idx_api_usage_hourly_key ON api_usage_hourly(key_id, hour);
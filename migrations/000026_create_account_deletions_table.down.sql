@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS account_deletions;
+
+-- 削除手続き中だったアカウントは無効化された状態に戻す
+UPDATE users SET status = 'deactivated', deactivated_at = COALESCE(deactivated_at, NOW())
+WHERE status = 'deleting';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'deactivated'));
@@ -0,0 +1,23 @@
+-- 削除手続き中のアカウント。ユーザーを削除した後も進捗を確認できるよう、usersへの外部キーは設定しない
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'deactivated', 'deleting'));
+
+CREATE TABLE IF NOT EXISTS account_deletions (
+    user_id UUID PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    step VARCHAR(30) NOT NULL,
+    media_total INTEGER NOT NULL DEFAULT 0,
+    media_deleted INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletions_due ON account_deletions(next_attempt_at)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_users_username_lower;
+
+-- 停止されていたアカウントは無効化された状態に戻す
+UPDATE users SET status = 'deactivated', deactivated_at = COALESCE(deactivated_at, NOW())
+WHERE status = 'suspended';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'deactivated', 'deleting'));
@@ -0,0 +1,6 @@
+-- SCIMでIdPから無効化されたアカウント。本人のログインでは再開できず、IdPから有効化されるまですべてのエンドポイントから隠す
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'deactivated', 'deleting', 'suspended'));
+
+CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users(LOWER(username));
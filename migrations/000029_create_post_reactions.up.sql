@@ -0,0 +1,11 @@
+-- 投稿への絵文字リアクション。1人のユーザーは同じ投稿に複数の種類の絵文字でリアクションできる
+CREATE TABLE IF NOT EXISTS post_reactions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id, emoji)
+);
+
+-- 投稿一覧の絵文字ごとの集計に使用する（閲覧者のリアクションは主キーで取得する）
+CREATE INDEX IF NOT EXISTS idx_post_reactions_post_id_emoji ON post_reactions(post_id, emoji);
@@ -0,0 +1,3 @@
+-- クライアントの種類（web・ios・androidなど）ごとの通知の表示形式（grouped・flat）。設定のないクライアントはflatとして扱う
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS notification_grouping JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,12 @@
+-- 分析イベント（ANALYTICS_SINK=postgresの場合の送信先）
+-- 削除されたユーザー・投稿のイベントは集計のため残し、IDのみ消す
+CREATE TABLE IF NOT EXISTS analytics_events (
+    id UUID PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE SET NULL,
+    properties JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_events_type_occurred_at ON analytics_events(type, occurred_at);
@@ -0,0 +1,4 @@
+-- モデレーターの権限を追加する
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'moderator', 'admin'));
@@ -0,0 +1,21 @@
+-- フォロー・フォロー解除のイベント（追記のみで更新・削除しない）
+-- followsテーブルはこのイベントから作られる射影で、projected_atがNULLのイベントは射影ワーカーが反映する
+CREATE TABLE IF NOT EXISTS follow_events (
+    seq BIGSERIAL PRIMARY KEY,
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(10) NOT NULL CHECK (event_type IN ('follow', 'unfollow')),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    projected_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_follow_events_pair ON follow_events(follower_id, followee_id, seq);
+CREATE INDEX IF NOT EXISTS idx_follow_events_followee_occurred_at ON follow_events(followee_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_follow_events_occurred_at ON follow_events(occurred_at);
+CREATE INDEX IF NOT EXISTS idx_follow_events_unprojected ON follow_events(seq) WHERE projected_at IS NULL;
+
+-- 既存のフォロー関係をフォローイベントとして取り込む（フォローした日時はfollows.created_atを引き継ぐ）
+INSERT INTO follow_events (follower_id, followee_id, event_type, occurred_at, projected_at)
+SELECT follower_id, followee_id, 'follow', created_at, NOW()
+FROM follows
+ORDER BY created_at;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_posts_moderated_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS replies_locked,
+    DROP COLUMN IF EXISTS content_warning,
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS moderation_status;
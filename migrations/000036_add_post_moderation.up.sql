@@ -0,0 +1,13 @@
+-- モデレーターによる投稿の非表示・削除、注意書き（コンテンツ警告）、返信のロック
+-- hiddenは確認中などで一時的に隠した状態、removedは規約違反として削除した状態（どちらも元に戻せる）
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(10) NOT NULL DEFAULT 'visible'
+        CHECK (moderation_status IN ('visible', 'hidden', 'removed')),
+    ADD COLUMN IF NOT EXISTS moderation_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS content_warning VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS replies_locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- モデレーターが対応した投稿の一覧用
+CREATE INDEX IF NOT EXISTS idx_posts_moderated_at ON posts(moderated_at) WHERE moderation_status <> 'visible';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_reports_open_automatic_target;
+
+DELETE FROM reports WHERE reporter_id IS NULL;
+ALTER TABLE reports ALTER COLUMN reporter_id SET NOT NULL;
+
+DROP TABLE IF EXISTS content_filter_rules;
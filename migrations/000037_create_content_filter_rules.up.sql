@@ -0,0 +1,19 @@
+-- 投稿・プロフィールの内容を審査する禁止語（コンテンツフィルター）のルール
+-- actionはreject（投稿を拒否）、flag（通報キューに載せる）、label（ratingのレーティングを自動で付ける）
+-- patternは小文字に正規化して保存し、大文字・小文字を区別せずに部分一致で判定する
+CREATE TABLE IF NOT EXISTS content_filter_rules (
+    id UUID PRIMARY KEY,
+    pattern VARCHAR(100) NOT NULL UNIQUE,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('reject', 'flag', 'label')),
+    rating VARCHAR(20) NOT NULL DEFAULT 'general' CHECK (rating IN ('general', 'sensitive', 'adult')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- コンテンツフィルターによる自動の通報（通報者なし）
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
+
+-- 同じ対象への未対応の自動通報は1件だけにする
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_automatic_target
+    ON reports(target_type, target_id) WHERE status = 'open' AND reporter_id IS NULL;
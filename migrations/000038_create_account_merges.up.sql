@@ -0,0 +1,42 @@
+-- 統合元のアカウント。統合が終わるまでログインできず、すべてのエンドポイントから隠す
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'deactivated', 'deleting', 'suspended', 'merging'));
+
+-- 管理者が開始したアカウントの統合（重複して作成されたアカウントの投稿・フォロー・いいね・通知を統合先へ移す）
+-- 統合元のユーザーは最後に削除されるため、source_user_idには外部キーを設定しない
+CREATE TABLE IF NOT EXISTS account_merges (
+    id UUID PRIMARY KEY,
+    source_user_id UUID NOT NULL,
+    source_username VARCHAR(30) NOT NULL,
+    target_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    step VARCHAR(30) NOT NULL,
+    -- 手順ごとに移した件数と、重複のため統合先に移さずに削除した件数
+    moved JSONB NOT NULL DEFAULT '{}'::jsonb,
+    dropped JSONB NOT NULL DEFAULT '{}'::jsonb,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- 同じアカウントの統合は同時に1件だけ
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_merges_active_source
+    ON account_merges(source_user_id) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS idx_account_merges_due ON account_merges(next_attempt_at)
+    WHERE status IN ('pending', 'running');
+
+-- 統合元のユーザー名から統合先のアカウントへの転送（転送中のユーザー名は新しく登録できない）
+CREATE TABLE IF NOT EXISTS username_redirects (
+    username VARCHAR(30) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_redirects_user_id ON username_redirects(user_id);
@@ -0,0 +1,11 @@
+-- アップロードされたメディアの実体（内容のハッシュが同じファイルは1つだけ保存し、参照数で共有する）
+-- hashは内容のSHA-256（16進数）、ref_countが0になった時点でストレージのファイルと行を削除する
+CREATE TABLE IF NOT EXISTS media_objects (
+    id UUID PRIMARY KEY,
+    hash CHAR(64) NOT NULL UNIQUE,
+    url TEXT NOT NULL UNIQUE,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    ref_count INTEGER NOT NULL DEFAULT 1 CHECK (ref_count >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
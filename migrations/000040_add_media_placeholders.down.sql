@@ -0,0 +1,3 @@
+ALTER TABLE media_objects DROP COLUMN IF EXISTS height;
+ALTER TABLE media_objects DROP COLUMN IF EXISTS width;
+ALTER TABLE media_objects DROP COLUMN IF EXISTS blurhash;
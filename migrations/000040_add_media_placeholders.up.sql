@@ -0,0 +1,5 @@
+-- 画像の読み込み前にクライアントが表示するプレースホルダー（BlurHash）と画像の大きさ
+-- 画像として読み込めないファイルや、追加前にアップロードされたメディアは空のまま
+ALTER TABLE media_objects ADD COLUMN IF NOT EXISTS blurhash VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE media_objects ADD COLUMN IF NOT EXISTS width INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE media_objects ADD COLUMN IF NOT EXISTS height INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS language;
+ALTER TABLE posts DROP COLUMN IF EXISTS media_alt_texts;
@@ -0,0 +1,3 @@
+-- 添付メディアの代替テキスト（media_urlsと同じ順序で、代替テキストのないメディアは空文字列）と投稿の言語（BCP 47の言語タグ）
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS media_alt_texts JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS language VARCHAR(35) NOT NULL DEFAULT '';
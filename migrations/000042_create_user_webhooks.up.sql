@@ -0,0 +1,18 @@
+-- 個人用Webhook（ユーザー自身へのフォロー・メンションを登録したURLへ送信する）
+-- eventsは送信するイベントの配列、secretは送信内容の署名（HMAC-SHA256）に使う
+-- failure_countは連続して送信に失敗した回数で、設定された回数に達するとactiveをfalseにして送信を止める
+CREATE TABLE IF NOT EXISTS user_webhooks (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events JSONB NOT NULL DEFAULT '[]',
+    secret VARCHAR(64) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    last_status_code INTEGER,
+    last_delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_webhooks_user_id ON user_webhooks(user_id);
@@ -0,0 +1,5 @@
+-- 投稿の有効期限（消える投稿）。expires_atを過ぎた投稿はすべての読み取りから除かれ、定期的なジョブで削除される
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+-- 期限切れの投稿を削除するジョブ用（有効期限のある投稿のみ）
+CREATE INDEX IF NOT EXISTS idx_posts_expires_at ON posts(expires_at) WHERE expires_at IS NOT NULL;
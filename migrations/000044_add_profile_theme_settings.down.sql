@@ -0,0 +1,4 @@
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS pinned_hashtags;
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS profile_accent_color;
@@ -0,0 +1,7 @@
+-- プロフィールのカスタマイズ（クライアントが描画する見た目の設定）
+-- アクセントカラー（#rrggbb形式。空の場合はクライアントのデフォルト）
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS profile_accent_color VARCHAR(7) NOT NULL DEFAULT '';
+-- プロフィールに表示するハッシュタグ（#を除いた形、表示順）
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS pinned_hashtags TEXT[] NOT NULL DEFAULT '{}';
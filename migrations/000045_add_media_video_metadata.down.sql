@@ -0,0 +1,2 @@
+ALTER TABLE media_objects DROP COLUMN IF EXISTS thumbnail_url;
+ALTER TABLE media_objects DROP COLUMN IF EXISTS duration_ms;
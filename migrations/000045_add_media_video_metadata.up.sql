@@ -0,0 +1,4 @@
+-- 動画の再生時間（ミリ秒）とサムネイル画像のURL
+-- 動画以外のファイルや、サムネイルを生成できなかった動画は空のまま
+ALTER TABLE media_objects ADD COLUMN IF NOT EXISTS duration_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE media_objects ADD COLUMN IF NOT EXISTS thumbnail_url TEXT NOT NULL DEFAULT '';
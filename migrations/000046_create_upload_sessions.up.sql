@@ -0,0 +1,19 @@
+-- 分割アップロードのセッション（大きなファイルをパートに分けて送信し、すべて揃った時点でサーバー側で結合する）
+-- received_partsは受信済みのパート番号（0始まり）で、再開時にクライアントは不足しているパートのみを送信する
+-- 完了するとurlに保存先のURLを記録する。expires_atを過ぎたセッションはパートとともに削除される
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    total_size BIGINT NOT NULL,
+    part_size BIGINT NOT NULL,
+    part_count INTEGER NOT NULL,
+    received_parts INTEGER[] NOT NULL DEFAULT '{}',
+    url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_user_id ON upload_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_expires_at ON upload_sessions(expires_at);
@@ -0,0 +1 @@
+ALTER TABLE media_objects DROP COLUMN IF EXISTS mime_type;
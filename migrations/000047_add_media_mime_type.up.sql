@@ -0,0 +1,23 @@
+-- メディアのMIMEタイプ（アップロード時にファイルの内容から判定する）
+ALTER TABLE media_objects ADD COLUMN IF NOT EXISTS mime_type VARCHAR(100) NOT NULL DEFAULT '';
+
+-- 追加前にアップロードされたメディアは拡張子から設定する
+UPDATE media_objects SET mime_type = CASE lower(substring(url from '\.([A-Za-z0-9]+)$'))
+    WHEN 'jpg' THEN 'image/jpeg'
+    WHEN 'jpeg' THEN 'image/jpeg'
+    WHEN 'png' THEN 'image/png'
+    WHEN 'webp' THEN 'image/webp'
+    WHEN 'avif' THEN 'image/avif'
+    WHEN 'heic' THEN 'image/heic'
+    WHEN 'gif' THEN 'image/gif'
+    WHEN 'mp4' THEN 'video/mp4'
+    WHEN 'm4v' THEN 'video/x-m4v'
+    WHEN 'mov' THEN 'video/quicktime'
+    WHEN 'webm' THEN 'video/webm'
+    WHEN 'mp3' THEN 'audio/mpeg'
+    WHEN 'm4a' THEN 'audio/mp4'
+    WHEN 'ogg' THEN 'audio/ogg'
+    WHEN 'wav' THEN 'audio/wav'
+    ELSE ''
+END
+WHERE mime_type = '';
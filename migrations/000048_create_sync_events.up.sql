@@ -0,0 +1,14 @@
+-- クライアントの差分同期に使う変更のイベント（追記のみで更新しない。保持期間を過ぎたイベントは定期的に削除する）
+-- user_idは投稿の作成者・フォローしたユーザー・通知の受信者、target_user_idはフォローされたユーザー、subject_idは投稿・通知のID
+CREATE TABLE IF NOT EXISTS sync_events (
+    seq BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(30) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    subject_id UUID,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_events_user_seq ON sync_events(user_id, seq);
+CREATE INDEX IF NOT EXISTS idx_sync_events_target_user_seq ON sync_events(target_user_id, seq) WHERE target_user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_sync_events_occurred_at ON sync_events(occurred_at);
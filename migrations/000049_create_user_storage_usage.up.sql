@@ -0,0 +1,8 @@
+-- ユーザーごとのメディアの使用量（アップロードしたファイルの合計サイズ。参照を外したファイルの分は差し引く）
+-- 同じ内容のファイルを重複排除して保存した場合も、アップロードしたユーザーごとにサイズを数える
+CREATE TABLE IF NOT EXISTS user_storage_usage (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    used_bytes BIGINT NOT NULL DEFAULT 0 CHECK (used_bytes >= 0),
+    file_count INTEGER NOT NULL DEFAULT 0 CHECK (file_count >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,12 @@
+-- モバイル端末のプッシュ通知の送信先（FCM・APNsの端末トークン）
+-- 同じ端末で別のユーザーがログインした場合は、後から登録したユーザーのトークンとして置き換える
+CREATE TABLE IF NOT EXISTS device_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(10) NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user_id ON device_tokens(user_id);
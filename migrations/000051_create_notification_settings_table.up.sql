@@ -0,0 +1,8 @@
+-- 通知の種類ごとの受け取り方の設定（行がない種類はアプリケーションのデフォルト値に従う）
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notification_type VARCHAR(20) NOT NULL,
+    email_enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, notification_type)
+);
@@ -0,0 +1,3 @@
+DELETE FROM notification_settings WHERE email_enabled IS NULL;
+ALTER TABLE notification_settings ALTER COLUMN email_enabled SET NOT NULL;
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS enabled;
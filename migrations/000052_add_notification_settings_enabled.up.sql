@@ -0,0 +1,4 @@
+-- 通知の種類ごとの受け取りの有効・無効（無効にした種類の通知は作成しない）
+-- 種類ごとに片方の設定だけを保存できるよう、NULLの項目はアプリケーションのデフォルト値に従う
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS enabled BOOLEAN;
+ALTER TABLE notification_settings ALTER COLUMN email_enabled DROP NOT NULL;
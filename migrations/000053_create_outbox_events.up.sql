@@ -0,0 +1,17 @@
+-- 通知の配信イベントのアウトボックス
+-- 通知の作成と同じトランザクションで配信先（WebSocket・プッシュ通知・メール・Webhook）ごとに1行保存し、
+-- バックグラウンドの配信処理が配信に成功するまで再試行する（配信した行は削除する）
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    channel VARCHAR(20) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- 最大回数まで失敗した日時（調査のため残し、配信の対象から外す）
+    failed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(next_attempt_at) WHERE failed_at IS NULL;
@@ -0,0 +1,2 @@
+-- 同じキーのジョブは1件のみ登録する（複数のインスタンスの定期実行が同じ時刻のジョブを重複して登録しないようにする）
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS unique_key VARCHAR(200) UNIQUE;
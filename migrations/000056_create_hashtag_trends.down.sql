@@ -0,0 +1 @@
+DROP TABLE IF EXISTS hashtag_trends;
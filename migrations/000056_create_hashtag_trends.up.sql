@@ -0,0 +1,11 @@
+-- ハッシュタグの1時間ごとの使用数（定期実行のタスクが投稿から集計する。ハッシュタグは小文字で保存する）
+-- post_countはハッシュタグを含む投稿の数、user_countはその投稿者の数
+CREATE TABLE IF NOT EXISTS hashtag_trends (
+    hashtag VARCHAR(100) NOT NULL,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    post_count INTEGER NOT NULL,
+    user_count INTEGER NOT NULL,
+    PRIMARY KEY (hashtag, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_hashtag_trends_hour ON hashtag_trends(hour);
@@ -0,0 +1,20 @@
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+DROP FUNCTION IF EXISTS reject_audit_log_changes();
+
+DROP INDEX IF EXISTS idx_audit_logs_action;
+DROP INDEX IF EXISTS idx_audit_logs_actor;
+
+-- 管理者の操作以外の記録は元のテーブルに戻せないため削除する
+DELETE FROM audit_logs WHERE target_type IS NULL OR target_id IS NULL;
+
+ALTER TABLE audit_logs
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address;
+
+ALTER TABLE audit_logs
+    ALTER COLUMN target_type SET NOT NULL,
+    ALTER COLUMN target_id SET NOT NULL;
+
+ALTER INDEX IF EXISTS idx_audit_logs_target RENAME TO idx_admin_audit_logs_target;
+ALTER INDEX IF EXISTS idx_audit_logs_created_at RENAME TO idx_admin_audit_logs_created_at;
+ALTER TABLE audit_logs RENAME TO admin_audit_logs;
@@ -0,0 +1,35 @@
+-- 管理者の操作の監査ログを、ログインの試行・パスワードの変更・データのエクスポートも記録する監査ログにする
+ALTER TABLE admin_audit_logs RENAME TO audit_logs;
+ALTER INDEX IF EXISTS idx_admin_audit_logs_created_at RENAME TO idx_audit_logs_created_at;
+ALTER INDEX IF EXISTS idx_admin_audit_logs_target RENAME TO idx_audit_logs_target;
+
+-- ログインに失敗した場合など、対象のない記録もある
+ALTER TABLE audit_logs
+    ALTER COLUMN target_type DROP NOT NULL,
+    ALTER COLUMN target_id DROP NOT NULL;
+
+-- 操作したクライアントのIPアドレスとユーザーエージェント
+ALTER TABLE audit_logs
+    ADD COLUMN IF NOT EXISTS ip_address INET,
+    ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs(action, created_at DESC);
+
+-- 監査ログは追記のみとし、更新・削除を拒否する
+-- 操作したユーザーの削除によってactor_idがNULLになる更新のみ許可する
+CREATE OR REPLACE FUNCTION reject_audit_log_changes() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.actor_id IS NULL
+        AND to_jsonb(NEW) - 'actor_id' = to_jsonb(OLD) - 'actor_id' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_changes();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_presence;
+
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS presence_visible;
@@ -0,0 +1,9 @@
+-- オンライン状態と最終ログイン日時を他のユーザーに表示するか
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS presence_visible BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- ユーザーが最後にWebSocketで接続していた日時（接続中は定期的に更新する）
+CREATE TABLE IF NOT EXISTS user_presence (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
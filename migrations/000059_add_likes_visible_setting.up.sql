@@ -0,0 +1,3 @@
+-- いいねした投稿の一覧を他のユーザーに公開するか
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS likes_visible BOOLEAN NOT NULL DEFAULT TRUE;
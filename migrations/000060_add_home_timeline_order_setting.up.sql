@@ -0,0 +1,4 @@
+-- ホームタイムラインの既定の並び順（latest: 新しい順、ranked: おすすめ順）
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS home_timeline_order VARCHAR(20) NOT NULL DEFAULT 'latest'
+        CHECK (home_timeline_order IN ('latest', 'ranked'));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS post_topics;
+DROP TABLE IF EXISTS topics;
@@ -0,0 +1,25 @@
+-- 探索タブを分類するトピック（管理者が登録する）
+CREATE TABLE IF NOT EXISTS topics (
+    id UUID PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- このトピックに自動で分類する投稿のハッシュタグ（小文字、#なし）
+    hashtags TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_topics_hashtags ON topics USING GIN (hashtags);
+
+-- 投稿のトピック（manual: 投稿者が指定、hashtag: ハッシュタグから分類）
+CREATE TABLE IF NOT EXISTS post_topics (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    topic_id UUID NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('manual', 'hashtag')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, topic_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_topics_topic_id ON post_topics(topic_id, created_at DESC);
@@ -0,0 +1,11 @@
+-- 外部サービスのアーカイブから取り込んだ投稿（同じアーカイブを再度取り込んだ場合に重複させないため、元の投稿のIDを記録する）
+CREATE TABLE IF NOT EXISTS post_imports (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, source, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_imports_post_id ON post_imports(post_id);
@@ -0,0 +1,13 @@
+-- メディアをアップロードしたユーザー（同じ内容のファイルを重複排除して保存した場合も、アップロードしたユーザーごとに記録する）
+-- upload_countはそのユーザーの使用量に数えているアップロードの回数で、参照を外すとそのユーザーの使用量から差し引いて1減らす
+-- 追加前にアップロードされたメディアはアップロードしたユーザーが分からないため、参照されなくなった後の掃除でのみ削除される
+CREATE TABLE IF NOT EXISTS media_uploads (
+    media_id UUID NOT NULL REFERENCES media_objects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    upload_count INTEGER NOT NULL DEFAULT 1 CHECK (upload_count >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (media_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_uploads_user_id ON media_uploads(user_id);
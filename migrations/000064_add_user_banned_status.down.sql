@@ -0,0 +1,6 @@
+-- 管理者が停止したアカウントはIdPから停止された状態に戻す
+UPDATE users SET status = 'suspended' WHERE status = 'banned';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'deactivated', 'deleting', 'suspended', 'merging'));
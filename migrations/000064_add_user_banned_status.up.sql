@@ -0,0 +1,4 @@
+-- 管理者が停止したアカウント。IdPからの停止（suspended）とは別に管理し、SCIMでは解除できない
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('active', 'deactivated', 'deleting', 'suspended', 'merging', 'banned'));
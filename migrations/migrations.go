@@ -0,0 +1,10 @@
+// Package migrations はデータベースのマイグレーションファイルをバイナリに埋め込みます
+// 実行環境にマイグレーションのディレクトリを配置しなくてもマイグレーションを実行できます
+package migrations
+
+import "embed"
+
+// FS はマイグレーションファイル（NNNNNN_name.(up|down).sql）を含むファイルシステムです
+//
+//go:embed *.sql
+var FS embed.FS
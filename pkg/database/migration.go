@@ -3,23 +3,26 @@ package database
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 
+	"github.com/TakuyaAizawa/gox/migrations"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 // MigrationOptions はマイグレーションの設定オプションを保持します
 type MigrationOptions struct {
-	// マイグレーションファイルのディレクトリパス
+	// マイグレーションファイルのディレクトリパス（空の場合はバイナリに埋め込まれたマイグレーションを使用）
 	MigrationsPath string
-	
+
 	// マイグレーションテーブル名
 	MigrationsTable string
-	
+
 	// スキーマ名
 	SchemaName string
 }
@@ -27,7 +30,7 @@ type MigrationOptions struct {
 // DefaultMigrationOptions はデフォルトのマイグレーション設定を返します
 func DefaultMigrationOptions() *MigrationOptions {
 	return &MigrationOptions{
-		MigrationsPath:  "migrations",
+		MigrationsPath:  "",
 		MigrationsTable: "schema_migrations",
 		SchemaName:      "public",
 	}
@@ -35,59 +38,164 @@ func DefaultMigrationOptions() *MigrationOptions {
 
 // RunMigrations はデータベースマイグレーションを実行します
 func RunMigrations(db *PostgresDB, options *MigrationOptions) error {
+	m, err := newMigrate(db, options)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	// マイグレーションの実行
+	log.Println("データベースマイグレーションを実行しています...")
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("マイグレーションの実行に失敗しました: %w", err)
+	}
+
+	return logMigrationVersion(m)
+}
+
+// RollbackMigration は最後のマイグレーションをロールバックします
+func RollbackMigration(db *PostgresDB, options *MigrationOptions) error {
+	m, err := newMigrate(db, options)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	// 1つ前のバージョンにロールバック
+	log.Println("最後のマイグレーションをロールバックしています...")
+	if err := m.Steps(-1); err != nil {
+		return fmt.Errorf("マイグレーションのロールバックに失敗しました: %w", err)
+	}
+
+	log.Println("マイグレーションのロールバックが完了しました")
+	return logMigrationVersion(m)
+}
+
+// MigrationVersion は適用済みのマイグレーションバージョンと、ダーティ状態かどうかを返します
+// マイグレーションが1件も適用されていない場合はappliedがfalseになります
+func MigrationVersion(db *PostgresDB, options *MigrationOptions) (version uint, dirty bool, applied bool, err error) {
+	m, err := newMigrate(db, options)
+	if err != nil {
+		return 0, false, false, err
+	}
+	defer m.Close()
+
+	version, dirty, err = m.Version()
+	if err == migrate.ErrNilVersion {
+		return 0, false, false, nil
+	}
+	if err != nil {
+		return 0, false, false, fmt.Errorf("マイグレーションバージョンの取得に失敗しました: %w", err)
+	}
+	return version, dirty, true, nil
+}
+
+// MigrateSteps はマイグレーションをn件適用します（nが負の場合は|n|件ロールバックします）
+func MigrateSteps(db *PostgresDB, options *MigrationOptions, n int) error {
+	if n == 0 {
+		return errors.New("適用するマイグレーションの件数が0です")
+	}
+
+	m, err := newMigrate(db, options)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if n > 0 {
+		log.Printf("マイグレーションを %d 件適用しています...", n)
+	} else {
+		log.Printf("マイグレーションを %d 件ロールバックしています...", -n)
+	}
+	if err := m.Steps(n); err != nil {
+		return fmt.Errorf("マイグレーションの実行に失敗しました: %w", err)
+	}
+
+	return logMigrationVersion(m)
+}
+
+// MigrateTo は指定したバージョンまでマイグレーションを適用またはロールバックします
+func MigrateTo(db *PostgresDB, options *MigrationOptions, version uint) error {
+	m, err := newMigrate(db, options)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	log.Printf("バージョン %d までマイグレーションを実行しています...", version)
+	if err := m.Migrate(version); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("マイグレーションの実行に失敗しました: %w", err)
+	}
+
+	return logMigrationVersion(m)
+}
+
+// ForceMigrationVersion はマイグレーションを実行せずに、適用済みのバージョンを設定してダーティ状態を解除します
+// 失敗したマイグレーションを手動で修復した後に使用します。versionが-1の場合は未適用の状態にします
+func ForceMigrationVersion(db *PostgresDB, options *MigrationOptions, version int) error {
+	if version < -1 {
+		return fmt.Errorf("無効なマイグレーションバージョンです: %d", version)
+	}
+
+	m, err := newMigrate(db, options)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	log.Printf("マイグレーションバージョンを %d に設定しています...", version)
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("マイグレーションバージョンの設定に失敗しました: %w", err)
+	}
+
+	return logMigrationVersion(m)
+}
+
+// newMigrate はマイグレーションファイルとデータベースからマイグレーションのインスタンスを作成します
+func newMigrate(db *PostgresDB, options *MigrationOptions) (*migrate.Migrate, error) {
 	if db == nil {
-		return errors.New("データベース接続がnilです")
+		return nil, errors.New("データベース接続がnilです")
 	}
-	
+
 	if options == nil {
 		options = DefaultMigrationOptions()
 	}
-	
-	// マイグレーションパスの存在確認
-	absPath, err := filepath.Abs(options.MigrationsPath)
+
+	// マイグレーションファイルの読み込み元
+	sourceDriver, err := migrationSource(options.MigrationsPath)
 	if err != nil {
-		return fmt.Errorf("マイグレーションパスの解決に失敗しました: %w", err)
+		return nil, err
 	}
-	
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return fmt.Errorf("マイグレーションディレクトリが存在しません: %s", absPath)
-	}
-	
+
 	// Postgresドライバーの設定
 	driver, err := postgres.WithInstance(db.DB, &postgres.Config{
 		MigrationsTable: options.MigrationsTable,
 		SchemaName:      options.SchemaName,
 	})
 	if err != nil {
-		return fmt.Errorf("マイグレーションドライバーの初期化に失敗しました: %w", err)
+		return nil, fmt.Errorf("マイグレーションドライバーの初期化に失敗しました: %w", err)
 	}
-	
+
 	// マイグレーションの初期化
-	// Windows対応のパス形式を使用
-	cleanPath := filepath.ToSlash(absPath)
-	sourceURL := "file://" + cleanPath
-	log.Printf("マイグレーションソースURL: %s", sourceURL)
-	m, err := migrate.NewWithDatabaseInstance(
-		sourceURL,
+	m, err := migrate.NewWithInstance(
+		"iofs",
+		sourceDriver,
 		"postgres",
 		driver,
 	)
 	if err != nil {
-		return fmt.Errorf("マイグレーションの初期化に失敗しました: %w", err)
+		return nil, fmt.Errorf("マイグレーションの初期化に失敗しました: %w", err)
 	}
-	
-	// マイグレーションの実行
-	log.Println("データベースマイグレーションを実行しています...")
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("マイグレーションの実行に失敗しました: %w", err)
-	}
-	
-	// マイグレーションのバージョン確認
+	return m, nil
+}
+
+// logMigrationVersion は現在のマイグレーションバージョンをログに出力します
+func logMigrationVersion(m *migrate.Migrate) error {
 	version, dirty, err := m.Version()
 	if err != nil && err != migrate.ErrNilVersion {
 		return fmt.Errorf("マイグレーションバージョンの取得に失敗しました: %w", err)
 	}
-	
+
 	if dirty {
 		log.Printf("警告: マイグレーションは「ダーティ」状態です (バージョン: %d)", version)
 	} else if err == migrate.ErrNilVersion {
@@ -95,55 +203,43 @@ func RunMigrations(db *PostgresDB, options *MigrationOptions) error {
 	} else {
 		log.Printf("マイグレーションが正常に完了しました (現在のバージョン: %d)", version)
 	}
-	
+
 	return nil
 }
 
-// RollbackMigration は最後のマイグレーションをロールバックします
-func RollbackMigration(db *PostgresDB, options *MigrationOptions) error {
-	if db == nil {
-		return errors.New("データベース接続がnilです")
+// MigrationFS はマイグレーションファイルを読み込むファイルシステムを返します
+// migrationsPathが空の場合はバイナリに埋め込まれたマイグレーションを、それ以外の場合はそのディレクトリを返します
+func MigrationFS(migrationsPath string) (fs.FS, error) {
+	if migrationsPath == "" {
+		return migrations.FS, nil
 	}
-	
-	if options == nil {
-		options = DefaultMigrationOptions()
-	}
-	
-	// マイグレーションパスの存在確認
-	absPath, err := filepath.Abs(options.MigrationsPath)
+
+	absPath, err := filepath.Abs(migrationsPath)
 	if err != nil {
-		return fmt.Errorf("マイグレーションパスの解決に失敗しました: %w", err)
+		return nil, fmt.Errorf("マイグレーションパスの解決に失敗しました: %w", err)
 	}
-	
-	// Postgresドライバーの設定
-	driver, err := postgres.WithInstance(db.DB, &postgres.Config{
-		MigrationsTable: options.MigrationsTable,
-		SchemaName:      options.SchemaName,
-	})
-	if err != nil {
-		return fmt.Errorf("マイグレーションドライバーの初期化に失敗しました: %w", err)
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("マイグレーションディレクトリが存在しません: %s", absPath)
 	}
-	
-	// マイグレーションの初期化
-	// Windows対応のパス形式を使用
-	cleanPath := filepath.ToSlash(absPath)
-	sourceURL := "file://" + cleanPath
-	log.Printf("マイグレーションソースURL: %s", sourceURL)
-	m, err := migrate.NewWithDatabaseInstance(
-		sourceURL,
-		"postgres",
-		driver,
-	)
+	return os.DirFS(absPath), nil
+}
+
+// migrationSource はマイグレーションファイルの読み込み元（iofsのソースドライバー）を作成します
+func migrationSource(migrationsPath string) (source.Driver, error) {
+	fsys, err := MigrationFS(migrationsPath)
 	if err != nil {
-		return fmt.Errorf("マイグレーションの初期化に失敗しました: %w", err)
+		return nil, err
 	}
-	
-	// 1つ前のバージョンにロールバック
-	log.Println("最後のマイグレーションをロールバックしています...")
-	if err := m.Steps(-1); err != nil {
-		return fmt.Errorf("マイグレーションのロールバックに失敗しました: %w", err)
+
+	if migrationsPath == "" {
+		log.Println("バイナリに埋め込まれたマイグレーションを使用します")
+	} else {
+		log.Printf("マイグレーションディレクトリ: %s", migrationsPath)
 	}
-	
-	log.Println("マイグレーションのロールバックが完了しました")
-	return nil
-}
\ No newline at end of file
+
+	sourceDriver, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーションファイルの読み込みに失敗しました: %w", err)
+	}
+	return sourceDriver, nil
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LintSeverity はリント結果の重要度を表します
+type LintSeverity string
+
+const (
+	// LintError はCIを失敗させる問題を表します
+	LintError LintSeverity = "error"
+	// LintWarning は確認が必要だがCIを失敗させない問題を表します
+	LintWarning LintSeverity = "warning"
+)
+
+// LintIssue はマイグレーションファイルの検証で見つかった問題を表します
+type LintIssue struct {
+	Severity LintSeverity
+	File     string
+	Message  string
+}
+
+// String は問題を1行の文字列として返します
+func (i LintIssue) String() string {
+	if i.File == "" {
+		return fmt.Sprintf("[%s] %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.File, i.Message)
+}
+
+// MigrationFile はマイグレーションファイル1件の情報を保持します
+type MigrationFile struct {
+	Version   uint
+	Name      string
+	Direction string
+	Path      string
+
+	// ファイルを読み込むファイルシステム
+	fsys fs.FS
+}
+
+// Read はマイグレーションファイルの内容を返します
+func (f *MigrationFile) Read() ([]byte, error) {
+	return fs.ReadFile(f.fsys, filepath.Base(f.Path))
+}
+
+// マイグレーションファイル名の形式（例: 000001_create_users_table.up.sql）
+var migrationFileNamePattern = regexp.MustCompile(`^(\d{6})_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// 元に戻せない（データが失われる）操作のパターン
+var irreversiblePatterns = []struct {
+	pattern *regexp.Regexp
+	message string
+}{
+	{regexp.MustCompile(`(?i)\bDROP\s+TABLE\b`), "テーブルを削除しています"},
+	{regexp.MustCompile(`(?i)\bDROP\s+COLUMN\b`), "カラムを削除しています"},
+	{regexp.MustCompile(`(?i)\bTRUNCATE\b`), "テーブルを空にしています"},
+	{regexp.MustCompile(`(?i)\bDELETE\s+FROM\b`), "行を削除しています"},
+	{regexp.MustCompile(`(?i)\bALTER\s+COLUMN\s+\w+\s+(SET\s+DATA\s+)?TYPE\b`), "カラムの型を変更しています"},
+	{regexp.MustCompile(`(?i)\bDROP\s+SCHEMA\b`), "スキーマを削除しています"},
+}
+
+// LoadMigrationFiles はディレクトリ内のマイグレーションファイルを読み込み、形式に従わないファイルを問題として返します
+// migrationsPathが空の場合はバイナリに埋め込まれたマイグレーションを読み込みます
+func LoadMigrationFiles(migrationsPath string) ([]MigrationFile, []LintIssue, error) {
+	fsys, err := MigrationFS(migrationsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, nil, fmt.Errorf("マイグレーションディレクトリの読み込みに失敗しました: %w", err)
+	}
+
+	var files []MigrationFile
+	var issues []LintIssue
+	for _, entry := range entries {
+		// マイグレーションを埋め込むGoのソースファイルは対象外
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) == ".go" {
+			continue
+		}
+
+		matches := migrationFileNamePattern.FindStringSubmatch(name)
+		if matches == nil {
+			issues = append(issues, LintIssue{
+				Severity: LintError,
+				File:     name,
+				Message:  "ファイル名が NNNNNN_snake_case_name.(up|down).sql の形式ではありません",
+			})
+			continue
+		}
+
+		version, _ := strconv.ParseUint(matches[1], 10, 64)
+		files = append(files, MigrationFile{
+			Version:   uint(version),
+			Name:      matches[2],
+			Direction: matches[3],
+			Path:      filepath.Join(migrationsPath, name),
+			fsys:      fsys,
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Version != files[j].Version {
+			return files[i].Version < files[j].Version
+		}
+		return files[i].Direction > files[j].Direction // up を先に並べる
+	})
+
+	return files, issues, nil
+}
+
+// LintMigrations はマイグレーションファイルを検証し、見つかった問題を返します
+// 検証内容: ファイル名の形式、up/downの対応、連番の欠番・重複、空ファイル、元に戻せない操作
+func LintMigrations(migrationsPath string) ([]LintIssue, error) {
+	files, issues, err := LoadMigrationFiles(migrationsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// バージョンごとにup/downをまとめる
+	type migrationPair struct {
+		names map[string]bool
+		up    *MigrationFile
+		down  *MigrationFile
+	}
+	pairs := make(map[uint]*migrationPair)
+	var versions []uint
+	for i := range files {
+		file := &files[i]
+		pair, ok := pairs[file.Version]
+		if !ok {
+			pair = &migrationPair{names: make(map[string]bool)}
+			pairs[file.Version] = pair
+			versions = append(versions, file.Version)
+		}
+		pair.names[file.Name] = true
+		if file.Direction == "up" {
+			pair.up = file
+		} else {
+			pair.down = file
+		}
+	}
+
+	for i, version := range versions {
+		pair := pairs[version]
+		label := fmt.Sprintf("%06d", version)
+
+		if len(pair.names) > 1 {
+			issues = append(issues, LintIssue{
+				Severity: LintError,
+				File:     label,
+				Message:  "同じバージョン番号に複数のマイグレーション名が存在します",
+			})
+		}
+		if pair.up == nil {
+			issues = append(issues, LintIssue{Severity: LintError, File: label, Message: "upファイルがありません"})
+		}
+		if pair.down == nil {
+			issues = append(issues, LintIssue{Severity: LintError, File: label, Message: "downファイルがありません"})
+		}
+
+		// 連番の確認
+		expected := uint(1)
+		if i > 0 {
+			expected = versions[i-1] + 1
+		}
+		if version != expected {
+			issues = append(issues, LintIssue{
+				Severity: LintError,
+				File:     label,
+				Message:  fmt.Sprintf("バージョン番号が連番ではありません (期待値: %06d)", expected),
+			})
+		}
+
+		for _, file := range []*MigrationFile{pair.up, pair.down} {
+			if file == nil {
+				continue
+			}
+			fileIssues, err := lintMigrationFile(file)
+			if err != nil {
+				return nil, err
+			}
+			issues = append(issues, fileIssues...)
+		}
+	}
+
+	return issues, nil
+}
+
+// lintMigrationFile は1つのマイグレーションファイルの内容を検証します
+func lintMigrationFile(file *MigrationFile) ([]LintIssue, error) {
+	content, err := file.Read()
+	if err != nil {
+		return nil, fmt.Errorf("マイグレーションファイルの読み込みに失敗しました: %w", err)
+	}
+
+	name := filepath.Base(file.Path)
+	sql := stripSQLComments(string(content))
+	if strings.TrimSpace(sql) == "" {
+		return []LintIssue{{Severity: LintError, File: name, Message: "ファイルが空です"}}, nil
+	}
+
+	// downファイルは元に戻す操作そのものなので、破壊的な操作はupファイルのみ警告する
+	if file.Direction != "up" {
+		return nil, nil
+	}
+
+	var issues []LintIssue
+	for _, p := range irreversiblePatterns {
+		if p.pattern.MatchString(sql) {
+			issues = append(issues, LintIssue{
+				Severity: LintWarning,
+				File:     name,
+				Message:  "元に戻せない操作: " + p.message,
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// stripSQLComments は行コメント（--）を取り除きます
+func stripSQLComments(sql string) string {
+	lines := strings.Split(sql, "\n")
+	for i, line := range lines {
+		if idx := strings.Index(line, "--"); idx >= 0 {
+			lines[i] = line[:idx]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HasLintErrors は問題の中にエラーが含まれるかを返します
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
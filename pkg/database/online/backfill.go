@@ -0,0 +1,209 @@
+package online
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+// Progress はバックフィルの進捗を表します
+type Progress struct {
+	Name          string
+	LastKey       *string
+	RowsProcessed int64
+	Completed     bool
+	StartedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Backfill は大きなテーブルのデータを主キー順に少しずつ更新するジョブです
+// 各バッチは短いトランザクションで実行され、進捗はonline_migration_progressテーブルに保存されるため、
+// 中断しても同じNameで再実行すれば続きから再開します
+type Backfill struct {
+	// 進捗を識別する一意な名前（例: "posts_backfill_is_reply"）
+	Name string
+
+	// 対象テーブル名
+	Table string
+
+	// バッチの区切りに使用する一意なカラム（通常は主キー）
+	KeyColumn string
+
+	// KeyColumnの型（デフォルトは "uuid"）
+	KeyType string
+
+	// UPDATEのSET句（例: "is_reply = (reply_to_id IS NOT NULL)"）
+	Set string
+
+	// 更新対象を絞り込む条件（省略可、例: "is_reply IS NULL"）
+	Where string
+
+	// 1バッチあたりの行数（デフォルトは1000）
+	BatchSize int
+
+	// バッチ間の待機時間（レプリケーション遅延やロック競合を抑えるため）
+	Pause time.Duration
+
+	// バッチごとに呼ばれる進捗通知（省略可）
+	OnProgress func(Progress)
+
+	Log logger.Logger
+}
+
+// Run はバックフィルを最後まで実行します。既に完了している場合は何もしません
+func (b *Backfill) Run(ctx context.Context, conn Conn) (*Progress, error) {
+	if b.Name == "" || b.Table == "" || b.KeyColumn == "" || b.Set == "" {
+		return nil, errors.New("Name・Table・KeyColumn・Setは必須です")
+	}
+
+	keyType := b.KeyType
+	if keyType == "" {
+		keyType = "uuid"
+	}
+	batchSize := b.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	progress, err := LoadProgress(ctx, conn, b.Name)
+	if err != nil {
+		return nil, err
+	}
+	if progress.Completed {
+		return progress, nil
+	}
+
+	query := b.batchSQL(keyType)
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		var lastKey *string
+		var updated int64
+		if err := conn.QueryRow(ctx, query, progress.LastKey, batchSize).Scan(&lastKey, &updated); err != nil {
+			return progress, fmt.Errorf("バックフィルのバッチ実行に失敗しました (%s): %w", b.Name, err)
+		}
+
+		// 対象行がなくなれば完了
+		if lastKey == nil {
+			progress.Completed = true
+		} else {
+			progress.LastKey = lastKey
+			progress.RowsProcessed += updated
+		}
+
+		if err := saveProgress(ctx, conn, progress); err != nil {
+			return progress, err
+		}
+		if b.OnProgress != nil {
+			b.OnProgress(*progress)
+		}
+		if b.Log != nil {
+			b.Log.Info("バックフィルの進捗", "name", b.Name, "rows_processed", progress.RowsProcessed, "completed", progress.Completed)
+		}
+
+		if progress.Completed {
+			return progress, nil
+		}
+
+		if b.Pause > 0 {
+			select {
+			case <-ctx.Done():
+				return progress, ctx.Err()
+			case <-time.After(b.Pause):
+			}
+		}
+	}
+}
+
+// batchSQL は1バッチ分を更新し、バッチ内の最後のキーと更新件数を返すSQLを組み立てます
+func (b *Backfill) batchSQL(keyType string) string {
+	key := quoteIdent(b.KeyColumn)
+	table := quoteIdent(b.Table)
+
+	where := fmt.Sprintf("($1::text IS NULL OR %s > $1::%s)", key, keyType)
+	if b.Where != "" {
+		where += " AND (" + b.Where + ")"
+	}
+
+	return fmt.Sprintf(`
+		WITH batch AS (
+			SELECT %[1]s FROM %[2]s
+			WHERE %[3]s
+			ORDER BY %[1]s
+			LIMIT $2
+		), updated AS (
+			UPDATE %[2]s AS t SET %[4]s
+			FROM batch
+			WHERE t.%[1]s = batch.%[1]s
+			RETURNING 1
+		)
+		SELECT
+			(SELECT %[1]s::text FROM batch ORDER BY %[1]s DESC LIMIT 1),
+			(SELECT COUNT(*) FROM updated)
+	`, key, table, where, b.Set)
+}
+
+// LoadProgress は保存されているバックフィルの進捗を取得します（未実行の場合は新しい進捗を返します）
+func LoadProgress(ctx context.Context, conn Conn, name string) (*Progress, error) {
+	query := `
+		SELECT name, last_key, rows_processed, completed, started_at, updated_at
+		FROM online_migration_progress
+		WHERE name = $1
+	`
+
+	rows, err := conn.Query(ctx, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("バックフィルの進捗取得に失敗しました (%s): %w", name, err)
+	}
+	defer rows.Close()
+
+	progress := &Progress{Name: name, StartedAt: time.Now().UTC()}
+	if rows.Next() {
+		if err := rows.Scan(
+			&progress.Name, &progress.LastKey, &progress.RowsProcessed,
+			&progress.Completed, &progress.StartedAt, &progress.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return progress, rows.Err()
+}
+
+// ResetProgress はバックフィルの進捗を削除し、次回の実行を最初からやり直せるようにします
+func ResetProgress(ctx context.Context, conn Conn, name string) error {
+	if _, err := conn.Exec(ctx, "DELETE FROM online_migration_progress WHERE name = $1", name); err != nil {
+		return fmt.Errorf("バックフィルの進捗削除に失敗しました (%s): %w", name, err)
+	}
+	return nil
+}
+
+// saveProgress はバックフィルの進捗を保存します
+func saveProgress(ctx context.Context, conn Conn, progress *Progress) error {
+	progress.UpdatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO online_migration_progress (name, last_key, rows_processed, completed, started_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			last_key = EXCLUDED.last_key,
+			rows_processed = EXCLUDED.rows_processed,
+			completed = EXCLUDED.completed,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := conn.Exec(ctx, query,
+		progress.Name, progress.LastKey, progress.RowsProcessed,
+		progress.Completed, progress.StartedAt, progress.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("バックフィルの進捗保存に失敗しました (%s): %w", progress.Name, err)
+	}
+
+	return nil
+}
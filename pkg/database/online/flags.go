@@ -0,0 +1,183 @@
+package online
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TakuyaAizawa/gox/pkg/logger"
+)
+
+// FlagStore はfeature_flagsテーブルに保存されたフィーチャーフラグを読み書きします
+// 読み込んだ値は一定時間キャッシュし、リクエストごとにデータベースへ問い合わせないようにします
+type FlagStore struct {
+	conn Conn
+	ttl  time.Duration
+	log  logger.Logger
+
+	mu    sync.RWMutex
+	cache map[string]cachedFlag
+}
+
+type cachedFlag struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// NewFlagStore 新しいフィーチャーフラグストアを作成する
+func NewFlagStore(conn Conn, ttl time.Duration, log logger.Logger) *FlagStore {
+	return &FlagStore{
+		conn:  conn,
+		ttl:   ttl,
+		log:   log,
+		cache: make(map[string]cachedFlag),
+	}
+}
+
+// Get はフラグの値を返します（未設定の場合は空文字列）
+// データベースから取得できない場合は、期限切れでも最後に取得した値を使用します
+func (s *FlagStore) Get(ctx context.Context, name string) (string, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[name]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < s.ttl {
+		return cached.value, nil
+	}
+
+	rows, err := s.conn.Query(ctx, "SELECT value FROM feature_flags WHERE name = $1", name)
+	if err != nil {
+		if ok {
+			s.log.Warn("フィーチャーフラグの取得に失敗したためキャッシュを使用します", "flag", name, "error", err)
+			return cached.value, nil
+		}
+		return "", fmt.Errorf("フィーチャーフラグの取得に失敗しました (%s): %w", name, err)
+	}
+	defer rows.Close()
+
+	value := ""
+	if rows.Next() {
+		if err := rows.Scan(&value); err != nil {
+			return "", err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.cache[name] = cachedFlag{value: value, fetchedAt: time.Now()}
+	s.mu.Unlock()
+
+	return value, nil
+}
+
+// Set はフラグの値を保存します。他のプロセスにはキャッシュの有効期限が切れた時点で反映されます
+func (s *FlagStore) Set(ctx context.Context, name, value string) error {
+	query := `
+		INSERT INTO feature_flags (name, value, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (name) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := s.conn.Exec(ctx, query, name, value); err != nil {
+		return fmt.Errorf("フィーチャーフラグの保存に失敗しました (%s): %w", name, err)
+	}
+
+	s.mu.Lock()
+	s.cache[name] = cachedFlag{value: value, fetchedAt: time.Now()}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// DualWritePhase は新旧スキーマ間の移行段階を表します
+type DualWritePhase string
+
+const (
+	// PhaseOldOnly 旧スキーマのみに書き込み・読み込みを行う（初期状態）
+	PhaseOldOnly DualWritePhase = "old_only"
+	// PhaseDualWrite 新旧両方に書き込み、旧スキーマから読み込む（この間にバックフィルを実行する）
+	PhaseDualWrite DualWritePhase = "dual_write"
+	// PhaseReadNew 新旧両方に書き込み、新スキーマから読み込む（問題があればPhaseDualWriteに戻せる）
+	PhaseReadNew DualWritePhase = "read_new"
+	// PhaseNewOnly 新スキーマのみに書き込み・読み込みを行う（この後で旧スキーマを削除できる）
+	PhaseNewOnly DualWritePhase = "new_only"
+)
+
+// IsValid は移行段階が定義済みの値かを返します
+func (p DualWritePhase) IsValid() bool {
+	switch p {
+	case PhaseOldOnly, PhaseDualWrite, PhaseReadNew, PhaseNewOnly:
+		return true
+	}
+	return false
+}
+
+// DualWrite はフィーチャーフラグで移行段階を切り替えながら新旧スキーマへの書き込みを調整します
+type DualWrite struct {
+	flags *FlagStore
+	flag  string
+	log   logger.Logger
+}
+
+// NewDualWrite 新しい二重書き込みトグルを作成する
+func NewDualWrite(flags *FlagStore, flag string, log logger.Logger) *DualWrite {
+	return &DualWrite{
+		flags: flags,
+		flag:  flag,
+		log:   log,
+	}
+}
+
+// Phase は現在の移行段階を返します。フラグが未設定・不正な場合はPhaseOldOnlyとして扱います
+func (d *DualWrite) Phase(ctx context.Context) DualWritePhase {
+	value, err := d.flags.Get(ctx, d.flag)
+	if err != nil {
+		d.log.Error("移行段階の取得に失敗したため旧スキーマを使用します", "flag", d.flag, "error", err)
+		return PhaseOldOnly
+	}
+
+	phase := DualWritePhase(value)
+	if !phase.IsValid() {
+		return PhaseOldOnly
+	}
+	return phase
+}
+
+// SetPhase は移行段階を変更します
+func (d *DualWrite) SetPhase(ctx context.Context, phase DualWritePhase) error {
+	if !phase.IsValid() {
+		return fmt.Errorf("無効な移行段階です: %s", phase)
+	}
+	return d.flags.Set(ctx, d.flag, string(phase))
+}
+
+// Write は移行段階に応じて旧・新スキーマへの書き込みを実行します
+// 二重書き込み中は旧スキーマへの書き込みが正となり、新スキーマへの書き込みの失敗は記録のみ行います
+// （バックフィルで後から整合させるため）。PhaseReadNew以降は新スキーマへの書き込み失敗をエラーとして返します
+func (d *DualWrite) Write(ctx context.Context, writeOld, writeNew func(ctx context.Context) error) error {
+	switch phase := d.Phase(ctx); phase {
+	case PhaseOldOnly:
+		return writeOld(ctx)
+	case PhaseNewOnly:
+		return writeNew(ctx)
+	default:
+		if err := writeOld(ctx); err != nil {
+			return err
+		}
+		if err := writeNew(ctx); err != nil {
+			if phase == PhaseReadNew {
+				return err
+			}
+			d.log.Error("新スキーマへの二重書き込みに失敗しました", "flag", d.flag, "error", err)
+		}
+		return nil
+	}
+}
+
+// ReadFromNew は新スキーマから読み込むべきかを返します
+func (d *DualWrite) ReadFromNew(ctx context.Context) bool {
+	phase := d.Phase(ctx)
+	return phase == PhaseReadNew || phase == PhaseNewOnly
+}
@@ -0,0 +1,113 @@
+package online
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// IndexSpec は作成するインデックスの定義を保持します
+type IndexSpec struct {
+	// インデックス名
+	Name string
+
+	// 対象テーブル名
+	Table string
+
+	// インデックスを作成するカラム、または式（例: "lower(username)"）
+	Columns []string
+
+	// ユニークインデックスとして作成するか
+	Unique bool
+
+	// 部分インデックスの条件（例: "deleted_at IS NULL"）
+	Where string
+}
+
+// createSQL はインデックス作成のSQLを返します
+func (s IndexSpec) createSQL() string {
+	var b strings.Builder
+	b.WriteString("CREATE ")
+	if s.Unique {
+		b.WriteString("UNIQUE ")
+	}
+	fmt.Fprintf(&b, "INDEX CONCURRENTLY IF NOT EXISTS %s ON %s (%s)",
+		quoteIdent(s.Name), quoteIdent(s.Table), strings.Join(s.Columns, ", "))
+	if s.Where != "" {
+		b.WriteString(" WHERE ")
+		b.WriteString(s.Where)
+	}
+	return b.String()
+}
+
+// CreateIndexConcurrently はテーブルへの書き込みをブロックせずにインデックスを作成します
+// CONCURRENTLYでの作成が途中で失敗すると無効なインデックスが残り、IF NOT EXISTS では作り直されないため、
+// 作成前に同名の無効なインデックスを削除します。トランザクション内では実行できません
+func CreateIndexConcurrently(ctx context.Context, conn Conn, spec IndexSpec) error {
+	if spec.Name == "" || spec.Table == "" || len(spec.Columns) == 0 {
+		return errors.New("インデックス名・テーブル名・カラムは必須です")
+	}
+
+	valid, exists, err := indexState(ctx, conn, spec.Name)
+	if err != nil {
+		return err
+	}
+	if exists && valid {
+		return nil
+	}
+	if exists {
+		// 前回の失敗で残った無効なインデックスを削除
+		if err := DropIndexConcurrently(ctx, conn, spec.Name); err != nil {
+			return err
+		}
+	}
+
+	if _, err := conn.Exec(ctx, spec.createSQL()); err != nil {
+		return fmt.Errorf("インデックスの作成に失敗しました (%s): %w", spec.Name, err)
+	}
+
+	// 作成中の一意制約違反などはエラーにならず無効なインデックスが残る場合があるため再確認する
+	valid, _, err = indexState(ctx, conn, spec.Name)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("インデックスが無効な状態で作成されました (%s)", spec.Name)
+	}
+
+	return nil
+}
+
+// DropIndexConcurrently はテーブルへの書き込みをブロックせずにインデックスを削除します
+func DropIndexConcurrently(ctx context.Context, conn Conn, name string) error {
+	if _, err := conn.Exec(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+quoteIdent(name)); err != nil {
+		return fmt.Errorf("インデックスの削除に失敗しました (%s): %w", name, err)
+	}
+	return nil
+}
+
+// indexState はインデックスが有効か、存在するかを返します
+func indexState(ctx context.Context, conn Conn, name string) (valid bool, exists bool, err error) {
+	query := `
+		SELECT i.indisvalid
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE c.relname = $1 AND pg_table_is_visible(c.oid)
+	`
+
+	rows, err := conn.Query(ctx, query, name)
+	if err != nil {
+		return false, false, fmt.Errorf("インデックスの状態確認に失敗しました (%s): %w", name, err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&valid); err != nil {
+			return false, false, err
+		}
+		exists = true
+	}
+
+	return valid, exists, rows.Err()
+}
@@ -0,0 +1,28 @@
+// Package online は大きなテーブルをAPIを止めずに変更するためのオンラインマイグレーション支援機能を提供します
+//
+// 通常のマイグレーション（golang-migrate）はトランザクション内でテーブルロックを取るため、
+// 行数の多いテーブルに対するインデックス作成やデータ移行はこのパッケージの機能を使って
+// マイグレーションとは別に段階的に実行します。
+//   - CreateIndexConcurrently: CREATE INDEX CONCURRENTLY のラッパー（失敗時に残る無効なインデックスの掃除を含む）
+//   - Backfill: 主キー順のバッチ更新と進捗の永続化（中断しても続きから再開できる）
+//   - DualWrite: フィーチャーフラグで切り替える新旧カラム・テーブルへの二重書き込み
+package online
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Conn はオンラインマイグレーションで使用するデータベース操作を定義します（*pgxpool.Poolが実装します）
+type Conn interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// quoteIdent は識別子をSQLに埋め込めるようにクォートします
+func quoteIdent(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
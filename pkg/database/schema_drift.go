@@ -0,0 +1,242 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 期待されるスキーマを構築するための一時スキーマ名
+const driftShadowSchema = "gox_drift_shadow"
+
+// SchemaObject はスキーマを構成するオブジェクト（カラム・インデックス・制約）1件を表します
+type SchemaObject struct {
+	Kind       string
+	Table      string
+	Name       string
+	Definition string
+}
+
+// key はオブジェクトを一意に識別する文字列を返します
+func (o SchemaObject) key() string {
+	return o.Kind + ":" + o.Table + "." + o.Name
+}
+
+// DriftItem はライブスキーマとマイグレーションの差分1件を表します
+type DriftItem struct {
+	// "+" はライブスキーマにのみ存在、"-" はマイグレーションにのみ存在、"~" は定義の相違
+	Change   string
+	Object   SchemaObject
+	Expected string
+}
+
+// String は差分をdiff形式の1行として返します
+func (d DriftItem) String() string {
+	switch d.Change {
+	case "~":
+		return fmt.Sprintf("~ %s %s.%s\n    expected: %s\n    actual:   %s",
+			d.Object.Kind, d.Object.Table, d.Object.Name, d.Expected, d.Object.Definition)
+	default:
+		return fmt.Sprintf("%s %s %s.%s: %s", d.Change, d.Object.Kind, d.Object.Table, d.Object.Name, d.Object.Definition)
+	}
+}
+
+// DriftReport はスキーマドリフト検出の結果を保持します
+type DriftReport struct {
+	AppliedVersion uint
+	LatestVersion  uint
+	Dirty          bool
+	Items          []DriftItem
+}
+
+// HasDrift はドリフト（未適用のマイグレーションやスキーマの差分）があるかを返します
+func (r *DriftReport) HasDrift() bool {
+	return r.Dirty || r.AppliedVersion != r.LatestVersion || len(r.Items) > 0
+}
+
+// DetectSchemaDrift はマイグレーションを一時スキーマに適用して期待されるスキーマを構築し、
+// ライブスキーマとの差分を返します。一時スキーマはトランザクションのロールバックで破棄されます
+func DetectSchemaDrift(ctx context.Context, db *PostgresDB, options *MigrationOptions) (*DriftReport, error) {
+	if db == nil {
+		return nil, errors.New("データベース接続がnilです")
+	}
+
+	if options == nil {
+		options = DefaultMigrationOptions()
+	}
+
+	files, _, err := LoadMigrationFiles(options.MigrationsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{}
+	for _, file := range files {
+		if file.Version > report.LatestVersion {
+			report.LatestVersion = file.Version
+		}
+	}
+
+	// 適用済みバージョンの取得
+	query := fmt.Sprintf(`SELECT version, dirty FROM %s.%s LIMIT 1`, options.SchemaName, options.MigrationsTable)
+	var version int64
+	if err := db.QueryRowContext(ctx, query).Scan(&version, &report.Dirty); err != nil {
+		return nil, fmt.Errorf("適用済みマイグレーションバージョンの取得に失敗しました: %w", err)
+	}
+	report.AppliedVersion = uint(version)
+
+	actual, err := introspectSchema(ctx, db, options.SchemaName, options.MigrationsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	// 一時スキーマにマイグレーションを適用
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの開始に失敗しました: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		"CREATE SCHEMA " + driftShadowSchema,
+		// 拡張機能の関数を解決するため既存のスキーマも検索パスに含める
+		fmt.Sprintf("SET LOCAL search_path TO %s, %s", driftShadowSchema, options.SchemaName),
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("一時スキーマの作成に失敗しました: %w", err)
+		}
+	}
+
+	for _, file := range files {
+		if file.Direction != "up" {
+			continue
+		}
+		content, err := file.Read()
+		if err != nil {
+			return nil, fmt.Errorf("マイグレーションファイルの読み込みに失敗しました: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+			return nil, fmt.Errorf("一時スキーマへのマイグレーション適用に失敗しました (%06d_%s): %w", file.Version, file.Name, err)
+		}
+	}
+
+	expected, err := introspectSchema(ctx, tx, driftShadowSchema, options.MigrationsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Items = diffSchemas(expected, actual)
+	return report, nil
+}
+
+// queryer はスキーマ情報の取得に必要なクエリメソッドを定義します（*sql.DBと*sql.Txの共通部分）
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// introspectSchema は指定したスキーマのカラム・インデックス・制約を取得します
+func introspectSchema(ctx context.Context, q queryer, schema, migrationsTable string) (map[string]SchemaObject, error) {
+	queries := []struct {
+		kind  string
+		query string
+	}{
+		{"column", `
+			SELECT table_name, column_name,
+				data_type || COALESCE('(' || character_maximum_length || ')', '') ||
+				CASE WHEN is_nullable = 'NO' THEN ' NOT NULL' ELSE '' END ||
+				COALESCE(' DEFAULT ' || column_default, '')
+			FROM information_schema.columns
+			WHERE table_schema = $1 AND table_name <> $2`},
+		{"index", `
+			SELECT tablename, indexname, indexdef
+			FROM pg_indexes
+			WHERE schemaname = $1 AND tablename <> $2`},
+		{"constraint", `
+			SELECT rel.relname, con.conname, pg_get_constraintdef(con.oid)
+			FROM pg_constraint con
+			JOIN pg_class rel ON rel.oid = con.conrelid
+			JOIN pg_namespace nsp ON nsp.oid = rel.relnamespace
+			WHERE nsp.nspname = $1 AND rel.relname <> $2`},
+	}
+
+	objects := make(map[string]SchemaObject)
+	for _, item := range queries {
+		rows, err := q.QueryContext(ctx, item.query, schema, migrationsTable)
+		if err != nil {
+			return nil, fmt.Errorf("スキーマ情報の取得に失敗しました (%s): %w", item.kind, err)
+		}
+
+		for rows.Next() {
+			obj := SchemaObject{Kind: item.kind}
+			if err := rows.Scan(&obj.Table, &obj.Name, &obj.Definition); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("スキーマ情報の読み取りに失敗しました (%s): %w", item.kind, err)
+			}
+			// スキーマ名による差異を除外して比較できるようにする
+			obj.Definition = strings.ReplaceAll(obj.Definition, schema+".", "")
+			objects[obj.key()] = obj
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("スキーマ情報の読み取りに失敗しました (%s): %w", item.kind, err)
+		}
+		rows.Close()
+	}
+
+	return objects, nil
+}
+
+// diffSchemas は期待されるスキーマと実際のスキーマの差分を返します
+func diffSchemas(expected, actual map[string]SchemaObject) []DriftItem {
+	var items []DriftItem
+	for key, exp := range expected {
+		act, ok := actual[key]
+		if !ok {
+			items = append(items, DriftItem{Change: "-", Object: exp})
+			continue
+		}
+		if act.Definition != exp.Definition {
+			items = append(items, DriftItem{Change: "~", Object: act, Expected: exp.Definition})
+		}
+	}
+	for key, act := range actual {
+		if _, ok := expected[key]; !ok {
+			items = append(items, DriftItem{Change: "+", Object: act})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Object.key() < items[j].Object.key()
+	})
+	return items
+}
+
+// FormatDriftReport はドリフト検出結果をレポート文字列に整形します
+func FormatDriftReport(report *DriftReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "適用済みバージョン: %06d / 最新のマイグレーション: %06d\n", report.AppliedVersion, report.LatestVersion)
+	if report.Dirty {
+		b.WriteString("! マイグレーションが「ダーティ」状態です\n")
+	}
+	if report.AppliedVersion < report.LatestVersion {
+		fmt.Fprintf(&b, "! 未適用のマイグレーションが %d 件あります\n", report.LatestVersion-report.AppliedVersion)
+	} else if report.AppliedVersion > report.LatestVersion {
+		b.WriteString("! データベースにマイグレーションファイルより新しいバージョンが適用されています\n")
+	}
+
+	if len(report.Items) == 0 {
+		b.WriteString("スキーマの差分はありません\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "スキーマの差分 (%d 件, -: マイグレーションのみ, +: ライブスキーマのみ, ~: 定義の相違):\n", len(report.Items))
+	for _, item := range report.Items {
+		b.WriteString(item.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}